@@ -47,6 +47,9 @@ type ApplicationOrg interface {
 type Application interface {
 	// Organizations returns a map of org ID to ApplicationOrg
 	Organizations() map[string]ApplicationOrg
+
+	// TxReorderingEnabled returns whether the conflicting transactions of a block may be reordered during validation
+	TxReorderingEnabled() bool
 }
 
 // Channel gives read only access to the channel configuration
@@ -93,6 +96,10 @@ type Orderer interface {
 	// Kafka brokers, i.e. this is not necessarily the entire set of Kafka brokers
 	// used for ordering
 	KafkaBrokers() []string
+
+	// RaftConsenters returns the addresses (host:port notation) of the orderer
+	// nodes replicating the blocks of the channel with Raft
+	RaftConsenters() []string
 }
 
 type ValueProposer interface {
@@ -20,13 +20,21 @@ import (
 	"fmt"
 
 	"github.com/hyperledger/fabric/common/config/msp"
+	pb "github.com/hyperledger/fabric/protos/peer"
 )
 
 const (
 	// ApplicationGroupKey is the group name for the Application config
 	ApplicationGroupKey = "Application"
+
+	// TxReorderingKey is the key name for the TxReordering ConfigValue
+	TxReorderingKey = "TxReordering"
 )
 
+type ApplicationProtos struct {
+	TxReordering *pb.TxReordering
+}
+
 // ApplicationGroup represents the application config group
 type ApplicationGroup struct {
 	*Proposer
@@ -36,6 +44,7 @@ type ApplicationGroup struct {
 
 type ApplicationConfig struct {
 	*standardValues
+	protos *ApplicationProtos
 
 	applicationGroup *ApplicationGroup
 	applicationOrgs  map[string]ApplicationOrg
@@ -61,16 +70,16 @@ func (ag *ApplicationGroup) Allocate() Values {
 }
 
 func NewApplicationConfig(ag *ApplicationGroup) *ApplicationConfig {
-	sv, err := NewStandardValues(&(struct{}{}))
+	protos := &ApplicationProtos{}
+	sv, err := NewStandardValues(protos)
 	if err != nil {
 		logger.Panicf("Programming error: %s", err)
 	}
 
 	return &ApplicationConfig{
 		applicationGroup: ag,
-
-		// Currently there are no config values
-		standardValues: sv,
+		protos:           protos,
+		standardValues:   sv,
 	}
 }
 
@@ -94,3 +103,8 @@ func (ac *ApplicationConfig) Commit() {
 func (ac *ApplicationConfig) Organizations() map[string]ApplicationOrg {
 	return ac.applicationOrgs
 }
+
+// TxReorderingEnabled returns whether the conflicting transactions of a block may be reordered during validation
+func (ac *ApplicationConfig) TxReorderingEnabled() bool {
+	return ac.protos.TxReordering.Enabled
+}
@@ -32,6 +32,16 @@ func applicationConfigGroup(orgID string, key string, value []byte) *cb.ConfigGr
 	return result
 }
 
+// TemplateTxReordering creates a headerless config item enabling or disabling the reordering of transactions
+func TemplateTxReordering(enabled bool) *cb.ConfigGroup {
+	result := cb.NewConfigGroup()
+	result.Groups[ApplicationGroupKey] = cb.NewConfigGroup()
+	result.Groups[ApplicationGroupKey].Values[TxReorderingKey] = &cb.ConfigValue{
+		Value: utils.MarshalOrPanic(&pb.TxReordering{Enabled: enabled}),
+	}
+	return result
+}
+
 // TemplateAnchorPeers creates a headerless config item representing the anchor peers
 func TemplateAnchorPeers(orgID string, anchorPeers []*pb.AnchorPeer) *cb.ConfigGroup {
 	return applicationConfigGroup(orgID, AnchorPeersKey, utils.MarshalOrPanic(&pb.AnchorPeers{AnchorPeers: anchorPeers}))
@@ -47,6 +47,9 @@ const (
 
 	// KafkaBrokersKey is the cb.ConfigItem type key name for the KafkaBrokers message
 	KafkaBrokersKey = "KafkaBrokers"
+
+	// RaftConsentersKey is the cb.ConfigItem type key name for the RaftConsenters message
+	RaftConsentersKey = "RaftConsenters"
 )
 
 // OrdererProtos is used as the source of the OrdererConfig
@@ -55,6 +58,7 @@ type OrdererProtos struct {
 	BatchSize           *ab.BatchSize
 	BatchTimeout        *ab.BatchTimeout
 	KafkaBrokers        *ab.KafkaBrokers
+	RaftConsenters      *ab.RaftConsenters
 	ChannelRestrictions *ab.ChannelRestrictions
 }
 
@@ -135,6 +139,12 @@ func (oc *OrdererConfig) KafkaBrokers() []string {
 	return oc.protos.KafkaBrokers.Brokers
 }
 
+// RaftConsenters returns the addresses (host:port notation) of the orderer
+// nodes replicating the blocks of the channel with Raft
+func (oc *OrdererConfig) RaftConsenters() []string {
+	return oc.protos.RaftConsenters.Consenters
+}
+
 // MaxChannelsCount returns the maximum count of channels this orderer supports
 func (oc *OrdererConfig) MaxChannelsCount() uint64 {
 	return oc.protos.ChannelRestrictions.MaxCount
@@ -146,6 +156,7 @@ func (oc *OrdererConfig) Validate(tx interface{}, groups map[string]ValuePropose
 		oc.validateBatchSize,
 		oc.validateBatchTimeout,
 		oc.validateKafkaBrokers,
+		oc.validateRaftConsenters,
 	} {
 		if err := validator(); err != nil {
 			return err
@@ -200,6 +211,20 @@ func (oc *OrdererConfig) validateKafkaBrokers() error {
 	return nil
 }
 
+func (oc *OrdererConfig) validateRaftConsenters() error {
+	seen := make(map[string]struct{})
+	for _, consenter := range oc.protos.RaftConsenters.Consenters {
+		if !brokerEntrySeemsValid(consenter) {
+			return fmt.Errorf("Invalid raft consenter entry: %s", consenter)
+		}
+		if _, ok := seen[consenter]; ok {
+			return fmt.Errorf("Duplicate raft consenter entry: %s", consenter)
+		}
+		seen[consenter] = struct{}{}
+	}
+	return nil
+}
+
 // This does just a barebones sanity check.
 func brokerEntrySeemsValid(broker string) bool {
 	if !strings.Contains(broker, ":") {
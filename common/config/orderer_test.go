@@ -83,3 +83,15 @@ func TestKafkaBrokers(t *testing.T) {
 	oc = &OrdererConfig{protos: &OrdererProtos{KafkaBrokers: &ab.KafkaBrokers{Brokers: []string{"127.0.0.1", "foo.bar", "127.0.0.1:-1", "localhost:65536", "foo.bar.:9092", ".127.0.0.1:9092", "-foo.bar:9092"}}}}
 	assert.Error(t, oc.validateKafkaBrokers(), "Invalid kafka brokers")
 }
+
+func TestRaftConsenters(t *testing.T) {
+	oc := &OrdererConfig{protos: &OrdererProtos{RaftConsenters: &ab.RaftConsenters{Consenters: []string{"127.0.0.1:7090", "orderer1.example.com:7090"}}}}
+	assert.NoError(t, oc.validateRaftConsenters(), "Valid raft consenters")
+	assert.Equal(t, []string{"127.0.0.1:7090", "orderer1.example.com:7090"}, oc.RaftConsenters())
+
+	oc = &OrdererConfig{protos: &OrdererProtos{RaftConsenters: &ab.RaftConsenters{Consenters: []string{"orderer0.example.com"}}}}
+	assert.Error(t, oc.validateRaftConsenters(), "Invalid raft consenter")
+
+	oc = &OrdererConfig{protos: &OrdererProtos{RaftConsenters: &ab.RaftConsenters{Consenters: []string{"127.0.0.1:7090", "127.0.0.1:7090"}}}}
+	assert.Error(t, oc.validateRaftConsenters(), "Duplicate raft consenter")
+}
@@ -55,3 +55,8 @@ func TemplateChannelRestrictions(maxChannels uint64) *cb.ConfigGroup {
 func TemplateKafkaBrokers(brokers []string) *cb.ConfigGroup {
 	return ordererConfigGroup(KafkaBrokersKey, utils.MarshalOrPanic(&ab.KafkaBrokers{Brokers: brokers}))
 }
+
+// TemplateRaftConsenters creates a headerless config item representing the raft consenters
+func TemplateRaftConsenters(consenters []string) *cb.ConfigGroup {
+	return ordererConfigGroup(RaftConsentersKey, utils.MarshalOrPanic(&ab.RaftConsenters{Consenters: consenters}))
+}
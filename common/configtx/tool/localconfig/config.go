@@ -59,6 +59,8 @@ const (
 	SampleInsecureProfile = "SampleInsecureSolo"
 	// SampleSingleMSPSoloProfile references the sample profile which includes only the sample MSP and uses solo for ordering.
 	SampleSingleMSPSoloProfile = "SampleSingleMSPSolo"
+	// SampleInsecureRaftProfile references the sample profile which does not include any MSPs and uses Raft for ordering.
+	SampleInsecureRaftProfile = "SampleInsecureRaft"
 
 	// SampleConsortiumName is the sample consortium from the sample configtx.yaml
 	SampleConsortiumName = "SampleConsortium"
@@ -95,6 +97,7 @@ type Consortium struct {
 // Application encodes the application-level configuration needed in config transactions.
 type Application struct {
 	Organizations []*Organization `yaml:"Organizations"`
+	TxReordering  bool            `yaml:"TxReordering"`
 }
 
 // Organization encodes the organization-level configuration needed in config transactions.
@@ -131,6 +134,7 @@ type Orderer struct {
 	BatchTimeout  time.Duration   `yaml:"BatchTimeout"`
 	BatchSize     BatchSize       `yaml:"BatchSize"`
 	Kafka         Kafka           `yaml:"Kafka"`
+	Raft          Raft            `yaml:"Raft"`
 	Organizations []*Organization `yaml:"Organizations"`
 	MaxChannels   uint64          `yaml:"MaxChannels"`
 }
@@ -147,6 +151,11 @@ type Kafka struct {
 	Brokers []string `yaml:"Brokers"`
 }
 
+// Raft contains configuration for the Raft-based orderer.
+type Raft struct {
+	Consenters []string `yaml:"Consenters"`
+}
+
 var genesisDefaults = TopLevel{
 	Orderer: &Orderer{
 		OrdererType:  "solo",
@@ -160,6 +169,9 @@ var genesisDefaults = TopLevel{
 		Kafka: Kafka{
 			Brokers: []string{"127.0.0.1:9092"},
 		},
+		Raft: Raft{
+			Consenters: []string{"127.0.0.1:7090"},
+		},
 	},
 }
 
@@ -257,6 +269,9 @@ func (p *Profile) completeInitialization(configDir string) {
 		case p.Orderer.Kafka.Brokers == nil:
 			logger.Infof("Orderer.Kafka.Brokers unset, setting to %v", genesisDefaults.Orderer.Kafka.Brokers)
 			p.Orderer.Kafka.Brokers = genesisDefaults.Orderer.Kafka.Brokers
+		case p.Orderer.Raft.Consenters == nil:
+			logger.Infof("Orderer.Raft.Consenters unset, setting to %v", genesisDefaults.Orderer.Raft.Consenters)
+			p.Orderer.Raft.Consenters = genesisDefaults.Orderer.Raft.Consenters
 		default:
 			return
 		}
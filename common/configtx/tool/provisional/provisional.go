@@ -65,6 +65,8 @@ const (
 	ConsensusTypeKafka = "kafka"
 	// ConsensusTypeSbft identifies the SBFT consensus implementation.
 	ConsensusTypeSbft = "sbft"
+	// ConsensusTypeRaft identifies the Raft-based consensus implementation.
+	ConsensusTypeRaft = "raft"
 
 	// TestChainID is the default value of ChainID. It is used by all testing
 	// networks. It it necessary to set and export this variable so that test
@@ -134,6 +136,8 @@ func New(conf *genesisconfig.Profile) Generator {
 		case ConsensusTypeSolo, ConsensusTypeSbft:
 		case ConsensusTypeKafka:
 			bs.ordererGroups = append(bs.ordererGroups, config.TemplateKafkaBrokers(conf.Orderer.Kafka.Brokers))
+		case ConsensusTypeRaft:
+			bs.ordererGroups = append(bs.ordererGroups, config.TemplateRaftConsenters(conf.Orderer.Raft.Consenters))
 		default:
 			panic(fmt.Errorf("Wrong consenter type value given: %s", conf.Orderer.OrdererType))
 		}
@@ -169,6 +173,10 @@ func New(conf *genesisconfig.Profile) Generator {
 			bs.applicationGroups = append(bs.applicationGroups, config.TemplateAnchorPeers(org.Name, anchorProtos))
 		}
 
+		if conf.Application.TxReordering {
+			bs.applicationGroups = append(bs.applicationGroups, config.TemplateTxReordering(true))
+		}
+
 	}
 
 	if conf.Consortiums != nil {
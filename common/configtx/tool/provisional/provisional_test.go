@@ -24,12 +24,13 @@ import (
 	cb "github.com/hyperledger/fabric/protos/common"
 )
 
-var confSolo *genesisconfig.Profile
+var confSolo, confRaft *genesisconfig.Profile
 var testCases []*genesisconfig.Profile
 
 func init() {
 	confSolo = genesisconfig.Load(genesisconfig.SampleSingleMSPSoloProfile)
-	testCases = []*genesisconfig.Profile{confSolo}
+	confRaft = genesisconfig.Load(genesisconfig.SampleInsecureRaftProfile)
+	testCases = []*genesisconfig.Profile{confSolo, confRaft}
 }
 
 func TestGenesisBlockHeader(t *testing.T) {
@@ -0,0 +1,44 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crypto
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/msp"
+)
+
+// ExpiresAt returns when the given serialized identity expires, or the zero
+// time if the identity is not an x509 certificate or could not be parsed
+func ExpiresAt(identityBytes []byte) time.Time {
+	sID := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(identityBytes, sID); err != nil {
+		return time.Time{}
+	}
+	bl, _ := pem.Decode(sID.IdBytes)
+	if bl == nil {
+		return time.Time{}
+	}
+	cert, err := x509.ParseCertificate(bl.Bytes)
+	if err != nil {
+		return time.Time{}
+	}
+	return cert.NotAfter
+}
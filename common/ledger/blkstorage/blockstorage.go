@@ -47,12 +47,17 @@ var (
 	ErrNotFoundInIndex = errors.New("Entry not found in index")
 	// ErrAttrNotIndexed is used to indicate that an attribute is not indexed
 	ErrAttrNotIndexed = errors.New("Attribute not indexed")
+	// ErrBlockPruned is used to indicate that the requested block has been removed from the block storage by pruning
+	ErrBlockPruned = errors.New("Block has been pruned")
 )
 
 // BlockStoreProvider provides an handle to a BlockStore
 type BlockStoreProvider interface {
 	CreateBlockStore(ledgerid string) (BlockStore, error)
 	OpenBlockStore(ledgerid string) (BlockStore, error)
+	// BootstrapBlockStore creates a block store whose first block is the given block instead of a genesis block.
+	// This is used for a ledger that is created from a snapshot taken at the height `lastBlock.Header.Number + 1`
+	BootstrapBlockStore(ledgerid string, lastBlock *common.Block) (BlockStore, error)
 	Exists(ledgerid string) (bool, error)
 	List() ([]string, error)
 	Close()
@@ -71,5 +76,29 @@ type BlockStore interface {
 	RetrieveTxByBlockNumTranNum(blockNum uint64, tranNum uint64) (*common.Envelope, error)
 	RetrieveBlockByTxID(txID string) (*common.Block, error)
 	RetrieveTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error)
+	// Prune removes the blocks preceding the block `firstBlockToRetain`. An implementation may retain
+	// some of these blocks if it removes blocks at a coarser granularity (e.g., whole block files).
+	// The last block of the chain is never removed
+	Prune(firstBlockToRetain uint64) error
+	// VerifyIntegrity reads all the stored blocks and checks the hash chaining of the blocks,
+	// the data hash of each block and the index entries of each block
+	VerifyIntegrity() (*IntegrityReport, error)
+	// RebuildIndex discards the index and recreates it from the stored blocks
+	RebuildIndex() error
 	Shutdown()
 }
+
+// IntegrityReport lists the inconsistencies found by `BlockStore.VerifyIntegrity`
+type IntegrityReport struct {
+	// FirstBlockNum and LastBlockNum are the numbers of the first and the last block read from the block storage
+	FirstBlockNum uint64
+	LastBlockNum  uint64
+	// NumBlocks is the number of blocks read from the block storage
+	NumBlocks uint64
+	// BlockIssues lists the blocks that cannot be read or that break the hash chain.
+	// These cannot be repaired from the block storage itself
+	BlockIssues []string
+	// IndexIssues lists the index entries that do not agree with the stored blocks.
+	// These are repaired by `BlockStore.RebuildIndex`
+	IndexIssues []string
+}
@@ -19,6 +19,7 @@ package fsblkstorage
 import (
 	"fmt"
 	"math"
+	"os"
 	"sync"
 	"sync/atomic"
 
@@ -39,7 +40,8 @@ const (
 )
 
 var (
-	blkMgrInfoKey = []byte("blkMgrInfo")
+	blkMgrInfoKey   = []byte("blkMgrInfo")
+	blkPruneInfoKey = []byte("blkPruneInfo")
 )
 
 type conf struct {
@@ -56,6 +58,7 @@ type blockfileMgr struct {
 	cpInfoCond        *sync.Cond
 	currentFileWriter *blockfileWriter
 	bcInfo            atomic.Value
+	pruneInfo         atomic.Value
 }
 
 /*
@@ -139,6 +142,17 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 		panic(fmt.Sprintf("Could not truncate current file to known size in db: %s", err))
 	}
 
+	// Load the information about the blocks removed by pruning (if any) and remove the block files
+	// that may have been left behind by a crash during pruning
+	pruneInfo, err := mgr.loadPruneInfo()
+	if err != nil {
+		panic(fmt.Sprintf("Could not get prune info from db: %s", err))
+	}
+	if err = removePrunedBlockfiles(rootDir, pruneInfo.firstFileSuffixNum); err != nil {
+		panic(fmt.Sprintf("Could not remove pruned block files: %s", err))
+	}
+	mgr.pruneInfo.Store(pruneInfo)
+
 	// Create a new KeyValue store database handler for the blocks index in the keyvalue database
 	mgr.index = newBlockIndex(indexConfig, indexStore)
 
@@ -323,6 +337,24 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 	return nil
 }
 
+// bootstrapFromBlock adds the given block as the first block of an empty block storage.
+// Subsequently, the block storage accepts the blocks starting from the number `block.Header.Number + 1`
+func (mgr *blockfileMgr) bootstrapFromBlock(block *common.Block) error {
+	if !mgr.cpInfo.isChainEmpty {
+		return fmt.Errorf("Block storage is not empty. Cannot bootstrap from block [%d]", block.Header.Number)
+	}
+	bcInfo := mgr.getBlockchainInfo()
+	mgr.bcInfo.Store(&common.BlockchainInfo{
+		Height:            block.Header.Number,
+		CurrentBlockHash:  block.Header.PreviousHash,
+		PreviousBlockHash: nil})
+	if err := mgr.addBlock(block); err != nil {
+		mgr.bcInfo.Store(bcInfo)
+		return err
+	}
+	return nil
+}
+
 func (mgr *blockfileMgr) syncIndex() error {
 	var lastBlockIndexed uint64
 	var indexEmpty bool
@@ -334,8 +366,8 @@ func (mgr *blockfileMgr) syncIndex() error {
 		}
 		indexEmpty = true
 	}
-	//initialize index to file number:zero, offset:zero and blockNum:0
-	startFileNum := 0
+	//initialize index to the first available file (file number zero, unless pruned), offset:zero and blockNum:0
+	startFileNum := mgr.getPruneInfo().firstFileSuffixNum
 	startOffset := 0
 	blockNum := uint64(0)
 	skipFirstBlock := false
@@ -433,6 +465,131 @@ func (mgr *blockfileMgr) updateBlockchainInfo(latestBlockHash []byte, latestBloc
 	mgr.bcInfo.Store(newBCInfo)
 }
 
+func (mgr *blockfileMgr) getPruneInfo() *pruneInfo {
+	return mgr.pruneInfo.Load().(*pruneInfo)
+}
+
+// checkNotPruned returns `ErrBlockPruned` if the given block has been removed by pruning
+func (mgr *blockfileMgr) checkNotPruned(blockNum uint64) error {
+	if blockNum < mgr.getPruneInfo().firstBlockNum {
+		return blkstorage.ErrBlockPruned
+	}
+	return nil
+}
+
+// prune removes the block files that contain only the blocks preceding the block `firstBlockToRetain`.
+// The current block file is never removed and hence, the last block is always retained.
+// The index entries of the removed blocks and the new prune info are committed in a single batch
+// before the files are deleted so that a crash leaves behind only the files that are removed on restart
+func (mgr *blockfileMgr) prune(firstBlockToRetain uint64) error {
+	currentPruneInfo := mgr.getPruneInfo()
+	if firstBlockToRetain <= currentPruneInfo.firstBlockNum {
+		return nil
+	}
+	newPruneInfo := &pruneInfo{currentPruneInfo.firstFileSuffixNum, currentPruneInfo.firstBlockNum}
+	batch := leveldbhelper.NewUpdateBatch()
+	for newPruneInfo.firstFileSuffixNum < mgr.cpInfo.latestFileChunkSuffixNum {
+		nextFileFirstBlockNum, found, err := mgr.firstBlockNumInFile(newPruneInfo.firstFileSuffixNum + 1)
+		if err != nil {
+			return err
+		}
+		if !found || nextFileFirstBlockNum > firstBlockToRetain {
+			break
+		}
+		if err = mgr.removeFileFromIndex(newPruneInfo.firstFileSuffixNum, batch); err != nil {
+			return err
+		}
+		newPruneInfo.firstFileSuffixNum++
+		newPruneInfo.firstBlockNum = nextFileFirstBlockNum
+	}
+	if newPruneInfo.firstFileSuffixNum == currentPruneInfo.firstFileSuffixNum {
+		logger.Debugf("No block file can be pruned for retaining blocks from [%d]", firstBlockToRetain)
+		return nil
+	}
+	b, err := newPruneInfo.marshal()
+	if err != nil {
+		return err
+	}
+	batch.Put(blkPruneInfoKey, b)
+	if err = mgr.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	mgr.pruneInfo.Store(newPruneInfo)
+	logger.Infof("Pruned blocks [%d] to [%d]", currentPruneInfo.firstBlockNum, newPruneInfo.firstBlockNum-1)
+	return removePrunedBlockfiles(mgr.rootDir, newPruneInfo.firstFileSuffixNum)
+}
+
+// firstBlockNumInFile returns the number of the first block in the given file.
+// False is returned if the file does not contain any block
+func (mgr *blockfileMgr) firstBlockNumInFile(fileNum int) (uint64, bool, error) {
+	stream, err := newBlockfileStream(mgr.rootDir, fileNum, 0)
+	if err != nil {
+		return 0, false, err
+	}
+	defer stream.close()
+	blockBytes, err := stream.nextBlockBytes()
+	if err != nil || blockBytes == nil {
+		return 0, false, err
+	}
+	info, err := extractSerializedBlockInfo(blockBytes)
+	if err != nil {
+		return 0, false, err
+	}
+	return info.blockHeader.Number, true, nil
+}
+
+// removeFileFromIndex adds to the batch the deletes for the index entries of all the blocks in the given file
+func (mgr *blockfileMgr) removeFileFromIndex(fileNum int, batch *leveldbhelper.UpdateBatch) error {
+	stream, err := newBlockfileStream(mgr.rootDir, fileNum, 0)
+	if err != nil {
+		return err
+	}
+	defer stream.close()
+	for {
+		blockBytes, placementInfo, err := stream.nextBlockBytesAndPlacementInfo()
+		if err != nil {
+			return err
+		}
+		if blockBytes == nil {
+			return nil
+		}
+		info, err := extractSerializedBlockInfo(blockBytes)
+		if err != nil {
+			return err
+		}
+		blockIdxInfo := &blockIdxInfo{
+			blockNum:  info.blockHeader.Number,
+			blockHash: info.blockHeader.Hash(),
+			flp: &fileLocPointer{fileSuffixNum: placementInfo.fileNum,
+				locPointer: locPointer{offset: int(placementInfo.blockStartOffset)}},
+			txOffsets: info.txOffsets,
+			metadata:  info.metadata}
+		if err = mgr.index.removeBlockIndex(blockIdxInfo, batch); err != nil {
+			return err
+		}
+	}
+}
+
+// removePrunedBlockfiles deletes the block files preceding the file `firstFileSuffixNum`
+func removePrunedBlockfiles(rootDir string, firstFileSuffixNum int) error {
+	for fileNum := firstFileSuffixNum - 1; fileNum >= 0; fileNum-- {
+		filePath := deriveBlockfilePath(rootDir, fileNum)
+		exists, _, err := util.FileExists(filePath)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			// files preceding this one have been removed by an earlier pruning
+			return nil
+		}
+		logger.Debugf("Removing pruned block file [%s]", filePath)
+		if err = os.Remove(filePath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (mgr *blockfileMgr) retrieveBlockByHash(blockHash []byte) (*common.Block, error) {
 	logger.Debugf("retrieveBlockByHash() - blockHash = [%#v]", blockHash)
 	loc, err := mgr.index.getBlockLocByHash(blockHash)
@@ -449,6 +606,9 @@ func (mgr *blockfileMgr) retrieveBlockByNumber(blockNum uint64) (*common.Block,
 	if blockNum == math.MaxUint64 {
 		blockNum = mgr.getBlockchainInfo().Height - 1
 	}
+	if err := mgr.checkNotPruned(blockNum); err != nil {
+		return nil, err
+	}
 
 	loc, err := mgr.index.getBlockLocByBlockNum(blockNum)
 	if err != nil {
@@ -475,6 +635,9 @@ func (mgr *blockfileMgr) retrieveTxValidationCodeByTxID(txID string) (peer.TxVal
 
 func (mgr *blockfileMgr) retrieveBlockHeaderByNumber(blockNum uint64) (*common.BlockHeader, error) {
 	logger.Debugf("retrieveBlockHeaderByNumber() - blockNum = [%d]", blockNum)
+	if err := mgr.checkNotPruned(blockNum); err != nil {
+		return nil, err
+	}
 	loc, err := mgr.index.getBlockLocByBlockNum(blockNum)
 	if err != nil {
 		return nil, err
@@ -505,6 +668,9 @@ func (mgr *blockfileMgr) retrieveTransactionByID(txID string) (*common.Envelope,
 
 func (mgr *blockfileMgr) retrieveTransactionByBlockNumTranNum(blockNum uint64, tranNum uint64) (*common.Envelope, error) {
 	logger.Debugf("retrieveTransactionByBlockNumTranNum() - blockNum = [%d], tranNum = [%d]", blockNum, tranNum)
+	if err := mgr.checkNotPruned(blockNum); err != nil {
+		return nil, err
+	}
 	loc, err := mgr.index.getTXLocByBlockNumTranNum(blockNum, tranNum)
 	if err != nil {
 		return nil, err
@@ -577,6 +743,23 @@ func (mgr *blockfileMgr) loadCurrentInfo() (*checkpointInfo, error) {
 	return i, nil
 }
 
+//Get the information about the blocks removed by pruning. A zero value is returned if the blocks were never pruned
+func (mgr *blockfileMgr) loadPruneInfo() (*pruneInfo, error) {
+	b, err := mgr.db.Get(blkPruneInfoKey)
+	if err != nil {
+		return nil, err
+	}
+	i := &pruneInfo{}
+	if b == nil {
+		return i, nil
+	}
+	if err = i.unmarshal(b); err != nil {
+		return nil, err
+	}
+	logger.Debugf("loaded pruneInfo:%s", i)
+	return i, nil
+}
+
 func (mgr *blockfileMgr) saveCurrentInfo(i *checkpointInfo, sync bool) error {
 	b, err := i.marshal()
 	if err != nil {
@@ -678,3 +861,41 @@ func (i *checkpointInfo) String() string {
 	return fmt.Sprintf("latestFileChunkSuffixNum=[%d], latestFileChunksize=[%d], isChainEmpty=[%t], lastBlockNumber=[%d]",
 		i.latestFileChunkSuffixNum, i.latestFileChunksize, i.isChainEmpty, i.lastBlockNumber)
 }
+
+// pruneInfo captures the first block file and the first block that are available after pruning
+type pruneInfo struct {
+	firstFileSuffixNum int
+	firstBlockNum      uint64
+}
+
+func (i *pruneInfo) marshal() ([]byte, error) {
+	buffer := proto.NewBuffer([]byte{})
+	var err error
+	if err = buffer.EncodeVarint(uint64(i.firstFileSuffixNum)); err != nil {
+		return nil, err
+	}
+	if err = buffer.EncodeVarint(i.firstBlockNum); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (i *pruneInfo) unmarshal(b []byte) error {
+	buffer := proto.NewBuffer(b)
+	var val uint64
+	var err error
+
+	if val, err = buffer.DecodeVarint(); err != nil {
+		return err
+	}
+	i.firstFileSuffixNum = int(val)
+
+	if i.firstBlockNum, err = buffer.DecodeVarint(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (i *pruneInfo) String() string {
+	return fmt.Sprintf("firstFileSuffixNum=[%d], firstBlockNum=[%d]", i.firstFileSuffixNum, i.firstBlockNum)
+}
@@ -18,10 +18,13 @@ package fsblkstorage
 
 import (
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/ledger/util"
 
 	"github.com/hyperledger/fabric/protos/common"
 	putil "github.com/hyperledger/fabric/protos/utils"
@@ -232,6 +235,65 @@ func TestBlockfileMgrFileRolling(t *testing.T) {
 	blkfileMgrWrapper.testGetBlockByHash(blocks[100:])
 }
 
+func TestBlockfileMgrPrune(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 30)
+	size := 0
+	for _, block := range blocks[:10] {
+		by, _, err := serializeBlock(block)
+		testutil.AssertNoError(t, err, "Error while serializing block")
+		size += len(by) + len(proto.EncodeVarint(uint64(len(by))))
+	}
+	// each block file can accommodate around 10 blocks
+	env := newTestEnv(t, NewConf(testPath(), size+size/20))
+	defer env.Cleanup()
+	ledgerid := "testLedger"
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+	blkfileMgrWrapper.addBlocks(blocks)
+	blkfileMgr := blkfileMgrWrapper.blockfileMgr
+	testutil.AssertEquals(t, blkfileMgr.cpInfo.latestFileChunkSuffixNum, 2)
+	secondFileFirstBlockNum, _, err := blkfileMgr.firstBlockNumInFile(1)
+	testutil.AssertNoError(t, err, "")
+
+	// retaining a block from the first file should not prune anything
+	testutil.AssertNoError(t, blkfileMgr.prune(secondFileFirstBlockNum-1), "")
+	_, err = blkfileMgr.retrieveBlockByNumber(0)
+	testutil.AssertNoError(t, err, "")
+
+	// retaining a block from the second file should prune the first file only
+	testutil.AssertNoError(t, blkfileMgr.prune(secondFileFirstBlockNum+1), "")
+	exists, _, _ := util.FileExists(deriveBlockfilePath(blkfileMgr.rootDir, 0))
+	testutil.AssertEquals(t, exists, false)
+	for _, block := range blocks[:secondFileFirstBlockNum] {
+		_, err = blkfileMgr.retrieveBlockByNumber(block.Header.Number)
+		testutil.AssertEquals(t, err, blkstorage.ErrBlockPruned)
+		_, err = blkfileMgr.retrieveBlockByHash(block.Header.Hash())
+		testutil.AssertEquals(t, err, blkstorage.ErrNotFoundInIndex)
+		txID, _ := extractTxID(block.Data.Data[0])
+		_, err = blkfileMgr.retrieveTransactionByID(txID)
+		testutil.AssertEquals(t, err, blkstorage.ErrNotFoundInIndex)
+	}
+	blkfileMgrWrapper.testGetBlockByHash(blocks[secondFileFirstBlockNum:])
+	itr, err := blkfileMgr.retrieveBlocks(0)
+	testutil.AssertNoError(t, err, "")
+	_, err = itr.Next()
+	testutil.AssertEquals(t, err, blkstorage.ErrBlockPruned)
+	itr.Close()
+
+	// the last block file is never pruned and the pruning survives a restart
+	testutil.AssertNoError(t, blkfileMgr.prune(math.MaxUint64), "")
+	blkfileMgrWrapper.close()
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	blkfileMgr = blkfileMgrWrapper.blockfileMgr
+	thirdFileFirstBlockNum, _, err := blkfileMgr.firstBlockNumInFile(2)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, blkfileMgr.getPruneInfo(), &pruneInfo{2, thirdFileFirstBlockNum})
+	_, err = blkfileMgr.retrieveBlockByNumber(thirdFileFirstBlockNum - 1)
+	testutil.AssertEquals(t, err, blkstorage.ErrBlockPruned)
+	blkfileMgrWrapper.testGetBlockByNumber(blocks[thirdFileFirstBlockNum:], thirdFileFirstBlockNum)
+	testutil.AssertEquals(t, blkfileMgr.getBlockchainInfo().Height, uint64(30))
+}
+
 func TestBlockfileMgrGetBlockByTxID(t *testing.T) {
 	env := newTestEnv(t, NewConf(testPath(), 0))
 	defer env.Cleanup()
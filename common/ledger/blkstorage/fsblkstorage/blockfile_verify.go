@@ -0,0 +1,207 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fsblkstorage
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+// maxIndexDeletesPerBatch limits the number of index entries removed in a single batch while rebuilding the index
+const maxIndexDeletesPerBatch = 10000
+
+// verifyIntegrity reads all the blocks from the block files and checks that
+//  -- each block can be read and its number follows the number of the preceding block
+//  -- the previous hash in the header of each block matches the hash of the header of the preceding block
+//  -- the data hash in the header of each block matches the hash of the data of the block
+//  -- the index entries of each block point to the location of the block in the block files
+//  -- the checkpoint info and the index checkpoint agree with the last block in the block files
+// The blocks are read directly from the block files so that the verification does not depend on the index
+func (mgr *blockfileMgr) verifyIntegrity() (*blkstorage.IntegrityReport, error) {
+	report := &blkstorage.IntegrityReport{}
+	if mgr.cpInfo.isChainEmpty {
+		return report, nil
+	}
+	pruneInfo := mgr.getPruneInfo()
+	stream, err := newBlockStream(mgr.rootDir, pruneInfo.firstFileSuffixNum, 0, mgr.cpInfo.latestFileChunkSuffixNum)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.close()
+
+	var previousHeader *common.BlockHeader
+	for {
+		blockBytes, placementInfo, err := stream.nextBlockBytesAndPlacementInfo()
+		if err != nil {
+			report.BlockIssues = append(report.BlockIssues,
+				fmt.Sprintf("Error reading the block following block [%d]: %s", report.LastBlockNum, err))
+			break
+		}
+		if blockBytes == nil {
+			break
+		}
+		block, err := deserializeBlock(blockBytes)
+		if err != nil {
+			report.BlockIssues = append(report.BlockIssues,
+				fmt.Sprintf("Block at [%s] cannot be deserialized: %s", placementInfo, err))
+			previousHeader = nil
+			continue
+		}
+		blockNum := block.Header.Number
+		switch {
+		case report.NumBlocks == 0:
+			report.FirstBlockNum = blockNum
+			if pruneInfo.firstBlockNum != 0 && blockNum != pruneInfo.firstBlockNum {
+				report.BlockIssues = append(report.BlockIssues,
+					fmt.Sprintf("First block is [%d] while the blocks preceding block [%d] are recorded as pruned",
+						blockNum, pruneInfo.firstBlockNum))
+			}
+		case previousHeader == nil:
+			// the preceding block could not be deserialized and has already been reported
+		case blockNum != previousHeader.Number+1:
+			report.BlockIssues = append(report.BlockIssues,
+				fmt.Sprintf("Block [%d] follows block [%d]", blockNum, previousHeader.Number))
+		case !bytes.Equal(block.Header.PreviousHash, previousHeader.Hash()):
+			report.BlockIssues = append(report.BlockIssues,
+				fmt.Sprintf("Previous hash of block [%d] does not match the hash of block [%d]", blockNum, previousHeader.Number))
+		}
+		if !bytes.Equal(block.Header.DataHash, block.Data.Hash()) {
+			report.BlockIssues = append(report.BlockIssues, fmt.Sprintf("Data hash of block [%d] does not match its data", blockNum))
+		}
+		blockIdxInfo, err := newBlockIdxInfo(blockBytes, placementInfo)
+		if err != nil {
+			return nil, err
+		}
+		report.IndexIssues = append(report.IndexIssues, mgr.verifyBlockIndex(blockIdxInfo)...)
+		report.LastBlockNum = blockNum
+		report.NumBlocks++
+		previousHeader = block.Header
+	}
+
+	if report.NumBlocks == 0 {
+		report.BlockIssues = append(report.BlockIssues, "No block found in the block files")
+		return report, nil
+	}
+	if report.LastBlockNum != mgr.cpInfo.lastBlockNumber {
+		report.BlockIssues = append(report.BlockIssues,
+			fmt.Sprintf("Last block in the block files is [%d] while the checkpoint records block [%d]",
+				report.LastBlockNum, mgr.cpInfo.lastBlockNumber))
+	}
+	lastBlockIndexed, err := mgr.index.getLastBlockIndexed()
+	switch {
+	case err == errIndexEmpty:
+		// the index checkpoint is not maintained if the index is configured not to index anything
+		if _, err = mgr.index.getBlockLocByBlockNum(report.LastBlockNum); err != blkstorage.ErrAttrNotIndexed {
+			report.IndexIssues = append(report.IndexIssues, "No block has been indexed")
+		}
+	case err != nil:
+		return nil, err
+	case lastBlockIndexed != report.LastBlockNum:
+		report.IndexIssues = append(report.IndexIssues,
+			fmt.Sprintf("Last block indexed is [%d] while the last block in the block files is [%d]",
+				lastBlockIndexed, report.LastBlockNum))
+	}
+	return report, nil
+}
+
+// verifyBlockIndex returns the index entries of the block that are missing or that do not point to the block.
+// The entries keyed by a transaction ID are only checked for presence, because a transaction ID that is
+// repeated in a later block legitimately points to the later block
+func (mgr *blockfileMgr) verifyBlockIndex(blockIdxInfo *blockIdxInfo) []string {
+	var issues []string
+	blockNum := blockIdxInfo.blockNum
+	flp, err := mgr.index.getBlockLocByBlockNum(blockNum)
+	issues = appendIndexIssue(issues, fmt.Sprintf("block number entry of block [%d]", blockNum), flp, err, blockIdxInfo.flp)
+	flp, err = mgr.index.getBlockLocByHash(blockIdxInfo.blockHash)
+	issues = appendIndexIssue(issues, fmt.Sprintf("block hash entry of block [%d]", blockNum), flp, err, blockIdxInfo.flp)
+	for txNum, txOffset := range blockIdxInfo.txOffsets {
+		txFLP := newFileLocationPointer(blockIdxInfo.flp.fileSuffixNum, blockIdxInfo.flp.offset, txOffset.loc)
+		flp, err = mgr.index.getTXLocByBlockNumTranNum(blockNum, uint64(txNum))
+		issues = appendIndexIssue(issues, fmt.Sprintf("entry of transaction [%d:%d]", blockNum, txNum), flp, err, txFLP)
+		if txOffset.txID == "" {
+			continue
+		}
+		flp, err = mgr.index.getTxLoc(txOffset.txID)
+		issues = appendIndexIssue(issues, fmt.Sprintf("transaction ID entry of transaction [%d:%d]", blockNum, txNum), flp, err, nil)
+	}
+	return issues
+}
+
+// appendIndexIssue appends an issue if the index entry could not be retrieved or if it does not point to
+// the expected location. A nil expected location only checks that the entry is present
+func appendIndexIssue(issues []string, entry string, flp *fileLocPointer, err error, expected *fileLocPointer) []string {
+	switch {
+	case err == blkstorage.ErrAttrNotIndexed:
+		return issues
+	case err == blkstorage.ErrNotFoundInIndex:
+		return append(issues, fmt.Sprintf("Index %s is missing", entry))
+	case err != nil:
+		return append(issues, fmt.Sprintf("Index %s cannot be read: %s", entry, err))
+	case expected != nil && *flp != *expected:
+		return append(issues, fmt.Sprintf("Index %s points to [%s] instead of [%s]", entry, flp, expected))
+	}
+	return issues
+}
+
+// rebuildIndex removes all the index entries and indexes the blocks again from the block files.
+// The checkpoint info and the prune info, which are stored alongside the index, are retained
+func (mgr *blockfileMgr) rebuildIndex() error {
+	logger.Infof("Rebuilding the block index from the block files in [%s]", mgr.rootDir)
+	itr := mgr.db.GetIterator(nil, nil)
+	defer itr.Release()
+	batch := leveldbhelper.NewUpdateBatch()
+	for itr.Next() {
+		key := itr.Key()
+		if bytes.Equal(key, blkMgrInfoKey) || bytes.Equal(key, blkPruneInfoKey) {
+			continue
+		}
+		batch.Delete(key)
+		if len(batch.KVs) == maxIndexDeletesPerBatch {
+			if err := mgr.db.WriteBatch(batch, false); err != nil {
+				return err
+			}
+			batch = leveldbhelper.NewUpdateBatch()
+		}
+	}
+	if err := mgr.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	return mgr.syncIndex()
+}
+
+// newBlockIdxInfo constructs the index information for the serialized block read from the given placement in the block files
+func newBlockIdxInfo(blockBytes []byte, placementInfo *blockPlacementInfo) (*blockIdxInfo, error) {
+	info, err := extractSerializedBlockInfo(blockBytes)
+	if err != nil {
+		return nil, err
+	}
+	// the tx offsets are relative to the block bytes, which follow the encoded length of the block
+	numBytesToShift := int(placementInfo.blockBytesOffset - placementInfo.blockStartOffset)
+	for _, offset := range info.txOffsets {
+		offset.loc.offset += numBytesToShift
+	}
+	return &blockIdxInfo{
+		blockNum:  info.blockHeader.Number,
+		blockHash: info.blockHeader.Hash(),
+		flp: &fileLocPointer{fileSuffixNum: placementInfo.fileNum,
+			locPointer: locPointer{offset: int(placementInfo.blockStartOffset)}},
+		txOffsets: info.txOffsets,
+		metadata:  info.metadata}, nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fsblkstorage
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+func TestBlockfileMgrVerifyIntegrity(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 30)
+	// each block file can accommodate around 10 blocks
+	env := newTestEnv(t, NewConf(testPath(), 10*blockSizeOnDisk(t, blocks[1])))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	blkfileMgrWrapper.addBlocks(blocks)
+	blkfileMgr := blkfileMgrWrapper.blockfileMgr
+
+	report, err := blkfileMgr.verifyIntegrity()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, report, &blkstorage.IntegrityReport{FirstBlockNum: 0, LastBlockNum: 29, NumBlocks: 30})
+
+	// remove and corrupt some of the index entries
+	testutil.AssertNoError(t, blkfileMgr.db.Delete(constructBlockHashKey(blocks[5].Header.Hash()), true), "")
+	testutil.AssertNoError(t, blkfileMgr.db.Delete(constructBlockNumTranNumKey(6, 0), true), "")
+	flpBytes, err := blkfileMgr.db.Get(constructBlockNumKey(8))
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNoError(t, blkfileMgr.db.Put(constructBlockNumKey(7), flpBytes, true), "")
+	testutil.AssertNoError(t, blkfileMgr.db.Put(indexCheckpointKey, encodeBlockNum(25), true), "")
+	report, err = blkfileMgr.verifyIntegrity()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, len(report.BlockIssues), 0)
+	testutil.AssertEquals(t, len(report.IndexIssues), 4)
+	testutil.AssertContains(t, report.IndexIssues, "Index block hash entry of block [5] is missing")
+	testutil.AssertContains(t, report.IndexIssues, "Index entry of transaction [6:0] is missing")
+	testutil.AssertContains(t, report.IndexIssues, "Last block indexed is [25] while the last block in the block files is [29]")
+
+	testutil.AssertNoError(t, blkfileMgr.rebuildIndex(), "")
+	report, err = blkfileMgr.verifyIntegrity()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, report, &blkstorage.IntegrityReport{FirstBlockNum: 0, LastBlockNum: 29, NumBlocks: 30})
+	blkfileMgrWrapper.testGetBlockByHash(blocks)
+	blkfileMgrWrapper.testGetBlockByNumber(blocks, 0)
+
+	// the pruned blocks are not expected to be present
+	secondFileFirstBlockNum, _, err := blkfileMgr.firstBlockNumInFile(1)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNoError(t, blkfileMgr.prune(secondFileFirstBlockNum), "")
+	testutil.AssertNoError(t, blkfileMgr.rebuildIndex(), "")
+	report, err = blkfileMgr.verifyIntegrity()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, report,
+		&blkstorage.IntegrityReport{FirstBlockNum: secondFileFirstBlockNum, LastBlockNum: 29, NumBlocks: 30 - secondFileFirstBlockNum})
+	blkfileMgrWrapper.testGetBlockByHash(blocks[secondFileFirstBlockNum:])
+}
+
+func TestBlockfileMgrVerifyIntegrityBrokenChain(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	blocks[4].Header.DataHash = []byte("tampered-data-hash")
+	blkfileMgrWrapper.addBlocks(blocks)
+
+	report, err := blkfileMgrWrapper.blockfileMgr.verifyIntegrity()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, report.BlockIssues, []string{
+		"Data hash of block [4] does not match its data",
+		"Previous hash of block [5] does not match the hash of block [4]",
+	})
+	testutil.AssertEquals(t, len(report.IndexIssues), 0)
+}
+
+func blockSizeOnDisk(t *testing.T, block *common.Block) int {
+	blockBytes, _, err := serializeBlock(block)
+	testutil.AssertNoError(t, err, "Error while serializing block")
+	return len(blockBytes) + len(proto.EncodeVarint(uint64(len(blockBytes))))
+}
@@ -46,6 +46,7 @@ var errIndexEmpty = errors.New("NoBlockIndexed")
 type index interface {
 	getLastBlockIndexed() (uint64, error)
 	indexBlock(blockIdxInfo *blockIdxInfo) error
+	removeBlockIndex(blockIdxInfo *blockIdxInfo, batch *leveldbhelper.UpdateBatch) error
 	getBlockLocByHash(blockHash []byte) (*fileLocPointer, error)
 	getBlockLocByBlockNum(blockNum uint64) (*fileLocPointer, error)
 	getTxLoc(txID string) (*fileLocPointer, error)
@@ -162,6 +163,68 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 	return nil
 }
 
+// removeBlockIndex adds to the batch the deletes for the index entries of a block that is being pruned.
+// The entries keyed by a transaction ID are left intact if they point to a block that is not being pruned,
+// which is possible if a transaction ID is repeated in a later block
+func (index *blockIndex) removeBlockIndex(blockIdxInfo *blockIdxInfo, batch *leveldbhelper.UpdateBatch) error {
+	if len(index.indexItemsMap) == 0 {
+		return nil
+	}
+	logger.Debugf("Removing index entries of block [%d]", blockIdxInfo.blockNum)
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockHash]; ok {
+		batch.Delete(constructBlockHashKey(blockIdxInfo.blockHash))
+	}
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockNum]; ok {
+		batch.Delete(constructBlockNumKey(blockIdxInfo.blockNum))
+	}
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockNumTranNum]; ok {
+		for txIterator := range blockIdxInfo.txOffsets {
+			batch.Delete(constructBlockNumTranNumKey(blockIdxInfo.blockNum, uint64(txIterator)))
+		}
+	}
+	for _, txoffset := range blockIdxInfo.txOffsets {
+		txInBlock, err := index.isTxIndexedInBlock(txoffset.txID, blockIdxInfo.flp)
+		if err != nil {
+			return err
+		}
+		if !txInBlock {
+			continue
+		}
+		if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxID]; ok {
+			batch.Delete(constructTxIDKey(txoffset.txID))
+		}
+		if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockTxID]; ok {
+			batch.Delete(constructBlockTxIDKey(txoffset.txID))
+		}
+		if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxValidationCode]; ok {
+			batch.Delete(constructTxValidationCodeIDKey(txoffset.txID))
+		}
+	}
+	return nil
+}
+
+// isTxIndexedInBlock returns true if the index entries for the transaction ID point to the block at the given location.
+// If the block location cannot be determined from the index, the entries are assumed to point to the block
+func (index *blockIndex) isTxIndexedInBlock(txID string, blockFLP *fileLocPointer) (bool, error) {
+	var b []byte
+	var err error
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockTxID]; ok {
+		b, err = index.db.Get(constructBlockTxIDKey(txID))
+	} else if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxID]; ok {
+		b, err = index.db.Get(constructTxIDKey(txID))
+	} else {
+		return true, nil
+	}
+	if err != nil || b == nil {
+		return false, err
+	}
+	flp := &fileLocPointer{}
+	if err = flp.unmarshal(b); err != nil {
+		return false, err
+	}
+	return flp.fileSuffixNum == blockFLP.fileSuffixNum, nil
+}
+
 func (index *blockIndex) getBlockLocByHash(blockHash []byte) (*fileLocPointer, error) {
 	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockHash]; !ok {
 		return nil, blkstorage.ErrAttrNotIndexed
@@ -22,6 +22,7 @@ import (
 
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/peer"
@@ -37,6 +38,9 @@ func (i *noopIndex) getLastBlockIndexed() (uint64, error) {
 func (i *noopIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 	return nil
 }
+func (i *noopIndex) removeBlockIndex(blockIdxInfo *blockIdxInfo, batch *leveldbhelper.UpdateBatch) error {
+	return nil
+}
 func (i *noopIndex) getBlockLocByHash(blockHash []byte) (*fileLocPointer, error) {
 	return nil, nil
 }
@@ -73,6 +73,9 @@ func (itr *blocksItr) waitForBlock(blockNum uint64) uint64 {
 func (itr *blocksItr) initStream() error {
 	var lp *fileLocPointer
 	var err error
+	if err = itr.mgr.checkNotPruned(itr.blockNumToRetrieve); err != nil {
+		return err
+	}
 	if lp, err = itr.mgr.index.getBlockLocByBlockNum(itr.blockNumToRetrieve); err != nil {
 		return err
 	}
@@ -119,5 +122,7 @@ func (itr *blocksItr) Close() {
 	itr.mgr.cpInfoCond.L.Lock()
 	defer itr.mgr.cpInfoCond.L.Unlock()
 	itr.mgr.cpInfoCond.Broadcast()
-	itr.stream.close()
+	if itr.stream != nil {
+		itr.stream.close()
+	}
 }
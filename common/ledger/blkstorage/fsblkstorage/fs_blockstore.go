@@ -86,6 +86,21 @@ func (store *fsBlockStore) RetrieveTxValidationCodeByTxID(txID string) (peer.TxV
 	return store.fileMgr.retrieveTxValidationCodeByTxID(txID)
 }
 
+// Prune removes the block files that contain only the blocks preceding the block `firstBlockToRetain`
+func (store *fsBlockStore) Prune(firstBlockToRetain uint64) error {
+	return store.fileMgr.prune(firstBlockToRetain)
+}
+
+// VerifyIntegrity checks the blocks in the block files against each other and against the index
+func (store *fsBlockStore) VerifyIntegrity() (*blkstorage.IntegrityReport, error) {
+	return store.fileMgr.verifyIntegrity()
+}
+
+// RebuildIndex discards the index and indexes the blocks again from the block files
+func (store *fsBlockStore) RebuildIndex() error {
+	return store.fileMgr.rebuildIndex()
+}
+
 // Shutdown shuts down the block store
 func (store *fsBlockStore) Shutdown() {
 	logger.Debugf("closing fs blockStore:%s", store.id)
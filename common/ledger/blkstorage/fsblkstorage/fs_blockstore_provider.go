@@ -20,6 +20,7 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/protos/common"
 )
 
 // FsBlockstoreProvider provides handle to block storage - this is not thread-safe
@@ -48,6 +49,18 @@ func (p *FsBlockstoreProvider) OpenBlockStore(ledgerid string) (blkstorage.Block
 	return newFsBlockStore(ledgerid, p.conf, p.indexConfig, indexStoreHandle), nil
 }
 
+// BootstrapBlockStore opens a block store for given ledgerid and adds the lastBlock as the first block in it.
+// The block store is expected to be empty. Blocks prior to lastBlock are not available from the returned block store
+func (p *FsBlockstoreProvider) BootstrapBlockStore(ledgerid string, lastBlock *common.Block) (blkstorage.BlockStore, error) {
+	indexStoreHandle := p.leveldbProvider.GetDBHandle(ledgerid)
+	store := newFsBlockStore(ledgerid, p.conf, p.indexConfig, indexStoreHandle)
+	if err := store.fileMgr.bootstrapFromBlock(lastBlock); err != nil {
+		store.Shutdown()
+		return nil, err
+	}
+	return store, nil
+}
+
 // Exists tells whether the BlockStore with given id exists
 func (p *FsBlockstoreProvider) Exists(ledgerid string) (bool, error) {
 	exists, _, err := util.FileExists(p.conf.getLedgerBlockDir(ledgerid))
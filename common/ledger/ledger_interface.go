@@ -17,6 +17,8 @@ limitations under the License.
 package ledger
 
 import (
+	"time"
+
 	"github.com/hyperledger/fabric/protos/common"
 )
 
@@ -56,5 +58,20 @@ type BlockHolder interface {
 	GetBlockBytes() []byte
 }
 
-// PrunePolicy - a general interface for supporting different pruning policies
+// PrunePolicy - a general interface for supporting different pruning policies.
+// The supported policies are `KeepLastNBlocks`, `KeepBlocksNewerThan`, and `KeepBlocksSinceLastConfig`
 type PrunePolicy interface{}
+
+// KeepLastNBlocks is a prune policy that retains the last `NumBlocks` blocks of the chain
+type KeepLastNBlocks struct {
+	NumBlocks uint64
+}
+
+// KeepBlocksNewerThan is a prune policy that retains the blocks that carry transactions
+// whose timestamps are not older than `Time`
+type KeepBlocksNewerThan struct {
+	Time time.Time
+}
+
+// KeepBlocksSinceLastConfig is a prune policy that retains the last config block and the blocks after it
+type KeepBlocksSinceLastConfig struct{}
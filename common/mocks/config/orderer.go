@@ -32,6 +32,8 @@ type Orderer struct {
 	BatchTimeoutVal time.Duration
 	// KafkaBrokersVal is returned as the result of KafkaBrokers()
 	KafkaBrokersVal []string
+	// RaftConsentersVal is returned as the result of RaftConsenters()
+	RaftConsentersVal []string
 	// MaxChannelsCountVal is returns as the result of MaxChannelsCount()
 	MaxChannelsCountVal uint64
 }
@@ -56,6 +58,11 @@ func (scm *Orderer) KafkaBrokers() []string {
 	return scm.KafkaBrokersVal
 }
 
+// RaftConsenters returns the RaftConsentersVal
+func (scm *Orderer) RaftConsenters() []string {
+	return scm.RaftConsentersVal
+}
+
 // MaxChannelsCount returns the MaxChannelsCountVal
 func (scm *Orderer) MaxChannelsCount() uint64 {
 	return scm.MaxChannelsCountVal
@@ -39,6 +39,8 @@ import (
 	"github.com/hyperledger/fabric/core/container"
 	"github.com/hyperledger/fabric/core/container/api"
 	"github.com/hyperledger/fabric/core/container/ccintf"
+	"github.com/hyperledger/fabric/core/container/externalbuilder"
+	"github.com/hyperledger/fabric/core/container/externalcontroller"
 	"github.com/hyperledger/fabric/core/ledger"
 	pb "github.com/hyperledger/fabric/protos/peer"
 )
@@ -57,6 +59,9 @@ const (
 
 	//HistoryQueryExecutorKey is used to attach ledger history query executor context
 	HistoryQueryExecutorKey key = "historyqueryexecutorkey"
+
+	//QueryExecutorKey is used to attach a read-only ledger context, the chaincode reads through it and cannot write
+	QueryExecutorKey key = "queryexecutorkey"
 )
 
 //this is basically the singleton that supports the
@@ -75,6 +80,18 @@ func getTxSimulator(context context.Context) ledger.TxSimulator {
 	return nil
 }
 
+//use this for reading the ledger, the query executor of the context if any or else its TXSimulator
+func getQueryExecutor(context context.Context) ledger.QueryExecutor {
+	if queryExecutor, ok := context.Value(QueryExecutorKey).(ledger.QueryExecutor); ok {
+		return queryExecutor
+	}
+	if txsim := getTxSimulator(context); txsim != nil {
+		return txsim
+	}
+	//chaincode will not allow state operations
+	return nil
+}
+
 //use this for ledger access and make sure HistoryQueryExecutor is being used
 func getHistoryQueryExecutor(context context.Context) ledger.HistoryQueryExecutor {
 	if historyQueryExecutor, ok := context.Value(HistoryQueryExecutorKey).(ledger.HistoryQueryExecutor); ok {
@@ -89,6 +106,48 @@ func getHistoryQueryExecutor(context context.Context) ledger.HistoryQueryExecuto
 //This is where the VM that's running the chaincode would hook in
 type chaincodeRTEnv struct {
 	handler *Handler
+	limits  *resourceLimits
+	//one element per invocation being executed, nil when their
+	//number is not limited
+	executing chan struct{}
+}
+
+func newChaincodeRTEnv(handler *Handler, limits *resourceLimits) *chaincodeRTEnv {
+	chrte := &chaincodeRTEnv{handler: handler, limits: limits}
+	if limits != nil && limits.maxConcurrency > 0 {
+		chrte.executing = make(chan struct{}, limits.maxConcurrency)
+	}
+	return chrte
+}
+
+//reserve a slot for an invocation, false if the maximum number of
+//invocations are already being executed
+func (chrte *chaincodeRTEnv) acquire() bool {
+	if chrte.executing == nil {
+		return true
+	}
+	select {
+	case chrte.executing <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+//release the slot of an invocation reserved with acquire
+func (chrte *chaincodeRTEnv) release() {
+	if chrte.executing != nil {
+		<-chrte.executing
+	}
+}
+
+//the execution timeout of the chaincode and true, or the given default
+//one and false when the chaincode has no execution timeout
+func (chrte *chaincodeRTEnv) executeTimeout(timeout time.Duration) (time.Duration, bool) {
+	if chrte.limits != nil && chrte.limits.executeTimeout > 0 {
+		return chrte.limits.executeTimeout, true
+	}
+	return timeout, false
 }
 
 // runningChaincodes contains maps of chaincodeIDs to their chaincodeRTEs
@@ -104,12 +163,12 @@ func GetChain() *ChaincodeSupport {
 }
 
 //call this under lock
-func (chaincodeSupport *ChaincodeSupport) preLaunchSetup(chaincode string) chan bool {
+func (chaincodeSupport *ChaincodeSupport) preLaunchSetup(chaincode string, limits *resourceLimits) chan bool {
 	//register placeholder Handler. This will be transferred in registerHandler
 	//NOTE: from this point, existence of handler for this chaincode means the chaincode
 	//is in the process of getting started (or has been started)
 	notfy := make(chan bool, 1)
-	chaincodeSupport.runningChaincodes.chaincodeMap[chaincode] = &chaincodeRTEnv{handler: &Handler{readyNotify: notfy}}
+	chaincodeSupport.runningChaincodes.chaincodeMap[chaincode] = newChaincodeRTEnv(&Handler{readyNotify: notfy}, limits)
 	return notfy
 }
 
@@ -254,7 +313,13 @@ func (chaincodeSupport *ChaincodeSupport) registerHandler(chaincodehandler *Hand
 		chaincodehandler.readyNotify = chrte2.handler.readyNotify
 		chrte2.handler = chaincodehandler
 	} else {
-		chaincodeSupport.runningChaincodes.chaincodeMap[key] = &chaincodeRTEnv{handler: chaincodehandler}
+		//the chaincode was not launched by the peer (e.g. it runs in dev mode), only
+		//the limits configured for it are known as it is not invoked on any channel yet
+		limits, err := getResourceLimits(context.Background(), strings.SplitN(key, ":", 2)[0])
+		if err != nil {
+			return err
+		}
+		chaincodeSupport.runningChaincodes.chaincodeMap[key] = newChaincodeRTEnv(chaincodehandler, limits)
 	}
 
 	chaincodehandler.registered = true
@@ -335,8 +400,8 @@ func (chaincodeSupport *ChaincodeSupport) sendReady(context context.Context, ccc
 	return err
 }
 
-//get args and env given chaincodeID
-func (chaincodeSupport *ChaincodeSupport) getArgsAndEnv(cccid *ccprovider.CCContext, cLang pb.ChaincodeSpec_Type) (args []string, envs []string, err error) {
+//get env given chaincodeID
+func (chaincodeSupport *ChaincodeSupport) getEnv(cccid *ccprovider.CCContext) (envs []string) {
 	canName := cccid.GetCanonicalName()
 	envs = []string{"CORE_CHAINCODE_ID_NAME=" + canName}
 
@@ -366,11 +431,19 @@ func (chaincodeSupport *ChaincodeSupport) getArgsAndEnv(cccid *ccprovider.CCCont
 	if chaincodeSupport.logFormat != "" {
 		envs = append(envs, "CORE_CHAINCODE_LOGFORMAT="+chaincodeSupport.logFormat)
 	}
+	return envs
+}
+
+//get args and env given chaincodeID
+func (chaincodeSupport *ChaincodeSupport) getArgsAndEnv(cccid *ccprovider.CCContext, cLang pb.ChaincodeSpec_Type) (args []string, envs []string, err error) {
+	envs = chaincodeSupport.getEnv(cccid)
 	switch cLang {
 	case pb.ChaincodeSpec_GOLANG, pb.ChaincodeSpec_CAR:
 		args = []string{"chaincode", fmt.Sprintf("-peer.address=%s", chaincodeSupport.peerAddress)}
 	case pb.ChaincodeSpec_JAVA:
 		args = []string{"java", "-jar", "chaincode.jar", "--peerAddress", chaincodeSupport.peerAddress}
+	case pb.ChaincodeSpec_NODE:
+		return nil, nil, fmt.Errorf("no external builder claims node chaincode %s", cccid.GetCanonicalName())
 	default:
 		return nil, nil, fmt.Errorf("Unknown chaincodeType: %s", cLang)
 	}
@@ -379,6 +452,20 @@ func (chaincodeSupport *ChaincodeSupport) getArgsAndEnv(cccid *ccprovider.CCCont
 	return args, envs, nil
 }
 
+//get env for chaincodes run by an external builder. They may be written in
+//any language and find the peer through their environment rather than args
+func (chaincodeSupport *ChaincodeSupport) getExternalBuilderEnv(cccid *ccprovider.CCContext) []string {
+	envs := append(chaincodeSupport.getEnv(cccid), "CORE_PEER_ADDRESS="+chaincodeSupport.peerAddress)
+	if chaincodeSupport.peerTLS {
+		rootCertFile := config.GetPath("peer.tls.rootcert.file")
+		if rootCertFile == "" {
+			rootCertFile = chaincodeSupport.peerTLSCertFile
+		}
+		envs = append(envs, "CORE_PEER_TLS_ROOTCERT_FILE="+rootCertFile)
+	}
+	return envs
+}
+
 // launchAndWaitForRegister will launch container if not already running. Use the targz to create the image if not found
 func (chaincodeSupport *ChaincodeSupport) launchAndWaitForRegister(ctxt context.Context, cccid *ccprovider.CCContext, cds *pb.ChaincodeDeploymentSpec, cLang pb.ChaincodeSpec_Type, builder api.BuildSpecFactory) error {
 	canName := cccid.GetCanonicalName()
@@ -386,6 +473,11 @@ func (chaincodeSupport *ChaincodeSupport) launchAndWaitForRegister(ctxt context.
 		return fmt.Errorf("chaincode name not set")
 	}
 
+	limits, err := getResourceLimits(ctxt, cccid.Name)
+	if err != nil {
+		return err
+	}
+
 	chaincodeSupport.runningChaincodes.Lock()
 	//if its in the map, its either up or being launched. Either case break the
 	//multiple launch by failing
@@ -395,25 +487,31 @@ func (chaincodeSupport *ChaincodeSupport) launchAndWaitForRegister(ctxt context.
 	}
 
 	//chaincodeHasBeenLaunch false... its not in the map, add it and proceed to launch
-	notfy := chaincodeSupport.preLaunchSetup(canName)
+	notfy := chaincodeSupport.preLaunchSetup(canName, limits)
 	chaincodeSupport.runningChaincodes.Unlock()
 
 	//launch the chaincode
 
-	args, env, err := chaincodeSupport.getArgsAndEnv(cccid, cLang)
-	if err != nil {
-		return err
+	vmtype, _ := chaincodeSupport.getVMType(cds)
+
+	var args, env []string
+	if vmtype == container.EXTERNALBUILDER {
+		env = chaincodeSupport.getExternalBuilderEnv(cccid)
+	} else {
+		args, env, err = chaincodeSupport.getArgsAndEnv(cccid, cLang)
+		if err != nil {
+			return err
+		}
 	}
 
 	chaincodeLogger.Debugf("start container: %s(networkid:%s,peerid:%s)", canName, chaincodeSupport.peerNetworkID, chaincodeSupport.peerID)
 	chaincodeLogger.Debugf("start container with args: %s", strings.Join(args, " "))
 	chaincodeLogger.Debugf("start container with env:\n\t%s", strings.Join(env, "\n\t"))
 
-	vmtype, _ := chaincodeSupport.getVMType(cds)
-
 	sir := container.StartImageReq{CCID: ccintf.CCID{ChaincodeSpec: cds.ChaincodeSpec, NetworkID: chaincodeSupport.peerNetworkID, PeerID: chaincodeSupport.peerID, Version: cccid.Version}, Builder: builder, Args: args, Env: env}
 
 	ipcCtxt := context.WithValue(ctxt, ccintf.GetCCHandlerKey(), chaincodeSupport)
+	ipcCtxt = context.WithValue(ipcCtxt, ccintf.GetResourceLimitsKey(), limits.containerLimits())
 
 	resp, err := container.VMCProcess(ipcCtxt, vmtype, sir)
 	if err != nil || (resp != nil && resp.(container.VMCResp).Err != nil) {
@@ -459,7 +557,7 @@ func (chaincodeSupport *ChaincodeSupport) Stop(context context.Context, cccid *c
 	// the chaincode container around to give you a chance to get data
 	//sir := container.StopImageReq{CCID: ccintf.CCID{ChaincodeSpec: cds.ChaincodeSpec, NetworkID: chaincodeSupport.peerNetworkID, PeerID: chaincodeSupport.peerID, ChainID: cccid.ChainID, Version: cccid.Version}, Timeout: 0, Dontremove: true}
 
-	vmtype, _ := chaincodeSupport.getVMType(cds)
+	vmtype := chaincodeSupport.getLaunchedVMType(cccid, cds)
 
 	_, err := container.VMCProcess(context, vmtype, sir)
 	if err != nil {
@@ -617,9 +715,35 @@ func (chaincodeSupport *ChaincodeSupport) getVMType(cds *pb.ChaincodeDeploymentS
 	if cds.ExecEnv == pb.ChaincodeDeploymentSpec_SYSTEM {
 		return container.SYSTEM, nil
 	}
+	if externalcontroller.IsExternal(cds.ChaincodeSpec.ChaincodeId.Name) {
+		return container.EXTERNAL, nil
+	}
+	//fall back to the platforms when no external builder claims the package
+	claimed, err := externalbuilder.Detect(cds)
+	if err != nil {
+		chaincodeLogger.Errorf("Error offering %s to the external builders, building it with Docker: %s", cds.ChaincodeSpec.ChaincodeId.Name, err)
+	} else if claimed {
+		return container.EXTERNALBUILDER, nil
+	}
 	return container.DOCKER, nil
 }
 
+//getLaunchedVMType returns the vm type a chaincode was launched with. Unlike
+//getVMType it never offers the package to the external builders, the cds
+//passed to Stop often comes without its code package
+func (chaincodeSupport *ChaincodeSupport) getLaunchedVMType(cccid *ccprovider.CCContext, cds *pb.ChaincodeDeploymentSpec) string {
+	if cds.ExecEnv == pb.ChaincodeDeploymentSpec_SYSTEM {
+		return container.SYSTEM
+	}
+	if externalcontroller.IsExternal(cccid.Name) {
+		return container.EXTERNAL
+	}
+	if externalbuilder.Claimed(cccid.Name, cccid.Version) {
+		return container.EXTERNALBUILDER
+	}
+	return container.DOCKER
+}
+
 // HandleChaincodeStream implements ccintf.HandleChaincodeStream for all vms to call with appropriate stream
 func (chaincodeSupport *ChaincodeSupport) HandleChaincodeStream(ctxt context.Context, stream ccintf.ChaincodeStream) error {
 	return HandleChaincodeStream(chaincodeSupport, ctxt, stream)
@@ -640,7 +764,12 @@ func createCCMessage(typ pb.ChaincodeMessage_Type, txid string, cMsg *pb.Chainco
 	return &pb.ChaincodeMessage{Type: typ, Payload: payload, Txid: txid}, nil
 }
 
-// Execute executes a transaction and waits for it to complete until a timeout value.
+// Execute executes a transaction and waits for it to complete until a timeout value,
+// or the execution timeout the chaincode is limited to. A ResourceLimitError is
+// returned when the chaincode is already executing as many invocations as allowed
+// or when its execution timeout expires. Invocations from another chaincode run
+// within the transaction of the calling chaincode, which was already admitted, and
+// are not counted.
 func (chaincodeSupport *ChaincodeSupport) Execute(ctxt context.Context, cccid *ccprovider.CCContext, msg *pb.ChaincodeMessage, timeout time.Duration) (*pb.ChaincodeMessage, error) {
 	canName := cccid.GetCanonicalName()
 	chaincodeSupport.runningChaincodes.Lock()
@@ -653,6 +782,15 @@ func (chaincodeSupport *ChaincodeSupport) Execute(ctxt context.Context, cccid *c
 	}
 	chaincodeSupport.runningChaincodes.Unlock()
 
+	if !calledByChaincode(ctxt) {
+		if !chrte.acquire() {
+			chaincodeLogger.Warningf("rejecting transaction %s, chaincode %s is executing %d transactions", msg.Txid, canName, chrte.limits.maxConcurrency)
+			return nil, &ResourceLimitError{ChaincodeName: canName, Reason: fmt.Sprintf("already executing %d transactions", chrte.limits.maxConcurrency)}
+		}
+		defer chrte.release()
+	}
+	timeout, limited := chrte.executeTimeout(timeout)
+
 	var notfy chan *pb.ChaincodeMessage
 	var err error
 	if notfy, err = chrte.handler.sendExecuteMessage(ctxt, cccid.ChainID, msg, cccid.SignedProposal, cccid.Proposal); err != nil {
@@ -664,7 +802,11 @@ func (chaincodeSupport *ChaincodeSupport) Execute(ctxt context.Context, cccid *c
 		//response is sent to user or calling chaincode. ChaincodeMessage_ERROR
 		//are typically treated as error
 	case <-time.After(timeout):
-		err = fmt.Errorf("Timeout expired while executing transaction")
+		if limited {
+			err = &ResourceLimitError{ChaincodeName: canName, Reason: fmt.Sprintf("timeout of %s expired while executing transaction", timeout)}
+		} else {
+			err = fmt.Errorf("Timeout expired while executing transaction")
+		}
 	}
 
 	//our responsibility to delete transaction context if sendExecuteMessage succeeded
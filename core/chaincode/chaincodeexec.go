@@ -38,8 +38,33 @@ func createCIS(ccname string, args [][]byte) (*pb.ChaincodeInvocationSpec, error
 	return spec, nil
 }
 
-// GetCDSFromLSCC gets chaincode deployment spec from LSCC
+// getCommittedChaincodeData returns the chaincode data of the definition committed through the lifecycle
+// system chaincode, which takes precedence over the one of LSCC, or nil if the chaincode has no committed
+// definition. The definition is read with the ledger context, so that the read is validated
+func getCommittedChaincodeData(ctxt context.Context, chaincodeID string) (*ccprovider.ChaincodeData, error) {
+	queryExecutor := getQueryExecutor(ctxt)
+	if queryExecutor == nil {
+		return nil, nil
+	}
+
+	return ccprovider.GetCommittedChaincodeData(queryExecutor, chaincodeID)
+}
+
+// GetCDSFromLSCC gets chaincode deployment spec from LSCC, or from the installed
+// package of the committed definition of the chaincode if it has one
 func GetCDSFromLSCC(ctxt context.Context, txid string, signedProp *pb.SignedProposal, prop *pb.Proposal, chainID string, chaincodeID string) ([]byte, error) {
+	cd, err := getCommittedChaincodeData(ctxt, chaincodeID)
+	if err != nil {
+		return nil, err
+	}
+	if cd != nil {
+		ccpack, err := ccprovider.GetChaincodePackageForData(cd)
+		if err != nil {
+			return nil, fmt.Errorf("Get ChaincodeDeploymentSpec for %s/%s from its definition error: %s", chaincodeID, chainID, err)
+		}
+		return ccpack.GetDepSpecBytes(), nil
+	}
+
 	version := util.GetSysCCVersion()
 	cccid := ccprovider.NewCCContext(chainID, "lscc", version, txid, true, signedProp, prop)
 	res, _, err := ExecuteChaincode(ctxt, cccid, [][]byte{[]byte("getdepspec"), []byte(chainID), []byte(chaincodeID)})
@@ -53,8 +78,13 @@ func GetCDSFromLSCC(ctxt context.Context, txid string, signedProp *pb.SignedProp
 	return res.Payload, nil
 }
 
-// GetChaincodeDataFromLSCC gets chaincode data from LSCC given name
+// GetChaincodeDataFromLSCC gets chaincode data from LSCC given name, unless
+// the chaincode has a definition committed through the lifecycle system chaincode
 func GetChaincodeDataFromLSCC(ctxt context.Context, txid string, signedProp *pb.SignedProposal, prop *pb.Proposal, chainID string, chaincodeID string) (*ccprovider.ChaincodeData, error) {
+	if cd, err := getCommittedChaincodeData(ctxt, chaincodeID); err != nil || cd != nil {
+		return cd, err
+	}
+
 	version := util.GetSysCCVersion()
 	cccid := ccprovider.NewCCContext(chainID, "lscc", version, txid, true, signedProp, prop)
 	res, _, err := ExecuteChaincode(ctxt, cccid, [][]byte{[]byte("getccdata"), []byte(chainID), []byte(chaincodeID)})
@@ -74,18 +104,21 @@ func GetChaincodeDataFromLSCC(ctxt context.Context, txid string, signedProp *pb.
 }
 
 // ExecuteChaincode executes a given chaincode given chaincode name and arguments
-func ExecuteChaincode(ctxt context.Context, cccid *ccprovider.CCContext, args [][]byte) (*pb.Response, *pb.ChaincodeEvent, error) {
+func ExecuteChaincode(ctxt context.Context, cccid *ccprovider.CCContext, args [][]byte) (*pb.Response, []*pb.ChaincodeEvent, error) {
 	var spec *pb.ChaincodeInvocationSpec
 	var err error
 	var res *pb.Response
-	var ccevent *pb.ChaincodeEvent
+	var ccevents []*pb.ChaincodeEvent
 
 	spec, err = createCIS(cccid.Name, args)
-	res, ccevent, err = Execute(ctxt, cccid, spec)
-	if err != nil {
+	res, ccevents, err = Execute(ctxt, cccid, spec)
+	if _, ok := err.(*ResourceLimitError); ok {
+		chaincodeLogger.Errorf("Error executing chaincode: %s", err)
+		return nil, nil, err
+	} else if err != nil {
 		chaincodeLogger.Errorf("Error executing chaincode: %s", err)
 		return nil, nil, fmt.Errorf("Error executing chaincode: %s", err)
 	}
 
-	return res, ccevent, err
+	return res, ccevents, err
 }
@@ -0,0 +1,69 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincode
+
+import (
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+//CrossChannelReadsKey is used to attach the collector of the reads done on other channels
+const CrossChannelReadsKey key = "crosschannelreadskey"
+
+// CrossChannelReads collects the read sets produced on other channels by the
+// chaincodes queried across channels while simulating a transaction. Those
+// read sets are recorded apart from the simulation results of the transaction
+// since they cannot be validated against the ledger of its channel
+type CrossChannelReads struct {
+	sync.Mutex
+	readSets []*pb.CrossChannelReadSet
+}
+
+// NewCrossChannelReads returns an empty collector of cross-channel reads
+func NewCrossChannelReads() *CrossChannelReads {
+	return &CrossChannelReads{}
+}
+
+func (c *CrossChannelReads) add(channelID string, results []byte) {
+	c.Lock()
+	defer c.Unlock()
+	c.readSets = append(c.readSets, &pb.CrossChannelReadSet{ChannelId: channelID, Results: results})
+}
+
+// Bytes returns the serialized CrossChannelReadSets of the reads collected so
+// far, in the order the channels were read, or nil if no other channel was read
+func (c *CrossChannelReads) Bytes() ([]byte, error) {
+	c.Lock()
+	defer c.Unlock()
+	if len(c.readSets) == 0 {
+		return nil, nil
+	}
+	return proto.Marshal(&pb.CrossChannelReadSets{ReadSets: c.readSets})
+}
+
+//use this to record the reads done on other channels during the simulation
+func getCrossChannelReads(context context.Context) *CrossChannelReads {
+	if crossChannelReads, ok := context.Value(CrossChannelReadsKey).(*CrossChannelReads); ok {
+		return crossChannelReads
+	}
+	//reads done on other channels will not be recorded
+	return nil
+}
@@ -0,0 +1,44 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincode
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+func TestCrossChannelReads(t *testing.T) {
+	crossChannelReads := NewCrossChannelReads()
+	if readsBytes, err := crossChannelReads.Bytes(); err != nil || readsBytes != nil {
+		t.Fatalf("Expected no cross-channel reads, got %v (err: %v)", readsBytes, err)
+	}
+
+	crossChannelReads.add("ch2", []byte("results2"))
+	crossChannelReads.add("ch3", []byte("results3"))
+	readsBytes, err := crossChannelReads.Bytes()
+	if err != nil {
+		t.Fatalf("Failed to marshal cross-channel reads: %s", err)
+	}
+	readSets, err := utils.GetCrossChannelReadSets(readsBytes)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal cross-channel reads: %s", err)
+	}
+	if len(readSets.ReadSets) != 2 || readSets.ReadSets[0].ChannelId != "ch2" || string(readSets.ReadSets[1].Results) != "results3" {
+		t.Fatalf("Unexpected cross-channel reads %v", readSets)
+	}
+}
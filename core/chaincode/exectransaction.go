@@ -30,7 +30,7 @@ import (
 )
 
 //Execute - execute proposal, return original response of chaincode
-func Execute(ctxt context.Context, cccid *ccprovider.CCContext, spec interface{}) (*pb.Response, *pb.ChaincodeEvent, error) {
+func Execute(ctxt context.Context, cccid *ccprovider.CCContext, spec interface{}) (*pb.Response, []*pb.ChaincodeEvent, error) {
 	var err error
 	var cds *pb.ChaincodeDeploymentSpec
 	var ci *pb.ChaincodeInvocationSpec
@@ -67,7 +67,10 @@ func Execute(ctxt context.Context, cccid *ccprovider.CCContext, spec interface{}
 	}
 
 	resp, err := theChaincodeSupport.Execute(ctxt, cccid, ccMsg, theChaincodeSupport.executetimeout)
-	if err != nil {
+	if _, ok := err.(*ResourceLimitError); ok {
+		// Rollback transaction, keeping the error distinguishable by the endorser
+		return nil, nil, err
+	} else if err != nil {
 		// Rollback transaction
 		return nil, nil, fmt.Errorf("Failed to execute transaction (%s)", err)
 	} else if resp == nil {
@@ -75,9 +78,13 @@ func Execute(ctxt context.Context, cccid *ccprovider.CCContext, spec interface{}
 		return nil, nil, fmt.Errorf("Failed to receive a response for (%s)", cccid.TxID)
 	}
 
-	if resp.ChaincodeEvent != nil {
-		resp.ChaincodeEvent.ChaincodeId = cccid.Name
-		resp.ChaincodeEvent.TxId = cccid.TxID
+	ccevents := resp.ChaincodeEvents
+	if len(ccevents) == 0 && resp.ChaincodeEvent != nil {
+		ccevents = []*pb.ChaincodeEvent{resp.ChaincodeEvent}
+	}
+	for _, ccevent := range ccevents {
+		ccevent.ChaincodeId = cccid.Name
+		ccevent.TxId = cccid.TxID
 	}
 
 	if resp.Type == pb.ChaincodeMessage_COMPLETED {
@@ -88,10 +95,10 @@ func Execute(ctxt context.Context, cccid *ccprovider.CCContext, spec interface{}
 		}
 
 		// Success
-		return res, resp.ChaincodeEvent, nil
+		return res, ccevents, nil
 	} else if resp.Type == pb.ChaincodeMessage_ERROR {
 		// Rollback transaction
-		return nil, resp.ChaincodeEvent, fmt.Errorf("Transaction returned with failure: %s", string(resp.Payload))
+		return nil, ccevents, fmt.Errorf("Transaction returned with failure: %s", string(resp.Payload))
 	}
 
 	//TODO - this should never happen ... a panic is more appropriate but will save that for future
@@ -100,8 +107,8 @@ func Execute(ctxt context.Context, cccid *ccprovider.CCContext, spec interface{}
 
 // ExecuteWithErrorFilter is similar to Execute, but filters error contained in chaincode response and returns Payload of response only.
 // Mostly used by unit-test.
-func ExecuteWithErrorFilter(ctxt context.Context, cccid *ccprovider.CCContext, spec interface{}) ([]byte, *pb.ChaincodeEvent, error) {
-	res, event, err := Execute(ctxt, cccid, spec)
+func ExecuteWithErrorFilter(ctxt context.Context, cccid *ccprovider.CCContext, spec interface{}) ([]byte, []*pb.ChaincodeEvent, error) {
+	res, events, err := Execute(ctxt, cccid, spec)
 	if err != nil {
 		chaincodeLogger.Errorf("ExecuteWithErrorFilter %s error: %s", cccid.Name, err)
 		return nil, nil, err
@@ -116,7 +123,7 @@ func ExecuteWithErrorFilter(ctxt context.Context, cccid *ccprovider.CCContext, s
 		return nil, nil, fmt.Errorf("%s", res.Message)
 	}
 
-	return res.Payload, event, nil
+	return res.Payload, events, nil
 }
 
 // GetSecureContext returns the security context from the context object or error
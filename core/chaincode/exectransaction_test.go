@@ -342,12 +342,12 @@ func deploy2(ctx context.Context, cccid *ccprovider.CCContext, chaincodeDeployme
 }
 
 // Invoke a chaincode.
-func invoke(ctx context.Context, chainID string, spec *pb.ChaincodeSpec, blockNumber uint64, creator []byte) (ccevt *pb.ChaincodeEvent, uuid string, retval []byte, err error) {
+func invoke(ctx context.Context, chainID string, spec *pb.ChaincodeSpec, blockNumber uint64, creator []byte) (ccevts []*pb.ChaincodeEvent, uuid string, retval []byte, err error) {
 	return invokeWithVersion(ctx, chainID, spec.GetChaincodeId().Version, spec, blockNumber, creator)
 }
 
 // Invoke a chaincode with version (needed for upgrade)
-func invokeWithVersion(ctx context.Context, chainID string, version string, spec *pb.ChaincodeSpec, blockNumber uint64, creator []byte) (ccevt *pb.ChaincodeEvent, uuid string, retval []byte, err error) {
+func invokeWithVersion(ctx context.Context, chainID string, version string, spec *pb.ChaincodeSpec, blockNumber uint64, creator []byte) (ccevts []*pb.ChaincodeEvent, uuid string, retval []byte, err error) {
 	cdInvocationSpec := &pb.ChaincodeInvocationSpec{ChaincodeSpec: spec}
 
 	// Now create the Transactions message and send to Peer.
@@ -375,12 +375,12 @@ func invokeWithVersion(ctx context.Context, chainID string, version string, spec
 	}
 	sprop, prop := putils.MockSignedEndorserProposalOrPanic(chainID, spec, creator, []byte("msg1"))
 	cccid := ccprovider.NewCCContext(chainID, cdInvocationSpec.ChaincodeSpec.ChaincodeId.Name, version, uuid, false, sprop, prop)
-	retval, ccevt, err = ExecuteWithErrorFilter(ctx, cccid, cdInvocationSpec)
+	retval, ccevts, err = ExecuteWithErrorFilter(ctx, cccid, cdInvocationSpec)
 	if err != nil {
 		return nil, uuid, nil, fmt.Errorf("Error invoking chaincode: %s", err)
 	}
 
-	return ccevt, uuid, retval, err
+	return ccevts, uuid, retval, err
 }
 
 func closeListenerAndSleep(l net.Listener) {
@@ -1369,8 +1369,8 @@ func TestGetEvent(t *testing.T) {
 
 			spec = &pb.ChaincodeSpec{Type: 1, ChaincodeId: cID, Input: &pb.ChaincodeInput{Args: args}}
 
-			var ccevt *pb.ChaincodeEvent
-			ccevt, _, _, err = invoke(ctxt, chainID, spec, nextBlockNumber, nil)
+			var ccevts []*pb.ChaincodeEvent
+			ccevts, _, _, err = invoke(ctxt, chainID, spec, nextBlockNumber, nil)
 			nextBlockNumber++
 
 			if err != nil {
@@ -1378,10 +1378,10 @@ func TestGetEvent(t *testing.T) {
 				t.Fail()
 			}
 
-			if ccevt == nil {
-				t.Logf("Error ccevt is nil %s(%s)", ccID, err)
-				t.Fail()
+			if len(ccevts) != 1 {
+				t.Fatalf("Error expected 1 ccevt, got %d %s(%s)", len(ccevts), ccID, err)
 			}
+			ccevt := ccevts[0]
 
 			if ccevt.ChaincodeId != ccID {
 				t.Logf("Error ccevt id(%s) != cid(%s)", ccevt.ChaincodeId, ccID)
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"sort"
 	"sync"
 	"time"
 
@@ -32,6 +33,7 @@ import (
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
 	"github.com/hyperledger/fabric/core/container/ccintf"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/blobstore"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/core/policy"
 	"github.com/hyperledger/fabric/msp/mgmt"
@@ -66,8 +68,17 @@ type transactionContext struct {
 	// tracks open iterators used for range queries
 	queryIteratorMap map[string]commonledger.ResultsIterator
 
+	// the chaincode reads through queryExecutor and writes through txsimulator,
+	// which is nil when the chaincode was queried read-only
 	txsimulator          ledger.TxSimulator
+	queryExecutor        ledger.QueryExecutor
 	historyQueryExecutor ledger.HistoryQueryExecutor
+
+	// collects the reads done on other channels, nil if they are not recorded
+	crossChannelReads *CrossChannelReads
+
+	// the values the chaincode is streaming, by key
+	stateWriters map[string]blobstore.Writer
 }
 
 type nextStateInfo struct {
@@ -204,10 +215,13 @@ func (handler *Handler) createTxContext(ctxt context.Context, chainID string, tx
 	}
 	txctx := &transactionContext{chainID: chainID, signedProp: signedProp,
 		proposal: prop, responseNotifier: make(chan *pb.ChaincodeMessage, 1),
-		queryIteratorMap: make(map[string]commonledger.ResultsIterator)}
+		queryIteratorMap: make(map[string]commonledger.ResultsIterator),
+		stateWriters:     make(map[string]blobstore.Writer)}
 	handler.txCtxs[txid] = txctx
 	txctx.txsimulator = getTxSimulator(ctxt)
+	txctx.queryExecutor = getQueryExecutor(ctxt)
 	txctx.historyQueryExecutor = getHistoryQueryExecutor(ctxt)
+	txctx.crossChannelReads = getCrossChannelReads(ctxt)
 
 	return txctx, nil
 }
@@ -222,6 +236,9 @@ func (handler *Handler) deleteTxContext(txid string) {
 	handler.Lock()
 	defer handler.Unlock()
 	if handler.txCtxs != nil {
+		if txContext := handler.txCtxs[txid]; txContext != nil {
+			abortStateWriters(txContext.stateWriters)
+		}
 		delete(handler.txCtxs, txid)
 	}
 }
@@ -444,9 +461,17 @@ func newChaincodeSupportHandler(chaincodeSupport *ChaincodeSupport, peerChatStre
 			{Name: pb.ChaincodeMessage_READY.String(), Src: []string{establishedstate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_PUT_STATE.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_DEL_STATE.String(), Src: []string{readystate}, Dst: readystate},
+			{Name: pb.ChaincodeMessage_PUT_PRIVATE_DATA.String(), Src: []string{readystate}, Dst: readystate},
+			{Name: pb.ChaincodeMessage_DEL_PRIVATE_DATA.String(), Src: []string{readystate}, Dst: readystate},
+			{Name: pb.ChaincodeMessage_PUT_STATE_METADATA.String(), Src: []string{readystate}, Dst: readystate},
+			{Name: pb.ChaincodeMessage_PUT_STATE_CHUNK.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_INVOKE_CHAINCODE.String(), Src: []string{readystate}, Dst: readystate},
+			{Name: pb.ChaincodeMessage_QUERY_CHAINCODE.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_COMPLETED.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_GET_STATE.String(), Src: []string{readystate}, Dst: readystate},
+			{Name: pb.ChaincodeMessage_GET_PRIVATE_DATA.String(), Src: []string{readystate}, Dst: readystate},
+			{Name: pb.ChaincodeMessage_GET_STATE_METADATA.String(), Src: []string{readystate}, Dst: readystate},
+			{Name: pb.ChaincodeMessage_GET_STATE_CHUNK.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_GET_STATE_BY_RANGE.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_GET_QUERY_RESULT.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_GET_HISTORY_FOR_KEY.String(), Src: []string{readystate}, Dst: readystate},
@@ -461,6 +486,9 @@ func newChaincodeSupportHandler(chaincodeSupport *ChaincodeSupport, peerChatStre
 			"before_" + pb.ChaincodeMessage_REGISTER.String():           func(e *fsm.Event) { v.beforeRegisterEvent(e, v.FSM.Current()) },
 			"before_" + pb.ChaincodeMessage_COMPLETED.String():          func(e *fsm.Event) { v.beforeCompletedEvent(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_GET_STATE.String():           func(e *fsm.Event) { v.afterGetState(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_GET_PRIVATE_DATA.String():    func(e *fsm.Event) { v.afterGetPrivateData(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_GET_STATE_METADATA.String():  func(e *fsm.Event) { v.afterGetStateMetadata(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_GET_STATE_CHUNK.String():     func(e *fsm.Event) { v.afterGetStateChunk(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_GET_STATE_BY_RANGE.String():  func(e *fsm.Event) { v.afterGetStateByRange(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_GET_QUERY_RESULT.String():    func(e *fsm.Event) { v.afterGetQueryResult(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_GET_HISTORY_FOR_KEY.String(): func(e *fsm.Event) { v.afterGetHistoryForKey(e, v.FSM.Current()) },
@@ -468,7 +496,12 @@ func newChaincodeSupportHandler(chaincodeSupport *ChaincodeSupport, peerChatStre
 			"after_" + pb.ChaincodeMessage_QUERY_STATE_CLOSE.String():   func(e *fsm.Event) { v.afterQueryStateClose(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_PUT_STATE.String():           func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_DEL_STATE.String():           func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_PUT_PRIVATE_DATA.String():    func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_DEL_PRIVATE_DATA.String():    func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_PUT_STATE_METADATA.String():  func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_PUT_STATE_CHUNK.String():     func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_INVOKE_CHAINCODE.String():    func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_QUERY_CHAINCODE.String():     func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
 			"enter_" + establishedstate:                                 func(e *fsm.Event) { v.enterEstablishedState(e, v.FSM.Current()) },
 			"enter_" + readystate:                                       func(e *fsm.Event) { v.enterReadyState(e, v.FSM.Current()) },
 			"enter_" + endstate:                                         func(e *fsm.Event) { v.enterEndState(e, v.FSM.Current()) },
@@ -615,7 +648,7 @@ func (handler *Handler) afterGetState(e *fsm.Event, state string) {
 // is this a txid for which there is a valid txsim
 func (handler *Handler) isValidTxSim(txid string, fmtStr string, args ...interface{}) (*transactionContext, *pb.ChaincodeMessage) {
 	txContext := handler.getTxContext(txid)
-	if txContext == nil || txContext.txsimulator == nil {
+	if txContext == nil || txContext.queryExecutor == nil {
 		// Send error msg back to chaincode. No ledger context
 		errStr := fmt.Sprintf(fmtStr, args...)
 		chaincodeLogger.Errorf(errStr)
@@ -665,7 +698,7 @@ func (handler *Handler) handleGetState(msg *pb.ChaincodeMessage) {
 
 		var res []byte
 		var err error
-		res, err = txContext.txsimulator.GetState(chaincodeID, key)
+		res, err = txContext.queryExecutor.GetState(chaincodeID, key)
 
 		if err != nil {
 			// Send error msg back to chaincode. GetState will not trigger event
@@ -689,6 +722,239 @@ func (handler *Handler) handleGetState(msg *pb.ChaincodeMessage) {
 	}()
 }
 
+// afterGetPrivateData handles a GET_PRIVATE_DATA request from the chaincode.
+func (handler *Handler) afterGetPrivateData(e *fsm.Event, state string) {
+	msg, ok := e.Args[0].(*pb.ChaincodeMessage)
+	if !ok {
+		e.Cancel(fmt.Errorf("Received unexpected message type"))
+		return
+	}
+	chaincodeLogger.Debugf("[%s]Received %s, invoking get private data from ledger", shorttxid(msg.Txid), pb.ChaincodeMessage_GET_PRIVATE_DATA)
+
+	// Query ledger for private data
+	handler.handleGetPrivateData(msg)
+}
+
+// Handles query to ledger to get the private data of a collection
+func (handler *Handler) handleGetPrivateData(msg *pb.ChaincodeMessage) {
+	// See handleGetState for why the state transition has to complete before the response is sent
+	go func() {
+		// Check if this is the unique state request from this chaincode txid
+		uniqueReq := handler.createTXIDEntry(msg.Txid)
+		if !uniqueReq {
+			// Drop this request
+			chaincodeLogger.Error("Another state request pending for this Txid. Cannot process.")
+			return
+		}
+
+		var serialSendMsg *pb.ChaincodeMessage
+		var txContext *transactionContext
+		txContext, serialSendMsg = handler.isValidTxSim(msg.Txid,
+			"[%s]No ledger context for GetPrivateData. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+
+		defer func() {
+			handler.deleteTXIDEntry(msg.Txid)
+			if chaincodeLogger.IsEnabledFor(logging.DEBUG) {
+				chaincodeLogger.Debugf("[%s]handleGetPrivateData serial send %s",
+					shorttxid(serialSendMsg.Txid), serialSendMsg.Type)
+			}
+			handler.serialSendAsync(serialSendMsg, nil)
+		}()
+
+		if txContext == nil {
+			return
+		}
+
+		getPrivateData := &pb.GetPrivateData{}
+		if err := proto.Unmarshal(msg.Payload, getPrivateData); err != nil {
+			chaincodeLogger.Errorf("[%s]Unable to decipher payload. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte(err.Error()), Txid: msg.Txid}
+			return
+		}
+
+		chaincodeID := handler.getCCRootName()
+		if chaincodeLogger.IsEnabledFor(logging.DEBUG) {
+			chaincodeLogger.Debugf("[%s] getting private data for chaincode %s, collection %s, key %s, channel %s",
+				shorttxid(msg.Txid), chaincodeID, getPrivateData.Collection, getPrivateData.Key, txContext.chainID)
+		}
+
+		res, err := txContext.queryExecutor.GetPrivateData(chaincodeID, getPrivateData.Collection, getPrivateData.Key)
+		if err != nil {
+			// Send error msg back to chaincode. GetPrivateData will not trigger event
+			chaincodeLogger.Errorf("[%s]Failed to get private data(%s). Sending %s",
+				shorttxid(msg.Txid), err, pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte(err.Error()), Txid: msg.Txid}
+			return
+		}
+		// a nil res is sent as an empty payload, which the chaincode receives as a missing key
+		serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: res, Txid: msg.Txid}
+	}()
+}
+
+// afterGetStateMetadata handles a GET_STATE_METADATA request from the chaincode.
+func (handler *Handler) afterGetStateMetadata(e *fsm.Event, state string) {
+	msg, ok := e.Args[0].(*pb.ChaincodeMessage)
+	if !ok {
+		e.Cancel(fmt.Errorf("Received unexpected message type"))
+		return
+	}
+	chaincodeLogger.Debugf("[%s]Received %s, invoking get state metadata from ledger", shorttxid(msg.Txid), pb.ChaincodeMessage_GET_STATE_METADATA)
+
+	// Query ledger for the metadata
+	handler.handleGetStateMetadata(msg)
+}
+
+// Handles query to ledger to get the metadata of a key
+func (handler *Handler) handleGetStateMetadata(msg *pb.ChaincodeMessage) {
+	// See handleGetState for why the state transition has to complete before the response is sent
+	go func() {
+		// Check if this is the unique state request from this chaincode txid
+		uniqueReq := handler.createTXIDEntry(msg.Txid)
+		if !uniqueReq {
+			// Drop this request
+			chaincodeLogger.Error("Another state request pending for this Txid. Cannot process.")
+			return
+		}
+
+		var serialSendMsg *pb.ChaincodeMessage
+		var txContext *transactionContext
+		txContext, serialSendMsg = handler.isValidTxSim(msg.Txid,
+			"[%s]No ledger context for GetStateMetadata. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+
+		defer func() {
+			handler.deleteTXIDEntry(msg.Txid)
+			if chaincodeLogger.IsEnabledFor(logging.DEBUG) {
+				chaincodeLogger.Debugf("[%s]handleGetStateMetadata serial send %s",
+					shorttxid(serialSendMsg.Txid), serialSendMsg.Type)
+			}
+			handler.serialSendAsync(serialSendMsg, nil)
+		}()
+
+		if txContext == nil {
+			return
+		}
+
+		getStateMetadata := &pb.GetStateMetadata{}
+		if err := proto.Unmarshal(msg.Payload, getStateMetadata); err != nil {
+			chaincodeLogger.Errorf("[%s]Unable to decipher payload. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte(err.Error()), Txid: msg.Txid}
+			return
+		}
+
+		chaincodeID := handler.getCCRootName()
+		if chaincodeLogger.IsEnabledFor(logging.DEBUG) {
+			chaincodeLogger.Debugf("[%s] getting state metadata for chaincode %s, key %s, channel %s",
+				shorttxid(msg.Txid), chaincodeID, getStateMetadata.Key, txContext.chainID)
+		}
+
+		metadata, err := txContext.queryExecutor.GetStateMetadata(chaincodeID, getStateMetadata.Key)
+		var res []byte
+		if err == nil {
+			res, err = proto.Marshal(getStateMetadataResult(metadata))
+		}
+		if err != nil {
+			// Send error msg back to chaincode. GetStateMetadata will not trigger event
+			chaincodeLogger.Errorf("[%s]Failed to get state metadata(%s). Sending %s",
+				shorttxid(msg.Txid), err, pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte(err.Error()), Txid: msg.Txid}
+			return
+		}
+		serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: res, Txid: msg.Txid}
+	}()
+}
+
+// getStateMetadataResult converts the metadata of a key into the response sent to the chaincode.
+// The entries are sorted by name
+func getStateMetadataResult(metadata map[string][]byte) *pb.StateMetadataResult {
+	names := make([]string, 0, len(metadata))
+	for name := range metadata {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	res := &pb.StateMetadataResult{}
+	for _, name := range names {
+		res.Entries = append(res.Entries, &pb.StateMetadata{Metakey: name, Value: metadata[name]})
+	}
+	return res
+}
+
+// afterGetStateChunk handles a GET_STATE_CHUNK request from the chaincode.
+func (handler *Handler) afterGetStateChunk(e *fsm.Event, state string) {
+	msg, ok := e.Args[0].(*pb.ChaincodeMessage)
+	if !ok {
+		e.Cancel(fmt.Errorf("Received unexpected message type"))
+		return
+	}
+	chaincodeLogger.Debugf("[%s]Received %s, invoking get state chunk from ledger", shorttxid(msg.Txid), pb.ChaincodeMessage_GET_STATE_CHUNK)
+
+	// Query ledger for the chunk of the value
+	handler.handleGetStateChunk(msg)
+}
+
+// Handles query to ledger to get a chunk of the value of a key
+func (handler *Handler) handleGetStateChunk(msg *pb.ChaincodeMessage) {
+	// See handleGetState for why the state transition has to complete before the response is sent
+	go func() {
+		// Check if this is the unique state request from this chaincode txid
+		uniqueReq := handler.createTXIDEntry(msg.Txid)
+		if !uniqueReq {
+			// Drop this request
+			chaincodeLogger.Error("Another state request pending for this Txid. Cannot process.")
+			return
+		}
+
+		var serialSendMsg *pb.ChaincodeMessage
+		var txContext *transactionContext
+		txContext, serialSendMsg = handler.isValidTxSim(msg.Txid,
+			"[%s]No ledger context for GetStateChunk. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+
+		defer func() {
+			handler.deleteTXIDEntry(msg.Txid)
+			if chaincodeLogger.IsEnabledFor(logging.DEBUG) {
+				chaincodeLogger.Debugf("[%s]handleGetStateChunk serial send %s",
+					shorttxid(serialSendMsg.Txid), serialSendMsg.Type)
+			}
+			handler.serialSendAsync(serialSendMsg, nil)
+		}()
+
+		if txContext == nil {
+			return
+		}
+
+		getStateChunkMsg := &pb.GetStateChunk{}
+		if err := proto.Unmarshal(msg.Payload, getStateChunkMsg); err != nil {
+			chaincodeLogger.Errorf("[%s]Unable to decipher payload. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte(err.Error()), Txid: msg.Txid}
+			return
+		}
+
+		chaincodeID := handler.getCCRootName()
+		if chaincodeLogger.IsEnabledFor(logging.DEBUG) {
+			chaincodeLogger.Debugf("[%s] getting state chunk for chaincode %s, key %s, offset %d, channel %s",
+				shorttxid(msg.Txid), chaincodeID, getStateChunkMsg.Key, getStateChunkMsg.Offset, txContext.chainID)
+		}
+
+		// every chunk reads the key, so that the value cannot change unnoticed while it is streamed
+		value, err := txContext.queryExecutor.GetState(chaincodeID, getStateChunkMsg.Key)
+		var res []byte
+		if err == nil && value != nil {
+			var chunk *pb.StateChunk
+			if chunk, err = getStateChunk(peer.GetBlobStore(txContext.chainID), value, getStateChunkMsg.Offset); err == nil {
+				res, err = proto.Marshal(chunk)
+			}
+		}
+		if err != nil {
+			// Send error msg back to chaincode. GetStateChunk will not trigger event
+			chaincodeLogger.Errorf("[%s]Failed to get state chunk(%s). Sending %s",
+				shorttxid(msg.Txid), err, pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte(err.Error()), Txid: msg.Txid}
+			return
+		}
+		// an empty payload tells the chaincode that the key does not exist
+		serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: res, Txid: msg.Txid}
+	}()
+}
+
 // afterGetStateByRange handles a GET_STATE_BY_RANGE request from the chaincode.
 func (handler *Handler) afterGetStateByRange(e *fsm.Event, state string) {
 	msg, ok := e.Args[0].(*pb.ChaincodeMessage)
@@ -744,7 +1010,27 @@ func (handler *Handler) handleGetStateByRange(msg *pb.ChaincodeMessage) {
 		}
 		chaincodeID := handler.getCCRootName()
 
-		rangeIter, err := txContext.txsimulator.GetStateRangeScanIterator(chaincodeID, getStateByRange.StartKey, getStateByRange.EndKey)
+		queryMetadata, err := unmarshalQueryMetadata(getStateByRange.Metadata)
+		if err != nil {
+			payload := []byte(err.Error())
+			chaincodeLogger.Errorf("Failed to unmarshall query metadata. Sending %s", pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid}
+			return
+		}
+
+		var rangeIter commonledger.ResultsIterator
+		var paginatedIter ledger.QueryResultsIterator
+		if queryMetadata != nil {
+			// the bookmark of a range query is the start key of the next page
+			startKey := getStateByRange.StartKey
+			if queryMetadata.Bookmark != "" {
+				startKey = queryMetadata.Bookmark
+			}
+			paginatedIter, err = txContext.queryExecutor.GetStateRangeScanIteratorWithPagination(chaincodeID, startKey, getStateByRange.EndKey, queryMetadata.PageSize)
+			rangeIter = paginatedIter
+		} else {
+			rangeIter, err = txContext.queryExecutor.GetStateRangeScanIterator(chaincodeID, getStateByRange.StartKey, getStateByRange.EndKey)
+		}
 		if err != nil {
 			// Send error msg back to chaincode. GetState will not trigger event
 			payload := []byte(err.Error())
@@ -755,7 +1041,11 @@ func (handler *Handler) handleGetStateByRange(msg *pb.ChaincodeMessage) {
 
 		handler.putQueryIterator(txContext, iterID, rangeIter)
 		var payload *pb.QueryResponse
-		payload, err = getQueryResponse(handler, txContext, rangeIter, iterID)
+		if paginatedIter != nil {
+			payload, err = getPaginatedQueryResponse(handler, txContext, paginatedIter, iterID)
+		} else {
+			payload, err = getQueryResponse(handler, txContext, rangeIter, iterID)
+		}
 		if err != nil {
 			rangeIter.Close()
 			handler.deleteQueryIterator(txContext, iterID)
@@ -822,6 +1112,56 @@ func getQueryResponse(handler *Handler, txContext *transactionContext, iter comm
 	return &pb.QueryResponse{Results: queryResultsBytes, HasMore: queryResult != nil, Id: iterID}, nil
 }
 
+//getPaginatedQueryResponse fetches a complete page of results from the iterator to construct QueryResponse.
+//The response carries QueryResponseMetadata with the number of results fetched and the bookmark for the next page
+func getPaginatedQueryResponse(handler *Handler, txContext *transactionContext, iter ledger.QueryResultsIterator,
+	iterID string) (*pb.QueryResponse, error) {
+
+	defer handler.deleteQueryIterator(txContext, iterID)
+
+	var queryResultsBytes []*pb.QueryResultBytes
+	for {
+		queryResult, err := iter.Next()
+		if err != nil {
+			chaincodeLogger.Errorf("Failed to get query result from iterator")
+			iter.Close()
+			return nil, err
+		}
+		if queryResult == nil {
+			break
+		}
+		resultBytes, err := proto.Marshal(queryResult.(proto.Message))
+		if err != nil {
+			chaincodeLogger.Errorf("Failed to get encode query result as bytes")
+			iter.Close()
+			return nil, err
+		}
+		queryResultsBytes = append(queryResultsBytes, &pb.QueryResultBytes{ResultBytes: resultBytes})
+	}
+
+	responseMetadata := &pb.QueryResponseMetadata{
+		FetchedRecordsCount: int32(len(queryResultsBytes)),
+		Bookmark:            iter.GetBookmarkAndClose(),
+	}
+	metadataBytes, err := proto.Marshal(responseMetadata)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.QueryResponse{Results: queryResultsBytes, HasMore: false, Id: iterID, Metadata: metadataBytes}, nil
+}
+
+//unmarshalQueryMetadata returns the QueryMetadata carried by a query request, or nil if the query is not paginated
+func unmarshalQueryMetadata(metadataBytes []byte) (*pb.QueryMetadata, error) {
+	if len(metadataBytes) == 0 {
+		return nil, nil
+	}
+	queryMetadata := &pb.QueryMetadata{}
+	if err := proto.Unmarshal(metadataBytes, queryMetadata); err != nil {
+		return nil, err
+	}
+	return queryMetadata, nil
+}
+
 // afterQueryStateNext handles a QUERY_STATE_NEXT request from the chaincode.
 func (handler *Handler) afterQueryStateNext(e *fsm.Event, state string) {
 	msg, ok := e.Args[0].(*pb.ChaincodeMessage)
@@ -1026,7 +1366,22 @@ func (handler *Handler) handleGetQueryResult(msg *pb.ChaincodeMessage) {
 
 		chaincodeID := handler.getCCRootName()
 
-		executeIter, err := txContext.txsimulator.ExecuteQuery(chaincodeID, getQueryResult.Query)
+		queryMetadata, err := unmarshalQueryMetadata(getQueryResult.Metadata)
+		if err != nil {
+			payload := []byte(err.Error())
+			chaincodeLogger.Errorf("Failed to unmarshall query metadata. Sending %s", pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid}
+			return
+		}
+
+		var executeIter commonledger.ResultsIterator
+		var paginatedIter ledger.QueryResultsIterator
+		if queryMetadata != nil {
+			paginatedIter, err = txContext.queryExecutor.ExecuteQueryWithPagination(chaincodeID, getQueryResult.Query, queryMetadata.Bookmark, queryMetadata.PageSize)
+			executeIter = paginatedIter
+		} else {
+			executeIter, err = txContext.queryExecutor.ExecuteQuery(chaincodeID, getQueryResult.Query)
+		}
 		if err != nil {
 			// Send error msg back to chaincode. GetState will not trigger event
 			payload := []byte(err.Error())
@@ -1037,7 +1392,11 @@ func (handler *Handler) handleGetQueryResult(msg *pb.ChaincodeMessage) {
 
 		handler.putQueryIterator(txContext, iterID, executeIter)
 		var payload *pb.QueryResponse
-		payload, err = getQueryResponse(handler, txContext, executeIter, iterID)
+		if paginatedIter != nil {
+			payload, err = getPaginatedQueryResponse(handler, txContext, paginatedIter, iterID)
+		} else {
+			payload, err = getQueryResponse(handler, txContext, executeIter, iterID)
+		}
 		if err != nil {
 			executeIter.Close()
 			handler.deleteQueryIterator(txContext, iterID)
@@ -1121,7 +1480,7 @@ func (handler *Handler) handleGetHistoryForKey(msg *pb.ChaincodeMessage) {
 		}
 		chaincodeID := handler.getCCRootName()
 
-		historyIter, err := txContext.historyQueryExecutor.GetHistoryForKey(chaincodeID, getHistoryForKey.Key)
+		historyIter, err := txContext.historyQueryExecutor.GetHistoryForKeyWithOptions(chaincodeID, getHistoryForKey.Key, getHistoryForKey.Options)
 		if err != nil {
 			// Send error msg back to chaincode. GetState will not trigger event
 			payload := []byte(err.Error())
@@ -1233,6 +1592,12 @@ func (handler *Handler) enterBusyState(e *fsm.Event, state string) {
 		}
 
 		chaincodeID := handler.getCCRootName()
+		if txContext.txsimulator == nil && msg.Type != pb.ChaincodeMessage_INVOKE_CHAINCODE && msg.Type != pb.ChaincodeMessage_QUERY_CHAINCODE {
+			errStr := fmt.Sprintf("[%s]chaincode %s was queried read-only, %s is not allowed", shorttxid(msg.Txid), chaincodeID, msg.Type)
+			chaincodeLogger.Error(errStr)
+			triggerNextStateMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte(errStr), Txid: msg.Txid}
+			return
+		}
 		var err error
 		var res []byte
 
@@ -1246,14 +1611,92 @@ func (handler *Handler) enterBusyState(e *fsm.Event, state string) {
 				return
 			}
 
-			err = txContext.txsimulator.SetState(chaincodeID, putStateInfo.Key, putStateInfo.Value)
+			if blobstore.IsReference(putStateInfo.Value) {
+				// only the peer writes the references to the values streamed by the chaincodes
+				err = fmt.Errorf("The value of key %s starts like a reference to a streamed value", putStateInfo.Key)
+			} else {
+				err = txContext.txsimulator.SetState(chaincodeID, putStateInfo.Key, putStateInfo.Value)
+			}
+		} else if msg.Type.String() == pb.ChaincodeMessage_PUT_STATE_CHUNK.String() {
+			putStateChunkMsg := &pb.PutStateChunk{}
+			unmarshalErr := proto.Unmarshal(msg.Payload, putStateChunkMsg)
+			if unmarshalErr != nil {
+				payload := []byte(unmarshalErr.Error())
+				chaincodeLogger.Debugf("[%s]Unable to decipher payload. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+				triggerNextStateMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid}
+				return
+			}
+
+			// the value goes to the blob store of the channel, only its reference is written to the key
+			store := peer.GetBlobStore(txContext.chainID)
+			if store == nil {
+				err = fmt.Errorf("No blob store for channel %s", txContext.chainID)
+			} else {
+				var ref []byte
+				ref, err = putStateChunk(store, txContext.stateWriters, putStateChunkMsg)
+				if err == nil && ref != nil {
+					err = txContext.txsimulator.SetState(chaincodeID, putStateChunkMsg.Key, ref)
+				}
+			}
 		} else if msg.Type.String() == pb.ChaincodeMessage_DEL_STATE.String() {
 			// Invoke ledger to delete state
 			key := string(msg.Payload)
 			err = txContext.txsimulator.DeleteState(chaincodeID, key)
-		} else if msg.Type.String() == pb.ChaincodeMessage_INVOKE_CHAINCODE.String() {
+		} else if msg.Type.String() == pb.ChaincodeMessage_PUT_PRIVATE_DATA.String() {
+			putPrivateData := &pb.PutPrivateData{}
+			unmarshalErr := proto.Unmarshal(msg.Payload, putPrivateData)
+			if unmarshalErr != nil {
+				payload := []byte(unmarshalErr.Error())
+				chaincodeLogger.Debugf("[%s]Unable to decipher payload. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+				triggerNextStateMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid}
+				return
+			}
+
+			err = txContext.txsimulator.SetPrivateData(chaincodeID, putPrivateData.Collection, putPrivateData.Key, putPrivateData.Value)
+		} else if msg.Type.String() == pb.ChaincodeMessage_DEL_PRIVATE_DATA.String() {
+			delPrivateData := &pb.DelPrivateData{}
+			unmarshalErr := proto.Unmarshal(msg.Payload, delPrivateData)
+			if unmarshalErr != nil {
+				payload := []byte(unmarshalErr.Error())
+				chaincodeLogger.Debugf("[%s]Unable to decipher payload. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+				triggerNextStateMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid}
+				return
+			}
+
+			err = txContext.txsimulator.DeletePrivateData(chaincodeID, delPrivateData.Collection, delPrivateData.Key)
+		} else if msg.Type.String() == pb.ChaincodeMessage_PUT_STATE_METADATA.String() {
+			putStateMetadata := &pb.PutStateMetadata{}
+			unmarshalErr := proto.Unmarshal(msg.Payload, putStateMetadata)
+			if unmarshalErr == nil && putStateMetadata.Metadata == nil {
+				unmarshalErr = fmt.Errorf("No metadata entry in %s", pb.ChaincodeMessage_PUT_STATE_METADATA)
+			}
+			if unmarshalErr != nil {
+				payload := []byte(unmarshalErr.Error())
+				chaincodeLogger.Debugf("[%s]Unable to decipher payload. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+				triggerNextStateMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid}
+				return
+			}
+
+			// the entry is merged into the existing metadata of the key, an empty value removes the entry
+			var metadata map[string][]byte
+			metadata, err = txContext.txsimulator.GetStateMetadata(chaincodeID, putStateMetadata.Key)
+			if err == nil {
+				if metadata == nil {
+					metadata = make(map[string][]byte)
+				}
+				if len(putStateMetadata.Metadata.Value) == 0 {
+					delete(metadata, putStateMetadata.Metadata.Metakey)
+				} else {
+					metadata[putStateMetadata.Metadata.Metakey] = putStateMetadata.Metadata.Value
+				}
+				err = txContext.txsimulator.SetStateMetadata(chaincodeID, putStateMetadata.Key, metadata)
+			}
+		} else if msg.Type.String() == pb.ChaincodeMessage_INVOKE_CHAINCODE.String() ||
+			msg.Type.String() == pb.ChaincodeMessage_QUERY_CHAINCODE.String() {
+			// a queried chaincode cannot write, whatever its channel
+			readOnly := msg.Type.String() == pb.ChaincodeMessage_QUERY_CHAINCODE.String()
 			if chaincodeLogger.IsEnabledFor(logging.DEBUG) {
-				chaincodeLogger.Debugf("[%s] C-call-C", shorttxid(msg.Txid))
+				chaincodeLogger.Debugf("[%s] C-call-C (read-only: %t)", shorttxid(msg.Txid), readOnly)
 			}
 			chaincodeSpec := &pb.ChaincodeSpec{}
 			unmarshalErr := proto.Unmarshal(msg.Payload, chaincodeSpec)
@@ -1288,10 +1731,15 @@ func (handler *Handler) enterBusyState(e *fsm.Event, state string) {
 			}
 
 			// Set up a new context for the called chaincode if on a different channel
-			// We grab the called channel's ledger simulator to hold the new state
+			// We grab the called channel's ledger simulator to hold the new state, its
+			// writes are discarded as they cannot be committed with the transaction of
+			// this channel. A queried chaincode only gets a query executor instead,
+			// which records what it reads on the called channel
 			ctxt := context.Background()
 			txsim := txContext.txsimulator
+			queryExecutor := txContext.queryExecutor
 			historyQueryExecutor := txContext.historyQueryExecutor
+			var calledChannelQueryExecutor ledger.ReadRecordingQueryExecutor
 			if calledCcParts.suffix != txContext.chainID {
 				lgr := peer.GetLedger(calledCcParts.suffix)
 				if lgr == nil {
@@ -1300,17 +1748,43 @@ func (handler *Handler) enterBusyState(e *fsm.Event, state string) {
 						Payload: []byte(payload), Txid: msg.Txid}
 					return
 				}
-				txsim2, err2 := lgr.NewTxSimulator()
+				if readOnly {
+					queryExecutor2, err2 := lgr.NewReadRecordingQueryExecutor()
+					if err2 != nil {
+						triggerNextStateMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR,
+							Payload: []byte(err2.Error()), Txid: msg.Txid}
+						return
+					}
+					defer queryExecutor2.Done()
+					calledChannelQueryExecutor = queryExecutor2
+					queryExecutor = queryExecutor2
+				} else {
+					txsim2, err2 := lgr.NewTxSimulator()
+					if err2 != nil {
+						triggerNextStateMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR,
+							Payload: []byte(err2.Error()), Txid: msg.Txid}
+						return
+					}
+					defer txsim2.Done()
+					txsim = txsim2
+					queryExecutor = txsim2
+				}
+				historyQueryExecutor2, err2 := lgr.NewHistoryQueryExecutor()
 				if err2 != nil {
 					triggerNextStateMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR,
 						Payload: []byte(err2.Error()), Txid: msg.Txid}
 					return
 				}
-				defer txsim2.Done()
-				txsim = txsim2
+				historyQueryExecutor = historyQueryExecutor2
+			}
+			if !readOnly {
+				ctxt = context.WithValue(ctxt, TXSimulatorKey, txsim)
 			}
-			ctxt = context.WithValue(ctxt, TXSimulatorKey, txsim)
+			ctxt = context.WithValue(ctxt, QueryExecutorKey, queryExecutor)
 			ctxt = context.WithValue(ctxt, HistoryQueryExecutorKey, historyQueryExecutor)
+			if txContext.crossChannelReads != nil {
+				ctxt = context.WithValue(ctxt, CrossChannelReadsKey, txContext.crossChannelReads)
+			}
 
 			if chaincodeLogger.IsEnabledFor(logging.DEBUG) {
 				chaincodeLogger.Debugf("[%s] calling lscc to get chaincode data for %s on channel %s",
@@ -1334,6 +1808,7 @@ func (handler *Handler) enterBusyState(e *fsm.Event, state string) {
 					triggerNextStateMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid}
 					return
 				}
+				ctxt = context.WithValue(ctxt, ResourceLimitsKey, cd.ResourceLimits)
 			} else {
 				//this is a system cc, just call it directly
 				cd = &ccprovider.ChaincodeData{Name: calledCcParts.name, Version: util.GetSysCCVersion()}
@@ -1356,13 +1831,12 @@ func (handler *Handler) enterBusyState(e *fsm.Event, state string) {
 				return
 			}
 
-			// TODO: Need to handle timeout correctly
-			timeout := time.Duration(30000) * time.Millisecond
-
 			ccMsg, _ := createCCMessage(pb.ChaincodeMessage_TRANSACTION, msg.Txid, chaincodeInput)
 
 			// Execute the chaincode... this CANNOT be an init at least for now
-			response, execErr := handler.chaincodeSupport.Execute(ctxt, cccid, ccMsg, timeout)
+			// it is part of this transaction, which already holds an execution slot
+			ctxt = context.WithValue(ctxt, calledByChaincodeKey, true)
+			response, execErr := handler.chaincodeSupport.Execute(ctxt, cccid, ccMsg, handler.chaincodeSupport.executetimeout)
 
 			//payload is marshalled and send to the calling chaincode's shim which unmarshals and
 			//sends it to chaincode
@@ -1372,6 +1846,15 @@ func (handler *Handler) enterBusyState(e *fsm.Event, state string) {
 			} else {
 				res, err = proto.Marshal(response)
 			}
+
+			// record what was read on the called channel so that it can be told
+			// apart from the reads of this channel in the proposal response
+			if err == nil && calledChannelQueryExecutor != nil && txContext.crossChannelReads != nil {
+				var calledChannelReads []byte
+				if calledChannelReads, err = calledChannelQueryExecutor.GetReadSet(); err == nil {
+					txContext.crossChannelReads.add(calledCcParts.suffix, calledChannelReads)
+				}
+			}
 		}
 
 		if err != nil {
@@ -0,0 +1,115 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincode
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+//ResourceLimitsKey is used to attach the marshalled ChaincodeResourceLimits
+//the chaincode to launch was instantiated with
+const ResourceLimitsKey key = "resourcelimitskey"
+
+//calledByChaincodeKey is set on the context of the invocations made by
+//another chaincode
+const calledByChaincodeKey key = "calledbychaincodekey"
+
+const limitsConfigKey = "chaincode.limits"
+
+// ResourceLimitError is returned when an invocation is rejected or aborted
+// because the chaincode reached one of its resource limits. The endorser
+// reports it to the client with the SERVICE_UNAVAILABLE status rather than
+// as a failure of the chaincode
+type ResourceLimitError struct {
+	ChaincodeName string
+	Reason        string
+}
+
+func (e *ResourceLimitError) Error() string {
+	return fmt.Sprintf("Resource limit of chaincode %s reached: %s", e.ChaincodeName, e.Reason)
+}
+
+// resourceLimits are the limits enforced on a chaincode launched by the
+// peer, zero values mean no limit
+type resourceLimits struct {
+	cpuShares      int64
+	memory         int64
+	maxConcurrency int
+	executeTimeout time.Duration
+}
+
+// getResourceLimits returns the limits of the chaincode with the given name:
+// the ones configured in core.yaml and the ones it was instantiated with
+// both apply, hence the lower non zero value of each limit wins
+func getResourceLimits(ctxt context.Context, name string) (*resourceLimits, error) {
+	limits := &resourceLimits{
+		cpuShares:      limitConfig(name, "cpuShares"),
+		memory:         limitConfig(name, "memory"),
+		maxConcurrency: int(limitConfig(name, "maxConcurrency")),
+		executeTimeout: time.Duration(limitConfig(name, "executeTimeout")) * time.Millisecond,
+	}
+
+	limitsBytes, _ := ctxt.Value(ResourceLimitsKey).([]byte)
+	if len(limitsBytes) == 0 {
+		return limits, nil
+	}
+	ccLimits := &pb.ChaincodeResourceLimits{}
+	if err := proto.Unmarshal(limitsBytes, ccLimits); err != nil {
+		return nil, fmt.Errorf("Invalid resource limits for chaincode %s: %s", name, err)
+	}
+	limits.cpuShares = lowerLimit(limits.cpuShares, ccLimits.CpuShares)
+	limits.memory = lowerLimit(limits.memory, ccLimits.Memory)
+	limits.maxConcurrency = int(lowerLimit(int64(limits.maxConcurrency), int64(ccLimits.MaxConcurrency)))
+	limits.executeTimeout = time.Duration(lowerLimit(int64(limits.executeTimeout), int64(ccLimits.ExecuteTimeout)*int64(time.Millisecond)))
+	return limits, nil
+}
+
+// calledByChaincode returns true when the invocation is made by another chaincode
+func calledByChaincode(ctxt context.Context) bool {
+	called, _ := ctxt.Value(calledByChaincodeKey).(bool)
+	return called
+}
+
+// limitConfig returns the limit configured in core.yaml for the chaincode
+// with the given name, or the default one when the chaincode has none
+func limitConfig(name string, limit string) int64 {
+	key := limitsConfigKey + ".chaincodes." + name + "." + limit
+	if !viper.IsSet(key) {
+		key = limitsConfigKey + ".default." + limit
+	}
+	return int64(viper.GetInt(key))
+}
+
+func lowerLimit(a int64, b int64) int64 {
+	if a <= 0 || (b > 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// containerLimits returns the limits enforced by the vm running the chaincode
+func (limits *resourceLimits) containerLimits() ccintf.ResourceLimits {
+	return ccintf.ResourceLimits{CPUShares: limits.cpuShares, Memory: limits.memory}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincode
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+func TestGetResourceLimits(t *testing.T) {
+	config := map[string]int{
+		"chaincode.limits.default.memory":                      512,
+		"chaincode.limits.default.maxConcurrency":              8,
+		"chaincode.limits.chaincodes.limitedcc.memory":         1024,
+		"chaincode.limits.chaincodes.limitedcc.executeTimeout": 2000,
+	}
+	for key, value := range config {
+		viper.Set(key, value)
+		defer viper.Set(key, nil)
+	}
+
+	limits, err := getResourceLimits(context.Background(), "othercc")
+	if err != nil || limits.memory != 512 || limits.maxConcurrency != 8 || limits.executeTimeout != 0 {
+		t.Fatalf("Expected the default limits, got %+v (err: %v)", limits, err)
+	}
+
+	limits, err = getResourceLimits(context.Background(), "limitedcc")
+	if err != nil || limits.memory != 1024 || limits.maxConcurrency != 8 || limits.executeTimeout != 2*time.Second {
+		t.Fatalf("Expected the limits of limitedcc, got %+v (err: %v)", limits, err)
+	}
+
+	// the lower of the configured and instantiation limits applies
+	ccLimits, _ := proto.Marshal(&pb.ChaincodeResourceLimits{CpuShares: 256, Memory: 2048, MaxConcurrency: 2, ExecuteTimeout: 3000})
+	ctxt := context.WithValue(context.Background(), ResourceLimitsKey, ccLimits)
+	limits, err = getResourceLimits(ctxt, "limitedcc")
+	if err != nil || limits.cpuShares != 256 || limits.memory != 1024 || limits.maxConcurrency != 2 || limits.executeTimeout != 2*time.Second {
+		t.Fatalf("Expected the lower limits, got %+v (err: %v)", limits, err)
+	}
+
+	ctxt = context.WithValue(context.Background(), ResourceLimitsKey, []byte("garbage"))
+	if _, err = getResourceLimits(ctxt, "limitedcc"); err == nil {
+		t.Fatal("Expected invalid instantiation limits to be rejected")
+	}
+}
+
+func TestChaincodeRTEnvLimits(t *testing.T) {
+	chrte := newChaincodeRTEnv(nil, &resourceLimits{maxConcurrency: 2, executeTimeout: time.Second})
+	if !chrte.acquire() || !chrte.acquire() {
+		t.Fatal("Expected 2 invocations to be allowed")
+	}
+	if chrte.acquire() {
+		t.Fatal("Expected a third invocation to be rejected")
+	}
+	chrte.release()
+	if !chrte.acquire() {
+		t.Fatal("Expected an invocation to be allowed once another one completed")
+	}
+	if timeout, limited := chrte.executeTimeout(30 * time.Second); timeout != time.Second || !limited {
+		t.Fatalf("Expected the timeout of the chaincode, got %s (limited: %t)", timeout, limited)
+	}
+
+	// without limits every invocation is allowed with the default timeout
+	chrte = newChaincodeRTEnv(nil, nil)
+	for i := 0; i < 10; i++ {
+		if !chrte.acquire() {
+			t.Fatal("Expected the invocations not to be limited")
+		}
+	}
+	if timeout, limited := chrte.executeTimeout(30 * time.Second); timeout != 30*time.Second || limited {
+		t.Fatalf("Expected the default timeout, got %s (limited: %t)", timeout, limited)
+	}
+}
+
+func TestCalledByChaincode(t *testing.T) {
+	if calledByChaincode(context.Background()) {
+		t.Fatal("Expected an invocation not to be made by a chaincode")
+	}
+	if !calledByChaincode(context.WithValue(context.Background(), calledByChaincodeKey, true)) {
+		t.Fatal("Expected an invocation to be made by a chaincode")
+	}
+}
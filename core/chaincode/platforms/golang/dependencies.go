@@ -0,0 +1,320 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package golang
+
+import (
+	"archive/tar"
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/build"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	cutil "github.com/hyperledger/fabric/core/container/util"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// LockFileName is the name of the file, at the root of a chaincode, pinning
+// its dependencies. Each line holds the import path of a dependency, usually
+// the root of its repository, its version and the content hash of the files
+// packaged for it separated by spaces. Blank lines and lines starting with //
+// are ignored. A chaincode is packaged with the dependencies it imports only,
+// taken from its vendor directory or else from GOPATH, and each of them must
+// be pinned in the lock file with the content hash of its files. The shim and
+// the packages it imports are provided by the chaincode environment, they are
+// neither packaged nor pinned, hence a chaincode which only imports them and
+// the standard library needs no lock file
+const LockFileName = "deps.lock"
+
+// shimPackage is the package of the shim provided by the chaincode environment
+const shimPackage = "github.com/hyperledger/fabric/core/chaincode/shim"
+
+type lockEntry struct {
+	path    string
+	version string
+	hash    string
+}
+
+type lockFile []*lockEntry
+
+// readLockFile reads the lock file of the chaincode at codePath. A missing
+// lock file pins nothing, the chaincode is refused later on if it has any
+// dependency to pin
+func readLockFile(codePath string) (lockFile, error) {
+	f, err := os.Open(filepath.Join(codePath, LockFileName))
+	if os.IsNotExist(err) {
+		return lockFile{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s: %s", LockFileName, err)
+	}
+	defer f.Close()
+
+	lock := lockFile{}
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("%s:%d: expected an import path, a version and a content hash, got \"%s\"", LockFileName, lineNum, line)
+		}
+		lock = append(lock, &lockEntry{path: strings.TrimSuffix(fields[0], "/"), version: fields[1], hash: fields[2]})
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read %s: %s", LockFileName, err)
+	}
+	return lock, nil
+}
+
+// lookup returns the entry pinning the package with the given import path,
+// that is the entry with the longest path the import path is or is under
+func (lock lockFile) lookup(importPath string) *lockEntry {
+	var found *lockEntry
+	for _, entry := range lock {
+		if importPath != entry.path && !strings.HasPrefix(importPath, entry.path+"/") {
+			continue
+		}
+		if found == nil || len(entry.path) > len(found.path) {
+			found = entry
+		}
+	}
+	return found
+}
+
+// dependency is a package imported by a chaincode, directly or not, outside
+// of the standard library and of the chaincode itself
+type dependency struct {
+	pkg      *build.Package
+	vendored bool
+	entry    *lockEntry
+}
+
+// resolveDependencies walks the imports of the chaincode package with the
+// given import path and of the packages it imports, resolving them as the go
+// tool would, and returns its dependencies sorted by directory. Any import
+// that cannot be resolved is an error, as is any dependency not pinned in lock
+// or whose files do not match the content hash pinned
+func resolveDependencies(gopath string, codepath string, lock lockFile) ([]*dependency, error) {
+	ctxt := build.Default
+	ctxt.GOPATH = gopath
+	//chaincodes are built to run in a linux container
+	ctxt.GOOS = "linux"
+
+	root, err := ctxt.Import(codepath, "", 0)
+	if err != nil {
+		return nil, fmt.Errorf("could not load chaincode package %s: %s", codepath, err)
+	}
+	codeDir := root.Dir + string(filepath.Separator)
+	vendorDir := codeDir + "vendor" + string(filepath.Separator)
+
+	provided := providedPackages(&ctxt)
+
+	var deps []*dependency
+	visited := map[string]bool{root.Dir: true}
+	pending := []*build.Package{root}
+	for len(pending) > 0 {
+		pkg := pending[0]
+		pending = pending[1:]
+		for _, importPath := range pkg.Imports {
+			if importPath == "C" {
+				continue
+			}
+			imported, err := ctxt.Import(importPath, pkg.Dir, 0)
+			if err != nil {
+				return nil, fmt.Errorf("unresolved dependency %s of %s: %s", importPath, pkg.ImportPath, err)
+			}
+			if imported.Goroot || visited[imported.Dir] || provided[imported.Dir] {
+				continue
+			}
+			visited[imported.Dir] = true
+			pending = append(pending, imported)
+
+			dep := &dependency{pkg: imported, vendored: strings.HasPrefix(imported.Dir, vendorDir)}
+			if !dep.vendored && strings.HasPrefix(imported.Dir, codeDir) {
+				//a package of the chaincode itself
+				continue
+			}
+			if dep.entry = lock.lookup(dep.importPath()); dep.entry == nil {
+				return nil, fmt.Errorf("dependency %s of %s is not pinned in %s", dep.importPath(), pkg.ImportPath, LockFileName)
+			}
+			deps = append(deps, dep)
+		}
+	}
+
+	sort.Sort(dependenciesByDir(deps))
+	if err = verifyDependencies(deps); err != nil {
+		return nil, err
+	}
+	return deps, nil
+}
+
+// providedPackages returns the directories of the shim and of the packages
+// it imports, directly or not, outside of the standard library. It returns
+// nil if the shim cannot be loaded, the chaincode cannot import it then
+func providedPackages(ctxt *build.Context) map[string]bool {
+	shim, err := ctxt.Import(shimPackage, "", 0)
+	if err != nil {
+		return nil
+	}
+	provided := map[string]bool{shim.Dir: true}
+	pending := []*build.Package{shim}
+	for len(pending) > 0 {
+		pkg := pending[0]
+		pending = pending[1:]
+		for _, importPath := range pkg.Imports {
+			if importPath == "C" {
+				continue
+			}
+			imported, err := ctxt.Import(importPath, pkg.Dir, 0)
+			if err != nil {
+				return nil
+			}
+			if imported.Goroot || provided[imported.Dir] {
+				continue
+			}
+			provided[imported.Dir] = true
+			pending = append(pending, imported)
+		}
+	}
+	return provided
+}
+
+// verifyDependencies checks that the files packaged for the dependencies
+// pinned by each entry of the lock file match the content hash it pins
+func verifyDependencies(deps []*dependency) error {
+	hashes, err := hashDependencies(deps)
+	if err != nil {
+		return err
+	}
+	for _, dep := range deps {
+		if hash := hashes[dep.entry]; hash != dep.entry.hash {
+			return fmt.Errorf("dependency %s does not match %s: its content hash is %s, %s is pinned", dep.entry.path, LockFileName, hash, dep.entry.hash)
+		}
+	}
+	return nil
+}
+
+// hashDependencies returns the content hash of the files packaged for the
+// dependencies pinned by each entry of the lock file. The hash is the hex
+// encoded SHA256 of the lines holding the import path of each file and the
+// hex encoded SHA256 of its content, separated by a space, in the order of
+// the dependencies, which are sorted by directory, and of their files
+func hashDependencies(deps []*dependency) (map[*lockEntry]string, error) {
+	lines := make(map[*lockEntry][]string)
+	for _, dep := range deps {
+		names, err := dep.files()
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			content, err := ioutil.ReadFile(filepath.Join(dep.pkg.Dir, name))
+			if err != nil {
+				return nil, fmt.Errorf("could not read dependency %s: %s", dep.pkg.ImportPath, err)
+			}
+			fileHash := sha256.Sum256(content)
+			lines[dep.entry] = append(lines[dep.entry], dep.importPath()+"/"+name+" "+hex.EncodeToString(fileHash[:])+"\n")
+		}
+	}
+	hashes := make(map[*lockEntry]string)
+	for entry, entryLines := range lines {
+		hash := sha256.Sum256([]byte(strings.Join(entryLines, "")))
+		hashes[entry] = hex.EncodeToString(hash[:])
+	}
+	return hashes, nil
+}
+
+// importPath returns the import path of the dependency, without the vendor
+// directory of the chaincode it may be packaged from
+func (dep *dependency) importPath() string {
+	if !dep.vendored {
+		return dep.pkg.ImportPath
+	}
+	return dep.pkg.ImportPath[strings.LastIndex(dep.pkg.ImportPath, "/vendor/")+len("/vendor/"):]
+}
+
+// files returns the names of the files of a dependency that are packaged,
+// sorted by name
+func (dep *dependency) files() ([]string, error) {
+	fis, err := ioutil.ReadDir(dep.pkg.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read dependency %s: %s", dep.pkg.ImportPath, err)
+	}
+	var names []string
+	for _, fi := range fis {
+		if fi.IsDir() || !includeFileTypes[filepath.Ext(fi.Name())] {
+			continue
+		}
+		names = append(names, fi.Name())
+	}
+	return names, nil
+}
+
+// writeToPackage writes the files of a dependency resolved from GOPATH to
+// the package, the vendored ones are packaged with the chaincode
+func (dep *dependency) writeToPackage(tw *tar.Writer) error {
+	if dep.vendored {
+		return nil
+	}
+	names, err := dep.files()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		packagepath := filepath.Join("src", dep.pkg.ImportPath, name)
+		if err = cutil.WriteFileToPackage(filepath.Join(dep.pkg.Dir, name), packagepath, tw); err != nil {
+			return fmt.Errorf("could not package dependency %s: %s", dep.pkg.ImportPath, err)
+		}
+	}
+	return nil
+}
+
+// getChaincodeDependencies returns the pinned dependencies of a chaincode,
+// in the form recorded in its deployment spec: one entry per lock file entry
+// used, sorted by path
+func getChaincodeDependencies(deps []*dependency) []*pb.ChaincodeDependency {
+	ccdeps := []*pb.ChaincodeDependency{}
+	recorded := make(map[string]bool)
+	for _, dep := range deps {
+		if recorded[dep.entry.path] {
+			continue
+		}
+		recorded[dep.entry.path] = true
+		ccdeps = append(ccdeps, &pb.ChaincodeDependency{Path: dep.entry.path, Version: dep.entry.version, Hash: dep.entry.hash, Vendored: dep.vendored})
+	}
+	sort.Sort(chaincodeDependenciesByPath(ccdeps))
+	return ccdeps
+}
+
+type dependenciesByDir []*dependency
+
+func (a dependenciesByDir) Len() int           { return len(a) }
+func (a dependenciesByDir) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a dependenciesByDir) Less(i, j int) bool { return a[i].pkg.Dir < a[j].pkg.Dir }
+
+type chaincodeDependenciesByPath []*pb.ChaincodeDependency
+
+func (a chaincodeDependenciesByPath) Len() int           { return len(a) }
+func (a chaincodeDependenciesByPath) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a chaincodeDependenciesByPath) Less(i, j int) bool { return a[i].Path < a[j].Path }
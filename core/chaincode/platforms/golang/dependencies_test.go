@@ -0,0 +1,229 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package golang
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+const (
+	libSource  = "package lib\n\nimport \"strings\"\n\nvar Name = strings.ToUpper(\"lib\")\n"
+	vlibSource = "package vlib\n\nconst Name = \"vlib\"\n"
+)
+
+// contentHash returns the content hash of the given files of a dependency,
+// as pinned in a lock file
+func contentHash(files ...string) string {
+	var lines string
+	for i := 0; i < len(files); i += 2 {
+		fileHash := sha256.Sum256([]byte(files[i+1]))
+		lines += files[i] + " " + hex.EncodeToString(fileHash[:]) + "\n"
+	}
+	hash := sha256.Sum256([]byte(lines))
+	return hex.EncodeToString(hash[:])
+}
+
+var (
+	libHash  = contentHash("example.com/lib/lib.go", libSource)
+	vlibHash = contentHash("example.com/vlib/vlib.go", vlibSource)
+)
+
+// setupGopath creates a GOPATH holding a chaincode with a vendored
+// dependency, a dependency and an unrelated package, and points GOPATH to it
+func setupGopath(t *testing.T, lock string) func() {
+	gopath, err := ioutil.TempDir("", "gopath")
+	if err != nil {
+		t.Fatalf("Could not create GOPATH: %s", err)
+	}
+	files := map[string]string{
+		"example.com/cc/main.go":                         "package main\n\nimport (\n\t\"fmt\"\n\n\t\"example.com/lib\"\n\t\"example.com/vlib\"\n)\n\nfunc main() { fmt.Println(lib.Name, vlib.Name) }\n",
+		"example.com/cc/vendor/example.com/vlib/vlib.go": vlibSource,
+		"example.com/lib/lib.go":                         libSource,
+		"example.com/lib/lib_test.dat":                   "not packaged",
+		"example.com/other/other.go":                     "package other\n",
+	}
+	if lock != "" {
+		files["example.com/cc/"+LockFileName] = lock
+	}
+	for name, content := range files {
+		path := filepath.Join(gopath, "src", name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err = ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Could not write %s: %s", name, err)
+		}
+	}
+
+	origGopath := os.Getenv("GOPATH")
+	os.Setenv("GOPATH", gopath)
+	return func() {
+		os.Setenv("GOPATH", origGopath)
+		os.RemoveAll(gopath)
+	}
+}
+
+func TestReadLockFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lock")
+	if err != nil {
+		t.Fatalf("Could not create dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if lock, err := readLockFile(dir); err != nil || len(lock) != 0 {
+		t.Fatalf("Expected a missing lock file to pin nothing, got %v (err: %v)", lock, err)
+	}
+
+	ioutil.WriteFile(filepath.Join(dir, LockFileName), []byte("// pinned\n\nexample.com/a v1.0.0 aaaa\nexample.com/a/b/ v2.0.0 bbbb\n"), 0644)
+	lock, err := readLockFile(dir)
+	if err != nil || len(lock) != 2 {
+		t.Fatalf("Expected 2 entries, got %v (err: %v)", lock, err)
+	}
+	for importPath, expected := range map[string]string{
+		"example.com/a":     "v1.0.0",
+		"example.com/a/c":   "v1.0.0",
+		"example.com/a/b/d": "v2.0.0",
+		"example.com/ab":    "",
+	} {
+		entry := lock.lookup(importPath)
+		if (entry == nil && expected != "") || (entry != nil && entry.version != expected) {
+			t.Fatalf("Expected version %q for %s, got %v", expected, importPath, entry)
+		}
+	}
+
+	if entry := lock.lookup("example.com/a/b"); entry.hash != "bbbb" {
+		t.Fatalf("Expected the content hash of example.com/a/b, got %v", entry)
+	}
+
+	ioutil.WriteFile(filepath.Join(dir, LockFileName), []byte("example.com/a v1.0.0\n"), 0644)
+	if _, err = readLockFile(dir); err == nil {
+		t.Fatal("Expected a malformed lock file to be rejected")
+	}
+}
+
+func TestGetDeploymentPayloadWithLockFile(t *testing.T) {
+	defer setupGopath(t, "example.com/lib v1.0.0 "+libHash+"\nexample.com/vlib v0.1.0 "+vlibHash+"\n")()
+
+	platform := &Platform{}
+	spec := &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "cc", Path: "example.com/cc"}}
+	payload, err := platform.GetDeploymentPayload(spec)
+	if err != nil {
+		t.Fatalf("Error getting deployment payload: %s", err)
+	}
+
+	gr, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		t.Fatalf("Error opening payload: %s", err)
+	}
+	tr := tar.NewReader(gr)
+	var files []string
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			break
+		}
+		files = append(files, header.Name)
+	}
+	expected := []string{
+		"src/example.com/cc/" + LockFileName,
+		"src/example.com/cc/main.go",
+		"src/example.com/cc/vendor/example.com/vlib/vlib.go",
+		"src/example.com/lib/lib.go",
+	}
+	if strings.Join(files, ",") != strings.Join(expected, ",") {
+		t.Fatalf("Expected the package to hold %v, got %v", expected, files)
+	}
+
+	// packaging is reproducible
+	if payload2, _ := platform.GetDeploymentPayload(spec); !bytes.Equal(payload, payload2) {
+		t.Fatal("Expected the same package when packaging twice")
+	}
+
+	_, deps, err := platform.GetDeploymentPayloadWithDependencies(spec)
+	if err != nil || len(deps) != 2 {
+		t.Fatalf("Expected 2 dependencies, got %v (err: %v)", deps, err)
+	}
+	if deps[0].Path != "example.com/lib" || deps[0].Version != "v1.0.0" || deps[0].Hash != libHash || deps[0].Vendored {
+		t.Fatalf("Unexpected dependency %v", deps[0])
+	}
+	if deps[1].Path != "example.com/vlib" || deps[1].Version != "v0.1.0" || deps[1].Hash != vlibHash || !deps[1].Vendored {
+		t.Fatalf("Unexpected dependency %v", deps[1])
+	}
+}
+
+func TestGetDeploymentPayloadWithoutLockFile(t *testing.T) {
+	defer setupGopath(t, "")()
+	path := filepath.Join(os.Getenv("GOPATH"), "src", "example.com", "std", "main.go")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	ioutil.WriteFile(path, []byte("package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(\"std\") }\n"), 0644)
+
+	// a chaincode importing the standard library only has nothing to pin
+	platform := &Platform{}
+	spec := &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "std", Path: "example.com/std"}}
+	_, deps, err := platform.GetDeploymentPayloadWithDependencies(spec)
+	if err != nil || len(deps) != 0 {
+		t.Fatalf("Expected the package without dependencies, got %v (err: %v)", deps, err)
+	}
+}
+
+func TestGetDeploymentPayloadWithUnresolvedDependencies(t *testing.T) {
+	spec := &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "cc", Path: "example.com/cc"}}
+	platform := &Platform{}
+
+	// no lock file
+	cleanup := setupGopath(t, "")
+	_, err := platform.GetDeploymentPayload(spec)
+	cleanup()
+	if err == nil || !strings.Contains(err.Error(), "not pinned in "+LockFileName) {
+		t.Fatalf("Expected the package to be refused without %s, got %v", LockFileName, err)
+	}
+
+	// a dependency not pinned
+	cleanup = setupGopath(t, "example.com/vlib v0.1.0 "+vlibHash+"\n")
+	_, err = platform.GetDeploymentPayload(spec)
+	cleanup()
+	if err == nil || !strings.Contains(err.Error(), "example.com/lib") {
+		t.Fatalf("Expected the package to be refused for example.com/lib, got %v", err)
+	}
+
+	// a dependency whose content does not match the lock file
+	cleanup = setupGopath(t, "example.com/lib v1.0.0 "+libHash+"\nexample.com/vlib v0.1.0 "+vlibHash+"\n")
+	ioutil.WriteFile(filepath.Join(os.Getenv("GOPATH"), "src", "example.com", "lib", "lib.go"), []byte(libSource+"\nvar Changed = true\n"), 0644)
+	_, err = platform.GetDeploymentPayload(spec)
+	cleanup()
+	if err == nil || !strings.Contains(err.Error(), "dependency example.com/lib does not match") {
+		t.Fatalf("Expected the package to be refused for example.com/lib, got %v", err)
+	}
+
+	// a dependency that cannot be found
+	cleanup = setupGopath(t, "example.com/lib v1.0.0 "+libHash+"\nexample.com/vlib v0.1.0 "+vlibHash+"\n")
+	os.RemoveAll(filepath.Join(os.Getenv("GOPATH"), "src", "example.com", "lib"))
+	_, err = platform.GetDeploymentPayload(spec)
+	cleanup()
+	if err == nil || !strings.Contains(err.Error(), "unresolved dependency example.com/lib") {
+		t.Fatalf("Expected the package to be refused for example.com/lib, got %v", err)
+	}
+}
@@ -34,7 +34,6 @@ import (
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/util"
 	ccutil "github.com/hyperledger/fabric/core/chaincode/platforms/util"
-	cutil "github.com/hyperledger/fabric/core/container/util"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/spf13/viper"
 )
@@ -146,18 +145,20 @@ func getCodeFromFS(path string) (codegopath string, err error) {
 
 //collectChaincodeFiles collects chaincode files and generates hashcode for the
 //package. If path is a HTTP(s) url it downloads the code first.
+//The dependencies pinned by the lock file of the chaincode are collected as
+//well and returned.
 //NOTE: for dev mode, user builds and runs chaincode manually. The name provided
 //by the user is equivalent to the path. This method will treat the name
 //as codebytes and compute the hash from it. ie, user cannot run the chaincode
 //with the same (name, input, args)
-func collectChaincodeFiles(spec *pb.ChaincodeSpec, tw *tar.Writer) (string, error) {
+func collectChaincodeFiles(spec *pb.ChaincodeSpec, tw *tar.Writer) (string, []*pb.ChaincodeDependency, error) {
 	if spec == nil {
-		return "", errors.New("Cannot collect files from nil spec")
+		return "", nil, errors.New("Cannot collect files from nil spec")
 	}
 
 	chaincodeID := spec.ChaincodeId
 	if chaincodeID == nil || chaincodeID.Path == "" {
-		return "", errors.New("Cannot collect files from empty chaincode path")
+		return "", nil, errors.New("Cannot collect files from empty chaincode path")
 	}
 
 	//install will not have inputs and we don't have to collect hash for it
@@ -169,7 +170,7 @@ func collectChaincodeFiles(spec *pb.ChaincodeSpec, tw *tar.Writer) (string, erro
 	} else {
 		inputbytes, err = proto.Marshal(spec.Input)
 		if err != nil {
-			return "", fmt.Errorf("Error marshalling constructor: %s", err)
+			return "", nil, fmt.Errorf("Error marshalling constructor: %s", err)
 		}
 	}
 
@@ -188,26 +189,39 @@ func collectChaincodeFiles(spec *pb.ChaincodeSpec, tw *tar.Writer) (string, erro
 	path := chaincodeID.Path
 
 	var actualcodepath string
+	//the GOPATH the dependencies of the chaincode are resolved against
+	gopath := os.Getenv("GOPATH")
 	if strings.HasPrefix(path, "http://") {
 		ishttp = true
 		actualcodepath = path[7:]
 		codegopath, err = getCodeFromHTTP(actualcodepath)
+		gopath = codegopath + string(os.PathListSeparator) + gopath
 	} else if strings.HasPrefix(path, "https://") {
 		ishttp = true
 		actualcodepath = path[8:]
 		codegopath, err = getCodeFromHTTP(actualcodepath)
+		gopath = codegopath + string(os.PathListSeparator) + gopath
 	} else {
 		actualcodepath = path
 		codegopath, err = getCodeFromFS(path)
 	}
 
 	if err != nil {
-		return "", fmt.Errorf("Error getting code %s", err)
+		return "", nil, fmt.Errorf("Error getting code %s", err)
 	}
 
 	tmppath := filepath.Join(codegopath, "src", actualcodepath)
 	if err = ccutil.IsCodeExist(tmppath); err != nil {
-		return "", fmt.Errorf("code does not exist %s", err)
+		return "", nil, fmt.Errorf("code does not exist %s", err)
+	}
+
+	lock, err := readLockFile(tmppath)
+	if err != nil {
+		return "", nil, fmt.Errorf("Could not read the lock file of %s - %s", path, err)
+	}
+	deps, err := resolveDependencies(gopath, actualcodepath, lock)
+	if err != nil {
+		return "", nil, fmt.Errorf("Could not resolve the dependencies of %s - %s", path, err)
 	}
 
 	hash := []byte{}
@@ -217,46 +231,24 @@ func collectChaincodeFiles(spec *pb.ChaincodeSpec, tw *tar.Writer) (string, erro
 
 	hash, err = ccutil.HashFilesInDir(filepath.Join(codegopath, "src"), actualcodepath, hash, tw)
 	if err != nil {
-		return "", fmt.Errorf("Could not get hashcode for %s - %s\n", path, err)
+		return "", nil, fmt.Errorf("Could not get hashcode for %s - %s\n", path, err)
 	}
 
-	return hex.EncodeToString(hash[:]), nil
-}
-
-//WriteGopathSrc tars up files under gopath src
-func writeGopathSrc(tw *tar.Writer, excludeDir string) error {
-	gopath := os.Getenv("GOPATH")
-	// Only take the first element of GOPATH
-	gopath = filepath.SplitList(gopath)[0]
-
-	rootDirectory := filepath.Join(gopath, "src")
-	logger.Infof("rootDirectory = %s", rootDirectory)
-
-	if err := cutil.WriteFolderToTarPackage(tw, rootDirectory, excludeDir, includeFileTypes, nil); err != nil {
-		logger.Errorf("Error writing folder to tar package %s", err)
-		return err
-	}
-
-	// Write the tar file out
-	if err := tw.Close(); err != nil {
-		return err
+	//the metadata (e.g., the index definitions for the state database) is also placed at
+	//the root of the package, where it is looked up when the chaincode is deployed
+	if tw != nil {
+		if err = ccutil.WriteMetadataToPackage(tmppath, tw); err != nil {
+			return "", nil, fmt.Errorf("Could not package metadata for %s - %s", path, err)
+		}
 	}
-	//ioutil.WriteFile("/tmp/chaincode_deployment.tar", inputbuf.Bytes(), 0644)
-	return nil
-}
 
-//tw is expected to have the chaincode in it from GenerateHashcode. This method
-//will just package rest of the bytes
-func writeChaincodePackage(spec *pb.ChaincodeSpec, tw *tar.Writer) error {
-
-	urlLocation, err := decodeUrl(spec)
-	if err != nil {
-		return fmt.Errorf("could not decode url: %s", err)
+	if tw != nil {
+		for _, dep := range deps {
+			if err = dep.writeToPackage(tw); err != nil {
+				return "", nil, err
+			}
+		}
 	}
 
-	err = writeGopathSrc(tw, urlLocation)
-	if err != nil {
-		return fmt.Errorf("Error writing Chaincode package contents: %s", err)
-	}
-	return nil
+	return hex.EncodeToString(hash[:]), getChaincodeDependencies(deps), nil
 }
@@ -114,7 +114,11 @@ func (goPlatform *Platform) ValidateDeploymentSpec(cds *pb.ChaincodeDeploymentSp
 	// the container itself needs to be the last line of defense and be configured to be
 	// resilient in enforcing constraints. However, we should still do our best to keep as much
 	// garbage out of the system as possible.
+	//
+	// The metadata packaged with the chaincode (e.g., the index definitions for the state database)
+	// is also allowed under /META-INF.  It is never handed to the compiler.
 	re := regexp.MustCompile(`(/)?src/.*`)
+	metadataRe := regexp.MustCompile(`^(/)?META-INF/.*`)
 	is := bytes.NewReader(cds.CodePackage)
 	gr, err := gzip.NewReader(is)
 	if err != nil {
@@ -132,7 +136,7 @@ func (goPlatform *Platform) ValidateDeploymentSpec(cds *pb.ChaincodeDeploymentSp
 		// --------------------------------------------------------------------------------------
 		// Check name for conforming path
 		// --------------------------------------------------------------------------------------
-		if !re.MatchString(header.Name) {
+		if !re.MatchString(header.Name) && !metadataRe.MatchString(header.Name) {
 			return fmt.Errorf("Illegal file detected in payload: \"%s\"", header.Name)
 		}
 
@@ -155,8 +159,13 @@ func (goPlatform *Platform) ValidateDeploymentSpec(cds *pb.ChaincodeDeploymentSp
 
 // WritePackage writes the Go chaincode package
 func (goPlatform *Platform) GetDeploymentPayload(spec *pb.ChaincodeSpec) ([]byte, error) {
+	payload, _, err := goPlatform.GetDeploymentPayloadWithDependencies(spec)
+	return payload, err
+}
 
-	var err error
+// GetDeploymentPayloadWithDependencies writes the Go chaincode package and
+// returns the dependencies packaged with it, as pinned by its lock file
+func (goPlatform *Platform) GetDeploymentPayloadWithDependencies(spec *pb.ChaincodeSpec) ([]byte, []*pb.ChaincodeDependency, error) {
 
 	inputbuf := bytes.NewBuffer(nil)
 	gw := gzip.NewWriter(inputbuf)
@@ -165,23 +174,17 @@ func (goPlatform *Platform) GetDeploymentPayload(spec *pb.ChaincodeSpec) ([]byte
 	//ignore the generated hash. Just use the tw
 	//The hash could be used in a future enhancement
 	//to check, warn of duplicate installs etc.
-	_, err = collectChaincodeFiles(spec, tw)
+	_, deps, err := collectChaincodeFiles(spec, tw)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	err = writeChaincodePackage(spec, tw)
-
 	tw.Close()
 	gw.Close()
 
-	if err != nil {
-		return nil, err
-	}
-
 	payload := inputbuf.Bytes()
 
-	return payload, nil
+	return payload, deps, nil
 }
 
 func (goPlatform *Platform) GenerateDockerfile(cds *pb.ChaincodeDeploymentSpec) (string, error) {
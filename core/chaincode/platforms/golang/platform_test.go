@@ -77,6 +77,9 @@ func TestValidateCDS(t *testing.T) {
 	specs = append(specs, spec{CCName: "NoCode", Path: "path/to/nowhere", File: "/bin/warez", Mode: 0100400, SuccessExpected: false})
 	specs = append(specs, spec{CCName: "NoCode", Path: "path/to/somewhere", File: "/src/path/to/somewhere/main.go", Mode: 0100400, SuccessExpected: true})
 	specs = append(specs, spec{CCName: "NoCode", Path: "path/to/somewhere", File: "/src/path/to/somewhere/warez", Mode: 0100555, SuccessExpected: false})
+	specs = append(specs, spec{CCName: "NoCode", Path: "path/to/somewhere", File: "META-INF/statedb/couchdb/indexes/indexOwner.json", Mode: 0100400, SuccessExpected: true})
+	specs = append(specs, spec{CCName: "NoCode", Path: "path/to/somewhere", File: "META-INF/statedb/couchdb/indexes/warez", Mode: 0100555, SuccessExpected: false})
+	specs = append(specs, spec{CCName: "NoCode", Path: "path/to/somewhere", File: "bin/META-INF/warez", Mode: 0100400, SuccessExpected: false})
 
 	for _, s := range specs {
 		cds, err := generateFakeCDS(s.CCName, s.Path, s.File, s.Mode)
@@ -92,20 +95,6 @@ func TestValidateCDS(t *testing.T) {
 	}
 }
 
-func Test_writeGopathSrc(t *testing.T) {
-
-	inputbuf := bytes.NewBuffer(nil)
-	tw := tar.NewWriter(inputbuf)
-
-	err := writeGopathSrc(tw, "")
-	if err != nil {
-		t.Fail()
-		t.Logf("Error writing gopath src: %s", err)
-	}
-	//ioutil.WriteFile("/tmp/chaincode_deployment.tar", inputbuf.Bytes(), 0644)
-
-}
-
 func Test_decodeUrl(t *testing.T) {
 	cs := &pb.ChaincodeSpec{
 		ChaincodeId: &pb.ChaincodeID{
@@ -181,6 +170,47 @@ func TestGetDeploymentPayload(t *testing.T) {
 	}
 }
 
+func TestGetDeploymentPayloadWithMetadata(t *testing.T) {
+	platform := &Platform{}
+
+	spec := &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "marbles", Path: "github.com/hyperledger/fabric/examples/chaincode/go/marbles02"}}
+	payload, err := platform.GetDeploymentPayload(spec)
+	if err != nil {
+		t.Fatalf("Error getting deployment payload: %s", err)
+	}
+
+	gr, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		t.Fatalf("Error opening payload: %s", err)
+	}
+	tr := tar.NewReader(gr)
+
+	metadataFiles := make(map[string]bool)
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			break
+		}
+		if strings.HasPrefix(header.Name, "META-INF/") {
+			metadataFiles[header.Name] = true
+		}
+	}
+
+	for _, expected := range []string{
+		"META-INF/statedb/couchdb/indexes/indexOwner.json",
+		"META-INF/statedb/couchdb/indexes/indexSizeSortDesc.json",
+	} {
+		if !metadataFiles[expected] {
+			t.Errorf("Expected %s in the deployment payload, found %v", expected, metadataFiles)
+		}
+	}
+
+	cds := &pb.ChaincodeDeploymentSpec{ChaincodeSpec: spec, CodePackage: payload}
+	if err = platform.ValidateDeploymentSpec(cds); err != nil {
+		t.Errorf("Unexpected validation failure: %s", err)
+	}
+}
+
 //TestGenerateDockerBuild goes through the functions needed to do docker build
 func TestGenerateDockerBuild(t *testing.T) {
 	platform := &Platform{}
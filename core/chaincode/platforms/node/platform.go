@@ -0,0 +1,95 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	cutil "github.com/hyperledger/fabric/core/container/util"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// errNoDockerBuild is returned when a node chaincode is about to be built
+// with Docker, there is no Docker build for node chaincodes
+var errNoDockerBuild = errors.New("node chaincodes can only be built by an external builder")
+
+// Platform for node chaincodes. The peer only packages their sources, the
+// packages are built and run by the external builders claiming them
+type Platform struct {
+}
+
+// ValidateSpec validates the node chaincode specs, the path must be a local
+// directory
+func (nodePlatform *Platform) ValidateSpec(spec *pb.ChaincodeSpec) error {
+	if spec.ChaincodeId == nil || spec.ChaincodeId.Path == "" {
+		return errors.New("ChaincodeSpec's path cannot be empty")
+	}
+	return nil
+}
+
+func (nodePlatform *Platform) ValidateDeploymentSpec(cds *pb.ChaincodeDeploymentSpec) error {
+	// the external builders validate the code package when detecting it
+	return nil
+}
+
+// GetDeploymentPayload packages the directory of the chaincode under src/
+func (nodePlatform *Platform) GetDeploymentPayload(spec *pb.ChaincodeSpec) ([]byte, error) {
+	if err := nodePlatform.ValidateSpec(spec); err != nil {
+		return nil, err
+	}
+
+	folder := filepath.Clean(spec.ChaincodeId.Path)
+	info, err := os.Stat(folder)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading chaincode path %s: %s", folder, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("Chaincode path %s is not a directory", folder)
+	}
+
+	payload := bytes.NewBuffer(nil)
+	gw := gzip.NewWriter(payload)
+	tw := tar.NewWriter(gw)
+
+	err = cutil.WriteFolderToTarPackage(tw, folder, "node_modules", nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error writing Chaincode package contents: %s", err)
+	}
+
+	if err = tw.Close(); err != nil {
+		return nil, err
+	}
+	if err = gw.Close(); err != nil {
+		return nil, err
+	}
+
+	return payload.Bytes(), nil
+}
+
+func (nodePlatform *Platform) GenerateDockerfile(cds *pb.ChaincodeDeploymentSpec) (string, error) {
+	return "", errNoDockerBuild
+}
+
+func (nodePlatform *Platform) GenerateDockerBuild(cds *pb.ChaincodeDeploymentSpec, tw *tar.Writer) error {
+	return errNoDockerBuild
+}
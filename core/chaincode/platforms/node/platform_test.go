@@ -0,0 +1,75 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDeploymentPayload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nodeplatform")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "package.json"), []byte("{}"), 0644))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "node_modules", "dep"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "node_modules", "dep", "index.js"), []byte(""), 0644))
+
+	platform := &Platform{}
+	spec := &pb.ChaincodeSpec{Type: pb.ChaincodeSpec_NODE, ChaincodeId: &pb.ChaincodeID{Name: "nodecc", Path: dir}}
+	payload, err := platform.GetDeploymentPayload(spec)
+	assert.NoError(t, err)
+
+	gr, err := gzip.NewReader(bytes.NewReader(payload))
+	assert.NoError(t, err)
+	tr := tar.NewReader(gr)
+	var names []string
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		names = append(names, header.Name)
+	}
+	assert.Equal(t, []string{"src/package.json"}, names)
+
+	spec.ChaincodeId.Path = filepath.Join(dir, "package.json")
+	_, err = platform.GetDeploymentPayload(spec)
+	assert.Error(t, err)
+
+	spec.ChaincodeId.Path = ""
+	_, err = platform.GetDeploymentPayload(spec)
+	assert.Error(t, err)
+}
+
+func TestNoDockerBuild(t *testing.T) {
+	platform := &Platform{}
+	cds := &pb.ChaincodeDeploymentSpec{ChaincodeSpec: &pb.ChaincodeSpec{Type: pb.ChaincodeSpec_NODE}}
+	_, err := platform.GenerateDockerfile(cds)
+	assert.Equal(t, errNoDockerBuild, err)
+	assert.Equal(t, errNoDockerBuild, platform.GenerateDockerBuild(cds, nil))
+}
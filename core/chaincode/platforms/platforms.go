@@ -32,6 +32,7 @@ import (
 	"github.com/hyperledger/fabric/core/chaincode/platforms/car"
 	"github.com/hyperledger/fabric/core/chaincode/platforms/golang"
 	"github.com/hyperledger/fabric/core/chaincode/platforms/java"
+	"github.com/hyperledger/fabric/core/chaincode/platforms/node"
 	"github.com/hyperledger/fabric/core/config"
 	cutil "github.com/hyperledger/fabric/core/container/util"
 	pb "github.com/hyperledger/fabric/protos/peer"
@@ -60,6 +61,8 @@ func Find(chaincodeType pb.ChaincodeSpec_Type) (Platform, error) {
 		return &car.Platform{}, nil
 	case pb.ChaincodeSpec_JAVA:
 		return &java.Platform{}, nil
+	case pb.ChaincodeSpec_NODE:
+		return &node.Platform{}, nil
 	default:
 		return nil, fmt.Errorf("Unknown chaincodeType: %s", chaincodeType)
 	}
@@ -75,6 +78,29 @@ func GetDeploymentPayload(spec *pb.ChaincodeSpec) ([]byte, error) {
 	return platform.GetDeploymentPayload(spec)
 }
 
+// DependencyResolver is implemented by the platforms pinning the versions of
+// the dependencies packaged with a chaincode
+type DependencyResolver interface {
+	GetDeploymentPayloadWithDependencies(spec *pb.ChaincodeSpec) ([]byte, []*pb.ChaincodeDependency, error)
+}
+
+// GetDeploymentPayloadWithDependencies returns the deployment payload of the
+// chaincode and the dependencies packaged with it, the dependencies are nil
+// if its platform does not pin them
+func GetDeploymentPayloadWithDependencies(spec *pb.ChaincodeSpec) ([]byte, []*pb.ChaincodeDependency, error) {
+	platform, err := Find(spec.Type)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resolver, ok := platform.(DependencyResolver)
+	if !ok {
+		payload, err := platform.GetDeploymentPayload(spec)
+		return payload, nil, err
+	}
+	return resolver.GetDeploymentPayloadWithDependencies(spec)
+}
+
 func getPeerTLSCert() ([]byte, error) {
 
 	if viper.GetBool("peer.tls.enabled") == false {
@@ -75,6 +75,44 @@ func HashFilesInDir(rootDir string, dir string, hash []byte, tw *tar.Writer) ([]
 	return hash, nil
 }
 
+//MetadataDir is the directory of the chaincode source holding the artifacts that are not part
+//of the chaincode code, such as the index definitions for the state database
+const MetadataDir = "META-INF"
+
+//WriteMetadataToPackage writes the files under the metadata directory of the chaincode source,
+//if any, to the metadata directory at the root of the package
+func WriteMetadataToPackage(codePath string, tw *tar.Writer) error {
+	metadataPath := filepath.Join(codePath, MetadataDir)
+	fi, err := os.Stat(metadataPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Could not stat %s: %s", metadataPath, err)
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("%s is not a directory", metadataPath)
+	}
+
+	return filepath.Walk(metadataPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		relPath, err := filepath.Rel(codePath, path)
+		if err != nil {
+			return err
+		}
+		logger.Debugf("Adding metadata file %s to the package", relPath)
+		if err = cutil.WriteFileToPackage(path, filepath.ToSlash(relPath), tw); err != nil {
+			return fmt.Errorf("Error adding metadata file to tar %s", err)
+		}
+		return nil
+	})
+}
+
 //IsCodeExist checks the chaincode if exists
 func IsCodeExist(tmppath string) error {
 	file, err := os.Open(tmppath)
@@ -54,12 +54,12 @@ const (
 // ChaincodeStub is an object passed to chaincode for shim side handling of
 // APIs.
 type ChaincodeStub struct {
-	TxID           string
-	chaincodeEvent *pb.ChaincodeEvent
-	args           [][]byte
-	handler        *Handler
-	signedProposal *pb.SignedProposal
-	proposal       *pb.Proposal
+	TxID            string
+	chaincodeEvents []*pb.ChaincodeEvent
+	args            [][]byte
+	handler         *Handler
+	signedProposal  *pb.SignedProposal
+	proposal        *pb.Proposal
 
 	// Additional fields extracted from the signedProposal
 	creator   []byte
@@ -341,6 +341,15 @@ func (stub *ChaincodeStub) InvokeChaincode(chaincodeName string, args [][]byte,
 	return stub.handler.handleInvokeChaincode(chaincodeName, args, stub.TxID)
 }
 
+// QueryChaincode documentation can be found in interfaces.go
+func (stub *ChaincodeStub) QueryChaincode(chaincodeName string, args [][]byte, channel string) pb.Response {
+	// Internally we handle chaincode name as a composite name
+	if channel != "" {
+		chaincodeName = chaincodeName + "/" + channel
+	}
+	return stub.handler.handleQueryChaincode(chaincodeName, args, stub.TxID)
+}
+
 // --------- State functions ----------
 
 // GetState returns the byte array value specified by the `key`.
@@ -358,6 +367,63 @@ func (stub *ChaincodeStub) DelState(key string) error {
 	return stub.handler.handleDelState(key, stub.TxID)
 }
 
+// PutStateStream documentation can be found in interfaces.go
+func (stub *ChaincodeStub) PutStateStream(key string) (io.WriteCloser, error) {
+	if key == "" {
+		return nil, fmt.Errorf("key must not be an empty string")
+	}
+	return &stateWriter{handler: stub.handler, key: key, txid: stub.TxID}, nil
+}
+
+// GetStateStream documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetStateStream(key string) (io.Reader, error) {
+	return newStateReader(stub.handler, key, stub.TxID)
+}
+
+// SetStateValidationParameter documentation can be found in interfaces.go
+func (stub *ChaincodeStub) SetStateValidationParameter(key string, ep []byte) error {
+	if key == "" {
+		return fmt.Errorf("key must not be an empty string")
+	}
+	return stub.handler.handlePutStateMetadata(key, pb.MetaDataKeys_VALIDATION_PARAMETER.String(), ep, stub.TxID)
+}
+
+// GetStateValidationParameter documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetStateValidationParameter(key string) ([]byte, error) {
+	metadata, err := stub.handler.handleGetStateMetadata(key, stub.TxID)
+	if err != nil {
+		return nil, err
+	}
+	return metadata[pb.MetaDataKeys_VALIDATION_PARAMETER.String()], nil
+}
+
+// GetPrivateData documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetPrivateData(collection string, key string) ([]byte, error) {
+	if collection == "" {
+		return nil, fmt.Errorf("collection must not be an empty string")
+	}
+	return stub.handler.handleGetPrivateData(collection, key, stub.TxID)
+}
+
+// PutPrivateData documentation can be found in interfaces.go
+func (stub *ChaincodeStub) PutPrivateData(collection string, key string, value []byte) error {
+	if collection == "" {
+		return fmt.Errorf("collection must not be an empty string")
+	}
+	if key == "" {
+		return fmt.Errorf("key must not be an empty string")
+	}
+	return stub.handler.handlePutPrivateData(collection, key, value, stub.TxID)
+}
+
+// DelPrivateData documentation can be found in interfaces.go
+func (stub *ChaincodeStub) DelPrivateData(collection string, key string) error {
+	if collection == "" {
+		return fmt.Errorf("collection must not be an empty string")
+	}
+	return stub.handler.handleDelPrivateData(collection, key, stub.TxID)
+}
+
 // CommonIterator allows a chaincode to iterate over a set of
 // key/value pairs in the state.
 type CommonIterator struct {
@@ -392,30 +458,86 @@ const (
 // between the startKey and endKey, inclusive. The order in which keys are
 // returned by the iterator is random.
 func (stub *ChaincodeStub) GetStateByRange(startKey, endKey string) (StateQueryIteratorInterface, error) {
-	response, err := stub.handler.handleGetStateByRange(startKey, endKey, stub.TxID)
+	response, err := stub.handler.handleGetStateByRange(startKey, endKey, nil, stub.TxID)
 	if err != nil {
 		return nil, err
 	}
 	return &StateQueryIterator{CommonIterator: &CommonIterator{stub.handler, stub.TxID, response, 0}}, nil
 }
 
+// GetStateByRangeWithPagination function can be invoked by a chaincode to query
+// a single page of a range of keys in the state. The returned iterator covers at most
+// pageSize keys starting from the bookmark, or from the startKey if the bookmark is empty.
+// The QueryResponseMetadata carries the bookmark for retrieving the next page.
+func (stub *ChaincodeStub) GetStateByRangeWithPagination(startKey, endKey string, pageSize int32,
+	bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	metadata, err := createQueryMetadata(pageSize, bookmark)
+	if err != nil {
+		return nil, nil, err
+	}
+	response, err := stub.handler.handleGetStateByRange(startKey, endKey, metadata, stub.TxID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return createPaginatedQueryIterator(stub, response)
+}
+
 // GetQueryResult function can be invoked by a chaincode to perform a
 // rich query against state database.  Only supported by state database implementations
 // that support rich query.  The query string is in the syntax of the underlying
 // state database. An iterator is returned which can be used to iterate (next) over
 // the query result set
 func (stub *ChaincodeStub) GetQueryResult(query string) (StateQueryIteratorInterface, error) {
-	response, err := stub.handler.handleGetQueryResult(query, stub.TxID)
+	response, err := stub.handler.handleGetQueryResult(query, nil, stub.TxID)
 	if err != nil {
 		return nil, err
 	}
 	return &StateQueryIterator{CommonIterator: &CommonIterator{stub.handler, stub.TxID, response, 0}}, nil
 }
 
+// GetQueryResultWithPagination function can be invoked by a chaincode to retrieve
+// a single page of the results of a rich query. The returned iterator covers at most
+// pageSize results following the ones covered by the (opaque) bookmark.
+// The QueryResponseMetadata carries the bookmark for retrieving the next page.
+func (stub *ChaincodeStub) GetQueryResultWithPagination(query string, pageSize int32,
+	bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	metadata, err := createQueryMetadata(pageSize, bookmark)
+	if err != nil {
+		return nil, nil, err
+	}
+	response, err := stub.handler.handleGetQueryResult(query, metadata, stub.TxID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return createPaginatedQueryIterator(stub, response)
+}
+
+func createQueryMetadata(pageSize int32, bookmark string) ([]byte, error) {
+	if pageSize <= 0 {
+		return nil, fmt.Errorf("Page size should be greater than zero, found [%d]", pageSize)
+	}
+	return proto.Marshal(&pb.QueryMetadata{PageSize: pageSize, Bookmark: bookmark})
+}
+
+func createPaginatedQueryIterator(stub *ChaincodeStub, response *pb.QueryResponse) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	responseMetadata := &pb.QueryResponseMetadata{}
+	if err := proto.Unmarshal(response.Metadata, responseMetadata); err != nil {
+		return nil, nil, err
+	}
+	return &StateQueryIterator{CommonIterator: &CommonIterator{stub.handler, stub.TxID, response, 0}}, responseMetadata, nil
+}
+
 // GetHistoryForKey function can be invoked by a chaincode to return a history of
 // key values across time. GetHistoryForKey is intended to be used for read-only queries.
 func (stub *ChaincodeStub) GetHistoryForKey(key string) (HistoryQueryIteratorInterface, error) {
-	response, err := stub.handler.handleGetHistoryForKey(key, stub.TxID)
+	return stub.GetHistoryForKeyWithOptions(key, nil)
+}
+
+// GetHistoryForKeyWithOptions function can be invoked by a chaincode to return
+// the history of key values restricted to a block range and a time range, in
+// chronological or reverse chronological order and limited to a number of results.
+func (stub *ChaincodeStub) GetHistoryForKeyWithOptions(key string, options *pb.HistoryQueryOptions) (HistoryQueryIteratorInterface, error) {
+	response, err := stub.handler.handleGetHistoryForKey(key, options, stub.TxID)
 	if err != nil {
 		return nil, err
 	}
@@ -671,15 +793,31 @@ func (stub *ChaincodeStub) GetTxTimestamp() (*timestamp.Timestamp, error) {
 
 // ------------- ChaincodeEvent API ----------------------
 
-// SetEvent saves the event to be sent when a transaction is made part of a block
+// SetEvent saves the event to be sent when a transaction is made part of a block.
+// SetEvent may be called several times in a transaction; all events are kept
+// and delivered in the order they were set
 func (stub *ChaincodeStub) SetEvent(name string, payload []byte) error {
 	if name == "" {
 		return errors.New("Event name can not be nil string.")
 	}
-	stub.chaincodeEvent = &pb.ChaincodeEvent{EventName: name, Payload: payload}
+	stub.chaincodeEvents = append(stub.chaincodeEvents, &pb.ChaincodeEvent{EventName: name, Payload: payload})
 	return nil
 }
 
+// withEvents attaches the events set by the chaincode to msg. A single event
+// is sent in the ChaincodeEvent field understood by older peers, several
+// events are sent in ChaincodeEvents
+func (stub *ChaincodeStub) withEvents(msg *pb.ChaincodeMessage) *pb.ChaincodeMessage {
+	switch len(stub.chaincodeEvents) {
+	case 0:
+	case 1:
+		msg.ChaincodeEvent = stub.chaincodeEvents[0]
+	default:
+		msg.ChaincodeEvents = stub.chaincodeEvents
+	}
+	return msg
+}
+
 // ------------- Logging Control and Chaincode Loggers ---------------
 
 // As independent programs, Go language chaincodes can use any logging
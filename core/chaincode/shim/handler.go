@@ -230,7 +230,7 @@ func (handler *Handler) handleInit(msg *pb.ChaincodeMessage) {
 		err := stub.init(handler, msg.Txid, input, msg.Proposal)
 		if err != nil {
 			chaincodeLogger.Errorf("[%s]Init get error response [%s]. Sending %s", shorttxid(msg.Txid), err.Error(), pb.ChaincodeMessage_ERROR)
-			nextStateMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte(err.Error()), Txid: msg.Txid, ChaincodeEvent: stub.chaincodeEvent}
+			nextStateMsg = stub.withEvents(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte(err.Error()), Txid: msg.Txid})
 			return
 		}
 		res := handler.cc.Init(stub)
@@ -239,7 +239,7 @@ func (handler *Handler) handleInit(msg *pb.ChaincodeMessage) {
 		if res.Status >= ERROR {
 			// Send ERROR message to chaincode support and change state
 			chaincodeLogger.Errorf("[%s]Init get error response [%s]. Sending %s", shorttxid(msg.Txid), res.Message, pb.ChaincodeMessage_ERROR)
-			nextStateMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte(res.Message), Txid: msg.Txid, ChaincodeEvent: stub.chaincodeEvent}
+			nextStateMsg = stub.withEvents(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte(res.Message), Txid: msg.Txid})
 			return
 		}
 
@@ -247,12 +247,12 @@ func (handler *Handler) handleInit(msg *pb.ChaincodeMessage) {
 		if err != nil {
 			payload := []byte(err.Error())
 			chaincodeLogger.Errorf("[%s]Init marshal response error [%s]. Sending %s", shorttxid(msg.Txid), err, pb.ChaincodeMessage_ERROR)
-			nextStateMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid, ChaincodeEvent: stub.chaincodeEvent}
+			nextStateMsg = stub.withEvents(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid})
 			return
 		}
 
 		// Send COMPLETED message to chaincode support and change state
-		nextStateMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED, Payload: resBytes, Txid: msg.Txid, ChaincodeEvent: stub.chaincodeEvent}
+		nextStateMsg = stub.withEvents(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED, Payload: resBytes, Txid: msg.Txid})
 		chaincodeLogger.Debugf("[%s]Init invoke succeeded. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_COMPLETED)
 	}()
 }
@@ -306,7 +306,7 @@ func (handler *Handler) handleTransaction(msg *pb.ChaincodeMessage) {
 			payload := []byte(err.Error())
 			// Send ERROR message to chaincode support and change state
 			chaincodeLogger.Errorf("[%s]Transaction execution failed. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
-			nextStateMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid, ChaincodeEvent: stub.chaincodeEvent}
+			nextStateMsg = stub.withEvents(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid})
 			return
 		}
 		res := handler.cc.Invoke(stub)
@@ -317,13 +317,13 @@ func (handler *Handler) handleTransaction(msg *pb.ChaincodeMessage) {
 			payload := []byte(err.Error())
 			// Send ERROR message to chaincode support and change state
 			chaincodeLogger.Errorf("[%s]Transaction execution failed. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
-			nextStateMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid, ChaincodeEvent: stub.chaincodeEvent}
+			nextStateMsg = stub.withEvents(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid})
 			return
 		}
 
 		// Send COMPLETED message to chaincode support and change state
 		chaincodeLogger.Debugf("[%s]Transaction completed. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_COMPLETED)
-		nextStateMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED, Payload: resBytes, Txid: msg.Txid, ChaincodeEvent: stub.chaincodeEvent}
+		nextStateMsg = stub.withEvents(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED, Payload: resBytes, Txid: msg.Txid})
 	}()
 }
 
@@ -506,7 +506,108 @@ func (handler *Handler) handleDelState(key string, txid string) error {
 	return errors.New("Incorrect chaincode message received")
 }
 
-func (handler *Handler) handleGetStateByRange(startKey, endKey string, txid string) (*pb.QueryResponse, error) {
+// handleGetPrivateData communicates with the validator to fetch the private data of a collection.
+func (handler *Handler) handleGetPrivateData(collection string, key string, txid string) ([]byte, error) {
+	return handler.handlePrivateDataRequest(pb.ChaincodeMessage_GET_PRIVATE_DATA, &pb.GetPrivateData{Collection: collection, Key: key}, txid)
+}
+
+// handlePutPrivateData communicates with the validator to put the private data of a collection.
+func (handler *Handler) handlePutPrivateData(collection string, key string, value []byte, txid string) error {
+	_, err := handler.handlePrivateDataRequest(pb.ChaincodeMessage_PUT_PRIVATE_DATA, &pb.PutPrivateData{Collection: collection, Key: key, Value: value}, txid)
+	return err
+}
+
+// handleDelPrivateData communicates with the validator to delete a key from the private data of a collection.
+func (handler *Handler) handleDelPrivateData(collection string, key string, txid string) error {
+	_, err := handler.handlePrivateDataRequest(pb.ChaincodeMessage_DEL_PRIVATE_DATA, &pb.DelPrivateData{Collection: collection, Key: key}, txid)
+	return err
+}
+
+// handleGetStateMetadata communicates with the validator to fetch the metadata of a key.
+func (handler *Handler) handleGetStateMetadata(key string, txid string) (map[string][]byte, error) {
+	res, err := handler.handlePrivateDataRequest(pb.ChaincodeMessage_GET_STATE_METADATA, &pb.GetStateMetadata{Key: key}, txid)
+	if err != nil {
+		return nil, err
+	}
+	metadataResult := &pb.StateMetadataResult{}
+	if err = proto.Unmarshal(res, metadataResult); err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal the state metadata: %s", err)
+	}
+	metadata := make(map[string][]byte)
+	for _, entry := range metadataResult.Entries {
+		metadata[entry.Metakey] = entry.Value
+	}
+	return metadata, nil
+}
+
+// handlePutStateMetadata communicates with the validator to set an entry of the metadata of a key.
+func (handler *Handler) handlePutStateMetadata(key string, metakey string, value []byte, txid string) error {
+	request := &pb.PutStateMetadata{Key: key, Metadata: &pb.StateMetadata{Metakey: metakey, Value: value}}
+	_, err := handler.handlePrivateDataRequest(pb.ChaincodeMessage_PUT_STATE_METADATA, request, txid)
+	return err
+}
+
+// handlePutStateChunk communicates with the validator to stream a chunk of the value of a key.
+func (handler *Handler) handlePutStateChunk(key string, data []byte, last bool, txid string) error {
+	_, err := handler.handlePrivateDataRequest(pb.ChaincodeMessage_PUT_STATE_CHUNK, &pb.PutStateChunk{Key: key, Data: data, Last: last}, txid)
+	return err
+}
+
+// handleGetStateChunk communicates with the validator to fetch the chunk of the value of a key starting at offset.
+// A nil chunk is returned if the key does not exist.
+func (handler *Handler) handleGetStateChunk(key string, offset int64, txid string) (*pb.StateChunk, error) {
+	res, err := handler.handlePrivateDataRequest(pb.ChaincodeMessage_GET_STATE_CHUNK, &pb.GetStateChunk{Key: key, Offset: offset}, txid)
+	if err != nil || len(res) == 0 {
+		return nil, err
+	}
+	chunk := &pb.StateChunk{}
+	if err = proto.Unmarshal(res, chunk); err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal the state chunk: %s", err)
+	}
+	return chunk, nil
+}
+
+// handlePrivateDataRequest sends a private data (or state metadata or chunk) request of the given type to the validator and waits for its response
+func (handler *Handler) handlePrivateDataRequest(msgType pb.ChaincodeMessage_Type, request proto.Message, txid string) ([]byte, error) {
+	payloadBytes, err := proto.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to process %s request", msgType)
+	}
+
+	// Create the channel on which to communicate the response from validating peer
+	respChan, uniqueReqErr := handler.createChannel(txid)
+	if uniqueReqErr != nil {
+		chaincodeLogger.Errorf("[%s]Another state request pending for this Txid. Cannot process.", shorttxid(txid))
+		return nil, uniqueReqErr
+	}
+
+	defer handler.deleteChannel(txid)
+
+	msg := &pb.ChaincodeMessage{Type: msgType, Payload: payloadBytes, Txid: txid}
+	chaincodeLogger.Debugf("[%s]Sending %s", shorttxid(msg.Txid), msgType)
+	responseMsg, err := handler.sendReceive(msg, respChan)
+	if err != nil {
+		chaincodeLogger.Errorf("[%s]error sending %s %s", shorttxid(txid), msgType, err)
+		return nil, errors.New("could not send msg")
+	}
+
+	if responseMsg.Type.String() == pb.ChaincodeMessage_RESPONSE.String() {
+		// Success response
+		chaincodeLogger.Debugf("[%s]Received %s for %s", shorttxid(responseMsg.Txid), pb.ChaincodeMessage_RESPONSE, msgType)
+		return responseMsg.Payload, nil
+	}
+	if responseMsg.Type.String() == pb.ChaincodeMessage_ERROR.String() {
+		// Error response
+		chaincodeLogger.Errorf("[%s]Received %s for %s. Payload: %s", shorttxid(responseMsg.Txid), pb.ChaincodeMessage_ERROR, msgType, responseMsg.Payload)
+		return nil, errors.New(string(responseMsg.Payload[:]))
+	}
+
+	// Incorrect chaincode message received
+	chaincodeLogger.Errorf("[%s]Incorrect chaincode message %s received. Expecting %s or %s", shorttxid(responseMsg.Txid), responseMsg.Type, pb.ChaincodeMessage_RESPONSE, pb.ChaincodeMessage_ERROR)
+	return nil, errors.New("Incorrect chaincode message received")
+}
+
+func (handler *Handler) handleGetStateByRange(startKey, endKey string, metadata []byte, txid string) (*pb.QueryResponse, error) {
 	// Create the channel on which to communicate the response from validating peer
 	respChan, uniqueReqErr := handler.createChannel(txid)
 	if uniqueReqErr != nil {
@@ -517,7 +618,7 @@ func (handler *Handler) handleGetStateByRange(startKey, endKey string, txid stri
 	defer handler.deleteChannel(txid)
 
 	// Send GET_STATE_BY_RANGE message to validator chaincode support
-	payload := &pb.GetStateByRange{StartKey: startKey, EndKey: endKey}
+	payload := &pb.GetStateByRange{StartKey: startKey, EndKey: endKey, Metadata: metadata}
 	payloadBytes, err := proto.Marshal(payload)
 	if err != nil {
 		return nil, errors.New("Failed to process range query state request")
@@ -650,7 +751,7 @@ func (handler *Handler) handleQueryStateClose(id, txid string) (*pb.QueryRespons
 	return nil, errors.New("Incorrect chaincode message received")
 }
 
-func (handler *Handler) handleGetQueryResult(query string, txid string) (*pb.QueryResponse, error) {
+func (handler *Handler) handleGetQueryResult(query string, metadata []byte, txid string) (*pb.QueryResponse, error) {
 	// Create the channel on which to communicate the response from validating peer
 	respChan, uniqueReqErr := handler.createChannel(txid)
 	if uniqueReqErr != nil {
@@ -661,7 +762,7 @@ func (handler *Handler) handleGetQueryResult(query string, txid string) (*pb.Que
 	defer handler.deleteChannel(txid)
 
 	// Send GET_QUERY_RESULT message to validator chaincode support
-	payload := &pb.GetQueryResult{Query: query}
+	payload := &pb.GetQueryResult{Query: query, Metadata: metadata}
 	payloadBytes, err := proto.Marshal(payload)
 	if err != nil {
 		return nil, errors.New("Failed to process query state request")
@@ -698,7 +799,7 @@ func (handler *Handler) handleGetQueryResult(query string, txid string) (*pb.Que
 	return nil, errors.New("Incorrect chaincode message received")
 }
 
-func (handler *Handler) handleGetHistoryForKey(key string, txid string) (*pb.QueryResponse, error) {
+func (handler *Handler) handleGetHistoryForKey(key string, options *pb.HistoryQueryOptions, txid string) (*pb.QueryResponse, error) {
 	// Create the channel on which to communicate the response from validating peer
 	respChan, uniqueReqErr := handler.createChannel(txid)
 	if uniqueReqErr != nil {
@@ -709,7 +810,7 @@ func (handler *Handler) handleGetHistoryForKey(key string, txid string) (*pb.Que
 	defer handler.deleteChannel(txid)
 
 	// Send GET_HISTORY_FOR_KEY message to validator chaincode support
-	payload := &pb.GetHistoryForKey{Key: key}
+	payload := &pb.GetHistoryForKey{Key: key, Options: options}
 	payloadBytes, err := proto.Marshal(payload)
 	if err != nil {
 		return nil, errors.New("Failed to process query state request")
@@ -748,6 +849,18 @@ func (handler *Handler) handleGetHistoryForKey(key string, txid string) (*pb.Que
 
 // handleInvokeChaincode communicates with the validator to invoke another chaincode.
 func (handler *Handler) handleInvokeChaincode(chaincodeName string, args [][]byte, txid string) pb.Response {
+	return handler.handleCallChaincode(pb.ChaincodeMessage_INVOKE_CHAINCODE, chaincodeName, args, txid)
+}
+
+// handleQueryChaincode communicates with the validator to query another
+// chaincode, which is not allowed to write.
+func (handler *Handler) handleQueryChaincode(chaincodeName string, args [][]byte, txid string) pb.Response {
+	return handler.handleCallChaincode(pb.ChaincodeMessage_QUERY_CHAINCODE, chaincodeName, args, txid)
+}
+
+// handleCallChaincode sends an INVOKE_CHAINCODE or QUERY_CHAINCODE message and
+// returns the response of the called chaincode.
+func (handler *Handler) handleCallChaincode(msgType pb.ChaincodeMessage_Type, chaincodeName string, args [][]byte, txid string) pb.Response {
 	chaincodeID := &pb.ChaincodeID{Name: chaincodeName}
 	input := &pb.ChaincodeInput{Args: args}
 	payload := &pb.ChaincodeSpec{ChaincodeId: chaincodeID, Input: input}
@@ -771,12 +884,12 @@ func (handler *Handler) handleInvokeChaincode(chaincodeName string, args [][]byt
 
 	defer handler.deleteChannel(txid)
 
-	// Send INVOKE_CHAINCODE message to validator chaincode support
-	msg := &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_INVOKE_CHAINCODE, Payload: payloadBytes, Txid: txid}
-	chaincodeLogger.Debugf("[%s]Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_INVOKE_CHAINCODE)
+	// Send INVOKE_CHAINCODE or QUERY_CHAINCODE message to validator chaincode support
+	msg := &pb.ChaincodeMessage{Type: msgType, Payload: payloadBytes, Txid: txid}
+	chaincodeLogger.Debugf("[%s]Sending %s", shorttxid(msg.Txid), msgType)
 	responseMsg, err := handler.sendReceive(msg, respChan)
 	if err != nil {
-		chaincodeLogger.Errorf("[%s]error sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_INVOKE_CHAINCODE)
+		chaincodeLogger.Errorf("[%s]error sending %s", shorttxid(msg.Txid), msgType)
 		return pb.Response{
 			Status:  ERROR,
 			Payload: []byte("could not send msg"),
@@ -17,6 +17,8 @@ limitations under the License.
 package shim
 
 import (
+	"io"
+
 	"github.com/golang/protobuf/ptypes/timestamp"
 
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
@@ -57,6 +59,15 @@ type ChaincodeStubInterface interface {
 	// a `Query`. If `channel` is empty, the caller's channel is assumed.
 	InvokeChaincode(chaincodeName string, args [][]byte, channel string) pb.Response
 
+	// QueryChaincode calls the specified chaincode `Invoke` read-only using the
+	// same transaction context: any attempt of the called chaincode to write
+	// the state returns an error. If the called chaincode is on a different
+	// channel, what it reads there is recorded in the proposal response apart
+	// from the reads of the caller, which are the only ones validated when the
+	// transaction is committed. If `channel` is empty, the caller's channel is
+	// assumed.
+	QueryChaincode(chaincodeName string, args [][]byte, channel string) pb.Response
+
 	// GetState returns the byte array value specified by the `key`.
 	GetState(key string) ([]byte, error)
 
@@ -66,6 +77,48 @@ type ChaincodeStubInterface interface {
 	// DelState removes the specified `key` and its value from the ledger.
 	DelState(key string) error
 
+	// PutStateStream returns a writer streaming the value of `key` to the peer
+	// in chunks, for values too large to be sent with PutState. The value is
+	// written to the key when the writer is closed. The peers keep the value
+	// in a content-addressed store; only its hash is recorded in the
+	// read-write set and the value is sent to the other peers of the channel
+	// apart from the transaction. GetState returns a reference to the value
+	// rather than the value itself.
+	PutStateStream(key string) (io.WriteCloser, error)
+
+	// GetStateStream returns a reader streaming the value of `key` from the
+	// peer in chunks, whether the value was written with PutStateStream or
+	// PutState. A nil reader is returned if the key does not exist.
+	GetStateStream(key string) (io.Reader, error)
+
+	// SetStateValidationParameter sets the key-level endorsement policy for `key`.
+	// The policy `ep` is a serialized common.SignaturePolicyEnvelope; once the
+	// transaction commits, the writes to `key` by later transactions must satisfy
+	// it instead of the chaincode-level endorsement policy. An empty `ep` removes
+	// the key-level policy.
+	SetStateValidationParameter(key string, ep []byte) error
+
+	// GetStateValidationParameter returns the key-level endorsement policy for
+	// `key`. A nil policy is returned if the key has no key-level policy.
+	GetStateValidationParameter(key string) ([]byte, error)
+
+	// GetPrivateData returns the value of the specified `key` from the specified
+	// `collection`. Private data is not written to the ledger; it is held in a
+	// separate store by the peers authorized for the collection, and only its
+	// hash is recorded on the ledger. A nil value is returned if the key does
+	// not exist in the collection.
+	GetPrivateData(collection, key string) ([]byte, error)
+
+	// PutPrivateData puts the specified `key` and `value` into the transaction's
+	// private writeset for the specified `collection`. The plaintext is
+	// disseminated to the peers authorized for the collection; only the hash of
+	// the writeset goes into the transaction proposal response.
+	PutPrivateData(collection string, key string, value []byte) error
+
+	// DelPrivateData records the specified `key` to be deleted from the
+	// specified `collection` in the transaction's private writeset.
+	DelPrivateData(collection, key string) error
+
 	// GetStateByRange function can be invoked by a chaincode to query of a range
 	// of keys in the state. Assuming the startKey and endKey are in lexical
 	// an iterator will be returned that can be used to iterate over all keys
@@ -73,6 +126,16 @@ type ChaincodeStubInterface interface {
 	// returned by the iterator is random.
 	GetStateByRange(startKey, endKey string) (StateQueryIteratorInterface, error)
 
+	// GetStateByRangeWithPagination is similar to GetStateByRange except that the
+	// iterator returned covers a single page of at most pageSize keys. The bookmark
+	// is expected to be either empty (for the first page) or the one returned in the
+	// QueryResponseMetadata of the previous page. The QueryResponseMetadata carries
+	// the number of keys fetched and the bookmark for the next page, which is empty
+	// if there are no more keys. The paginated queries are intended to be used for
+	// read-only queries and a transaction that performs them cannot write the state.
+	GetStateByRangeWithPagination(startKey, endKey string, pageSize int32,
+		bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error)
+
 	// GetStateByPartialCompositeKey function can be invoked by a chaincode to query the
 	// state based on a given partial composite key. This function returns an
 	// iterator which can be used to iterate over all composite keys whose prefix
@@ -98,10 +161,28 @@ type ChaincodeStubInterface interface {
 	// the query result set
 	GetQueryResult(query string) (StateQueryIteratorInterface, error)
 
+	// GetQueryResultWithPagination is similar to GetQueryResult except that the
+	// iterator returned covers a single page of at most pageSize results. The
+	// bookmark is opaque to the chaincode and is expected to be either empty (for
+	// the first page) or the one returned in the QueryResponseMetadata of the
+	// previous page. Like GetStateByRangeWithPagination, this is intended to be
+	// used for read-only queries.
+	GetQueryResultWithPagination(query string, pageSize int32,
+		bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error)
+
 	// GetHistoryForKey function can be invoked by a chaincode to return a history of
 	// key values across time. GetHistoryForKey is intended to be used for read-only queries.
 	GetHistoryForKey(key string) (HistoryQueryIteratorInterface, error)
 
+	// GetHistoryForKeyWithOptions is similar to GetHistoryForKey except that the
+	// key values returned are restricted to the block range and to the time
+	// range of the options, are ordered from the newest to the oldest if
+	// options.Reverse is set and are at most options.Limit (if not zero).
+	// For instance, the latest N values of a key are returned when Reverse is
+	// set and Limit is N. Like GetHistoryForKey, this is intended to be used
+	// for read-only queries.
+	GetHistoryForKeyWithOptions(key string, options *pb.HistoryQueryOptions) (HistoryQueryIteratorInterface, error)
+
 	// GetCreator returns SignatureHeader.Creator of the signedProposal
 	// this Stub refers to.
 	GetCreator() ([]byte, error)
@@ -127,7 +208,8 @@ type ChaincodeStubInterface interface {
 	// all endorsers.
 	GetTxTimestamp() (*timestamp.Timestamp, error)
 
-	// SetEvent saves the event to be sent when a transaction is made part of a block
+	// SetEvent saves the event to be sent when a transaction is made part of a block.
+	// It can be called more than once; every event set is delivered, in order
 	SetEvent(name string, payload []byte) error
 }
 
@@ -19,9 +19,11 @@ limitations under the License.
 package shim
 
 import (
+	"bytes"
 	"container/list"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/golang/protobuf/ptypes/timestamp"
@@ -50,6 +52,12 @@ type MockStub struct {
 	// State keeps name value pairs
 	State map[string][]byte
 
+	// PvtState keeps the name value pairs of each private data collection
+	PvtState map[string]map[string][]byte
+
+	// EndorsementPolicies keeps the key-level endorsement policies set by the chaincode
+	EndorsementPolicies map[string][]byte
+
 	// Keys stores the list of mapped values in lexical order
 	Keys *list.List
 
@@ -64,6 +72,9 @@ type MockStub struct {
 
 	// mocked signedProposal
 	signedProposal *pb.SignedProposal
+
+	// set while the stub is called through QueryChaincode
+	readOnly bool
 }
 
 func (stub *MockStub) GetTxID() string {
@@ -157,6 +168,9 @@ func (stub *MockStub) PutState(key string, value []byte) error {
 		mockLogger.Error("Cannot PutState without a transactions - call stub.MockTransactionStart()?")
 		return errors.New("Cannot PutState without a transactions - call stub.MockTransactionStart()?")
 	}
+	if err := stub.checkWritable(); err != nil {
+		return err
+	}
 
 	mockLogger.Debug("MockStub", stub.Name, "Putting", key, value)
 	stub.State[key] = value
@@ -194,10 +208,110 @@ func (stub *MockStub) PutState(key string, value []byte) error {
 	return nil
 }
 
+// PutStateStream returns a writer buffering the value of a key, which is
+// written with PutState when the writer is closed
+func (stub *MockStub) PutStateStream(key string) (io.WriteCloser, error) {
+	if err := stub.checkWritable(); err != nil {
+		return nil, err
+	}
+	return &mockStateWriter{put: func(value []byte) error { return stub.PutState(key, value) }}, nil
+}
+
+// GetStateStream returns a reader over the value of a key read with GetState
+func (stub *MockStub) GetStateStream(key string) (io.Reader, error) {
+	value, err := stub.GetState(key)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return bytes.NewReader(value), nil
+}
+
+// mockStateWriter buffers a streamed value until it is closed
+type mockStateWriter struct {
+	buf    bytes.Buffer
+	put    func([]byte) error
+	closed bool
+}
+
+func (w *mockStateWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errors.New("write to a closed state stream")
+	}
+	return w.buf.Write(p)
+}
+
+func (w *mockStateWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.put(w.buf.Bytes())
+}
+
+// SetStateValidationParameter sets the key-level endorsement policy of a key
+func (stub *MockStub) SetStateValidationParameter(key string, ep []byte) error {
+	if err := stub.checkWritable(); err != nil {
+		return err
+	}
+	if len(ep) == 0 {
+		delete(stub.EndorsementPolicies, key)
+		return nil
+	}
+	stub.EndorsementPolicies[key] = ep
+	return nil
+}
+
+// GetStateValidationParameter retrieves the key-level endorsement policy of a key
+func (stub *MockStub) GetStateValidationParameter(key string) ([]byte, error) {
+	return stub.EndorsementPolicies[key], nil
+}
+
+// GetPrivateData retrieves the value for a given key from a private data collection
+func (stub *MockStub) GetPrivateData(collection string, key string) ([]byte, error) {
+	m, in := stub.PvtState[collection]
+	if !in {
+		return nil, nil
+	}
+	return m[key], nil
+}
+
+// PutPrivateData writes the specified `value` and `key` into a private data collection
+func (stub *MockStub) PutPrivateData(collection string, key string, value []byte) error {
+	if stub.TxID == "" {
+		mockLogger.Error("Cannot PutPrivateData without a transactions - call stub.MockTransactionStart()?")
+		return errors.New("Cannot PutPrivateData without a transactions - call stub.MockTransactionStart()?")
+	}
+	if err := stub.checkWritable(); err != nil {
+		return err
+	}
+	m, in := stub.PvtState[collection]
+	if !in {
+		m = make(map[string][]byte)
+		stub.PvtState[collection] = m
+	}
+	m[key] = value
+	return nil
+}
+
+// DelPrivateData removes the specified `key` and its value from a private data collection
+func (stub *MockStub) DelPrivateData(collection string, key string) error {
+	if err := stub.checkWritable(); err != nil {
+		return err
+	}
+	if m, in := stub.PvtState[collection]; in {
+		delete(m, key)
+	}
+	return nil
+}
+
 // DelState removes the specified `key` and its value from the ledger.
 func (stub *MockStub) DelState(key string) error {
+	if err := stub.checkWritable(); err != nil {
+		return err
+	}
 	mockLogger.Debug("MockStub", stub.Name, "Deleting", key, stub.State[key])
 	delete(stub.State, key)
+	delete(stub.EndorsementPolicies, key)
 
 	for elem := stub.Keys.Front(); elem != nil; elem = elem.Next() {
 		if strings.Compare(key, elem.Value.(string)) == 0 {
@@ -212,6 +326,38 @@ func (stub *MockStub) GetStateByRange(startKey, endKey string) (StateQueryIterat
 	return NewMockStateRangeQueryIterator(stub, startKey, endKey), nil
 }
 
+// GetStateByRangeWithPagination function can be invoked by a chaincode to query
+// a single page of a range of keys in the state. The bookmark is the first key of
+// the page, if not empty.
+func (stub *MockStub) GetStateByRangeWithPagination(startKey, endKey string, pageSize int32,
+	bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	if pageSize <= 0 {
+		return nil, nil, fmt.Errorf("Page size should be greater than zero, found [%d]", pageSize)
+	}
+	if bookmark != "" {
+		startKey = bookmark
+	}
+	pageIter := &MockPaginatedQueryIterator{}
+	metadata := &pb.QueryResponseMetadata{}
+	// stub.Keys is sorted, the startKey is inclusive and the endKey (if not empty) is exclusive
+	for elem := stub.Keys.Front(); elem != nil; elem = elem.Next() {
+		key := elem.Value.(string)
+		if key < startKey {
+			continue
+		}
+		if endKey != "" && key >= endKey {
+			break
+		}
+		if len(pageIter.Results) == int(pageSize) {
+			metadata.Bookmark = key
+			break
+		}
+		pageIter.Results = append(pageIter.Results, &queryresult.KV{Key: key, Value: stub.State[key]})
+	}
+	metadata.FetchedRecordsCount = int32(len(pageIter.Results))
+	return pageIter, metadata, nil
+}
+
 // GetQueryResult function can be invoked by a chaincode to perform a
 // rich query against state database.  Only supported by state database implementations
 // that support rich query.  The query string is in the syntax of the underlying
@@ -224,12 +370,24 @@ func (stub *MockStub) GetQueryResult(query string) (StateQueryIteratorInterface,
 	return nil, errors.New("Not Implemented")
 }
 
+// GetQueryResultWithPagination is not implemented since the mock engine does not have a query engine
+func (stub *MockStub) GetQueryResultWithPagination(query string, pageSize int32,
+	bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	return nil, nil, errors.New("Not Implemented")
+}
+
 // GetHistoryForKey function can be invoked by a chaincode to return a history of
 // key values across time. GetHistoryForKey is intended to be used for read-only queries.
 func (stub *MockStub) GetHistoryForKey(key string) (HistoryQueryIteratorInterface, error) {
 	return nil, errors.New("Not Implemented")
 }
 
+// GetHistoryForKeyWithOptions function can be invoked by a chaincode to return a
+// restricted history of key values across time.
+func (stub *MockStub) GetHistoryForKeyWithOptions(key string, options *pb.HistoryQueryOptions) (HistoryQueryIteratorInterface, error) {
+	return nil, errors.New("Not Implemented")
+}
+
 //GetStateByPartialCompositeKey function can be invoked by a chaincode to query the
 //state based on a given partial composite key. This function returns an
 //iterator which can be used to iterate over all composite keys whose prefix
@@ -270,6 +428,30 @@ func (stub *MockStub) InvokeChaincode(chaincodeName string, args [][]byte, chann
 	return res
 }
 
+// QueryChaincode calls a peered chaincode read-only: its writes fail.
+// The peered chaincode is registered as for InvokeChaincode.
+func (stub *MockStub) QueryChaincode(chaincodeName string, args [][]byte, channel string) pb.Response {
+	// Internally we use chaincode name as a composite name
+	if channel != "" {
+		chaincodeName = chaincodeName + "/" + channel
+	}
+	otherStub := stub.Invokables[chaincodeName]
+	mockLogger.Debug("MockStub", stub.Name, "Querying peer chaincode", otherStub.Name, args)
+	readOnly := otherStub.readOnly
+	otherStub.readOnly = true
+	defer func() { otherStub.readOnly = readOnly }()
+	res := otherStub.MockInvoke(stub.TxID, args)
+	mockLogger.Debug("MockStub", stub.Name, "Queried peer chaincode", otherStub.Name, "got", fmt.Sprintf("%+v", res))
+	return res
+}
+
+func (stub *MockStub) checkWritable() error {
+	if stub.readOnly {
+		return fmt.Errorf("chaincode %s cannot write: it was called read-only", stub.Name)
+	}
+	return nil
+}
+
 // Not implemented
 func (stub *MockStub) GetCreator() ([]byte, error) {
 	return nil, nil
@@ -322,6 +504,8 @@ func NewMockStub(name string, cc Chaincode) *MockStub {
 	s.Name = name
 	s.cc = cc
 	s.State = make(map[string][]byte)
+	s.PvtState = make(map[string]map[string][]byte)
+	s.EndorsementPolicies = make(map[string][]byte)
 	s.Invokables = make(map[string]*MockStub)
 	s.Keys = list.New()
 
@@ -446,6 +630,41 @@ func NewMockStateRangeQueryIterator(stub *MockStub, startKey string, endKey stri
 	return iter
 }
 
+/*****************************
+ Paginated Query Iterator
+*****************************/
+
+// MockPaginatedQueryIterator iterates over a single page of query results
+type MockPaginatedQueryIterator struct {
+	Closed  bool
+	Results []*queryresult.KV
+	Current int
+}
+
+// HasNext returns true if the page contains additional keys and values.
+func (iter *MockPaginatedQueryIterator) HasNext() bool {
+	return !iter.Closed && iter.Current < len(iter.Results)
+}
+
+// Next returns the next key and value in the page.
+func (iter *MockPaginatedQueryIterator) Next() (*queryresult.KV, error) {
+	if !iter.HasNext() {
+		return nil, errors.New("MockPaginatedQueryIterator.Next() called when it does not HaveNext()")
+	}
+	kv := iter.Results[iter.Current]
+	iter.Current++
+	return kv, nil
+}
+
+// Close closes the iterator.
+func (iter *MockPaginatedQueryIterator) Close() error {
+	if iter.Closed {
+		return errors.New("MockPaginatedQueryIterator.Close() called after Close()")
+	}
+	iter.Closed = true
+	return nil
+}
+
 func getBytes(function string, args []string) [][]byte {
 	bytes := make([][]byte, 0, len(args)+1)
 	bytes = append(bytes, []byte(function))
@@ -19,9 +19,11 @@ package shim
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"reflect"
 	"testing"
 
+	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/spf13/viper"
 )
 
@@ -83,6 +85,46 @@ func TestMockStateRangeQueryIterator_openEnded(t *testing.T) {
 
 // TestSetupChaincodeLogging uses the utlity function defined in chaincode.go to
 // set the chaincodeLogger's logging format and level
+func TestMockGetStateByRangeWithPagination(t *testing.T) {
+	stub := NewMockStub("rangeTest", nil)
+	stub.MockTransactionStart("init")
+	for _, key := range []string{"1", "0", "5", "3", "4", "6"} {
+		stub.PutState(key, []byte(key))
+	}
+	stub.MockTransactionEnd("init")
+
+	var keys []string
+	bookmark := ""
+	numPages := 0
+	for {
+		iter, metadata, err := stub.GetStateByRangeWithPagination("", "", 4, bookmark)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		numRecs := 0
+		for iter.HasNext() {
+			kv, _ := iter.Next()
+			keys = append(keys, kv.Key)
+			numRecs++
+		}
+		iter.Close()
+		numPages++
+		if metadata.FetchedRecordsCount != int32(numRecs) {
+			t.Fatalf("Expected fetched records count %d, got %d", numRecs, metadata.FetchedRecordsCount)
+		}
+		if bookmark = metadata.Bookmark; bookmark == "" {
+			break
+		}
+	}
+	if numPages != 2 || !reflect.DeepEqual(keys, []string{"0", "1", "3", "4", "5", "6"}) {
+		t.Fatalf("Unexpected pages [%d] or keys %v", numPages, keys)
+	}
+
+	if _, _, err := stub.GetStateByRangeWithPagination("", "", 0, ""); err == nil {
+		t.Fatal("Expected an error for zero page size")
+	}
+}
+
 func TestSetupChaincodeLogging_blankLevel(t *testing.T) {
 	// set log level to a non-default level
 	testLogLevelString := ""
@@ -231,3 +273,126 @@ func TestGetTxTimestamp(t *testing.T) {
 
 	stub.MockTransactionEnd("init")
 }
+
+func TestMockPrivateData(t *testing.T) {
+	stub := NewMockStub("PrivateData", nil)
+	if err := stub.PutPrivateData("coll1", "key1", []byte("value1")); err == nil {
+		t.Fatal("Expected PutPrivateData outside a transaction to fail")
+	}
+
+	stub.MockTransactionStart("init")
+	stub.PutPrivateData("coll1", "key1", []byte("value1"))
+	stub.PutState("key1", []byte("public"))
+
+	value, err := stub.GetPrivateData("coll1", "key1")
+	if err != nil || string(value) != "value1" {
+		t.Fatalf("Expected value1, got %s (err: %v)", value, err)
+	}
+	// collections and the public state are isolated from each other
+	if value, _ = stub.GetPrivateData("coll2", "key1"); value != nil {
+		t.Fatalf("Expected nil, got %s", value)
+	}
+	if value, _ = stub.GetState("key1"); string(value) != "public" {
+		t.Fatalf("Expected public, got %s", value)
+	}
+
+	stub.DelPrivateData("coll1", "key1")
+	if value, _ = stub.GetPrivateData("coll1", "key1"); value != nil {
+		t.Fatalf("Expected nil after delete, got %s", value)
+	}
+	stub.MockTransactionEnd("init")
+}
+
+func TestMockStateValidationParameter(t *testing.T) {
+	stub := NewMockStub("ValidationParameter", nil)
+	stub.MockTransactionStart("init")
+	stub.PutState("key1", []byte("value1"))
+	stub.SetStateValidationParameter("key1", []byte("policy1"))
+
+	ep, err := stub.GetStateValidationParameter("key1")
+	if err != nil || string(ep) != "policy1" {
+		t.Fatalf("Expected policy1, got %s (err: %v)", ep, err)
+	}
+	if ep, _ = stub.GetStateValidationParameter("key2"); ep != nil {
+		t.Fatalf("Expected nil, got %s", ep)
+	}
+
+	// deleting the key removes its key-level policy
+	stub.DelState("key1")
+	if ep, _ = stub.GetStateValidationParameter("key1"); ep != nil {
+		t.Fatalf("Expected nil after delete, got %s", ep)
+	}
+	stub.MockTransactionEnd("init")
+}
+
+type putStateChaincode struct{}
+
+func (putStateChaincode) Init(stub ChaincodeStubInterface) pb.Response {
+	return Success(nil)
+}
+
+func (putStateChaincode) Invoke(stub ChaincodeStubInterface) pb.Response {
+	if err := stub.PutState("key", []byte("value")); err != nil {
+		return Error(err.Error())
+	}
+	return Success(nil)
+}
+
+func TestMockQueryChaincode(t *testing.T) {
+	callee := NewMockStub("callee", putStateChaincode{})
+	caller := NewMockStub("caller", nil)
+	caller.MockPeerChaincode("callee/ch2", callee)
+	caller.MockTransactionStart("tx1")
+
+	// a queried chaincode cannot write
+	res := caller.QueryChaincode("callee", nil, "ch2")
+	if res.Status != ERROR {
+		t.Fatalf("Expected the query to fail, got status %d", res.Status)
+	}
+	if _, ok := callee.State["key"]; ok {
+		t.Fatal("Expected the queried chaincode not to write")
+	}
+
+	// while an invoked one can
+	res = caller.InvokeChaincode("callee", nil, "ch2")
+	if res.Status != OK {
+		t.Fatalf("Expected the invoke to succeed, got status %d (%s)", res.Status, res.Message)
+	}
+	if string(callee.State["key"]) != "value" {
+		t.Fatalf("Expected the invoked chaincode to write, got %s", callee.State["key"])
+	}
+	caller.MockTransactionEnd("tx1")
+}
+
+func TestMockStateStream(t *testing.T) {
+	stub := NewMockStub("StateStream", nil)
+	stub.MockTransactionStart("init")
+	w, err := stub.PutStateStream("key1")
+	if err != nil {
+		t.Fatalf("Failed to open state stream: %s", err)
+	}
+	w.Write([]byte("part1,"))
+	w.Write([]byte("part2"))
+	if value, _ := stub.GetState("key1"); value != nil {
+		t.Fatalf("Expected the value to be written on close, got %s", value)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatalf("Failed to close state stream: %s", err)
+	}
+	if _, err = w.Write([]byte("part3")); err == nil {
+		t.Fatal("Expected write to a closed stream to fail")
+	}
+
+	r, err := stub.GetStateStream("key1")
+	if err != nil {
+		t.Fatalf("Failed to open state stream: %s", err)
+	}
+	value, err := ioutil.ReadAll(r)
+	if err != nil || string(value) != "part1,part2" {
+		t.Fatalf("Expected part1,part2, got %s (err: %v)", value, err)
+	}
+	if r, err = stub.GetStateStream("key2"); r != nil || err != nil {
+		t.Fatalf("Expected a nil reader for a missing key, got %v (err: %v)", r, err)
+	}
+	stub.MockTransactionEnd("init")
+}
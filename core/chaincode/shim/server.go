@@ -0,0 +1,96 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shim
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/core/comm"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// ChaincodeServer runs a chaincode as a long-lived service. Rather than
+// connecting to its peer, the chaincode listens on Address and the peer
+// connects to it through the Chaincode service
+type ChaincodeServer struct {
+	// CCID is the ID the chaincode registers with, in the name:version form
+	CCID string
+	// Address is the listen address of the server as hostname:port
+	Address string
+	// CC is the chaincode served to the peers connecting to the server
+	CC Chaincode
+	// SecureConfig configures TLS for the connections of the peers
+	SecureConfig comm.SecureServerConfig
+}
+
+// Start is the entry point for chaincodes run as a service. It serves the
+// peers connecting to Address and only returns when the server fails.
+func (cs *ChaincodeServer) Start() error {
+	if cs.CCID == "" {
+		return errors.New("Error chaincode id not provided")
+	}
+	if cs.CC == nil {
+		return errors.New("Error chaincode not provided")
+	}
+	if cs.Address == "" {
+		return errors.New("Error chaincode server address not provided")
+	}
+
+	SetupChaincodeLogging()
+
+	err := factory.InitFactories(&factory.DefaultOpts)
+	if err != nil {
+		return fmt.Errorf("Internal error, BCCSP could not be initialized with default options: %s", err)
+	}
+
+	server, err := comm.NewGRPCServer(cs.Address, cs.SecureConfig)
+	if err != nil {
+		return fmt.Errorf("Error creating chaincode server at address=%s: %s", cs.Address, err)
+	}
+	return cs.serve(server)
+}
+
+func (cs *ChaincodeServer) serve(server comm.GRPCServer) error {
+	pb.RegisterChaincodeServer(server.Server(), &chaincodeService{ccid: cs.CCID, cc: cs.CC})
+	chaincodeLogger.Infof("Chaincode %s listening on %s", cs.CCID, server.Address())
+	return server.Start()
+}
+
+// chaincodeService implements pb.ChaincodeServer
+type chaincodeService struct {
+	ccid string
+	cc   Chaincode
+}
+
+// Connect handles a stream opened by a peer exactly like a stream opened to
+// the peer by Start: the chaincode registers first and then serves the peer
+func (s *chaincodeService) Connect(stream pb.Chaincode_ConnectServer) error {
+	chaincodeLogger.Debugf("Peer connected, starting chat using name=%s", s.ccid)
+	return chatWithPeer(s.ccid, &serverStream{stream}, s.cc)
+}
+
+// serverStream adapts the server side of the Chaincode stream to
+// PeerChaincodeStream. The stream is closed when Connect returns
+type serverStream struct {
+	pb.Chaincode_ConnectServer
+}
+
+func (s *serverStream) CloseSend() error {
+	return nil
+}
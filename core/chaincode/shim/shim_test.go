@@ -20,6 +20,7 @@ import (
 	"os"
 	"testing"
 
+	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/op/go-logging"
 )
 
@@ -142,3 +143,52 @@ func TestNilEventName(t *testing.T) {
 	}
 
 }
+
+func TestMultipleEvents(t *testing.T) {
+	stub := ChaincodeStub{}
+	msg := stub.withEvents(&pb.ChaincodeMessage{})
+	if msg.ChaincodeEvent != nil || len(msg.ChaincodeEvents) != 0 {
+		t.Error("No events should be attached when none were set")
+	}
+
+	stub.SetEvent("first", []byte("1"))
+	msg = stub.withEvents(&pb.ChaincodeMessage{})
+	if msg.ChaincodeEvent == nil || msg.ChaincodeEvent.EventName != "first" || len(msg.ChaincodeEvents) != 0 {
+		t.Error("A single event should be sent in the ChaincodeEvent field")
+	}
+
+	stub.SetEvent("second", []byte("2"))
+	msg = stub.withEvents(&pb.ChaincodeMessage{})
+	if msg.ChaincodeEvent != nil || len(msg.ChaincodeEvents) != 2 {
+		t.Fatal("Several events should be sent in the ChaincodeEvents field")
+	}
+	if msg.ChaincodeEvents[0].EventName != "first" || msg.ChaincodeEvents[1].EventName != "second" {
+		t.Error("Events should be kept in the order they were set")
+	}
+}
+
+type shimTestCC struct {
+}
+
+func (cc *shimTestCC) Init(stub ChaincodeStubInterface) pb.Response {
+	return Success(nil)
+}
+
+func (cc *shimTestCC) Invoke(stub ChaincodeStubInterface) pb.Response {
+	return Success(nil)
+}
+
+func TestChaincodeServerStartErrors(t *testing.T) {
+	server := &ChaincodeServer{Address: "localhost:0", CC: &shimTestCC{}}
+	if err := server.Start(); err == nil {
+		t.Error("Chaincode server should not start without chaincode id")
+	}
+	server = &ChaincodeServer{CCID: "mycc:1.0", Address: "localhost:0"}
+	if err := server.Start(); err == nil {
+		t.Error("Chaincode server should not start without chaincode")
+	}
+	server = &ChaincodeServer{CCID: "mycc:1.0", CC: &shimTestCC{}}
+	if err := server.Start(); err == nil {
+		t.Error("Chaincode server should not start without address")
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shimtest
+
+import (
+	"errors"
+
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+)
+
+// stateQueryIterator iterates over the results of a range or rich query,
+// which are computed when the query is executed
+type stateQueryIterator struct {
+	results []*queryresult.KV
+	index   int
+}
+
+// HasNext returns true if the iterator has more results
+func (iter *stateQueryIterator) HasNext() bool {
+	return iter.index < len(iter.results)
+}
+
+// Next returns the next result of the query
+func (iter *stateQueryIterator) Next() (*queryresult.KV, error) {
+	if !iter.HasNext() {
+		return nil, errors.New("no more results")
+	}
+	iter.index++
+	return iter.results[iter.index-1], nil
+}
+
+// Close releases the iterator
+func (iter *stateQueryIterator) Close() error {
+	iter.index = len(iter.results)
+	return nil
+}
+
+// historyQueryIterator iterates over the modifications of a key
+type historyQueryIterator struct {
+	results []*queryresult.KeyModification
+	index   int
+}
+
+// HasNext returns true if the iterator has more modifications
+func (iter *historyQueryIterator) HasNext() bool {
+	return iter.index < len(iter.results)
+}
+
+// Next returns the next modification of the key
+func (iter *historyQueryIterator) Next() (*queryresult.KeyModification, error) {
+	if !iter.HasNext() {
+		return nil, errors.New("no more results")
+	}
+	iter.index++
+	return iter.results[iter.index-1], nil
+}
+
+// Close releases the iterator
+func (iter *historyQueryIterator) Close() error {
+	iter.index = len(iter.results)
+	return nil
+}
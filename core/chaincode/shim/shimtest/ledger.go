@@ -0,0 +1,255 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package shimtest provides an in-memory ledger to unit test chaincodes
+// through the full ChaincodeStubInterface, without a peer.
+package shimtest
+
+import (
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// Ledger is the in-memory ledger of a channel on which chaincodes are
+// deployed and invoked for their unit tests. Each invocation is simulated
+// against the committed state as an endorser does, then committed in a block
+// where it is validated as a committing peer does: a transaction whose reads
+// were updated by a preceding transaction, in the block or in a previous one,
+// is invalidated with an MVCC or phantom read conflict and its writes are
+// discarded. The rich queries are evaluated as CouchDB evaluates the Mango
+// selectors and, as on a peer, they are not validated at commit time.
+// A Ledger is not meant to be used from several goroutines.
+type Ledger struct {
+	// ChannelID is the channel of the transactions
+	ChannelID string
+
+	// Creator is the serialized identity that creates the next transactions
+	Creator []byte
+
+	// Transient is the transient map of the proposals of the next transactions
+	Transient map[string][]byte
+
+	// Clock gives the timestamp of the next transactions, time.Now by default
+	Clock func() time.Time
+
+	chaincodes map[string]shim.Chaincode
+	state      map[stateKey]*versionedValue
+	history    map[stateKey][]*historyEntry
+	// number of blocks committed, including the genesis block
+	height uint64
+}
+
+// Block gathers transactions that are simulated independently, against the
+// committed state, and then validated and committed together, in order
+type Block struct {
+	ledger *Ledger
+	txs    []*TxResult
+}
+
+// TxResult is the outcome of a transaction
+type TxResult struct {
+	// TxID is the ID of the transaction
+	TxID string
+
+	// Response is the response of the invoked chaincode
+	Response pb.Response
+
+	// Events are the events set by the invoked chaincode, in order
+	Events []*pb.ChaincodeEvent
+
+	// Committed is true once the block of the transaction is committed.
+	// A transaction whose response is an error is never committed since it
+	// would not be endorsed
+	Committed bool
+
+	// BlockNum is the number of the block of the transaction once committed
+	BlockNum uint64
+
+	// ValidationCode is the result of the validation of the transaction
+	// once committed, TxValidationCode_VALID if its writes were applied
+	ValidationCode pb.TxValidationCode
+
+	rwset     *txRWSet
+	timestamp *timestamp.Timestamp
+}
+
+// stateKey identifies a key of the public state of a chaincode (empty
+// collection) or of one of its private data collections
+type stateKey struct {
+	namespace  string
+	collection string
+	key        string
+}
+
+// height is the version of a value: the block and transaction that wrote it
+type height struct {
+	blockNum uint64
+	txNum    uint64
+}
+
+type versionedValue struct {
+	value               []byte
+	validationParameter []byte
+	version             *height
+}
+
+type historyEntry struct {
+	blockNum     uint64
+	modification *queryresult.KeyModification
+}
+
+// NewLedger returns an empty ledger for the channel channelID, holding its
+// genesis block only
+func NewLedger(channelID string) *Ledger {
+	return &Ledger{
+		ChannelID:  channelID,
+		Clock:      time.Now,
+		chaincodes: make(map[string]shim.Chaincode),
+		state:      make(map[stateKey]*versionedValue),
+		history:    make(map[stateKey][]*historyEntry),
+		height:     1,
+	}
+}
+
+// Deploy makes the chaincode cc available under the given name and commits
+// a block with the transaction calling its Init with the given arguments
+func (l *Ledger) Deploy(name string, cc shim.Chaincode, args ...[]byte) *TxResult {
+	l.chaincodes[name] = cc
+	block := l.NewBlock()
+	tx := block.simulate(name, args, true)
+	block.Commit()
+	return tx
+}
+
+// Invoke commits a block with the transaction invoking the chaincode name
+// with the given arguments
+func (l *Ledger) Invoke(name string, args ...[]byte) *TxResult {
+	block := l.NewBlock()
+	tx := block.Invoke(name, args...)
+	block.Commit()
+	return tx
+}
+
+// NewBlock returns an empty block, to be committed after the last block
+// committed when it is
+func (l *Ledger) NewBlock() *Block {
+	return &Block{ledger: l}
+}
+
+// Height returns the number of blocks committed, including the genesis block
+func (l *Ledger) Height() uint64 {
+	return l.height
+}
+
+// GetState returns the committed value of the key of the given chaincode,
+// nil if the key does not exist
+func (l *Ledger) GetState(namespace, key string) []byte {
+	return l.getValue(stateKey{namespace: namespace, key: key})
+}
+
+// GetPrivateData returns the committed value of the key in the private data
+// collection of the given chaincode, nil if the key does not exist
+func (l *Ledger) GetPrivateData(namespace, collection, key string) []byte {
+	return l.getValue(stateKey{namespace: namespace, collection: collection, key: key})
+}
+
+// GetStateValidationParameter returns the committed key-level endorsement
+// policy of the key of the given chaincode, nil if it has none
+func (l *Ledger) GetStateValidationParameter(namespace, key string) []byte {
+	if vv := l.state[stateKey{namespace: namespace, key: key}]; vv != nil {
+		return vv.validationParameter
+	}
+	return nil
+}
+
+func (l *Ledger) getValue(key stateKey) []byte {
+	if vv := l.state[key]; vv != nil {
+		return vv.value
+	}
+	return nil
+}
+
+func (l *Ledger) getVersion(key stateKey) *height {
+	if vv := l.state[key]; vv != nil {
+		return vv.version
+	}
+	return nil
+}
+
+// getSortedKeys returns in lexical order the keys of the public state of the
+// namespace in the range [startKey, endKey), endKey being unbounded if empty
+func (l *Ledger) getSortedKeys(namespace, startKey, endKey string) []string {
+	var keys []string
+	for key := range l.state {
+		if key.namespace != namespace || key.collection != "" || key.key < startKey {
+			continue
+		}
+		if endKey != "" && key.key >= endKey {
+			continue
+		}
+		keys = append(keys, key.key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Invoke simulates the transaction invoking the chaincode name with the given
+// arguments against the committed state. The transaction is validated when
+// the block is committed
+func (b *Block) Invoke(name string, args ...[]byte) *TxResult {
+	return b.simulate(name, args, false)
+}
+
+func (b *Block) simulate(name string, args [][]byte, isInit bool) *TxResult {
+	tx := newTransaction(b.ledger, name, args)
+	stub := newStub(tx, name, args, false)
+	cc, ok := b.ledger.chaincodes[name]
+	if !ok {
+		tx.result.Response = shim.Error("chaincode " + name + " is not deployed")
+	} else if isInit {
+		tx.result.Response = cc.Init(stub)
+	} else {
+		tx.result.Response = cc.Invoke(stub)
+	}
+	tx.result.Events = stub.events
+	if tx.result.Response.Status < shim.ERROR {
+		b.txs = append(b.txs, tx.result)
+	}
+	return tx.result
+}
+
+// Commit validates the transactions of the block in order and applies the
+// writes of the valid ones to the state of the ledger
+func (b *Block) Commit() {
+	l := b.ledger
+	blockNum := l.height
+	for txNum, tx := range b.txs {
+		tx.Committed = true
+		tx.BlockNum = blockNum
+		tx.ValidationCode = tx.rwset.validate(l)
+		if tx.ValidationCode == pb.TxValidationCode_VALID {
+			tx.rwset.apply(l, tx, &height{blockNum: blockNum, txNum: uint64(txNum)})
+		}
+	}
+	l.height++
+	b.txs = nil
+}
@@ -0,0 +1,218 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shimtest
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+type testChaincode struct{}
+
+func (testChaincode) Init(stub shim.ChaincodeStubInterface) pb.Response {
+	return shim.Success(nil)
+}
+
+func (testChaincode) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
+	function, args := stub.GetFunctionAndParameters()
+	var err error
+	var payload []byte
+	switch function {
+	case "put":
+		err = stub.PutState(args[0], []byte(args[1]))
+	case "get":
+		payload, err = stub.GetState(args[0])
+	case "incr":
+		var value []byte
+		if value, err = stub.GetState(args[0]); err == nil {
+			n, _ := strconv.Atoi(string(value))
+			err = stub.PutState(args[0], []byte(strconv.Itoa(n+1)))
+		}
+	case "count":
+		var iter shim.StateQueryIteratorInterface
+		if iter, err = stub.GetStateByRange(args[0], args[1]); err == nil {
+			n := 0
+			for ; iter.HasNext(); n++ {
+				iter.Next()
+			}
+			err = stub.PutState("count", []byte(strconv.Itoa(n)))
+		}
+	case "query":
+		var iter shim.StateQueryIteratorInterface
+		if iter, err = stub.GetQueryResult(args[0]); err == nil {
+			var keys []string
+			for iter.HasNext() {
+				kv, _ := iter.Next()
+				keys = append(keys, kv.Key)
+			}
+			payload = []byte(strings.Join(keys, ","))
+		}
+	case "history":
+		var iter shim.HistoryQueryIteratorInterface
+		if iter, err = stub.GetHistoryForKey(args[0]); err == nil {
+			var values []string
+			for iter.HasNext() {
+				km, _ := iter.Next()
+				values = append(values, string(km.Value))
+			}
+			payload = []byte(strings.Join(values, ","))
+		}
+	case "creator":
+		payload, err = stub.GetCreator()
+	case "transient":
+		var transient map[string][]byte
+		if transient, err = stub.GetTransient(); err == nil {
+			payload = transient[args[0]]
+		}
+	case "timestamp":
+		ts, _ := stub.GetTxTimestamp()
+		payload = []byte(strconv.FormatInt(ts.Seconds, 10))
+	case "event":
+		err = stub.SetEvent(args[0], []byte(args[1]))
+	case "querycc":
+		return stub.QueryChaincode(args[0], [][]byte{[]byte(args[1]), []byte(args[2]), []byte(args[3])}, "")
+	case "paginate":
+		if _, _, err = stub.GetStateByRangeWithPagination("", "", 1, ""); err == nil {
+			err = stub.PutState(args[0], []byte(args[1]))
+		}
+	}
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	return shim.Success(payload)
+}
+
+func args(strs ...string) [][]byte {
+	var res [][]byte
+	for _, s := range strs {
+		res = append(res, []byte(s))
+	}
+	return res
+}
+
+func TestLedgerInvoke(t *testing.T) {
+	l := NewLedger("ch1")
+	l.Clock = func() time.Time { return time.Unix(1000, 0) }
+	l.Creator = []byte("creator")
+	l.Transient = map[string][]byte{"secret": []byte("s3cr3t")}
+
+	if tx := l.Deploy("cc", testChaincode{}); !tx.Committed || tx.ValidationCode != pb.TxValidationCode_VALID {
+		t.Fatalf("Expected the deployment to be committed, got %v", tx)
+	}
+	l.Invoke("cc", args("put", "k1", "v1")...)
+	l.Invoke("cc", args("put", "k1", "v2")...)
+	if string(l.GetState("cc", "k1")) != "v2" || l.Height() != 4 {
+		t.Fatalf("Expected k1=v2 at height 4, got %s at height %d", l.GetState("cc", "k1"), l.Height())
+	}
+
+	for _, tc := range []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"get", "k1"}, "v2"},
+		{[]string{"history", "k1"}, "v1,v2"},
+		{[]string{"creator"}, "creator"},
+		{[]string{"transient", "secret"}, "s3cr3t"},
+		{[]string{"timestamp"}, "1000"},
+	} {
+		tx := l.Invoke("cc", args(tc.args...)...)
+		if string(tx.Response.Payload) != tc.expected {
+			t.Fatalf("Expected %s for %v, got %s", tc.expected, tc.args, tx.Response.Payload)
+		}
+	}
+
+	tx := l.Invoke("cc", args("event", "evt", "payload")...)
+	if len(tx.Events) != 1 || tx.Events[0].EventName != "evt" || tx.Events[0].TxId != tx.TxID || tx.Events[0].ChaincodeId != "cc" {
+		t.Fatalf("Unexpected events %v", tx.Events)
+	}
+
+	// a failed transaction is not committed
+	tx = l.Invoke("unknown", args("put", "k1", "v3")...)
+	if tx.Committed || tx.Response.Status != shim.ERROR {
+		t.Fatalf("Expected the transaction not to be committed, got %v", tx)
+	}
+}
+
+func TestBlockConflicts(t *testing.T) {
+	l := NewLedger("ch1")
+	l.Deploy("cc", testChaincode{})
+	l.Invoke("cc", args("put", "a", "1")...)
+
+	// both transactions read a, the second one conflicts
+	block := l.NewBlock()
+	tx1 := block.Invoke("cc", args("incr", "a")...)
+	tx2 := block.Invoke("cc", args("incr", "a")...)
+	block.Commit()
+	if tx1.ValidationCode != pb.TxValidationCode_VALID || tx2.ValidationCode != pb.TxValidationCode_MVCC_READ_CONFLICT {
+		t.Fatalf("Expected VALID and MVCC_READ_CONFLICT, got %s and %s", tx1.ValidationCode, tx2.ValidationCode)
+	}
+	if string(l.GetState("cc", "a")) != "2" {
+		t.Fatalf("Expected a=2, got %s", l.GetState("cc", "a"))
+	}
+
+	// a key inserted in the range read by a following transaction
+	block = l.NewBlock()
+	tx1 = block.Invoke("cc", args("put", "b", "1")...)
+	tx2 = block.Invoke("cc", args("count", "a", "c")...)
+	block.Commit()
+	if tx1.ValidationCode != pb.TxValidationCode_VALID || tx2.ValidationCode != pb.TxValidationCode_PHANTOM_READ_CONFLICT {
+		t.Fatalf("Expected VALID and PHANTOM_READ_CONFLICT, got %s and %s", tx1.ValidationCode, tx2.ValidationCode)
+	}
+	if l.GetState("cc", "count") != nil {
+		t.Fatalf("Expected the writes of the invalid transaction to be discarded")
+	}
+
+	// a block committed in between invalidates the transactions simulated before it
+	block = l.NewBlock()
+	tx1 = block.Invoke("cc", args("incr", "a")...)
+	l.Invoke("cc", args("put", "a", "10")...)
+	block.Commit()
+	if tx1.ValidationCode != pb.TxValidationCode_MVCC_READ_CONFLICT {
+		t.Fatalf("Expected MVCC_READ_CONFLICT, got %s", tx1.ValidationCode)
+	}
+}
+
+func TestQueriesAndReadOnlyCalls(t *testing.T) {
+	l := NewLedger("ch1")
+	l.Deploy("cc", testChaincode{})
+	l.Deploy("cc2", testChaincode{})
+	l.Invoke("cc", args("put", "m1", `{"color":"blue","size":3}`)...)
+	l.Invoke("cc", args("put", "m2", `{"color":"red","size":5}`)...)
+	l.Invoke("cc", args("put", "m3", `{"color":"blue","size":8}`)...)
+
+	tx := l.Invoke("cc", args("query", `{"selector":{"color":"blue","size":{"$gt":4}}}`)...)
+	if string(tx.Response.Payload) != "m3" {
+		t.Fatalf("Expected m3, got %s (%s)", tx.Response.Payload, tx.Response.Message)
+	}
+
+	// the queried chaincode cannot write
+	tx = l.Invoke("cc", args("querycc", "cc2", "put", "k", "v")...)
+	if tx.Response.Status != shim.ERROR || l.GetState("cc2", "k") != nil {
+		t.Fatalf("Expected the queried chaincode not to write, got %v", tx.Response)
+	}
+
+	// a transaction performing paginated queries cannot write
+	tx = l.Invoke("cc", args("paginate", "k", "v")...)
+	if tx.Response.Status != shim.ERROR || l.GetState("cc", "k") != nil {
+		t.Fatalf("Expected the paginated transaction not to write, got %v", tx.Response)
+	}
+}
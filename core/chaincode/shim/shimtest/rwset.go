@@ -0,0 +1,219 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shimtest
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// transaction holds what the chaincodes invoked by a transaction can see of
+// its proposal, and what they read and write
+type transaction struct {
+	ledger         *Ledger
+	result         *TxResult
+	rwset          *txRWSet
+	signedProposal *pb.SignedProposal
+	creator        []byte
+	transient      map[string][]byte
+	binding        []byte
+	timestamp      *timestamp.Timestamp
+}
+
+// txRWSet is the read-write set of a transaction, validated at commit time
+// as the ledger of a peer does
+type txRWSet struct {
+	// versions of the keys read, nil for keys that did not exist
+	reads        map[stateKey]*height
+	rangeQueries []*rangeQuery
+	writes       map[stateKey]*write
+	// key-level endorsement policies written, empty to remove them
+	validationParameters map[stateKey][]byte
+	// as on a peer, a transaction that performs paginated queries cannot write
+	paginatedQueries bool
+}
+
+type write struct {
+	value    []byte
+	isDelete bool
+}
+
+// rangeQuery records the keys returned by a range query so that the phantom
+// reads are detected when the transaction is validated
+type rangeQuery struct {
+	namespace string
+	startKey  string
+	endKey    string
+	// true if the chaincode iterated until the end of the range
+	exhausted bool
+	reads     []*keyVersion
+}
+
+type keyVersion struct {
+	key     string
+	version *height
+}
+
+func newTransaction(l *Ledger, name string, args [][]byte) *transaction {
+	cis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{
+		Type:        pb.ChaincodeSpec_GOLANG,
+		ChaincodeId: &pb.ChaincodeID{Name: name},
+		Input:       &pb.ChaincodeInput{Args: args},
+	}}
+	prop, txid, err := utils.CreateChaincodeProposalWithTransient(common.HeaderType_ENDORSER_TRANSACTION, l.ChannelID, cis, l.Creator, l.Transient)
+	if err != nil {
+		panic(fmt.Sprintf("Failed creating the proposal of the transaction: %s", err))
+	}
+
+	// the timestamp of the transaction is the one of its channel header
+	now := l.Clock()
+	ts := &timestamp.Timestamp{Seconds: now.Unix(), Nanos: int32(now.Nanosecond())}
+	hdr, err := utils.GetHeader(prop.Header)
+	if err != nil {
+		panic(fmt.Sprintf("Failed unmarshalling the header of the proposal: %s", err))
+	}
+	chdr, err := utils.UnmarshalChannelHeader(hdr.ChannelHeader)
+	if err != nil {
+		panic(fmt.Sprintf("Failed unmarshalling the channel header of the proposal: %s", err))
+	}
+	chdr.Timestamp = ts
+	hdr.ChannelHeader = utils.MarshalOrPanic(chdr)
+	prop.Header = utils.MarshalOrPanic(hdr)
+
+	binding, err := utils.ComputeProposalBinding(prop)
+	if err != nil {
+		panic(fmt.Sprintf("Failed computing the binding of the proposal: %s", err))
+	}
+
+	rwset := &txRWSet{
+		reads:                make(map[stateKey]*height),
+		writes:               make(map[stateKey]*write),
+		validationParameters: make(map[stateKey][]byte),
+	}
+	return &transaction{
+		ledger:         l,
+		result:         &TxResult{TxID: txid, rwset: rwset, timestamp: ts},
+		rwset:          rwset,
+		signedProposal: &pb.SignedProposal{ProposalBytes: utils.MarshalOrPanic(prop)},
+		creator:        l.Creator,
+		transient:      l.Transient,
+		binding:        binding,
+		timestamp:      ts,
+	}
+}
+
+func (s *txRWSet) addRead(key stateKey, version *height) {
+	s.reads[key] = version
+}
+
+// validate checks that the reads of the transaction are still the committed
+// ones, including the keys returned by its range queries
+func (s *txRWSet) validate(l *Ledger) pb.TxValidationCode {
+	for key, version := range s.reads {
+		if !sameVersion(version, l.getVersion(key)) {
+			return pb.TxValidationCode_MVCC_READ_CONFLICT
+		}
+	}
+	for _, rq := range s.rangeQueries {
+		if !rq.validate(l) {
+			return pb.TxValidationCode_PHANTOM_READ_CONFLICT
+		}
+	}
+	return pb.TxValidationCode_VALID
+}
+
+// validate executes the range query again: the keys in the range, up to the
+// last key read if the chaincode did not iterate until the end, and their
+// versions must be the ones read
+func (rq *rangeQuery) validate(l *Ledger) bool {
+	keys := l.getSortedKeys(rq.namespace, rq.startKey, rq.endKey)
+	if !rq.exhausted {
+		if len(rq.reads) == 0 {
+			return true
+		}
+		lastKey := rq.reads[len(rq.reads)-1].key
+		for i, key := range keys {
+			if key > lastKey {
+				keys = keys[:i]
+				break
+			}
+		}
+	}
+	if len(keys) != len(rq.reads) {
+		return false
+	}
+	for i, key := range keys {
+		read := rq.reads[i]
+		if key != read.key || !sameVersion(read.version, l.getVersion(stateKey{namespace: rq.namespace, key: key})) {
+			return false
+		}
+	}
+	return true
+}
+
+// apply writes the updates of a valid transaction to the state and records
+// the history of the public keys. A write keeps the key-level endorsement
+// policy of the key while a delete removes it, and a policy write applies to
+// an existing key only
+func (s *txRWSet) apply(l *Ledger, tx *TxResult, version *height) {
+	for key, w := range s.writes {
+		if w.isDelete {
+			delete(l.state, key)
+		} else {
+			vv := &versionedValue{value: w.value, version: version}
+			if committed := l.state[key]; committed != nil {
+				vv.validationParameter = committed.validationParameter
+			}
+			l.state[key] = vv
+		}
+		if key.collection == "" {
+			l.history[key] = append(l.history[key], &historyEntry{
+				blockNum: version.blockNum,
+				modification: &queryresult.KeyModification{
+					TxId:      tx.TxID,
+					Value:     w.value,
+					Timestamp: tx.timestamp,
+					IsDelete:  w.isDelete,
+				},
+			})
+		}
+	}
+	for key, validationParameter := range s.validationParameters {
+		committed := l.state[key]
+		if committed == nil {
+			continue
+		}
+		vv := &versionedValue{value: committed.value, version: version}
+		if len(validationParameter) != 0 {
+			vv.validationParameter = validationParameter
+		}
+		l.state[key] = vv
+	}
+}
+
+func sameVersion(h1, h2 *height) bool {
+	if h1 == nil || h2 == nil {
+		return h1 == h2
+	}
+	return *h1 == *h2
+}
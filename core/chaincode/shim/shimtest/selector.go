@@ -0,0 +1,638 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shimtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// query is a Mango query as evaluated by CouchDB: the documents queried are
+// the JSON values of the state, whose _id field is their key. The strings
+// are compared by their bytes rather than with the ICU collation of CouchDB
+type query struct {
+	selector selector
+	fields   []string
+	sort     []sortField
+	limit    int
+	skip     int
+}
+
+type sortField struct {
+	path []string
+	desc bool
+}
+
+// document is a JSON value of the state; the values that are not JSON
+// objects are not documents and are never returned by rich queries
+type document struct {
+	key    string
+	value  []byte
+	fields map[string]interface{}
+}
+
+func newDocument(key string, value []byte) (*document, bool) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(value, &fields); err != nil || fields == nil {
+		return nil, false
+	}
+	fields["_id"] = key
+	return &document{key: key, value: value, fields: fields}, true
+}
+
+func parseQuery(queryString string) (*query, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(queryString), &raw); err != nil {
+		return nil, fmt.Errorf("invalid query [%s]: %s", queryString, err)
+	}
+	rawSelector, ok := raw["selector"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid query [%s]: a selector object is required", queryString)
+	}
+
+	q := &query{}
+	var err error
+	if q.selector, err = parseSelector(rawSelector); err != nil {
+		return nil, err
+	}
+	if rawFields, ok := raw["fields"]; ok {
+		fields, ok := rawFields.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid fields %v: an array is expected", rawFields)
+		}
+		for _, field := range fields {
+			name, ok := field.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid field %v: a string is expected", field)
+			}
+			q.fields = append(q.fields, name)
+		}
+	}
+	if rawSort, ok := raw["sort"]; ok {
+		if q.sort, err = parseSort(rawSort); err != nil {
+			return nil, err
+		}
+	}
+	if q.limit, err = parseCount(raw, "limit"); err != nil {
+		return nil, err
+	}
+	if q.skip, err = parseCount(raw, "skip"); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func parseSort(rawSort interface{}) ([]sortField, error) {
+	fields, ok := rawSort.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid sort %v: an array is expected", rawSort)
+	}
+	var sortFields []sortField
+	for _, field := range fields {
+		switch f := field.(type) {
+		case string:
+			sortFields = append(sortFields, sortField{path: strings.Split(f, ".")})
+		case map[string]interface{}:
+			if len(f) != 1 {
+				return nil, fmt.Errorf("invalid sort field %v: a single field is expected", f)
+			}
+			for name, direction := range f {
+				if direction != "asc" && direction != "desc" {
+					return nil, fmt.Errorf("invalid sort direction %v of field %s", direction, name)
+				}
+				sortFields = append(sortFields, sortField{path: strings.Split(name, "."), desc: direction == "desc"})
+			}
+		default:
+			return nil, fmt.Errorf("invalid sort field %v", field)
+		}
+	}
+	return sortFields, nil
+}
+
+func parseCount(raw map[string]interface{}, name string) (int, error) {
+	rawCount, ok := raw[name]
+	if !ok {
+		return 0, nil
+	}
+	count, ok := rawCount.(float64)
+	if !ok || count < 0 || count != math.Trunc(count) {
+		return 0, fmt.Errorf("invalid %s %v: a positive integer is expected", name, rawCount)
+	}
+	return int(count), nil
+}
+
+// apply sorts the documents matching the selector, which are in the order
+// of their keys, and applies skip and limit
+func (q *query) apply(docs []*document) []*document {
+	if len(q.sort) != 0 {
+		sort.Stable(&documentSorter{docs: docs, fields: q.sort})
+	}
+	if q.skip >= len(docs) {
+		return nil
+	}
+	docs = docs[q.skip:]
+	if q.limit > 0 && len(docs) > q.limit {
+		docs = docs[:q.limit]
+	}
+	return docs
+}
+
+// project returns the value of the document restricted to the fields of the query
+func (q *query) project(doc *document) ([]byte, error) {
+	if len(q.fields) == 0 {
+		return doc.value, nil
+	}
+	projection := make(map[string]interface{})
+	for _, field := range q.fields {
+		path := strings.Split(field, ".")
+		value, exists := lookup(doc.fields, path)
+		if !exists || field == "_id" {
+			continue
+		}
+		target := projection
+		for _, name := range path[:len(path)-1] {
+			next, ok := target[name].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				target[name] = next
+			}
+			target = next
+		}
+		target[path[len(path)-1]] = value
+	}
+	return json.Marshal(projection)
+}
+
+type documentSorter struct {
+	docs   []*document
+	fields []sortField
+}
+
+func (s *documentSorter) Len() int {
+	return len(s.docs)
+}
+
+func (s *documentSorter) Swap(i, j int) {
+	s.docs[i], s.docs[j] = s.docs[j], s.docs[i]
+}
+
+func (s *documentSorter) Less(i, j int) bool {
+	for _, field := range s.fields {
+		vi, existsi := lookup(s.docs[i].fields, field.path)
+		vj, existsj := lookup(s.docs[j].fields, field.path)
+		var c int
+		switch {
+		case existsi && existsj:
+			c = collate(vi, vj)
+		case existsi:
+			c = 1
+		case existsj:
+			c = -1
+		}
+		if c != 0 {
+			return (c < 0) != field.desc
+		}
+	}
+	return false
+}
+
+// selector is a conjunction of clauses on a document
+type selector []clause
+
+type clause interface {
+	matches(doc interface{}) bool
+}
+
+func (sel selector) matches(doc interface{}) bool {
+	for _, c := range sel {
+		if !c.matches(doc) {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldClause holds if the condition holds for the field at path
+type fieldClause struct {
+	path []string
+	cond condition
+}
+
+func (c *fieldClause) matches(doc interface{}) bool {
+	value, exists := lookup(doc, c.path)
+	return c.cond.matches(value, exists)
+}
+
+// combinationClause is a $and, $or or $nor of selectors
+type combinationClause struct {
+	operator  string
+	selectors []selector
+}
+
+func (c *combinationClause) matches(doc interface{}) bool {
+	for _, sel := range c.selectors {
+		m := sel.matches(doc)
+		if c.operator == "$and" && !m {
+			return false
+		}
+		if c.operator == "$or" && m {
+			return true
+		}
+		if c.operator == "$nor" && m {
+			return false
+		}
+	}
+	return c.operator != "$or"
+}
+
+// notClause is a $not of a selector
+type notClause struct {
+	sel selector
+}
+
+func (c *notClause) matches(doc interface{}) bool {
+	return !c.sel.matches(doc)
+}
+
+func parseSelector(raw map[string]interface{}) (selector, error) {
+	var sel selector
+	for _, name := range sortedNames(raw) {
+		value := raw[name]
+		switch {
+		case name == "$and" || name == "$or" || name == "$nor":
+			rawSelectors, ok := value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid %s %v: an array is expected", name, value)
+			}
+			combination := &combinationClause{operator: name}
+			for _, rawSelector := range rawSelectors {
+				obj, ok := rawSelector.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("invalid %s selector %v: an object is expected", name, rawSelector)
+				}
+				s, err := parseSelector(obj)
+				if err != nil {
+					return nil, err
+				}
+				combination.selectors = append(combination.selectors, s)
+			}
+			sel = append(sel, combination)
+		case name == "$not":
+			obj, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid $not %v: an object is expected", value)
+			}
+			s, err := parseSelector(obj)
+			if err != nil {
+				return nil, err
+			}
+			sel = append(sel, &notClause{sel: s})
+		case strings.HasPrefix(name, "$"):
+			return nil, fmt.Errorf("unsupported operator %s in selector", name)
+		default:
+			cond, err := parseCondition(value)
+			if err != nil {
+				return nil, err
+			}
+			sel = append(sel, &fieldClause{path: strings.Split(name, "."), cond: cond})
+		}
+	}
+	return sel, nil
+}
+
+// condition holds or not for the value of a field, exists being false if the
+// document has no such field
+type condition interface {
+	matches(value interface{}, exists bool) bool
+}
+
+type conditionFunc func(value interface{}, exists bool) bool
+
+func (f conditionFunc) matches(value interface{}, exists bool) bool {
+	return f(value, exists)
+}
+
+// existing returns a condition that holds for the fields that exist and satisfy f
+func existing(f func(value interface{}) bool) condition {
+	return conditionFunc(func(value interface{}, exists bool) bool {
+		return exists && f(value)
+	})
+}
+
+// conjunction holds if all its conditions hold
+type conjunction []condition
+
+func (c conjunction) matches(value interface{}, exists bool) bool {
+	for _, cond := range c {
+		if !cond.matches(value, exists) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseCondition parses the condition on a field: an object of operators
+// and of subfields, or a value the field is equal to
+func parseCondition(raw interface{}) (condition, error) {
+	obj, ok := raw.(map[string]interface{})
+	if !ok {
+		return existing(func(value interface{}) bool { return equal(value, raw) }), nil
+	}
+	var conds conjunction
+	for _, name := range sortedNames(obj) {
+		if !strings.HasPrefix(name, "$") {
+			sub, err := parseSelector(map[string]interface{}{name: obj[name]})
+			if err != nil {
+				return nil, err
+			}
+			conds = append(conds, existing(func(value interface{}) bool { return sub.matches(value) }))
+			continue
+		}
+		cond, err := parseOperator(name, obj[name])
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, cond)
+	}
+	return conds, nil
+}
+
+func parseOperator(operator string, operand interface{}) (condition, error) {
+	switch operator {
+	case "$eq":
+		return existing(func(value interface{}) bool { return equal(value, operand) }), nil
+	case "$ne":
+		return existing(func(value interface{}) bool { return !equal(value, operand) }), nil
+	case "$gt":
+		return existing(func(value interface{}) bool { return collate(value, operand) > 0 }), nil
+	case "$gte":
+		return existing(func(value interface{}) bool { return collate(value, operand) >= 0 }), nil
+	case "$lt":
+		return existing(func(value interface{}) bool { return collate(value, operand) < 0 }), nil
+	case "$lte":
+		return existing(func(value interface{}) bool { return collate(value, operand) <= 0 }), nil
+	case "$exists":
+		expected, ok := operand.(bool)
+		if !ok {
+			return nil, fmt.Errorf("invalid $exists %v: a boolean is expected", operand)
+		}
+		return conditionFunc(func(value interface{}, exists bool) bool { return exists == expected }), nil
+	case "$type":
+		expected, ok := operand.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid $type %v: a string is expected", operand)
+		}
+		return existing(func(value interface{}) bool { return typeName(value) == expected }), nil
+	case "$in", "$nin":
+		values, ok := operand.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid %s %v: an array is expected", operator, operand)
+		}
+		in := operator == "$in"
+		return existing(func(value interface{}) bool { return contains(values, value) == in }), nil
+	case "$all":
+		values, ok := operand.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid $all %v: an array is expected", operand)
+		}
+		return existing(func(value interface{}) bool {
+			elements, ok := value.([]interface{})
+			if !ok {
+				return false
+			}
+			for _, v := range values {
+				if !contains(elements, v) {
+					return false
+				}
+			}
+			return true
+		}), nil
+	case "$size":
+		size, ok := operand.(float64)
+		if !ok {
+			return nil, fmt.Errorf("invalid $size %v: a number is expected", operand)
+		}
+		return existing(func(value interface{}) bool {
+			elements, ok := value.([]interface{})
+			return ok && float64(len(elements)) == size
+		}), nil
+	case "$mod":
+		args, ok := operand.([]interface{})
+		if !ok || len(args) != 2 {
+			return nil, fmt.Errorf("invalid $mod %v: [divisor, remainder] is expected", operand)
+		}
+		divisor, ok1 := args[0].(float64)
+		remainder, ok2 := args[1].(float64)
+		if !ok1 || !ok2 || divisor == 0 || divisor != math.Trunc(divisor) || remainder != math.Trunc(remainder) {
+			return nil, fmt.Errorf("invalid $mod %v: integers are expected", operand)
+		}
+		return existing(func(value interface{}) bool {
+			n, ok := value.(float64)
+			return ok && n == math.Trunc(n) && int64(n)%int64(divisor) == int64(remainder)
+		}), nil
+	case "$regex":
+		pattern, ok := operand.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid $regex %v: a string is expected", operand)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid $regex %s: %s", pattern, err)
+		}
+		return existing(func(value interface{}) bool {
+			s, ok := value.(string)
+			return ok && re.MatchString(s)
+		}), nil
+	case "$elemMatch", "$allMatch":
+		cond, err := parseCondition(operand)
+		if err != nil {
+			return nil, err
+		}
+		all := operator == "$allMatch"
+		return existing(func(value interface{}) bool {
+			elements, ok := value.([]interface{})
+			if !ok || (all && len(elements) == 0) {
+				return false
+			}
+			for _, element := range elements {
+				if cond.matches(element, true) != all {
+					return !all
+				}
+			}
+			return all
+		}), nil
+	case "$not":
+		cond, err := parseCondition(operand)
+		if err != nil {
+			return nil, err
+		}
+		return conditionFunc(func(value interface{}, exists bool) bool { return !cond.matches(value, exists) }), nil
+	case "$and", "$or", "$nor":
+		rawConds, ok := operand.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid %s %v: an array is expected", operator, operand)
+		}
+		var conds []condition
+		for _, rawCond := range rawConds {
+			cond, err := parseCondition(rawCond)
+			if err != nil {
+				return nil, err
+			}
+			conds = append(conds, cond)
+		}
+		return conditionFunc(func(value interface{}, exists bool) bool {
+			for _, cond := range conds {
+				m := cond.matches(value, exists)
+				if operator == "$and" && !m {
+					return false
+				}
+				if operator == "$or" && m {
+					return true
+				}
+				if operator == "$nor" && m {
+					return false
+				}
+			}
+			return operator != "$or"
+		}), nil
+	}
+	return nil, fmt.Errorf("unsupported operator %s", operator)
+}
+
+// lookup returns the field of the document at path
+func lookup(doc interface{}, path []string) (interface{}, bool) {
+	value := doc
+	for _, name := range path {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = obj[name]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func contains(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if equal(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func equal(v1, v2 interface{}) bool {
+	return reflect.DeepEqual(v1, v2)
+}
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	}
+	return "object"
+}
+
+// typeRank orders the JSON types as the CouchDB collation does
+func typeRank(value interface{}) int {
+	switch value.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case float64:
+		return 2
+	case string:
+		return 3
+	case []interface{}:
+		return 4
+	}
+	return 5
+}
+
+// collate compares two JSON values following the CouchDB collation:
+// null < booleans < numbers < strings < arrays < objects
+func collate(v1, v2 interface{}) int {
+	if r1, r2 := typeRank(v1), typeRank(v2); r1 != r2 {
+		return r1 - r2
+	}
+	switch x1 := v1.(type) {
+	case bool:
+		x2 := v2.(bool)
+		if x1 == x2 {
+			return 0
+		} else if x1 {
+			return 1
+		}
+		return -1
+	case float64:
+		x2 := v2.(float64)
+		if x1 < x2 {
+			return -1
+		} else if x1 > x2 {
+			return 1
+		}
+		return 0
+	case string:
+		return strings.Compare(x1, v2.(string))
+	case []interface{}:
+		x2 := v2.([]interface{})
+		for i := 0; i < len(x1) && i < len(x2); i++ {
+			if c := collate(x1[i], x2[i]); c != 0 {
+				return c
+			}
+		}
+		return len(x1) - len(x2)
+	case map[string]interface{}:
+		x2 := v2.(map[string]interface{})
+		names1, names2 := sortedNames(x1), sortedNames(x2)
+		for i := 0; i < len(names1) && i < len(names2); i++ {
+			if c := strings.Compare(names1[i], names2[i]); c != 0 {
+				return c
+			}
+			if c := collate(x1[names1[i]], x2[names2[i]]); c != 0 {
+				return c
+			}
+		}
+		return len(names1) - len(names2)
+	}
+	return 0
+}
+
+func sortedNames(obj map[string]interface{}) []string {
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,96 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shimtest
+
+import (
+	"testing"
+)
+
+func TestSelector(t *testing.T) {
+	doc, _ := newDocument("m1", []byte(`{"color":"blue","size":3,"owner":{"name":"tom"},"tags":["a","b"]}`))
+	for _, tc := range []struct {
+		selector string
+		matches  bool
+	}{
+		{`{"color":"blue"}`, true},
+		{`{"_id":"m1"}`, true},
+		{`{"color":{"$ne":"blue"}}`, false},
+		{`{"size":{"$gte":3,"$lt":4}}`, true},
+		{`{"size":{"$gt":"a"}}`, false},
+		{`{"owner.name":"tom"}`, true},
+		{`{"owner":{"name":"tom"}}`, true},
+		{`{"weight":{"$exists":false}}`, true},
+		{`{"weight":{"$ne":1}}`, false},
+		{`{"color":{"$in":["red","blue"]}}`, true},
+		{`{"color":{"$nin":["red","blue"]}}`, false},
+		{`{"color":{"$regex":"^bl"}}`, true},
+		{`{"tags":{"$size":2}}`, true},
+		{`{"tags":{"$all":["b","a"]}}`, true},
+		{`{"tags":{"$elemMatch":{"$eq":"b"}}}`, true},
+		{`{"size":{"$mod":[2,1]}}`, true},
+		{`{"size":{"$type":"number"}}`, true},
+		{`{"$or":[{"color":"red"},{"size":3}]}`, true},
+		{`{"$nor":[{"color":"red"},{"size":3}]}`, false},
+		{`{"$not":{"color":"red"}}`, true},
+		{`{"size":{"$not":{"$gt":2}}}`, false},
+	} {
+		q, err := parseQuery(`{"selector":` + tc.selector + `}`)
+		if err != nil {
+			t.Fatalf("Failed parsing %s: %s", tc.selector, err)
+		}
+		if q.selector.matches(doc.fields) != tc.matches {
+			t.Fatalf("Expected %s to match: %t", tc.selector, tc.matches)
+		}
+	}
+
+	if _, err := parseQuery(`{"selector":{"size":{"$unknown":1}}}`); err == nil {
+		t.Fatal("Expected an unknown operator to be rejected")
+	}
+	if _, err := parseQuery(`{"fields":["size"]}`); err == nil {
+		t.Fatal("Expected a query without selector to be rejected")
+	}
+}
+
+func TestQuerySortAndProjection(t *testing.T) {
+	var docs []*document
+	for _, kv := range [][]string{
+		{"m1", `{"color":"blue","size":3}`},
+		{"m2", `{"color":"red","size":8}`},
+		{"m3", `{"color":"green","size":5}`},
+		{"m4", "not json"},
+	} {
+		if doc, ok := newDocument(kv[0], []byte(kv[1])); ok {
+			docs = append(docs, doc)
+		}
+	}
+	if len(docs) != 3 {
+		t.Fatalf("Expected the value that is not a JSON object to be skipped, got %d documents", len(docs))
+	}
+
+	q, err := parseQuery(`{"selector":{"size":{"$gt":0}},"sort":[{"size":"desc"}],"skip":1,"limit":1,"fields":["color"]}`)
+	if err != nil {
+		t.Fatalf("Failed parsing the query: %s", err)
+	}
+	docs = q.apply(docs)
+	if len(docs) != 1 || docs[0].key != "m3" {
+		t.Fatalf("Expected m3, got %v", docs)
+	}
+	value, err := q.project(docs[0])
+	if err != nil || string(value) != `{"color":"green"}` {
+		t.Fatalf("Expected the color of m3, got %s (err: %v)", value, err)
+	}
+}
@@ -0,0 +1,493 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shimtest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+const (
+	minUnicodeRuneValue = 0            //U+0000
+	maxUnicodeRuneValue = utf8.MaxRune //U+10FFFF - maximum (and unallocated) code point
+)
+
+// stub is the ChaincodeStubInterface given to a chaincode invoked by a
+// transaction on a Ledger
+type stub struct {
+	tx        *transaction
+	namespace string
+	args      [][]byte
+	// set for the chaincodes called through QueryChaincode
+	readOnly bool
+	events   []*pb.ChaincodeEvent
+}
+
+var _ shim.ChaincodeStubInterface = &stub{}
+
+func newStub(tx *transaction, namespace string, args [][]byte, readOnly bool) *stub {
+	return &stub{tx: tx, namespace: namespace, args: args, readOnly: readOnly}
+}
+
+// GetArgs documentation can be found in interfaces.go
+func (s *stub) GetArgs() [][]byte {
+	return s.args
+}
+
+// GetStringArgs documentation can be found in interfaces.go
+func (s *stub) GetStringArgs() []string {
+	strargs := make([]string, 0, len(s.args))
+	for _, barg := range s.args {
+		strargs = append(strargs, string(barg))
+	}
+	return strargs
+}
+
+// GetFunctionAndParameters documentation can be found in interfaces.go
+func (s *stub) GetFunctionAndParameters() (function string, params []string) {
+	allargs := s.GetStringArgs()
+	params = []string{}
+	if len(allargs) >= 1 {
+		function = allargs[0]
+		params = allargs[1:]
+	}
+	return
+}
+
+// GetTxID documentation can be found in interfaces.go
+func (s *stub) GetTxID() string {
+	return s.tx.result.TxID
+}
+
+// InvokeChaincode calls a chaincode deployed on the same ledger within the
+// transaction. Only the channel of the ledger can be called
+func (s *stub) InvokeChaincode(chaincodeName string, args [][]byte, channel string) pb.Response {
+	return s.callChaincode(chaincodeName, args, channel, s.readOnly)
+}
+
+// QueryChaincode calls read-only a chaincode deployed on the same ledger
+// within the transaction. Only the channel of the ledger can be called
+func (s *stub) QueryChaincode(chaincodeName string, args [][]byte, channel string) pb.Response {
+	return s.callChaincode(chaincodeName, args, channel, true)
+}
+
+func (s *stub) callChaincode(chaincodeName string, args [][]byte, channel string, readOnly bool) pb.Response {
+	if channel != "" && channel != s.tx.ledger.ChannelID {
+		return shim.Error(fmt.Sprintf("Cannot call chaincode %s on channel %s: the ledger is the one of channel %s",
+			chaincodeName, channel, s.tx.ledger.ChannelID))
+	}
+	cc, ok := s.tx.ledger.chaincodes[chaincodeName]
+	if !ok {
+		return shim.Error(fmt.Sprintf("Chaincode %s is not deployed", chaincodeName))
+	}
+	return cc.Invoke(newStub(s.tx, chaincodeName, args, readOnly))
+}
+
+// GetState documentation can be found in interfaces.go
+func (s *stub) GetState(key string) ([]byte, error) {
+	return s.getValue(stateKey{namespace: s.namespace, key: key}), nil
+}
+
+// PutState documentation can be found in interfaces.go
+func (s *stub) PutState(key string, value []byte) error {
+	return s.putValue(stateKey{namespace: s.namespace, key: key}, &write{value: value})
+}
+
+// DelState documentation can be found in interfaces.go
+func (s *stub) DelState(key string) error {
+	return s.putValue(stateKey{namespace: s.namespace, key: key}, &write{isDelete: true})
+}
+
+// PutStateStream documentation can be found in interfaces.go. The value is
+// written as with PutState when the writer is closed: the harness has no blob
+// store, so GetState returns the value itself rather than a reference to it
+func (s *stub) PutStateStream(key string) (io.WriteCloser, error) {
+	if key == "" {
+		return nil, errors.New("key must not be an empty string")
+	}
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
+	return &valueWriter{put: func(value []byte) error { return s.PutState(key, value) }}, nil
+}
+
+// GetStateStream documentation can be found in interfaces.go
+func (s *stub) GetStateStream(key string) (io.Reader, error) {
+	value, err := s.GetState(key)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return bytes.NewReader(value), nil
+}
+
+// valueWriter buffers a streamed value until it is closed
+type valueWriter struct {
+	buf    bytes.Buffer
+	put    func([]byte) error
+	closed bool
+}
+
+func (w *valueWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errors.New("write to a closed state stream")
+	}
+	return w.buf.Write(p)
+}
+
+func (w *valueWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.put(w.buf.Bytes())
+}
+
+// SetStateValidationParameter documentation can be found in interfaces.go
+func (s *stub) SetStateValidationParameter(key string, ep []byte) error {
+	if key == "" {
+		return errors.New("key must not be an empty string")
+	}
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+	s.tx.rwset.validationParameters[stateKey{namespace: s.namespace, key: key}] = ep
+	return nil
+}
+
+// GetStateValidationParameter documentation can be found in interfaces.go
+func (s *stub) GetStateValidationParameter(key string) ([]byte, error) {
+	if key == "" {
+		return nil, errors.New("key must not be an empty string")
+	}
+	k := stateKey{namespace: s.namespace, key: key}
+	s.tx.rwset.addRead(k, s.tx.ledger.getVersion(k))
+	return s.tx.ledger.GetStateValidationParameter(s.namespace, key), nil
+}
+
+// GetPrivateData documentation can be found in interfaces.go
+func (s *stub) GetPrivateData(collection, key string) ([]byte, error) {
+	if collection == "" {
+		return nil, errors.New("collection must not be an empty string")
+	}
+	return s.getValue(stateKey{namespace: s.namespace, collection: collection, key: key}), nil
+}
+
+// PutPrivateData documentation can be found in interfaces.go
+func (s *stub) PutPrivateData(collection string, key string, value []byte) error {
+	if collection == "" {
+		return errors.New("collection must not be an empty string")
+	}
+	return s.putValue(stateKey{namespace: s.namespace, collection: collection, key: key}, &write{value: value})
+}
+
+// DelPrivateData documentation can be found in interfaces.go
+func (s *stub) DelPrivateData(collection, key string) error {
+	if collection == "" {
+		return errors.New("collection must not be an empty string")
+	}
+	return s.putValue(stateKey{namespace: s.namespace, collection: collection, key: key}, &write{isDelete: true})
+}
+
+// getValue returns the committed value of the key, which is added to the
+// read set: as on a peer, a transaction does not read its own writes
+func (s *stub) getValue(key stateKey) []byte {
+	s.tx.rwset.addRead(key, s.tx.ledger.getVersion(key))
+	return s.tx.ledger.getValue(key)
+}
+
+func (s *stub) putValue(key stateKey, w *write) error {
+	if key.key == "" {
+		return errors.New("key must not be an empty string")
+	}
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+	s.tx.rwset.writes[key] = w
+	return nil
+}
+
+func (s *stub) checkWritable() error {
+	if s.readOnly {
+		return fmt.Errorf("chaincode %s cannot write: it was called read-only", s.namespace)
+	}
+	if s.tx.rwset.paginatedQueries {
+		return fmt.Errorf("Transaction [%s] has performed paginated queries, writes are not allowed", s.GetTxID())
+	}
+	return nil
+}
+
+func (s *stub) startPaginatedQuery() error {
+	if len(s.tx.rwset.writes) != 0 || len(s.tx.rwset.validationParameters) != 0 {
+		return fmt.Errorf("Transaction [%s] has performed writes, paginated queries are not allowed", s.GetTxID())
+	}
+	s.tx.rwset.paginatedQueries = true
+	return nil
+}
+
+// GetStateByRange documentation can be found in interfaces.go
+func (s *stub) GetStateByRange(startKey, endKey string) (shim.StateQueryIteratorInterface, error) {
+	keys := s.tx.ledger.getSortedKeys(s.namespace, startKey, endKey)
+	return s.rangeQuery(startKey, endKey, keys, true), nil
+}
+
+// GetStateByRangeWithPagination documentation can be found in interfaces.go
+func (s *stub) GetStateByRangeWithPagination(startKey, endKey string, pageSize int32,
+	bookmark string) (shim.StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	if err := s.startPaginatedQuery(); err != nil {
+		return nil, nil, err
+	}
+	if bookmark != "" {
+		startKey = bookmark
+	}
+	keys := s.tx.ledger.getSortedKeys(s.namespace, startKey, endKey)
+	// the bookmark is the first key of the next page
+	nextBookmark := ""
+	if pageSize > 0 && len(keys) > int(pageSize) {
+		nextBookmark = keys[pageSize]
+		keys = keys[:pageSize]
+	}
+	metadata := &pb.QueryResponseMetadata{FetchedRecordsCount: int32(len(keys)), Bookmark: nextBookmark}
+	return s.rangeQuery(startKey, endKey, keys, nextBookmark == ""), metadata, nil
+}
+
+// rangeQuery records the given keys of the range in the read set of the
+// transaction and returns an iterator over them
+func (s *stub) rangeQuery(startKey, endKey string, keys []string, exhausted bool) shim.StateQueryIteratorInterface {
+	rq := &rangeQuery{namespace: s.namespace, startKey: startKey, endKey: endKey, exhausted: exhausted}
+	results := make([]*queryresult.KV, 0, len(keys))
+	for _, key := range keys {
+		k := stateKey{namespace: s.namespace, key: key}
+		rq.reads = append(rq.reads, &keyVersion{key: key, version: s.tx.ledger.getVersion(k)})
+		results = append(results, &queryresult.KV{Namespace: s.namespace, Key: key, Value: s.tx.ledger.getValue(k)})
+	}
+	s.tx.rwset.rangeQueries = append(s.tx.rwset.rangeQueries, rq)
+	return &stateQueryIterator{results: results}
+}
+
+// GetStateByPartialCompositeKey documentation can be found in interfaces.go
+func (s *stub) GetStateByPartialCompositeKey(objectType string, attributes []string) (shim.StateQueryIteratorInterface, error) {
+	partialCompositeKey, err := s.CreateCompositeKey(objectType, attributes)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetStateByRange(partialCompositeKey, partialCompositeKey+string(rune(maxUnicodeRuneValue)))
+}
+
+// CreateCompositeKey documentation can be found in interfaces.go
+func (s *stub) CreateCompositeKey(objectType string, attributes []string) (string, error) {
+	if err := validateCompositeKeyAttribute(objectType); err != nil {
+		return "", err
+	}
+	ck := objectType + string(rune(minUnicodeRuneValue))
+	for _, att := range attributes {
+		if err := validateCompositeKeyAttribute(att); err != nil {
+			return "", err
+		}
+		ck += att + string(rune(minUnicodeRuneValue))
+	}
+	return ck, nil
+}
+
+// SplitCompositeKey documentation can be found in interfaces.go
+func (s *stub) SplitCompositeKey(compositeKey string) (string, []string, error) {
+	componentIndex := 0
+	components := []string{}
+	for i := 0; i < len(compositeKey); i++ {
+		if compositeKey[i] == minUnicodeRuneValue {
+			components = append(components, compositeKey[componentIndex:i])
+			componentIndex = i + 1
+		}
+	}
+	if len(components) == 0 {
+		return "", nil, fmt.Errorf("%s is not a composite key", compositeKey)
+	}
+	return components[0], components[1:], nil
+}
+
+func validateCompositeKeyAttribute(str string) error {
+	if !utf8.ValidString(str) {
+		return fmt.Errorf("Not a valid utf8 string: [%x]", str)
+	}
+	for index, runeValue := range str {
+		if runeValue == minUnicodeRuneValue || runeValue == maxUnicodeRuneValue {
+			return fmt.Errorf(`Input contain unicode %#U starting at position [%d]. %#U and %#U are not allowed in the input attribute of a composite key`,
+				runeValue, index, minUnicodeRuneValue, maxUnicodeRuneValue)
+		}
+	}
+	return nil
+}
+
+// GetQueryResult evaluates the Mango query against the committed state, as
+// CouchDB does. As on a peer, its results are not validated at commit time
+func (s *stub) GetQueryResult(query string) (shim.StateQueryIteratorInterface, error) {
+	results, err := s.executeQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return &stateQueryIterator{results: results}, nil
+}
+
+// GetQueryResultWithPagination is similar to GetQueryResult; the bookmark
+// is the number of results of the previous pages
+func (s *stub) GetQueryResultWithPagination(query string, pageSize int32,
+	bookmark string) (shim.StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	if err := s.startPaginatedQuery(); err != nil {
+		return nil, nil, err
+	}
+	results, err := s.executeQuery(query)
+	if err != nil {
+		return nil, nil, err
+	}
+	skip := 0
+	if bookmark != "" {
+		if skip, err = strconv.Atoi(bookmark); err != nil || skip < 0 {
+			return nil, nil, fmt.Errorf("invalid bookmark [%s]", bookmark)
+		}
+	}
+	if skip > len(results) {
+		skip = len(results)
+	}
+	results = results[skip:]
+	nextBookmark := ""
+	if pageSize > 0 && len(results) > int(pageSize) {
+		results = results[:pageSize]
+		nextBookmark = strconv.Itoa(skip + int(pageSize))
+	}
+	metadata := &pb.QueryResponseMetadata{FetchedRecordsCount: int32(len(results)), Bookmark: nextBookmark}
+	return &stateQueryIterator{results: results}, metadata, nil
+}
+
+func (s *stub) executeQuery(query string) ([]*queryresult.KV, error) {
+	q, err := parseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	var docs []*document
+	for _, key := range s.tx.ledger.getSortedKeys(s.namespace, "", "") {
+		doc, ok := newDocument(key, s.tx.ledger.GetState(s.namespace, key))
+		if ok && q.selector.matches(doc.fields) {
+			docs = append(docs, doc)
+		}
+	}
+	docs = q.apply(docs)
+
+	results := make([]*queryresult.KV, 0, len(docs))
+	for _, doc := range docs {
+		value, err := q.project(doc)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &queryresult.KV{Namespace: s.namespace, Key: doc.key, Value: value})
+	}
+	return results, nil
+}
+
+// GetHistoryForKey documentation can be found in interfaces.go
+func (s *stub) GetHistoryForKey(key string) (shim.HistoryQueryIteratorInterface, error) {
+	return s.GetHistoryForKeyWithOptions(key, nil)
+}
+
+// GetHistoryForKeyWithOptions documentation can be found in interfaces.go
+func (s *stub) GetHistoryForKeyWithOptions(key string, options *pb.HistoryQueryOptions) (shim.HistoryQueryIteratorInterface, error) {
+	if options == nil {
+		options = &pb.HistoryQueryOptions{}
+	}
+	var results []*queryresult.KeyModification
+	for _, entry := range s.tx.ledger.history[stateKey{namespace: s.namespace, key: key}] {
+		if entry.blockNum < options.StartBlock || (options.HasEndBlock && entry.blockNum > options.EndBlock) {
+			continue
+		}
+		if options.StartTime != nil && timestampBefore(entry.modification.Timestamp, options.StartTime) {
+			continue
+		}
+		if options.EndTime != nil && !timestampBefore(entry.modification.Timestamp, options.EndTime) {
+			continue
+		}
+		results = append(results, entry.modification)
+	}
+	if options.Reverse {
+		for i, j := 0, len(results)-1; i < j; i, j = i+1, j-1 {
+			results[i], results[j] = results[j], results[i]
+		}
+	}
+	if options.Limit > 0 && len(results) > int(options.Limit) {
+		results = results[:options.Limit]
+	}
+	return &historyQueryIterator{results: results}, nil
+}
+
+func timestampBefore(t1, t2 *timestamp.Timestamp) bool {
+	return t1.Seconds < t2.Seconds || (t1.Seconds == t2.Seconds && t1.Nanos < t2.Nanos)
+}
+
+// GetCreator documentation can be found in interfaces.go
+func (s *stub) GetCreator() ([]byte, error) {
+	return s.tx.creator, nil
+}
+
+// GetTransient documentation can be found in interfaces.go
+func (s *stub) GetTransient() (map[string][]byte, error) {
+	return s.tx.transient, nil
+}
+
+// GetBinding documentation can be found in interfaces.go
+func (s *stub) GetBinding() ([]byte, error) {
+	return s.tx.binding, nil
+}
+
+// GetSignedProposal returns the proposal of the transaction, which is not signed
+func (s *stub) GetSignedProposal() (*pb.SignedProposal, error) {
+	return s.tx.signedProposal, nil
+}
+
+// GetArgsSlice documentation can be found in interfaces.go
+func (s *stub) GetArgsSlice() ([]byte, error) {
+	res := []byte{}
+	for _, barg := range s.args {
+		res = append(res, barg...)
+	}
+	return res, nil
+}
+
+// GetTxTimestamp documentation can be found in interfaces.go
+func (s *stub) GetTxTimestamp() (*timestamp.Timestamp, error) {
+	return s.tx.timestamp, nil
+}
+
+// SetEvent documentation can be found in interfaces.go
+func (s *stub) SetEvent(name string, payload []byte) error {
+	if name == "" {
+		return errors.New("Event name can not be nil string.")
+	}
+	s.events = append(s.events, &pb.ChaincodeEvent{
+		ChaincodeId: s.namespace,
+		TxId:        s.GetTxID(),
+		EventName:   name,
+		Payload:     payload,
+	})
+	return nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shim
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// stateChunkSize is the size of the chunks in which a value is streamed to the peer
+const stateChunkSize = 1024 * 1024
+
+// stateWriter streams the value of a key to the peer, one chunk at a time
+type stateWriter struct {
+	handler *Handler
+	key     string
+	txid    string
+	buf     []byte
+	closed  bool
+	err     error
+}
+
+// Write implements method in interface io.Writer. A chunk is sent to the peer each time
+// stateChunkSize bytes are buffered
+func (w *stateWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errors.New("write to a closed state stream")
+	}
+	if w.err != nil {
+		return 0, w.err
+	}
+	n := 0
+	for len(p) > 0 {
+		free := stateChunkSize - len(w.buf)
+		if free > len(p) {
+			free = len(p)
+		}
+		w.buf = append(w.buf, p[:free]...)
+		p = p[free:]
+		n += free
+		if len(w.buf) == stateChunkSize {
+			if w.err = w.handler.handlePutStateChunk(w.key, w.buf, false, w.txid); w.err != nil {
+				return n, w.err
+			}
+			w.buf = w.buf[:0]
+		}
+	}
+	return n, nil
+}
+
+// Close implements method in interface io.Closer. It sends the last chunk of the
+// value, upon which the peer writes the value to the key
+func (w *stateWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.err != nil {
+		return w.err
+	}
+	return w.handler.handlePutStateChunk(w.key, w.buf, true, w.txid)
+}
+
+// stateReader streams the value of a key from the peer, one chunk at a time
+type stateReader struct {
+	handler *Handler
+	key     string
+	txid    string
+	data    []byte
+	next    int64
+	size    int64
+	hash    []byte
+}
+
+// newStateReader returns a reader of the value of a key, or nil if the key does not exist
+func newStateReader(handler *Handler, key string, txid string) (io.Reader, error) {
+	chunk, err := handler.handleGetStateChunk(key, 0, txid)
+	if err != nil || chunk == nil {
+		return nil, err
+	}
+	return &stateReader{handler: handler, key: key, txid: txid,
+		data: chunk.Data, next: int64(len(chunk.Data)), size: chunk.Size, hash: chunk.Hash}, nil
+}
+
+// Read implements method in interface io.Reader. The next chunk is fetched from the peer
+// once the previous one is consumed
+func (r *stateReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		if r.next >= r.size {
+			return 0, io.EOF
+		}
+		chunk, err := r.handler.handleGetStateChunk(r.key, r.next, r.txid)
+		if err != nil {
+			return 0, err
+		}
+		if chunk == nil || len(chunk.Data) == 0 || !bytes.Equal(chunk.Hash, r.hash) {
+			return 0, fmt.Errorf("the value of key %s changed while it was read", r.key)
+		}
+		r.data = chunk.Data
+		r.next += int64(len(chunk.Data))
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincode
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/ledger/blobstore"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// stateChunkSize is the maximum size of the chunks of a value streamed to the chaincode
+const stateChunkSize = 1024 * 1024
+
+// putStateChunk writes a chunk of a value streamed by the chaincode to the blob store, using the
+// writer of the key if the chaincode already streamed a part of its value. The reference to the
+// value, to be written to the key in place of the value, is returned once the last chunk is written
+func putStateChunk(store blobstore.Store, writers map[string]blobstore.Writer, chunk *pb.PutStateChunk) ([]byte, error) {
+	if chunk.Key == "" {
+		return nil, fmt.Errorf("key must not be an empty string")
+	}
+	w, ok := writers[chunk.Key]
+	if !ok {
+		var err error
+		if w, err = store.NewWriter(); err != nil {
+			return nil, err
+		}
+		writers[chunk.Key] = w
+	}
+	if _, err := w.Write(chunk.Data); err != nil {
+		delete(writers, chunk.Key)
+		w.Abort()
+		return nil, err
+	}
+	if !chunk.Last {
+		return nil, nil
+	}
+	delete(writers, chunk.Key)
+	hash, err := w.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return blobstore.Reference(hash), nil
+}
+
+// abortStateWriters discards the values the chaincode did not finish streaming
+func abortStateWriters(writers map[string]blobstore.Writer) {
+	for key, w := range writers {
+		w.Abort()
+		delete(writers, key)
+	}
+}
+
+// getStateChunk returns the chunk starting at offset of a value read from the state. If the value
+// is a reference to a value streamed by a chaincode, the chunk is read from the blob store
+func getStateChunk(store blobstore.Store, value []byte, offset int64) (*pb.StateChunk, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("Invalid offset %d", offset)
+	}
+	hash, isReference := blobstore.ParseReference(value)
+	if !isReference {
+		size := int64(len(value))
+		if offset > size {
+			offset = size
+		}
+		end := offset + stateChunkSize
+		if end > size {
+			end = size
+		}
+		return &pb.StateChunk{Data: value[offset:end], Size: size, Hash: util.ComputeSHA256(value)}, nil
+	}
+
+	if store == nil {
+		return nil, &blobstore.ErrBlobNotFound{Hash: hash}
+	}
+	b, err := store.Open(hash)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+	size := b.Size()
+	if offset > size {
+		offset = size
+	}
+	n := size - offset
+	if n > stateChunkSize {
+		n = stateChunkSize
+	}
+	data := make([]byte, n)
+	if _, err = b.ReadAt(data, offset); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return &pb.StateChunk{Data: data, Size: size, Hash: hash}, nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincode
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger/blobstore"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
+)
+
+func newTestBlobStore(t *testing.T) (blobstore.Store, func()) {
+	dir, err := ioutil.TempDir("", "statestream")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %s", err)
+	}
+	fsPath := viper.Get("peer.fileSystemPath")
+	viper.Set("peer.fileSystemPath", dir)
+	provider := blobstore.NewStoreProvider()
+	store, err := provider.OpenStore("ch1")
+	if err != nil {
+		t.Fatalf("Failed to open blob store: %s", err)
+	}
+	return store, func() {
+		provider.Close()
+		viper.Set("peer.fileSystemPath", fsPath)
+		os.RemoveAll(dir)
+	}
+}
+
+// commitReference commits a block writing the reference to a value streamed by the chaincode,
+// which adds the staged value to the blob store
+func commitReference(t *testing.T, store blobstore.Store, key string, ref []byte) {
+	builder := rwsetutil.NewRWSetBuilder()
+	builder.AddToWriteSet("cc", key, ref)
+	results, err := builder.GetTxReadWriteSet().ToProtoBytes()
+	if err != nil {
+		t.Fatalf("Failed to marshal the read-write set: %s", err)
+	}
+	if err = store.Commit(testutil.ConstructBlock(t, 0, nil, [][]byte{results}, false)); err != nil {
+		t.Fatalf("Failed to commit the reference: %s", err)
+	}
+}
+
+func TestStateStream(t *testing.T) {
+	store, cleanup := newTestBlobStore(t)
+	defer cleanup()
+
+	value := bytes.Repeat([]byte("0123456789"), stateChunkSize/5)
+	writers := make(map[string]blobstore.Writer)
+	ref, err := putStateChunk(store, writers, &pb.PutStateChunk{Key: "key1", Data: value[:stateChunkSize]})
+	if err != nil || ref != nil {
+		t.Fatalf("Expected no reference before the last chunk, got %x (err: %v)", ref, err)
+	}
+	ref, err = putStateChunk(store, writers, &pb.PutStateChunk{Key: "key1", Data: value[stateChunkSize:], Last: true})
+	if err != nil || !blobstore.IsReference(ref) {
+		t.Fatalf("Expected a reference after the last chunk, got %x (err: %v)", ref, err)
+	}
+	if len(writers) != 0 {
+		t.Fatalf("Expected the writer of the key to be released, got %v", writers)
+	}
+
+	// the value can only be read once the transaction writing it is committed
+	if _, err = getStateChunk(store, ref, 0); err == nil {
+		t.Fatal("Expected a value that is not committed to fail")
+	}
+	commitReference(t, store, "key1", ref)
+
+	// the value is read back in chunks from the blob store
+	var read []byte
+	var hash []byte
+	for offset := int64(0); offset < int64(len(value)); {
+		chunk, err := getStateChunk(store, ref, offset)
+		if err != nil {
+			t.Fatalf("Failed to get chunk at offset %d: %s", offset, err)
+		}
+		if chunk.Size != int64(len(value)) || len(chunk.Data) > stateChunkSize {
+			t.Fatalf("Unexpected chunk of size %d of a value of size %d", len(chunk.Data), chunk.Size)
+		}
+		if hash != nil && !bytes.Equal(hash, chunk.Hash) {
+			t.Fatal("Expected the chunks to have the same hash")
+		}
+		hash = chunk.Hash
+		read = append(read, chunk.Data...)
+		offset += int64(len(chunk.Data))
+	}
+	if !bytes.Equal(value, read) {
+		t.Fatal("Expected the value read to be the value written")
+	}
+
+	// a value written with PutState is streamed as well
+	chunk, err := getStateChunk(store, []byte("value"), 2)
+	if err != nil || string(chunk.Data) != "lue" || chunk.Size != 5 {
+		t.Fatalf("Unexpected chunk %v (err: %v)", chunk, err)
+	}
+	if _, err = getStateChunk(store, []byte("value"), -1); err == nil {
+		t.Fatal("Expected a negative offset to fail")
+	}
+}
+
+func TestStateStreamAbort(t *testing.T) {
+	store, cleanup := newTestBlobStore(t)
+	defer cleanup()
+
+	writers := make(map[string]blobstore.Writer)
+	if _, err := putStateChunk(store, writers, &pb.PutStateChunk{Data: []byte("data")}); err == nil {
+		t.Fatal("Expected an empty key to fail")
+	}
+	if _, err := putStateChunk(store, writers, &pb.PutStateChunk{Key: "key1", Data: []byte("data")}); err != nil {
+		t.Fatalf("Failed to put chunk: %s", err)
+	}
+	abortStateWriters(writers)
+	if len(writers) != 0 {
+		t.Fatalf("Expected the writers to be released, got %v", writers)
+	}
+
+	// a reference to a value missing from the blob store cannot be read
+	ref := blobstore.Reference(bytes.Repeat([]byte{1}, 32))
+	if _, err := getStateChunk(store, ref, 0); err == nil {
+		t.Fatal("Expected a missing value to fail")
+	}
+	if _, err := getStateChunk(nil, ref, 0); err == nil {
+		t.Fatal("Expected a missing blob store to fail")
+	}
+}
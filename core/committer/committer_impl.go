@@ -20,8 +20,12 @@ import (
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/committer/txvalidator"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/blobstore"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/transientstore"
 	"github.com/hyperledger/fabric/events/producer"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/op/go-logging"
 )
 
@@ -40,15 +44,28 @@ func init() {
 // it keeps the reference to the ledger to commit blocks and retreive
 // chain information
 type LedgerCommitter struct {
-	ledger    ledger.PeerLedger
-	validator txvalidator.Validator
+	ledger       ledger.PeerLedger
+	validator    txvalidator.Validator
+	pvtDataStore transientstore.Store
+	fetchBlobs   BlobFetcher
 }
 
+// BlobFetcher retrieves from other peers the blobs with the given hashes
+type BlobFetcher func(hashes [][]byte) error
+
 // NewLedgerCommitter is a factory function to create an instance of the committer
 func NewLedgerCommitter(ledger ledger.PeerLedger, validator txvalidator.Validator) *LedgerCommitter {
 	return &LedgerCommitter{ledger: ledger, validator: validator}
 }
 
+// NewLedgerCommitterWithPvtData is a factory function to create an instance of the committer
+// that commits, along with each block, the private data held in the given transient store
+// for the transactions of the block. The blobs referenced by the valid transactions of a block
+// which are missing on this peer are retrieved with fetchBlobs before the block is committed
+func NewLedgerCommitterWithPvtData(ledger ledger.PeerLedger, validator txvalidator.Validator, pvtDataStore transientstore.Store, fetchBlobs BlobFetcher) *LedgerCommitter {
+	return &LedgerCommitter{ledger: ledger, validator: validator, pvtDataStore: pvtDataStore, fetchBlobs: fetchBlobs}
+}
+
 // Commit commits block to into the ledger
 // Note, it is important that this always be called serially
 func (lc *LedgerCommitter) Commit(block *common.Block) error {
@@ -58,7 +75,15 @@ func (lc *LedgerCommitter) Commit(block *common.Block) error {
 		return err
 	}
 
-	if err := lc.ledger.Commit(block); err != nil {
+	if lc.fetchBlobs != nil {
+		lc.fetchMissingBlobs(block)
+	}
+
+	if lc.pvtDataStore == nil {
+		if err := lc.ledger.Commit(block); err != nil {
+			return err
+		}
+	} else if err := lc.commitWithPvtData(block); err != nil {
 		return err
 	}
 
@@ -70,6 +95,89 @@ func (lc *LedgerCommitter) Commit(block *common.Block) error {
 	return nil
 }
 
+// commitWithPvtData commits the block along with the private data of its transactions
+// and then purges the private data that is no longer needed from the transient store
+func (lc *LedgerCommitter) commitWithPvtData(block *common.Block) error {
+	blockAndPvtData := &ledger.BlockAndPvtData{Block: block, BlockPvtData: make(map[uint64]*ledger.TxPvtData)}
+	var txids []string
+	for seqInBlock := range block.Data.Data {
+		txid := extractTxID(block, seqInBlock)
+		if txid == "" {
+			continue
+		}
+		txids = append(txids, txid)
+		pvtRWSet, err := lc.pvtDataStore.GetTxPvtRWSetByTxid(txid)
+		if err != nil {
+			return err
+		}
+		if pvtRWSet != nil {
+			blockAndPvtData.BlockPvtData[uint64(seqInBlock)] = &ledger.TxPvtData{SeqInBlock: uint64(seqInBlock), WriteSet: pvtRWSet}
+		}
+	}
+
+	if err := lc.ledger.CommitWithPvtData(blockAndPvtData); err != nil {
+		return err
+	}
+
+	// failing to purge does not affect the committed state, the data is purged by height later on
+	if err := lc.pvtDataStore.PurgeByTxids(txids); err != nil {
+		logger.Errorf("Failed purging the private data of the transactions of block %d: %s", block.Header.Number, err)
+	}
+	retention := ledgerconfig.GetTransientStoreMaxBlockRetention()
+	if block.Header.Number > retention && block.Header.Number%retention == 0 {
+		if err := lc.pvtDataStore.PurgeByHeight(block.Header.Number - retention); err != nil {
+			logger.Errorf("Failed purging the private data persisted below height %d: %s", block.Header.Number-retention, err)
+		}
+	}
+	return nil
+}
+
+// fetchMissingBlobs retrieves the blobs referenced by the valid transactions of the block which are not
+// available on this peer. The block is committed all the same if a blob cannot be retrieved, the blob
+// store then records the blob as referenced and adds it once it is received
+func (lc *LedgerCommitter) fetchMissingBlobs(block *common.Block) {
+	store := lc.ledger.GetBlobStore()
+	if store == nil {
+		return
+	}
+	hashes, err := blobstore.BlockReferences(block)
+	if err != nil {
+		logger.Errorf("Failed extracting the blobs referenced by block %d: %s", block.Header.Number, err)
+		return
+	}
+	var missing [][]byte
+	for _, hash := range hashes {
+		if !store.Has(hash) {
+			missing = append(missing, hash)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+	logger.Debugf("Retrieving %d blobs referenced by block %d", len(missing), block.Header.Number)
+	if err = lc.fetchBlobs(missing); err != nil {
+		logger.Warningf("Committing block %d without the blobs it references that are missing: %s", block.Header.Number, err)
+	}
+}
+
+// extractTxID returns the id of the transaction at the given position of the block,
+// or an empty string if the transaction cannot be parsed
+func extractTxID(block *common.Block, seqInBlock int) string {
+	env, err := utils.GetEnvelopeFromBlock(block.Data.Data[seqInBlock])
+	if err != nil {
+		return ""
+	}
+	payload, err := utils.GetPayload(env)
+	if err != nil || payload.Header == nil {
+		return ""
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return ""
+	}
+	return chdr.TxId
+}
+
 // LedgerHeight returns recently committed block sequence number
 func (lc *LedgerCommitter) LedgerHeight() (uint64, error) {
 	var info *common.BlockchainInfo
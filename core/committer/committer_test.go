@@ -17,6 +17,7 @@ limitations under the License.
 package committer
 
 import (
+	"crypto/sha256"
 	"testing"
 
 	"github.com/hyperledger/fabric/common/configtx/test"
@@ -24,8 +25,10 @@ import (
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/hyperledger/fabric/core/ledger/blobstore"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
 	"github.com/hyperledger/fabric/core/mocks/validator"
+	"github.com/hyperledger/fabric/core/transientstore"
 	"github.com/hyperledger/fabric/protos/common"
 )
 
@@ -73,3 +76,92 @@ func TestKVLedgerBlockStorage(t *testing.T) {
 	testutil.AssertEquals(t, bcInfo, &common.BlockchainInfo{
 		Height: 2, CurrentBlockHash: block1Hash, PreviousBlockHash: gbHash})
 }
+
+func TestKVLedgerBlockStorageWithPvtData(t *testing.T) {
+	viper.Set("peer.fileSystemPath", "/tmp/fabric/committertest")
+	ledgermgmt.InitializeTestEnv()
+	defer ledgermgmt.CleanupTestEnv()
+	gb, _ := test.MakeGenesisBlock("TestLedger")
+	gbHash := gb.Header.Hash()
+	ledger, err := ledgermgmt.CreateLedger(gb)
+	assert.NoError(t, err, "Error while creating ledger: %s", err)
+	defer ledger.Close()
+
+	storeProvider := transientstore.NewStoreProvider()
+	defer storeProvider.Close()
+	store, err := storeProvider.OpenStore("TestLedger")
+	assert.NoError(t, err)
+
+	committer := NewLedgerCommitterWithPvtData(ledger, &validator.MockValidator{}, store, nil)
+
+	simulator, _ := ledger.NewTxSimulator()
+	simulator.SetState("ns1", "key1", []byte("value1"))
+	simulator.SetPrivateData("ns1", "coll1", "key2", []byte("pvtValue2"))
+	simulator.Done()
+
+	simRes, _ := simulator.GetTxSimulationResults()
+	pvtSimRes, _ := simulator.GetTxPvtSimulationResults()
+	block1 := testutil.ConstructBlock(t, 1, gbHash, [][]byte{simRes}, true)
+	txid := extractTxID(block1, 0)
+	assert.NotEqual(t, "", txid)
+	assert.NoError(t, store.Persist(txid, 1, pvtSimRes))
+
+	err = committer.Commit(block1)
+	assert.NoError(t, err)
+
+	qe, _ := ledger.NewQueryExecutor()
+	value, err := qe.GetPrivateData("ns1", "coll1", "key2")
+	qe.Done()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("pvtValue2"), value)
+
+	// the private data of the committed transaction is purged from the transient store
+	pvtRWSet, err := store.GetTxPvtRWSetByTxid(txid)
+	assert.NoError(t, err)
+	assert.Nil(t, pvtRWSet)
+}
+
+func TestKVLedgerBlockStorageFetchesMissingBlobs(t *testing.T) {
+	viper.Set("peer.fileSystemPath", "/tmp/fabric/committertest")
+	ledgermgmt.InitializeTestEnv()
+	defer ledgermgmt.CleanupTestEnv()
+	gb, _ := test.MakeGenesisBlock("TestLedger")
+	gbHash := gb.Header.Hash()
+	ledger, err := ledgermgmt.CreateLedger(gb)
+	assert.NoError(t, err, "Error while creating ledger: %s", err)
+	defer ledger.Close()
+
+	content := []byte("blob1")
+	sum := sha256.Sum256(content)
+	hash := sum[:]
+
+	// the blob is staged by the fetcher, as if it was received from another peer of the channel
+	var fetched [][]byte
+	committer := NewLedgerCommitterWithPvtData(ledger, &validator.MockValidator{}, nil, func(hashes [][]byte) error {
+		fetched = append(fetched, hashes...)
+		_, err := ledger.GetBlobStore().StageChunk(hash, 0, uint64(len(content)), content)
+		return err
+	})
+
+	simulator, _ := ledger.NewTxSimulator()
+	simulator.SetState("ns1", "key1", blobstore.Reference(hash))
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	block1 := testutil.ConstructBlock(t, 1, gbHash, [][]byte{simRes}, true)
+
+	assert.NoError(t, committer.Commit(block1))
+	assert.Equal(t, [][]byte{hash}, fetched)
+	b, err := ledger.GetBlobStore().Open(hash)
+	assert.NoError(t, err)
+	b.Close()
+
+	// a blob available on this peer is not fetched again
+	fetched = nil
+	simulator, _ = ledger.NewTxSimulator()
+	simulator.SetState("ns1", "key2", blobstore.Reference(hash))
+	simulator.Done()
+	simRes, _ = simulator.GetTxSimulationResults()
+	block2 := testutil.ConstructBlock(t, 2, block1.Header.Hash(), [][]byte{simRes}, true)
+	assert.NoError(t, committer.Commit(block2))
+	assert.Nil(t, fetched)
+}
@@ -23,6 +23,8 @@ import (
 	"github.com/hyperledger/fabric/common/configtx/test"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	util2 "github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
@@ -32,6 +34,7 @@ import (
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	msptesttools "github.com/hyperledger/fabric/msp/mgmt/testtools"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/spf13/viper"
@@ -204,6 +207,52 @@ func TestGetTxCCInstance(t *testing.T) {
 	}
 	assert.EqualValues(t, expectInvokeCCIns, invokeCCIns)
 	assert.EqualValues(t, expectUpgradeCCIns, upgradeCCIns)
+
+	// committing a chaincode definition upgrades the chaincode
+	def := &peer.ChaincodeDefinition{Name: upgradeCCName, Version: upgradeCCVersion, Sequence: 1}
+	env, err = createLifecycleCommitEnvelope(chainID, utils.MarshalOrPanic(def), signer)
+	assert.NoError(t, err)
+	payload, err = utils.GetPayload(env)
+	assert.NoError(t, err)
+	invokeCCIns, upgradeCCIns, err = tValidator.getTxCCInstance(payload)
+	assert.NoError(t, err)
+	assert.Equal(t, ccprovider.LifecycleSysCC, invokeCCIns.ChaincodeName)
+	assert.EqualValues(t, expectUpgradeCCIns, upgradeCCIns)
+
+	// and an invalid definition is reported
+	env, err = createLifecycleCommitEnvelope(chainID, []byte("garbage"), signer)
+	assert.NoError(t, err)
+	payload, err = utils.GetPayload(env)
+	assert.NoError(t, err)
+	_, _, err = tValidator.getTxCCInstance(payload)
+	assert.Error(t, err)
+}
+
+func createLifecycleCommitEnvelope(chainID string, defBytes []byte, signer msp.SigningIdentity) (*common.Envelope, error) {
+	creator, err := signer.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	cis := &peer.ChaincodeInvocationSpec{
+		ChaincodeSpec: &peer.ChaincodeSpec{
+			ChaincodeId: &peer.ChaincodeID{Name: ccprovider.LifecycleSysCC},
+			Input:       &peer.ChaincodeInput{Args: [][]byte{[]byte("commit"), defBytes}},
+		},
+	}
+	prop, _, err := utils.CreateChaincodeProposal(common.HeaderType_ENDORSER_TRANSACTION, chainID, cis, creator)
+	if err != nil {
+		return nil, err
+	}
+
+	proposalResponse := &peer.ProposalResponse{
+		Response: &peer.Response{
+			Status: 200, // endorsed successfully
+		},
+		Endorsement: &peer.Endorsement{},
+	}
+
+	return utils.CreateSignedTx(prop, signer, proposalResponse)
 }
 
 func TestInvalidTXsForUpgradeCC(t *testing.T) {
@@ -248,3 +297,76 @@ func TestInvalidTXsForUpgradeCC(t *testing.T) {
 
 	assert.EqualValues(t, expectTxsFltr, finalfltr)
 }
+
+func TestUpdatedMetadataKeys(t *testing.T) {
+	updatedMetadataKeys := make(map[metadataKey]bool)
+
+	// tx1 sets the metadata of key1 and deletes key2
+	tx1RWSet := &rwsetutil.TxRwSet{NsRwSets: []*rwsetutil.NsRwSet{{NameSpace: "cc0", KvRwSet: &kvrwset.KVRWSet{
+		Writes:         []*kvrwset.KVWrite{{Key: "key2", IsDelete: true}, {Key: "key3", Value: []byte("value3")}},
+		MetadataWrites: []*kvrwset.KVMetadataWrite{{Key: "key1"}},
+	}}}}
+	_, found := findWrittenKey(tx1RWSet, updatedMetadataKeys)
+	assert.False(t, found)
+	addMetadataWrites(tx1RWSet, updatedMetadataKeys)
+	assert.Equal(t, map[metadataKey]bool{{"cc0", "key1"}: true, {"cc0", "key2"}: true}, updatedMetadataKeys)
+
+	// a later tx writing key1 conflicts with tx1, whereas writing key3 or key1 of another chaincode does not
+	tx2RWSet := &rwsetutil.TxRwSet{NsRwSets: []*rwsetutil.NsRwSet{{NameSpace: "cc0", KvRwSet: &kvrwset.KVRWSet{
+		Writes: []*kvrwset.KVWrite{{Key: "key3", Value: []byte("value3")}, {Key: "key1", Value: []byte("value1")}},
+	}}}}
+	key, found := findWrittenKey(tx2RWSet, updatedMetadataKeys)
+	assert.True(t, found)
+	assert.Equal(t, metadataKey{"cc0", "key1"}, key)
+
+	tx3RWSet := &rwsetutil.TxRwSet{NsRwSets: []*rwsetutil.NsRwSet{{NameSpace: "cc1", KvRwSet: &kvrwset.KVRWSet{
+		Writes: []*kvrwset.KVWrite{{Key: "key1", Value: []byte("value1")}},
+	}}}}
+	_, found = findWrittenKey(tx3RWSet, updatedMetadataKeys)
+	assert.False(t, found)
+	_, found = findWrittenKey(nil, updatedMetadataKeys)
+	assert.False(t, found)
+}
+
+func TestGetStaleReads(t *testing.T) {
+	viper.Set("peer.fileSystemPath", "/tmp/fabric/txvalidatortest")
+	ledgermgmt.InitializeTestEnv()
+	defer ledgermgmt.CleanupTestEnv()
+
+	gb, _ := test.MakeGenesisBlock("TestLedger")
+	ledger, _ := ledgermgmt.CreateLedger(gb)
+	defer ledger.Close()
+
+	simulator, _ := ledger.NewTxSimulator()
+	simulator.SetState("ns1", "key1", []byte("value1"))
+	simulator.SetState("ns1", "key2", []byte("value2"))
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	block1 := testutil.ConstructBlock(t, 1, gb.Header.Hash(), [][]byte{simRes}, false)
+	assert.NoError(t, ledger.Commit(block1))
+
+	// the reads of a query done on this channel by a chaincode of another channel
+	simulator, _ = ledger.NewTxSimulator()
+	simulator.GetState("ns1", "key1")
+	simulator.GetState("ns1", "key2")
+	simulator.GetState("ns1", "key3")
+	simulator.Done()
+	readRes, _ := simulator.GetTxSimulationResults()
+
+	staleKeys, err := getStaleReads(ledger, readRes)
+	assert.NoError(t, err)
+	assert.Empty(t, staleKeys)
+
+	// updating key2 and creating key3 makes their reads stale
+	simulator, _ = ledger.NewTxSimulator()
+	simulator.SetState("ns1", "key2", []byte("value2.1"))
+	simulator.SetState("ns1", "key3", []byte("value3"))
+	simulator.Done()
+	simRes, _ = simulator.GetTxSimulationResults()
+	block2 := testutil.ConstructBlock(t, 2, block1.Header.Hash(), [][]byte{simRes}, false)
+	assert.NoError(t, ledger.Commit(block2))
+
+	staleKeys, err = getStaleReads(ledger, readRes)
+	assert.NoError(t, err)
+	assert.Equal(t, []metadataKey{{"ns1", "key2"}, {"ns1", "key3"}}, staleKeys)
+}
@@ -27,6 +27,8 @@ import (
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/validation"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/msp"
 
@@ -58,6 +60,14 @@ type Support interface {
 	GetMSPIDs(cid string) []string
 }
 
+// ChannelLedgerGetter is optionally implemented by the Support of a validator
+// to give access to the ledgers of the other channels joined by the peer, so
+// that the stale reads done by the transactions on those channels are flagged
+type ChannelLedgerGetter interface {
+	// ChannelLedger returns the ledger of the given channel, nil if the peer has not joined it
+	ChannelLedger(cid string) ledger.PeerLedger
+}
+
 //Validator interface which defines API to validate block transactions
 // and return the bit array mask indicating invalid transactions which
 // didn't pass validation.
@@ -121,6 +131,9 @@ func (v *txValidator) Validate(block *common.Block) error {
 	txsChaincodeNames := make(map[int]*ChaincodeInstance)
 	// upgradedChaincodes records all the chaincodes that are upgrded in a block
 	txsUpgradedChaincodes := make(map[int]*ChaincodeInstance)
+	// updatedMetadataKeys records the keys whose metadata (and hence validation parameter) is updated by the
+	// transactions of the block validated so far
+	updatedMetadataKeys := make(map[metadataKey]bool)
 	for tIdx, d := range block.Data.Data {
 		if d != nil {
 			if env, err := utils.GetEnvelopeFromBlock(d); err != nil {
@@ -168,6 +181,16 @@ func (v *txValidator) Validate(block *common.Block) error {
 						continue
 					}
 
+					// the key-level policies are read from the committed state, hence a transaction writing a key
+					// whose validation parameter is updated by a preceding transaction of the block cannot be validated
+					txRWSet := getTxRWSet(d)
+					if key, found := findWrittenKey(txRWSet, updatedMetadataKeys); found {
+						logger.Errorf("Transaction txId = %s writes key [%s:%s] whose validation parameter is updated by a preceding transaction in the block",
+							txID, key.namespace, key.key)
+						txsfltr.SetFlag(tIdx, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+						continue
+					}
+
 					//the payload is used to get headers
 					logger.Debug("Validating transaction vscc tx validate")
 					if err = v.vscc.VSCCValidateTx(payload, d, env); err != nil {
@@ -176,6 +199,8 @@ func (v *txValidator) Validate(block *common.Block) error {
 						txsfltr.SetFlag(tIdx, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
 						continue
 					}
+					addMetadataWrites(txRWSet, updatedMetadataKeys)
+					v.flagStaleCrossChannelReads(txID, d)
 
 					invokeCC, upgradeCC, err := v.getTxCCInstance(payload)
 					if err != nil {
@@ -343,9 +368,33 @@ func (v *txValidator) getTxCCInstance(payload *common.Payload) (invokeCCIns, upg
 		}
 	}
 
+	if invokeCC.Name == ccprovider.LifecycleSysCC {
+		// committing a chaincode definition upgrades the chaincode like an lscc upgrade does
+		if args := cis.ChaincodeSpec.Input.Args; len(args) > 1 && string(args[0]) == "commit" {
+			upgradeIns, err := v.getCommitTxInstance(chainID, args[1])
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid chaincode definition committed by transaction: %s", err)
+			}
+			return invokeIns, upgradeIns, nil
+		}
+	}
+
 	return invokeIns, nil, nil
 }
 
+func (v *txValidator) getCommitTxInstance(chainID string, defBytes []byte) (*ChaincodeInstance, error) {
+	def := &peer.ChaincodeDefinition{}
+	if err := proto.Unmarshal(defBytes, def); err != nil {
+		return nil, err
+	}
+
+	return &ChaincodeInstance{
+		ChainID:          chainID,
+		ChaincodeName:    def.Name,
+		ChaincodeVersion: def.Version,
+	}, nil
+}
+
 func (v *txValidator) getUpgradeTxInstance(chainID string, cdsBytes []byte) (*ChaincodeInstance, error) {
 	cds, err := utils.GetChaincodeDeploymentSpec(cdsBytes)
 	if err != nil {
@@ -395,9 +444,12 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 		return err
 	}
 
+	// the lifecycle system chaincodes are validated alike
+	isLifecycleCC := hdrExt.ChaincodeId.Name == "lscc" || hdrExt.ChaincodeId.Name == ccprovider.LifecycleSysCC
+
 	var vscc string
 	var policy []byte
-	if hdrExt.ChaincodeId.Name != "lscc" {
+	if !isLifecycleCC {
 		// when we are validating any chaincode other than
 		// LSCC, we need to ask LSCC to give us the name
 		// of VSCC and of the policy that should be used
@@ -411,9 +463,10 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 		vscc = cd.Vscc
 		policy = cd.Policy
 	} else {
-		// when we are validating LSCC, we use the default
-		// VSCC and a default policy that requires one signature
-		// from any of the members of the channel
+		// when we are validating LSCC (or the lifecycle system
+		// chaincode), we use the default VSCC and a default policy
+		// that requires one signature from any of the members of
+		// the channel
 		vscc = "vscc"
 		policy = cauthdsl.SignedByAnyMember(v.support.GetMSPIDs(chainID))
 	}
@@ -422,7 +475,22 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 	// args[0] - function name (not used now)
 	// args[1] - serialized Envelope
 	// args[2] - serialized policy
+	// args[3] - serialized key-level endorsement policies, only if any of the written keys has one
 	args := [][]byte{[]byte(""), envBytes, policy}
+	if !isLifecycleCC {
+		keyPolicies, err := v.getKeyEndorsementPolicies(hdrExt.ChaincodeId.Name, getTxRWSet(envBytes))
+		if err != nil {
+			logger.Errorf("Unable to get the key-level endorsement policies for txid %s, due to %s", txid, err)
+			return err
+		}
+		if keyPolicies != nil {
+			keyPoliciesBytes, err := proto.Marshal(keyPolicies)
+			if err != nil {
+				return err
+			}
+			args = append(args, keyPoliciesBytes)
+		}
+	}
 
 	vscctxid := coreUtil.GenerateUUID()
 
@@ -457,6 +525,15 @@ func (v *vsccValidatorImpl) getCDataForCC(ccid string) (*ccprovider.ChaincodeDat
 	}
 	defer qe.Done()
 
+	// a definition committed through the lifecycle system chaincode takes precedence over LSCC's
+	cd, err := ccprovider.GetCommittedChaincodeData(qe, ccid)
+	if err != nil {
+		return nil, err
+	}
+	if cd != nil {
+		return cd, nil
+	}
+
 	bytes, err := qe.GetState("lscc", ccid)
 	if err != nil {
 		return nil, fmt.Errorf("Could not retrieve state for chaincode %s, error %s", ccid, err)
@@ -466,7 +543,7 @@ func (v *vsccValidatorImpl) getCDataForCC(ccid string) (*ccprovider.ChaincodeDat
 		return nil, fmt.Errorf("lscc's state for [%s] not found.", ccid)
 	}
 
-	cd := &ccprovider.ChaincodeData{}
+	cd = &ccprovider.ChaincodeData{}
 	err = proto.Unmarshal(bytes, cd)
 	if err != nil {
 		return nil, fmt.Errorf("Unmarshalling ChaincodeQueryResponse failed, error %s", err)
@@ -482,3 +559,187 @@ func (v *vsccValidatorImpl) getCDataForCC(ccid string) (*ccprovider.ChaincodeDat
 
 	return cd, err
 }
+
+// getKeyEndorsementPolicies returns the committed key-level endorsement policies of the keys of the namespace
+// written by the transaction. It returns nil if none of the written keys has a key-level policy
+func (v *vsccValidatorImpl) getKeyEndorsementPolicies(ns string, txRWSet *rwsetutil.TxRwSet) (*peer.KeyEndorsementPolicies, error) {
+	if txRWSet == nil {
+		return nil, nil
+	}
+	l := v.support.Ledger()
+	if l == nil {
+		return nil, fmt.Errorf("nil ledger instance")
+	}
+	qe, err := l.NewQueryExecutor()
+	if err != nil {
+		return nil, fmt.Errorf("Could not retrieve QueryExecutor, error %s", err)
+	}
+	defer qe.Done()
+
+	var keyPolicies *peer.KeyEndorsementPolicies
+	for _, key := range getWrittenKeys(txRWSet) {
+		if key.namespace != ns {
+			continue
+		}
+		metadata, err := qe.GetStateMetadata(ns, key.key)
+		if err != nil {
+			return nil, fmt.Errorf("Could not retrieve the metadata of key [%s:%s], error %s", ns, key.key, err)
+		}
+		keyPolicy := metadata[peer.MetaDataKeys_VALIDATION_PARAMETER.String()]
+		if len(keyPolicy) == 0 {
+			continue
+		}
+		if keyPolicies == nil {
+			keyPolicies = &peer.KeyEndorsementPolicies{Policies: make(map[string][]byte)}
+		}
+		keyPolicies.Policies[key.key] = keyPolicy
+	}
+	return keyPolicies, nil
+}
+
+// metadataKey identifies a key of the state of a chaincode
+type metadataKey struct {
+	namespace string
+	key       string
+}
+
+// getTxRWSet returns the read-write set of the transaction, or nil if it cannot be extracted
+func getTxRWSet(envBytes []byte) *rwsetutil.TxRwSet {
+	ccAction, err := utils.GetActionFromEnvelope(envBytes)
+	if err != nil {
+		return nil
+	}
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err = txRWSet.FromProtoBytes(ccAction.Results); err != nil {
+		return nil
+	}
+	return txRWSet
+}
+
+// getWrittenKeys returns the keys whose value or metadata is written by the transaction
+func getWrittenKeys(txRWSet *rwsetutil.TxRwSet) []metadataKey {
+	var keys []metadataKey
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+			keys = append(keys, metadataKey{nsRWSet.NameSpace, kvWrite.Key})
+		}
+		for _, kvMetadataWrite := range nsRWSet.KvRwSet.MetadataWrites {
+			keys = append(keys, metadataKey{nsRWSet.NameSpace, kvMetadataWrite.Key})
+		}
+	}
+	return keys
+}
+
+// findWrittenKey returns a key written by the transaction that is present in the given keys, if any
+func findWrittenKey(txRWSet *rwsetutil.TxRwSet, keys map[metadataKey]bool) (metadataKey, bool) {
+	if txRWSet == nil || len(keys) == 0 {
+		return metadataKey{}, false
+	}
+	for _, key := range getWrittenKeys(txRWSet) {
+		if keys[key] {
+			return key, true
+		}
+	}
+	return metadataKey{}, false
+}
+
+// addMetadataWrites adds to the given keys the keys whose metadata is written by the transaction.
+// A delete removes the metadata of the key as well
+func addMetadataWrites(txRWSet *rwsetutil.TxRwSet, keys map[metadataKey]bool) {
+	if txRWSet == nil {
+		return
+	}
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+			if kvWrite.IsDelete {
+				keys[metadataKey{nsRWSet.NameSpace, kvWrite.Key}] = true
+			}
+		}
+		for _, kvMetadataWrite := range nsRWSet.KvRwSet.MetadataWrites {
+			keys[metadataKey{nsRWSet.NameSpace, kvMetadataWrite.Key}] = true
+		}
+	}
+}
+
+// flagStaleCrossChannelReads logs a warning for each key read on another channel while endorsing
+// the transaction whose committed version has changed since. Such a read cannot invalidate the
+// transaction as the blocks of the other channels are not ordered with the blocks of this one
+func (v *txValidator) flagStaleCrossChannelReads(txID string, envBytes []byte) {
+	ledgerGetter, ok := v.support.(ChannelLedgerGetter)
+	if !ok {
+		return
+	}
+	action, err := utils.GetActionFromEnvelope(envBytes)
+	if err != nil || len(action.CrossChannelReads) == 0 {
+		return
+	}
+	readSets, err := utils.GetCrossChannelReadSets(action.CrossChannelReads)
+	if err != nil {
+		logger.Warningf("Could not unmarshal the cross-channel reads of transaction txId = %s: %s", txID, err)
+		return
+	}
+	for _, readSet := range readSets.ReadSets {
+		lgr := ledgerGetter.ChannelLedger(readSet.ChannelId)
+		if lgr == nil {
+			logger.Debugf("Cannot check the reads of transaction txId = %s on channel %s which is not joined", txID, readSet.ChannelId)
+			continue
+		}
+		staleKeys, err := getStaleReads(lgr, readSet.Results)
+		if err != nil {
+			logger.Warningf("Could not check the reads of transaction txId = %s on channel %s: %s", txID, readSet.ChannelId, err)
+			continue
+		}
+		for _, key := range staleKeys {
+			logger.Warningf("Transaction txId = %s read key [%s:%s] on channel %s which has been updated since the endorsement",
+				txID, key.namespace, key.key, readSet.ChannelId)
+		}
+	}
+}
+
+// getStaleReads returns the keys read in the given serialized TxReadWriteSet whose version is no
+// longer the one committed in the ledger
+func getStaleReads(lgr ledger.PeerLedger, results []byte) ([]metadataKey, error) {
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(results); err != nil {
+		return nil, err
+	}
+
+	// reading the keys again records their committed versions
+	txsim, err := lgr.NewTxSimulator()
+	if err != nil {
+		return nil, err
+	}
+	defer txsim.Done()
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		for _, kvRead := range nsRWSet.KvRwSet.Reads {
+			if _, err := txsim.GetState(nsRWSet.NameSpace, kvRead.Key); err != nil {
+				return nil, err
+			}
+		}
+	}
+	committedResults, err := txsim.GetTxSimulationResults()
+	if err != nil {
+		return nil, err
+	}
+	committedRWSet := &rwsetutil.TxRwSet{}
+	if err := committedRWSet.FromProtoBytes(committedResults); err != nil {
+		return nil, err
+	}
+	committedVersions := make(map[metadataKey]*version.Height)
+	for _, nsRWSet := range committedRWSet.NsRwSets {
+		for _, kvRead := range nsRWSet.KvRwSet.Reads {
+			committedVersions[metadataKey{nsRWSet.NameSpace, kvRead.Key}] = rwsetutil.NewVersion(kvRead.Version)
+		}
+	}
+
+	var staleKeys []metadataKey
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		for _, kvRead := range nsRWSet.KvRwSet.Reads {
+			key := metadataKey{nsRWSet.NameSpace, kvRead.Key}
+			if !version.AreSame(committedVersions[key], rwsetutil.NewVersion(kvRead.Version)) {
+				staleKeys = append(staleKeys, key)
+			}
+		}
+	}
+	return staleKeys, nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ccprovider
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// LifecycleSysCC is the name of the system chaincode with which the organizations of a channel
+// approve and commit chaincode definitions; its state holds the approvals and the committed definitions
+const LifecycleSysCC = "_lifecycle"
+
+// StateReader reads the state of a channel, as both a query executor and a tx simulator do
+type StateReader interface {
+	GetState(namespace string, key string) ([]byte, error)
+}
+
+// ChaincodeDefinitionKey returns the key of the lifecycle system chaincode state
+// under which the committed definition of a chaincode is stored
+func ChaincodeDefinitionKey(ccname string) string {
+	return "namespaces/" + ccname
+}
+
+// ChaincodeApprovalKey returns the key of the lifecycle system chaincode state under which
+// the approval of an organization for the given sequence of a chaincode definition is stored
+func ChaincodeApprovalKey(ccname string, sequence int64, mspID string) string {
+	return fmt.Sprintf("approvals/%s/%d/%s", ccname, sequence, mspID)
+}
+
+// GetChaincodeDefinition returns the committed definition of a chaincode, or nil if none was committed
+func GetChaincodeDefinition(r StateReader, ccname string) (*pb.ChaincodeDefinition, error) {
+	defBytes, err := r.GetState(LifecycleSysCC, ChaincodeDefinitionKey(ccname))
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve the definition of chaincode %s, error %s", ccname, err)
+	}
+	if defBytes == nil {
+		return nil, nil
+	}
+
+	def := &pb.ChaincodeDefinition{}
+	if err = proto.Unmarshal(defBytes, def); err != nil {
+		return nil, fmt.Errorf("invalid definition of chaincode %s, error %s", ccname, err)
+	}
+
+	return def, nil
+}
+
+// GetCommittedChaincodeData returns the chaincode data of the committed definition of a chaincode, or nil
+// if none was committed. A committed definition takes precedence over the chaincode data of LSCC
+func GetCommittedChaincodeData(r StateReader, ccname string) (*ChaincodeData, error) {
+	def, err := GetChaincodeDefinition(r, ccname)
+	if err != nil || def == nil {
+		return nil, err
+	}
+
+	return NewChaincodeDataFromDefinition(def), nil
+}
+
+// NewChaincodeDataFromDefinition returns the chaincode data equivalent to a committed chaincode definition
+func NewChaincodeDataFromDefinition(def *pb.ChaincodeDefinition) *ChaincodeData {
+	return &ChaincodeData{
+		Name:    def.Name,
+		Version: def.Version,
+		Escc:    def.Escc,
+		Vscc:    def.Vscc,
+		Policy:  def.EndorsementPolicy,
+		Id:      def.PackageHash,
+	}
+}
+
+// GetChaincodePackageForData returns the package of a chaincode installed on this peer, after checking
+// that its hash matches the one of the chaincode data
+func GetChaincodePackageForData(cd *ChaincodeData) (CCPackage, error) {
+	ccpack, err := GetChaincodeFromFS(cd.Name, cd.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(ccpack.GetId(), cd.Id) {
+		return nil, fmt.Errorf("chaincode %s:%s installed on the peer does not match the package hash of its definition", cd.Name, cd.Version)
+	}
+
+	return ccpack, nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ccprovider
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type mapStateReader map[string][]byte
+
+func (m mapStateReader) GetState(namespace string, key string) ([]byte, error) {
+	if v, ok := m[namespace+"/"+key]; ok && v == nil {
+		return nil, fmt.Errorf("unreadable key %s", key)
+	}
+	return m[namespace+"/"+key], nil
+}
+
+func TestGetCommittedChaincodeData(t *testing.T) {
+	def := &pb.ChaincodeDefinition{Name: "mycc", Version: "1.0", Sequence: 2, EndorsementPolicy: []byte("policy"), PackageHash: []byte("hash"), Escc: "escc", Vscc: "vscc"}
+	state := mapStateReader{
+		"_lifecycle/namespaces/mycc":      utils.MarshalOrPanic(def),
+		"_lifecycle/namespaces/invalidcc": []byte("invalid"),
+		"_lifecycle/namespaces/errorcc":   nil,
+		"lscc/othercc":                    []byte("othercc"),
+	}
+
+	cd, err := GetCommittedChaincodeData(state, "mycc")
+	assert.NoError(t, err)
+	assert.Equal(t, &ChaincodeData{Name: "mycc", Version: "1.0", Escc: "escc", Vscc: "vscc", Policy: []byte("policy"), Id: []byte("hash")}, cd)
+
+	cd, err = GetCommittedChaincodeData(state, "othercc")
+	assert.NoError(t, err)
+	assert.Nil(t, cd)
+
+	_, err = GetCommittedChaincodeData(state, "invalidcc")
+	assert.Error(t, err)
+	_, err = GetCommittedChaincodeData(state, "errorcc")
+	assert.Error(t, err)
+
+	assert.Equal(t, "approvals/mycc/2/Org1MSP", ChaincodeApprovalKey("mycc", 2, "Org1MSP"))
+}
+
+func TestGetChaincodePackageForData(t *testing.T) {
+	ccdir := setupccdir()
+	defer os.RemoveAll(ccdir)
+
+	cds := &pb.ChaincodeDeploymentSpec{ChaincodeSpec: &pb.ChaincodeSpec{Type: 1, ChaincodeId: &pb.ChaincodeID{Name: "testcc", Version: "0"}, Input: &pb.ChaincodeInput{Args: [][]byte{[]byte("")}}}, CodePackage: []byte("code")}
+	ccpack, _, _, err := processCDS(cds, true)
+	assert.NoError(t, err)
+
+	pack, err := GetChaincodePackageForData(&ChaincodeData{Name: "testcc", Version: "0", Id: ccpack.GetId()})
+	assert.NoError(t, err)
+	assert.Equal(t, ccpack.GetId(), pack.GetId())
+
+	_, err = GetChaincodePackageForData(&ChaincodeData{Name: "testcc", Version: "0", Id: []byte("otherhash")})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "does not match the package hash of its definition")
+
+	_, err = GetChaincodePackageForData(&ChaincodeData{Name: "testcc", Version: "1", Id: ccpack.GetId()})
+	assert.Error(t, err)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ccprovider
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// MetadataDir is the directory of a chaincode code package that holds the artifacts which are
+// not part of the chaincode source, such as the index definitions for the state database
+const MetadataDir = "META-INF"
+
+// statedbIndexesDir is the directory, under "META-INF/statedb/<db type>", holding the index definitions
+const statedbIndexesDir = "indexes"
+
+// ExtractStatedbIndexes extracts the index definitions packaged with a chaincode from its code package
+// (a gzipped tar). Index definitions are the json files placed under "META-INF/statedb/<db type>/indexes".
+// The returned map is keyed by the db type (e.g., "couchdb") and then by the file name of the index definition
+func ExtractStatedbIndexes(codePackage []byte) (map[string]map[string][]byte, error) {
+	indexes := make(map[string]map[string][]byte)
+	if len(codePackage) == 0 {
+		return indexes, nil
+	}
+
+	gr, err := gzip.NewReader(bytes.NewReader(codePackage))
+	if err != nil {
+		return nil, fmt.Errorf("Failure opening codepackage gzip stream: %s", err)
+	}
+	tr := tar.NewReader(gr)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Failure reading codepackage tar stream: %s", err)
+		}
+
+		// the expected path is META-INF/statedb/<db type>/indexes/<file name>.json
+		pathElements := strings.Split(strings.TrimPrefix(header.Name, "/"), "/")
+		if len(pathElements) != 5 || pathElements[0] != MetadataDir || pathElements[1] != "statedb" ||
+			pathElements[3] != statedbIndexesDir || filepath.Ext(pathElements[4]) != ".json" {
+			continue
+		}
+
+		dbType, fileName := pathElements[2], pathElements[4]
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("Failure reading index definition %s from codepackage: %s", header.Name, err)
+		}
+		ccproviderLogger.Debugf("Found index definition %s for state database type %s", fileName, dbType)
+
+		if _, ok := indexes[dbType]; !ok {
+			indexes[dbType] = make(map[string][]byte)
+		}
+		indexes[dbType][fileName] = content
+	}
+	return indexes, nil
+}
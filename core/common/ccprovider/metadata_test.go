@@ -0,0 +1,73 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ccprovider
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+)
+
+func createCodePackage(t *testing.T, files map[string]string) []byte {
+	buf := bytes.NewBuffer(nil)
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		testutil.AssertNoError(t, tw.WriteHeader(&tar.Header{Name: name, Size: int64(len(content)), Mode: 0100644}), "")
+		_, err := tw.Write([]byte(content))
+		testutil.AssertNoError(t, err, "")
+	}
+	tw.Close()
+	gw.Close()
+	return buf.Bytes()
+}
+
+func TestExtractStatedbIndexes(t *testing.T) {
+	codePackage := createCodePackage(t, map[string]string{
+		"src/github.com/example/cc/cc.go":                                   "package main",
+		"src/github.com/example/cc/META-INF/statedb/couchdb/indexes/a.json": `{"index":{"fields":["a"]}}`,
+		"META-INF/statedb/couchdb/indexes/indexOwner.json":                  `{"index":{"fields":["owner"]}}`,
+		"META-INF/statedb/couchdb/indexes/indexSize.json":                   `{"index":{"fields":["size"]}}`,
+		"META-INF/statedb/couchdb/indexes/README.md":                        "not an index",
+		"META-INF/statedb/couchdb/other/indexOther.json":                    `{"index":{"fields":["other"]}}`,
+		"META-INF/statedb/otherdb/indexes/indexOther.json":                  `{"index":{"fields":["other"]}}`,
+	})
+
+	indexes, err := ExtractStatedbIndexes(codePackage)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, indexes, map[string]map[string][]byte{
+		"couchdb": {
+			"indexOwner.json": []byte(`{"index":{"fields":["owner"]}}`),
+			"indexSize.json":  []byte(`{"index":{"fields":["size"]}}`),
+		},
+		"otherdb": {
+			"indexOther.json": []byte(`{"index":{"fields":["other"]}}`),
+		},
+	})
+
+	// an empty code package has no indexes
+	indexes, err = ExtractStatedbIndexes(nil)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, len(indexes), 0)
+
+	// a code package that is not a gzipped tar is rejected
+	_, err = ExtractStatedbIndexes([]byte("code"))
+	testutil.AssertError(t, err, "Expected an error for an invalid code package")
+}
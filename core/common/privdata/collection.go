@@ -0,0 +1,141 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privdata
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+)
+
+// collectionSeparator is the separator used to build the key under which
+// the collection configuration of a chaincode is stored in the lscc namespace
+const collectionSeparator = "~"
+
+// collectionSuffix is the suffix of the key under which the collection
+// configuration of a chaincode is stored in the lscc namespace
+const collectionSuffix = "collection"
+
+// Collection defines a common interface for collections
+type Collection interface {
+	// CollectionID returns this collection's ID
+	CollectionID() string
+
+	// MemberOrgs returns the collection's members as MSP IDs. This serves as
+	// a human-readable way of quickly identifying who is part of a collection.
+	MemberOrgs() []string
+
+	// RequiredPeerCount returns the minimum number of peers the private data
+	// of this collection has to be disseminated to upon endorsement
+	RequiredPeerCount() int
+
+	// MaximumPeerCount returns the maximum number of peers the private data
+	// of this collection is disseminated to upon endorsement
+	MaximumPeerCount() int
+}
+
+// BuildCollectionKVSKey constructs the key under which the collection
+// configuration of the given chaincode is stored in the lscc namespace
+func BuildCollectionKVSKey(ccname string) string {
+	return ccname + collectionSeparator + collectionSuffix
+}
+
+// IsCollectionConfigKey returns true if the given key of the lscc namespace
+// holds the collection configuration of a chaincode
+func IsCollectionConfigKey(key string) bool {
+	suffix := collectionSeparator + collectionSuffix
+	return len(key) > len(suffix) && key[len(key)-len(suffix):] == suffix
+}
+
+// simpleCollection implements a collection with static properties
+// and a member orgs list derived from a signature policy
+type simpleCollection struct {
+	name       string
+	memberOrgs []string
+	conf       *common.StaticCollectionConfig
+}
+
+// NewSimpleCollection constructs a Collection out of a static collection configuration
+func NewSimpleCollection(collectionConfig *common.StaticCollectionConfig) (Collection, error) {
+	if collectionConfig == nil {
+		return nil, fmt.Errorf("Nil collection config")
+	}
+	if collectionConfig.Name == "" {
+		return nil, fmt.Errorf("Collection name cannot be empty")
+	}
+	if collectionConfig.RequiredPeerCount < 0 {
+		return nil, fmt.Errorf("Collection [%s]: required peer count cannot be negative", collectionConfig.Name)
+	}
+	if collectionConfig.MaximumPeerCount < collectionConfig.RequiredPeerCount {
+		return nil, fmt.Errorf("Collection [%s]: maximum peer count (%d) cannot be lower than the required peer count (%d)",
+			collectionConfig.Name, collectionConfig.MaximumPeerCount, collectionConfig.RequiredPeerCount)
+	}
+	policy := collectionConfig.MemberOrgsPolicy.GetSignaturePolicy()
+	if policy == nil {
+		return nil, fmt.Errorf("Collection [%s]: member orgs policy must be a signature policy", collectionConfig.Name)
+	}
+	memberOrgs, err := getMemberOrgs(policy)
+	if err != nil {
+		return nil, fmt.Errorf("Collection [%s]: %s", collectionConfig.Name, err)
+	}
+	return &simpleCollection{name: collectionConfig.Name, memberOrgs: memberOrgs, conf: collectionConfig}, nil
+}
+
+// CollectionID returns the collection's ID
+func (sc *simpleCollection) CollectionID() string {
+	return sc.name
+}
+
+// MemberOrgs returns the MSP IDs that are part of this collection
+func (sc *simpleCollection) MemberOrgs() []string {
+	return sc.memberOrgs
+}
+
+// RequiredPeerCount returns the minimum number of peers to disseminate the private data to
+func (sc *simpleCollection) RequiredPeerCount() int {
+	return int(sc.conf.RequiredPeerCount)
+}
+
+// MaximumPeerCount returns the maximum number of peers to disseminate the private data to
+func (sc *simpleCollection) MaximumPeerCount() int {
+	return int(sc.conf.MaximumPeerCount)
+}
+
+// getMemberOrgs returns the MSP IDs of the role principals of a signature policy
+func getMemberOrgs(policy *common.SignaturePolicyEnvelope) ([]string, error) {
+	var memberOrgs []string
+	seen := make(map[string]bool)
+	for _, principal := range policy.Identities {
+		if principal.PrincipalClassification != msp.MSPPrincipal_ROLE {
+			return nil, fmt.Errorf("Unsupported principal classification [%s] in member orgs policy", principal.PrincipalClassification)
+		}
+		mspRole := &msp.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, mspRole); err != nil {
+			return nil, fmt.Errorf("Invalid principal in member orgs policy: %s", err)
+		}
+		if !seen[mspRole.MspIdentifier] {
+			seen[mspRole.MspIdentifier] = true
+			memberOrgs = append(memberOrgs, mspRole.MspIdentifier)
+		}
+	}
+	if len(memberOrgs) == 0 {
+		return nil, fmt.Errorf("Member orgs policy does not name any org")
+	}
+	return memberOrgs, nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privdata
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+// lsccNamespace is the namespace of the lifecycle system chaincode,
+// which holds the collection configurations of the chaincodes
+const lsccNamespace = "lscc"
+
+// QueryExecutorFactory creates query executors on the state of a channel
+type QueryExecutorFactory interface {
+	NewQueryExecutor() (ledger.QueryExecutor, error)
+}
+
+// CollectionStore retrieves the collections of the chaincodes deployed on a channel
+type CollectionStore interface {
+	// RetrieveCollection returns the collection of the given chaincode with the given name.
+	// It returns nil if the chaincode does not define such a collection
+	RetrieveCollection(namespace, collection string) (Collection, error)
+
+	// RetrieveCollectionConfigPackage returns the collection configuration the given chaincode
+	// has been instantiated (or last upgraded) with. It returns nil if none was supplied
+	RetrieveCollectionConfigPackage(namespace string) (*common.CollectionConfigPackage, error)
+}
+
+// simpleCollectionStore implements CollectionStore by reading the lscc namespace of a channel
+type simpleCollectionStore struct {
+	qeFactory QueryExecutorFactory
+}
+
+// NewSimpleCollectionStore returns a CollectionStore backed by the state of the given channel
+func NewSimpleCollectionStore(qeFactory QueryExecutorFactory) CollectionStore {
+	return &simpleCollectionStore{qeFactory}
+}
+
+// RetrieveCollectionConfigPackage implements method in interface CollectionStore
+func (s *simpleCollectionStore) RetrieveCollectionConfigPackage(namespace string) (*common.CollectionConfigPackage, error) {
+	qe, err := s.qeFactory.NewQueryExecutor()
+	if err != nil {
+		return nil, err
+	}
+	defer qe.Done()
+	cb, err := qe.GetState(lsccNamespace, BuildCollectionKVSKey(namespace))
+	if err != nil {
+		return nil, err
+	}
+	if cb == nil {
+		return nil, nil
+	}
+	return ParseCollectionConfigPackage(cb)
+}
+
+// RetrieveCollection implements method in interface CollectionStore
+func (s *simpleCollectionStore) RetrieveCollection(namespace, collection string) (Collection, error) {
+	collections, err := s.RetrieveCollectionConfigPackage(namespace)
+	if err != nil || collections == nil {
+		return nil, err
+	}
+	for _, config := range collections.Config {
+		staticConfig := config.GetStaticCollectionConfig()
+		if staticConfig != nil && staticConfig.Name == collection {
+			return NewSimpleCollection(staticConfig)
+		}
+	}
+	return nil, nil
+}
+
+// ParseCollectionConfigPackage unmarshals and validates a collection configuration package
+func ParseCollectionConfigPackage(collectionConfigBytes []byte) (*common.CollectionConfigPackage, error) {
+	collections := &common.CollectionConfigPackage{}
+	if err := proto.Unmarshal(collectionConfigBytes, collections); err != nil {
+		return nil, fmt.Errorf("Invalid collection configuration: %s", err)
+	}
+	names := make(map[string]bool)
+	for _, config := range collections.Config {
+		staticConfig := config.GetStaticCollectionConfig()
+		if staticConfig == nil {
+			return nil, fmt.Errorf("Unsupported collection configuration type")
+		}
+		if _, err := NewSimpleCollection(staticConfig); err != nil {
+			return nil, err
+		}
+		if names[staticConfig.Name] {
+			return nil, fmt.Errorf("Collection [%s] is defined more than once", staticConfig.Name)
+		}
+		names[staticConfig.Name] = true
+	}
+	return collections, nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privdata
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+type mockQueryExecutor struct {
+	ledger.QueryExecutor
+	state map[string][]byte
+}
+
+func (qe *mockQueryExecutor) GetState(namespace, key string) ([]byte, error) {
+	return qe.state[namespace+"/"+key], nil
+}
+
+func (qe *mockQueryExecutor) Done() {
+}
+
+type mockQueryExecutorFactory struct {
+	qe *mockQueryExecutor
+}
+
+func (f *mockQueryExecutorFactory) NewQueryExecutor() (ledger.QueryExecutor, error) {
+	return f.qe, nil
+}
+
+func sampleCollectionConfig(name string, required, maximum int32, orgs ...string) *common.CollectionConfig {
+	policyEnvelope := &common.SignaturePolicyEnvelope{}
+	proto.Unmarshal(cauthdsl.SignedByAnyMember(orgs), policyEnvelope)
+	return &common.CollectionConfig{
+		Payload: &common.CollectionConfig_StaticCollectionConfig{
+			StaticCollectionConfig: &common.StaticCollectionConfig{
+				Name:              name,
+				MemberOrgsPolicy:  &common.CollectionPolicyConfig{Payload: &common.CollectionPolicyConfig_SignaturePolicy{SignaturePolicy: policyEnvelope}},
+				RequiredPeerCount: required,
+				MaximumPeerCount:  maximum,
+			},
+		},
+	}
+}
+
+func TestCollectionKVSKey(t *testing.T) {
+	testutil.AssertEquals(t, BuildCollectionKVSKey("mycc"), "mycc~collection")
+	testutil.AssertEquals(t, IsCollectionConfigKey("mycc~collection"), true)
+	testutil.AssertEquals(t, IsCollectionConfigKey("mycc"), false)
+	testutil.AssertEquals(t, IsCollectionConfigKey("~collection"), false)
+}
+
+func TestRetrieveCollection(t *testing.T) {
+	ccp := &common.CollectionConfigPackage{Config: []*common.CollectionConfig{
+		sampleCollectionConfig("coll1", 1, 2, "Org1MSP", "Org2MSP"),
+		sampleCollectionConfig("coll2", 0, 0, "Org1MSP"),
+	}}
+	ccpBytes, err := proto.Marshal(ccp)
+	testutil.AssertNoError(t, err, "")
+	qe := &mockQueryExecutor{state: map[string][]byte{"lscc/mycc~collection": ccpBytes}}
+	store := NewSimpleCollectionStore(&mockQueryExecutorFactory{qe})
+
+	coll, err := store.RetrieveCollection("mycc", "coll1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, coll.CollectionID(), "coll1")
+	testutil.AssertEquals(t, coll.MemberOrgs(), []string{"Org1MSP", "Org2MSP"})
+	testutil.AssertEquals(t, coll.RequiredPeerCount(), 1)
+	testutil.AssertEquals(t, coll.MaximumPeerCount(), 2)
+
+	coll, err = store.RetrieveCollection("mycc", "coll3")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, coll)
+
+	coll, err = store.RetrieveCollection("othercc", "coll1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, coll)
+}
+
+func TestParseCollectionConfigPackage(t *testing.T) {
+	validPackage := &common.CollectionConfigPackage{Config: []*common.CollectionConfig{sampleCollectionConfig("coll1", 1, 2, "Org1MSP")}}
+	validBytes, _ := proto.Marshal(validPackage)
+	_, err := ParseCollectionConfigPackage(validBytes)
+	testutil.AssertNoError(t, err, "")
+
+	_, err = ParseCollectionConfigPackage([]byte("garbage"))
+	testutil.AssertError(t, err, "Expected an error for an unparsable package")
+
+	invalidPackages := []*common.CollectionConfigPackage{
+		{Config: []*common.CollectionConfig{sampleCollectionConfig("", 1, 2, "Org1MSP")}},
+		{Config: []*common.CollectionConfig{sampleCollectionConfig("coll1", 2, 1, "Org1MSP")}},
+		{Config: []*common.CollectionConfig{sampleCollectionConfig("coll1", 1, 2)}},
+		{Config: []*common.CollectionConfig{sampleCollectionConfig("coll1", 1, 2, "Org1MSP"), sampleCollectionConfig("coll1", 1, 2, "Org2MSP")}},
+		{Config: []*common.CollectionConfig{{}}},
+	}
+	for _, invalidPackage := range invalidPackages {
+		invalidBytes, _ := proto.Marshal(invalidPackage)
+		_, err = ParseCollectionConfigPackage(invalidBytes)
+		testutil.AssertError(t, err, "Expected an error for an invalid package")
+	}
+}
@@ -16,6 +16,10 @@ limitations under the License.
 
 package sysccprovider
 
+import (
+	"github.com/hyperledger/fabric/core/ledger"
+)
+
 // SystemChaincodeProvider provides an abstraction layer that is
 // used for different packages to interact with code in the
 // system chaincode package without importing it; more methods
@@ -27,6 +31,10 @@ type SystemChaincodeProvider interface {
 	// IsSysCCAndNotInvokableCC2CC returns true if the supplied chaincode
 	// is a system chaincode and is not invokable through a cc2cc invocation
 	IsSysCCAndNotInvokableCC2CC(name string) bool
+
+	// GetQueryExecutorForLedger returns a query executor for the
+	// ledger of the supplied channel
+	GetQueryExecutorForLedger(cid string) (ledger.QueryExecutor, error)
 }
 
 var sccFactory SystemChaincodeProviderFactory
@@ -18,7 +18,8 @@ package ccintf
 
 //This package defines the interfaces that support runtime and
 //communication between chaincode and peer (chaincode support).
-//Currently inproccontroller uses it. dockercontroller does not.
+//Currently inproccontroller and externalcontroller use it. dockercontroller
+//does not.
 
 import (
 	"encoding/hex"
@@ -45,6 +46,19 @@ func GetCCHandlerKey() string {
 	return "CCHANDLER"
 }
 
+// ResourceLimits are the limits on the resources of a chaincode container,
+// enforced by the vms supporting them. Zero values mean no limit
+type ResourceLimits struct {
+	CPUShares int64
+	Memory    int64
+}
+
+// GetResourceLimitsKey is used to pass the ResourceLimits of the chaincode
+// to start via context
+func GetResourceLimitsKey() string {
+	return "CCRESOURCELIMITS"
+}
+
 //CCID encapsulates chaincode ID
 type CCID struct {
 	ChaincodeSpec *pb.ChaincodeSpec
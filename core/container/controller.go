@@ -26,6 +26,8 @@ import (
 	"github.com/hyperledger/fabric/core/container/api"
 	"github.com/hyperledger/fabric/core/container/ccintf"
 	"github.com/hyperledger/fabric/core/container/dockercontroller"
+	"github.com/hyperledger/fabric/core/container/externalbuilder"
+	"github.com/hyperledger/fabric/core/container/externalcontroller"
 	"github.com/hyperledger/fabric/core/container/inproccontroller"
 )
 
@@ -49,8 +51,10 @@ var vmcontroller *VMController
 
 //constants for supported containers
 const (
-	DOCKER = "Docker"
-	SYSTEM = "System"
+	DOCKER          = "Docker"
+	SYSTEM          = "System"
+	EXTERNAL        = "External"
+	EXTERNALBUILDER = "ExternalBuilder"
 )
 
 //NewVMController - creates/returns singleton
@@ -69,6 +73,10 @@ func (vmc *VMController) newVM(typ string) api.VM {
 		v = &dockercontroller.DockerVM{}
 	case SYSTEM:
 		v = &inproccontroller.InprocVM{}
+	case EXTERNAL:
+		v = &externalcontroller.ExternalVM{}
+	case EXTERNALBUILDER:
+		v = &externalbuilder.ExternalBuilderVM{}
 	default:
 		v = &dockercontroller.DockerVM{}
 	}
@@ -107,9 +107,35 @@ func getDockerHostConfig() *docker.HostConfig {
 	return hostConfig
 }
 
+//getContainerHostConfig returns the host config of the container of a chaincode,
+//applying the resource limits passed in the context to the configured one. The
+//cpu shares of the chaincode replace the configured ones while the lower of the
+//memory limits wins
+func getContainerHostConfig(ctxt context.Context) *docker.HostConfig {
+	hostConfig := getDockerHostConfig()
+	limits, ok := ctxt.Value(ccintf.GetResourceLimitsKey()).(ccintf.ResourceLimits)
+	if !ok || (limits.CPUShares <= 0 && limits.Memory <= 0) {
+		return hostConfig
+	}
+
+	containerHostConfig := *hostConfig
+	if limits.CPUShares > 0 {
+		containerHostConfig.CPUShares = limits.CPUShares
+	}
+	if limits.Memory > 0 && (containerHostConfig.Memory <= 0 || limits.Memory < containerHostConfig.Memory) {
+		containerHostConfig.Memory = limits.Memory
+		//docker rejects a memory+swap limit lower than the memory one
+		if containerHostConfig.MemorySwap > 0 && containerHostConfig.MemorySwap < limits.Memory {
+			containerHostConfig.MemorySwap = limits.Memory
+		}
+	}
+	dockerLogger.Debugf("docker container hostconfig CpuShares: %d, Memory: %d", containerHostConfig.CPUShares, containerHostConfig.Memory)
+	return &containerHostConfig
+}
+
 func (vm *DockerVM) createContainer(ctxt context.Context, client *docker.Client, imageID string, containerID string, args []string, env []string, attachStdout bool) error {
 	config := docker.Config{Cmd: args, Image: imageID, Env: env, AttachStdout: attachStdout, AttachStderr: attachStdout}
-	copts := docker.CreateContainerOptions{Name: containerID, Config: &config, HostConfig: getDockerHostConfig()}
+	copts := docker.CreateContainerOptions{Name: containerID, Config: &config, HostConfig: getContainerHostConfig(ctxt)}
 	dockerLogger.Debugf("Create container: %s", containerID)
 	_, err := client.CreateContainer(copts)
 	if err != nil {
@@ -25,7 +25,9 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/container/ccintf"
 	coreutil "github.com/hyperledger/fabric/core/testutil"
+	"golang.org/x/net/context"
 )
 
 func TestHostConfig(t *testing.T) {
@@ -54,3 +56,23 @@ func TestGetDockerHostConfig(t *testing.T) {
 	testutil.AssertEquals(t, hostConfig.Memory, int64(1024*1024*1024*2))
 	testutil.AssertEquals(t, hostConfig.CPUShares, int64(1024*1024*1024*2))
 }
+
+func TestGetContainerHostConfig(t *testing.T) {
+	coreutil.SetupTestConfig()
+	hostConfig := getDockerHostConfig()
+
+	// without limits the configured host config is used
+	testutil.AssertEquals(t, getContainerHostConfig(context.Background()), hostConfig)
+
+	limits := ccintf.ResourceLimits{CPUShares: 512, Memory: 256 * 1024 * 1024}
+	ctxt := context.WithValue(context.Background(), ccintf.GetResourceLimitsKey(), limits)
+	containerHostConfig := getContainerHostConfig(ctxt)
+	testutil.AssertEquals(t, containerHostConfig.CPUShares, int64(512))
+	testutil.AssertEquals(t, containerHostConfig.Memory, int64(256*1024*1024))
+	testutil.AssertEquals(t, containerHostConfig.NetworkMode, hostConfig.NetworkMode)
+
+	// a memory limit higher than the configured one is ignored
+	limits = ccintf.ResourceLimits{Memory: hostConfig.Memory + 1}
+	ctxt = context.WithValue(context.Background(), ccintf.GetResourceLimitsKey(), limits)
+	testutil.AssertEquals(t, getContainerHostConfig(ctxt).Memory, hostConfig.Memory)
+}
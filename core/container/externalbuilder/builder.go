@@ -0,0 +1,275 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalbuilder
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/hyperledger/fabric/core/config"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
+)
+
+// buildersKey is the configuration of the external builders in core.yaml
+const buildersKey = "chaincode.externalBuilders"
+
+// DefaultEnvWhitelist lists the environment variables of the peer passed to
+// every builder executable, in addition to the builder's own whitelist
+var DefaultEnvWhitelist = []string{"LD_LIBRARY_PATH", "LIBPATH", "PATH", "TMPDIR"}
+
+// Builder is an external builder. It is a directory whose bin subdirectory
+// holds the detect, build, release and run executables:
+//
+//	detect SOURCE METADATA exits with 0 if the builder claims the package
+//	build SOURCE METADATA OUTPUT builds the package into OUTPUT
+//	release OUTPUT RELEASE, which is optional, provides information about
+//	  the built chaincode in RELEASE
+//	run OUTPUT RUN_METADATA runs the chaincode until the peer stops it
+type Builder struct {
+	Name                 string   `mapstructure:"name"`
+	Path                 string   `mapstructure:"path"`
+	EnvironmentWhitelist []string `mapstructure:"environmentWhitelist"`
+}
+
+// GetBuilders returns the external builders configured in core.yaml, in the
+// order they are tried in
+func GetBuilders() ([]*Builder, error) {
+	var builders []*Builder
+	if err := viper.UnmarshalKey(buildersKey, &builders); err != nil {
+		return nil, fmt.Errorf("Error reading external builders configuration: %s", err)
+	}
+	for _, builder := range builders {
+		if builder.Path == "" {
+			return nil, fmt.Errorf("No path configured for external builder %s", builder.Name)
+		}
+		builder.Path = config.TranslatePath(filepath.Dir(viper.ConfigFileUsed()), builder.Path)
+		if builder.Name == "" {
+			builder.Name = filepath.Base(builder.Path)
+		}
+	}
+	return builders, nil
+}
+
+// BuildContext is the directory tree a chaincode package is detected, built,
+// released and run in
+type BuildContext struct {
+	ScratchDir  string
+	SourceDir   string
+	MetadataDir string
+	OutputDir   string
+	ReleaseDir  string
+	RunDir      string
+}
+
+// packageMetadata is written to metadata.json in the metadata directory
+type packageMetadata struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Path    string `json:"path"`
+	Type    string `json:"type"`
+}
+
+// NewBuildContext extracts the package of the chaincode into a fresh build
+// context under root
+func NewBuildContext(root string, cds *pb.ChaincodeDeploymentSpec) (*BuildContext, error) {
+	ccID := cds.ChaincodeSpec.ChaincodeId
+	scratchDir := filepath.Join(root, fmt.Sprintf("%s-%s", ccID.Name, ccID.Version))
+	bc := &BuildContext{
+		ScratchDir:  scratchDir,
+		SourceDir:   filepath.Join(scratchDir, "source"),
+		MetadataDir: filepath.Join(scratchDir, "metadata"),
+		OutputDir:   filepath.Join(scratchDir, "output"),
+		ReleaseDir:  filepath.Join(scratchDir, "release"),
+		RunDir:      filepath.Join(scratchDir, "run"),
+	}
+
+	if err := os.RemoveAll(scratchDir); err != nil {
+		return nil, fmt.Errorf("Error removing build directory %s: %s", scratchDir, err)
+	}
+	for _, dir := range []string{bc.SourceDir, bc.MetadataDir, bc.OutputDir, bc.ReleaseDir, bc.RunDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("Error creating build directory %s: %s", dir, err)
+		}
+	}
+
+	if err := untar(cds.CodePackage, bc.SourceDir); err != nil {
+		return nil, fmt.Errorf("Error extracting the package of %s: %s", ccID.Name, err)
+	}
+
+	metadata, err := json.Marshal(&packageMetadata{
+		Name:    ccID.Name,
+		Version: ccID.Version,
+		Path:    ccID.Path,
+		Type:    cds.ChaincodeSpec.Type.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(bc.MetadataDir, "metadata.json"), metadata, 0644); err != nil {
+		return nil, fmt.Errorf("Error writing package metadata: %s", err)
+	}
+	return bc, nil
+}
+
+// untar extracts a gzipped tar code package into dir
+func untar(codePackage []byte, dir string) error {
+	gr, err := gzip.NewReader(bytes.NewReader(codePackage))
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := filepath.Clean(header.Name)
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("Illegal file name %s in package", header.Name)
+		}
+		target := filepath.Join(dir, name)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg, tar.TypeRegA:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(header.Mode)&0755|0600)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			f.Close()
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("Unsupported type of file %s in package", header.Name)
+		}
+	}
+}
+
+func (b *Builder) executable(name string) string {
+	return filepath.Join(b.Path, "bin", name)
+}
+
+// environment returns the whitelisted environment of the peer followed by env
+func (b *Builder) environment(env []string) []string {
+	var whitelisted []string
+	for _, key := range append(DefaultEnvWhitelist, b.EnvironmentWhitelist...) {
+		if value, ok := os.LookupEnv(key); ok {
+			whitelisted = append(whitelisted, key+"="+value)
+		}
+	}
+	return append(whitelisted, env...)
+}
+
+func (b *Builder) command(name string, args ...string) *exec.Cmd {
+	cmd := exec.Command(b.executable(name), args...)
+	cmd.Env = b.environment(nil)
+	return cmd
+}
+
+// Detect returns true if the builder claims the package of the build context
+func (b *Builder) Detect(bc *BuildContext) bool {
+	err := b.command("detect", bc.SourceDir, bc.MetadataDir).Run()
+	if err != nil {
+		logger.Debugf("external builder %s does not claim %s: %s", b.Name, bc.SourceDir, err)
+		return false
+	}
+	return true
+}
+
+// Build builds the package of the build context into its output directory
+func (b *Builder) Build(bc *BuildContext) error {
+	output, err := b.command("build", bc.SourceDir, bc.MetadataDir, bc.OutputDir).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("External builder %s failed to build %s: %s (output = %s)", b.Name, bc.SourceDir, err, output)
+	}
+	logger.Debugf("external builder %s built %s: %s", b.Name, bc.SourceDir, output)
+	return nil
+}
+
+// Release runs the release executable of the builder if it has one
+func (b *Builder) Release(bc *BuildContext) error {
+	if _, err := os.Stat(b.executable("release")); os.IsNotExist(err) {
+		return nil
+	}
+	output, err := b.command("release", bc.OutputDir, bc.ReleaseDir).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("External builder %s failed to release %s: %s (output = %s)", b.Name, bc.OutputDir, err, output)
+	}
+	return nil
+}
+
+// Run starts the built chaincode with env added to the environment of the
+// builder. runMetadata is written to chaincode.json in the run directory
+func (b *Builder) Run(bc *BuildContext, runMetadata interface{}, env []string, output io.Writer) (*exec.Cmd, error) {
+	metadata, err := json.Marshal(runMetadata)
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(bc.RunDir, "chaincode.json"), metadata, 0600); err != nil {
+		return nil, fmt.Errorf("Error writing run metadata: %s", err)
+	}
+
+	cmd := b.command("run", bc.OutputDir, bc.RunDir)
+	cmd.Env = b.environment(env)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("External builder %s failed to run %s: %s", b.Name, bc.OutputDir, err)
+	}
+	return cmd, nil
+}
+
+// logWriter logs every line written to it
+type logWriter struct {
+	prefix string
+	buf    bytes.Buffer
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	for {
+		line, err := w.buf.ReadString('\n')
+		if err != nil {
+			// keep the incomplete line until the rest of it is written
+			w.buf.WriteString(line)
+			return len(p), nil
+		}
+		logger.Infof("%s %s", w.prefix, strings.TrimSuffix(line, "\n"))
+	}
+}
@@ -0,0 +1,284 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalbuilder
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/config"
+	container "github.com/hyperledger/fabric/core/container/api"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"golang.org/x/net/context"
+)
+
+var (
+	logger = flogging.MustGetLogger("externalbuilder")
+
+	// lock guards the maps below, it is never held while a builder runs
+	lock sync.Mutex
+	// pkgLocks serialize, by package ID, the detection and build of a package
+	pkgLocks = make(map[string]*sync.Mutex)
+	// detected holds, by package ID, the builder claiming each package
+	// offered to the builders, or nil if none of them claims it
+	detected = make(map[string]*detection)
+	// instRegistry holds the running chaincodes by vm name
+	instRegistry = make(map[string]*instance)
+)
+
+type detection struct {
+	builder *Builder
+	bc      *BuildContext
+	built   bool
+}
+
+type instance struct {
+	cmd  *exec.Cmd
+	done chan struct{}
+}
+
+// runMetadata is written to chaincode.json in the run directory
+type runMetadata struct {
+	ChaincodeID string `json:"chaincode_id"`
+	PeerAddress string `json:"peer_address"`
+}
+
+func packageID(name string, version string) string {
+	return name + ":" + version
+}
+
+// packageLock returns the lock serializing the detection and build of the
+// package pkgID
+func packageLock(pkgID string) *sync.Mutex {
+	lock.Lock()
+	defer lock.Unlock()
+	l, ok := pkgLocks[pkgID]
+	if !ok {
+		l = &sync.Mutex{}
+		pkgLocks[pkgID] = l
+	}
+	return l
+}
+
+func getDetection(pkgID string) (*detection, bool) {
+	lock.Lock()
+	defer lock.Unlock()
+	d, ok := detected[pkgID]
+	return d, ok
+}
+
+func setDetection(pkgID string, d *detection) {
+	lock.Lock()
+	detected[pkgID] = d
+	lock.Unlock()
+}
+
+// Claimed returns true if an external builder claimed the package of the
+// chaincode name:version when it was offered to them. Unlike Detect it
+// never offers the package to the builders
+func Claimed(name string, version string) bool {
+	d, _ := getDetection(packageID(name, version))
+	return d != nil
+}
+
+// buildRoot returns the directory the build contexts are created in
+func buildRoot() string {
+	return filepath.Join(config.GetPath("peer.fileSystemPath"), "externalbuilds")
+}
+
+// Detect returns true if one of the configured external builders claims the
+// package of the chaincode. A package is only offered to the builders once,
+// the builder claiming it builds and runs it from then on
+func Detect(cds *pb.ChaincodeDeploymentSpec) (bool, error) {
+	ccID := cds.ChaincodeSpec.ChaincodeId
+	pkgID := packageID(ccID.Name, ccID.Version)
+
+	pkgLock := packageLock(pkgID)
+	pkgLock.Lock()
+	defer pkgLock.Unlock()
+	if d, ok := getDetection(pkgID); ok {
+		return d != nil, nil
+	}
+	if len(cds.CodePackage) == 0 {
+		return false, nil
+	}
+
+	builders, err := GetBuilders()
+	if err != nil || len(builders) == 0 {
+		return false, err
+	}
+
+	bc, err := NewBuildContext(buildRoot(), cds)
+	if err != nil {
+		return false, err
+	}
+	for _, builder := range builders {
+		if builder.Detect(bc) {
+			logger.Infof("External builder %s claims chaincode %s", builder.Name, pkgID)
+			setDetection(pkgID, &detection{builder: builder, bc: bc})
+			return true, nil
+		}
+	}
+
+	logger.Debugf("no external builder claims chaincode %s", pkgID)
+	setDetection(pkgID, nil)
+	return false, os.RemoveAll(bc.ScratchDir)
+}
+
+func envValue(env []string, key string) string {
+	for _, v := range env {
+		if strings.HasPrefix(v, key+"=") {
+			return strings.TrimPrefix(v, key+"=")
+		}
+	}
+	return ""
+}
+
+// ExternalBuilderVM is a vm for the chaincodes claimed by an external
+// builder. They are built and run by the executables of the builder instead
+// of in Docker containers
+type ExternalBuilderVM struct {
+}
+
+// Deploy does nothing, the package is built when the chaincode is started
+func (vm *ExternalBuilderVM) Deploy(ctxt context.Context, ccid ccintf.CCID, args []string, env []string, reader io.Reader) error {
+	return nil
+}
+
+// Start builds the package of the chaincode, unless it was built before, and
+// runs it with env. The chaincode then connects to the peer and registers
+func (vm *ExternalBuilderVM) Start(ctxt context.Context, ccid ccintf.CCID, args []string, env []string, builder container.BuildSpecFactory) error {
+	instName, _ := vm.GetVMName(ccid)
+	pkgID := packageID(ccid.ChaincodeSpec.ChaincodeId.Name, ccid.Version)
+
+	// the package lock is held until the chaincode is registered as running,
+	// other packages are built and started meanwhile
+	pkgLock := packageLock(pkgID)
+	pkgLock.Lock()
+	defer pkgLock.Unlock()
+
+	lock.Lock()
+	_, running := instRegistry[instName]
+	d := detected[pkgID]
+	lock.Unlock()
+
+	if running {
+		return fmt.Errorf("chaincode %s is already running", instName)
+	}
+	if d == nil {
+		return fmt.Errorf("no external builder claims chaincode %s", pkgID)
+	}
+
+	if !d.built {
+		if err := d.builder.Build(d.bc); err != nil {
+			return err
+		}
+		if err := d.builder.Release(d.bc); err != nil {
+			return err
+		}
+		d.built = true
+	}
+
+	metadata := &runMetadata{
+		ChaincodeID: envValue(env, "CORE_CHAINCODE_ID_NAME"),
+		PeerAddress: envValue(env, "CORE_PEER_ADDRESS"),
+	}
+	cmd, err := d.builder.Run(d.bc, metadata, env, &logWriter{prefix: instName})
+	if err != nil {
+		return err
+	}
+
+	inst := &instance{cmd: cmd, done: make(chan struct{})}
+	lock.Lock()
+	instRegistry[instName] = inst
+	lock.Unlock()
+	logger.Debugf("chaincode %s started by external builder %s", instName, d.builder.Name)
+
+	go func() {
+		err := cmd.Wait()
+		logger.Infof("Chaincode %s exited: %v", instName, err)
+		close(inst.done)
+
+		lock.Lock()
+		if instRegistry[instName] == inst {
+			delete(instRegistry, instName)
+		}
+		lock.Unlock()
+	}()
+
+	return nil
+}
+
+// Stop terminates the chaincode, killing it if it is still running after
+// timeout seconds
+func (vm *ExternalBuilderVM) Stop(ctxt context.Context, ccid ccintf.CCID, timeout uint, dontkill bool, dontremove bool) error {
+	instName, _ := vm.GetVMName(ccid)
+
+	lock.Lock()
+	inst, ok := instRegistry[instName]
+	delete(instRegistry, instName)
+	lock.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%s not running", instName)
+	}
+
+	if timeout > 0 {
+		inst.cmd.Process.Signal(syscall.SIGTERM)
+		select {
+		case <-inst.done:
+			return nil
+		case <-time.After(time.Duration(timeout) * time.Second):
+		}
+	}
+	inst.cmd.Process.Kill()
+	<-inst.done
+	return nil
+}
+
+// Destroy removes the build of the package of the chaincode, it is offered
+// to the builders again on the next launch
+func (vm *ExternalBuilderVM) Destroy(ctxt context.Context, ccid ccintf.CCID, force bool, noprune bool) error {
+	pkgID := packageID(ccid.ChaincodeSpec.ChaincodeId.Name, ccid.Version)
+
+	pkgLock := packageLock(pkgID)
+	pkgLock.Lock()
+	defer pkgLock.Unlock()
+
+	lock.Lock()
+	d, ok := detected[pkgID]
+	delete(detected, pkgID)
+	lock.Unlock()
+	if !ok || d == nil {
+		return nil
+	}
+	return os.RemoveAll(d.bc.ScratchDir)
+}
+
+// GetVMName ignores the peer and network name as it just needs to be unique in process
+func (vm *ExternalBuilderVM) GetVMName(ccid ccintf.CCID) (string, error) {
+	return ccid.GetName(), nil
+}
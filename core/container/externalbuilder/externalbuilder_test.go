@@ -0,0 +1,228 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalbuilder
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+)
+
+// testPackage returns a gzipped tar code package holding files
+func testPackage(t *testing.T, files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}
+		testutil.AssertNoError(t, tw.WriteHeader(header), "")
+		_, err := tw.Write([]byte(content))
+		testutil.AssertNoError(t, err, "")
+	}
+	testutil.AssertNoError(t, tw.Close(), "")
+	testutil.AssertNoError(t, gw.Close(), "")
+	return buf.Bytes()
+}
+
+func testDeploymentSpec(t *testing.T, name string, files map[string]string) *pb.ChaincodeDeploymentSpec {
+	return &pb.ChaincodeDeploymentSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_NODE,
+			ChaincodeId: &pb.ChaincodeID{Name: name, Version: "1.0", Path: "chaincode/" + name},
+		},
+		CodePackage: testPackage(t, files),
+	}
+}
+
+func setupTestBuilders(t *testing.T) func() {
+	fsPath, err := ioutil.TempDir("", "externalbuilder")
+	testutil.AssertNoError(t, err, "")
+	viper.Set("peer.fileSystemPath", fsPath)
+	viper.Set(buildersKey, []interface{}{
+		map[string]interface{}{"name": "test", "path": "testdata/builder"},
+	})
+	return func() {
+		viper.Set(buildersKey, nil)
+		viper.Set("peer.fileSystemPath", "")
+		os.RemoveAll(fsPath)
+	}
+}
+
+func TestGetBuilders(t *testing.T) {
+	defer viper.Set(buildersKey, nil)
+
+	builders, err := GetBuilders()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, len(builders), 0)
+
+	viper.Set(buildersKey, []interface{}{
+		map[string]interface{}{"name": "node", "path": "/opt/builders/node", "environmentWhitelist": []interface{}{"HOME"}},
+		map[string]interface{}{"path": "/opt/builders/rust"},
+	})
+	builders, err = GetBuilders()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, builders, []*Builder{
+		{Name: "node", Path: "/opt/builders/node", EnvironmentWhitelist: []string{"HOME"}},
+		{Name: "rust", Path: "/opt/builders/rust"},
+	})
+
+	viper.Set(buildersKey, []interface{}{map[string]interface{}{"name": "nopath"}})
+	_, err = GetBuilders()
+	testutil.AssertError(t, err, "Expected an error for a builder without path")
+}
+
+func TestNewBuildContext(t *testing.T) {
+	root, err := ioutil.TempDir("", "externalbuilder")
+	testutil.AssertNoError(t, err, "")
+	defer os.RemoveAll(root)
+
+	cds := testDeploymentSpec(t, "ctxcc", map[string]string{"src/main.js": "main", "package.json": "{}"})
+	bc, err := NewBuildContext(root, cds)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, bc.ScratchDir, filepath.Join(root, "ctxcc-1.0"))
+
+	content, err := ioutil.ReadFile(filepath.Join(bc.SourceDir, "src", "main.js"))
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, string(content), "main")
+
+	metadata := &packageMetadata{}
+	content, err = ioutil.ReadFile(filepath.Join(bc.MetadataDir, "metadata.json"))
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNoError(t, json.Unmarshal(content, metadata), "")
+	testutil.AssertEquals(t, metadata, &packageMetadata{Name: "ctxcc", Version: "1.0", Path: "chaincode/ctxcc", Type: "NODE"})
+
+	cds = testDeploymentSpec(t, "ctxcc", map[string]string{"../escape": "escape"})
+	_, err = NewBuildContext(root, cds)
+	testutil.AssertError(t, err, "Expected an error for a package writing outside of the source directory")
+}
+
+func TestDetect(t *testing.T) {
+	cds := testDeploymentSpec(t, "detectcc", map[string]string{"chaincode.sh": "exec sleep 60"})
+
+	claimed, err := Detect(cds)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, claimed, false)
+
+	defer setupTestBuilders(t)()
+
+	// Claimed only reports the outcome of an earlier detection
+	testutil.AssertEquals(t, Claimed("detectcc", "1.0"), false)
+	claimed, err = Detect(cds)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, claimed, true)
+	testutil.AssertEquals(t, Claimed("detectcc", "1.0"), true)
+
+	// the outcome of the detection is kept, even without builders
+	viper.Set(buildersKey, nil)
+	claimed, err = Detect(cds)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, claimed, true)
+
+	viper.Set(buildersKey, []interface{}{
+		map[string]interface{}{"name": "test", "path": "testdata/builder"},
+	})
+	cds = testDeploymentSpec(t, "dockercc", map[string]string{"main.go": "package main"})
+	claimed, err = Detect(cds)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, claimed, false)
+	testutil.AssertEquals(t, Claimed("dockercc", "1.0"), false)
+	_, err = os.Stat(filepath.Join(buildRoot(), "dockercc-1.0"))
+	testutil.AssertEquals(t, os.IsNotExist(err), true)
+}
+
+func TestExternalBuilderVM(t *testing.T) {
+	defer setupTestBuilders(t)()
+
+	vm := &ExternalBuilderVM{}
+	cds := testDeploymentSpec(t, "vmcc", map[string]string{"chaincode.sh": "exec sleep 60"})
+	ccid := ccintf.CCID{ChaincodeSpec: cds.ChaincodeSpec, Version: "1.0"}
+	env := []string{"CORE_CHAINCODE_ID_NAME=vmcc:1.0", "CORE_PEER_ADDRESS=peer0:7051"}
+
+	err := vm.Start(context.Background(), ccid, nil, env, nil)
+	testutil.AssertError(t, err, "Expected an error for a package not offered to the builders")
+
+	claimed, err := Detect(cds)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, claimed, true)
+
+	testutil.AssertNoError(t, vm.Start(context.Background(), ccid, nil, env, nil), "")
+	err = vm.Start(context.Background(), ccid, nil, env, nil)
+	testutil.AssertError(t, err, "Expected an error for a running chaincode")
+
+	bc := detected[packageID("vmcc", "1.0")].bc
+	_, err = os.Stat(filepath.Join(bc.ReleaseDir, "release.json"))
+	testutil.AssertNoError(t, err, "")
+
+	runMetadata := &runMetadata{}
+	content, err := ioutil.ReadFile(filepath.Join(bc.RunDir, "chaincode.json"))
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNoError(t, json.Unmarshal(content, runMetadata), "")
+	testutil.AssertEquals(t, runMetadata.ChaincodeID, "vmcc:1.0")
+	testutil.AssertEquals(t, runMetadata.PeerAddress, "peer0:7051")
+
+	// the chaincode is run with the environment supplied by the peer
+	chaincodeIDFile := filepath.Join(bc.RunDir, "chaincode_id")
+	for i := 0; i < 50; i++ {
+		if _, err = os.Stat(chaincodeIDFile); err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	content, err = ioutil.ReadFile(chaincodeIDFile)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, string(content), "vmcc:1.0\n")
+
+	testutil.AssertNoError(t, vm.Stop(context.Background(), ccid, 1, false, false), "")
+	testutil.AssertError(t, vm.Stop(context.Background(), ccid, 1, false, false), "Expected an error for a stopped chaincode")
+
+	// the chaincode is restarted without being built again
+	testutil.AssertNoError(t, os.RemoveAll(bc.SourceDir), "")
+	testutil.AssertNoError(t, vm.Start(context.Background(), ccid, nil, env, nil), "")
+	testutil.AssertNoError(t, vm.Stop(context.Background(), ccid, 0, false, false), "")
+
+	testutil.AssertNoError(t, vm.Destroy(context.Background(), ccid, false, false), "")
+	_, err = os.Stat(bc.ScratchDir)
+	testutil.AssertEquals(t, os.IsNotExist(err), true)
+}
+
+func TestExternalBuilderVMBuildFailure(t *testing.T) {
+	defer setupTestBuilders(t)()
+
+	vm := &ExternalBuilderVM{}
+	cds := testDeploymentSpec(t, "failcc", map[string]string{"chaincode.sh": "exec sleep 60", "fail": ""})
+	ccid := ccintf.CCID{ChaincodeSpec: cds.ChaincodeSpec, Version: "1.0"}
+
+	claimed, err := Detect(cds)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, claimed, true)
+
+	err = vm.Start(context.Background(), ccid, nil, nil, nil)
+	testutil.AssertError(t, err, "Expected an error for a package failing to build")
+	testutil.AssertNoError(t, vm.Destroy(context.Background(), ccid, false, false), "")
+}
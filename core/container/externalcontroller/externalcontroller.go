@@ -0,0 +1,235 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalcontroller
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/core/config"
+	container "github.com/hyperledger/fabric/core/container/api"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// externalConfigKey is the section of the peer configuration holding the
+// chaincodes run as external services, keyed by chaincode name
+const externalConfigKey = "chaincode.external"
+
+var (
+	externalLogger = flogging.MustGetLogger("externalcontroller")
+
+	// instRegistry holds the connections to the running external chaincodes
+	instLock     sync.Mutex
+	instRegistry = make(map[string]*externalChaincode)
+)
+
+// ChaincodeServerConfig describes how the peer connects to a chaincode run
+// as an external service
+type ChaincodeServerConfig struct {
+	// Address of the chaincode server as hostname:port
+	Address string
+	// TLSEnabled is true if the chaincode server uses TLS
+	TLSEnabled bool
+	// RootCertFile holds the CA certificates used to verify the chaincode
+	// server. The system roots are used when it is empty
+	RootCertFile string
+	// ClientCertFile and ClientKeyFile hold the certificate and key the peer
+	// authenticates with when the chaincode server requires client certificates
+	ClientCertFile string
+	ClientKeyFile  string
+	// ServerHostOverride is the name expected in the server certificate
+	// when it differs from the host of Address
+	ServerHostOverride string
+}
+
+func configKey(name string, key string) string {
+	return externalConfigKey + "." + name + "." + key
+}
+
+// IsExternal returns true if the chaincode with the given name is configured
+// to run as an external service
+func IsExternal(name string) bool {
+	return viper.GetString(configKey(name, "address")) != ""
+}
+
+// GetChaincodeServerConfig returns the configuration of the external
+// chaincode with the given name
+func GetChaincodeServerConfig(name string) (*ChaincodeServerConfig, error) {
+	address := viper.GetString(configKey(name, "address"))
+	if address == "" {
+		return nil, fmt.Errorf("No chaincode server address configured for %s", name)
+	}
+	return &ChaincodeServerConfig{
+		Address:            address,
+		TLSEnabled:         viper.GetBool(configKey(name, "tls.enabled")),
+		RootCertFile:       config.GetPath(configKey(name, "tls.rootcert.file")),
+		ClientCertFile:     config.GetPath(configKey(name, "tls.clientCert.file")),
+		ClientKeyFile:      config.GetPath(configKey(name, "tls.clientKey.file")),
+		ServerHostOverride: viper.GetString(configKey(name, "tls.serverhostoverride")),
+	}, nil
+}
+
+// clientCredentials returns the TLS credentials the peer dials the chaincode
+// server with
+func (c *ChaincodeServerConfig) clientCredentials() (credentials.TransportCredentials, error) {
+	tlsConfig := &tls.Config{ServerName: c.ServerHostOverride}
+	if c.RootCertFile != "" {
+		rootCert, err := ioutil.ReadFile(c.RootCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading chaincode server root certificate: %s", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(rootCert) {
+			return nil, fmt.Errorf("No certificate found in %s", c.RootCertFile)
+		}
+	}
+	if c.ClientCertFile != "" || c.ClientKeyFile != "" {
+		clientCert, err := tls.LoadX509KeyPair(c.ClientCertFile, c.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error loading chaincode client certificate: %s", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+func (c *ChaincodeServerConfig) dial() (*grpc.ClientConn, error) {
+	if !c.TLSEnabled {
+		return comm.NewClientConnectionWithAddress(c.Address, true, false, nil)
+	}
+	creds, err := c.clientCredentials()
+	if err != nil {
+		return nil, err
+	}
+	return comm.NewClientConnectionWithAddress(c.Address, true, true, creds)
+}
+
+// externalChaincode is the connection to a running external chaincode
+type externalChaincode struct {
+	conn   *grpc.ClientConn
+	cancel context.CancelFunc
+}
+
+func (ec *externalChaincode) close() {
+	ec.cancel()
+	ec.conn.Close()
+}
+
+// ExternalVM is a vm for chaincodes that are built, run and managed outside
+// of the peer. Instead of starting the chaincode and waiting for it to
+// register, the peer connects to the chaincode server configured for it
+type ExternalVM struct {
+}
+
+// Deploy does nothing, external chaincodes are deployed by their operators
+func (vm *ExternalVM) Deploy(ctxt context.Context, ccid ccintf.CCID, args []string, env []string, reader io.Reader) error {
+	return nil
+}
+
+// Start connects to the chaincode server and hands the stream over to the
+// chaincode support, exactly as a stream opened by the chaincode through
+// Register. The chaincode then registers on the stream
+func (vm *ExternalVM) Start(ctxt context.Context, ccid ccintf.CCID, args []string, env []string, builder container.BuildSpecFactory) error {
+	ccSupport, ok := ctxt.Value(ccintf.GetCCHandlerKey()).(ccintf.CCSupport)
+	if !ok || ccSupport == nil {
+		return fmt.Errorf("chaincode support not supplied")
+	}
+
+	name := ccid.ChaincodeSpec.ChaincodeId.Name
+	cfg, err := GetChaincodeServerConfig(name)
+	if err != nil {
+		return err
+	}
+
+	instName, _ := vm.GetVMName(ccid)
+
+	instLock.Lock()
+	defer instLock.Unlock()
+	if _, ok := instRegistry[instName]; ok {
+		return fmt.Errorf("chaincode %s is already connected", instName)
+	}
+
+	conn, err := cfg.dial()
+	if err != nil {
+		return fmt.Errorf("Error dialing chaincode %s at %s: %s", instName, cfg.Address, err)
+	}
+	streamCtxt, cancel := context.WithCancel(context.Background())
+	stream, err := pb.NewChaincodeClient(conn).Connect(streamCtxt)
+	if err != nil {
+		cancel()
+		conn.Close()
+		return fmt.Errorf("Error connecting to chaincode %s at %s: %s", instName, cfg.Address, err)
+	}
+
+	ec := &externalChaincode{conn: conn, cancel: cancel}
+	instRegistry[instName] = ec
+	externalLogger.Debugf("connected to chaincode %s at %s", instName, cfg.Address)
+
+	go func() {
+		err := ccSupport.HandleChaincodeStream(stream.Context(), stream)
+		if err != nil {
+			externalLogger.Errorf("chaincode %s ended with err: %s", instName, err)
+		}
+		externalLogger.Debugf("chaincode-support ended for %s", instName)
+
+		ec.close()
+		instLock.Lock()
+		if instRegistry[instName] == ec {
+			delete(instRegistry, instName)
+		}
+		instLock.Unlock()
+	}()
+
+	return nil
+}
+
+// Stop disconnects from the chaincode. The chaincode itself keeps running
+func (vm *ExternalVM) Stop(ctxt context.Context, ccid ccintf.CCID, timeout uint, dontkill bool, dontremove bool) error {
+	instName, _ := vm.GetVMName(ccid)
+
+	instLock.Lock()
+	ec, ok := instRegistry[instName]
+	delete(instRegistry, instName)
+	instLock.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%s not connected", instName)
+	}
+	ec.close()
+	return nil
+}
+
+// Destroy does nothing, external chaincodes are removed by their operators
+func (vm *ExternalVM) Destroy(ctxt context.Context, ccid ccintf.CCID, force bool, noprune bool) error {
+	return nil
+}
+
+// GetVMName ignores the peer and network name as it just needs to be unique in process
+func (vm *ExternalVM) GetVMName(ccid ccintf.CCID) (string, error) {
+	return ccid.GetName(), nil
+}
@@ -0,0 +1,191 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalcontroller
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+)
+
+type testChaincode struct {
+}
+
+func (cc *testChaincode) Init(stub shim.ChaincodeStubInterface) pb.Response {
+	return shim.Success(nil)
+}
+
+func (cc *testChaincode) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
+	return shim.Success(nil)
+}
+
+// testCCSupport records the messages of the streams handed over by the vm
+type testCCSupport struct {
+	msgs chan *pb.ChaincodeMessage
+	done chan struct{}
+}
+
+func (s *testCCSupport) HandleChaincodeStream(ctxt context.Context, stream ccintf.ChaincodeStream) error {
+	defer close(s.done)
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		s.msgs <- msg
+	}
+}
+
+func freeAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "localhost:0")
+	testutil.AssertNoError(t, err, "")
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+func setConfig(name string, values map[string]interface{}) {
+	for key, value := range values {
+		viper.Set(configKey(name, key), value)
+	}
+}
+
+func TestGetChaincodeServerConfig(t *testing.T) {
+	defer setConfig("mycc", map[string]interface{}{"address": "", "tls.enabled": false, "tls.rootcert.file": "", "tls.serverhostoverride": ""})
+	setConfig("mycc", map[string]interface{}{
+		"address":                "mycc.example.com:9999",
+		"tls.enabled":            true,
+		"tls.rootcert.file":      "/etc/mycc/ca.pem",
+		"tls.serverhostoverride": "mycc",
+	})
+
+	testutil.AssertEquals(t, IsExternal("mycc"), true)
+	testutil.AssertEquals(t, IsExternal("othercc"), false)
+
+	cfg, err := GetChaincodeServerConfig("mycc")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, cfg, &ChaincodeServerConfig{
+		Address:            "mycc.example.com:9999",
+		TLSEnabled:         true,
+		RootCertFile:       "/etc/mycc/ca.pem",
+		ServerHostOverride: "mycc",
+	})
+
+	_, err = GetChaincodeServerConfig("othercc")
+	testutil.AssertError(t, err, "Expected an error for a chaincode without server address")
+}
+
+func TestClientCredentials(t *testing.T) {
+	certs := "../../comm/testdata/certs/"
+	cfg := &ChaincodeServerConfig{
+		RootCertFile:   certs + "Org1-cert.pem",
+		ClientCertFile: certs + "Org1-client1-cert.pem",
+		ClientKeyFile:  certs + "Org1-client1-key.pem",
+	}
+	creds, err := cfg.clientCredentials()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, creds.Info().SecurityProtocol, "tls")
+
+	cfg.RootCertFile = certs + "Org1-client1-key.pem"
+	_, err = cfg.clientCredentials()
+	testutil.AssertError(t, err, "Expected an error for a root certificate file without certificate")
+
+	cfg.RootCertFile = certs + "Org1-cert.pem"
+	cfg.ClientKeyFile = certs + "missing-key.pem"
+	_, err = cfg.clientCredentials()
+	testutil.AssertError(t, err, "Expected an error for a missing client key")
+}
+
+func TestExternalVMStartStop(t *testing.T) {
+	address := freeAddress(t)
+	defer viper.Set(configKey("mycc", "address"), "")
+	viper.Set(configKey("mycc", "address"), address)
+
+	server := &shim.ChaincodeServer{CCID: "mycc:1.0", Address: address, CC: &testChaincode{}}
+	go server.Start()
+
+	vm := &ExternalVM{}
+	ccid := ccintf.CCID{ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "mycc"}}, Version: "1.0"}
+
+	err := vm.Start(context.Background(), ccid, nil, nil, nil)
+	testutil.AssertError(t, err, "Expected an error when the chaincode support is not supplied")
+
+	ccSupport := &testCCSupport{msgs: make(chan *pb.ChaincodeMessage, 1), done: make(chan struct{})}
+	ctxt := context.WithValue(context.Background(), ccintf.GetCCHandlerKey(), ccSupport)
+	err = vm.Start(ctxt, ccid, nil, nil, nil)
+	testutil.AssertNoError(t, err, "")
+
+	// the chaincode registers on the stream opened by the peer
+	select {
+	case msg := <-ccSupport.msgs:
+		testutil.AssertEquals(t, msg.Type, pb.ChaincodeMessage_REGISTER)
+		chaincodeID := &pb.ChaincodeID{}
+		testutil.AssertNoError(t, proto.Unmarshal(msg.Payload, chaincodeID), "")
+		testutil.AssertEquals(t, chaincodeID.Name, "mycc:1.0")
+	case <-time.After(5 * time.Second):
+		t.Fatal("Chaincode did not register")
+	}
+
+	err = vm.Start(ctxt, ccid, nil, nil, nil)
+	testutil.AssertError(t, err, "Expected an error when the chaincode is already connected")
+
+	testutil.AssertNoError(t, vm.Stop(ctxt, ccid, 0, false, false), "")
+	select {
+	case <-ccSupport.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Chaincode stream was not closed on stop")
+	}
+	testutil.AssertError(t, vm.Stop(ctxt, ccid, 0, false, false), "Expected an error when the chaincode is not connected")
+
+	// the chaincode server keeps serving after the peer disconnected
+	ccSupport = &testCCSupport{msgs: make(chan *pb.ChaincodeMessage, 1), done: make(chan struct{})}
+	ctxt = context.WithValue(context.Background(), ccintf.GetCCHandlerKey(), ccSupport)
+	testutil.AssertNoError(t, vm.Start(ctxt, ccid, nil, nil, nil), "")
+	select {
+	case msg := <-ccSupport.msgs:
+		testutil.AssertEquals(t, msg.Type, pb.ChaincodeMessage_REGISTER)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Chaincode did not register again")
+	}
+	testutil.AssertNoError(t, vm.Stop(ctxt, ccid, 0, false, false), "")
+}
+
+func TestExternalVMStartUnreachable(t *testing.T) {
+	address := freeAddress(t)
+	defer viper.Set(configKey("othercc", "address"), "")
+	viper.Set(configKey("othercc", "address"), address)
+
+	vm := &ExternalVM{}
+	ccSupport := &testCCSupport{msgs: make(chan *pb.ChaincodeMessage, 1), done: make(chan struct{})}
+	ctxt := context.WithValue(context.Background(), ccintf.GetCCHandlerKey(), ccSupport)
+
+	ccid := ccintf.CCID{ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "unknowncc"}}, Version: "1.0"}
+	err := vm.Start(ctxt, ccid, nil, nil, nil)
+	testutil.AssertError(t, err, "Expected an error for a chaincode without server address")
+
+	ccid = ccintf.CCID{ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "othercc"}}, Version: "1.0"}
+	err = vm.Start(ctxt, ccid, nil, nil, nil)
+	testutil.AssertError(t, err, fmt.Sprintf("Expected an error when no chaincode server listens on %s", address))
+}
@@ -150,6 +150,13 @@ func WriteStreamToPackage(is io.Reader, localpath string, packagepath string, tw
 	header.ChangeTime = zeroTime
 	header.Name = packagepath
 	header.Mode = 0100644
+	//nor should the owner of the files: FileInfoHeader takes the uid, gid and
+	//user and group names of the packager, so the same chaincode packaged by
+	//two users would otherwise yield different packages and code hashes
+	header.Uid = 0
+	header.Gid = 0
+	header.Uname = ""
+	header.Gname = ""
 
 	if err = tw.WriteHeader(header); err != nil {
 		return fmt.Errorf("Error write header for (path: %s, oldname:%s,newname:%s,sz:%d) : %s", localpath, oldname, packagepath, header.Size, err)
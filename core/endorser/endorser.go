@@ -32,12 +32,16 @@ import (
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/validation"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/blobstore"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/core/policy"
 	syscc "github.com/hyperledger/fabric/core/scc"
+	"github.com/hyperledger/fabric/gossip/service"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	putils "github.com/hyperledger/fabric/protos/utils"
 )
@@ -47,9 +51,17 @@ var endorserLogger = flogging.MustGetLogger("endorser")
 // The Jira issue that documents Endorser flow along with its relationship to
 // the lifecycle chaincode - https://jira.hyperledger.org/browse/FAB-181
 
+// privateDataDistributor distributes the private data of an endorsed transaction
+type privateDataDistributor func(chainID string, txID string, privData *rwset.TxPvtReadWriteSet) error
+
+// blobDistributor sends the blobs referenced by the writes of an endorsed transaction
+type blobDistributor func(chainID string, hashes [][]byte) error
+
 // Endorser provides the Endorser service ProcessProposal
 type Endorser struct {
-	policyChecker policy.PolicyChecker
+	policyChecker         policy.PolicyChecker
+	distributePrivateData privateDataDistributor
+	distributeBlobs       blobDistributor
 }
 
 // NewEndorserServer creates and returns a new Endorser server instance.
@@ -60,10 +72,44 @@ func NewEndorserServer() pb.EndorserServer {
 		mgmt.GetLocalMSP(),
 		mgmt.NewLocalMSPPrincipalGetter(),
 	)
+	e.distributePrivateData = distributePrivateData
+	e.distributeBlobs = distributeBlobs
 
 	return e
 }
 
+// distributePrivateData persists the private data of a transaction in the transient store
+// of the channel and disseminates it to the peers authorized for its collections
+func distributePrivateData(chainID string, txID string, privData *rwset.TxPvtReadWriteSet) error {
+	store := peer.GetTransientStore(chainID)
+	lgr := peer.GetLedger(chainID)
+	if store == nil || lgr == nil {
+		return fmt.Errorf("No transient store for channel %s", chainID)
+	}
+	info, err := lgr.GetBlockchainInfo()
+	if err != nil {
+		return err
+	}
+	if err = store.Persist(txID, info.Height, privData); err != nil {
+		return err
+	}
+	gossipService := service.GetGossipService()
+	if gossipService == nil {
+		return fmt.Errorf("Gossip service is not initialized, cannot distribute private data of transaction %s", txID)
+	}
+	return gossipService.DistributePrivateData(chainID, txID, privData)
+}
+
+// distributeBlobs sends the blobs streamed by the chaincode during the simulation, which are staged
+// in the blob store of the channel, to the other peers of the channel
+func distributeBlobs(chainID string, hashes [][]byte) error {
+	gossipService := service.GetGossipService()
+	if gossipService == nil {
+		return fmt.Errorf("Gossip service is not initialized, cannot distribute blobs")
+	}
+	return gossipService.DistributeBlobs(chainID, hashes)
+}
+
 // checkACL checks that the supplied proposal complies
 // with the writers policy of the chain
 func (e *Endorser) checkACL(signedProp *pb.SignedProposal, chdr *common.ChannelHeader, shdr *common.SignatureHeader, hdrext *pb.ChaincodeHeaderExtension) error {
@@ -92,10 +138,10 @@ func (*Endorser) getHistoryQueryExecutor(ledgername string) (ledger.HistoryQuery
 }
 
 //call specified chaincode (system or user)
-func (e *Endorser) callChaincode(ctxt context.Context, chainID string, version string, txid string, signedProp *pb.SignedProposal, prop *pb.Proposal, cis *pb.ChaincodeInvocationSpec, cid *pb.ChaincodeID, txsim ledger.TxSimulator) (*pb.Response, *pb.ChaincodeEvent, error) {
+func (e *Endorser) callChaincode(ctxt context.Context, chainID string, version string, txid string, signedProp *pb.SignedProposal, prop *pb.Proposal, cis *pb.ChaincodeInvocationSpec, cid *pb.ChaincodeID, txsim ledger.TxSimulator) (*pb.Response, []*pb.ChaincodeEvent, error) {
 	var err error
 	var res *pb.Response
-	var ccevent *pb.ChaincodeEvent
+	var ccevents []*pb.ChaincodeEvent
 
 	if txsim != nil {
 		ctxt = context.WithValue(ctxt, chaincode.TXSimulatorKey, txsim)
@@ -106,7 +152,7 @@ func (e *Endorser) callChaincode(ctxt context.Context, chainID string, version s
 
 	cccid := ccprovider.NewCCContext(chainID, cid.Name, version, txid, scc, signedProp, prop)
 
-	res, ccevent, err = chaincode.ExecuteChaincode(ctxt, cccid, cis.ChaincodeSpec.Input.Args)
+	res, ccevents, err = chaincode.ExecuteChaincode(ctxt, cccid, cis.ChaincodeSpec.Input.Args)
 
 	if err != nil {
 		return nil, nil, err
@@ -140,6 +186,13 @@ func (e *Endorser) callChaincode(ctxt context.Context, chainID string, version s
 			return nil, nil, fmt.Errorf("attempting to deploy a system chaincode %s/%s", cds.ChaincodeSpec.ChaincodeId.Name, chainID)
 		}
 
+		//launch the chaincode with the resource limits lscc recorded for it
+		cd := &ccprovider.ChaincodeData{}
+		if err = proto.Unmarshal(res.Payload, cd); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal chaincode data of %s - %s", cds.ChaincodeSpec.ChaincodeId.Name, err)
+		}
+		ctxt = context.WithValue(ctxt, chaincode.ResourceLimitsKey, cd.ResourceLimits)
+
 		cccid = ccprovider.NewCCContext(chainID, cds.ChaincodeSpec.ChaincodeId.Name, cds.ChaincodeSpec.ChaincodeId.Version, txid, false, signedProp, prop)
 
 		_, _, err = chaincode.Execute(ctxt, cccid, cds)
@@ -149,22 +202,23 @@ func (e *Endorser) callChaincode(ctxt context.Context, chainID string, version s
 	}
 	//----- END -------
 
-	return res, ccevent, err
+	return res, ccevents, err
 }
 
 //simulate the proposal by calling the chaincode
-func (e *Endorser) simulateProposal(ctx context.Context, chainID string, txid string, signedProp *pb.SignedProposal, prop *pb.Proposal, cid *pb.ChaincodeID, txsim ledger.TxSimulator) (*ccprovider.ChaincodeData, *pb.Response, []byte, *pb.ChaincodeEvent, error) {
+//the reads done on other channels are returned apart from the simulation results
+func (e *Endorser) simulateProposal(ctx context.Context, chainID string, txid string, signedProp *pb.SignedProposal, prop *pb.Proposal, cid *pb.ChaincodeID, txsim ledger.TxSimulator) (*ccprovider.ChaincodeData, *pb.Response, []byte, []byte, []*pb.ChaincodeEvent, error) {
 	//we do expect the payload to be a ChaincodeInvocationSpec
 	//if we are supporting other payloads in future, this be glaringly point
 	//as something that should change
 	cis, err := putils.GetChaincodeInvocationSpec(prop)
 	if err != nil {
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, err
 	}
 
 	//---1. check ESCC and VSCC for the chaincode
 	if err = e.checkEsccAndVscc(prop); err != nil {
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, err
 	}
 
 	var cd *ccprovider.ChaincodeData
@@ -174,27 +228,68 @@ func (e *Endorser) simulateProposal(ctx context.Context, chainID string, txid st
 	if !syscc.IsSysCC(cid.Name) {
 		cd, err = e.getCDSFromLSCC(ctx, chainID, txid, signedProp, prop, cid.Name, txsim)
 		if err != nil {
-			return nil, nil, nil, nil, fmt.Errorf("failed to obtain cds for %s - %s", cid.Name, err)
+			return nil, nil, nil, nil, nil, fmt.Errorf("failed to obtain cds for %s - %s", cid.Name, err)
 		}
 		version = cd.Version
+		ctx = context.WithValue(ctx, chaincode.ResourceLimitsKey, cd.ResourceLimits)
 	}
 
 	//---3. execute the proposal and get simulation results
 	var simResult []byte
+	var crossChannelReadsBytes []byte
 	var res *pb.Response
-	var ccevent *pb.ChaincodeEvent
-	res, ccevent, err = e.callChaincode(ctx, chainID, version, txid, signedProp, prop, cis, cid, txsim)
+	var ccevents []*pb.ChaincodeEvent
+	crossChannelReads := chaincode.NewCrossChannelReads()
+	ctx = context.WithValue(ctx, chaincode.CrossChannelReadsKey, crossChannelReads)
+	res, ccevents, err = e.callChaincode(ctx, chainID, version, txid, signedProp, prop, cis, cid, txsim)
 	if err != nil {
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, err
+	}
+
+	if crossChannelReadsBytes, err = crossChannelReads.Bytes(); err != nil {
+		return nil, nil, nil, nil, nil, err
 	}
 
 	if txsim != nil {
 		if simResult, err = txsim.GetTxSimulationResults(); err != nil {
-			return nil, nil, nil, nil, err
+			return nil, nil, nil, nil, nil, err
+		}
+
+		// private data only ever leaves this peer for a successful simulation
+		if res.Status < shim.ERROR {
+			pvtSimResult, err := txsim.GetTxPvtSimulationResults()
+			if err != nil {
+				return nil, nil, nil, nil, nil, err
+			}
+			if pvtSimResult != nil {
+				if e.distributePrivateData == nil {
+					return nil, nil, nil, nil, nil, fmt.Errorf("Private data is not supported for transaction %s", txid)
+				}
+				if err = e.distributePrivateData(chainID, txid, pvtSimResult); err != nil {
+					return nil, nil, nil, nil, nil, fmt.Errorf("failed to distribute private data for transaction %s - %s", txid, err)
+				}
+			}
+
+			// the transaction only holds the hashes of the blobs streamed by the chaincode,
+			// their content is sent apart to the peers that commit the transaction
+			if simResult != nil {
+				txRWSet := &rwsetutil.TxRwSet{}
+				if err = txRWSet.FromProtoBytes(simResult); err != nil {
+					return nil, nil, nil, nil, nil, err
+				}
+				if hashes := blobstore.References(txRWSet); len(hashes) != 0 {
+					if e.distributeBlobs == nil {
+						return nil, nil, nil, nil, nil, fmt.Errorf("Blobs are not supported for transaction %s", txid)
+					}
+					if err = e.distributeBlobs(chainID, hashes); err != nil {
+						return nil, nil, nil, nil, nil, fmt.Errorf("failed to distribute blobs for transaction %s - %s", txid, err)
+					}
+				}
+			}
 		}
 	}
 
-	return cd, res, simResult, ccevent, nil
+	return cd, res, simResult, crossChannelReadsBytes, ccevents, nil
 }
 
 func (e *Endorser) getCDSFromLSCC(ctx context.Context, chainID string, txid string, signedProp *pb.SignedProposal, prop *pb.Proposal, chaincodeID string, txsim ledger.TxSimulator) (*ccprovider.ChaincodeData, error) {
@@ -207,7 +302,7 @@ func (e *Endorser) getCDSFromLSCC(ctx context.Context, chainID string, txid stri
 }
 
 //endorse the proposal by calling the ESCC
-func (e *Endorser) endorseProposal(ctx context.Context, chainID string, txid string, signedProp *pb.SignedProposal, proposal *pb.Proposal, response *pb.Response, simRes []byte, event *pb.ChaincodeEvent, visibility []byte, ccid *pb.ChaincodeID, txsim ledger.TxSimulator, cd *ccprovider.ChaincodeData) (*pb.ProposalResponse, error) {
+func (e *Endorser) endorseProposal(ctx context.Context, chainID string, txid string, signedProp *pb.SignedProposal, proposal *pb.Proposal, response *pb.Response, simRes []byte, crossChannelReads []byte, events []*pb.ChaincodeEvent, visibility []byte, ccid *pb.ChaincodeID, txsim ledger.TxSimulator, cd *ccprovider.ChaincodeData) (*pb.ProposalResponse, error) {
 	endorserLogger.Debugf("endorseProposal starts for chainID %s, ccid %s", chainID, ccid)
 
 	isSysCC := cd == nil
@@ -228,13 +323,9 @@ func (e *Endorser) endorseProposal(ctx context.Context, chainID string, txid str
 	endorserLogger.Debugf("endorseProposal info: escc for cid %s is %s", ccid, escc)
 
 	// marshalling event bytes
-	var err error
-	var eventBytes []byte
-	if event != nil {
-		eventBytes, err = putils.GetBytesChaincodeEvent(event)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal event bytes - %s", err)
-		}
+	eventBytes, err := putils.GetBytesChaincodeEvents(events)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event bytes - %s", err)
 	}
 
 	resBytes, err := putils.GetBytesResponse(response)
@@ -266,7 +357,11 @@ func (e *Endorser) endorseProposal(ctx context.Context, chainID string, txid str
 	// args[5] - binary blob of simulation results
 	// args[6] - serialized events
 	// args[7] - payloadVisibility
+	// args[8] - reads done on other channels
 	args := [][]byte{[]byte(""), proposal.Header, proposal.Payload, ccidBytes, resBytes, simRes, eventBytes, visibility}
+	if crossChannelReads != nil {
+		args = append(args, crossChannelReads)
+	}
 	version := util.GetSysCCVersion()
 	ecccis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{Type: pb.ChaincodeSpec_GOLANG, ChaincodeId: &pb.ChaincodeID{Name: escc}, Input: &pb.ChaincodeInput{Args: args}}}
 	res, _, err := e.callChaincode(ctx, chainID, version, txid, signedProp, proposal, ecccis, &pb.ChaincodeID{Name: escc}, txsim)
@@ -386,8 +481,11 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 	//       to validate the supplied action before endorsing it
 
 	//1 -- simulate
-	cd, res, simulationResult, ccevent, err := e.simulateProposal(ctx, chainID, txid, signedProp, prop, hdrExt.ChaincodeId, txsim)
-	if err != nil {
+	cd, res, simulationResult, crossChannelReads, ccevents, err := e.simulateProposal(ctx, chainID, txid, signedProp, prop, hdrExt.ChaincodeId, txsim)
+	if _, ok := err.(*chaincode.ResourceLimitError); ok {
+		// the chaincode did not fail, the peer declined to run it further
+		return &pb.ProposalResponse{Response: &pb.Response{Status: int32(common.Status_SERVICE_UNAVAILABLE), Message: err.Error()}}, err
+	} else if err != nil {
 		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
 	}
 
@@ -399,7 +497,7 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 	if chainID == "" {
 		pResp = &pb.ProposalResponse{Response: res}
 	} else {
-		pResp, err = e.endorseProposal(ctx, chainID, txid, signedProp, prop, res, simulationResult, ccevent, hdrExt.PayloadVisibility, hdrExt.ChaincodeId, txsim, cd)
+		pResp, err = e.endorseProposal(ctx, chainID, txid, signedProp, prop, res, simulationResult, crossChannelReads, ccevents, hdrExt.PayloadVisibility, hdrExt.ChaincodeId, txsim, cd)
 		if err != nil {
 			return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
 		}
@@ -0,0 +1,747 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blobstore
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	ledgerutil "github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	putils "github.com/hyperledger/fabric/protos/utils"
+)
+
+var logger = flogging.MustGetLogger("blobstore")
+
+// referencePrefix starts the state value of a key whose value is kept in the blob store. The prefix is followed
+// by the SHA-256 hash of the value, so that only the hash of the value is recorded in the read-write set
+var referencePrefix = []byte("\x00blob:sha256:")
+
+// tmpDir is the directory of a store holding the blobs being written or received
+const tmpDir = "tmp"
+
+// stagedDir is the directory of a store holding the blobs streamed during the simulation of a transaction
+// or received from other peers, until a transaction referencing them is committed
+const stagedDir = "staged"
+
+// stagedBlobExpiry is the time after which a staged or partly received blob that has not been committed is removed
+const stagedBlobExpiry = time.Hour
+
+var savePointKey = []byte{0x00}
+var indexKeyPrefix = []byte("i")
+var refCountKeyPrefix = []byte("r")
+var compositeKeySep = []byte{0x00}
+
+// Reference returns the state value referencing the blob with the given hash
+func Reference(hash []byte) []byte {
+	return append(append([]byte{}, referencePrefix...), hash...)
+}
+
+// ParseReference returns the hash of the blob referenced by a state value. The returned boolean is false
+// if the value is not a reference to a blob
+func ParseReference(value []byte) ([]byte, bool) {
+	if len(value) != len(referencePrefix)+sha256.Size || !bytes.HasPrefix(value, referencePrefix) {
+		return nil, false
+	}
+	return value[len(referencePrefix):], true
+}
+
+// IsReference returns true if the state value is a reference to a blob
+func IsReference(value []byte) bool {
+	return bytes.HasPrefix(value, referencePrefix)
+}
+
+// References returns the hashes of the blobs referenced by the writes of a transaction
+func References(txRWSet *rwsetutil.TxRwSet) [][]byte {
+	var hashes [][]byte
+	found := make(map[string]bool)
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+			if kvWrite.IsDelete {
+				continue
+			}
+			if hash, ok := ParseReference(kvWrite.Value); ok && !found[string(hash)] {
+				found[string(hash)] = true
+				hashes = append(hashes, hash)
+			}
+		}
+	}
+	return hashes
+}
+
+// BlockReferences returns the hashes of the blobs referenced by the writes of the valid transactions of a block
+func BlockReferences(block *common.Block) ([][]byte, error) {
+	var hashes [][]byte
+	found := make(map[string]bool)
+	txsFilter := util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	for tranNo, envBytes := range block.Data.Data {
+		if len(txsFilter) != 0 && txsFilter.IsInvalid(tranNo) {
+			continue
+		}
+		txRWSet, err := getTxRWSet(envBytes)
+		if err != nil {
+			return nil, err
+		}
+		if txRWSet == nil {
+			continue
+		}
+		for _, hash := range References(txRWSet) {
+			if !found[string(hash)] {
+				found[string(hash)] = true
+				hashes = append(hashes, hash)
+			}
+		}
+	}
+	return hashes, nil
+}
+
+// StoreProvider provides an instance of a blob store per ledger
+type StoreProvider interface {
+	// OpenStore returns a handle to the blob store of the given ledger
+	OpenStore(ledgerID string) (Store, error)
+	// Close releases any resources held by StoreProvider
+	Close()
+}
+
+// Store keeps the state values that are too large to be held in the state database. A value is addressed
+// by the SHA-256 hash of its content; the state database, the transactions and the blocks only hold a
+// reference to it. A chaincode streams the value during the simulation of a transaction, which stages the
+// blob on the endorsing peer. The blob is then sent to the other peers of the channel apart from the
+// transaction, in chunks which they stage, and the staged blobs referenced by the valid transactions of a
+// block are added to the store when the block is committed. A blob is removed from the store once the state
+// no longer references it
+type Store interface {
+	// NewWriter returns a writer staging a new blob
+	NewWriter() (Writer, error)
+	// StageChunk appends a chunk of the content of the blob with the given hash and size to the part of
+	// the blob received so far, if the chunk starts at its end, and stages the blob once its content is
+	// complete. It returns the number of bytes of the blob held by the store, which is its size once the
+	// blob is staged or committed
+	StageChunk(hash []byte, offset uint64, size uint64, data []byte) (uint64, error)
+	// ReadChunk returns at most maxLen bytes of the content of the staged or committed blob with
+	// the given hash starting at offset, along with the size of the blob
+	ReadChunk(hash []byte, offset uint64, maxLen int) ([]byte, uint64, error)
+	// Has returns whether the content of the blob with the given hash is staged or committed
+	Has(hash []byte) bool
+	// Open returns the committed blob with the given hash
+	Open(hash []byte) (Blob, error)
+	// Commit adds the staged blobs referenced by the writes of the valid transactions of the block to the
+	// store and removes the blobs that are no longer referenced by the state. A referenced blob which is not
+	// staged is recorded as referenced all the same, and added to the store once it is available
+	Commit(block *common.Block) error
+	// Bootstrap sets the savepoint of the store of a ledger created from a snapshot. Note that the
+	// snapshot does not contain the blobs referenced by its state
+	Bootstrap(savepoint *version.Height) error
+	// ShouldRecover returns whether the store is behind the block storage and, if so,
+	// the block number to start the recovery from
+	ShouldRecover(lastAvailableBlock uint64) (bool, uint64, error)
+	// CommitLostBlock recommits the block
+	CommitLostBlock(block *common.Block) error
+}
+
+// Writer writes the content of a blob to the store
+type Writer interface {
+	io.Writer
+	// Commit ends the content of the blob and returns its hash, under which the blob is staged
+	Commit() ([]byte, error)
+	// Abort discards the content written so far
+	Abort()
+}
+
+// Blob gives access to the content of a blob
+type Blob interface {
+	io.ReaderAt
+	io.Closer
+	// Size returns the size of the content of the blob
+	Size() int64
+}
+
+// ErrBlobNotFound is returned by Store.Open and Store.ReadChunk when the store does not hold the blob
+type ErrBlobNotFound struct {
+	Hash []byte
+}
+
+func (e *ErrBlobNotFound) Error() string {
+	return fmt.Sprintf("blob %x is not available on this peer", e.Hash)
+}
+
+// storeProvider implements interface StoreProvider
+type storeProvider struct {
+	dirPath    string
+	dbProvider *leveldbhelper.Provider
+}
+
+// NewStoreProvider constructs a StoreProvider keeping the blobs of each ledger in a directory
+// and the references to them in a level db
+func NewStoreProvider() StoreProvider {
+	dirPath := ledgerconfig.GetBlobStorePath()
+	dbPath := ledgerconfig.GetBlobStoreIndexPath()
+	logger.Debugf("constructing blob StoreProvider dirPath=%s dbPath=%s", dirPath, dbPath)
+	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: dbPath})
+	return &storeProvider{dirPath, dbProvider}
+}
+
+// OpenStore implements method in interface StoreProvider
+func (provider *storeProvider) OpenStore(ledgerID string) (Store, error) {
+	dirPath := filepath.Join(provider.dirPath, ledgerID)
+	// the blobs that were being written when the peer stopped are discarded
+	if err := os.RemoveAll(filepath.Join(dirPath, tmpDir)); err != nil {
+		return nil, fmt.Errorf("Failed to clear the blob store of ledger %s: %s", ledgerID, err)
+	}
+	for _, dir := range []string{tmpDir, stagedDir} {
+		if err := os.MkdirAll(filepath.Join(dirPath, dir), 0755); err != nil {
+			return nil, fmt.Errorf("Failed to create the blob store of ledger %s: %s", ledgerID, err)
+		}
+	}
+	s := &store{dirPath: dirPath, ledgerID: ledgerID, db: provider.dbProvider.GetDBHandle(ledgerID)}
+	if err := s.removeUnreferenced(); err != nil {
+		return nil, fmt.Errorf("Failed to clear the blob store of ledger %s: %s", ledgerID, err)
+	}
+	return s, nil
+}
+
+// Close implements method in interface StoreProvider
+func (provider *storeProvider) Close() {
+	provider.dbProvider.Close()
+}
+
+// store implements interface Store. Each blob is kept in a file named after the hex encoding of its hash;
+// the blobs being written or received are kept in the tmp directory and the blobs written during the
+// simulation of a transaction or received from other peers in the staged directory. The db maps each key
+// of the state referencing a blob to the hash of the blob and each blob to the number of keys referencing it
+type store struct {
+	dirPath   string
+	ledgerID  string
+	db        *leveldbhelper.DBHandle
+	purgeLock sync.Mutex
+	lastPurge time.Time
+	// chunkLock serializes the chunks appended to the partly received blobs
+	chunkLock sync.Mutex
+}
+
+// NewWriter implements method in interface Store
+func (s *store) NewWriter() (Writer, error) {
+	f, err := ioutil.TempFile(filepath.Join(s.dirPath, tmpDir), "blob")
+	if err != nil {
+		return nil, err
+	}
+	return &writer{s: s, f: f, h: sha256.New()}, nil
+}
+
+// StageChunk implements method in interface Store
+func (s *store) StageChunk(hash []byte, offset uint64, size uint64, data []byte) (uint64, error) {
+	s.chunkLock.Lock()
+	defer s.chunkLock.Unlock()
+	if s.Has(hash) {
+		return size, nil
+	}
+	partialPath := s.partialPath(hash)
+	var received uint64
+	if info, err := os.Stat(partialPath); err == nil {
+		received = uint64(info.Size())
+	}
+	if offset != received || received+uint64(len(data)) > size {
+		// the chunk does not follow the part received so far
+		return received, nil
+	}
+	f, err := os.OpenFile(partialPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return received, err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(partialPath)
+		return 0, fmt.Errorf("Failed to write a chunk of blob %x to the blob store of ledger %s: %s", hash, s.ledgerID, err)
+	}
+	received += uint64(len(data))
+	if received < size {
+		return received, nil
+	}
+
+	content, err := ioutil.ReadFile(partialPath)
+	if err != nil {
+		return received, err
+	}
+	if computed := sha256.Sum256(content); !bytes.Equal(computed[:], hash) {
+		os.Remove(partialPath)
+		return 0, fmt.Errorf("the content received for blob %x has hash %x", hash, computed)
+	}
+	if err = os.Rename(partialPath, s.stagedPath(hash)); err != nil {
+		os.Remove(partialPath)
+		return 0, fmt.Errorf("Failed to stage blob %x in the blob store of ledger %s: %s", hash, s.ledgerID, err)
+	}
+	logger.Debugf("Channel [%s]: Staged received blob %x", s.ledgerID, hash)
+	return size, nil
+}
+
+// ReadChunk implements method in interface Store
+func (s *store) ReadChunk(hash []byte, offset uint64, maxLen int) ([]byte, uint64, error) {
+	f, err := os.Open(s.stagedPath(hash))
+	if os.IsNotExist(err) {
+		f, err = os.Open(s.blobPath(hash))
+	}
+	if os.IsNotExist(err) {
+		return nil, 0, &ErrBlobNotFound{Hash: hash}
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+	size := uint64(info.Size())
+	if offset > size {
+		return nil, 0, fmt.Errorf("offset %d is past the end of blob %x of size %d", offset, hash, size)
+	}
+	if remaining := size - offset; remaining < uint64(maxLen) {
+		maxLen = int(remaining)
+	}
+	data := make([]byte, maxLen)
+	if _, err = f.ReadAt(data, int64(offset)); err != nil {
+		return nil, 0, err
+	}
+	return data, size, nil
+}
+
+// Has implements method in interface Store
+func (s *store) Has(hash []byte) bool {
+	if _, err := os.Stat(s.stagedPath(hash)); err == nil {
+		return true
+	}
+	_, err := os.Stat(s.blobPath(hash))
+	return err == nil
+}
+
+// Open implements method in interface Store. A referenced blob which was not available when the
+// block referencing it was committed is added to the store if it has been staged since
+func (s *store) Open(hash []byte) (Blob, error) {
+	f, err := os.Open(s.blobPath(hash))
+	if os.IsNotExist(err) {
+		if refCount, dbErr := s.db.Get(constructRefCountKey(hash)); dbErr != nil || refCount == nil {
+			return nil, &ErrBlobNotFound{Hash: hash}
+		}
+		if available, writeErr := s.writeBlob(hash); writeErr != nil || !available {
+			return nil, &ErrBlobNotFound{Hash: hash}
+		}
+		f, err = os.Open(s.blobPath(hash))
+	}
+	if os.IsNotExist(err) {
+		return nil, &ErrBlobNotFound{Hash: hash}
+	}
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &blob{File: f, size: info.Size()}, nil
+}
+
+// Commit implements method in interface Store. The blobs are written before the references
+// and removed after them, so that a failure leaves unreferenced blobs only, which are removed
+// when the store is opened
+func (s *store) Commit(block *common.Block) error {
+	blockNo := block.Header.Number
+	logger.Debugf("Channel [%s]: Updating blob store for blockNo [%v] with [%d] transactions",
+		s.ledgerID, blockNo, len(block.Data.Data))
+
+	c := &blockCommit{s: s, dbBatch: leveldbhelper.NewUpdateBatch(),
+		index: make(map[string][]byte), refCounts: make(map[string]uint64)}
+	txsFilter := util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	for tranNo, envBytes := range block.Data.Data {
+		if len(txsFilter) != 0 && txsFilter.IsInvalid(tranNo) {
+			continue
+		}
+		txRWSet, err := getTxRWSet(envBytes)
+		if err != nil {
+			return err
+		}
+		if txRWSet == nil {
+			continue
+		}
+		if err = c.addTx(txRWSet); err != nil {
+			return err
+		}
+	}
+	unreferenced := c.prepareBatch()
+
+	// add savepoint for recovery purpose
+	height := version.NewHeight(blockNo, uint64(len(block.Data.Data)))
+	c.dbBatch.Put(savePointKey, height.ToBytes())
+	if err := s.db.WriteBatch(c.dbBatch, true); err != nil {
+		return err
+	}
+
+	for _, hash := range unreferenced {
+		if err := os.Remove(s.blobPath(hash)); err != nil && !os.IsNotExist(err) {
+			logger.Warningf("Channel [%s]: Failed to remove unreferenced blob %x: %s", s.ledgerID, hash, err)
+			continue
+		}
+		logger.Debugf("Channel [%s]: Removed unreferenced blob %x", s.ledgerID, hash)
+	}
+	s.purgeExpiredStaged()
+	logger.Debugf("Channel [%s]: Updates committed to blob store for blockNo [%v]", s.ledgerID, blockNo)
+	return nil
+}
+
+// Bootstrap implements method in interface Store
+func (s *store) Bootstrap(savepoint *version.Height) error {
+	return s.db.Put(savePointKey, savepoint.ToBytes(), true)
+}
+
+// ShouldRecover implements method in interface Store
+func (s *store) ShouldRecover(lastAvailableBlock uint64) (bool, uint64, error) {
+	versionBytes, err := s.db.Get(savePointKey)
+	if err != nil {
+		return false, 0, err
+	}
+	if versionBytes == nil {
+		return true, 0, nil
+	}
+	savepoint, _ := version.NewHeightFromBytes(versionBytes)
+	return savepoint.BlockNum != lastAvailableBlock, savepoint.BlockNum + 1, nil
+}
+
+// CommitLostBlock implements method in interface Store
+func (s *store) CommitLostBlock(block *common.Block) error {
+	return s.Commit(block)
+}
+
+// writeBlob adds a staged blob to the store. The returned boolean is false if the blob is not staged
+func (s *store) writeBlob(hash []byte) (bool, error) {
+	if _, err := os.Stat(s.blobPath(hash)); err == nil {
+		return true, nil
+	}
+	stagedPath := s.stagedPath(hash)
+	content, err := ioutil.ReadFile(stagedPath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	f, err := ioutil.TempFile(filepath.Join(s.dirPath, tmpDir), "blob")
+	if err != nil {
+		return false, err
+	}
+	_, err = f.Write(content)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), s.blobPath(hash))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return false, fmt.Errorf("Failed to commit blob %x to the blob store of ledger %s: %s", hash, s.ledgerID, err)
+	}
+	os.Remove(stagedPath)
+	logger.Debugf("Channel [%s]: Committed blob %x", s.ledgerID, hash)
+	return true, nil
+}
+
+// removeUnreferenced removes the blobs that are not referenced by the state
+func (s *store) removeUnreferenced() error {
+	files, err := ioutil.ReadDir(s.dirPath)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		hash, err := hex.DecodeString(f.Name())
+		if f.IsDir() || err != nil || len(hash) != sha256.Size {
+			continue
+		}
+		refCount, err := s.db.Get(constructRefCountKey(hash))
+		if err != nil {
+			return err
+		}
+		if refCount == nil {
+			logger.Debugf("Channel [%s]: Removing unreferenced blob %x", s.ledgerID, hash)
+			if err = os.Remove(filepath.Join(s.dirPath, f.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// purgeExpiredStaged removes the staged blobs that have not been committed within stagedBlobExpiry,
+// e.g. because the transaction referencing them was never submitted or was invalidated, and the
+// blobs whose content was not received entirely within stagedBlobExpiry
+func (s *store) purgeExpiredStaged() {
+	s.purgeLock.Lock()
+	defer s.purgeLock.Unlock()
+	now := time.Now()
+	if now.Sub(s.lastPurge) < stagedBlobExpiry {
+		return
+	}
+	s.lastPurge = now
+	for _, dir := range []string{stagedDir, tmpDir} {
+		files, err := ioutil.ReadDir(filepath.Join(s.dirPath, dir))
+		if err != nil {
+			logger.Warningf("Channel [%s]: Failed to list the blobs of %s: %s", s.ledgerID, dir, err)
+			continue
+		}
+		for _, f := range files {
+			// the tmp directory also holds the blobs being written, which are not named after their hash
+			if _, err := hex.DecodeString(f.Name()); err != nil || now.Sub(f.ModTime()) < stagedBlobExpiry {
+				continue
+			}
+			logger.Debugf("Channel [%s]: Removing expired blob %s/%s", s.ledgerID, dir, f.Name())
+			os.Remove(filepath.Join(s.dirPath, dir, f.Name()))
+		}
+	}
+}
+
+func (s *store) blobPath(hash []byte) string {
+	return filepath.Join(s.dirPath, hex.EncodeToString(hash))
+}
+
+func (s *store) stagedPath(hash []byte) string {
+	return filepath.Join(s.dirPath, stagedDir, hex.EncodeToString(hash))
+}
+
+func (s *store) partialPath(hash []byte) string {
+	return filepath.Join(s.dirPath, tmpDir, hex.EncodeToString(hash))
+}
+
+// blockCommit accumulates the updates of the references to the blobs made by the transactions of a block
+type blockCommit struct {
+	s         *store
+	dbBatch   *leveldbhelper.UpdateBatch
+	index     map[string][]byte
+	refCounts map[string]uint64
+}
+
+// addTx updates the references to the blobs with the writes of a valid transaction
+func (c *blockCommit) addTx(txRWSet *rwsetutil.TxRwSet) error {
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+			indexKey := constructIndexKey(nsRWSet.NameSpace, kvWrite.Key)
+			oldHash, err := c.getIndex(indexKey)
+			if err != nil {
+				return err
+			}
+			var newHash []byte
+			if !kvWrite.IsDelete {
+				newHash, _ = ParseReference(kvWrite.Value)
+			}
+			if bytes.Equal(oldHash, newHash) {
+				continue
+			}
+			if newHash != nil {
+				if err = c.addReference(newHash); err != nil {
+					return err
+				}
+			}
+			if oldHash != nil {
+				if err = c.removeReference(oldHash); err != nil {
+					return err
+				}
+			}
+			c.index[string(indexKey)] = newHash
+		}
+	}
+	return nil
+}
+
+func (c *blockCommit) addReference(hash []byte) error {
+	refCount, err := c.getRefCount(hash)
+	if err != nil {
+		return err
+	}
+	available, err := c.s.writeBlob(hash)
+	if err != nil {
+		return err
+	}
+	if !available {
+		logger.Warningf("Channel [%s]: Blob %x referenced by a valid transaction is not available on this peer", c.s.ledgerID, hash)
+	}
+	c.refCounts[string(hash)] = refCount + 1
+	return nil
+}
+
+func (c *blockCommit) removeReference(hash []byte) error {
+	refCount, err := c.getRefCount(hash)
+	if err != nil {
+		return err
+	}
+	if refCount > 0 {
+		c.refCounts[string(hash)] = refCount - 1
+	}
+	return nil
+}
+
+func (c *blockCommit) getIndex(indexKey []byte) ([]byte, error) {
+	if hash, ok := c.index[string(indexKey)]; ok {
+		return hash, nil
+	}
+	return c.s.db.Get(indexKey)
+}
+
+func (c *blockCommit) getRefCount(hash []byte) (uint64, error) {
+	if refCount, ok := c.refCounts[string(hash)]; ok {
+		return refCount, nil
+	}
+	refCountBytes, err := c.s.db.Get(constructRefCountKey(hash))
+	if err != nil || refCountBytes == nil {
+		return 0, err
+	}
+	refCount, _ := ledgerutil.DecodeOrderPreservingVarUint64(refCountBytes)
+	return refCount, nil
+}
+
+// prepareBatch adds the updated references to the batch and returns the hashes of the blobs
+// that are no longer referenced
+func (c *blockCommit) prepareBatch() [][]byte {
+	for indexKey, hash := range c.index {
+		if hash == nil {
+			c.dbBatch.Delete([]byte(indexKey))
+		} else {
+			c.dbBatch.Put([]byte(indexKey), hash)
+		}
+	}
+	var unreferenced [][]byte
+	for hash, refCount := range c.refCounts {
+		if refCount == 0 {
+			c.dbBatch.Delete(constructRefCountKey([]byte(hash)))
+			unreferenced = append(unreferenced, []byte(hash))
+		} else {
+			c.dbBatch.Put(constructRefCountKey([]byte(hash)), ledgerutil.EncodeOrderPreservingVarUint64(refCount))
+		}
+	}
+	return unreferenced
+}
+
+func constructIndexKey(ns string, key string) []byte {
+	indexKey := append(append([]byte{}, indexKeyPrefix...), []byte(ns)...)
+	indexKey = append(indexKey, compositeKeySep...)
+	return append(indexKey, []byte(key)...)
+}
+
+func constructRefCountKey(hash []byte) []byte {
+	return append(append([]byte{}, refCountKeyPrefix...), hash...)
+}
+
+// getTxRWSet returns the read-write set of an endorser transaction, nil for the other transactions
+func getTxRWSet(envBytes []byte) (*rwsetutil.TxRwSet, error) {
+	env, err := putils.GetEnvelopeFromBlock(envBytes)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := putils.GetPayload(env)
+	if err != nil {
+		return nil, err
+	}
+	chdr, err := putils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	if common.HeaderType(chdr.Type) != common.HeaderType_ENDORSER_TRANSACTION {
+		return nil, nil
+	}
+	tx, err := putils.GetTransaction(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+	if len(tx.Actions) == 0 {
+		return nil, fmt.Errorf("At least one TransactionAction is required")
+	}
+	_, respPayload, err := putils.GetPayloads(tx.Actions[0])
+	if err != nil {
+		return nil, err
+	}
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err = txRWSet.FromProtoBytes(respPayload.Results); err != nil {
+		return nil, err
+	}
+	return txRWSet, nil
+}
+
+// writer implements interface Writer, hashing the content while it is written to a temporary file
+type writer struct {
+	s *store
+	f *os.File
+	h hash.Hash
+}
+
+// Write implements method in interface io.Writer
+func (w *writer) Write(p []byte) (int, error) {
+	n, err := w.f.Write(p)
+	w.h.Write(p[:n])
+	return n, err
+}
+
+// Commit implements method in interface Writer
+func (w *writer) Commit() ([]byte, error) {
+	hash := w.h.Sum(nil)
+	err := w.f.Sync()
+	if closeErr := w.f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(w.f.Name(), w.s.stagedPath(hash))
+	}
+	if err != nil {
+		os.Remove(w.f.Name())
+		return nil, fmt.Errorf("Failed to stage blob in the blob store of ledger %s: %s", w.s.ledgerID, err)
+	}
+	logger.Debugf("Channel [%s]: Staged blob %x", w.s.ledgerID, hash)
+	return hash, nil
+}
+
+// Abort implements method in interface Writer
+func (w *writer) Abort() {
+	w.f.Close()
+	os.Remove(w.f.Name())
+}
+
+// blob implements interface Blob
+type blob struct {
+	*os.File
+	size int64
+}
+
+// Size implements method in interface Blob
+func (b *blob) Size() int64 {
+	return b.size
+}
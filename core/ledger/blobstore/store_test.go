@@ -0,0 +1,318 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blobstore
+
+import (
+	"crypto/sha256"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	viper.Set("peer.fileSystemPath", "/tmp/fabric/core/ledger/blobstore")
+	os.Exit(m.Run())
+}
+
+func newTestStore(t *testing.T) (StoreProvider, Store) {
+	assert.NoError(t, os.RemoveAll(ledgerconfig.GetRootPath()))
+	provider := NewStoreProvider()
+	s, err := provider.OpenStore("ledger1")
+	assert.NoError(t, err)
+	return provider, s
+}
+
+func newTestRWSet(t *testing.T, writes ...[]byte) *rwsetutil.TxRwSet {
+	builder := rwsetutil.NewRWSetBuilder()
+	for i := 0; i < len(writes); i += 2 {
+		builder.AddToWriteSet("ns", string(writes[i]), writes[i+1])
+	}
+	return builder.GetTxReadWriteSet()
+}
+
+func constructTestBlock(t *testing.T, blockNum uint64, rwSets ...*rwsetutil.TxRwSet) *common.Block {
+	simulationResults := [][]byte{}
+	for _, rwSet := range rwSets {
+		results, err := rwSet.ToProtoBytes()
+		assert.NoError(t, err)
+		simulationResults = append(simulationResults, results)
+	}
+	return testutil.ConstructBlock(t, blockNum, nil, simulationResults, false)
+}
+
+// stageBlob stages a blob as received from another peer, in chunks of two bytes
+func stageBlob(t *testing.T, s Store, content string) []byte {
+	hash := hashOf(content)
+	size := uint64(len(content))
+	for offset := uint64(0); offset < size || size == 0; offset += 2 {
+		end := offset + 2
+		if end > size {
+			end = size
+		}
+		received, err := s.StageChunk(hash, offset, size, []byte(content[offset:end]))
+		assert.NoError(t, err)
+		assert.Equal(t, end, received)
+		if size == 0 {
+			break
+		}
+	}
+	assert.True(t, s.Has(hash))
+	return hash
+}
+
+func hashOf(content string) []byte {
+	hash := sha256.Sum256([]byte(content))
+	return hash[:]
+}
+
+func TestReference(t *testing.T) {
+	hash := hashOf("value")
+	ref := Reference(hash)
+	assert.True(t, IsReference(ref))
+	parsed, ok := ParseReference(ref)
+	assert.True(t, ok)
+	assert.Equal(t, hash, parsed)
+
+	_, ok = ParseReference([]byte("value"))
+	assert.False(t, ok)
+	assert.False(t, IsReference([]byte("value")))
+	_, ok = ParseReference(ref[:len(ref)-1])
+	assert.False(t, ok)
+}
+
+func TestReferences(t *testing.T) {
+	rwSet := newTestRWSet(t, []byte("k1"), Reference(hashOf("a")), []byte("k2"), Reference(hashOf("a")),
+		[]byte("k3"), Reference(hashOf("b")), []byte("k4"), []byte("value"))
+	assert.Equal(t, [][]byte{hashOf("a"), hashOf("b")}, References(rwSet))
+}
+
+func TestBlockReferences(t *testing.T) {
+	block := constructTestBlock(t, 0,
+		newTestRWSet(t, []byte("k1"), Reference(hashOf("a")), []byte("k2"), Reference(hashOf("b"))),
+		newTestRWSet(t, []byte("k3"), Reference(hashOf("c"))),
+		newTestRWSet(t, []byte("k4"), Reference(hashOf("b"))))
+	util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER]).SetFlag(1,
+		peer.TxValidationCode_MVCC_READ_CONFLICT)
+	hashes, err := BlockReferences(block)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{hashOf("a"), hashOf("b")}, hashes)
+}
+
+func TestStageAndReadChunks(t *testing.T) {
+	provider, s := newTestStore(t)
+	defer os.RemoveAll(ledgerconfig.GetRootPath())
+	defer provider.Close()
+
+	hash := hashOf("hello world")
+	assert.False(t, s.Has(hash))
+	_, _, err := s.ReadChunk(hash, 0, 4)
+	assert.IsType(t, &ErrBlobNotFound{}, err)
+
+	// the chunks which do not follow the part received so far are ignored
+	received, err := s.StageChunk(hash, 0, 11, []byte("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), received)
+	received, err = s.StageChunk(hash, 8, 11, []byte("rld"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), received)
+	received, err = s.StageChunk(hash, 0, 11, []byte("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), received)
+	assert.False(t, s.Has(hash))
+	received, err = s.StageChunk(hash, 5, 11, []byte(" world"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(11), received)
+	assert.True(t, s.Has(hash))
+
+	data, size, err := s.ReadChunk(hash, 6, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(11), size)
+	assert.Equal(t, "worl", string(data))
+	data, _, err = s.ReadChunk(hash, 10, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, "d", string(data))
+	data, _, err = s.ReadChunk(hash, 11, 4)
+	assert.NoError(t, err)
+	assert.Empty(t, data)
+	_, _, err = s.ReadChunk(hash, 12, 4)
+	assert.Error(t, err)
+
+	// a content not matching its hash is discarded
+	received, err = s.StageChunk(hashOf("a"), 0, 1, []byte("b"))
+	assert.Error(t, err)
+	assert.Equal(t, uint64(0), received)
+	assert.False(t, s.Has(hashOf("a")))
+
+	stageBlob(t, s, "")
+}
+
+func TestWriteAndCommitStaged(t *testing.T) {
+	provider, s := newTestStore(t)
+	defer os.RemoveAll(ledgerconfig.GetRootPath())
+	defer provider.Close()
+
+	w, err := s.NewWriter()
+	assert.NoError(t, err)
+	_, err = w.Write([]byte("hello "))
+	assert.NoError(t, err)
+	_, err = w.Write([]byte("world"))
+	assert.NoError(t, err)
+	hash, err := w.Commit()
+	assert.NoError(t, err)
+	assert.Equal(t, hashOf("hello world"), hash)
+
+	// the blob is staged until a transaction referencing it is committed
+	_, err = s.Open(hash)
+	assert.IsType(t, &ErrBlobNotFound{}, err)
+	assert.True(t, s.Has(hash))
+	content, size, err := s.ReadChunk(hash, 0, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(11), size)
+	assert.Equal(t, "hello world", string(content))
+
+	assert.NoError(t, s.Commit(constructTestBlock(t, 0, newTestRWSet(t, []byte("k1"), Reference(hash)))))
+	b, err := s.Open(hash)
+	assert.NoError(t, err)
+	defer b.Close()
+	assert.Equal(t, int64(11), b.Size())
+	buf := make([]byte, 5)
+	_, err = b.ReadAt(buf, 6)
+	assert.NoError(t, err)
+	assert.Equal(t, "world", string(buf))
+
+	// the staged blob is removed once committed, and read from the committed blob
+	files, err := ioutil.ReadDir(filepath.Join(ledgerconfig.GetBlobStorePath(), "ledger1", stagedDir))
+	assert.NoError(t, err)
+	assert.Len(t, files, 0)
+	assert.True(t, s.Has(hash))
+	content, _, err = s.ReadChunk(hash, 0, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(content))
+}
+
+func TestCommitAndRemoveUnreferenced(t *testing.T) {
+	provider, s := newTestStore(t)
+	defer os.RemoveAll(ledgerconfig.GetRootPath())
+	defer provider.Close()
+
+	hashA, hashB := stageBlob(t, s, "a"), stageBlob(t, s, "b")
+	block := constructTestBlock(t, 0,
+		newTestRWSet(t, []byte("k1"), Reference(hashA), []byte("k2"), Reference(hashA)),
+		newTestRWSet(t, []byte("k3"), Reference(hashB)))
+	// the blobs referenced by invalid transactions are not added to the store
+	util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER]).SetFlag(1,
+		peer.TxValidationCode_MVCC_READ_CONFLICT)
+	assert.NoError(t, s.Commit(block))
+	assertBlob(t, s, hashA, "a")
+	_, err := s.Open(hashB)
+	assert.IsType(t, &ErrBlobNotFound{}, err)
+
+	// the blob is kept as long as a key references it
+	assert.NoError(t, s.Commit(constructTestBlock(t, 1, newTestRWSet(t, []byte("k1"), nil))))
+	assertBlob(t, s, hashA, "a")
+	assert.NoError(t, s.Commit(constructTestBlock(t, 2, newTestRWSet(t, []byte("k2"), []byte("value")))))
+	_, err = s.Open(hashA)
+	assert.IsType(t, &ErrBlobNotFound{}, err)
+
+	// a blob which is not available is referenced all the same, and added to the store once received
+	hashC := hashOf("c")
+	assert.NoError(t, s.Commit(constructTestBlock(t, 3, newTestRWSet(t, []byte("k1"), Reference(hashC)))))
+	_, err = s.Open(hashC)
+	assert.IsType(t, &ErrBlobNotFound{}, err)
+	stageBlob(t, s, "c")
+	assertBlob(t, s, hashC, "c")
+}
+
+func TestRecovery(t *testing.T) {
+	provider, s := newTestStore(t)
+	defer os.RemoveAll(ledgerconfig.GetRootPath())
+
+	recover, firstBlockNum, err := s.ShouldRecover(0)
+	assert.NoError(t, err)
+	assert.True(t, recover)
+	assert.Equal(t, uint64(0), firstBlockNum)
+
+	hash := stageBlob(t, s, "a")
+	assert.NoError(t, s.CommitLostBlock(constructTestBlock(t, 0, newTestRWSet(t, []byte("k1"), Reference(hash)))))
+	recover, _, err = s.ShouldRecover(0)
+	assert.NoError(t, err)
+	assert.False(t, recover)
+	recover, firstBlockNum, err = s.ShouldRecover(2)
+	assert.NoError(t, err)
+	assert.True(t, recover)
+	assert.Equal(t, uint64(1), firstBlockNum)
+
+	assert.NoError(t, s.Bootstrap(version.NewHeight(5, 2)))
+	recover, _, err = s.ShouldRecover(5)
+	assert.NoError(t, err)
+	assert.False(t, recover)
+
+	// the blobs left unreferenced by a failure are removed when the store is opened
+	unreferenced := hashOf("unreferenced")
+	assert.NoError(t, ioutil.WriteFile(s.(*store).blobPath(unreferenced), []byte("unreferenced"), 0644))
+	provider.Close()
+	provider = NewStoreProvider()
+	defer provider.Close()
+	s, err = provider.OpenStore("ledger1")
+	assert.NoError(t, err)
+	_, err = s.Open(unreferenced)
+	assert.IsType(t, &ErrBlobNotFound{}, err)
+	assertBlob(t, s, hash, "a")
+}
+
+func TestAbortAndNotFound(t *testing.T) {
+	provider, s := newTestStore(t)
+	defer os.RemoveAll(ledgerconfig.GetRootPath())
+	defer provider.Close()
+
+	w, err := s.NewWriter()
+	assert.NoError(t, err)
+	w.Write([]byte("discarded"))
+	w.Abort()
+	files, err := ioutil.ReadDir(filepath.Join(ledgerconfig.GetBlobStorePath(), "ledger1", tmpDir))
+	assert.NoError(t, err)
+	assert.Len(t, files, 0)
+
+	hash := hashOf("discarded")
+	_, err = s.Open(hash)
+	assert.IsType(t, &ErrBlobNotFound{}, err)
+	assert.Contains(t, err.Error(), "is not available on this peer")
+	assert.False(t, s.Has(hash))
+}
+
+func assertBlob(t *testing.T, s Store, hash []byte, content string) {
+	b, err := s.Open(hash)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer b.Close()
+	buf := make([]byte, b.Size())
+	_, err = b.ReadAt(buf, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, content, string(buf))
+}
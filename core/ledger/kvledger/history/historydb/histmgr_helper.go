@@ -53,6 +53,43 @@ func ConstructPartialCompositeHistoryKey(ns string, key string, endkey bool) []b
 	return compositeKey
 }
 
+//DecodeCompositeHistoryKey returns the candidate block numbers and transaction numbers encoded in the given
+// History Key of namespace~key~blocknum~trannum. Because the key may itself contain the separator, more than one
+// candidate may be found. The caller is expected to pick the candidate for which ConstructCompositeHistoryKey
+// reproduces the given History Key
+func DecodeCompositeHistoryKey(compositeKey []byte) (blockNums []uint64, tranNums []uint64) {
+	nsEnd := bytes.Index(compositeKey, compositeKeySep)
+	if nsEnd < 0 {
+		return nil, nil
+	}
+	for i := nsEnd + 1; i < len(compositeKey); i++ {
+		if compositeKey[i] != compositeKeySep[0] {
+			continue
+		}
+		blockNumTranNumBytes := compositeKey[i+1:]
+		blockNum, n1, ok := decodeOrderPreservingVarUint64(blockNumTranNumBytes)
+		if !ok {
+			continue
+		}
+		tranNum, n2, ok := decodeOrderPreservingVarUint64(blockNumTranNumBytes[n1:])
+		if !ok || n1+n2 != len(blockNumTranNumBytes) {
+			continue
+		}
+		blockNums = append(blockNums, blockNum)
+		tranNums = append(tranNums, tranNum)
+	}
+	return blockNums, tranNums
+}
+
+// decodeOrderPreservingVarUint64 guards util.DecodeOrderPreservingVarUint64 against malformed input
+func decodeOrderPreservingVarUint64(b []byte) (uint64, int, bool) {
+	if len(b) == 0 || b[0] > 8 || len(b) < int(b[0])+1 {
+		return 0, 0, false
+	}
+	num, n := util.DecodeOrderPreservingVarUint64(b)
+	return num, n, true
+}
+
 //SplitCompositeHistoryKey splits the key bytes using a separator
 func SplitCompositeHistoryKey(bytesToSplit []byte, separator []byte) ([]byte, []byte) {
 	split := bytes.SplitN(bytesToSplit, separator, 2)
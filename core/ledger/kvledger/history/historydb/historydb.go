@@ -17,10 +17,12 @@ limitations under the License.
 package historydb
 
 import (
+	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
 )
 
 // HistoryDBProvider provides an instance of a history DB
@@ -38,4 +40,19 @@ type HistoryDB interface {
 	GetLastSavepoint() (*version.Height, error)
 	ShouldRecover(lastAvailableBlock uint64) (bool, uint64, error)
 	CommitLostBlock(block *common.Block) error
+	// GetHistoryRecordsIterator returns an iterator over all the records in the history db.
+	// The returned ResultsIterator contains results of type *HistoryRecord
+	GetHistoryRecordsIterator(blockStore blkstorage.BlockStore) (commonledger.ResultsIterator, error)
+	// ImportHistoryRecords adds the given records and records the given height as the savepoint
+	ImportHistoryRecords(records []*HistoryRecord, savepoint *version.Height) error
+	// Clear removes all the records and the savepoint from the history db
+	Clear() error
+}
+
+// HistoryRecord represents a record in the history db along with the modification of the key that the record refers to.
+// This is used for exporting the history db to a ledger snapshot, because a ledger created from the snapshot
+// does not hold the blocks that carry the modifications
+type HistoryRecord struct {
+	CompositeKey    []byte
+	KeyModification *queryresult.KeyModification
 }
@@ -0,0 +1,90 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package historyleveldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/protos/peer"
+)
+
+// blockTimeKeyPrefix is the prefix of the keys that record, for each block, the range of the timestamps of its
+// transactions. A history query with a time range uses them to skip the history records of the blocks that fall
+// outside of that range without retrieving the blocks from the block storage. Namespaces never start with this byte,
+// so these keys do not collide with the history keys
+var blockTimeKeyPrefix = []byte{0x01}
+
+// blockTimeRange is the range of the timestamps of the transactions of a block
+type blockTimeRange struct {
+	earliest *timestamp.Timestamp
+	latest   *timestamp.Timestamp
+}
+
+// add extends the range to cover the given timestamp
+func (r *blockTimeRange) add(ts *timestamp.Timestamp) {
+	if ts == nil {
+		return
+	}
+	if r.earliest == nil || compareTimestamps(ts, r.earliest) < 0 {
+		r.earliest = ts
+	}
+	if r.latest == nil || compareTimestamps(ts, r.latest) > 0 {
+		r.latest = ts
+	}
+}
+
+// overlaps checks whether a transaction of the block may fall in the time range of the options
+func (r *blockTimeRange) overlaps(options *peer.HistoryQueryOptions) bool {
+	if options.StartTime != nil && compareTimestamps(r.latest, options.StartTime) < 0 {
+		return false
+	}
+	if options.EndTime != nil && compareTimestamps(r.earliest, options.EndTime) >= 0 {
+		return false
+	}
+	return true
+}
+
+func constructBlockTimeKey(blockNum uint64) []byte {
+	return append(append([]byte{}, blockTimeKeyPrefix...), util.EncodeOrderPreservingVarUint64(blockNum)...)
+}
+
+func isBlockTimeKey(key []byte) bool {
+	return bytes.HasPrefix(key, blockTimeKeyPrefix)
+}
+
+func encodeBlockTimeRange(r *blockTimeRange) []byte {
+	value := make([]byte, 24)
+	binary.BigEndian.PutUint64(value[0:], uint64(r.earliest.Seconds))
+	binary.BigEndian.PutUint32(value[8:], uint32(r.earliest.Nanos))
+	binary.BigEndian.PutUint64(value[12:], uint64(r.latest.Seconds))
+	binary.BigEndian.PutUint32(value[20:], uint32(r.latest.Nanos))
+	return value
+}
+
+func decodeBlockTimeRange(value []byte) (*blockTimeRange, error) {
+	if len(value) != 24 {
+		return nil, fmt.Errorf("Invalid block time range of length [%d]", len(value))
+	}
+	return &blockTimeRange{
+		earliest: &timestamp.Timestamp{Seconds: int64(binary.BigEndian.Uint64(value[0:])), Nanos: int32(binary.BigEndian.Uint32(value[8:]))},
+		latest:   &timestamp.Timestamp{Seconds: int64(binary.BigEndian.Uint64(value[12:])), Nanos: int32(binary.BigEndian.Uint32(value[20:]))},
+	}, nil
+}
@@ -17,7 +17,12 @@ limitations under the License.
 package historyleveldb
 
 import (
+	"bytes"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
+	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/ledger"
@@ -27,6 +32,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
 	putils "github.com/hyperledger/fabric/protos/utils"
 )
 
@@ -36,6 +42,9 @@ var compositeKeySep = []byte{0x00}
 var savePointKey = []byte{0x00}
 var emptyValue = []byte{}
 
+// maxDeletesPerBatch limits the number of records removed in a single batch while clearing the history db
+const maxDeletesPerBatch = 10000
+
 // HistoryDBProvider implements interface HistoryDBProvider
 type HistoryDBProvider struct {
 	dbProvider *leveldbhelper.Provider
@@ -89,6 +98,7 @@ func (historyDB *historyDB) Commit(block *common.Block) error {
 	var tranNo uint64
 
 	dbBatch := leveldbhelper.NewUpdateBatch()
+	timeRange := &blockTimeRange{}
 
 	logger.Debugf("Channel [%s]: Updating history database for blockNo [%v] with [%d] transactions",
 		historyDB.dbName, blockNo, len(block.Data.Data))
@@ -128,6 +138,7 @@ func (historyDB *historyDB) Commit(block *common.Block) error {
 		}
 
 		if common.HeaderType(chdr.Type) == common.HeaderType_ENDORSER_TRANSACTION {
+			timeRange.add(chdr.Timestamp)
 
 			// extract actions from the envelope message
 			respPayload, err := putils.GetActionFromEnvelope(envBytes)
@@ -165,6 +176,11 @@ func (historyDB *historyDB) Commit(block *common.Block) error {
 		tranNo++
 	}
 
+	// record the time range of the block for the history queries with a time range
+	if timeRange.earliest != nil {
+		dbBatch.Put(constructBlockTimeKey(blockNo), encodeBlockTimeRange(timeRange))
+	}
+
 	// add savepoint for recovery purpose
 	height := version.NewHeight(blockNo, tranNo)
 	dbBatch.Put(savePointKey, height.ToBytes())
@@ -215,3 +231,107 @@ func (historyDB *historyDB) CommitLostBlock(block *common.Block) error {
 	}
 	return nil
 }
+
+// GetHistoryRecordsIterator implements method in HistoryDB interface
+func (historyDB *historyDB) GetHistoryRecordsIterator(blockStore blkstorage.BlockStore) (commonledger.ResultsIterator, error) {
+	dbItr := historyDB.db.GetIterator(nil, nil)
+	return &historyRecordsScanner{dbItr, blockStore}, nil
+}
+
+// ImportHistoryRecords implements method in HistoryDB interface
+// The key modification is stored as the value of the record so that the queries on
+// the history db do not need to retrieve it from the block storage
+func (historyDB *historyDB) ImportHistoryRecords(records []*historydb.HistoryRecord, savepoint *version.Height) error {
+	dbBatch := leveldbhelper.NewUpdateBatch()
+	for _, record := range records {
+		keyModificationBytes, err := proto.Marshal(record.KeyModification)
+		if err != nil {
+			return err
+		}
+		dbBatch.Put(record.CompositeKey, keyModificationBytes)
+	}
+	dbBatch.Put(savePointKey, savepoint.ToBytes())
+	return historyDB.db.WriteBatch(dbBatch, true)
+}
+
+// Clear implements method in HistoryDB interface
+// The records are removed in multiple batches, the first of which removes the savepoint
+func (historyDB *historyDB) Clear() error {
+	logger.Infof("Channel [%s]: Removing all the records from history database", historyDB.dbName)
+	dbItr := historyDB.db.GetIterator(nil, nil)
+	defer dbItr.Release()
+	dbBatch := leveldbhelper.NewUpdateBatch()
+	dbBatch.Delete(savePointKey)
+	for dbItr.Next() {
+		dbBatch.Delete(dbItr.Key())
+		if len(dbBatch.KVs) == maxDeletesPerBatch {
+			if err := historyDB.db.WriteBatch(dbBatch, true); err != nil {
+				return err
+			}
+			dbBatch = leveldbhelper.NewUpdateBatch()
+		}
+	}
+	return historyDB.db.WriteBatch(dbBatch, true)
+}
+
+type historyRecordsScanner struct {
+	dbItr      *leveldbhelper.Iterator
+	blockStore blkstorage.BlockStore
+}
+
+func (scanner *historyRecordsScanner) Next() (commonledger.QueryResult, error) {
+	for scanner.dbItr.Next() {
+		historyKey := scanner.dbItr.Key()
+		if bytes.Equal(historyKey, savePointKey) || isBlockTimeKey(historyKey) {
+			continue
+		}
+		historyKeyCopy := make([]byte, len(historyKey))
+		copy(historyKeyCopy, historyKey)
+		keyModification, err := retrieveKeyModification(historyKeyCopy, scanner.dbItr.Value(), scanner.blockStore)
+		if err == blkstorage.ErrBlockPruned {
+			// The transaction of this history record has been removed by pruning
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &historydb.HistoryRecord{CompositeKey: historyKeyCopy, KeyModification: keyModification}, nil
+	}
+	return nil, nil
+}
+
+func (scanner *historyRecordsScanner) Close() {
+	scanner.dbItr.Release()
+}
+
+// retrieveKeyModification returns the key modification that the given history record refers to. The key modification
+// is either stored as the value of the record (for a ledger created from a snapshot) or retrieved from the block storage
+func retrieveKeyModification(historyKey []byte, value []byte, blockStore blkstorage.BlockStore) (*queryresult.KeyModification, error) {
+	if len(value) > 0 {
+		keyModification := &queryresult.KeyModification{}
+		if err := proto.Unmarshal(value, keyModification); err != nil {
+			return nil, err
+		}
+		return keyModification, nil
+	}
+	blockNums, tranNums := historydb.DecodeCompositeHistoryKey(historyKey)
+	for i := range blockNums {
+		tranEnvelope, err := blockStore.RetrieveTxByBlockNumTranNum(blockNums[i], tranNums[i])
+		if err != nil {
+			return nil, err
+		}
+		txID, timestamp, txRWSet, err := getTxRWSetFromTran(tranEnvelope)
+		if err != nil {
+			return nil, err
+		}
+		for _, nsRWSet := range txRWSet.NsRwSets {
+			for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+				if bytes.Equal(historydb.ConstructCompositeHistoryKey(nsRWSet.NameSpace, kvWrite.Key, blockNums[i], tranNums[i]), historyKey) {
+					return &queryresult.KeyModification{TxId: txID, Value: kvWrite.Value,
+						Timestamp: timestamp, IsDelete: kvWrite.IsDelete}, nil
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("Key modification not found for history key [%#v]", historyKey)
+}
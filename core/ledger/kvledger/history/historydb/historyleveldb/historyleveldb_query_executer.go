@@ -18,7 +18,10 @@ package historyleveldb
 
 import (
 	"errors"
+	"fmt"
+	"math"
 
+	"github.com/golang/protobuf/ptypes/timestamp"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util"
@@ -27,6 +30,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+	"github.com/hyperledger/fabric/protos/peer"
 	putils "github.com/hyperledger/fabric/protos/utils"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
 )
@@ -39,19 +43,50 @@ type LevelHistoryDBQueryExecutor struct {
 
 // GetHistoryForKey implements method in interface `ledger.HistoryQueryExecutor`
 func (q *LevelHistoryDBQueryExecutor) GetHistoryForKey(namespace string, key string) (commonledger.ResultsIterator, error) {
+	return q.GetHistoryForKeyWithOptions(namespace, key, &peer.HistoryQueryOptions{})
+}
+
+// GetHistoryForKeyWithOptions implements method in interface `ledger.HistoryQueryExecutor`
+// The block range is translated into the range of the history keys to scan, since the history keys are ordered by
+// height, whereas the time range is applied to the key modifications as they are retrieved from the block storage.
+// The history records of the blocks whose transactions all fall outside of the time range are skipped without
+// retrieving the blocks
+func (q *LevelHistoryDBQueryExecutor) GetHistoryForKeyWithOptions(namespace string, key string,
+	options *peer.HistoryQueryOptions) (commonledger.ResultsIterator, error) {
 
 	if ledgerconfig.IsHistoryDBEnabled() == false {
 		return nil, errors.New("History tracking not enabled - historyDatabase is false")
 	}
+	if options == nil {
+		options = &peer.HistoryQueryOptions{}
+	}
+	if options.HasEndBlock && options.EndBlock < options.StartBlock {
+		return nil, fmt.Errorf("Invalid block range [%d, %d] for history query", options.StartBlock, options.EndBlock)
+	}
+	if options.Limit < 0 {
+		return nil, fmt.Errorf("Invalid limit [%d] for history query", options.Limit)
+	}
 
-	var compositeStartKey []byte
-	var compositeEndKey []byte
-	compositeStartKey = historydb.ConstructPartialCompositeHistoryKey(namespace, key, false)
-	compositeEndKey = historydb.ConstructPartialCompositeHistoryKey(namespace, key, true)
+	compositePartialKey := historydb.ConstructPartialCompositeHistoryKey(namespace, key, false)
+	compositeStartKey := compositePartialKey
+	if options.StartBlock > 0 {
+		compositeStartKey = constructBlockBoundaryKey(compositePartialKey, options.StartBlock)
+	}
+	compositeEndKey := historydb.ConstructPartialCompositeHistoryKey(namespace, key, true)
+	if options.HasEndBlock && options.EndBlock < math.MaxUint64 {
+		compositeEndKey = constructBlockBoundaryKey(compositePartialKey, options.EndBlock+1)
+	}
 
-	// range scan to find any history records starting with namespace~key
+	// range scan to find the history records of namespace~key within the block range
 	dbItr := q.historyDB.db.GetIterator(compositeStartKey, compositeEndKey)
-	return newHistoryScanner(compositeStartKey, namespace, key, dbItr, q.blockStore), nil
+	return newHistoryScanner(compositePartialKey, namespace, key, dbItr, q.historyDB, q.blockStore, options), nil
+}
+
+// constructBlockBoundaryKey returns the smallest history key of namespace~key with the given block number
+func constructBlockBoundaryKey(compositePartialKey []byte, blockNum uint64) []byte {
+	var boundaryKey []byte
+	boundaryKey = append(boundaryKey, compositePartialKey...)
+	return append(boundaryKey, util.EncodeOrderPreservingVarUint64(blockNum)...)
 }
 
 //historyScanner implements ResultsIterator for iterating through history results
@@ -60,18 +95,98 @@ type historyScanner struct {
 	namespace           string
 	key                 string
 	dbItr               iterator.Iterator
+	historyDB           *historyDB
 	blockStore          blkstorage.BlockStore
+	options             *peer.HistoryQueryOptions
+	started             bool
+	numResults          int32
+	// the time range of the block of the last history record, see isOutsideTimeRange
+	timeRangeLoaded   bool
+	timeRangeBlockNum uint64
+	timeRange         *blockTimeRange
 }
 
-func newHistoryScanner(compositePartialKey []byte, namespace string, key string,
-	dbItr iterator.Iterator, blockStore blkstorage.BlockStore) *historyScanner {
-	return &historyScanner{compositePartialKey, namespace, key, dbItr, blockStore}
+func newHistoryScanner(compositePartialKey []byte, namespace string, key string, dbItr iterator.Iterator,
+	historyDB *historyDB, blockStore blkstorage.BlockStore, options *peer.HistoryQueryOptions) *historyScanner {
+	return &historyScanner{compositePartialKey: compositePartialKey, namespace: namespace, key: key,
+		dbItr: dbItr, historyDB: historyDB, blockStore: blockStore, options: options}
 }
 
 func (scanner *historyScanner) Next() (commonledger.QueryResult, error) {
-	if !scanner.dbItr.Next() {
-		return nil, nil
+	for {
+		if scanner.options.Limit > 0 && scanner.numResults >= scanner.options.Limit {
+			return nil, nil
+		}
+		if !scanner.advance() {
+			return nil, nil
+		}
+		outside, err := scanner.isOutsideTimeRange()
+		if err != nil {
+			return nil, err
+		}
+		if outside {
+			continue
+		}
+		keyModification, err := scanner.getKeyModification()
+		if err == blkstorage.ErrBlockPruned {
+			// The transaction of this history record has been removed by pruning, and so have
+			// the ones of all the older records
+			if scanner.options.Reverse {
+				return nil, nil
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !isInTimeRange(keyModification.Timestamp, scanner.options) {
+			continue
+		}
+		scanner.numResults++
+		return keyModification, nil
 	}
+}
+
+// advance moves the db iterator to the next history record, in the order requested by the options
+func (scanner *historyScanner) advance() bool {
+	if !scanner.options.Reverse {
+		return scanner.dbItr.Next()
+	}
+	if !scanner.started {
+		scanner.started = true
+		return scanner.dbItr.Last()
+	}
+	return scanner.dbItr.Prev()
+}
+
+// isOutsideTimeRange checks, using the time range recorded for the block of the current history record, whether
+// the key modification of the record is known to fall outside of the time range of the options. The time range
+// is not recorded for the blocks committed before it was introduced and for a ledger created from a snapshot
+func (scanner *historyScanner) isOutsideTimeRange() (bool, error) {
+	if scanner.options.StartTime == nil && scanner.options.EndTime == nil {
+		return false, nil
+	}
+	_, blockNumTranNumBytes := historydb.SplitCompositeHistoryKey(scanner.dbItr.Key(), scanner.compositePartialKey)
+	blockNum, _ := util.DecodeOrderPreservingVarUint64(blockNumTranNumBytes)
+	if !scanner.timeRangeLoaded || blockNum != scanner.timeRangeBlockNum {
+		scanner.timeRangeLoaded = true
+		scanner.timeRangeBlockNum = blockNum
+		scanner.timeRange = nil
+		value, err := scanner.historyDB.db.Get(constructBlockTimeKey(blockNum))
+		if err != nil {
+			return false, err
+		}
+		if value != nil {
+			if scanner.timeRange, err = decodeBlockTimeRange(value); err != nil {
+				return false, err
+			}
+		}
+	}
+	return scanner.timeRange != nil && !scanner.timeRange.overlaps(scanner.options), nil
+}
+
+// getKeyModification returns the key modification of the current history record
+func (scanner *historyScanner) getKeyModification() (*queryresult.KeyModification, error) {
 	historyKey := scanner.dbItr.Key() // history key is in the form namespace~key~blocknum~trannum
 
 	// SplitCompositeKey(namespace~key~blocknum~trannum, namespace~key~) will return the blocknum~trannum in second position
@@ -81,6 +196,11 @@ func (scanner *historyScanner) Next() (commonledger.QueryResult, error) {
 	logger.Debugf("Found history record for namespace:%s key:%s at blockNumTranNum %v:%v\n",
 		scanner.namespace, scanner.key, blockNum, tranNum)
 
+	// A ledger created from a snapshot stores the key modification in the history record itself
+	if value := scanner.dbItr.Value(); len(value) > 0 {
+		return retrieveKeyModification(historyKey, value, scanner.blockStore)
+	}
+
 	// Get the transaction from block storage that is associated with this history record
 	tranEnvelope, err := scanner.blockStore.RetrieveTxByBlockNumTranNum(blockNum, tranNum)
 	if err != nil {
@@ -92,64 +212,102 @@ func (scanner *historyScanner) Next() (commonledger.QueryResult, error) {
 	if err != nil {
 		return nil, err
 	}
+	keyModification := queryResult.(*queryresult.KeyModification)
 	logger.Debugf("Found historic key value for namespace:%s key:%s from transaction %s\n",
-		scanner.namespace, scanner.key, queryResult.(*queryresult.KeyModification).TxId)
-	return queryResult, nil
+		scanner.namespace, scanner.key, keyModification.TxId)
+	return keyModification, nil
 }
 
 func (scanner *historyScanner) Close() {
 	scanner.dbItr.Release()
 }
 
+// isInTimeRange checks whether the timestamp of a key modification falls in the time range of the options
+func isInTimeRange(ts *timestamp.Timestamp, options *peer.HistoryQueryOptions) bool {
+	if options.StartTime == nil && options.EndTime == nil {
+		return true
+	}
+	if ts == nil {
+		return false
+	}
+	if options.StartTime != nil && compareTimestamps(ts, options.StartTime) < 0 {
+		return false
+	}
+	if options.EndTime != nil && compareTimestamps(ts, options.EndTime) >= 0 {
+		return false
+	}
+	return true
+}
+
+func compareTimestamps(ts1, ts2 *timestamp.Timestamp) int {
+	switch {
+	case ts1.Seconds < ts2.Seconds:
+		return -1
+	case ts1.Seconds > ts2.Seconds:
+		return 1
+	case ts1.Nanos < ts2.Nanos:
+		return -1
+	case ts1.Nanos > ts2.Nanos:
+		return 1
+	}
+	return 0
+}
+
 // getTxIDandKeyWriteValueFromTran inspects a transaction for writes to a given key
 func getKeyModificationFromTran(tranEnvelope *common.Envelope, namespace string, key string) (commonledger.QueryResult, error) {
 	logger.Debugf("Entering getKeyModificationFromTran()\n", namespace, key)
 
+	txID, timestamp, txRWSet, err := getTxRWSetFromTran(tranEnvelope)
+	if err != nil {
+		return nil, err
+	}
+
+	// look for the namespace and key by looping through the transaction's ReadWriteSets
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if nsRWSet.NameSpace == namespace {
+			// got the correct namespace, now find the key write
+			for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+				if kvWrite.Key == key {
+					return &queryresult.KeyModification{TxId: txID, Value: kvWrite.Value,
+						Timestamp: timestamp, IsDelete: kvWrite.IsDelete}, nil
+				}
+			} // end keys loop
+			return nil, errors.New("Key not found in namespace's writeset")
+		} // end if
+	} //end namespaces loop
+	return nil, errors.New("Namespace not found in transaction's ReadWriteSets")
+
+}
+
+// getTxRWSetFromTran extracts the txid, the timestamp and the read-write set from a transaction
+func getTxRWSetFromTran(tranEnvelope *common.Envelope) (string, *timestamp.Timestamp, *rwsetutil.TxRwSet, error) {
 	// extract action from the envelope
 	payload, err := putils.GetPayload(tranEnvelope)
 	if err != nil {
-		return nil, err
+		return "", nil, nil, err
 	}
 
 	tx, err := putils.GetTransaction(payload.Data)
 	if err != nil {
-		return nil, err
+		return "", nil, nil, err
 	}
 
 	_, respPayload, err := putils.GetPayloads(tx.Actions[0])
 	if err != nil {
-		return nil, err
+		return "", nil, nil, err
 	}
 
 	chdr, err := putils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
 	if err != nil {
-		return nil, err
+		return "", nil, nil, err
 	}
 
-	txID := chdr.TxId
-	timestamp := chdr.Timestamp
-
 	txRWSet := &rwsetutil.TxRwSet{}
 
 	// Get the Result from the Action and then Unmarshal
 	// it into a TxReadWriteSet using custom unmarshalling
 	if err = txRWSet.FromProtoBytes(respPayload.Results); err != nil {
-		return nil, err
+		return "", nil, nil, err
 	}
-
-	// look for the namespace and key by looping through the transaction's ReadWriteSets
-	for _, nsRWSet := range txRWSet.NsRwSets {
-		if nsRWSet.NameSpace == namespace {
-			// got the correct namespace, now find the key write
-			for _, kvWrite := range nsRWSet.KvRwSet.Writes {
-				if kvWrite.Key == key {
-					return &queryresult.KeyModification{TxId: txID, Value: kvWrite.Value,
-						Timestamp: timestamp, IsDelete: kvWrite.IsDelete}, nil
-				}
-			} // end keys loop
-			return nil, errors.New("Key not found in namespace's writeset")
-		} // end if
-	} //end namespaces loop
-	return nil, errors.New("Namespace not found in transaction's ReadWriteSets")
-
+	return chdr.TxId, chdr.Timestamp, txRWSet, nil
 }
@@ -17,12 +17,16 @@ limitations under the License.
 package historyleveldb
 
 import (
+	"math"
 	"os"
 	"strconv"
 	"testing"
 
+	"github.com/golang/protobuf/ptypes/timestamp"
 	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
@@ -157,6 +161,106 @@ func TestHistory(t *testing.T) {
 	testutil.AssertEquals(t, count, 4)
 }
 
+func TestHistoryWithOptions(t *testing.T) {
+	env := NewTestHistoryEnv(t)
+	defer env.cleanup()
+	provider := env.testBlockStorageEnv.provider
+	ledger1id := "ledger1"
+	store1, err := provider.OpenBlockStore(ledger1id)
+	testutil.AssertNoError(t, err, "Error upon provider.OpenBlockStore()")
+	defer store1.Shutdown()
+
+	bg, gb := testutil.NewBlockGenerator(t, ledger1id, false)
+	testutil.AssertNoError(t, store1.AddBlock(gb), "")
+	testutil.AssertNoError(t, env.testHistoryDB.Commit(gb), "")
+
+	// block1 writes value1, block2 writes value2 and value3 (along with another key) and block3 writes value4
+	for _, blockValues := range [][]string{{"value1"}, {"value2", "value3"}, {"value4"}} {
+		simulationResults := [][]byte{}
+		for _, value := range blockValues {
+			simulator, _ := env.txmgr.NewTxSimulator()
+			simulator.SetState("ns1", "key7", []byte(value))
+			simulator.SetState("ns1", "key8", []byte("other"+value))
+			simulator.Done()
+			simRes, _ := simulator.GetTxSimulationResults()
+			simulationResults = append(simulationResults, simRes)
+		}
+		block := bg.NextBlock(simulationResults)
+		testutil.AssertNoError(t, store1.AddBlock(block), "")
+		testutil.AssertNoError(t, env.testHistoryDB.Commit(block), "")
+	}
+
+	qhistory, err := env.testHistoryDB.NewHistoryQueryExecutor(store1)
+	testutil.AssertNoError(t, err, "Error upon NewHistoryQueryExecutor")
+
+	testHistoryWithOptions(t, qhistory, nil, []string{"value1", "value2", "value3", "value4"})
+	testHistoryWithOptions(t, qhistory, &peer.HistoryQueryOptions{StartBlock: 2, EndBlock: 2, HasEndBlock: true}, []string{"value2", "value3"})
+	testHistoryWithOptions(t, qhistory, &peer.HistoryQueryOptions{StartBlock: 2}, []string{"value2", "value3", "value4"})
+	testHistoryWithOptions(t, qhistory, &peer.HistoryQueryOptions{EndBlock: 1, HasEndBlock: true}, []string{"value1"})
+	// the end block is only considered if it is flagged, and block 0 can be queried on its own
+	testHistoryWithOptions(t, qhistory, &peer.HistoryQueryOptions{EndBlock: 1}, []string{"value1", "value2", "value3", "value4"})
+	testHistoryWithOptions(t, qhistory, &peer.HistoryQueryOptions{HasEndBlock: true}, []string{})
+	testHistoryWithOptions(t, qhistory, &peer.HistoryQueryOptions{StartBlock: 3, EndBlock: math.MaxUint64, HasEndBlock: true}, []string{"value4"})
+	testHistoryWithOptions(t, qhistory, &peer.HistoryQueryOptions{StartBlock: 4}, []string{})
+	testHistoryWithOptions(t, qhistory, &peer.HistoryQueryOptions{Limit: 1}, []string{"value1"})
+	testHistoryWithOptions(t, qhistory, &peer.HistoryQueryOptions{Reverse: true}, []string{"value4", "value3", "value2", "value1"})
+	// latest N modifications
+	testHistoryWithOptions(t, qhistory, &peer.HistoryQueryOptions{Reverse: true, Limit: 2}, []string{"value4", "value3"})
+	testHistoryWithOptions(t, qhistory, &peer.HistoryQueryOptions{Reverse: true, StartBlock: 1, EndBlock: 2, HasEndBlock: true, Limit: 2}, []string{"value3", "value2"})
+
+	// time range, bounded by the timestamps of the transactions that wrote value2 and value4
+	kmods := getKeyModifications(t, qhistory, nil)
+	startTime, endTime := kmods[1].Timestamp, kmods[3].Timestamp
+	expectedValues := []string{}
+	for _, kmod := range kmods {
+		if compareTimestamps(kmod.Timestamp, startTime) >= 0 && compareTimestamps(kmod.Timestamp, endTime) < 0 {
+			expectedValues = append(expectedValues, string(kmod.Value))
+		}
+	}
+	testutil.AssertContains(t, expectedValues, "value2")
+	testutil.AssertEquals(t, testutil.Contains(expectedValues, "value4"), false)
+	testHistoryWithOptions(t, qhistory, &peer.HistoryQueryOptions{StartTime: startTime, EndTime: endTime}, expectedValues)
+
+	// the blocks whose transactions all fall outside of the time range are not retrieved from the block storage
+	countingStore := &countingBlockStore{BlockStore: store1}
+	qhistory, err = env.testHistoryDB.NewHistoryQueryExecutor(countingStore)
+	testutil.AssertNoError(t, err, "Error upon NewHistoryQueryExecutor")
+	lastTime := kmods[3].Timestamp
+	testHistoryWithOptions(t, qhistory, &peer.HistoryQueryOptions{StartTime: &timestamp.Timestamp{Seconds: lastTime.Seconds + 1}}, []string{})
+	testHistoryWithOptions(t, qhistory, &peer.HistoryQueryOptions{EndTime: kmods[0].Timestamp}, []string{})
+	testutil.AssertEquals(t, countingStore.numRetrievals, 0)
+	testHistoryWithOptions(t, qhistory, &peer.HistoryQueryOptions{StartTime: kmods[0].Timestamp}, []string{"value1", "value2", "value3", "value4"})
+	testutil.AssertEquals(t, countingStore.numRetrievals, 4)
+
+	_, err = qhistory.GetHistoryForKeyWithOptions("ns1", "key7", &peer.HistoryQueryOptions{StartBlock: 3, EndBlock: 2, HasEndBlock: true})
+	testutil.AssertError(t, err, "Expected an error for an invalid block range")
+	_, err = qhistory.GetHistoryForKeyWithOptions("ns1", "key7", &peer.HistoryQueryOptions{Limit: -1})
+	testutil.AssertError(t, err, "Expected an error for a negative limit")
+}
+
+func testHistoryWithOptions(t *testing.T, qhistory ledger.HistoryQueryExecutor, options *peer.HistoryQueryOptions, expectedValues []string) {
+	values := []string{}
+	for _, kmod := range getKeyModifications(t, qhistory, options) {
+		values = append(values, string(kmod.Value))
+	}
+	testutil.AssertEquals(t, values, expectedValues)
+}
+
+func getKeyModifications(t *testing.T, qhistory ledger.HistoryQueryExecutor, options *peer.HistoryQueryOptions) []*queryresult.KeyModification {
+	itr, err := qhistory.GetHistoryForKeyWithOptions("ns1", "key7", options)
+	testutil.AssertNoError(t, err, "Error upon GetHistoryForKeyWithOptions()")
+	defer itr.Close()
+	kmods := []*queryresult.KeyModification{}
+	for {
+		kmod, err := itr.Next()
+		testutil.AssertNoError(t, err, "")
+		if kmod == nil {
+			return kmods
+		}
+		kmods = append(kmods, kmod.(*queryresult.KeyModification))
+	}
+}
+
 func TestHistoryForInvalidTran(t *testing.T) {
 
 	env := NewTestHistoryEnv(t)
@@ -229,3 +333,99 @@ func TestGenesisBlockNoError(t *testing.T) {
 	err = env.testHistoryDB.Commit(block)
 	testutil.AssertNoError(t, err, "")
 }
+
+func TestClear(t *testing.T) {
+	env := NewTestHistoryEnv(t)
+	defer env.cleanup()
+	store, err := env.testBlockStorageEnv.provider.OpenBlockStore("ledger1")
+	testutil.AssertNoError(t, err, "")
+	defer store.Shutdown()
+
+	bg, gb := testutil.NewBlockGenerator(t, "ledger1", false)
+	testutil.AssertNoError(t, store.AddBlock(gb), "")
+	testutil.AssertNoError(t, env.testHistoryDB.Commit(gb), "")
+	simulator, _ := env.txmgr.NewTxSimulator()
+	simulator.SetState("ns1", "key1", []byte("value1"))
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	block1 := bg.NextBlock([][]byte{simRes})
+	testutil.AssertNoError(t, store.AddBlock(block1), "")
+	testutil.AssertNoError(t, env.testHistoryDB.Commit(block1), "")
+
+	testutil.AssertNoError(t, env.testHistoryDB.Clear(), "")
+	savepoint, err := env.testHistoryDB.GetLastSavepoint()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, savepoint)
+	qhistory, err := env.testHistoryDB.NewHistoryQueryExecutor(store)
+	testutil.AssertNoError(t, err, "")
+	itr, err := qhistory.GetHistoryForKey("ns1", "key1")
+	testutil.AssertNoError(t, err, "")
+	kmod, err := itr.Next()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, kmod)
+}
+
+// countingBlockStore counts the transactions retrieved from the block storage
+type countingBlockStore struct {
+	blkstorage.BlockStore
+	numRetrievals int
+}
+
+func (s *countingBlockStore) RetrieveTxByBlockNumTranNum(blockNum uint64, tranNum uint64) (*common.Envelope, error) {
+	s.numRetrievals++
+	return s.BlockStore.RetrieveTxByBlockNumTranNum(blockNum, tranNum)
+}
+
+// prunedBlockStore reports the blocks preceding firstBlockToRetain as pruned
+type prunedBlockStore struct {
+	blkstorage.BlockStore
+	firstBlockToRetain uint64
+}
+
+func (s *prunedBlockStore) RetrieveTxByBlockNumTranNum(blockNum uint64, tranNum uint64) (*common.Envelope, error) {
+	if blockNum < s.firstBlockToRetain {
+		return nil, blkstorage.ErrBlockPruned
+	}
+	return s.BlockStore.RetrieveTxByBlockNumTranNum(blockNum, tranNum)
+}
+
+func TestHistoryAfterPrune(t *testing.T) {
+	env := NewTestHistoryEnv(t)
+	defer env.cleanup()
+	store, err := env.testBlockStorageEnv.provider.OpenBlockStore("ledger1")
+	testutil.AssertNoError(t, err, "")
+	defer store.Shutdown()
+
+	bg, gb := testutil.NewBlockGenerator(t, "ledger1", false)
+	testutil.AssertNoError(t, store.AddBlock(gb), "")
+	testutil.AssertNoError(t, env.testHistoryDB.Commit(gb), "")
+	for _, value := range []string{"value1", "value2", "value3"} {
+		simulator, _ := env.txmgr.NewTxSimulator()
+		simulator.SetState("ns1", "key7", []byte(value))
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		block := bg.NextBlock([][]byte{simRes})
+		testutil.AssertNoError(t, store.AddBlock(block), "")
+		testutil.AssertNoError(t, env.testHistoryDB.Commit(block), "")
+	}
+
+	pruned := &prunedBlockStore{BlockStore: store, firstBlockToRetain: 3}
+	qhistory, err := env.testHistoryDB.NewHistoryQueryExecutor(pruned)
+	testutil.AssertNoError(t, err, "")
+	testHistoryWithOptions(t, qhistory, nil, []string{"value3"})
+	testHistoryWithOptions(t, qhistory, &peer.HistoryQueryOptions{Reverse: true}, []string{"value3"})
+
+	itr, err := env.testHistoryDB.GetHistoryRecordsIterator(pruned)
+	testutil.AssertNoError(t, err, "")
+	defer itr.Close()
+	numRecords := 0
+	for {
+		record, err := itr.Next()
+		testutil.AssertNoError(t, err, "")
+		if record == nil {
+			break
+		}
+		numRecords++
+	}
+	testutil.AssertEquals(t, numRecords, 1)
+}
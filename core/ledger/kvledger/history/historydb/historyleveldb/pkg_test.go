@@ -53,7 +53,7 @@ func NewTestHistoryEnv(t *testing.T) *levelDBLockBasedHistoryEnv {
 	testDB, err := testDBEnv.DBProvider.GetDBHandle("TestDB")
 	testutil.AssertNoError(t, err, "")
 
-	txMgr := lockbasedtxmgr.NewLockBasedTxMgr(testDB)
+	txMgr := lockbasedtxmgr.NewLockBasedTxMgr(testDB, nil)
 
 	testHistoryDBProvider := NewHistoryDBProvider()
 	testHistoryDB, err := testHistoryDBProvider.GetDBHandle("TestHistoryDB")
@@ -0,0 +1,292 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kvledger
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+)
+
+const (
+	stateDBName   = "state database"
+	historyDBName = "history database"
+
+	// maxStateDeletesPerBatch limits the number of keys removed in a single batch while clearing the state database
+	maxStateDeletesPerBatch = 10000
+)
+
+// VerifyIntegrity implements the corresponding method from interface ledger.PeerLedgerProvider
+// The state database and the history database are reported as inconsistent only if they cannot be brought
+// in sync with the block files by the recovery that takes place when the ledger is opened. For instance,
+// a savepoint that lags behind the block files is not reported if the blocks following the savepoint are available
+func (provider *Provider) VerifyIntegrity(ledgerID string) (*ledger.IntegrityReport, error) {
+	if err := provider.checkLedgerIDExists(ledgerID); err != nil {
+		return nil, err
+	}
+	blockStore, err := provider.blockStoreProvider.OpenBlockStore(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	defer blockStore.Shutdown()
+	blkReport, err := blockStore.VerifyIntegrity()
+	if err != nil {
+		return nil, err
+	}
+	report := &ledger.IntegrityReport{
+		LedgerID:         ledgerID,
+		BlockIssues:      blkReport.BlockIssues,
+		BlockIndexIssues: blkReport.IndexIssues,
+	}
+	if blkReport.NumBlocks > 0 {
+		report.Height = blkReport.LastBlockNum + 1
+	}
+
+	vDB, err := provider.vdbProvider.GetDBHandle(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	if err = vDB.Open(); err != nil {
+		return nil, err
+	}
+	defer vDB.Close()
+	stateSavepoint, err := vDB.GetLatestSavePoint()
+	if err != nil {
+		return nil, err
+	}
+	if report.StateDBIssues, err = provider.verifySavepoint(ledgerID, stateDBName, stateSavepoint, blkReport); err != nil {
+		return nil, err
+	}
+
+	if !ledgerconfig.IsHistoryDBEnabled() {
+		return report, nil
+	}
+	historyDB, err := provider.historydbProvider.GetDBHandle(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	historySavepoint, err := historyDB.GetLastSavepoint()
+	if err != nil {
+		return nil, err
+	}
+	if report.HistoryDBIssues, err = provider.verifySavepoint(ledgerID, historyDBName, historySavepoint, blkReport); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// verifySavepoint returns the reasons for which a database with the given savepoint cannot be brought in sync
+// with the block files by recommitting the blocks that follow the savepoint
+func (provider *Provider) verifySavepoint(ledgerID string, dbName string, savepoint *version.Height,
+	blkReport *blkstorage.IntegrityReport) ([]string, error) {
+	var issues []string
+	rebuildRequested, err := provider.idStore.isRebuildDBFlagSet(ledgerID, dbName)
+	if err != nil {
+		return nil, err
+	}
+	if rebuildRequested {
+		issues = append(issues, fmt.Sprintf("Rebuild of the %s has not completed", dbName))
+	}
+	// the block from which the database is recovered when the ledger is opened
+	recoveryBlockNum := uint64(0)
+	savepointDesc := "missing"
+	if savepoint != nil {
+		recoveryBlockNum = savepoint.BlockNum + 1
+		savepointDesc = fmt.Sprintf("block [%d]", savepoint.BlockNum)
+	}
+	switch {
+	case blkReport.NumBlocks == 0:
+		if savepoint != nil {
+			issues = append(issues, fmt.Sprintf("Savepoint of the %s is %s while the block files are empty", dbName, savepointDesc))
+		}
+	case savepoint != nil && savepoint.BlockNum > blkReport.LastBlockNum:
+		issues = append(issues, fmt.Sprintf("Savepoint of the %s is %s while the last block in the block files is [%d]",
+			dbName, savepointDesc, blkReport.LastBlockNum))
+	case recoveryBlockNum <= blkReport.LastBlockNum && recoveryBlockNum < blkReport.FirstBlockNum:
+		issues = append(issues, fmt.Sprintf("Savepoint of the %s is %s while the first block in the block files is [%d]",
+			dbName, savepointDesc, blkReport.FirstBlockNum))
+	}
+	return issues, nil
+}
+
+// Repair implements the corresponding method from interface ledger.PeerLedgerProvider
+// The state database and the history database are rebuilt by recommitting all the blocks starting from the genesis
+// block and hence, these cannot be rebuilt for a ledger that was created from a snapshot or whose blocks were pruned.
+// Since the private data is not part of the blocks, a rebuilt state database holds only the hashes of the private data
+func (provider *Provider) Repair(ledgerID string, options *ledger.RepairOptions) error {
+	if err := provider.checkLedgerIDExists(ledgerID); err != nil {
+		return err
+	}
+	rebuildHistoryDB := options.RebuildHistoryDB
+	if rebuildHistoryDB && !ledgerconfig.IsHistoryDBEnabled() {
+		logger.Warningf("Channel [%s]: History database is disabled, skipping its rebuild", ledgerID)
+		rebuildHistoryDB = false
+	}
+	if err := provider.prepareBlockStoreForRepair(ledgerID, options.RebuildBlockIndex,
+		options.RebuildStateDB || rebuildHistoryDB); err != nil {
+		return err
+	}
+
+	if options.RebuildStateDB {
+		if err := provider.idStore.setRebuildDBFlag(ledgerID, stateDBName); err != nil {
+			return err
+		}
+	}
+	if rebuildHistoryDB {
+		if err := provider.idStore.setRebuildDBFlag(ledgerID, historyDBName); err != nil {
+			return err
+		}
+	}
+	if !options.RebuildStateDB && !rebuildHistoryDB {
+		return nil
+	}
+	// the ledger completes the requested rebuilds while it is being opened
+	l, err := provider.openInternal(ledgerID)
+	if err != nil {
+		return err
+	}
+	l.Close()
+	return nil
+}
+
+// prepareBlockStoreForRepair rebuilds the block index, if requested, and checks that the genesis block is available
+// if the databases are to be rebuilt
+func (provider *Provider) prepareBlockStoreForRepair(ledgerID string, rebuildIndex bool, rebuildDBs bool) error {
+	if !rebuildIndex && !rebuildDBs {
+		return nil
+	}
+	blockStore, err := provider.blockStoreProvider.OpenBlockStore(ledgerID)
+	if err != nil {
+		return err
+	}
+	defer blockStore.Shutdown()
+	if rebuildIndex {
+		if err = blockStore.RebuildIndex(); err != nil {
+			return err
+		}
+		logger.Infof("Channel [%s]: Rebuilt the block index", ledgerID)
+	}
+	if !rebuildDBs {
+		return nil
+	}
+	if _, err = blockStore.RetrieveBlockByNumber(0); err != nil {
+		return fmt.Errorf("Cannot rebuild the databases of ledger [%s] because the genesis block is not available: %s", ledgerID, err)
+	}
+	return nil
+}
+
+func (provider *Provider) checkLedgerIDExists(ledgerID string) error {
+	exists, err := provider.idStore.ledgerIDExists(ledgerID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNonExistingLedgerID
+	}
+	return nil
+}
+
+// rebuildable is a database of the ledger that can be rebuilt by clearing it and recommitting all the blocks
+type rebuildable struct {
+	dbName      string
+	clear       func() error
+	recoverable recoverable
+}
+
+// rebuildDBsIfRequested rebuilds the databases for which a rebuild is recorded in the id store
+func (l *kvLedger) rebuildDBsIfRequested() error {
+	rebuildables := []*rebuildable{
+		{stateDBName, l.clearStateDB, l.txtmgmt},
+		{historyDBName, l.historyDB.Clear, l.historyDB},
+	}
+	for _, r := range rebuildables {
+		rebuildRequested, err := l.idStore.isRebuildDBFlagSet(l.ledgerID, r.dbName)
+		if err != nil {
+			return err
+		}
+		if !rebuildRequested {
+			continue
+		}
+		if err = l.rebuildDB(r); err != nil {
+			return err
+		}
+		if err = l.idStore.unsetRebuildDBFlag(l.ledgerID, r.dbName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *kvLedger) rebuildDB(r *rebuildable) error {
+	info, err := l.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return err
+	}
+	logger.Infof("Channel [%s]: Rebuilding the %s from [%d] block(s)", l.ledgerID, r.dbName, info.Height)
+	if err = r.clear(); err != nil {
+		return err
+	}
+	if info.Height == 0 {
+		return nil
+	}
+	if err = l.recommitLostBlocks(0, info.Height-1, r.recoverable); err != nil {
+		return err
+	}
+	logger.Infof("Channel [%s]: Rebuilt the %s", l.ledgerID, r.dbName)
+	return nil
+}
+
+// clearStateDB removes all the keys from the state database, except the private data of the collections, which is
+// not part of the blocks and so could not be restored by recommitting them (the hashes of the private data are part
+// of the blocks and are rebuilt). The savepoint of the state database cannot be removed and is set to the genesis
+// block, which is of no consequence since the blocks are recommitted from the genesis block
+func (l *kvLedger) clearStateDB() error {
+	for {
+		itr, err := l.versionedDB.GetFullScanIterator()
+		if err != nil {
+			return err
+		}
+		batch := statedb.NewUpdateBatch()
+		numKeys := 0
+		for numKeys < maxStateDeletesPerBatch {
+			result, err := itr.Next()
+			if err != nil {
+				itr.Close()
+				return err
+			}
+			if result == nil {
+				break
+			}
+			vkv := result.(*statedb.VersionedKV)
+			if statedb.IsPvtDataNs(vkv.Namespace) {
+				continue
+			}
+			batch.Delete(vkv.Namespace, vkv.Key, vkv.Version)
+			numKeys++
+		}
+		itr.Close()
+		if numKeys == 0 {
+			return nil
+		}
+		if err = l.versionedDB.ApplyUpdates(batch, version.NewHeight(0, 0)); err != nil {
+			return err
+		}
+	}
+}
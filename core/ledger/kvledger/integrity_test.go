@@ -0,0 +1,174 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kvledger
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+	"github.com/spf13/viper"
+)
+
+func TestLedgerVerifyAndRepair(t *testing.T) {
+	viper.Set("ledger.history.enableHistoryDatabase", true)
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider := createTestLedgerWithBlocks(t, "testLedger")
+	defer provider.Close()
+
+	_, err := provider.VerifyIntegrity("nonExistingLedger")
+	testutil.AssertEquals(t, err, ErrNonExistingLedgerID)
+	report, err := provider.VerifyIntegrity("testLedger")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, report, &ledger.IntegrityReport{LedgerID: "testLedger", Height: 3})
+	testutil.AssertEquals(t, report.IsConsistent(), true)
+
+	// move the savepoint of the state database ahead of the block files along with a key that no block writes
+	vDB, err := provider.vdbProvider.GetDBHandle("testLedger")
+	testutil.AssertNoError(t, err, "")
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key3", []byte("value5"), version.NewHeight(5, 0))
+	// the private data is not part of the blocks and is expected to survive the rebuild
+	batch.Put(statedb.DerivePvtDataNs("ns1", "coll1"), "pvtkey1", []byte("pvtvalue1"), version.NewHeight(2, 0))
+	testutil.AssertNoError(t, vDB.ApplyUpdates(batch, version.NewHeight(5, 0)), "")
+	report, err = provider.VerifyIntegrity("testLedger")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, report.StateDBIssues,
+		[]string{"Savepoint of the state database is block [5] while the last block in the block files is [2]"})
+	testutil.AssertEquals(t, report.RepairOptions(), &ledger.RepairOptions{RebuildStateDB: true})
+
+	testutil.AssertNoError(t, provider.Repair("testLedger", report.RepairOptions()), "")
+	report, err = provider.VerifyIntegrity("testLedger")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, report.IsConsistent(), true)
+	l, err := provider.Open("testLedger")
+	testutil.AssertNoError(t, err, "")
+	defer l.Close()
+	qe, _ := l.NewQueryExecutor()
+	defer qe.Done()
+	value, _ := qe.GetState("ns1", "key1")
+	testutil.AssertEquals(t, value, []byte("value3"))
+	value, _ = qe.GetState("ns1", "key2")
+	testutil.AssertNil(t, value)
+	value, _ = qe.GetState("ns1", "key3")
+	testutil.AssertNil(t, value)
+	vv, err := vDB.GetState(statedb.DerivePvtDataNs("ns1", "coll1"), "pvtkey1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, vv.Value, []byte("pvtvalue1"))
+}
+
+func TestLedgerResumeInterruptedRebuild(t *testing.T) {
+	viper.Set("ledger.history.enableHistoryDatabase", true)
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider := createTestLedgerWithBlocks(t, "testLedger")
+	defer provider.Close()
+
+	// a rebuild that is interrupted after clearing the history database leaves behind the rebuild flag
+	historyDB, err := provider.historydbProvider.GetDBHandle("testLedger")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNoError(t, provider.idStore.setRebuildDBFlag("testLedger", historyDBName), "")
+	testutil.AssertNoError(t, historyDB.Clear(), "")
+	report, err := provider.VerifyIntegrity("testLedger")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, report.HistoryDBIssues, []string{"Rebuild of the history database has not completed"})
+
+	// the rebuild is resumed when the ledger is opened
+	l, err := provider.Open("testLedger")
+	testutil.AssertNoError(t, err, "")
+	hqe, err := l.NewHistoryQueryExecutor()
+	testutil.AssertNoError(t, err, "")
+	itr, err := hqe.GetHistoryForKey("ns1", "key1")
+	testutil.AssertNoError(t, err, "")
+	values := [][]byte{}
+	for {
+		kmod, _ := itr.Next()
+		if kmod == nil {
+			break
+		}
+		values = append(values, kmod.(*queryresult.KeyModification).Value)
+	}
+	testutil.AssertEquals(t, values, [][]byte{[]byte("value1"), []byte("value3")})
+	l.Close()
+	report, err = provider.VerifyIntegrity("testLedger")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, report.IsConsistent(), true)
+}
+
+func TestLedgerRepairWithoutGenesisBlock(t *testing.T) {
+	snapshotDir := "/tmp/fabric/ledgertests/integritysnapshot"
+	os.RemoveAll(snapshotDir)
+	defer os.RemoveAll(snapshotDir)
+	env := createTestEnv(t, "/tmp/fabric/ledgertests/integrity1")
+	provider := createTestLedgerWithBlocks(t, "testLedger")
+	l, err := provider.Open("testLedger")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNoError(t, l.ExportSnapshot(snapshotDir), "")
+	l.Close()
+	provider.Close()
+	env.cleanup()
+
+	// a ledger created from a snapshot does not hold the blocks preceding the snapshot
+	env = createTestEnv(t, "/tmp/fabric/ledgertests/integrity2")
+	defer env.cleanup()
+	p, _ := NewProvider()
+	provider = p.(*Provider)
+	defer provider.Close()
+	l, err = provider.CreateFromSnapshot(snapshotDir)
+	testutil.AssertNoError(t, err, "")
+	l.Close()
+	report, err := provider.VerifyIntegrity("testLedger")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, report.IsConsistent(), true)
+
+	err = provider.Repair("testLedger", &ledger.RepairOptions{RebuildStateDB: true})
+	testutil.AssertError(t, err, "Expected an error while rebuilding the state database without the genesis block")
+	rebuildRequested, err := provider.idStore.isRebuildDBFlagSet("testLedger", stateDBName)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, rebuildRequested, false)
+	testutil.AssertNoError(t, provider.Repair("testLedger", &ledger.RepairOptions{RebuildBlockIndex: true}), "")
+}
+
+// createTestLedgerWithBlocks creates a ledger with two blocks in addition to the genesis block and closes the ledger
+func createTestLedgerWithBlocks(t *testing.T, ledgerID string) *Provider {
+	p, err := NewProvider()
+	testutil.AssertNoError(t, err, "")
+	provider := p.(*Provider)
+	bg, gb := testutil.NewBlockGenerator(t, ledgerID, false)
+	l, err := provider.Create(gb)
+	testutil.AssertNoError(t, err, "")
+	defer l.Close()
+
+	simulator, _ := l.NewTxSimulator()
+	simulator.SetState("ns1", "key1", []byte("value1"))
+	simulator.SetState("ns1", "key2", []byte("value2"))
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	testutil.AssertNoError(t, l.Commit(bg.NextBlock([][]byte{simRes})), "")
+
+	simulator, _ = l.NewTxSimulator()
+	simulator.SetState("ns1", "key1", []byte("value3"))
+	simulator.DeleteState("ns1", "key2")
+	simulator.Done()
+	simRes, _ = simulator.GetTxSimulationResults()
+	testutil.AssertNoError(t, l.Commit(bg.NextBlock([][]byte{simRes})), "")
+	return provider
+}
@@ -17,13 +17,14 @@ limitations under the License.
 package kvledger
 
 import (
-	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/hyperledger/fabric/common/flogging"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/blobstore"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr"
@@ -38,31 +39,57 @@ var logger = flogging.MustGetLogger("kvledger")
 // KVLedger provides an implementation of `ledger.PeerLedger`.
 // This implementation provides a key-value based data model
 type kvLedger struct {
-	ledgerID   string
-	blockStore blkstorage.BlockStore
-	txtmgmt    txmgr.TxMgr
-	historyDB  historydb.HistoryDB
+	ledgerID    string
+	blockStore  blkstorage.BlockStore
+	versionedDB statedb.VersionedDB
+	txtmgmt     txmgr.TxMgr
+	historyDB   historydb.HistoryDB
+	blobStore   blobstore.Store
+	idStore     *idStore
+	// retainedConfigBlock is the last config block, if the block storage does not contain it
+	// (i.e., if the ledger was created from a snapshot taken after this block or if the block was pruned)
+	retainedConfigBlock *common.Block
+	configBlockLock     sync.RWMutex
+	commitLock          sync.Mutex
+	stateListeners      map[string]ledger.StateListener
 }
 
 // NewKVLedger constructs new `KVLedger`
 func newKVLedger(ledgerID string, blockStore blkstorage.BlockStore,
-	versionedDB statedb.VersionedDB, historyDB historydb.HistoryDB) (*kvLedger, error) {
+	versionedDB statedb.VersionedDB, historyDB historydb.HistoryDB, blobStore blobstore.Store, idStore *idStore,
+	stateListeners map[string]ledger.StateListener) (*kvLedger, error) {
 
 	logger.Debugf("Creating KVLedger ledgerID=%s: ", ledgerID)
 
-	//Initialize transaction manager using state database
-	var txmgmt txmgr.TxMgr
-	txmgmt = lockbasedtxmgr.NewLockBasedTxMgr(versionedDB)
-
 	// Create a kvLedger for this chain/ledger, which encasulates the underlying
 	// id store, blockstore, txmgr (state database), history database
-	l := &kvLedger{ledgerID, blockStore, txmgmt, historyDB}
+	l := &kvLedger{ledgerID: ledgerID, blockStore: blockStore, versionedDB: versionedDB,
+		historyDB: historyDB, blobStore: blobStore, idStore: idStore, stateListeners: stateListeners}
+
+	//Initialize transaction manager using state database, the config blocks read during
+	//the validation are retrieved from the ledger
+	l.txtmgmt = lockbasedtxmgr.NewLockBasedTxMgr(versionedDB, l.GetBlockByNumber)
+
+	var err error
+	if l.retainedConfigBlock, err = idStore.getRetainedConfigBlock(ledgerID); err != nil {
+		return nil, err
+	}
+
+	//Complete the rebuild of the state DB and history DB, if requested by a repair of the ledger
+	if err := l.rebuildDBsIfRequested(); err != nil {
+		return nil, err
+	}
 
 	//Recover both state DB and history DB if they are out of sync with block storage
 	if err := l.recoverDBs(); err != nil {
 		panic(fmt.Errorf(`Error during state DB recovery:%s`, err))
 	}
 
+	//Recover the blob store if it is out of sync with block storage
+	if err := l.recoverBlobStore(); err != nil {
+		panic(fmt.Errorf(`Error during blob store recovery:%s`, err))
+	}
+
 	return l, nil
 }
 
@@ -112,6 +139,21 @@ func (l *kvLedger) recoverDBs() error {
 		recoverers[0].recoverable, recoverers[1].recoverable)
 }
 
+//Recover the blob store by recommitting last valid blocks. The blob store only depends on
+//the blocks, hence it is recovered independently of the state database and history database
+func (l *kvLedger) recoverBlobStore() error {
+	info, _ := l.blockStore.GetBlockchainInfo()
+	if info.Height == 0 {
+		return nil
+	}
+	lastAvailableBlockNum := info.Height - 1
+	recoverFlag, firstBlockNum, err := l.blobStore.ShouldRecover(lastAvailableBlockNum)
+	if err != nil || !recoverFlag {
+		return err
+	}
+	return l.recommitLostBlocks(firstBlockNum, lastAvailableBlockNum, l.blobStore)
+}
+
 //recommitLostBlocks retrieves blocks in specified range and commit the write set to either
 //state DB or history DB or both
 func (l *kvLedger) recommitLostBlocks(firstBlockNum uint64, lastBlockNum uint64, recoverables ...recoverable) error {
@@ -125,6 +167,9 @@ func (l *kvLedger) recommitLostBlocks(firstBlockNum uint64, lastBlockNum uint64,
 			if err := r.CommitLostBlock(block); err != nil {
 				return err
 			}
+			if r == l.txtmgmt {
+				l.notifyStateListeners(block)
+			}
 		}
 	}
 	return nil
@@ -156,8 +201,25 @@ func (l *kvLedger) GetBlockchainInfo() (*common.BlockchainInfo, error) {
 // GetBlockByNumber returns block at a given height
 // blockNumber of  math.MaxUint64 will return last block
 func (l *kvLedger) GetBlockByNumber(blockNumber uint64) (*common.Block, error) {
-	return l.blockStore.RetrieveBlockByNumber(blockNumber)
+	block, err := l.blockStore.RetrieveBlockByNumber(blockNumber)
+	if err == blkstorage.ErrNotFoundInIndex || err == blkstorage.ErrBlockPruned {
+		if configBlock := l.getRetainedConfigBlock(); configBlock != nil && configBlock.Header.Number == blockNumber {
+			return configBlock, nil
+		}
+	}
+	return block, err
+}
+
+func (l *kvLedger) getRetainedConfigBlock() *common.Block {
+	l.configBlockLock.RLock()
+	defer l.configBlockLock.RUnlock()
+	return l.retainedConfigBlock
+}
 
+func (l *kvLedger) setRetainedConfigBlock(configBlock *common.Block) {
+	l.configBlockLock.Lock()
+	defer l.configBlockLock.Unlock()
+	l.retainedConfigBlock = configBlock
 }
 
 // GetBlocksIterator returns an iterator that starts from `startBlockNumber`(inclusive).
@@ -182,11 +244,6 @@ func (l *kvLedger) GetTxValidationCodeByTxID(txID string) (peer.TxValidationCode
 	return l.blockStore.RetrieveTxValidationCodeByTxID(txID)
 }
 
-//Prune prunes the blocks/transactions that satisfy the given policy
-func (l *kvLedger) Prune(policy commonledger.PrunePolicy) error {
-	return errors.New("Not yet implemented")
-}
-
 // NewTxSimulator returns new `ledger.TxSimulator`
 func (l *kvLedger) NewTxSimulator() (ledger.TxSimulator, error) {
 	return l.txtmgmt.NewTxSimulator()
@@ -199,6 +256,11 @@ func (l *kvLedger) NewQueryExecutor() (ledger.QueryExecutor, error) {
 	return l.txtmgmt.NewQueryExecutor()
 }
 
+// NewReadRecordingQueryExecutor gives handle to a query executor recording the versions of the keys it reads
+func (l *kvLedger) NewReadRecordingQueryExecutor() (ledger.ReadRecordingQueryExecutor, error) {
+	return l.txtmgmt.NewReadRecordingQueryExecutor()
+}
+
 // NewHistoryQueryExecutor gives handle to a history query executor.
 // A client can obtain more than one 'HistoryQueryExecutor's for parallel execution.
 // Any synchronization should be performed at the implementation level if required
@@ -207,13 +269,38 @@ func (l *kvLedger) NewHistoryQueryExecutor() (ledger.HistoryQueryExecutor, error
 	return l.historyDB.NewHistoryQueryExecutor(l.blockStore)
 }
 
+// CreateChaincodeIndexes implements method in interface `ledger.PeerLedger`
+func (l *kvLedger) CreateChaincodeIndexes(namespace string, indexes map[string]map[string][]byte) error {
+	indexCapable, ok := l.versionedDB.(statedb.IndexCapable)
+	if !ok {
+		logger.Debugf("Channel [%s]: State database does not support indexes, skipping the indexes for chaincode [%s]", l.ledgerID, namespace)
+		return nil
+	}
+	indexFiles := indexes[indexCapable.GetDBType()]
+	if len(indexFiles) == 0 {
+		logger.Debugf("Channel [%s]: No %s indexes found for chaincode [%s]", l.ledgerID, indexCapable.GetDBType(), namespace)
+		return nil
+	}
+	logger.Infof("Channel [%s]: Creating %d index(es) for chaincode [%s]", l.ledgerID, len(indexFiles), namespace)
+	return indexCapable.ProcessIndexesForChaincodeDeploy(namespace, indexFiles)
+}
+
 // Commit commits the valid block (returned in the method RemoveInvalidTransactionsAndPrepare) and related state changes
 func (l *kvLedger) Commit(block *common.Block) error {
+	return l.CommitWithPvtData(&ledger.BlockAndPvtData{Block: block})
+}
+
+// CommitWithPvtData commits the block and the private data of its valid transactions
+func (l *kvLedger) CommitWithPvtData(blockAndPvtdata *ledger.BlockAndPvtData) error {
 	var err error
+	block := blockAndPvtdata.Block
 	blockNo := block.Header.Number
 
+	l.commitLock.Lock()
+	defer l.commitLock.Unlock()
+
 	logger.Debugf("Channel [%s]: Validating block [%d]", l.ledgerID, blockNo)
-	err = l.txtmgmt.ValidateAndPrepare(block, true)
+	err = l.txtmgmt.ValidateAndPrepareWithPvtData(blockAndPvtdata, true)
 	if err != nil {
 		return err
 	}
@@ -228,6 +315,7 @@ func (l *kvLedger) Commit(block *common.Block) error {
 	if err = l.txtmgmt.Commit(); err != nil {
 		panic(fmt.Errorf(`Error during commit to txmgr:%s`, err))
 	}
+	l.notifyStateListeners(block)
 
 	// History database could be written in parallel with state and/or async as a future optimization
 	if ledgerconfig.IsHistoryDBEnabled() {
@@ -237,9 +325,22 @@ func (l *kvLedger) Commit(block *common.Block) error {
 		}
 	}
 
+	logger.Debugf("Channel [%s]: Committing block [%d] blobs to blob store", l.ledgerID, blockNo)
+	if err := l.blobStore.Commit(block); err != nil {
+		panic(fmt.Errorf(`Error during commit to blob store:%s`, err))
+	}
+
+	if (blockNo+1)%ledgerconfig.GetBlockPruneInterval() == 0 {
+		l.pruneAsConfigured()
+	}
 	return nil
 }
 
+// GetBlobStore implements method in interface `ledger.PeerLedger`
+func (l *kvLedger) GetBlobStore() blobstore.Store {
+	return l.blobStore
+}
+
 // Close closes `KVLedger`
 func (l *kvLedger) Close() {
 	l.blockStore.Shutdown()
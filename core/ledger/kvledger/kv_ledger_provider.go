@@ -17,7 +17,6 @@ limitations under the License.
 package kvledger
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 
@@ -26,11 +25,13 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/blobstore"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb/historyleveldb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
-	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statecouchdb"
-	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateleveldb"
+	// the state databases built into the peer register themselves with statedb
+	_ "github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statecouchdb"
+	_ "github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateleveldb"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -47,6 +48,9 @@ var (
 
 	underConstructionLedgerKey = []byte("underConstructionLedgerKey")
 	ledgerKeyPrefix            = []byte("l")
+	ledgerKeyStop              = []byte("m")
+	configBlockKeyPrefix       = []byte("c")
+	rebuildDBKeyPrefix         = []byte("r")
 )
 
 // Provider implements interface ledger.PeerLedgerProvider
@@ -55,14 +59,30 @@ type Provider struct {
 	blockStoreProvider blkstorage.BlockStoreProvider
 	vdbProvider        statedb.VersionedDBProvider
 	historydbProvider  historydb.HistoryDBProvider
+	blobStoreProvider  blobstore.StoreProvider
+	stateListeners     map[string]ledger.StateListener
 }
 
 // NewProvider instantiates a new Provider.
 // This is not thread-safe and assumed to be synchronized be the caller
 func NewProvider() (ledger.PeerLedgerProvider, error) {
+	return NewProviderWithStateListeners(nil)
+}
+
+// NewProviderWithStateListeners instantiates a new Provider whose ledgers notify the given
+// listeners, keyed by namespace, of the committed writes to their namespace
+func NewProviderWithStateListeners(stateListeners map[string]ledger.StateListener) (ledger.PeerLedgerProvider, error) {
 
 	logger.Info("Initializing ledger provider")
 
+	// Initialize the versioned database (state database)
+	stateDatabase := ledgerconfig.GetStateDatabase()
+	logger.Debugf("Constructing [%s] VersionedDBProvider", stateDatabase)
+	vdbProvider, err := statedb.NewVersionedDBProvider(stateDatabase)
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize the ID store (inventory of chainIds/ledgerIds)
 	idStore := openIDStore(ledgerconfig.GetLedgerProviderPath())
 
@@ -80,26 +100,15 @@ func NewProvider() (ledger.PeerLedgerProvider, error) {
 		fsblkstorage.NewConf(ledgerconfig.GetBlockStorePath(), ledgerconfig.GetMaxBlockfileSize()),
 		indexConfig)
 
-	// Initialize the versioned database (state database)
-	var vdbProvider statedb.VersionedDBProvider
-	if !ledgerconfig.IsCouchDBEnabled() {
-		logger.Debug("Constructing leveldb VersionedDBProvider")
-		vdbProvider = stateleveldb.NewVersionedDBProvider()
-	} else {
-		logger.Debug("Constructing CouchDB VersionedDBProvider")
-		var err error
-		vdbProvider, err = statecouchdb.NewVersionedDBProvider()
-		if err != nil {
-			return nil, err
-		}
-	}
-
 	// Initialize the history database (index for history of values by key)
 	var historydbProvider historydb.HistoryDBProvider
 	historydbProvider = historyleveldb.NewHistoryDBProvider()
 
+	// Initialize the blob store (state values streamed by the chaincodes)
+	blobStoreProvider := blobstore.NewStoreProvider()
+
 	logger.Info("ledger provider Initialized")
-	provider := &Provider{idStore, blockStoreProvider, vdbProvider, historydbProvider}
+	provider := &Provider{idStore, blockStoreProvider, vdbProvider, historydbProvider, blobStoreProvider, stateListeners}
 	provider.recoverUnderConstructionLedger()
 	return provider, nil
 }
@@ -172,9 +181,15 @@ func (provider *Provider) openInternal(ledgerID string) (ledger.PeerLedger, erro
 		return nil, err
 	}
 
+	// Get the blob store (state values streamed by the chaincodes) for a chain/ledger
+	blobStore, err := provider.blobStoreProvider.OpenStore(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create a kvLedger for this chain/ledger, which encasulates the underlying data stores
-	// (id store, blockstore, state database, history database)
-	l, err := newKVLedger(ledgerID, blockStore, vDB, historyDB)
+	// (id store, blockstore, state database, history database, blob store)
+	l, err := newKVLedger(ledgerID, blockStore, vDB, historyDB, blobStore, provider.idStore, provider.stateListeners)
 	if err != nil {
 		return nil, err
 	}
@@ -197,6 +212,7 @@ func (provider *Provider) Close() {
 	provider.blockStoreProvider.Close()
 	provider.vdbProvider.Close()
 	provider.historydbProvider.Close()
+	provider.blobStoreProvider.Close()
 }
 
 // recoverUnderConstructionLedger checks whether the under construction flag is set - this would be the case
@@ -229,6 +245,13 @@ func (provider *Provider) recoverUnderConstructionLedger() {
 		panicOnErr(err, "Error while retrieving genesis block from blockchain for ledger [%s]", ledgerID)
 		panicOnErr(provider.idStore.createLedgerID(ledgerID, genesisBlock), "Error while adding ledgerID [%s] to created list", ledgerID)
 	default:
+		if _, err := ledger.GetBlockByNumber(0); err == blkstorage.ErrNotFoundInIndex {
+			logger.Infof("Ledger was created from a snapshot. Hence, marking the peer ledger as created")
+			lastBlock, err := ledger.GetBlockByNumber(bcInfo.Height - 1)
+			panicOnErr(err, "Error while retrieving last block from blockchain for ledger [%s]", ledgerID)
+			panicOnErr(provider.idStore.createLedgerID(ledgerID, lastBlock), "Error while adding ledgerID [%s] to created list", ledgerID)
+			return
+		}
 		panic(fmt.Errorf(
 			"Data inconsistency: under construction flag is set for ledger [%s] while the height of the blockchain is [%d]",
 			ledgerID, bcInfo.Height))
@@ -315,19 +338,55 @@ func (s *idStore) ledgerIDExists(ledgerID string) (bool, error) {
 
 func (s *idStore) getAllLedgerIds() ([]string, error) {
 	var ids []string
-	itr := s.db.GetIterator(nil, nil)
-	itr.First()
-	for itr.Valid() {
-		if bytes.Equal(itr.Key(), underConstructionLedgerKey) {
-			continue
-		}
+	itr := s.db.GetIterator(ledgerKeyPrefix, ledgerKeyStop)
+	defer itr.Release()
+	for itr.Next() {
 		id := string(s.decodeLedgerID(itr.Key()))
 		ids = append(ids, id)
-		itr.Next()
 	}
 	return ids, nil
 }
 
+// setRetainedConfigBlock saves the last config block of a ledger whose block storage does not contain
+// the block, either because the ledger was created from a snapshot or because the block was pruned
+func (s *idStore) setRetainedConfigBlock(ledgerID string, configBlock *common.Block) error {
+	val, err := proto.Marshal(configBlock)
+	if err != nil {
+		return err
+	}
+	return s.db.Put(s.encodeConfigBlockKey(ledgerID), val, true)
+}
+
+func (s *idStore) getRetainedConfigBlock(ledgerID string) (*common.Block, error) {
+	val, err := s.db.Get(s.encodeConfigBlockKey(ledgerID))
+	if err != nil || val == nil {
+		return nil, err
+	}
+	configBlock := &common.Block{}
+	if err = proto.Unmarshal(val, configBlock); err != nil {
+		return nil, err
+	}
+	return configBlock, nil
+}
+
+// setRebuildDBFlag records that the given database of a ledger is to be rebuilt from the block files.
+// The flag is removed once the rebuild completes so that an interrupted rebuild is resumed when the ledger is opened
+func (s *idStore) setRebuildDBFlag(ledgerID string, dbName string) error {
+	return s.db.Put(s.encodeRebuildDBKey(ledgerID, dbName), []byte{}, true)
+}
+
+func (s *idStore) unsetRebuildDBFlag(ledgerID string, dbName string) error {
+	return s.db.Delete(s.encodeRebuildDBKey(ledgerID, dbName), true)
+}
+
+func (s *idStore) isRebuildDBFlagSet(ledgerID string, dbName string) (bool, error) {
+	val, err := s.db.Get(s.encodeRebuildDBKey(ledgerID, dbName))
+	if err != nil {
+		return false, err
+	}
+	return val != nil, nil
+}
+
 func (s *idStore) close() {
 	s.db.Close()
 }
@@ -336,6 +395,17 @@ func (s *idStore) encodeLedgerKey(ledgerID string) []byte {
 	return append(ledgerKeyPrefix, []byte(ledgerID)...)
 }
 
+func (s *idStore) encodeConfigBlockKey(ledgerID string) []byte {
+	return append(configBlockKeyPrefix, []byte(ledgerID)...)
+}
+
+func (s *idStore) encodeRebuildDBKey(ledgerID string, dbName string) []byte {
+	key := append([]byte{}, rebuildDBKeyPrefix...)
+	key = append(key, []byte(dbName)...)
+	key = append(key, 0x00)
+	return append(key, []byte(ledgerID)...)
+}
+
 func (s *idStore) decodeLedgerID(key []byte) string {
 	return string(key[len(ledgerKeyPrefix):])
 }
@@ -26,6 +26,7 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateleveldb"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
@@ -75,6 +76,24 @@ func TestLedgerProvider(t *testing.T) {
 	testutil.AssertEquals(t, err, ErrNonExistingLedgerID)
 }
 
+func TestLedgerProviderWithUnknownStateDatabase(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	defer viper.Set("ledger.state.stateDatabase", "goleveldb")
+	// a state database that is not registered is an error
+	viper.Set("ledger.state.stateDatabase", "unknown")
+	_, err := NewProvider()
+	testutil.AssertError(t, err, "")
+
+	// the ledger uses goleveldb when no state database is set
+	viper.Set("ledger.state.stateDatabase", "")
+	provider, err := NewProvider()
+	testutil.AssertNoError(t, err, "")
+	defer provider.Close()
+	_, ok := provider.(*Provider).vdbProvider.(*stateleveldb.VersionedDBProvider)
+	testutil.AssertEquals(t, ok, true)
+}
+
 func TestMultipleLedgerBasicRW(t *testing.T) {
 	env := newTestEnv(t)
 	defer env.cleanup()
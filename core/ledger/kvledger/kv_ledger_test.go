@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger/blobstore"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	ledgertestutil "github.com/hyperledger/fabric/core/ledger/testutil"
 	"github.com/hyperledger/fabric/protos/common"
@@ -432,6 +433,62 @@ func TestKVLedgerDBRecovery(t *testing.T) {
 	simulator.Done()
 }
 
+func TestKVLedgerBlobStore(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	defer provider.Close()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, _ := provider.Create(gb)
+	defer ledger.Close()
+
+	// a blob staged during the simulation is added to the blob store when the block is committed
+	w, err := ledger.GetBlobStore().NewWriter()
+	assert.NoError(t, err)
+	w.Write([]byte("blob1"))
+	hash, err := w.Commit()
+	assert.NoError(t, err)
+	simulator, _ := ledger.NewTxSimulator()
+	simulator.SetState("ns1", "key1", blobstore.Reference(hash))
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	assert.NoError(t, ledger.Commit(bg.NextBlock([][]byte{simRes})))
+	b, err := ledger.GetBlobStore().Open(hash)
+	assert.NoError(t, err)
+	b.Close()
+
+	// the blob is removed once no key references it
+	simulator, _ = ledger.NewTxSimulator()
+	simulator.SetState("ns1", "key1", []byte("value1"))
+	simulator.Done()
+	simRes, _ = simulator.GetTxSimulationResults()
+	assert.NoError(t, ledger.Commit(bg.NextBlock([][]byte{simRes})))
+	_, err = ledger.GetBlobStore().Open(hash)
+	assert.IsType(t, &blobstore.ErrBlobNotFound{}, err)
+	recover, _, err := ledger.GetBlobStore().ShouldRecover(2)
+	assert.NoError(t, err)
+	assert.False(t, recover)
+}
+
+func TestCreateChaincodeIndexesWithoutIndexSupport(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	defer provider.Close()
+
+	_, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, _ := provider.Create(gb)
+	defer ledger.Close()
+
+	// goleveldb does not support indexes, so the index definitions are expected to be ignored
+	indexes := map[string]map[string][]byte{
+		"couchdb": {"indexOwner.json": []byte(`{"index":{"fields":["owner"]},"name":"indexOwner","type":"json"}`)},
+	}
+	testutil.AssertNoError(t, ledger.CreateChaincodeIndexes("ns1", indexes), "")
+	testutil.AssertNoError(t, ledger.CreateChaincodeIndexes("ns1", nil), "")
+}
+
 func TestLedgerWithCouchDbEnabledWithBinaryAndJSONData(t *testing.T) {
 
 	//call a helper method to load the core.yaml
@@ -0,0 +1,197 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvledger
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/protos/common"
+	putils "github.com/hyperledger/fabric/protos/utils"
+)
+
+// Prune implements method in interface `ledger.PeerLedger`.
+// The block storage removes the blocks at the granularity of block files and hence, a few blocks
+// older than the ones required by the policy may be retained. The last block of the chain is always retained.
+// If the last config block gets pruned, it is retained outside the block storage so that it can still be
+// retrieved via `GetBlockByNumber`
+func (l *kvLedger) Prune(policy commonledger.PrunePolicy) error {
+	l.commitLock.Lock()
+	defer l.commitLock.Unlock()
+	return l.prune(policy)
+}
+
+// pruneAsConfigured prunes the blocks as per the policy configured for the peer, if any.
+// A failure to prune does not affect the commit of the blocks and hence, is only logged
+func (l *kvLedger) pruneAsConfigured() {
+	policy, err := ledgerconfig.GetBlockPrunePolicy()
+	if err == nil && policy != nil {
+		err = l.prune(policy)
+	}
+	if err != nil {
+		logger.Warningf("Channel [%s]: Error while pruning blocks: %s", l.ledgerID, err)
+	}
+}
+
+func (l *kvLedger) prune(policy commonledger.PrunePolicy) error {
+	bcInfo, err := l.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return err
+	}
+	if bcInfo.Height == 0 {
+		return nil
+	}
+	lastBlock, err := l.blockStore.RetrieveBlockByNumber(bcInfo.Height - 1)
+	if err != nil {
+		return err
+	}
+	firstBlockToRetain, err := l.firstBlockToRetain(policy, lastBlock)
+	if err != nil {
+		return err
+	}
+	if firstBlockToRetain > lastBlock.Header.Number {
+		firstBlockToRetain = lastBlock.Header.Number
+	}
+	logger.Debugf("Channel [%s]: Pruning blocks preceding block [%d] as per policy [%#v]", l.ledgerID, firstBlockToRetain, policy)
+	if err = l.retainLastConfigBlock(lastBlock, firstBlockToRetain); err != nil {
+		return err
+	}
+	return l.blockStore.Prune(firstBlockToRetain)
+}
+
+// firstBlockToRetain returns the number of the oldest block that the given policy requires to retain
+func (l *kvLedger) firstBlockToRetain(policy commonledger.PrunePolicy, lastBlock *common.Block) (uint64, error) {
+	height := lastBlock.Header.Number + 1
+	switch p := policy.(type) {
+	case commonledger.KeepLastNBlocks:
+		if p.NumBlocks == 0 {
+			return 0, errors.New("Number of blocks to retain should be greater than zero")
+		}
+		if height <= p.NumBlocks {
+			return 0, nil
+		}
+		return height - p.NumBlocks, nil
+
+	case commonledger.KeepBlocksNewerThan:
+		return l.firstBlockNewerThan(p.Time, height)
+
+	case commonledger.KeepBlocksSinceLastConfig:
+		lastConfigBlockNum, ok := lastConfigBlockNumber(lastBlock)
+		if !ok {
+			return 0, fmt.Errorf("Last config index not found in block [%d]", lastBlock.Header.Number)
+		}
+		return lastConfigBlockNum, nil
+
+	default:
+		return 0, fmt.Errorf("Unsupported prune policy [%T]", policy)
+	}
+}
+
+// firstBlockNewerThan performs a binary search for the first block that carries a transaction timestamp
+// not older than the given time. The blocks that have already been pruned are considered older.
+// This assumes that the transaction timestamps are (roughly) increasing along the chain
+func (l *kvLedger) firstBlockNewerThan(t time.Time, height uint64) (uint64, error) {
+	var searchErr error
+	blockNum := sort.Search(int(height), func(i int) bool {
+		if searchErr != nil {
+			return true
+		}
+		block, err := l.blockStore.RetrieveBlockByNumber(uint64(i))
+		if err == blkstorage.ErrBlockPruned {
+			return false
+		}
+		if err != nil {
+			searchErr = err
+			return true
+		}
+		blockTime, err := blockTimestamp(block)
+		if err != nil {
+			searchErr = err
+			return true
+		}
+		return !blockTime.Before(t)
+	})
+	if searchErr != nil {
+		return 0, searchErr
+	}
+	return uint64(blockNum), nil
+}
+
+// retainLastConfigBlock saves the last config block outside the block storage,
+// if the block is going to be pruned and has not been saved already
+func (l *kvLedger) retainLastConfigBlock(lastBlock *common.Block, firstBlockToRetain uint64) error {
+	lastConfigBlockNum, ok := lastConfigBlockNumber(lastBlock)
+	if !ok || lastConfigBlockNum >= firstBlockToRetain {
+		return nil
+	}
+	if retained := l.getRetainedConfigBlock(); retained != nil && retained.Header.Number == lastConfigBlockNum {
+		return nil
+	}
+	configBlock, err := l.blockStore.RetrieveBlockByNumber(lastConfigBlockNum)
+	if err != nil {
+		return err
+	}
+	if err = l.idStore.setRetainedConfigBlock(l.ledgerID, configBlock); err != nil {
+		return err
+	}
+	l.setRetainedConfigBlock(configBlock)
+	return nil
+}
+
+// lastConfigBlockNumber returns the last config index carried in the metadata of the given block
+func lastConfigBlockNumber(block *common.Block) (uint64, bool) {
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(common.BlockMetadataIndex_LAST_CONFIG) ||
+		len(block.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG]) == 0 {
+		return 0, false
+	}
+	lastConfigIndex, err := putils.GetLastConfigIndexFromBlock(block)
+	if err != nil {
+		return 0, false
+	}
+	return lastConfigIndex, true
+}
+
+// blockTimestamp returns the timestamp of the first transaction in the block
+func blockTimestamp(block *common.Block) (time.Time, error) {
+	if block.Data == nil || len(block.Data.Data) == 0 {
+		return time.Time{}, fmt.Errorf("Block [%d] does not contain any transaction", block.Header.Number)
+	}
+	env, err := putils.GetEnvelopeFromBlock(block.Data.Data[0])
+	if err != nil {
+		return time.Time{}, err
+	}
+	payload, err := putils.GetPayload(env)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if payload.Header == nil {
+		return time.Time{}, fmt.Errorf("Header missing in the first transaction of block [%d]", block.Header.Number)
+	}
+	chdr, err := putils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if chdr.Timestamp == nil {
+		return time.Time{}, nil
+	}
+	return time.Unix(chdr.Timestamp.Seconds, int64(chdr.Timestamp.Nanos)), nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvledger
+
+import (
+	"testing"
+	"time"
+
+	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/protos/common"
+	putils "github.com/hyperledger/fabric/protos/utils"
+)
+
+func TestPrunePolicies(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	defer provider.Close()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	setLastConfigIndex(gb, 0)
+	ledger, _ := provider.Create(gb)
+	defer ledger.Close()
+	kvl := ledger.(*kvLedger)
+
+	var blocks []*common.Block
+	for i := 0; i < 5; i++ {
+		simulator, _ := ledger.NewTxSimulator()
+		simulator.SetState("ns1", "key1", []byte("value1"))
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		block := bg.NextBlock([][]byte{simRes})
+		setLastConfigIndex(block, 2)
+		testutil.AssertNoError(t, ledger.Commit(block), "")
+		blocks = append(blocks, block)
+	}
+	lastBlock := blocks[4]
+
+	firstBlock, err := kvl.firstBlockToRetain(commonledger.KeepLastNBlocks{NumBlocks: 2}, lastBlock)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, firstBlock, uint64(4))
+	firstBlock, err = kvl.firstBlockToRetain(commonledger.KeepLastNBlocks{NumBlocks: 10}, lastBlock)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, firstBlock, uint64(0))
+	_, err = kvl.firstBlockToRetain(commonledger.KeepLastNBlocks{}, lastBlock)
+	testutil.AssertError(t, err, "Expected an error for retaining zero blocks")
+
+	firstBlock, err = kvl.firstBlockToRetain(commonledger.KeepBlocksNewerThan{Time: time.Now().Add(-time.Hour)}, lastBlock)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, firstBlock, uint64(0))
+	firstBlock, err = kvl.firstBlockToRetain(commonledger.KeepBlocksNewerThan{Time: time.Now().Add(time.Hour)}, lastBlock)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, firstBlock, uint64(6))
+
+	firstBlock, err = kvl.firstBlockToRetain(commonledger.KeepBlocksSinceLastConfig{}, lastBlock)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, firstBlock, uint64(2))
+
+	testutil.AssertError(t, ledger.Prune("unknown-policy"), "Expected an error for an unsupported policy")
+	testutil.AssertNoError(t, ledger.Prune(commonledger.KeepBlocksSinceLastConfig{}), "")
+	testutil.AssertNil(t, kvl.getRetainedConfigBlock())
+
+	// the last config block is retained outside the block storage, if it precedes the blocks to retain
+	testutil.AssertNoError(t, ledger.Prune(commonledger.KeepLastNBlocks{NumBlocks: 1}), "")
+	testutil.AssertEquals(t, kvl.getRetainedConfigBlock(), blocks[1])
+	retainedConfigBlock, err := kvl.idStore.getRetainedConfigBlock("testLedger")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, retainedConfigBlock, blocks[1])
+
+	// the blocks remain in the block storage since all of them are in the current block file
+	for _, block := range blocks {
+		b, err := ledger.GetBlockByNumber(block.Header.Number)
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, b, block)
+	}
+}
+
+func setLastConfigIndex(block *common.Block, index uint64) {
+	block.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG] = putils.MarshalOrPanic(&common.Metadata{
+		Value: putils.MarshalOrPanic(&common.LastConfig{Index: index})})
+}
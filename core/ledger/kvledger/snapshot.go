@@ -0,0 +1,518 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvledger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+)
+
+const (
+	snapshotMetadataFileName    = "snapshot_metadata.json"
+	snapshotStateFileName       = "state.data"
+	snapshotHistoryFileName     = "history.data"
+	snapshotLastBlockFileName   = "lastblock.data"
+	snapshotConfigBlockFileName = "configblock.data"
+
+	// snapshotImportBatchSize is the number of entries that are written to the state db or history db in one batch
+	snapshotImportBatchSize = 1000
+)
+
+// snapshotMetadata captures the information about a snapshot. This is persisted
+// in the snapshot directory alongside the data files
+type snapshotMetadata struct {
+	LedgerID         string            `json:"ledgerID"`
+	Height           uint64            `json:"height"`
+	CurrentBlockHash []byte            `json:"currentBlockHash"`
+	StateSavepoint   *version.Height   `json:"stateSavepoint"`
+	HistorySavepoint *version.Height   `json:"historySavepoint,omitempty"`
+	FileHashes       map[string][]byte `json:"fileHashes"`
+}
+
+// ExportSnapshot implements method in interface `ledger.PeerLedger`
+func (l *kvLedger) ExportSnapshot(snapshotDir string) error {
+	l.commitLock.Lock()
+	defer l.commitLock.Unlock()
+
+	bcInfo, err := l.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return err
+	}
+	if bcInfo.Height == 0 {
+		return fmt.Errorf("Ledger [%s] is empty. Nothing to export", l.ledgerID)
+	}
+	empty, err := util.CreateDirIfMissing(snapshotDir)
+	if err != nil {
+		return err
+	}
+	if !empty {
+		return fmt.Errorf("Snapshot directory [%s] is not empty", snapshotDir)
+	}
+	logger.Infof("Channel [%s]: Exporting snapshot at height [%d] to [%s]", l.ledgerID, bcInfo.Height, snapshotDir)
+
+	metadata := &snapshotMetadata{
+		LedgerID:         l.ledgerID,
+		Height:           bcInfo.Height,
+		CurrentBlockHash: bcInfo.CurrentBlockHash,
+		FileHashes:       make(map[string][]byte),
+	}
+	if metadata.StateSavepoint, err = l.txtmgmt.GetLastSavepoint(); err != nil {
+		return err
+	}
+	if err = l.exportState(snapshotDir, metadata); err != nil {
+		return err
+	}
+	if ledgerconfig.IsHistoryDBEnabled() {
+		if metadata.HistorySavepoint, err = l.historyDB.GetLastSavepoint(); err != nil {
+			return err
+		}
+		if err = l.exportHistory(snapshotDir, metadata); err != nil {
+			return err
+		}
+	}
+
+	lastBlock, err := l.blockStore.RetrieveBlockByNumber(bcInfo.Height - 1)
+	if err != nil {
+		return err
+	}
+	if err = exportBlock(snapshotDir, snapshotLastBlockFileName, lastBlock, metadata); err != nil {
+		return err
+	}
+	configBlock, err := l.retrieveLastConfigBlock(lastBlock)
+	if err != nil {
+		return err
+	}
+	if configBlock != nil && configBlock.Header.Number != lastBlock.Header.Number {
+		if err = exportBlock(snapshotDir, snapshotConfigBlockFileName, configBlock, metadata); err != nil {
+			return err
+		}
+	}
+
+	metadataBytes, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(filepath.Join(snapshotDir, snapshotMetadataFileName), metadataBytes, 0644); err != nil {
+		return err
+	}
+	logger.Infof("Channel [%s]: Exported snapshot at height [%d]", l.ledgerID, bcInfo.Height)
+	return nil
+}
+
+func (l *kvLedger) exportState(snapshotDir string, metadata *snapshotMetadata) error {
+	itr, err := l.versionedDB.GetFullScanIterator()
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+	w, err := newSnapshotFileWriter(snapshotDir, snapshotStateFileName)
+	if err != nil {
+		return err
+	}
+	defer w.close()
+	for {
+		queryResult, err := itr.Next()
+		if err != nil {
+			return err
+		}
+		if queryResult == nil {
+			break
+		}
+		vkv := queryResult.(*statedb.VersionedKV)
+		// the private data is never shared outside the peer, only the hashes of the private data are exported
+		if statedb.IsPvtDataNs(vkv.Namespace) {
+			continue
+		}
+		// the metadata of the key, if any, is exported along with the version in the encoding used by the state db
+		if err = w.encodeBytes([]byte(vkv.Namespace), []byte(vkv.Key), vkv.Value,
+			statedb.EncodeValueAndMetadata(nil, vkv.Metadata, vkv.Version)); err != nil {
+			return err
+		}
+	}
+	if metadata.FileHashes[snapshotStateFileName], err = w.done(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (l *kvLedger) exportHistory(snapshotDir string, metadata *snapshotMetadata) error {
+	itr, err := l.historyDB.GetHistoryRecordsIterator(l.blockStore)
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+	w, err := newSnapshotFileWriter(snapshotDir, snapshotHistoryFileName)
+	if err != nil {
+		return err
+	}
+	defer w.close()
+	for {
+		queryResult, err := itr.Next()
+		if err != nil {
+			return err
+		}
+		if queryResult == nil {
+			break
+		}
+		record := queryResult.(*historydb.HistoryRecord)
+		keyModificationBytes, err := proto.Marshal(record.KeyModification)
+		if err != nil {
+			return err
+		}
+		if err = w.encodeBytes(record.CompositeKey, keyModificationBytes); err != nil {
+			return err
+		}
+	}
+	if metadata.FileHashes[snapshotHistoryFileName], err = w.done(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// retrieveLastConfigBlock returns the config block referred by the last config index in the metadata of the given block.
+// A nil block is returned if the metadata does not carry the last config index
+func (l *kvLedger) retrieveLastConfigBlock(block *common.Block) (*common.Block, error) {
+	lastConfigIndex, ok := lastConfigBlockNumber(block)
+	if !ok {
+		logger.Debugf("Channel [%s]: Last config index not found in block [%d]", l.ledgerID, block.Header.Number)
+		return nil, nil
+	}
+	return l.GetBlockByNumber(lastConfigIndex)
+}
+
+func exportBlock(snapshotDir string, fileName string, block *common.Block, metadata *snapshotMetadata) error {
+	blockBytes, err := proto.Marshal(block)
+	if err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(filepath.Join(snapshotDir, fileName), blockBytes, 0644); err != nil {
+		return err
+	}
+	metadata.FileHashes[fileName] = computeHash(blockBytes)
+	return nil
+}
+
+// CreateFromSnapshot implements the corresponding method from interface ledger.PeerLedgerProvider
+// Similar to `Create`, this function sets the under construction flag before populating the stores
+// from the snapshot and removes the flag once the ledger is created
+func (provider *Provider) CreateFromSnapshot(snapshotDir string) (ledger.PeerLedger, error) {
+	metadata, err := loadSnapshotMetadata(snapshotDir)
+	if err != nil {
+		return nil, err
+	}
+	ledgerID := metadata.LedgerID
+	exists, err := provider.idStore.ledgerIDExists(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrLedgerIDExists
+	}
+	lastBlock, err := loadSnapshotBlock(snapshotDir, snapshotLastBlockFileName, metadata)
+	if err != nil {
+		return nil, err
+	}
+	if lastBlock.Header.Number != metadata.Height-1 || !bytes.Equal(lastBlock.Header.Hash(), metadata.CurrentBlockHash) {
+		return nil, fmt.Errorf("Last block in snapshot does not match the snapshot height [%d]", metadata.Height)
+	}
+	var configBlock *common.Block
+	if _, ok := metadata.FileHashes[snapshotConfigBlockFileName]; ok {
+		if configBlock, err = loadSnapshotBlock(snapshotDir, snapshotConfigBlockFileName, metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Infof("Creating ledger [%s] from snapshot at height [%d]", ledgerID, metadata.Height)
+	if err = provider.idStore.setUnderConstructionFlag(ledgerID); err != nil {
+		return nil, err
+	}
+	l, err := provider.populateFromSnapshot(snapshotDir, metadata, lastBlock, configBlock)
+	if err != nil {
+		logger.Errorf("Error in creating ledger from snapshot. Unsetting under construction flag. Err: %s", err)
+		panicOnErr(provider.runCleanup(ledgerID), "Error while running cleanup for ledger id [%s]", ledgerID)
+		panicOnErr(provider.idStore.unsetUnderConstructionFlag(), "Error while unsetting under construction flag")
+		return nil, err
+	}
+	panicOnErr(provider.idStore.createLedgerID(ledgerID, lastBlock), "Error while marking ledger as created")
+	logger.Infof("Created ledger [%s] from snapshot at height [%d]", ledgerID, metadata.Height)
+	return l, nil
+}
+
+func (provider *Provider) populateFromSnapshot(snapshotDir string, metadata *snapshotMetadata,
+	lastBlock *common.Block, configBlock *common.Block) (ledger.PeerLedger, error) {
+	ledgerID := metadata.LedgerID
+	vDB, err := provider.vdbProvider.GetDBHandle(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	if err = importState(snapshotDir, metadata, vDB); err != nil {
+		return nil, err
+	}
+	historyDB, err := provider.historydbProvider.GetDBHandle(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := metadata.FileHashes[snapshotHistoryFileName]; ok {
+		if err = importHistory(snapshotDir, metadata, historyDB); err != nil {
+			return nil, err
+		}
+	}
+	// the snapshot does not contain the blobs referenced by the state, which remain unavailable on this peer
+	blobStore, err := provider.blobStoreProvider.OpenStore(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	if err = blobStore.Bootstrap(metadata.StateSavepoint); err != nil {
+		return nil, err
+	}
+	if configBlock != nil {
+		if err = provider.idStore.setRetainedConfigBlock(ledgerID, configBlock); err != nil {
+			return nil, err
+		}
+	}
+	// bootstrap the block store in the end so that the state db and history db are found in sync
+	// with the block store when the ledger is opened
+	blockStore, err := provider.blockStoreProvider.BootstrapBlockStore(ledgerID, lastBlock)
+	if err != nil {
+		return nil, err
+	}
+	return newKVLedger(ledgerID, blockStore, vDB, historyDB, blobStore, provider.idStore, provider.stateListeners)
+}
+
+func importState(snapshotDir string, metadata *snapshotMetadata, vDB statedb.VersionedDB) error {
+	if metadata.StateSavepoint == nil {
+		return fmt.Errorf("State savepoint missing in snapshot metadata")
+	}
+	r, err := newSnapshotFileReader(snapshotDir, snapshotStateFileName, metadata)
+	if err != nil {
+		return err
+	}
+	defer r.close()
+	batch := statedb.NewUpdateBatch()
+	batchSize := 0
+	for {
+		fields, err := r.decodeBytes(4)
+		if err != nil {
+			return err
+		}
+		if fields == nil {
+			break
+		}
+		_, keyMetadata, ver := statedb.DecodeValueAndMetadata(fields[3])
+		batch.PutValAndMetadata(string(fields[0]), string(fields[1]), fields[2], keyMetadata, ver)
+		batchSize++
+		if batchSize == snapshotImportBatchSize {
+			if err = vDB.ApplyUpdates(batch, metadata.StateSavepoint); err != nil {
+				return err
+			}
+			batch = statedb.NewUpdateBatch()
+			batchSize = 0
+		}
+	}
+	return vDB.ApplyUpdates(batch, metadata.StateSavepoint)
+}
+
+func importHistory(snapshotDir string, metadata *snapshotMetadata, historyDB historydb.HistoryDB) error {
+	if metadata.HistorySavepoint == nil {
+		return fmt.Errorf("History savepoint missing in snapshot metadata")
+	}
+	r, err := newSnapshotFileReader(snapshotDir, snapshotHistoryFileName, metadata)
+	if err != nil {
+		return err
+	}
+	defer r.close()
+	records := []*historydb.HistoryRecord{}
+	for {
+		fields, err := r.decodeBytes(2)
+		if err != nil {
+			return err
+		}
+		if fields == nil {
+			break
+		}
+		keyModification := &queryresult.KeyModification{}
+		if err = proto.Unmarshal(fields[1], keyModification); err != nil {
+			return err
+		}
+		records = append(records, &historydb.HistoryRecord{CompositeKey: fields[0], KeyModification: keyModification})
+		if len(records) == snapshotImportBatchSize {
+			if err = historyDB.ImportHistoryRecords(records, metadata.HistorySavepoint); err != nil {
+				return err
+			}
+			records = []*historydb.HistoryRecord{}
+		}
+	}
+	return historyDB.ImportHistoryRecords(records, metadata.HistorySavepoint)
+}
+
+func loadSnapshotMetadata(snapshotDir string) (*snapshotMetadata, error) {
+	metadataBytes, err := ioutil.ReadFile(filepath.Join(snapshotDir, snapshotMetadataFileName))
+	if err != nil {
+		return nil, err
+	}
+	metadata := &snapshotMetadata{}
+	if err = json.Unmarshal(metadataBytes, metadata); err != nil {
+		return nil, fmt.Errorf("Error while unmarshalling snapshot metadata: %s", err)
+	}
+	if metadata.LedgerID == "" || metadata.Height == 0 {
+		return nil, fmt.Errorf("Invalid snapshot metadata: ledgerID=[%s], height=[%d]", metadata.LedgerID, metadata.Height)
+	}
+	return metadata, nil
+}
+
+func loadSnapshotBlock(snapshotDir string, fileName string, metadata *snapshotMetadata) (*common.Block, error) {
+	blockBytes, err := ioutil.ReadFile(filepath.Join(snapshotDir, fileName))
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(computeHash(blockBytes), metadata.FileHashes[fileName]) {
+		return nil, fmt.Errorf("Hash mismatch for snapshot file [%s]", fileName)
+	}
+	block := &common.Block{}
+	if err = proto.Unmarshal(blockBytes, block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+func computeHash(b []byte) []byte {
+	h := sha256.Sum256(b)
+	return h[:]
+}
+
+// snapshotFileWriter writes a sequence of length-prefixed byte arrays to a snapshot data file
+// and computes the hash of the file contents on the fly
+type snapshotFileWriter struct {
+	file   *os.File
+	buf    *bufio.Writer
+	hasher hash.Hash
+}
+
+func newSnapshotFileWriter(snapshotDir string, fileName string) (*snapshotFileWriter, error) {
+	file, err := os.Create(filepath.Join(snapshotDir, fileName))
+	if err != nil {
+		return nil, err
+	}
+	hasher := sha256.New()
+	return &snapshotFileWriter{file, bufio.NewWriter(io.MultiWriter(file, hasher)), hasher}, nil
+}
+
+func (w *snapshotFileWriter) encodeBytes(fields ...[]byte) error {
+	lenBytes := make([]byte, binary.MaxVarintLen64)
+	for _, field := range fields {
+		n := binary.PutUvarint(lenBytes, uint64(len(field)))
+		if _, err := w.buf.Write(lenBytes[:n]); err != nil {
+			return err
+		}
+		if _, err := w.buf.Write(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// done flushes the buffered data to the file and returns the hash of the file contents
+func (w *snapshotFileWriter) done() ([]byte, error) {
+	if err := w.buf.Flush(); err != nil {
+		return nil, err
+	}
+	if err := w.file.Sync(); err != nil {
+		return nil, err
+	}
+	return w.hasher.Sum(nil), nil
+}
+
+func (w *snapshotFileWriter) close() {
+	w.file.Close()
+}
+
+// snapshotFileReader reads the entries written by snapshotFileWriter
+type snapshotFileReader struct {
+	fileName string
+	file     *os.File
+	buf      *bufio.Reader
+}
+
+// newSnapshotFileReader verifies the hash of the file contents against the hash recorded
+// in the snapshot metadata before returning a reader positioned at the beginning of the file
+func newSnapshotFileReader(snapshotDir string, fileName string, metadata *snapshotMetadata) (*snapshotFileReader, error) {
+	expectedHash, ok := metadata.FileHashes[fileName]
+	if !ok {
+		return nil, fmt.Errorf("Snapshot file [%s] not listed in snapshot metadata", fileName)
+	}
+	file, err := os.Open(filepath.Join(snapshotDir, fileName))
+	if err != nil {
+		return nil, err
+	}
+	hasher := sha256.New()
+	if _, err = io.Copy(hasher, file); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if !bytes.Equal(hasher.Sum(nil), expectedHash) {
+		file.Close()
+		return nil, fmt.Errorf("Hash mismatch for snapshot file [%s]", fileName)
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &snapshotFileReader{fileName, file, bufio.NewReader(file)}, nil
+}
+
+// decodeBytes reads the next entry that consists of the given number of fields.
+// A nil slice is returned when the end of the file is reached
+func (r *snapshotFileReader) decodeBytes(numFields int) ([][]byte, error) {
+	fields := make([][]byte, numFields)
+	for i := 0; i < numFields; i++ {
+		length, err := binary.ReadUvarint(r.buf)
+		if err == io.EOF && i == 0 {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Error while reading snapshot file [%s]: %s", r.fileName, err)
+		}
+		fields[i] = make([]byte, length)
+		if _, err = io.ReadFull(r.buf, fields[i]); err != nil {
+			return nil, fmt.Errorf("Error while reading snapshot file [%s]: %s", r.fileName, err)
+		}
+	}
+	return fields, nil
+}
+
+func (r *snapshotFileReader) close() {
+	r.file.Close()
+}
@@ -0,0 +1,168 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvledger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+	"github.com/spf13/viper"
+)
+
+func TestLedgerSnapshot(t *testing.T) {
+	ledgerid := "TestLedger"
+	originalPath := "/tmp/fabric/ledgertests/kvledger1"
+	newPath := "/tmp/fabric/ledgertests/kvledger2"
+	snapshotDir := "/tmp/fabric/ledgertests/snapshot"
+	viper.Set("ledger.history.enableHistoryDatabase", true)
+	os.RemoveAll(snapshotDir)
+	defer os.RemoveAll(snapshotDir)
+
+	// create and populate a ledger in the original environment and export a snapshot
+	env := createTestEnv(t, originalPath)
+	provider, _ := NewProvider()
+	bg, gb := testutil.NewBlockGenerator(t, ledgerid, false)
+	ledger, _ := provider.Create(gb)
+
+	simulator, _ := ledger.NewTxSimulator()
+	simulator.SetState("ns1", "key1", []byte("value1"))
+	simulator.SetState("ns1", "key2", []byte("value2"))
+	simulator.SetState("ns2", "key1", []byte("value3"))
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	block1 := bg.NextBlock([][]byte{simRes})
+	testutil.AssertNoError(t, ledger.Commit(block1), "")
+
+	simulator, _ = ledger.NewTxSimulator()
+	simulator.SetState("ns1", "key1", []byte("value4"))
+	simulator.DeleteState("ns1", "key2")
+	simulator.Done()
+	simRes, _ = simulator.GetTxSimulationResults()
+	block2 := bg.NextBlock([][]byte{simRes})
+	testutil.AssertNoError(t, ledger.Commit(block2), "")
+
+	testutil.AssertNoError(t, ledger.ExportSnapshot(snapshotDir), "")
+	testutil.AssertError(t, ledger.ExportSnapshot(snapshotDir), "Expected an error while exporting to a non-empty directory")
+	ledger.Close()
+	provider.Close()
+	env.cleanup()
+
+	// create the ledger from the snapshot in a new environment
+	env = createTestEnv(t, newPath)
+	defer env.cleanup()
+	provider, _ = NewProvider()
+	ledger, err := provider.CreateFromSnapshot(snapshotDir)
+	testutil.AssertNoError(t, err, "")
+	_, err = provider.CreateFromSnapshot(snapshotDir)
+	testutil.AssertEquals(t, err, ErrLedgerIDExists)
+
+	bcInfo, _ := ledger.GetBlockchainInfo()
+	testutil.AssertEquals(t, bcInfo, &common.BlockchainInfo{
+		Height: 3, CurrentBlockHash: block2.Header.Hash(), PreviousBlockHash: block1.Header.Hash()})
+	b2, _ := ledger.GetBlockByNumber(2)
+	testutil.AssertEquals(t, b2, block2)
+	_, err = ledger.GetBlockByNumber(1)
+	testutil.AssertEquals(t, err, blkstorage.ErrNotFoundInIndex)
+
+	qe, _ := ledger.NewQueryExecutor()
+	val, _ := qe.GetState("ns1", "key1")
+	testutil.AssertEquals(t, val, []byte("value4"))
+	val, _ = qe.GetState("ns1", "key2")
+	testutil.AssertNil(t, val)
+	val, _ = qe.GetState("ns2", "key1")
+	testutil.AssertEquals(t, val, []byte("value3"))
+	qe.Done()
+
+	hqe, _ := ledger.NewHistoryQueryExecutor()
+	itr, _ := hqe.GetHistoryForKey("ns1", "key2")
+	var kmods []*queryresult.KeyModification
+	for {
+		kmod, _ := itr.Next()
+		if kmod == nil {
+			break
+		}
+		kmods = append(kmods, kmod.(*queryresult.KeyModification))
+	}
+	itr.Close()
+	testutil.AssertEquals(t, len(kmods), 2)
+	testutil.AssertEquals(t, kmods[0].Value, []byte("value2"))
+	testutil.AssertEquals(t, kmods[1].IsDelete, true)
+
+	// the ledger created from the snapshot should resume committing blocks
+	simulator, _ = ledger.NewTxSimulator()
+	simulator.SetState("ns1", "key3", []byte("value5"))
+	simulator.Done()
+	simRes, _ = simulator.GetTxSimulationResults()
+	block3 := bg.NextBlock([][]byte{simRes})
+	testutil.AssertNoError(t, ledger.Commit(block3), "")
+	ledger.Close()
+	provider.Close()
+
+	// the ledger should be listed and opened like any other ledger
+	provider, _ = NewProvider()
+	defer provider.Close()
+	ledgerIDs, _ := provider.List()
+	testutil.AssertEquals(t, ledgerIDs, []string{ledgerid})
+	ledger, _ = provider.Open(ledgerid)
+	defer ledger.Close()
+	bcInfo, _ = ledger.GetBlockchainInfo()
+	testutil.AssertEquals(t, bcInfo.Height, uint64(4))
+	qe, _ = ledger.NewQueryExecutor()
+	val, _ = qe.GetState("ns1", "key3")
+	testutil.AssertEquals(t, val, []byte("value5"))
+	qe.Done()
+}
+
+func TestLedgerSnapshotTampered(t *testing.T) {
+	snapshotDir := "/tmp/fabric/ledgertests/snapshot"
+	os.RemoveAll(snapshotDir)
+	defer os.RemoveAll(snapshotDir)
+
+	env := createTestEnv(t, "/tmp/fabric/ledgertests/kvledger1")
+	provider, _ := NewProvider()
+	bg, gb := testutil.NewBlockGenerator(t, "TestLedger", false)
+	ledger, _ := provider.Create(gb)
+	simulator, _ := ledger.NewTxSimulator()
+	simulator.SetState("ns1", "key1", []byte("value1"))
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	ledger.Commit(bg.NextBlock([][]byte{simRes}))
+	testutil.AssertNoError(t, ledger.ExportSnapshot(snapshotDir), "")
+	ledger.Close()
+	provider.Close()
+	env.cleanup()
+
+	stateFile := filepath.Join(snapshotDir, snapshotStateFileName)
+	stateBytes, _ := ioutil.ReadFile(stateFile)
+	stateBytes[len(stateBytes)-1]++
+	testutil.AssertNoError(t, ioutil.WriteFile(stateFile, stateBytes, 0644), "")
+
+	env = createTestEnv(t, "/tmp/fabric/ledgertests/kvledger2")
+	defer env.cleanup()
+	provider, _ = NewProvider()
+	defer provider.Close()
+	_, err := provider.CreateFromSnapshot(snapshotDir)
+	testutil.AssertError(t, err, "Expected an error while creating ledger from a tampered snapshot")
+	ledgerIDs, _ := provider.List()
+	testutil.AssertEquals(t, len(ledgerIDs), 0)
+}
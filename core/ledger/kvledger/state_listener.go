@@ -0,0 +1,89 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvledger
+
+import (
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	putils "github.com/hyperledger/fabric/protos/utils"
+)
+
+// notifyStateListeners passes the writes of the valid transactions of the committed block
+// to the listeners registered for their namespace. A failing listener does not fail the
+// commit, as the state changes of the block are already durable at this point
+func (l *kvLedger) notifyStateListeners(block *common.Block) {
+	if len(l.stateListeners) == 0 {
+		return
+	}
+	writes, err := getNamespaceWrites(block, l.stateListeners)
+	if err != nil {
+		logger.Errorf("Channel [%s]: Error while collecting the writes of block [%d] for the state listeners: %s",
+			l.ledgerID, block.Header.Number, err)
+		return
+	}
+	for ns, nsWrites := range writes {
+		if err := l.stateListeners[ns].HandleStateUpdates(l.ledgerID, l, nsWrites); err != nil {
+			logger.Errorf("Channel [%s]: State listener for namespace [%s] failed to handle the updates of block [%d]: %s",
+				l.ledgerID, ns, block.Header.Number, err)
+		}
+	}
+}
+
+// getNamespaceWrites returns the writes of the valid endorser transactions of the block,
+// in block order, to the namespaces that have a listener
+func getNamespaceWrites(block *common.Block, stateListeners map[string]ledger.StateListener) (map[string][]*kvrwset.KVWrite, error) {
+	writes := make(map[string][]*kvrwset.KVWrite)
+	txsFilter := util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	for tranNo, envBytes := range block.Data.Data {
+		if len(txsFilter) != 0 && txsFilter.IsInvalid(tranNo) {
+			continue
+		}
+		env, err := putils.GetEnvelopeFromBlock(envBytes)
+		if err != nil {
+			return nil, err
+		}
+		payload, err := putils.GetPayload(env)
+		if err != nil {
+			return nil, err
+		}
+		chdr, err := putils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			return nil, err
+		}
+		if common.HeaderType(chdr.Type) != common.HeaderType_ENDORSER_TRANSACTION {
+			continue
+		}
+		respPayload, err := putils.GetActionFromEnvelope(envBytes)
+		if err != nil {
+			return nil, err
+		}
+		txRWSet := &rwsetutil.TxRwSet{}
+		if err = txRWSet.FromProtoBytes(respPayload.Results); err != nil {
+			return nil, err
+		}
+		for _, nsRWSet := range txRWSet.NsRwSets {
+			if _, ok := stateListeners[nsRWSet.NameSpace]; !ok {
+				continue
+			}
+			writes[nsRWSet.NameSpace] = append(writes[nsRWSet.NameSpace], nsRWSet.KvRwSet.Writes...)
+		}
+	}
+	return writes, nil
+}
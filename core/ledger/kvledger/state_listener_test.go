@@ -0,0 +1,88 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvledger
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+)
+
+type mockStateListener struct {
+	ledgerID string
+	writes   []*kvrwset.KVWrite
+	values   [][]byte
+}
+
+func (m *mockStateListener) HandleStateUpdates(ledgerID string, lgr ledger.PeerLedger, writes []*kvrwset.KVWrite) error {
+	m.ledgerID = ledgerID
+	m.writes = append(m.writes, writes...)
+	// the state changes of the block are expected to be committed when the listener is invoked
+	qe, err := lgr.NewQueryExecutor()
+	if err != nil {
+		return err
+	}
+	defer qe.Done()
+	for _, write := range writes {
+		value, err := qe.GetState("ns1", write.Key)
+		if err != nil {
+			return err
+		}
+		m.values = append(m.values, value)
+	}
+	return nil
+}
+
+func TestStateListener(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	listener := &mockStateListener{}
+	provider, _ := NewProviderWithStateListeners(map[string]ledger.StateListener{"ns1": listener})
+	defer provider.Close()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	lgr, _ := provider.Create(gb)
+	defer lgr.Close()
+	testutil.AssertEquals(t, len(listener.writes), 0)
+
+	simulator, _ := lgr.NewTxSimulator()
+	simulator.SetState("ns1", "key1", []byte("value1"))
+	simulator.SetState("ns2", "key2", []byte("value2"))
+	simulator.Done()
+	simRes1, _ := simulator.GetTxSimulationResults()
+	simulator, _ = lgr.NewTxSimulator()
+	simulator.SetState("ns1", "key3", []byte("value3"))
+	simulator.Done()
+	simRes2, _ := simulator.GetTxSimulationResults()
+	testutil.AssertNoError(t, lgr.Commit(bg.NextBlock([][]byte{simRes1, simRes2})), "")
+
+	testutil.AssertEquals(t, listener.ledgerID, "testLedger")
+	testutil.AssertEquals(t, len(listener.writes), 2)
+	testutil.AssertEquals(t, listener.writes[0].Key, "key1")
+	testutil.AssertEquals(t, listener.writes[1].Key, "key3")
+	testutil.AssertEquals(t, listener.values, [][]byte{[]byte("value1"), []byte("value3")})
+
+	// a block without writes to the namespace does not invoke the listener
+	simulator, _ = lgr.NewTxSimulator()
+	simulator.SetState("ns2", "key4", []byte("value4"))
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	testutil.AssertNoError(t, lgr.Commit(bg.NextBlock([][]byte{simRes})), "")
+	testutil.AssertEquals(t, len(listener.writes), 2)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package rwsetutil
 
 import (
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/util"
@@ -28,14 +29,29 @@ var logger = flogging.MustGetLogger("rwset")
 type nsRWs struct {
 	readMap          map[string]*kvrwset.KVRead //for mvcc validation
 	writeMap         map[string]*kvrwset.KVWrite
+	metadataWriteMap map[string]*kvrwset.KVMetadataWrite
 	rangeQueriesMap  map[rangeQueryKey]*kvrwset.RangeQueryInfo //for phantom read validation
 	rangeQueriesKeys []rangeQueryKey
+	collRWsMap       map[string]*collRWs
 }
 
 func newNsRWs() *nsRWs {
 	return &nsRWs{make(map[string]*kvrwset.KVRead),
 		make(map[string]*kvrwset.KVWrite),
-		make(map[rangeQueryKey]*kvrwset.RangeQueryInfo), nil}
+		make(map[string]*kvrwset.KVMetadataWrite),
+		make(map[rangeQueryKey]*kvrwset.RangeQueryInfo), nil,
+		make(map[string]*collRWs)}
+}
+
+// collRWs maintains the reads and writes on the private data of a collection. The reads are maintained only
+// in the hashed form whereas the writes are maintained in the raw form (from which the hashed form is derived)
+type collRWs struct {
+	readMap  map[string]*kvrwset.KVReadHash //for mvcc validation
+	writeMap map[string]*kvrwset.KVWrite
+}
+
+func newCollRWs() *collRWs {
+	return &collRWs{make(map[string]*kvrwset.KVReadHash), make(map[string]*kvrwset.KVWrite)}
 }
 
 type rangeQueryKey struct {
@@ -66,6 +82,13 @@ func (rws *RWSetBuilder) AddToWriteSet(ns string, key string, value []byte) {
 	nsRWs.writeMap[key] = newKVWrite(key, value)
 }
 
+// AddToMetadataWriteSet adds the metadata of a key to the metadata write-set. The metadata replaces the existing
+// metadata of the key on commit and an empty metadata removes the existing metadata
+func (rws *RWSetBuilder) AddToMetadataWriteSet(ns string, key string, metadata map[string][]byte) {
+	nsRWs := rws.getOrCreateNsRW(ns)
+	nsRWs.metadataWriteMap[key] = newKVMetadataWrite(key, metadata)
+}
+
 // AddToRangeQuerySet adds a range query info for performing phantom read validation
 func (rws *RWSetBuilder) AddToRangeQuerySet(ns string, rqi *kvrwset.RangeQueryInfo) {
 	nsRWs := rws.getOrCreateNsRW(ns)
@@ -77,7 +100,59 @@ func (rws *RWSetBuilder) AddToRangeQuerySet(ns string, rqi *kvrwset.RangeQueryIn
 	}
 }
 
-// GetTxReadWriteSet returns the read-write set in the form that can be serialized
+// AddToHashedReadSet adds a key of a private data collection and corresponding version to the hashed read-set
+func (rws *RWSetBuilder) AddToHashedReadSet(ns string, coll string, key string, version *version.Height) {
+	collRWs := rws.getOrCreateCollRW(ns, coll)
+	collRWs.readMap[key] = newKVReadHash(key, version)
+}
+
+// AddToPvtAndHashedWriteSet adds a key and value of a private data collection to the private write-set.
+// The hashes of the key and value are added to the hashed write-set
+func (rws *RWSetBuilder) AddToPvtAndHashedWriteSet(ns string, coll string, key string, value []byte) {
+	collRWs := rws.getOrCreateCollRW(ns, coll)
+	collRWs.writeMap[key] = newKVWrite(key, value)
+}
+
+// GetTxSimulationResults returns the read-write set that goes into the transaction and the private read-write set
+// that is kept off the transaction. The former includes the hashed read-write sets of the private data collections.
+// The private read-write set is nil if the transaction did not write any private data
+func (rws *RWSetBuilder) GetTxSimulationResults() (*TxRwSet, *TxPvtRwSet, error) {
+	txRWSet := rws.GetTxReadWriteSet()
+	txPvtRWSet := &TxPvtRwSet{}
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		nsRWs := rws.rwMap[nsRWSet.NameSpace]
+		var nsPvtRWSet *NsPvtRwSet
+		for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+			writeMap := nsRWs.collRWsMap[collHashedRWSet.CollectionName].writeMap
+			if len(writeMap) == 0 {
+				continue
+			}
+			var writes []*kvrwset.KVWrite
+			for _, key := range util.GetSortedKeys(writeMap) {
+				writes = append(writes, writeMap[key])
+			}
+			collPvtRWSet := &CollPvtRwSet{CollectionName: collHashedRWSet.CollectionName, KvRwSet: &kvrwset.KVRWSet{Writes: writes}}
+			pvtRWSetBytes, err := proto.Marshal(collPvtRWSet.KvRwSet)
+			if err != nil {
+				return nil, nil, err
+			}
+			collHashedRWSet.PvtRwSetHash = ComputeHash(pvtRWSetBytes)
+			if nsPvtRWSet == nil {
+				nsPvtRWSet = &NsPvtRwSet{NameSpace: nsRWSet.NameSpace}
+				txPvtRWSet.NsPvtRwSets = append(txPvtRWSet.NsPvtRwSets, nsPvtRWSet)
+			}
+			nsPvtRWSet.CollPvtRwSets = append(nsPvtRWSet.CollPvtRwSets, collPvtRWSet)
+		}
+	}
+	if len(txPvtRWSet.NsPvtRwSets) == 0 {
+		return txRWSet, nil, nil
+	}
+	return txRWSet, txPvtRWSet, nil
+}
+
+// GetTxReadWriteSet returns the read-write set in the form that can be serialized.
+// The hashed read-write sets of the private data collections do not carry the hash of the private read-write set,
+// see function `GetTxSimulationResults` for including the same
 func (rws *RWSetBuilder) GetTxReadWriteSet() *TxRwSet {
 	txRWSet := &TxRwSet{}
 	sortedNamespaces := util.GetSortedKeys(rws.rwMap)
@@ -99,19 +174,52 @@ func (rws *RWSetBuilder) GetTxReadWriteSet() *TxRwSet {
 			writes = append(writes, nsReadWriteMap.writeMap[key])
 		}
 
+		//add metadata write set
+		var metadataWrites []*kvrwset.KVMetadataWrite
+		for _, key := range util.GetSortedKeys(nsReadWriteMap.metadataWriteMap) {
+			metadataWrites = append(metadataWrites, nsReadWriteMap.metadataWriteMap[key])
+		}
+
 		//add range query info
 		var rangeQueriesInfo []*kvrwset.RangeQueryInfo
 		rangeQueriesMap := nsReadWriteMap.rangeQueriesMap
 		for _, key := range nsReadWriteMap.rangeQueriesKeys {
 			rangeQueriesInfo = append(rangeQueriesInfo, rangeQueriesMap[key])
 		}
-		kvRWs := &kvrwset.KVRWSet{Reads: reads, Writes: writes, RangeQueriesInfo: rangeQueriesInfo}
-		nsRWs := &NsRwSet{ns, kvRWs}
+		kvRWs := &kvrwset.KVRWSet{Reads: reads, Writes: writes, RangeQueriesInfo: rangeQueriesInfo, MetadataWrites: metadataWrites}
+		nsRWs := &NsRwSet{ns, kvRWs, getCollHashedRwSets(nsReadWriteMap.collRWsMap)}
 		txRWSet.NsRwSets = append(txRWSet.NsRwSets, nsRWs)
 	}
 	return txRWSet
 }
 
+func getCollHashedRwSets(collRWsMap map[string]*collRWs) []*CollHashedRwSet {
+	var collHashedRwSets []*CollHashedRwSet
+	for _, coll := range util.GetSortedKeys(collRWsMap) {
+		collReadWriteMap := collRWsMap[coll]
+		hashedRWSet := &kvrwset.HashedRWSet{}
+		for _, key := range util.GetSortedKeys(collReadWriteMap.readMap) {
+			hashedRWSet.HashedReads = append(hashedRWSet.HashedReads, collReadWriteMap.readMap[key])
+		}
+		for _, key := range util.GetSortedKeys(collReadWriteMap.writeMap) {
+			kvWrite := collReadWriteMap.writeMap[key]
+			hashedRWSet.HashedWrites = append(hashedRWSet.HashedWrites, newKVWriteHash(kvWrite.Key, kvWrite.Value))
+		}
+		collHashedRwSets = append(collHashedRwSets, &CollHashedRwSet{CollectionName: coll, HashedRwSet: hashedRWSet})
+	}
+	return collHashedRwSets
+}
+
+func (rws *RWSetBuilder) getOrCreateCollRW(ns string, coll string) *collRWs {
+	nsRWs := rws.getOrCreateNsRW(ns)
+	collRWs, ok := nsRWs.collRWsMap[coll]
+	if !ok {
+		collRWs = newCollRWs()
+		nsRWs.collRWsMap[coll] = collRWs
+	}
+	return collRWs
+}
+
 func (rws *RWSetBuilder) getOrCreateNsRW(ns string) *nsRWs {
 	var nsRWs *nsRWs
 	var ok bool
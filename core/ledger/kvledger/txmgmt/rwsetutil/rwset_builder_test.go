@@ -19,6 +19,7 @@ package rwsetutil
 import (
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
@@ -53,12 +54,12 @@ func TestRWSetHolder(t *testing.T) {
 
 	txRWSet := rwSetBuilder.GetTxReadWriteSet()
 
-	ns1RWSet := &NsRwSet{"ns1", &kvrwset.KVRWSet{
+	ns1RWSet := &NsRwSet{NameSpace: "ns1", KvRwSet: &kvrwset.KVRWSet{
 		Reads:            []*kvrwset.KVRead{NewKVRead("key1", version.NewHeight(1, 1)), NewKVRead("key2", version.NewHeight(1, 2))},
 		RangeQueriesInfo: []*kvrwset.RangeQueryInfo{rqi1, rqi3},
 		Writes:           []*kvrwset.KVWrite{newKVWrite("key2", []byte("value2"))}}}
 
-	ns2RWSet := &NsRwSet{"ns2", &kvrwset.KVRWSet{
+	ns2RWSet := &NsRwSet{NameSpace: "ns2", KvRwSet: &kvrwset.KVRWSet{
 		Reads:            []*kvrwset.KVRead{NewKVRead("key2", version.NewHeight(1, 2))},
 		RangeQueriesInfo: nil,
 		Writes:           []*kvrwset.KVWrite{newKVWrite("key3", []byte("value3"))}}}
@@ -67,3 +68,80 @@ func TestRWSetHolder(t *testing.T) {
 	t.Logf("Actual=%s\n Expected=%s", txRWSet, expectedTxRWSet)
 	testutil.AssertEquals(t, txRWSet, expectedTxRWSet)
 }
+
+func TestRWSetBuilderWithMetadata(t *testing.T) {
+	rwSetBuilder := NewRWSetBuilder()
+	rwSetBuilder.AddToWriteSet("ns1", "key1", []byte("value1"))
+	rwSetBuilder.AddToMetadataWriteSet("ns1", "key2", map[string][]byte{"entry2": []byte("m2"), "entry1": []byte("m1")})
+	rwSetBuilder.AddToMetadataWriteSet("ns1", "key1", nil)
+
+	txRWSet := rwSetBuilder.GetTxReadWriteSet()
+	testutil.AssertEquals(t, txRWSet.NsRwSets[0].KvRwSet.MetadataWrites, []*kvrwset.KVMetadataWrite{
+		{Key: "key1"},
+		{Key: "key2", Entries: []*kvrwset.KVMetadataEntry{{Name: "entry1", Value: []byte("m1")}, {Name: "entry2", Value: []byte("m2")}}},
+	})
+
+	metadata := map[string][]byte{"entry2": []byte("m2"), "entry1": []byte("m1")}
+	metadataBytes, err := SerializeMetadata(metadata)
+	testutil.AssertNoError(t, err, "")
+	deserializedMetadata, err := DeserializeMetadata(metadataBytes)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, deserializedMetadata, metadata)
+
+	metadataBytes, _ = SerializeMetadata(nil)
+	testutil.AssertNil(t, metadataBytes)
+	deserializedMetadata, _ = DeserializeMetadata(nil)
+	testutil.AssertNil(t, deserializedMetadata)
+}
+
+func TestRWSetBuilderWithCollections(t *testing.T) {
+	rwSetBuilder := NewRWSetBuilder()
+	rwSetBuilder.AddToWriteSet("ns1", "key1", []byte("value1"))
+	rwSetBuilder.AddToHashedReadSet("ns1", "coll1", "key2", version.NewHeight(1, 2))
+	rwSetBuilder.AddToPvtAndHashedWriteSet("ns1", "coll1", "key3", []byte("value3"))
+	rwSetBuilder.AddToPvtAndHashedWriteSet("ns1", "coll1", "key4", nil)
+	rwSetBuilder.AddToHashedReadSet("ns1", "coll2", "key5", nil)
+
+	txRWSet, txPvtRWSet, err := rwSetBuilder.GetTxSimulationResults()
+	testutil.AssertNoError(t, err, "")
+
+	expectedPvtKVRWSet := &kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{newKVWrite("key3", []byte("value3")), newKVWrite("key4", nil)}}
+	expectedTxPvtRWSet := &TxPvtRwSet{[]*NsPvtRwSet{
+		&NsPvtRwSet{NameSpace: "ns1", CollPvtRwSets: []*CollPvtRwSet{
+			&CollPvtRwSet{CollectionName: "coll1", KvRwSet: expectedPvtKVRWSet},
+		}},
+	}}
+	testutil.AssertEquals(t, txPvtRWSet, expectedTxPvtRWSet)
+
+	pvtKVRWSetBytes, _ := proto.Marshal(expectedPvtKVRWSet)
+	expectedTxRWSet := &TxRwSet{[]*NsRwSet{
+		&NsRwSet{NameSpace: "ns1",
+			KvRwSet: &kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{newKVWrite("key1", []byte("value1"))}},
+			CollHashedRwSets: []*CollHashedRwSet{
+				&CollHashedRwSet{
+					CollectionName: "coll1",
+					HashedRwSet: &kvrwset.HashedRWSet{
+						HashedReads:  []*kvrwset.KVReadHash{newKVReadHash("key2", version.NewHeight(1, 2))},
+						HashedWrites: []*kvrwset.KVWriteHash{newKVWriteHash("key3", []byte("value3")), newKVWriteHash("key4", nil)},
+					},
+					PvtRwSetHash: ComputeHash(pvtKVRWSetBytes),
+				},
+				&CollHashedRwSet{
+					CollectionName: "coll2",
+					HashedRwSet: &kvrwset.HashedRWSet{
+						HashedReads: []*kvrwset.KVReadHash{newKVReadHash("key5", nil)},
+					},
+				},
+			},
+		},
+	}}
+	testutil.AssertEquals(t, txRWSet, expectedTxRWSet)
+	testutil.AssertEquals(t, txRWSet.NsRwSets[0].CollHashedRwSets[0].HashedRwSet.HashedWrites[1].IsDelete, true)
+
+	// A transaction without private writes does not produce private read-write set
+	rwSetBuilder = NewRWSetBuilder()
+	rwSetBuilder.AddToHashedReadSet("ns1", "coll1", "key2", version.NewHeight(1, 2))
+	_, txPvtRWSet, err = rwSetBuilder.GetTxSimulationResults()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, txPvtRWSet)
+}
@@ -18,7 +18,9 @@ package rwsetutil
 
 import (
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	ledgerutil "github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 )
@@ -29,9 +31,37 @@ type TxRwSet struct {
 }
 
 // NsRwSet encapsulates 'kvrwset.KVRWSet' proto message for a specific name space (chaincode)
+// and the hashed read-write sets of the private data collections of the name space
 type NsRwSet struct {
-	NameSpace string
-	KvRwSet   *kvrwset.KVRWSet
+	NameSpace        string
+	KvRwSet          *kvrwset.KVRWSet
+	CollHashedRwSets []*CollHashedRwSet
+}
+
+// CollHashedRwSet encapsulates 'kvrwset.HashedRWSet' proto message for a specific collection
+// PvtRwSetHash is the hash of the serialized private read-write set of the collection
+type CollHashedRwSet struct {
+	CollectionName string
+	HashedRwSet    *kvrwset.HashedRWSet
+	PvtRwSetHash   []byte
+}
+
+// TxPvtRwSet acts as a proxy of 'rwset.TxPvtReadWriteSet' proto message and helps constructing the private
+// read-write set specifically for KV data model
+type TxPvtRwSet struct {
+	NsPvtRwSets []*NsPvtRwSet
+}
+
+// NsPvtRwSet encapsulates the private read-write sets of the collections of a specific name space (chaincode)
+type NsPvtRwSet struct {
+	NameSpace     string
+	CollPvtRwSets []*CollPvtRwSet
+}
+
+// CollPvtRwSet encapsulates 'kvrwset.KVRWSet' proto message for the private data of a specific collection
+type CollPvtRwSet struct {
+	CollectionName string
+	KvRwSet        *kvrwset.KVRWSet
 }
 
 // ToProtoBytes constructs TxReadWriteSet proto message and serializes using protobuf Marshal
@@ -46,6 +76,13 @@ func (txRwSet *TxRwSet) ToProtoBytes() ([]byte, error) {
 			return nil, err
 		}
 		protoNsRwSet.Rwset = protoRwSetBytes
+		for _, collHashedRwSet := range nsRwSet.CollHashedRwSets {
+			protoCollHashedRwSet, err := collHashedRwSet.toProtoMsg()
+			if err != nil {
+				return nil, err
+			}
+			protoNsRwSet.CollectionHashedRwset = append(protoNsRwSet.CollectionHashedRwset, protoCollHashedRwSet)
+		}
 		protoTxRWSet.NsRwset = append(protoTxRWSet.NsRwset, protoNsRwSet)
 	}
 	protoTxRwSetBytes, err := proto.Marshal(protoTxRWSet)
@@ -73,11 +110,95 @@ func (txRwSet *TxRwSet) FromProtoBytes(protoBytes []byte) error {
 			return err
 		}
 		nsRwSet.KvRwSet = protoKvRwSet
+		for _, protoCollHashedRwSet := range protoNsRwSet.CollectionHashedRwset {
+			collHashedRwSet, err := collHashedRwSetFromProtoMsg(protoCollHashedRwSet)
+			if err != nil {
+				return err
+			}
+			nsRwSet.CollHashedRwSets = append(nsRwSet.CollHashedRwSets, collHashedRwSet)
+		}
 		txRwSet.NsRwSets = append(txRwSet.NsRwSets, nsRwSet)
 	}
 	return nil
 }
 
+func (collHashedRwSet *CollHashedRwSet) toProtoMsg() (*rwset.CollectionHashedReadWriteSet, error) {
+	hashedRwSetBytes, err := proto.Marshal(collHashedRwSet.HashedRwSet)
+	if err != nil {
+		return nil, err
+	}
+	return &rwset.CollectionHashedReadWriteSet{
+		CollectionName: collHashedRwSet.CollectionName,
+		HashedRwset:    hashedRwSetBytes,
+		PvtRwsetHash:   collHashedRwSet.PvtRwSetHash,
+	}, nil
+}
+
+func collHashedRwSetFromProtoMsg(protoMsg *rwset.CollectionHashedReadWriteSet) (*CollHashedRwSet, error) {
+	hashedRwSet := &kvrwset.HashedRWSet{}
+	if err := proto.Unmarshal(protoMsg.HashedRwset, hashedRwSet); err != nil {
+		return nil, err
+	}
+	return &CollHashedRwSet{
+		CollectionName: protoMsg.CollectionName,
+		HashedRwSet:    hashedRwSet,
+		PvtRwSetHash:   protoMsg.PvtRwsetHash,
+	}, nil
+}
+
+// ToProtoMsg constructs TxPvtReadWriteSet proto message
+func (txPvtRwSet *TxPvtRwSet) ToProtoMsg() (*rwset.TxPvtReadWriteSet, error) {
+	protoTxPvtRwSet := &rwset.TxPvtReadWriteSet{DataModel: rwset.TxReadWriteSet_KV}
+	for _, nsPvtRwSet := range txPvtRwSet.NsPvtRwSets {
+		protoNsPvtRwSet := &rwset.NsPvtReadWriteSet{Namespace: nsPvtRwSet.NameSpace}
+		for _, collPvtRwSet := range nsPvtRwSet.CollPvtRwSets {
+			protoRwSetBytes, err := proto.Marshal(collPvtRwSet.KvRwSet)
+			if err != nil {
+				return nil, err
+			}
+			protoNsPvtRwSet.CollectionPvtRwset = append(protoNsPvtRwSet.CollectionPvtRwset,
+				&rwset.CollectionPvtReadWriteSet{CollectionName: collPvtRwSet.CollectionName, Rwset: protoRwSetBytes})
+		}
+		protoTxPvtRwSet.NsPvtRwset = append(protoTxPvtRwSet.NsPvtRwset, protoNsPvtRwSet)
+	}
+	return protoTxPvtRwSet, nil
+}
+
+// ToProtoBytes constructs TxPvtReadWriteSet proto message and serializes using protobuf Marshal
+func (txPvtRwSet *TxPvtRwSet) ToProtoBytes() ([]byte, error) {
+	protoTxPvtRwSet, err := txPvtRwSet.ToProtoMsg()
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(protoTxPvtRwSet)
+}
+
+// FromProtoMsg populates 'TxPvtRwSet' from TxPvtReadWriteSet proto message
+func (txPvtRwSet *TxPvtRwSet) FromProtoMsg(protoTxPvtRwSet *rwset.TxPvtReadWriteSet) error {
+	for _, protoNsPvtRwSet := range protoTxPvtRwSet.NsPvtRwset {
+		nsPvtRwSet := &NsPvtRwSet{NameSpace: protoNsPvtRwSet.Namespace}
+		for _, protoCollPvtRwSet := range protoNsPvtRwSet.CollectionPvtRwset {
+			protoKvRwSet := &kvrwset.KVRWSet{}
+			if err := proto.Unmarshal(protoCollPvtRwSet.Rwset, protoKvRwSet); err != nil {
+				return err
+			}
+			nsPvtRwSet.CollPvtRwSets = append(nsPvtRwSet.CollPvtRwSets,
+				&CollPvtRwSet{CollectionName: protoCollPvtRwSet.CollectionName, KvRwSet: protoKvRwSet})
+		}
+		txPvtRwSet.NsPvtRwSets = append(txPvtRwSet.NsPvtRwSets, nsPvtRwSet)
+	}
+	return nil
+}
+
+// FromProtoBytes deserializes protobytes into TxPvtReadWriteSet proto message and populates 'TxPvtRwSet'
+func (txPvtRwSet *TxPvtRwSet) FromProtoBytes(protoBytes []byte) error {
+	protoTxPvtRwSet := &rwset.TxPvtReadWriteSet{}
+	if err := proto.Unmarshal(protoBytes, protoTxPvtRwSet); err != nil {
+		return err
+	}
+	return txPvtRwSet.FromProtoMsg(protoTxPvtRwSet)
+}
+
 // NewKVRead helps constructing proto message kvrwset.KVRead
 func NewKVRead(key string, version *version.Height) *kvrwset.KVRead {
 	return &kvrwset.KVRead{Key: key, Version: newProtoVersion(version)}
@@ -101,3 +222,67 @@ func newProtoVersion(height *version.Height) *kvrwset.Version {
 func newKVWrite(key string, value []byte) *kvrwset.KVWrite {
 	return &kvrwset.KVWrite{Key: key, IsDelete: value == nil, Value: value}
 }
+
+func newKVMetadataWrite(key string, metadata map[string][]byte) *kvrwset.KVMetadataWrite {
+	return &kvrwset.KVMetadataWrite{Key: key, Entries: newKVMetadataEntries(metadata)}
+}
+
+// newKVMetadataEntries returns the entries of the metadata sorted by name, so that the serialized form is deterministic
+func newKVMetadataEntries(metadata map[string][]byte) []*kvrwset.KVMetadataEntry {
+	var entries []*kvrwset.KVMetadataEntry
+	for _, name := range ledgerutil.GetSortedKeys(metadata) {
+		entries = append(entries, &kvrwset.KVMetadataEntry{Name: name, Value: metadata[name]})
+	}
+	return entries
+}
+
+// SerializeMetadata serializes the metadata of a key for storing it in the state db.
+// It returns nil for an empty metadata
+func SerializeMetadata(metadata map[string][]byte) ([]byte, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	return proto.Marshal(&kvrwset.KVMetadataWrite{Entries: newKVMetadataEntries(metadata)})
+}
+
+// DeserializeMetadata is the inverse of SerializeMetadata. It returns nil for an empty metadata
+func DeserializeMetadata(metadataBytes []byte) (map[string][]byte, error) {
+	if len(metadataBytes) == 0 {
+		return nil, nil
+	}
+	metadataWrite := &kvrwset.KVMetadataWrite{}
+	if err := proto.Unmarshal(metadataBytes, metadataWrite); err != nil {
+		return nil, err
+	}
+	return MetadataEntriesToMap(metadataWrite.Entries), nil
+}
+
+// MetadataEntriesToMap converts the entries of a metadata write to a map keyed by the name of the entry.
+// It returns nil if there are no entries
+func MetadataEntriesToMap(entries []*kvrwset.KVMetadataEntry) map[string][]byte {
+	if len(entries) == 0 {
+		return nil
+	}
+	metadata := make(map[string][]byte)
+	for _, entry := range entries {
+		metadata[entry.Name] = entry.Value
+	}
+	return metadata
+}
+
+func newKVReadHash(key string, version *version.Height) *kvrwset.KVReadHash {
+	return &kvrwset.KVReadHash{KeyHash: ComputeHash([]byte(key)), Version: newProtoVersion(version)}
+}
+
+func newKVWriteHash(key string, value []byte) *kvrwset.KVWriteHash {
+	kvWriteHash := &kvrwset.KVWriteHash{KeyHash: ComputeHash([]byte(key)), IsDelete: value == nil}
+	if value != nil {
+		kvWriteHash.ValueHash = ComputeHash(value)
+	}
+	return kvWriteHash
+}
+
+// ComputeHash computes the hash used for the keys and values of the private data and for the private read-write sets
+func ComputeHash(data []byte) []byte {
+	return util.ComputeSHA256(data)
+}
@@ -37,22 +37,22 @@ func TestTxRWSetMarshalUnmarshal(t *testing.T) {
 	rqi2.SetMerkelSummary(&kvrwset.QueryReadsMerkleSummary{MaxDegree: 5, MaxLevel: 4, MaxLevelHashes: [][]byte{[]byte("Hash-1"), []byte("Hash-2")}})
 
 	txRwSet.NsRwSets = []*NsRwSet{
-		&NsRwSet{"ns1", &kvrwset.KVRWSet{
-			[]*kvrwset.KVRead{&kvrwset.KVRead{Key: "key1", Version: &kvrwset.Version{BlockNum: 1, TxNum: 1}}},
-			[]*kvrwset.RangeQueryInfo{rqi1},
-			[]*kvrwset.KVWrite{&kvrwset.KVWrite{Key: "key2", IsDelete: false, Value: []byte("value2")}},
+		&NsRwSet{NameSpace: "ns1", KvRwSet: &kvrwset.KVRWSet{
+			Reads:            []*kvrwset.KVRead{&kvrwset.KVRead{Key: "key1", Version: &kvrwset.Version{BlockNum: 1, TxNum: 1}}},
+			RangeQueriesInfo: []*kvrwset.RangeQueryInfo{rqi1},
+			Writes:           []*kvrwset.KVWrite{&kvrwset.KVWrite{Key: "key2", IsDelete: false, Value: []byte("value2")}},
 		}},
 
-		&NsRwSet{"ns2", &kvrwset.KVRWSet{
-			[]*kvrwset.KVRead{&kvrwset.KVRead{Key: "key3", Version: &kvrwset.Version{BlockNum: 1, TxNum: 1}}},
-			[]*kvrwset.RangeQueryInfo{rqi2},
-			[]*kvrwset.KVWrite{&kvrwset.KVWrite{Key: "key3", IsDelete: false, Value: []byte("value3")}},
+		&NsRwSet{NameSpace: "ns2", KvRwSet: &kvrwset.KVRWSet{
+			Reads:            []*kvrwset.KVRead{&kvrwset.KVRead{Key: "key3", Version: &kvrwset.Version{BlockNum: 1, TxNum: 1}}},
+			RangeQueriesInfo: []*kvrwset.RangeQueryInfo{rqi2},
+			Writes:           []*kvrwset.KVWrite{&kvrwset.KVWrite{Key: "key3", IsDelete: false, Value: []byte("value3")}},
 		}},
 
-		&NsRwSet{"ns3", &kvrwset.KVRWSet{
-			[]*kvrwset.KVRead{&kvrwset.KVRead{Key: "key4", Version: &kvrwset.Version{BlockNum: 1, TxNum: 1}}},
-			nil,
-			[]*kvrwset.KVWrite{&kvrwset.KVWrite{Key: "key4", IsDelete: false, Value: []byte("value4")}},
+		&NsRwSet{NameSpace: "ns3", KvRwSet: &kvrwset.KVRWSet{
+			Reads:            []*kvrwset.KVRead{&kvrwset.KVRead{Key: "key4", Version: &kvrwset.Version{BlockNum: 1, TxNum: 1}}},
+			RangeQueriesInfo: nil,
+			Writes:           []*kvrwset.KVWrite{&kvrwset.KVWrite{Key: "key4", IsDelete: false, Value: []byte("value4")}},
 		}},
 	}
 
@@ -63,3 +63,49 @@ func TestTxRWSetMarshalUnmarshal(t *testing.T) {
 	t.Logf("txRwSet=%s, txRwSet1=%s", spew.Sdump(txRwSet), spew.Sdump(txRwSet1))
 	testutil.AssertEquals(t, txRwSet1, txRwSet)
 }
+
+func TestTxRWSetWithCollectionsMarshalUnmarshal(t *testing.T) {
+	txRwSet := &TxRwSet{}
+	txRwSet.NsRwSets = []*NsRwSet{
+		&NsRwSet{NameSpace: "ns1", KvRwSet: &kvrwset.KVRWSet{
+			Writes: []*kvrwset.KVWrite{&kvrwset.KVWrite{Key: "key1", Value: []byte("value1")}},
+		},
+			CollHashedRwSets: []*CollHashedRwSet{
+				&CollHashedRwSet{
+					CollectionName: "coll1",
+					HashedRwSet: &kvrwset.HashedRWSet{
+						HashedReads:  []*kvrwset.KVReadHash{&kvrwset.KVReadHash{KeyHash: []byte("Hash-key1"), Version: &kvrwset.Version{BlockNum: 1, TxNum: 1}}},
+						HashedWrites: []*kvrwset.KVWriteHash{&kvrwset.KVWriteHash{KeyHash: []byte("Hash-key2"), ValueHash: []byte("Hash-value2")}},
+					},
+					PvtRwSetHash: []byte("Hash-pvtRwSet"),
+				},
+			},
+		},
+	}
+
+	protoBytes, err := txRwSet.ToProtoBytes()
+	testutil.AssertNoError(t, err, "")
+	txRwSet1 := &TxRwSet{}
+	testutil.AssertNoError(t, txRwSet1.FromProtoBytes(protoBytes), "")
+	testutil.AssertEquals(t, txRwSet1, txRwSet)
+}
+
+func TestTxPvtRWSetMarshalUnmarshal(t *testing.T) {
+	txPvtRwSet := &TxPvtRwSet{}
+	txPvtRwSet.NsPvtRwSets = []*NsPvtRwSet{
+		&NsPvtRwSet{NameSpace: "ns1", CollPvtRwSets: []*CollPvtRwSet{
+			&CollPvtRwSet{CollectionName: "coll1", KvRwSet: &kvrwset.KVRWSet{
+				Writes: []*kvrwset.KVWrite{&kvrwset.KVWrite{Key: "key1", Value: []byte("value1")}},
+			}},
+			&CollPvtRwSet{CollectionName: "coll2", KvRwSet: &kvrwset.KVRWSet{
+				Writes: []*kvrwset.KVWrite{&kvrwset.KVWrite{Key: "key2", IsDelete: true}},
+			}},
+		}},
+	}
+
+	protoBytes, err := txPvtRwSet.ToProtoBytes()
+	testutil.AssertNoError(t, err, "")
+	txPvtRwSet1 := &TxPvtRwSet{}
+	testutil.AssertNoError(t, txPvtRwSet1.FromProtoBytes(protoBytes), "")
+	testutil.AssertEquals(t, txPvtRwSet1, txPvtRwSet)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commontests
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+)
+
+// TestDBProviderFactory returns a VersionedDBProvider backed by an empty store, along with
+// a function that closes the provider and removes the data created by the test
+type TestDBProviderFactory func(t *testing.T) (statedb.VersionedDBProvider, func())
+
+// RunConformanceTests runs the tests that any VersionedDBProvider implementation is expected to pass.
+// Each test is run as a subtest against a new provider obtained from newDBProvider. The tests of the
+// queries on the values (i.e., ExecuteQuery) are run only if supportsQueries is true
+func RunConformanceTests(t *testing.T, newDBProvider TestDBProviderFactory, supportsQueries bool) {
+	tests := []struct {
+		name         string
+		test         func(t *testing.T, dbProvider statedb.VersionedDBProvider)
+		needsQueries bool
+	}{
+		{"BasicRW", TestBasicRW, false},
+		{"MultiDBBasicRW", TestMultiDBBasicRW, false},
+		{"Deletes", TestDeletes, false},
+		{"Iterator", TestIterator, false},
+		{"PaginatedRangeQuery", TestPaginatedRangeQuery, false},
+		{"GetStateMultipleKeys", TestGetStateMultipleKeys, false},
+		{"FullScanIterator", TestFullScanIterator, false},
+		{"Query", TestQuery, true},
+		{"PaginatedQuery", TestPaginatedQuery, true},
+	}
+	for _, tst := range tests {
+		if tst.needsQueries && !supportsQueries {
+			continue
+		}
+		test := tst.test
+		t.Run(tst.name, func(t *testing.T) {
+			dbProvider, cleanup := newDBProvider(t)
+			defer cleanup()
+			test(t, dbProvider)
+		})
+	}
+}
+
+// TestGetStateMultipleKeys tests reading multiple keys in a single call
+func TestGetStateMultipleKeys(t *testing.T, dbProvider statedb.VersionedDBProvider) {
+	db, err := dbProvider.GetDBHandle("testgetmultiplekeys")
+	testutil.AssertNoError(t, err, "")
+	db.Open()
+	defer db.Close()
+	batch := statedb.NewUpdateBatch()
+	vv1 := statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 1)}
+	vv2 := statedb.VersionedValue{Value: []byte("value2"), Version: version.NewHeight(1, 2)}
+	batch.Put("ns1", "key1", vv1.Value, vv1.Version)
+	batch.Put("ns1", "key2", vv2.Value, vv2.Version)
+	batch.Put("ns2", "key3", []byte("value3"), version.NewHeight(1, 3))
+	db.ApplyUpdates(batch, version.NewHeight(2, 3))
+
+	vvs, err := db.GetStateMultipleKeys("ns1", []string{"key2", "non-existent-key", "key1"})
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, vvs, []*statedb.VersionedValue{&vv2, nil, &vv1})
+}
+
+// TestFullScanIterator tests the iterator over the complete state
+func TestFullScanIterator(t *testing.T, dbProvider statedb.VersionedDBProvider) {
+	db, err := dbProvider.GetDBHandle("testfullscaniterator")
+	testutil.AssertNoError(t, err, "")
+	db.Open()
+	defer db.Close()
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	batch.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 2))
+	batch.Put("ns2", "key3", []byte("value3"), version.NewHeight(1, 3))
+	db.ApplyUpdates(batch, version.NewHeight(2, 3))
+
+	itr, err := db.GetFullScanIterator()
+	testutil.AssertNoError(t, err, "")
+	defer itr.Close()
+	// the order of the results across the namespaces is specific to the implementation
+	results := make(map[statedb.CompositeKey]*statedb.VersionedValue)
+	for {
+		queryResult, err := itr.Next()
+		testutil.AssertNoError(t, err, "")
+		if queryResult == nil {
+			break
+		}
+		vkv := queryResult.(*statedb.VersionedKV)
+		results[vkv.CompositeKey] = &statedb.VersionedValue{Value: vkv.Value, Version: vkv.Version}
+	}
+	testutil.AssertEquals(t, results, map[statedb.CompositeKey]*statedb.VersionedValue{
+		statedb.CompositeKey{Namespace: "ns1", Key: "key1"}: &statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 1)},
+		statedb.CompositeKey{Namespace: "ns1", Key: "key2"}: &statedb.VersionedValue{Value: []byte("value2"), Version: version.NewHeight(1, 2)},
+		statedb.CompositeKey{Namespace: "ns2", Key: "key3"}: &statedb.VersionedValue{Value: []byte("value3"), Version: version.NewHeight(1, 3)},
+	})
+}
@@ -59,6 +59,14 @@ func TestBasicRW(t *testing.T, dbProvider statedb.VersionedDBProvider) {
 	vv, _ = db.GetState("ns2", "key4")
 	testutil.AssertEquals(t, vv, &vv4)
 
+	ver, err := db.GetVersion("ns2", "key3")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, ver, vv3.Version)
+
+	ver, err = db.GetVersion("ns2", "non-existent-key")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, ver)
+
 	sp, err = db.GetLatestSavePoint()
 	testutil.AssertNoError(t, err, "")
 	testutil.AssertEquals(t, sp, savePoint)
@@ -103,6 +111,37 @@ func TestMultiDBBasicRW(t *testing.T, dbProvider statedb.VersionedDBProvider) {
 	testutil.AssertEquals(t, sp, savePoint2)
 }
 
+// TestValueAndMetadataWrites tests that the metadata of a key is stored and retrieved along with its value
+func TestValueAndMetadataWrites(t *testing.T, dbProvider statedb.VersionedDBProvider) {
+	db, err := dbProvider.GetDBHandle("testvalueandmetadata")
+	testutil.AssertNoError(t, err, "")
+
+	batch := statedb.NewUpdateBatch()
+	vv1 := statedb.VersionedValue{Value: []byte("value1"), Metadata: []byte("metadata1"), Version: version.NewHeight(1, 1)}
+	vv2 := statedb.VersionedValue{Value: []byte("value2"), Version: version.NewHeight(1, 2)}
+	batch.PutValAndMetadata("ns1", "key1", vv1.Value, vv1.Metadata, vv1.Version)
+	batch.Put("ns1", "key2", vv2.Value, vv2.Version)
+	db.ApplyUpdates(batch, version.NewHeight(1, 2))
+
+	vv, _ := db.GetState("ns1", "key1")
+	testutil.AssertEquals(t, vv, &vv1)
+	vv, _ = db.GetState("ns1", "key2")
+	testutil.AssertEquals(t, vv, &vv2)
+
+	itr, err := db.GetStateRangeScanIterator("ns1", "", "")
+	testutil.AssertNoError(t, err, "")
+	defer itr.Close()
+	queryResult, err := itr.Next()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, queryResult.(*statedb.VersionedKV).VersionedValue, vv1)
+
+	batch = statedb.NewUpdateBatch()
+	batch.Delete("ns1", "key1", version.NewHeight(2, 1))
+	db.ApplyUpdates(batch, version.NewHeight(2, 1))
+	vv, _ = db.GetState("ns1", "key1")
+	testutil.AssertNil(t, vv)
+}
+
 // TestDeletes tests deteles
 func TestDeletes(t *testing.T, dbProvider statedb.VersionedDBProvider) {
 	db, err := dbProvider.GetDBHandle("testdeletes")
@@ -168,6 +207,85 @@ func TestIterator(t *testing.T, dbProvider statedb.VersionedDBProvider) {
 	testItr(t, itr4, []string{"key5", "key6"})
 }
 
+// TestPaginatedRangeQuery tests the range scan iterator with pagination
+func TestPaginatedRangeQuery(t *testing.T, dbProvider statedb.VersionedDBProvider) {
+	db, err := dbProvider.GetDBHandle("testpaginatedrangequery")
+	testutil.AssertNoError(t, err, "")
+	db.Open()
+	defer db.Close()
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	batch.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 2))
+	batch.Put("ns1", "key3", []byte("value3"), version.NewHeight(1, 3))
+	batch.Put("ns1", "key4", []byte("value4"), version.NewHeight(1, 4))
+	batch.Put("ns1", "key5", []byte("value5"), version.NewHeight(1, 5))
+	batch.Put("ns2", "key6", []byte("value6"), version.NewHeight(1, 6))
+	savePoint := version.NewHeight(2, 6)
+	db.ApplyUpdates(batch, savePoint)
+
+	itr, err := db.GetStateRangeScanIteratorWithPagination("ns1", "", "", 2)
+	testutil.AssertNoError(t, err, "")
+	testPaginatedItr(t, itr, []string{"key1", "key2"}, "key3")
+
+	itr, _ = db.GetStateRangeScanIteratorWithPagination("ns1", "key3", "", 2)
+	testPaginatedItr(t, itr, []string{"key3", "key4"}, "key5")
+
+	itr, _ = db.GetStateRangeScanIteratorWithPagination("ns1", "key5", "", 2)
+	testPaginatedItr(t, itr, []string{"key5"}, "")
+
+	// the page that ends exactly at the end of the range
+	itr, _ = db.GetStateRangeScanIteratorWithPagination("ns1", "key2", "key4", 2)
+	testPaginatedItr(t, itr, []string{"key2", "key3"}, "")
+
+	itr, _ = db.GetStateRangeScanIteratorWithPagination("ns2", "", "", 5)
+	testPaginatedItr(t, itr, []string{"key6"}, "")
+
+	_, err = db.GetStateRangeScanIteratorWithPagination("ns1", "", "", 0)
+	testutil.AssertError(t, err, "Expected an error for zero page size")
+}
+
+// TestPaginatedQuery tests the queries with pagination
+func TestPaginatedQuery(t *testing.T, dbProvider statedb.VersionedDBProvider) {
+	db, err := dbProvider.GetDBHandle("testpaginatedquery")
+	testutil.AssertNoError(t, err, "")
+	db.Open()
+	defer db.Close()
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("{\"owner\": \"fred\"}"), version.NewHeight(1, 1))
+	batch.Put("ns1", "key2", []byte("{\"owner\": \"jerry\"}"), version.NewHeight(1, 2))
+	batch.Put("ns1", "key3", []byte("{\"owner\": \"fred\"}"), version.NewHeight(1, 3))
+	batch.Put("ns1", "key4", []byte("{\"owner\": \"fred\"}"), version.NewHeight(1, 4))
+	batch.Put("ns2", "key5", []byte("{\"owner\": \"fred\"}"), version.NewHeight(1, 5))
+	savePoint := version.NewHeight(2, 5)
+	db.ApplyUpdates(batch, savePoint)
+
+	query := "{\"selector\":{\"owner\":\"fred\"}}"
+	itr, err := db.ExecuteQueryWithPagination("ns1", query, "", 2)
+	testutil.AssertNoError(t, err, "")
+	testItr(t, itr, []string{"key1", "key3"})
+	bookmark := itr.GetBookmarkAndClose()
+	testutil.AssertNotEquals(t, bookmark, "")
+
+	itr, err = db.ExecuteQueryWithPagination("ns1", query, bookmark, 2)
+	testutil.AssertNoError(t, err, "")
+	testPaginatedItr(t, itr, []string{"key4"}, "")
+
+	_, err = db.ExecuteQueryWithPagination("ns1", query, "not-a-bookmark", 2)
+	testutil.AssertError(t, err, "Expected an error for an invalid bookmark")
+}
+
+func testPaginatedItr(t *testing.T, itr statedb.QueryResultsIterator, expectedKeys []string, expectedBookmark string) {
+	for _, expectedKey := range expectedKeys {
+		queryResult, _ := itr.Next()
+		vkv := queryResult.(*statedb.VersionedKV)
+		testutil.AssertEquals(t, vkv.Key, expectedKey)
+	}
+	last, err := itr.Next()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, last)
+	testutil.AssertEquals(t, itr.GetBookmarkAndClose(), expectedBookmark)
+}
+
 func testItr(t *testing.T, itr statedb.ResultsIterator, expectedKeys []string) {
 	defer itr.Close()
 	for _, expectedKey := range expectedKeys {
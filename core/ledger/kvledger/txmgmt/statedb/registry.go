@@ -0,0 +1,71 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statedb
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// VersionedDBProviderFactory constructs a VersionedDBProvider. It is invoked once, when the ledger provider is initialized
+type VersionedDBProviderFactory func() (VersionedDBProvider, error)
+
+var (
+	providerFactoriesLock sync.RWMutex
+	providerFactories     = make(map[string]VersionedDBProviderFactory)
+)
+
+// RegisterVersionedDBProvider makes a VersionedDBProvider implementation available under the given name, which is the
+// value that selects the implementation in the property "ledger.state.stateDatabase" of core.yaml. It is meant to be
+// called from the init function of the package of the implementation and it panics if the name is empty or if the
+// name is already registered
+func RegisterVersionedDBProvider(name string, factory VersionedDBProviderFactory) {
+	providerFactoriesLock.Lock()
+	defer providerFactoriesLock.Unlock()
+	if name == "" || factory == nil {
+		panic("statedb: a VersionedDBProvider must be registered with a name and a factory")
+	}
+	if _, exists := providerFactories[name]; exists {
+		panic(fmt.Sprintf("statedb: a VersionedDBProvider is already registered with name [%s]", name))
+	}
+	providerFactories[name] = factory
+}
+
+// NewVersionedDBProvider constructs the VersionedDBProvider registered under the given name
+func NewVersionedDBProvider(name string) (VersionedDBProvider, error) {
+	providerFactoriesLock.RLock()
+	factory, ok := providerFactories[name]
+	providerFactoriesLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("No state database registered with name [%s]. Registered state databases: %v",
+			name, RegisteredVersionedDBProviders())
+	}
+	return factory()
+}
+
+// RegisteredVersionedDBProviders returns the sorted names of the registered VersionedDBProvider implementations
+func RegisteredVersionedDBProviders() []string {
+	providerFactoriesLock.RLock()
+	defer providerFactoriesLock.RUnlock()
+	names := make([]string, 0, len(providerFactories))
+	for name := range providerFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
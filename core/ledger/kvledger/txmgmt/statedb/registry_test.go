@@ -0,0 +1,60 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statedb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+)
+
+type dummyVersionedDBProvider struct {
+	VersionedDBProvider
+}
+
+func TestVersionedDBProviderRegistry(t *testing.T) {
+	RegisterVersionedDBProvider("testdb", func() (VersionedDBProvider, error) {
+		return &dummyVersionedDBProvider{}, nil
+	})
+	RegisterVersionedDBProvider("testdb-failing", func() (VersionedDBProvider, error) {
+		return nil, errors.New("cannot connect")
+	})
+	testutil.AssertContainsAll(t, RegisteredVersionedDBProviders(), []string{"testdb", "testdb-failing"})
+
+	provider, err := NewVersionedDBProvider("testdb")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, provider, &dummyVersionedDBProvider{})
+
+	_, err = NewVersionedDBProvider("testdb-failing")
+	testutil.AssertError(t, err, "Expected the error of the factory")
+
+	_, err = NewVersionedDBProvider("testdb-unknown")
+	testutil.AssertError(t, err, "Expected an error for a name that is not registered")
+}
+
+func TestRegisterVersionedDBProviderTwice(t *testing.T) {
+	factory := func() (VersionedDBProvider, error) { return &dummyVersionedDBProvider{}, nil }
+	RegisterVersionedDBProvider("testdb-twice", factory)
+	defer testutil.AssertPanic(t, "Expected a panic when registering a name twice")
+	RegisterVersionedDBProvider("testdb-twice", factory)
+}
+
+func TestRegisterVersionedDBProviderWithoutName(t *testing.T) {
+	defer testutil.AssertPanic(t, "Expected a panic when registering without a name")
+	RegisterVersionedDBProvider("", func() (VersionedDBProvider, error) { return &dummyVersionedDBProvider{}, nil })
+}
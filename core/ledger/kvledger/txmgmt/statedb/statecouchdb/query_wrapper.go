@@ -29,6 +29,9 @@ const jsonQuerySelector = "selector"
 const jsonQueryUseIndex = "use_index"
 const jsonQueryLimit = "limit"
 const jsonQuerySkip = "skip"
+const jsonIndex = "index"
+const jsonIndexFields = "fields"
+const jsonChaincodeID = "chaincodeid"
 
 var validOperators = []string{"$and", "$or", "$not", "$nor", "$all", "$elemMatch",
 	"$lt", "$lte", "$eq", "$ne", "$gte", "$gt", "$exits", "$type", "$in", "$nin",
@@ -112,6 +115,82 @@ func ApplyQueryWrapper(namespace, queryString string, queryLimit, querySkip int)
 
 }
 
+/*
+ApplyIndexWrapper parses an index definition packaged with a chaincode
+the wrapper prepends the wrapper "data." to all the fields listed in the index,
+since the chaincode values are stored under the "data" field of the CouchDB documents.
+Both the plain field names and the sort objects are supported
+
+- The "chaincodeid" field, which is added to the selector of all queries, is not wrapped
+
+Example:
+
+Source Index:
+{"index":{"fields":["chaincodeid","owner",{"size":"desc"}]},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}
+
+Result Wrapped Index:
+{"ddoc":"indexOwnerDoc","index":{"fields":["chaincodeid","data.owner",{"data.size":"desc"}]},"name":"indexOwner","type":"json"}
+
+*/
+func ApplyIndexWrapper(indexDefinition string) (string, error) {
+
+	//create a generic map for the index json
+	jsonIndexMap := make(map[string]interface{})
+
+	//unmarshal the index definition into the generic map
+	decoder := json.NewDecoder(bytes.NewBuffer([]byte(indexDefinition)))
+	decoder.UseNumber()
+	err := decoder.Decode(&jsonIndexMap)
+	if err != nil {
+		return "", err
+	}
+
+	indexValue, ok := jsonIndexMap[jsonIndex].(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("Index definition does not contain an \"%s\" object", jsonIndex)
+	}
+
+	fields, ok := indexValue[jsonIndexFields].([]interface{})
+	if !ok || len(fields) == 0 {
+		return "", fmt.Errorf("Index definition does not contain any \"%s\"", jsonIndexFields)
+	}
+
+	for i, field := range fields {
+		switch fieldValue := field.(type) {
+
+		case string:
+			//this is a simple field name, so wrap the field and replace in the array
+			if fieldValue != jsonChaincodeID {
+				fields[i] = fmt.Sprintf("%v.%v", dataWrapper, fieldValue)
+			}
+
+		case map[string]interface{}:
+			//this is a sort object such as {"size":"desc"}, so wrap the field names
+			//(collect the names first, since wrapping replaces the keys in the map)
+			fieldNames := make([]string, 0, len(fieldValue))
+			for fieldName := range fieldValue {
+				fieldNames = append(fieldNames, fieldName)
+			}
+			for _, fieldName := range fieldNames {
+				if fieldName == jsonChaincodeID {
+					continue
+				}
+				wrapFieldName(fieldValue, fieldName, fieldValue[fieldName])
+			}
+
+		default:
+			return "", fmt.Errorf("Invalid field [%v] in the index definition", field)
+		}
+	}
+
+	//Marshal the updated index definition
+	editedIndex, _ := json.Marshal(jsonIndexMap)
+
+	logger.Debugf("Rewritten index definition with data wrapper: %s", editedIndex)
+
+	return string(editedIndex), nil
+}
+
 //setNamespaceInSelector adds an additional heirarchy in the "selector"
 //{"owner": {"$eq": "tom"}}
 //would be mapped as (assuming a namespace of "marble"):
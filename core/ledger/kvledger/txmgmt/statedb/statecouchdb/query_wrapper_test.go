@@ -381,3 +381,30 @@ func TestQueryWithLargeInteger(t *testing.T) {
 	testutil.AssertEquals(t, strings.Count(wrappedQuery, "{\"$eq\":1000007}"), 1)
 
 }
+
+// TestIndexWrapper tests wrapping the fields of an index definition
+func TestIndexWrapper(t *testing.T) {
+
+	rawIndex := []byte(`{"index":{"fields":["chaincodeid","owner",{"size":"desc"},{"chaincodeid":"desc"}]},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}`)
+
+	wrappedIndex, err := ApplyIndexWrapper(string(rawIndex))
+
+	//Make sure the index did not throw an exception
+	testutil.AssertNoError(t, err, "Unexpected error thrown when for index JSON")
+
+	testutil.AssertEquals(t, wrappedIndex,
+		`{"ddoc":"indexOwnerDoc","index":{"fields":["chaincodeid","data.owner",{"data.size":"desc"},{"chaincodeid":"desc"}]},"name":"indexOwner","type":"json"}`)
+
+	//an index without fields should be rejected
+	_, err = ApplyIndexWrapper(`{"index":{"fields":[]},"name":"indexOwner","type":"json"}`)
+	testutil.AssertError(t, err, "Expected an error for an index without fields")
+
+	//a definition without an index should be rejected
+	_, err = ApplyIndexWrapper(`{"name":"indexOwner","type":"json"}`)
+	testutil.AssertError(t, err, "Expected an error for a definition without an index")
+
+	//an invalid JSON should be rejected
+	_, err = ApplyIndexWrapper(`{"index":{"fields"["owner"]}}`)
+	testutil.AssertError(t, err, "Expected an error for an invalid JSON")
+
+}
@@ -18,9 +18,11 @@ package statecouchdb
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -34,11 +36,28 @@ import (
 
 var logger = flogging.MustGetLogger("statecouchdb")
 
+// ProviderName is the name under which the CouchDB backed VersionedDBProvider is registered
+const ProviderName = "CouchDB"
+
+func init() {
+	statedb.RegisterVersionedDBProvider(ProviderName, func() (statedb.VersionedDBProvider, error) {
+		return NewVersionedDBProvider()
+	})
+}
+
 var compositeKeySep = []byte{0x00}
 var lastKeyIndicator = byte(0x01)
 
 var binaryWrapper = "valueBytes"
 
+// fields of a CouchDB document used for bulk updates
+const idField = "_id"
+const revField = "_rev"
+const deletedField = "_deleted"
+
+// metadataField holds the base64 encoded metadata of a key, if any
+const metadataField = "~metadata"
+
 //querySkip is implemented for future use by query paging
 //currently defaulted to 0 and is not used
 var querySkip = 0
@@ -88,8 +107,27 @@ func (provider *VersionedDBProvider) Close() {
 
 // VersionedDB implements VersionedDB interface
 type VersionedDB struct {
-	db     *couchdb.CouchDatabase
-	dbName string
+	db                 *couchdb.CouchDatabase
+	dbName             string
+	committedDataCache *committedDataCache
+}
+
+// committedDataCache holds the committed versions, the metadata and the CouchDB revision numbers of
+// the keys loaded in bulk before validating a block. The revision numbers are needed for updating
+// the documents in bulk when the block is committed, after which the cache is cleared
+type committedDataCache struct {
+	sync.RWMutex
+	committedVersions map[statedb.CompositeKey]*version.Height
+	committedMetadata map[statedb.CompositeKey][]byte
+	revisionNumbers   map[statedb.CompositeKey]string
+}
+
+func newCommittedDataCache() *committedDataCache {
+	return &committedDataCache{
+		committedVersions: make(map[statedb.CompositeKey]*version.Height),
+		committedMetadata: make(map[statedb.CompositeKey][]byte),
+		revisionNumbers:   make(map[statedb.CompositeKey]string),
+	}
 }
 
 // newVersionedDB constructs an instance of VersionedDB
@@ -99,7 +137,7 @@ func newVersionedDB(couchInstance *couchdb.CouchInstance, dbName string) (*Versi
 	if err != nil {
 		return nil, err
 	}
-	return &VersionedDB{db, dbName}, nil
+	return &VersionedDB{db, dbName, newCommittedDataCache()}, nil
 }
 
 // Open implements method in VersionedDB interface
@@ -113,6 +151,34 @@ func (vdb *VersionedDB) Close() {
 	// no need to close db since a shared couch instance is used
 }
 
+// GetDBType implements method in statedb.IndexCapable interface
+func (vdb *VersionedDB) GetDBType() string {
+	return "couchdb"
+}
+
+// ProcessIndexesForChaincodeDeploy implements method in statedb.IndexCapable interface.
+// The field names in the index definitions are wrapped with the data wrapper before creating the indexes.
+// Since the indexes are shared by all the documents in the channel database, the namespace is used only for logging
+func (vdb *VersionedDB) ProcessIndexesForChaincodeDeploy(namespace string, indexFiles map[string][]byte) error {
+	fileNames := make([]string, 0, len(indexFiles))
+	for fileName := range indexFiles {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	for _, fileName := range fileNames {
+		indexDefinition, err := ApplyIndexWrapper(string(indexFiles[fileName]))
+		if err != nil {
+			return fmt.Errorf("Error processing index definition [%s] for chaincode [%s]: %s", fileName, namespace, err)
+		}
+		if _, err := vdb.db.CreateIndex(indexDefinition); err != nil {
+			return fmt.Errorf("Error creating index from definition [%s] for chaincode [%s]: %s", fileName, namespace, err)
+		}
+		logger.Infof("Created index from definition [%s] for chaincode [%s] in state database [%s]", fileName, namespace, vdb.dbName)
+	}
+	return nil
+}
+
 // GetState implements method in VersionedDB interface
 func (vdb *VersionedDB) GetState(namespace string, key string) (*statedb.VersionedValue, error) {
 	logger.Debugf("GetState(). ns=%s, key=%s", namespace, key)
@@ -128,12 +194,141 @@ func (vdb *VersionedDB) GetState(namespace string, key string) (*statedb.Version
 	}
 
 	//remove the data wrapper and return the value and version
-	returnValue, returnVersion := removeDataWrapper(couchDoc.JSONValue, couchDoc.Attachments)
+	returnValue, returnMetadata, returnVersion := removeDataWrapper(couchDoc.JSONValue, couchDoc.Attachments)
+
+	return &statedb.VersionedValue{Value: returnValue, Metadata: returnMetadata, Version: &returnVersion}, nil
+}
+
+// GetVersion implements method in VersionedDB interface.
+// The version is served from the cache, if the key was loaded by LoadCommittedVersions
+func (vdb *VersionedDB) GetVersion(namespace string, key string) (*version.Height, error) {
+	if ver, ok := vdb.GetCachedVersion(namespace, key); ok {
+		return ver, nil
+	}
+	versionedValue, err := vdb.GetState(namespace, key)
+	if err != nil {
+		return nil, err
+	}
+	if versionedValue == nil {
+		return nil, nil
+	}
+	return versionedValue.Version, nil
+}
+
+// LoadCommittedVersions implements method in statedb.BulkOptimizable interface.
+// The versions, the metadata and the revision numbers of all the keys are retrieved in a single bulk request.
+// The keys that do not exist in the db are cached with a nil version
+func (vdb *VersionedDB) LoadCommittedVersions(keys []*statedb.CompositeKey) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(keys))
+	for _, key := range keys {
+		ids = append(ids, string(constructCompositeKey(key.Namespace, key.Key)))
+	}
+
+	docMetadata, err := vdb.db.BatchRetrieveIDRevision(ids)
+	if err != nil {
+		return err
+	}
+
+	cache := vdb.committedDataCache
+	cache.Lock()
+	defer cache.Unlock()
+
+	for _, key := range keys {
+		cache.committedVersions[*key] = nil
+		delete(cache.committedMetadata, *key)
+		delete(cache.revisionNumbers, *key)
+	}
 
-	return &statedb.VersionedValue{Value: returnValue, Version: &returnVersion}, nil
+	for _, metadata := range docMetadata {
+		// keys that are not found or deleted have no revision
+		if metadata.ID == "" || metadata.Rev == "" {
+			continue
+		}
+		ns, key := splitCompositeKey([]byte(metadata.ID))
+		committedVersion, err := createVersionHeightFromVersionString(metadata.Version)
+		if err != nil {
+			return err
+		}
+		compositeKey := statedb.CompositeKey{Namespace: ns, Key: key}
+		cache.committedVersions[compositeKey] = committedVersion
+		cache.revisionNumbers[compositeKey] = metadata.Rev
+		if metadata.Metadata != "" {
+			keyMetadata, err := base64.StdEncoding.DecodeString(metadata.Metadata)
+			if err != nil {
+				return err
+			}
+			cache.committedMetadata[compositeKey] = keyMetadata
+		}
+	}
+	logger.Debugf("Channel [%s]: Loaded the committed versions of %d key(s)", vdb.dbName, len(keys))
+	return nil
 }
 
-func removeDataWrapper(wrappedValue []byte, attachments []*couchdb.Attachment) ([]byte, version.Height) {
+// GetCachedVersion implements method in statedb.BulkOptimizable interface
+func (vdb *VersionedDB) GetCachedVersion(namespace, key string) (*version.Height, bool) {
+	cache := vdb.committedDataCache
+	cache.RLock()
+	defer cache.RUnlock()
+	ver, ok := cache.committedVersions[statedb.CompositeKey{Namespace: namespace, Key: key}]
+	return ver, ok
+}
+
+// GetCachedMetadata implements method in statedb.BulkOptimizable interface
+func (vdb *VersionedDB) GetCachedMetadata(namespace, key string) ([]byte, bool) {
+	cache := vdb.committedDataCache
+	cache.RLock()
+	defer cache.RUnlock()
+	compositeKey := statedb.CompositeKey{Namespace: namespace, Key: key}
+	if _, ok := cache.committedVersions[compositeKey]; !ok {
+		return nil, false
+	}
+	return cache.committedMetadata[compositeKey], true
+}
+
+// ClearCachedVersions implements method in statedb.BulkOptimizable interface
+func (vdb *VersionedDB) ClearCachedVersions() {
+	cache := vdb.committedDataCache
+	cache.Lock()
+	defer cache.Unlock()
+	cache.committedVersions = make(map[statedb.CompositeKey]*version.Height)
+	cache.committedMetadata = make(map[statedb.CompositeKey][]byte)
+	cache.revisionNumbers = make(map[statedb.CompositeKey]string)
+}
+
+// getCachedRevision returns the CouchDB revision number of the key from the cache.
+// The boolean indicates whether the key is present in the cache
+func (vdb *VersionedDB) getCachedRevision(key statedb.CompositeKey) (string, bool) {
+	cache := vdb.committedDataCache
+	cache.RLock()
+	defer cache.RUnlock()
+	if _, ok := cache.committedVersions[key]; !ok {
+		return "", false
+	}
+	return cache.revisionNumbers[key], true
+}
+
+// createVersionHeightFromVersionString parses the "version" field of a CouchDB document
+func createVersionHeightFromVersionString(encodedVersion string) (*version.Height, error) {
+	versionArray := strings.Split(encodedVersion, ":")
+	if len(versionArray) != 2 {
+		return nil, fmt.Errorf("Invalid version [%s]", encodedVersion)
+	}
+	blockNum, err := strconv.ParseUint(versionArray[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid version [%s]: %s", encodedVersion, err)
+	}
+	txNum, err := strconv.ParseUint(versionArray[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid version [%s]: %s", encodedVersion, err)
+	}
+	return version.NewHeight(blockNum, txNum), nil
+}
+
+func removeDataWrapper(wrappedValue []byte, attachments []*couchdb.Attachment) ([]byte, []byte, version.Height) {
 
 	//initialize the return value
 	returnValue := []byte{}
@@ -166,6 +361,12 @@ func removeDataWrapper(wrappedValue []byte, attachments []*couchdb.Attachment) (
 
 	}
 
+	//the metadata is marshalled as a base64 encoded string
+	var returnMetadata []byte
+	if encodedMetadata, ok := jsonResult[metadataField].(string); ok {
+		returnMetadata, _ = base64.StdEncoding.DecodeString(encodedMetadata)
+	}
+
 	//create an array containing the blockNum and txNum
 	versionArray := strings.Split(fmt.Sprintf("%s", jsonResult["version"]), ":")
 
@@ -178,7 +379,7 @@ func removeDataWrapper(wrappedValue []byte, attachments []*couchdb.Attachment) (
 	//create the version based on the blockNum and txNum
 	returnVersion = version.NewHeight(blockNum, txNum)
 
-	return returnValue, *returnVersion
+	return returnValue, returnMetadata, *returnVersion
 
 }
 
@@ -205,19 +406,41 @@ func (vdb *VersionedDB) GetStateRangeScanIterator(namespace string, startKey str
 	//Get the querylimit from core.yaml
 	queryLimit := ledgerconfig.GetQueryLimit()
 
+	queryResult, err := vdb.readDocRange(namespace, startKey, endKey, queryLimit)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debugf("Exiting GetStateRangeScanIterator")
+	return newKVScanner(namespace, queryResult, 0), nil
+
+}
+
+// GetStateRangeScanIteratorWithPagination implements method in VersionedDB interface
+// One more document than the page size is retrieved so as to find the startKey of the next page
+func (vdb *VersionedDB) GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string, pageSize int32) (statedb.QueryResultsIterator, error) {
+	if err := validatePageSize(pageSize); err != nil {
+		return nil, err
+	}
+	queryResult, err := vdb.readDocRange(namespace, startKey, endKey, int(pageSize)+1)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debugf("Exiting GetStateRangeScanIteratorWithPagination")
+	return newKVScanner(namespace, queryResult, pageSize), nil
+}
+
+func (vdb *VersionedDB) readDocRange(namespace string, startKey string, endKey string, limit int) ([]couchdb.QueryResult, error) {
 	compositeStartKey := constructCompositeKey(namespace, startKey)
 	compositeEndKey := constructCompositeKey(namespace, endKey)
 	if endKey == "" {
 		compositeEndKey[len(compositeEndKey)-1] = lastKeyIndicator
 	}
-	queryResult, err := vdb.db.ReadDocRange(string(compositeStartKey), string(compositeEndKey), queryLimit, querySkip)
+	queryResult, err := vdb.db.ReadDocRange(string(compositeStartKey), string(compositeEndKey), limit, querySkip)
 	if err != nil {
 		logger.Debugf("Error calling ReadDocRange(): %s\n", err.Error())
 		return nil, err
 	}
-	logger.Debugf("Exiting GetStateRangeScanIterator")
-	return newKVScanner(namespace, *queryResult), nil
-
+	return *queryResult, nil
 }
 
 // ExecuteQuery implements method in VersionedDB interface
@@ -226,7 +449,39 @@ func (vdb *VersionedDB) ExecuteQuery(namespace, query string) (statedb.ResultsIt
 	//Get the querylimit from core.yaml
 	queryLimit := ledgerconfig.GetQueryLimit()
 
-	queryString, err := ApplyQueryWrapper(namespace, query, queryLimit, 0)
+	queryResult, err := vdb.queryDocuments(namespace, query, queryLimit, 0)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debugf("Exiting ExecuteQuery")
+	return newQueryScanner(queryResult, 0, 0), nil
+}
+
+// ExecuteQueryWithPagination implements method in VersionedDB interface
+// The bookmark carries the number of documents to skip in order to retrieve the next page.
+// Like the range queries, one more document than the page size is retrieved so as to find out
+// whether there are more results
+func (vdb *VersionedDB) ExecuteQueryWithPagination(namespace, query, bookmark string, pageSize int32) (statedb.QueryResultsIterator, error) {
+	if err := validatePageSize(pageSize); err != nil {
+		return nil, err
+	}
+	skip := 0
+	if bookmark != "" {
+		var err error
+		if skip, err = strconv.Atoi(bookmark); err != nil || skip < 0 {
+			return nil, fmt.Errorf("Invalid bookmark [%s]", bookmark)
+		}
+	}
+	queryResult, err := vdb.queryDocuments(namespace, query, int(pageSize)+1, skip)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debugf("Exiting ExecuteQueryWithPagination")
+	return newQueryScanner(queryResult, skip, pageSize), nil
+}
+
+func (vdb *VersionedDB) queryDocuments(namespace, query string, limit, skip int) ([]couchdb.QueryResult, error) {
+	queryString, err := ApplyQueryWrapper(namespace, query, limit, skip)
 	if err != nil {
 		logger.Debugf("Error calling ApplyQueryWrapper(): %s\n", err.Error())
 		return nil, err
@@ -237,55 +492,104 @@ func (vdb *VersionedDB) ExecuteQuery(namespace, query string) (statedb.ResultsIt
 		logger.Debugf("Error calling QueryDocuments(): %s\n", err.Error())
 		return nil, err
 	}
-	logger.Debugf("Exiting ExecuteQuery")
-	return newQueryScanner(*queryResult), nil
+	return *queryResult, nil
 }
 
-// ApplyUpdates implements method in VersionedDB interface
+func validatePageSize(pageSize int32) error {
+	if pageSize <= 0 {
+		return fmt.Errorf("Page size should be greater than zero, found [%d]", pageSize)
+	}
+	if queryLimit := ledgerconfig.GetQueryLimit(); int(pageSize) > queryLimit {
+		return fmt.Errorf("Page size [%d] exceeds the query limit [%d]", pageSize, queryLimit)
+	}
+	return nil
+}
+
+// GetFullScanIterator implements method in VersionedDB interface
+func (vdb *VersionedDB) GetFullScanIterator() (statedb.ResultsIterator, error) {
+	//Get the querylimit from core.yaml, used as the page size for retrieving the documents
+	queryLimit := ledgerconfig.GetQueryLimit()
+	return newFullScanner(vdb.db, queryLimit), nil
+}
+
+// ApplyUpdates implements method in VersionedDB interface.
+// All the updates are written in a single bulk request, using the revision numbers cached while
+// validating the block. The revision numbers of the keys that are not cached (e.g., during recovery)
+// are retrieved in a single bulk request as well. The cache is cleared once the updates are applied
 func (vdb *VersionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version.Height) error {
+	defer vdb.ClearCachedVersions()
 
 	namespaces := batch.GetUpdatedNamespaces()
+
+	//load the revision numbers of the keys that were not loaded while validating the block
+	var missingKeys []*statedb.CompositeKey
+	for _, ns := range namespaces {
+		for k := range batch.GetUpdates(ns) {
+			compositeKey := statedb.CompositeKey{Namespace: ns, Key: k}
+			if _, ok := vdb.getCachedRevision(compositeKey); !ok {
+				missingKeys = append(missingKeys, &compositeKey)
+			}
+		}
+	}
+	if err := vdb.LoadCommittedVersions(missingKeys); err != nil {
+		logger.Errorf("Error during Commit(): %s\n", err.Error())
+		return err
+	}
+
+	var batchUpdateDocs []*couchdb.CouchDoc
 	for _, ns := range namespaces {
 		updates := batch.GetUpdates(ns)
 		for k, vv := range updates {
 			compositeKey := constructCompositeKey(ns, k)
 			logger.Debugf("Channel [%s]: Applying key=[%#v]", vdb.dbName, compositeKey)
 
+			rev, _ := vdb.getCachedRevision(statedb.CompositeKey{Namespace: ns, Key: k})
+
 			//convert nils to deletes
 			if vv.Value == nil {
+				//nothing to delete if the document does not exist
+				if rev == "" {
+					continue
+				}
+				batchUpdateDocs = append(batchUpdateDocs, &couchdb.CouchDoc{JSONValue: createDeletedDocJSON(string(compositeKey), rev)})
+				continue
+			}
 
-				vdb.db.DeleteDoc(string(compositeKey), "")
-
-			} else {
-				couchDoc := &couchdb.CouchDoc{}
+			couchDoc := &couchdb.CouchDoc{}
 
-				//Check to see if the value is a valid JSON
-				//If this is not a valid JSON, then store as an attachment
-				if couchdb.IsJSON(string(vv.Value)) {
-					// Handle it as json
-					couchDoc.JSONValue = addVersionAndChainCodeID(vv.Value, ns, vv.Version)
-				} else { // if the data is not JSON, save as binary attachment in Couch
+			//Check to see if the value is a valid JSON
+			//If this is not a valid JSON, then store as an attachment
+			if couchdb.IsJSON(string(vv.Value)) {
+				// Handle it as json
+				couchDoc.JSONValue = createCouchdbDocJSON(string(compositeKey), rev, vv.Value, vv.Metadata, ns, vv.Version)
+			} else { // if the data is not JSON, save as binary attachment in Couch
 
-					attachment := &couchdb.Attachment{}
-					attachment.AttachmentBytes = vv.Value
-					attachment.ContentType = "application/octet-stream"
-					attachment.Name = binaryWrapper
-					attachments := append([]*couchdb.Attachment{}, attachment)
+				attachment := &couchdb.Attachment{}
+				attachment.AttachmentBytes = vv.Value
+				attachment.ContentType = "application/octet-stream"
+				attachment.Name = binaryWrapper
+				attachments := append([]*couchdb.Attachment{}, attachment)
 
-					couchDoc.Attachments = attachments
-					couchDoc.JSONValue = addVersionAndChainCodeID(nil, ns, vv.Version)
-				}
+				couchDoc.Attachments = attachments
+				couchDoc.JSONValue = createCouchdbDocJSON(string(compositeKey), rev, nil, vv.Metadata, ns, vv.Version)
+			}
+			batchUpdateDocs = append(batchUpdateDocs, couchDoc)
+		}
+	}
 
-				// SaveDoc using couchdb client and use attachment to persist the binary data
-				rev, err := vdb.db.SaveDoc(string(compositeKey), "", couchDoc)
-				if err != nil {
-					logger.Errorf("Error during Commit(): %s\n", err.Error())
-					return err
-				}
-				if rev != "" {
-					logger.Debugf("Saved document revision number: %s\n", rev)
-				}
+	if len(batchUpdateDocs) > 0 {
+		batchUpdateResp, err := vdb.db.BatchUpdateDocuments(batchUpdateDocs)
+		if err != nil {
+			logger.Errorf("Error during Commit(): %s\n", err.Error())
+			return err
+		}
+		for _, resp := range batchUpdateResp {
+			if !resp.Ok {
+				err = fmt.Errorf("Error committing key [%s] to state database: %s, reason: %s", resp.ID, resp.Error, resp.Reason)
+				logger.Errorf("Error during Commit(): %s\n", err.Error())
+				return err
 			}
+			logger.Debugf("Saved document [%s] with revision number: %s\n", resp.ID, resp.Rev)
 		}
 	}
 
@@ -299,15 +603,27 @@ func (vdb *VersionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version
 	return nil
 }
 
-//addVersionAndChainCodeID adds keys for version and chaincodeID to the JSON value
-func addVersionAndChainCodeID(value []byte, chaincodeID string, version *version.Height) []byte {
+//createCouchdbDocJSON adds keys for the document id, revision, version, metadata and chaincodeID to the JSON value.
+//The revision is omitted for a new document and the metadata is omitted if the key has none
+func createCouchdbDocJSON(id, rev string, value []byte, metadata []byte, chaincodeID string, version *version.Height) []byte {
 
 	//create a version mapping
 	jsonMap := map[string]interface{}{"version": fmt.Sprintf("%v:%v", version.BlockNum, version.TxNum)}
 
+	//add the id and the revision of the document
+	jsonMap[idField] = id
+	if rev != "" {
+		jsonMap[revField] = rev
+	}
+
 	//add the chaincodeID
 	jsonMap["chaincodeid"] = chaincodeID
 
+	//add the metadata, which json.Marshal encodes in base64
+	if len(metadata) > 0 {
+		jsonMap[metadataField] = metadata
+	}
+
 	//Add the wrapped data if the value is not null
 	if value != nil {
 
@@ -326,6 +642,13 @@ func addVersionAndChainCodeID(value []byte, chaincodeID string, version *version
 
 }
 
+//createDeletedDocJSON creates the JSON for deleting the given revision of a document in a bulk update
+func createDeletedDocJSON(id, rev string) []byte {
+	jsonMap := map[string]interface{}{idField: id, revField: rev, deletedField: true}
+	returnJSON, _ := json.Marshal(jsonMap)
+	return returnJSON
+}
+
 // Savepoint docid (key) for couchdb
 const savepointDocID = "statedb_savepoint"
 
@@ -420,21 +743,21 @@ func splitCompositeKey(compositeKey []byte) (string, string) {
 	return string(split[0]), string(split[1])
 }
 
+// kvScanner iterates over the results of a range query. A non-zero pageSize limits the number of results returned
 type kvScanner struct {
 	cursor    int
 	namespace string
 	results   []couchdb.QueryResult
+	pageSize  int32
 }
 
-func newKVScanner(namespace string, queryResults []couchdb.QueryResult) *kvScanner {
-	return &kvScanner{-1, namespace, queryResults}
+func newKVScanner(namespace string, queryResults []couchdb.QueryResult, pageSize int32) *kvScanner {
+	return &kvScanner{-1, namespace, queryResults, pageSize}
 }
 
 func (scanner *kvScanner) Next() (statedb.QueryResult, error) {
 
-	scanner.cursor++
-
-	if scanner.cursor >= len(scanner.results) {
+	if !advanceCursor(&scanner.cursor, len(scanner.results), scanner.pageSize) {
 		return nil, nil
 	}
 
@@ -443,31 +766,43 @@ func (scanner *kvScanner) Next() (statedb.QueryResult, error) {
 	_, key := splitCompositeKey([]byte(selectedKV.ID))
 
 	//remove the data wrapper and return the value and version
-	returnValue, returnVersion := removeDataWrapper(selectedKV.Value, selectedKV.Attachments)
+	returnValue, returnMetadata, returnVersion := removeDataWrapper(selectedKV.Value, selectedKV.Attachments)
 
 	return &statedb.VersionedKV{
 		CompositeKey:   statedb.CompositeKey{Namespace: scanner.namespace, Key: key},
-		VersionedValue: statedb.VersionedValue{Value: returnValue, Version: &returnVersion}}, nil
+		VersionedValue: statedb.VersionedValue{Value: returnValue, Metadata: returnMetadata, Version: &returnVersion}}, nil
 }
 
 func (scanner *kvScanner) Close() {
 	scanner = nil
 }
 
+// GetBookmarkAndClose implements method in QueryResultsIterator interface.
+// The bookmark is the key that follows the last key returned, if any
+func (scanner *kvScanner) GetBookmarkAndClose() string {
+	next := scanner.cursor + 1
+	if next >= len(scanner.results) {
+		return ""
+	}
+	_, key := splitCompositeKey([]byte(scanner.results[next].ID))
+	return key
+}
+
+// queryScanner iterates over the results of a rich query. A non-zero pageSize limits the number of results returned
 type queryScanner struct {
-	cursor  int
-	results []couchdb.QueryResult
+	cursor   int
+	results  []couchdb.QueryResult
+	skip     int
+	pageSize int32
 }
 
-func newQueryScanner(queryResults []couchdb.QueryResult) *queryScanner {
-	return &queryScanner{-1, queryResults}
+func newQueryScanner(queryResults []couchdb.QueryResult, skip int, pageSize int32) *queryScanner {
+	return &queryScanner{-1, queryResults, skip, pageSize}
 }
 
 func (scanner *queryScanner) Next() (statedb.QueryResult, error) {
 
-	scanner.cursor++
-
-	if scanner.cursor >= len(scanner.results) {
+	if !advanceCursor(&scanner.cursor, len(scanner.results), scanner.pageSize) {
 		return nil, nil
 	}
 
@@ -476,13 +811,84 @@ func (scanner *queryScanner) Next() (statedb.QueryResult, error) {
 	namespace, key := splitCompositeKey([]byte(selectedResultRecord.ID))
 
 	//remove the data wrapper and return the value and version
-	returnValue, returnVersion := removeDataWrapper(selectedResultRecord.Value, selectedResultRecord.Attachments)
+	returnValue, returnMetadata, returnVersion := removeDataWrapper(selectedResultRecord.Value, selectedResultRecord.Attachments)
 
 	return &statedb.VersionedKV{
 		CompositeKey:   statedb.CompositeKey{Namespace: namespace, Key: key},
-		VersionedValue: statedb.VersionedValue{Value: returnValue, Version: &returnVersion}}, nil
+		VersionedValue: statedb.VersionedValue{Value: returnValue, Metadata: returnMetadata, Version: &returnVersion}}, nil
 }
 
 func (scanner *queryScanner) Close() {
 	scanner = nil
 }
+
+// GetBookmarkAndClose implements method in QueryResultsIterator interface.
+// The bookmark is the number of documents to skip for retrieving the results that follow the last result returned, if any
+func (scanner *queryScanner) GetBookmarkAndClose() string {
+	next := scanner.cursor + 1
+	if next >= len(scanner.results) {
+		return ""
+	}
+	return strconv.Itoa(scanner.skip + next)
+}
+
+// advanceCursor moves the cursor to the next result, unless the results or the page (if pageSize is non-zero) are exhausted
+func advanceCursor(cursor *int, numResults int, pageSize int32) bool {
+	if *cursor+1 >= numResults || (pageSize > 0 && *cursor+1 >= int(pageSize)) {
+		return false
+	}
+	*cursor++
+	return true
+}
+
+// fullScanner retrieves all the documents in the database page by page.
+// The documents that do not represent a key-value (e.g., the savepoint document) are skipped
+type fullScanner struct {
+	db        *couchdb.CouchDatabase
+	pageSize  int
+	skip      int
+	cursor    int
+	results   []couchdb.QueryResult
+	exhausted bool
+}
+
+func newFullScanner(db *couchdb.CouchDatabase, pageSize int) *fullScanner {
+	return &fullScanner{db: db, pageSize: pageSize}
+}
+
+func (scanner *fullScanner) Next() (statedb.QueryResult, error) {
+	for {
+		if scanner.cursor >= len(scanner.results) {
+			if scanner.exhausted {
+				return nil, nil
+			}
+			queryResult, err := scanner.db.ReadDocRange("", "", scanner.pageSize, scanner.skip)
+			if err != nil {
+				logger.Debugf("Error calling ReadDocRange(): %s\n", err.Error())
+				return nil, err
+			}
+			scanner.results = *queryResult
+			scanner.cursor = 0
+			scanner.skip += len(scanner.results)
+			scanner.exhausted = len(scanner.results) < scanner.pageSize
+			if len(scanner.results) == 0 {
+				return nil, nil
+			}
+		}
+		selectedKV := scanner.results[scanner.cursor]
+		scanner.cursor++
+		if !bytes.Contains([]byte(selectedKV.ID), compositeKeySep) {
+			continue
+		}
+		namespace, key := splitCompositeKey([]byte(selectedKV.ID))
+		//remove the data wrapper and return the value and version
+		returnValue, returnMetadata, returnVersion := removeDataWrapper(selectedKV.Value, selectedKV.Attachments)
+		return &statedb.VersionedKV{
+			CompositeKey:   statedb.CompositeKey{Namespace: namespace, Key: key},
+			VersionedValue: statedb.VersionedValue{Value: returnValue, Metadata: returnMetadata, Version: &returnVersion}}, nil
+	}
+}
+
+func (scanner *fullScanner) Close() {
+	scanner.results = nil
+}
@@ -18,6 +18,7 @@ package statecouchdb
 
 import (
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -57,6 +58,40 @@ func TestMain(m *testing.M) {
 	os.Exit(result)
 }
 
+// conformanceDBProvider drops the couch databases of the handles it returns, both
+// before they are first used and once the conformance test is complete
+type conformanceDBProvider struct {
+	statedb.VersionedDBProvider
+	dbNames []string
+}
+
+func (p *conformanceDBProvider) GetDBHandle(dbName string) (statedb.VersionedDB, error) {
+	for _, name := range p.dbNames {
+		if name == dbName {
+			return p.VersionedDBProvider.GetDBHandle(dbName)
+		}
+	}
+	cleanupDB(strings.ToLower(dbName))
+	p.dbNames = append(p.dbNames, dbName)
+	return p.VersionedDBProvider.GetDBHandle(dbName)
+}
+
+func (p *conformanceDBProvider) cleanup() {
+	for _, dbName := range p.dbNames {
+		cleanupDB(strings.ToLower(dbName))
+	}
+	p.VersionedDBProvider.Close()
+}
+
+func TestConformance(t *testing.T) {
+	if ledgerconfig.IsCouchDBEnabled() == true {
+		commontests.RunConformanceTests(t, func(t *testing.T) (statedb.VersionedDBProvider, func()) {
+			dbProvider := &conformanceDBProvider{VersionedDBProvider: NewTestVDBEnv(t).DBProvider}
+			return dbProvider, dbProvider.cleanup
+		}, true)
+	}
+}
+
 func TestBasicRW(t *testing.T) {
 	if ledgerconfig.IsCouchDBEnabled() == true {
 
@@ -81,6 +116,15 @@ func TestMultiDBBasicRW(t *testing.T) {
 	}
 }
 
+func TestValueAndMetadataWrites(t *testing.T) {
+	if ledgerconfig.IsCouchDBEnabled() == true {
+		env := NewTestVDBEnv(t)
+		env.Cleanup("testvalueandmetadata")
+		defer env.Cleanup("testvalueandmetadata")
+		commontests.TestValueAndMetadataWrites(t, env.DBProvider)
+	}
+}
+
 func TestDeletes(t *testing.T) {
 	if ledgerconfig.IsCouchDBEnabled() == true {
 		env := NewTestVDBEnv(t)
@@ -101,6 +145,17 @@ func TestIterator(t *testing.T) {
 	}
 }
 
+func TestPaginatedRangeQuery(t *testing.T) {
+	if ledgerconfig.IsCouchDBEnabled() == true {
+
+		env := NewTestVDBEnv(t)
+		env.Cleanup("testpaginatedrangequery")
+		defer env.Cleanup("testpaginatedrangequery")
+		commontests.TestPaginatedRangeQuery(t, env.DBProvider)
+
+	}
+}
+
 func TestEncodeDecodeValueAndVersion(t *testing.T) {
 	testValueAndVersionEncoding(t, []byte("value1"), version.NewHeight(1, 2))
 	testValueAndVersionEncoding(t, []byte{}, version.NewHeight(50, 50))
@@ -142,3 +197,124 @@ func TestQuery(t *testing.T) {
 
 	}
 }
+
+func TestPaginatedQuery(t *testing.T) {
+	if ledgerconfig.IsCouchDBEnabled() == true {
+
+		env := NewTestVDBEnv(t)
+		env.Cleanup("testpaginatedquery")
+		defer env.Cleanup("testpaginatedquery")
+		commontests.TestPaginatedQuery(t, env.DBProvider)
+
+	}
+}
+
+func TestProcessIndexesForChaincodeDeploy(t *testing.T) {
+	if ledgerconfig.IsCouchDBEnabled() == true {
+
+		env := NewTestVDBEnv(t)
+		env.Cleanup("testprocessindexes")
+		defer env.Cleanup("testprocessindexes")
+
+		db, err := env.DBProvider.GetDBHandle("testprocessindexes")
+		testutil.AssertNoError(t, err, "")
+		indexCapable, ok := db.(statedb.IndexCapable)
+		testutil.AssertEquals(t, ok, true)
+		testutil.AssertEquals(t, indexCapable.GetDBType(), "couchdb")
+
+		indexFiles := map[string][]byte{
+			"indexOwner.json": []byte(`{"index":{"fields":["owner"]},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}`),
+			"indexSize.json":  []byte(`{"index":{"fields":[{"size":"desc"}]},"ddoc":"indexSizeDoc","name":"indexSize","type":"json"}`),
+		}
+		err = indexCapable.ProcessIndexesForChaincodeDeploy("ns1", indexFiles)
+		testutil.AssertNoError(t, err, "")
+
+		indexes, err := db.(*VersionedDB).db.ListIndex()
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, len(indexes), 2)
+		for _, index := range indexes {
+			testutil.AssertContains(t, []string{"indexOwnerDoc", "indexSizeDoc"}, index.DesignDocument)
+		}
+
+		//an invalid index definition should be reported
+		err = indexCapable.ProcessIndexesForChaincodeDeploy("ns1", map[string][]byte{"bad.json": []byte(`{"name":"bad"}`)})
+		testutil.AssertError(t, err, "Expected an error for an invalid index definition")
+	}
+}
+
+func TestLoadCommittedVersions(t *testing.T) {
+	if ledgerconfig.IsCouchDBEnabled() == true {
+
+		env := NewTestVDBEnv(t)
+		env.Cleanup("testloadcommittedversions")
+		defer env.Cleanup("testloadcommittedversions")
+
+		db, err := env.DBProvider.GetDBHandle("testloadcommittedversions")
+		testutil.AssertNoError(t, err, "")
+		bulkOptimizable, ok := db.(statedb.BulkOptimizable)
+		testutil.AssertEquals(t, ok, true)
+
+		batch := statedb.NewUpdateBatch()
+		batch.Put("ns1", "key1", []byte(`{"asset_name":"marble1"}`), version.NewHeight(1, 1))
+		batch.Put("ns1", "key2", []byte("binary value"), version.NewHeight(1, 2))
+		batch.PutValAndMetadata("ns1", "key3", []byte("value3"), []byte("metadata3"), version.NewHeight(1, 3))
+		testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 3)), "")
+
+		keys := []*statedb.CompositeKey{
+			{Namespace: "ns1", Key: "key1"}, {Namespace: "ns1", Key: "key2"},
+			{Namespace: "ns1", Key: "key3"}, {Namespace: "ns1", Key: "key4"},
+		}
+		testutil.AssertNoError(t, bulkOptimizable.LoadCommittedVersions(keys), "")
+
+		ver, ok := bulkOptimizable.GetCachedVersion("ns1", "key1")
+		testutil.AssertEquals(t, ok, true)
+		testutil.AssertEquals(t, ver, version.NewHeight(1, 1))
+		ver, ok = bulkOptimizable.GetCachedVersion("ns1", "key2")
+		testutil.AssertEquals(t, ok, true)
+		testutil.AssertEquals(t, ver, version.NewHeight(1, 2))
+
+		// a key that does not exist is cached with a nil version
+		ver, ok = bulkOptimizable.GetCachedVersion("ns1", "key4")
+		testutil.AssertEquals(t, ok, true)
+		testutil.AssertNil(t, ver)
+
+		// the metadata is loaded along with the versions
+		metadata, ok := bulkOptimizable.GetCachedMetadata("ns1", "key3")
+		testutil.AssertEquals(t, ok, true)
+		testutil.AssertEquals(t, metadata, []byte("metadata3"))
+		metadata, ok = bulkOptimizable.GetCachedMetadata("ns1", "key1")
+		testutil.AssertEquals(t, ok, true)
+		testutil.AssertNil(t, metadata)
+		_, ok = bulkOptimizable.GetCachedMetadata("ns2", "key1")
+		testutil.AssertEquals(t, ok, false)
+
+		// a key that was not loaded is not in the cache
+		_, ok = bulkOptimizable.GetCachedVersion("ns2", "key1")
+		testutil.AssertEquals(t, ok, false)
+
+		// update and delete using the cached revisions; the cache is cleared after the commit
+		batch = statedb.NewUpdateBatch()
+		batch.Put("ns1", "key1", []byte(`{"asset_name":"marble1","size":12345678901234567}`), version.NewHeight(2, 1))
+		batch.Delete("ns1", "key2", version.NewHeight(2, 2))
+		batch.Delete("ns1", "key4", version.NewHeight(2, 3))
+		testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 3)), "")
+		_, ok = bulkOptimizable.GetCachedVersion("ns1", "key1")
+		testutil.AssertEquals(t, ok, false)
+
+		vv, err := db.GetState("ns1", "key1")
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, vv.Value, []byte(`{"asset_name":"marble1","size":12345678901234567}`))
+		testutil.AssertEquals(t, vv.Version, version.NewHeight(2, 1))
+		vv, err = db.GetState("ns1", "key2")
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertNil(t, vv)
+
+		// updating a key that was not loaded retrieves its revision at commit
+		batch = statedb.NewUpdateBatch()
+		batch.Put("ns1", "key3", []byte("value3_new"), version.NewHeight(3, 1))
+		testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(3, 1)), "")
+		ver, err = db.GetVersion("ns1", "key3")
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, ver, version.NewHeight(3, 1))
+	}
+}
@@ -35,6 +35,8 @@ type VersionedDBProvider interface {
 type VersionedDB interface {
 	// GetState gets the value for given namespace and key. For a chaincode, the namespace corresponds to the chaincodeId
 	GetState(namespace string, key string) (*VersionedValue, error)
+	// GetVersion gets the version for given namespace and key. For a chaincode, the namespace corresponds to the chaincodeId
+	GetVersion(namespace string, key string) (*version.Height, error)
 	// GetStateMultipleKeys gets the values for multiple keys in a single call
 	GetStateMultipleKeys(namespace string, keys []string) ([]*VersionedValue, error)
 	// GetStateRangeScanIterator returns an iterator that contains all the key-values between given key ranges.
@@ -42,8 +44,21 @@ type VersionedDB interface {
 	// endKey is exclusive
 	// The returned ResultsIterator contains results of type *VersionedKV
 	GetStateRangeScanIterator(namespace string, startKey string, endKey string) (ResultsIterator, error)
+	// GetStateRangeScanIteratorWithPagination returns an iterator that contains at most pageSize key-values between given key ranges.
+	// startKey is inclusive
+	// endKey is exclusive
+	// The bookmark returned by the iterator is the startKey for retrieving the next page
+	// The returned QueryResultsIterator contains results of type *VersionedKV
+	GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string, pageSize int32) (QueryResultsIterator, error)
 	// ExecuteQuery executes the given query and returns an iterator that contains results of type *VersionedKV.
 	ExecuteQuery(namespace, query string) (ResultsIterator, error)
+	// ExecuteQueryWithPagination executes the given query and returns an iterator that contains at most pageSize results of type *VersionedKV.
+	// The bookmark is expected to be either empty (for the first page) or the one returned by the iterator for the previous page
+	ExecuteQueryWithPagination(namespace, query, bookmark string, pageSize int32) (QueryResultsIterator, error)
+	// GetFullScanIterator returns an iterator that contains all the key-values across all the namespaces.
+	// This is intended for exporting the complete state, for instance, while taking a snapshot of the ledger.
+	// The returned ResultsIterator contains results of type *VersionedKV
+	GetFullScanIterator() (ResultsIterator, error)
 	// ApplyUpdates applies the batch to the underlying db.
 	// height is the height of the highest transaction in the Batch that
 	// a state db implementation is expected to ues as a save point
@@ -57,16 +72,46 @@ type VersionedDB interface {
 	Close()
 }
 
+// BulkOptimizable is implemented by the VersionedDB implementations that are capable of batch operations,
+// for which reading the committed versions of the keys one at a time is expensive (e.g., CouchDB).
+// The versions and the metadata of all the keys accessed by a block are loaded in bulk before validating
+// the block and are kept in a cache until the block is committed
+type BulkOptimizable interface {
+	// LoadCommittedVersions loads the committed versions of the given keys into the cache in a single call
+	LoadCommittedVersions(keys []*CompositeKey) error
+	// GetCachedVersion returns the version of the key from the cache. The boolean indicates whether the key
+	// is present in the cache, a nil version for a cached key means that the key does not exist in the db
+	GetCachedVersion(namespace, key string) (*version.Height, bool)
+	// GetCachedMetadata returns the metadata of the key from the cache. The boolean indicates whether the key
+	// is present in the cache, a nil metadata for a cached key means that the key has no metadata or does not exist
+	GetCachedMetadata(namespace, key string) ([]byte, bool)
+	// ClearCachedVersions clears the cache
+	ClearCachedVersions()
+}
+
+// IndexCapable is implemented by the VersionedDB implementations that support indexes on the values
+// of a namespace. The index definitions are packaged with a chaincode under the directory
+// "META-INF/statedb/<db type>/indexes" and are created when the chaincode is deployed or upgraded
+type IndexCapable interface {
+	// GetDBType returns the type of the db, as used in the path of the index definitions in a chaincode package
+	GetDBType() string
+	// ProcessIndexesForChaincodeDeploy creates the given indexes for the namespace.
+	// indexFiles maps the file name of an index definition to its content
+	ProcessIndexesForChaincodeDeploy(namespace string, indexFiles map[string][]byte) error
+}
+
 // CompositeKey encloses Namespace and Key components
 type CompositeKey struct {
 	Namespace string
 	Key       string
 }
 
-// VersionedValue encloses value and corresponding version
+// VersionedValue encloses value and corresponding version.
+// Metadata holds the serialized metadata a chaincode associated with the key, if any
 type VersionedValue struct {
-	Value   []byte
-	Version *version.Height
+	Value    []byte
+	Metadata []byte
+	Version  *version.Height
 }
 
 // VersionedKV encloses key and corresponding VersionedValue
@@ -81,6 +126,14 @@ type ResultsIterator interface {
 	Close()
 }
 
+// QueryResultsIterator hepls in iterating over a single page of query results
+type QueryResultsIterator interface {
+	ResultsIterator
+	// GetBookmarkAndClose returns the bookmark for retrieving the next page and releases the iterator.
+	// An empty bookmark indicates that there are no more results
+	GetBookmarkAndClose() string
+}
+
 // QueryResult - a general interface for supporting different types of query results. Actual types differ for different queries
 type QueryResult interface{}
 
@@ -117,17 +170,22 @@ func (batch *UpdateBatch) Get(ns string, key string) *VersionedValue {
 
 // Put adds a VersionedKV
 func (batch *UpdateBatch) Put(ns string, key string, value []byte, version *version.Height) {
+	batch.PutValAndMetadata(ns, key, value, nil, version)
+}
+
+// PutValAndMetadata adds a VersionedKV along with the metadata associated with the key
+func (batch *UpdateBatch) PutValAndMetadata(ns string, key string, value []byte, metadata []byte, version *version.Height) {
 	if value == nil {
 		panic("Nil value not allowed")
 	}
 	nsUpdates := batch.getOrCreateNsUpdates(ns)
-	nsUpdates.m[key] = &VersionedValue{value, version}
+	nsUpdates.m[key] = &VersionedValue{Value: value, Metadata: metadata, Version: version}
 }
 
 // Delete deletes a Key and associated value
 func (batch *UpdateBatch) Delete(ns string, key string, version *version.Height) {
 	nsUpdates := batch.getOrCreateNsUpdates(ns)
-	nsUpdates.m[key] = &VersionedValue{nil, version}
+	nsUpdates.m[key] = &VersionedValue{Value: nil, Version: version}
 }
 
 // Exists checks whether the given key exists in the batch
@@ -217,7 +275,7 @@ func (itr *nsIterator) Next() (QueryResult, error) {
 	key := itr.sortedKeys[itr.nextIndex]
 	vv := itr.nsUpdates.m[key]
 	itr.nextIndex++
-	return &VersionedKV{CompositeKey{itr.ns, key}, VersionedValue{vv.Value, vv.Version}}, nil
+	return &VersionedKV{CompositeKey{itr.ns, key}, VersionedValue{vv.Value, vv.Metadata, vv.Version}}, nil
 }
 
 // Close implements the method from QueryResult interface
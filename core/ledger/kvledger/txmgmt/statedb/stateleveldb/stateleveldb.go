@@ -19,6 +19,7 @@ package stateleveldb
 import (
 	"bytes"
 	"errors"
+	"fmt"
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
@@ -30,6 +31,15 @@ import (
 
 var logger = flogging.MustGetLogger("stateleveldb")
 
+// ProviderName is the name under which the leveldb backed VersionedDBProvider is registered
+const ProviderName = "goleveldb"
+
+func init() {
+	statedb.RegisterVersionedDBProvider(ProviderName, func() (statedb.VersionedDBProvider, error) {
+		return NewVersionedDBProvider(), nil
+	})
+}
+
 var compositeKeySep = []byte{0x00}
 var lastKeyIndicator = byte(0x01)
 var savePointKey = []byte{0x00}
@@ -90,8 +100,20 @@ func (vdb *versionedDB) GetState(namespace string, key string) (*statedb.Version
 	if dbVal == nil {
 		return nil, nil
 	}
-	val, ver := statedb.DecodeValue(dbVal)
-	return &statedb.VersionedValue{Value: val, Version: ver}, nil
+	val, metadata, ver := statedb.DecodeValueAndMetadata(dbVal)
+	return &statedb.VersionedValue{Value: val, Metadata: metadata, Version: ver}, nil
+}
+
+// GetVersion implements method in VersionedDB interface
+func (vdb *versionedDB) GetVersion(namespace string, key string) (*version.Height, error) {
+	versionedValue, err := vdb.GetState(namespace, key)
+	if err != nil {
+		return nil, err
+	}
+	if versionedValue == nil {
+		return nil, nil
+	}
+	return versionedValue.Version, nil
 }
 
 // GetStateMultipleKeys implements method in VersionedDB interface
@@ -111,13 +133,25 @@ func (vdb *versionedDB) GetStateMultipleKeys(namespace string, keys []string) ([
 // startKey is inclusive
 // endKey is exclusive
 func (vdb *versionedDB) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (statedb.ResultsIterator, error) {
+	return vdb.getStateRangeScanIterator(namespace, startKey, endKey, 0), nil
+}
+
+// GetStateRangeScanIteratorWithPagination implements method in VersionedDB interface
+func (vdb *versionedDB) GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string, pageSize int32) (statedb.QueryResultsIterator, error) {
+	if pageSize <= 0 {
+		return nil, fmt.Errorf("Page size should be greater than zero, found [%d]", pageSize)
+	}
+	return vdb.getStateRangeScanIterator(namespace, startKey, endKey, pageSize), nil
+}
+
+func (vdb *versionedDB) getStateRangeScanIterator(namespace string, startKey string, endKey string, pageSize int32) *kvScanner {
 	compositeStartKey := constructCompositeKey(namespace, startKey)
 	compositeEndKey := constructCompositeKey(namespace, endKey)
 	if endKey == "" {
 		compositeEndKey[len(compositeEndKey)-1] = lastKeyIndicator
 	}
 	dbItr := vdb.db.GetIterator(compositeStartKey, compositeEndKey)
-	return newKVScanner(namespace, dbItr), nil
+	return newKVScanner(namespace, dbItr, pageSize)
 }
 
 // ExecuteQuery implements method in VersionedDB interface
@@ -125,6 +159,17 @@ func (vdb *versionedDB) ExecuteQuery(namespace, query string) (statedb.ResultsIt
 	return nil, errors.New("ExecuteQuery not supported for leveldb")
 }
 
+// ExecuteQueryWithPagination implements method in VersionedDB interface
+func (vdb *versionedDB) ExecuteQueryWithPagination(namespace, query, bookmark string, pageSize int32) (statedb.QueryResultsIterator, error) {
+	return nil, errors.New("ExecuteQuery not supported for leveldb")
+}
+
+// GetFullScanIterator implements method in VersionedDB interface
+func (vdb *versionedDB) GetFullScanIterator() (statedb.ResultsIterator, error) {
+	dbItr := vdb.db.GetIterator(nil, nil)
+	return &fullScanner{dbItr}, nil
+}
+
 // ApplyUpdates implements method in VersionedDB interface
 func (vdb *versionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version.Height) error {
 	dbBatch := leveldbhelper.NewUpdateBatch()
@@ -138,7 +183,7 @@ func (vdb *versionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version
 			if vv.Value == nil {
 				dbBatch.Delete(compositeKey)
 			} else {
-				dbBatch.Put(compositeKey, statedb.EncodeValue(vv.Value, vv.Version))
+				dbBatch.Put(compositeKey, statedb.EncodeValueAndMetadata(vv.Value, vv.Metadata, vv.Version))
 			}
 		}
 	}
@@ -171,30 +216,74 @@ func splitCompositeKey(compositeKey []byte) (string, string) {
 	return string(split[0]), string(split[1])
 }
 
+// kvScanner iterates over the keys in a range. A non-zero pageSize limits the number of results returned
 type kvScanner struct {
-	namespace string
-	dbItr     iterator.Iterator
+	namespace       string
+	dbItr           iterator.Iterator
+	pageSize        int32
+	numRecsReturned int32
 }
 
-func newKVScanner(namespace string, dbItr iterator.Iterator) *kvScanner {
-	return &kvScanner{namespace, dbItr}
+func newKVScanner(namespace string, dbItr iterator.Iterator, pageSize int32) *kvScanner {
+	return &kvScanner{namespace, dbItr, pageSize, 0}
 }
 
 func (scanner *kvScanner) Next() (statedb.QueryResult, error) {
+	if scanner.pageSize > 0 && scanner.numRecsReturned >= scanner.pageSize {
+		return nil, nil
+	}
 	if !scanner.dbItr.Next() {
 		return nil, nil
 	}
+	scanner.numRecsReturned++
 	dbKey := scanner.dbItr.Key()
 	dbVal := scanner.dbItr.Value()
 	dbValCopy := make([]byte, len(dbVal))
 	copy(dbValCopy, dbVal)
 	_, key := splitCompositeKey(dbKey)
-	value, version := statedb.DecodeValue(dbValCopy)
+	value, metadata, version := statedb.DecodeValueAndMetadata(dbValCopy)
 	return &statedb.VersionedKV{
 		CompositeKey:   statedb.CompositeKey{Namespace: scanner.namespace, Key: key},
-		VersionedValue: statedb.VersionedValue{Value: value, Version: version}}, nil
+		VersionedValue: statedb.VersionedValue{Value: value, Metadata: metadata, Version: version}}, nil
 }
 
 func (scanner *kvScanner) Close() {
 	scanner.dbItr.Release()
 }
+
+// GetBookmarkAndClose implements method in QueryResultsIterator interface.
+// The bookmark is the key that follows the last key returned, if any
+func (scanner *kvScanner) GetBookmarkAndClose() string {
+	defer scanner.Close()
+	if !scanner.dbItr.Next() {
+		return ""
+	}
+	_, key := splitCompositeKey(scanner.dbItr.Key())
+	return key
+}
+
+type fullScanner struct {
+	dbItr iterator.Iterator
+}
+
+func (scanner *fullScanner) Next() (statedb.QueryResult, error) {
+	for scanner.dbItr.Next() {
+		dbKey := scanner.dbItr.Key()
+		if bytes.Equal(dbKey, savePointKey) {
+			continue
+		}
+		dbVal := scanner.dbItr.Value()
+		dbValCopy := make([]byte, len(dbVal))
+		copy(dbValCopy, dbVal)
+		namespace, key := splitCompositeKey(dbKey)
+		value, metadata, version := statedb.DecodeValueAndMetadata(dbValCopy)
+		return &statedb.VersionedKV{
+			CompositeKey:   statedb.CompositeKey{Namespace: namespace, Key: key},
+			VersionedValue: statedb.VersionedValue{Value: value, Metadata: metadata, Version: version}}, nil
+	}
+	return nil, nil
+}
+
+func (scanner *fullScanner) Close() {
+	scanner.dbItr.Release()
+}
@@ -44,6 +44,12 @@ func TestMultiDBBasicRW(t *testing.T) {
 	commontests.TestMultiDBBasicRW(t, env.DBProvider)
 }
 
+func TestValueAndMetadataWrites(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestValueAndMetadataWrites(t, env.DBProvider)
+}
+
 func TestDeletes(t *testing.T) {
 	env := NewTestVDBEnv(t)
 	defer env.Cleanup()
@@ -56,6 +62,28 @@ func TestIterator(t *testing.T) {
 	commontests.TestIterator(t, env.DBProvider)
 }
 
+func TestPaginatedRangeQuery(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestPaginatedRangeQuery(t, env.DBProvider)
+}
+
+func TestConformance(t *testing.T) {
+	commontests.RunConformanceTests(t, func(t *testing.T) (statedb.VersionedDBProvider, func()) {
+		env := NewTestVDBEnv(t)
+		return env.DBProvider, env.Cleanup
+	}, false)
+}
+
+func TestRegisteredProvider(t *testing.T) {
+	testutil.AssertContains(t, statedb.RegisteredVersionedDBProviders(), ProviderName)
+	dbProvider, err := statedb.NewVersionedDBProvider(ProviderName)
+	testutil.AssertNoError(t, err, "")
+	defer dbProvider.Close()
+	_, ok := dbProvider.(*VersionedDBProvider)
+	testutil.AssertEquals(t, ok, true)
+}
+
 func TestEncodeDecodeValueAndVersion(t *testing.T) {
 	testValueAndVersionEncodeing(t, []byte("value1"), version.NewHeight(1, 2))
 	testValueAndVersionEncodeing(t, []byte{}, version.NewHeight(50, 50))
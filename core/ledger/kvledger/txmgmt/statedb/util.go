@@ -16,20 +16,86 @@ limitations under the License.
 
 package statedb
 
-import "github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+)
+
+// The private data of a collection and the hashes of the private data are maintained in the
+// state db in separate namespaces derived from the namespace of the chaincode and the name of the collection
+const (
+	collectionNsSep    = "$$"
+	pvtDataNsPrefix    = "p"
+	hashedDataNsPrefix = "h"
+)
+
+// metadataFormatMarker starts the encoding of a value that carries metadata. The encoding of a value without
+// metadata starts with the length of the block number in the version, which is at most 8, so the two never collide
+const metadataFormatMarker = byte(0xff)
 
 //EncodeValue appends the value to the version, allows storage of version and value in binary form
 func EncodeValue(value []byte, version *version.Height) []byte {
-	encodedValue := version.ToBytes()
+	return EncodeValueAndMetadata(value, nil, version)
+}
+
+//DecodeValue separates the version and value from a binary value
+func DecodeValue(encodedValue []byte) ([]byte, *version.Height) {
+	value, _, version := DecodeValueAndMetadata(encodedValue)
+	return value, version
+}
+
+//EncodeValueAndMetadata encodes the version, the metadata and the value in binary form.
+//A value without metadata is encoded exactly as by EncodeValue in the earlier releases
+func EncodeValueAndMetadata(value []byte, metadata []byte, version *version.Height) []byte {
+	var encodedValue []byte
+	if len(metadata) > 0 {
+		encodedValue = append(encodedValue, metadataFormatMarker)
+	}
+	encodedValue = append(encodedValue, version.ToBytes()...)
+	if len(metadata) > 0 {
+		encodedValue = append(encodedValue, proto.EncodeVarint(uint64(len(metadata)))...)
+		encodedValue = append(encodedValue, metadata...)
+	}
 	if value != nil {
 		encodedValue = append(encodedValue, value...)
 	}
 	return encodedValue
 }
 
-//DecodeValue separates the version and value from a binary value
-func DecodeValue(encodedValue []byte) ([]byte, *version.Height) {
+//DecodeValueAndMetadata separates the version, the metadata and the value from a binary value
+func DecodeValueAndMetadata(encodedValue []byte) ([]byte, []byte, *version.Height) {
+	if len(encodedValue) == 0 || encodedValue[0] != metadataFormatMarker {
+		version, n := version.NewHeightFromBytes(encodedValue)
+		return encodedValue[n:], nil, version
+	}
+	encodedValue = encodedValue[1:]
 	version, n := version.NewHeightFromBytes(encodedValue)
-	value := encodedValue[n:]
-	return value, version
+	encodedValue = encodedValue[n:]
+	metadataLen, n := proto.DecodeVarint(encodedValue)
+	encodedValue = encodedValue[n:]
+	return encodedValue[metadataLen:], encodedValue[:metadataLen], version
+}
+
+//DerivePvtDataNs returns the namespace that holds the private data of a collection of a chaincode
+func DerivePvtDataNs(namespace, collection string) string {
+	return namespace + collectionNsSep + pvtDataNsPrefix + collection
+}
+
+//DeriveHashedDataNs returns the namespace that holds the hashes of the private data of a collection of a chaincode
+func DeriveHashedDataNs(namespace, collection string) string {
+	return namespace + collectionNsSep + hashedDataNsPrefix + collection
+}
+
+//IsPvtDataNs tells whether the namespace holds the private data of a collection
+func IsPvtDataNs(namespace string) bool {
+	i := strings.Index(namespace, collectionNsSep)
+	return i >= 0 && strings.HasPrefix(namespace[i+len(collectionNsSep):], pvtDataNsPrefix)
+}
+
+//EncodeHashedKey encodes the hash of a key of private data as the key in the hashed data namespace
+func EncodeHashedKey(keyHash []byte) string {
+	return hex.EncodeToString(keyHash)
 }
@@ -52,3 +52,38 @@ func TestEncodeDecodeJSON(t *testing.T) {
 	testutil.AssertEquals(t, decodedVersion, version2)
 
 }
+
+// TestEncodeDecodeValueAndMetadata tests encoding and decoding a value along with its metadata
+func TestEncodeDecodeValueAndMetadata(t *testing.T) {
+	value := []byte("value1")
+	metadata := []byte("metadata1")
+	ver := version.NewHeight(1, 2)
+
+	decodedValue, decodedMetadata, decodedVersion := DecodeValueAndMetadata(EncodeValueAndMetadata(value, metadata, ver))
+	testutil.AssertEquals(t, decodedValue, value)
+	testutil.AssertEquals(t, decodedMetadata, metadata)
+	testutil.AssertEquals(t, decodedVersion, ver)
+
+	// a value without metadata is encoded in the format used before metadata was introduced
+	testutil.AssertEquals(t, EncodeValueAndMetadata(value, nil, ver), append(ver.ToBytes(), value...))
+	decodedValue, decodedMetadata, decodedVersion = DecodeValueAndMetadata(EncodeValue(value, ver))
+	testutil.AssertEquals(t, decodedValue, value)
+	testutil.AssertNil(t, decodedMetadata)
+	testutil.AssertEquals(t, decodedVersion, ver)
+
+	// a delete marker carries metadata but no value
+	decodedValue, decodedMetadata, _ = DecodeValueAndMetadata(EncodeValueAndMetadata(nil, metadata, ver))
+	testutil.AssertEquals(t, len(decodedValue), 0)
+	testutil.AssertEquals(t, decodedMetadata, metadata)
+}
+
+func TestCollectionNamespaces(t *testing.T) {
+	pvtNs := DerivePvtDataNs("ns1", "coll1")
+	hashedNs := DeriveHashedDataNs("ns1", "coll1")
+	testutil.AssertNotEquals(t, pvtNs, hashedNs)
+	testutil.AssertNotEquals(t, pvtNs, "ns1")
+	testutil.AssertEquals(t, IsPvtDataNs(pvtNs), true)
+	testutil.AssertEquals(t, IsPvtDataNs(hashedNs), false)
+	testutil.AssertEquals(t, IsPvtDataNs("ns1"), false)
+	testutil.AssertEquals(t, EncodeHashedKey([]byte{0x0a, 0xff}), "0aff")
+}
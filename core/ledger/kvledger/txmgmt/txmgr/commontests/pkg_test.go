@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statecouchdb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateleveldb"
@@ -28,6 +29,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr/lockbasedtxmgr"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	"github.com/spf13/viper"
 )
 
@@ -68,7 +70,7 @@ func (env *levelDBLockBasedEnv) init(t *testing.T, testLedgerID string) {
 	testDB, err := testDBEnv.DBProvider.GetDBHandle(testLedgerID)
 	testutil.AssertNoError(t, err, "")
 
-	txMgr := lockbasedtxmgr.NewLockBasedTxMgr(testDB)
+	txMgr := lockbasedtxmgr.NewLockBasedTxMgr(testDB, nil)
 	env.testLedgerID = testLedgerID
 	env.testDBEnv = testDBEnv
 	env.testDB = testDB
@@ -118,7 +120,7 @@ func (env *couchDBLockBasedEnv) init(t *testing.T, testLedgerID string) {
 	testDB, err := testDBEnv.DBProvider.GetDBHandle(testLedgerID)
 	testutil.AssertNoError(t, err, "")
 
-	txMgr := lockbasedtxmgr.NewLockBasedTxMgr(testDB)
+	txMgr := lockbasedtxmgr.NewLockBasedTxMgr(testDB, nil)
 	env.testLedgerID = testLedgerID
 	env.testDBEnv = testDBEnv
 	env.testDB = testDB
@@ -167,6 +169,17 @@ func (h *txMgrTestHelper) validateAndCommitRWSet(txRWSet []byte) {
 	testutil.AssertNoError(h.t, err, "")
 }
 
+func (h *txMgrTestHelper) validateAndCommitRWSetWithPvtData(txRWSet []byte, txPvtRWSet *rwset.TxPvtReadWriteSet) {
+	block := h.bg.NextBlock([][]byte{txRWSet})
+	pvtData := map[uint64]*ledger.TxPvtData{0: {SeqInBlock: 0, WriteSet: txPvtRWSet}}
+	err := h.txMgr.ValidateAndPrepareWithPvtData(&ledger.BlockAndPvtData{Block: block, BlockPvtData: pvtData}, true)
+	testutil.AssertNoError(h.t, err, "")
+	txsFltr := util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	testutil.AssertEquals(h.t, txsFltr.IsValid(0), true)
+	err = h.txMgr.Commit()
+	testutil.AssertNoError(h.t, err, "")
+}
+
 func (h *txMgrTestHelper) checkRWsetInvalid(txRWSet []byte) {
 	block := h.bg.NextBlock([][]byte{txRWSet})
 	err := h.txMgr.ValidateAndPrepare(block, true)
@@ -17,6 +17,7 @@ limitations under the License.
 package commontests
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"testing"
@@ -24,6 +25,7 @@ import (
 	"os"
 
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	ledgertestutil "github.com/hyperledger/fabric/core/ledger/testutil"
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
@@ -114,6 +116,122 @@ func testTxSimulatorWithExistingData(t *testing.T, env testEnv) {
 	testutil.AssertEquals(t, vv.Version, version.NewHeight(1, 0))
 }
 
+func TestTxSimulatorWithStateMetadata(t *testing.T) {
+	for _, testEnv := range testEnvs {
+		t.Run(testEnv.getName(), func(t *testing.T) {
+			testLedgerID := "testtxsimulatorwithstatemetadata"
+			testEnv.init(t, testLedgerID)
+			testTxSimulatorWithStateMetadata(t, testEnv)
+			testEnv.cleanup()
+		})
+	}
+}
+
+func testTxSimulatorWithStateMetadata(t *testing.T, env testEnv) {
+	txMgr := env.getTxMgr()
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+	// simulate tx1 that writes a key along with its metadata
+	s1, _ := txMgr.NewTxSimulator()
+	s1.SetState("ns1", "key1", []byte("value1"))
+	s1.SetState("ns1", "key2", []byte("value2"))
+	s1.SetStateMetadata("ns1", "key1", map[string][]byte{"entry1": []byte("metadata1")})
+	s1.Done()
+	txRWSet1, _ := s1.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet1)
+
+	// a tx writing the metadata of a non-existing key is invalid
+	s, _ := txMgr.NewTxSimulator()
+	s.SetStateMetadata("ns1", "non-existing-key", map[string][]byte{"entry1": []byte("metadata1")})
+	s.Done()
+	txRWSet, _ := s.GetTxSimulationResults()
+	txMgrHelper.checkRWsetInvalid(txRWSet)
+
+	// simulate tx2 that updates the value of key1 and the metadata of key2
+	s2, _ := txMgr.NewTxSimulator()
+	metadata, err := s2.GetStateMetadata("ns1", "key1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, metadata, map[string][]byte{"entry1": []byte("metadata1")})
+	metadata, _ = s2.GetStateMetadata("ns1", "non-existing-key")
+	testutil.AssertNil(t, metadata)
+	s2.SetState("ns1", "key1", []byte("value1_1"))
+	s2.SetStateMetadata("ns1", "key2", map[string][]byte{"entry2": []byte("metadata2")})
+	s2.Done()
+	txRWSet2, _ := s2.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet2)
+
+	// the metadata of key1 is retained across the value update, whereas the value of key2 is retained across the metadata update
+	qe, _ := txMgr.NewQueryExecutor()
+	metadata, _ = qe.GetStateMetadata("ns1", "key1")
+	testutil.AssertEquals(t, metadata, map[string][]byte{"entry1": []byte("metadata1")})
+	value, _ := qe.GetState("ns1", "key2")
+	testutil.AssertEquals(t, value, []byte("value2"))
+	metadata, _ = qe.GetStateMetadata("ns1", "key2")
+	testutil.AssertEquals(t, metadata, map[string][]byte{"entry2": []byte("metadata2")})
+	qe.Done()
+	vv, _ := env.getVDB().GetState("ns1", "key2")
+	testutil.AssertEquals(t, vv.Version, version.NewHeight(3, 0))
+
+	// simulate tx3 that deletes the metadata of key1 and deletes key2
+	s3, _ := txMgr.NewTxSimulator()
+	s3.DeleteStateMetadata("ns1", "key1")
+	s3.DeleteState("ns1", "key2")
+	s3.Done()
+	txRWSet3, _ := s3.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet3)
+
+	qe, _ = txMgr.NewQueryExecutor()
+	defer qe.Done()
+	value, _ = qe.GetState("ns1", "key1")
+	testutil.AssertEquals(t, value, []byte("value1_1"))
+	metadata, _ = qe.GetStateMetadata("ns1", "key1")
+	testutil.AssertNil(t, metadata)
+	metadata, _ = qe.GetStateMetadata("ns1", "key2")
+	testutil.AssertNil(t, metadata)
+}
+
+func TestReadRecordingQueryExecutor(t *testing.T) {
+	for _, testEnv := range testEnvs {
+		t.Run(testEnv.getName(), func(t *testing.T) {
+			testLedgerID := "testreadrecordingqueryexecutor"
+			testEnv.init(t, testLedgerID)
+			testReadRecordingQueryExecutor(t, testEnv)
+			testEnv.cleanup()
+		})
+	}
+}
+
+func testReadRecordingQueryExecutor(t *testing.T, env testEnv) {
+	txMgr := env.getTxMgr()
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+	s1, _ := txMgr.NewTxSimulator()
+	s1.SetState("ns1", "key1", []byte("value1"))
+	s1.Done()
+	txRWSet1, _ := s1.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet1)
+
+	qe, err := txMgr.NewReadRecordingQueryExecutor()
+	testutil.AssertNoError(t, err, "")
+	value, err := qe.GetState("ns1", "key1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, value, []byte("value1"))
+	value, _ = qe.GetState("ns1", "key2")
+	testutil.AssertNil(t, value)
+	readSet, err := qe.GetReadSet()
+	testutil.AssertNoError(t, err, "")
+
+	// the reads are recorded with the committed versions and there are no writes
+	txRWSet := &rwsetutil.TxRwSet{}
+	testutil.AssertNoError(t, txRWSet.FromProtoBytes(readSet), "")
+	testutil.AssertEquals(t, len(txRWSet.NsRwSets), 1)
+	kvRWSet := txRWSet.NsRwSets[0].KvRwSet
+	testutil.AssertEquals(t, len(kvRWSet.Reads), 2)
+	testutil.AssertEquals(t, kvRWSet.Reads[0].Key, "key1")
+	testutil.AssertEquals(t, rwsetutil.NewVersion(kvRWSet.Reads[0].Version), version.NewHeight(1, 0))
+	testutil.AssertEquals(t, kvRWSet.Reads[1].Key, "key2")
+	testutil.AssertNil(t, kvRWSet.Reads[1].Version)
+	testutil.AssertEquals(t, len(kvRWSet.Writes), 0)
+}
+
 func TestTxValidation(t *testing.T) {
 	for _, testEnv := range testEnvs {
 		t.Logf("Running test for TestEnv = %s", testEnv.getName())
@@ -271,6 +389,81 @@ func testTxPhantomValidation(t *testing.T, env testEnv) {
 	txMgrHelper.validateAndCommitRWSet(txRWSet4)
 }
 
+func TestPaginatedRangeQuery(t *testing.T) {
+	for _, testEnv := range testEnvs {
+		t.Logf("Running test for TestEnv = %s", testEnv.getName())
+		testLedgerID := "testpaginatedrangequery"
+		testEnv.init(t, testLedgerID)
+		testPaginatedRangeQuery(t, testEnv)
+		testEnv.cleanup()
+	}
+}
+
+func testPaginatedRangeQuery(t *testing.T, env testEnv) {
+	txMgr := env.getTxMgr()
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+	s1, _ := txMgr.NewTxSimulator()
+	for i := 1; i <= 5; i++ {
+		s1.SetState("ns", createTestKey(i), createTestValue(i))
+	}
+	s1.Done()
+	txRWSet1, _ := s1.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet1)
+
+	// simulate tx2 that reads the first page of the range
+	s2, _ := txMgr.NewTxSimulator()
+	itr2, err := s2.GetStateRangeScanIteratorWithPagination("ns", "", "", 2)
+	testutil.AssertNoError(t, err, "")
+	var keys []string
+	for {
+		result, _ := itr2.Next()
+		if result == nil {
+			break
+		}
+		keys = append(keys, result.(*queryresult.KV).Key)
+	}
+	testutil.AssertEquals(t, keys, []string{createTestKey(1), createTestKey(2)})
+	testutil.AssertEquals(t, itr2.GetBookmarkAndClose(), createTestKey(3))
+	// writes are not allowed after a paginated query
+	testutil.AssertError(t, s2.SetState("ns", createTestKey(6), createTestValue(6)), "Expected an error for a write after a paginated query")
+	s2.Done()
+	txRWSet2, _ := s2.GetTxSimulationResults()
+
+	// simulate tx3 that reads the same page
+	s3, _ := txMgr.NewTxSimulator()
+	itr3, _ := s3.GetStateRangeScanIteratorWithPagination("ns", "", "", 2)
+	for {
+		if result, _ := itr3.Next(); result == nil {
+			break
+		}
+	}
+	s3.Done()
+	txRWSet3, _ := s3.GetTxSimulationResults()
+
+	// paginated queries are not allowed after writes
+	s4, _ := txMgr.NewTxSimulator()
+	s4.SetState("ns", createTestKey(6), createTestValue(6))
+	_, err = s4.GetStateRangeScanIteratorWithPagination("ns", "", "", 2)
+	testutil.AssertError(t, err, "Expected an error for a paginated query after a write")
+	s4.Done()
+
+	// a change beyond the page does not affect the validity of tx2
+	s5, _ := txMgr.NewTxSimulator()
+	s5.DeleteState("ns", createTestKey(4))
+	s5.Done()
+	txRWSet5, _ := s5.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet5)
+	txMgrHelper.validateAndCommitRWSet(txRWSet2)
+
+	// a change within the page makes tx3 invalid
+	s6, _ := txMgr.NewTxSimulator()
+	s6.DeleteState("ns", createTestKey(2))
+	s6.Done()
+	txRWSet6, _ := s6.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet6)
+	txMgrHelper.checkRWsetInvalid(txRWSet3)
+}
+
 func TestIterator(t *testing.T) {
 	for _, testEnv := range testEnvs {
 		t.Logf("Running test for TestEnv = %s", testEnv.getName())
@@ -607,3 +800,78 @@ func testExecuteQuery(t *testing.T, env testEnv) {
 	testutil.AssertEquals(t, counter, 3)
 
 }
+
+func TestTxSimulatorWithPvtData(t *testing.T) {
+	for _, testEnv := range testEnvs {
+		t.Logf("Running test for TestEnv = %s", testEnv.getName())
+		testLedgerID := "testtxsimulatorwithpvtdata"
+		testEnv.init(t, testLedgerID)
+		testTxSimulatorWithPvtData(t, testEnv)
+		testEnv.cleanup()
+	}
+}
+
+func testTxSimulatorWithPvtData(t *testing.T, env testEnv) {
+	txMgr := env.getTxMgr()
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+
+	// simulate tx1 that writes private data
+	s1, _ := txMgr.NewTxSimulator()
+	s1.SetState("ns1", "key1", []byte("value1"))
+	s1.SetPrivateData("ns1", "coll1", "key2", []byte("pvt_value2"))
+	s1.SetPrivateData("ns1", "coll1", "key3", []byte("pvt_value3"))
+	s1.Done()
+	txRWSet1, err := s1.GetTxSimulationResults()
+	testutil.AssertNoError(t, err, "")
+	txPvtRWSet1, err := s1.GetTxPvtSimulationResults()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNotNil(t, txPvtRWSet1)
+	// the private values do not appear in the results that go into the block
+	testutil.AssertEquals(t, bytes.Contains(txRWSet1, []byte("pvt_value2")), false)
+	txMgrHelper.validateAndCommitRWSetWithPvtData(txRWSet1, txPvtRWSet1)
+
+	// simulate tx2 that reads and updates the private data
+	s2, _ := txMgr.NewTxSimulator()
+	value, err := s2.GetPrivateData("ns1", "coll1", "key2")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, value, []byte("pvt_value2"))
+	value, err = s2.GetPrivateData("ns1", "coll1", "key4")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, value)
+	s2.SetPrivateData("ns1", "coll1", "key2", []byte("pvt_value2_1"))
+	s2.DeletePrivateData("ns1", "coll1", "key3")
+	s2.Done()
+	txRWSet2, _ := s2.GetTxSimulationResults()
+	txPvtRWSet2, _ := s2.GetTxPvtSimulationResults()
+
+	// simulate tx3 that reads the same private data as tx2 - should fail validation after commit of tx2
+	s3, _ := txMgr.NewTxSimulator()
+	s3.GetPrivateData("ns1", "coll1", "key2")
+	s3.SetState("ns1", "key1", []byte("value1_1"))
+	s3.Done()
+	txRWSet3, _ := s3.GetTxSimulationResults()
+	txPvtRWSet3, _ := s3.GetTxPvtSimulationResults()
+	testutil.AssertNil(t, txPvtRWSet3)
+
+	txMgrHelper.validateAndCommitRWSetWithPvtData(txRWSet2, txPvtRWSet2)
+	txMgrHelper.checkRWsetInvalid(txRWSet3)
+
+	qe, _ := txMgr.NewQueryExecutor()
+	value, _ = qe.GetPrivateData("ns1", "coll1", "key2")
+	testutil.AssertEquals(t, value, []byte("pvt_value2_1"))
+	value, _ = qe.GetPrivateData("ns1", "coll1", "key3")
+	testutil.AssertNil(t, value)
+	qe.Done()
+
+	// commit tx4 that writes private data without supplying the private data (e.g., a peer not eligible for the collection)
+	s4, _ := txMgr.NewTxSimulator()
+	s4.SetPrivateData("ns1", "coll2", "key5", []byte("pvt_value5"))
+	s4.Done()
+	txRWSet4, _ := s4.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet4)
+
+	qe1, _ := txMgr.NewQueryExecutor()
+	defer qe1.Done()
+	_, err = qe1.GetPrivateData("ns1", "coll2", "key5")
+	testutil.AssertError(t, err, "Expected error as the private data is not available at the peer")
+}
@@ -17,7 +17,10 @@ limitations under the License.
 package lockbasedtxmgr
 
 import (
+	"fmt"
+
 	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
@@ -47,6 +50,49 @@ func (h *queryHelper) getState(ns string, key string) ([]byte, error) {
 	return val, nil
 }
 
+// getStateMetadata returns the metadata of a key and adds the key to the read-set, as the metadata is committed
+// along with the value of the key and shares its version
+func (h *queryHelper) getStateMetadata(ns string, key string) (map[string][]byte, error) {
+	h.checkDone()
+	versionedValue, err := h.txmgr.db.GetState(ns, key)
+	if err != nil {
+		return nil, err
+	}
+	var metadataBytes []byte
+	var ver *version.Height
+	if versionedValue != nil {
+		metadataBytes, ver = versionedValue.Metadata, versionedValue.Version
+	}
+	if h.rwsetBuilder != nil {
+		h.rwsetBuilder.AddToReadSet(ns, key, ver)
+	}
+	return rwsetutil.DeserializeMetadata(metadataBytes)
+}
+
+// getPrivateData returns the value of a private data item. The version of the item is taken from the hashes of the
+// private data (which are maintained by all the peers of the channel) and is added to the hashed read-set. An error
+// is returned if the hashes show that the item exists but the peer does not hold a matching version of the private data
+func (h *queryHelper) getPrivateData(ns, coll, key string) ([]byte, error) {
+	h.checkDone()
+	hashedVersion, err := h.txmgr.db.GetVersion(statedb.DeriveHashedDataNs(ns, coll),
+		statedb.EncodeHashedKey(rwsetutil.ComputeHash([]byte(key))))
+	if err != nil {
+		return nil, err
+	}
+	versionedValue, err := h.txmgr.db.GetState(statedb.DerivePvtDataNs(ns, coll), key)
+	if err != nil {
+		return nil, err
+	}
+	val, ver := decomposeVersionedValue(versionedValue)
+	if !version.AreSame(hashedVersion, ver) {
+		return nil, fmt.Errorf("Private data for key [%s] in collection [%s:%s] is not available at this peer", key, ns, coll)
+	}
+	if h.rwsetBuilder != nil {
+		h.rwsetBuilder.AddToHashedReadSet(ns, coll, key, hashedVersion)
+	}
+	return val, nil
+}
+
 func (h *queryHelper) getStateMultipleKeys(namespace string, keys []string) ([][]byte, error) {
 	h.checkDone()
 	versionedValues, err := h.txmgr.db.GetStateMultipleKeys(namespace, keys)
@@ -65,8 +111,14 @@ func (h *queryHelper) getStateMultipleKeys(namespace string, keys []string) ([][
 }
 
 func (h *queryHelper) getStateRangeScanIterator(namespace string, startKey string, endKey string) (commonledger.ResultsIterator, error) {
+	return h.getStateRangeScanIteratorWithPagination(namespace, startKey, endKey, 0)
+}
+
+// getStateRangeScanIteratorWithPagination returns a range scan iterator limited to pageSize results.
+// A zero pageSize implies no limit
+func (h *queryHelper) getStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string, pageSize int32) (ledger.QueryResultsIterator, error) {
 	h.checkDone()
-	itr, err := newResultsItr(namespace, startKey, endKey, h.txmgr.db, h.rwsetBuilder,
+	itr, err := newResultsItr(namespace, startKey, endKey, pageSize, h.txmgr.db, h.rwsetBuilder,
 		ledgerconfig.IsQueryReadsHashingEnabled(), ledgerconfig.GetMaxDegreeQueryReadsHashing())
 	if err != nil {
 		return nil, err
@@ -83,6 +135,14 @@ func (h *queryHelper) executeQuery(namespace, query string) (commonledger.Result
 	return &queryResultsItr{DBItr: dbItr, RWSetBuilder: h.rwsetBuilder}, nil
 }
 
+func (h *queryHelper) executeQueryWithPagination(namespace, query, bookmark string, pageSize int32) (ledger.QueryResultsIterator, error) {
+	dbItr, err := h.txmgr.db.ExecuteQueryWithPagination(namespace, query, bookmark, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &queryResultsItr{DBItr: dbItr, RWSetBuilder: h.rwsetBuilder}, nil
+}
+
 func (h *queryHelper) done() {
 	if h.doneInvoked {
 		return
@@ -127,19 +187,27 @@ func (h *queryHelper) checkDone() {
 type resultsItr struct {
 	ns                      string
 	endKey                  string
+	pageSize                int32
+	numRecsReturned         int32
 	dbItr                   statedb.ResultsIterator
 	rwSetBuilder            *rwsetutil.RWSetBuilder
 	rangeQueryInfo          *kvrwset.RangeQueryInfo
 	rangeQueryResultsHelper *rwsetutil.RangeQueryResultsHelper
 }
 
-func newResultsItr(ns string, startKey string, endKey string,
+func newResultsItr(ns string, startKey string, endKey string, pageSize int32,
 	db statedb.VersionedDB, rwsetBuilder *rwsetutil.RWSetBuilder, enableHashing bool, maxDegree uint32) (*resultsItr, error) {
-	dbItr, err := db.GetStateRangeScanIterator(ns, startKey, endKey)
+	var dbItr statedb.ResultsIterator
+	var err error
+	if pageSize > 0 {
+		dbItr, err = db.GetStateRangeScanIteratorWithPagination(ns, startKey, endKey, pageSize)
+	} else {
+		dbItr, err = db.GetStateRangeScanIterator(ns, startKey, endKey)
+	}
 	if err != nil {
 		return nil, err
 	}
-	itr := &resultsItr{ns: ns, dbItr: dbItr}
+	itr := &resultsItr{ns: ns, dbItr: dbItr, pageSize: pageSize}
 	// it's a simulation request so, enable capture of range query info
 	if rwsetBuilder != nil {
 		itr.rwSetBuilder = rwsetBuilder
@@ -171,6 +239,7 @@ func (itr *resultsItr) Next() (commonledger.QueryResult, error) {
 	if queryResult == nil {
 		return nil, nil
 	}
+	itr.numRecsReturned++
 	versionedKV := queryResult.(*statedb.VersionedKV)
 	return &queryresult.KV{Namespace: versionedKV.Namespace, Key: versionedKV.Key, Value: versionedKV.Value}, nil
 }
@@ -180,12 +249,17 @@ func (itr *resultsItr) Next() (commonledger.QueryResult, error) {
 //                                  because, we do not know if the caller is again going to invoke Next() or not.
 //                            or b) the last key that was supplied in the original query (if the iterator is exhausted)
 // 2) The ItrExhausted - set to true if the iterator is going to return nil as a result of the Next() call
+// For a paginated query, a nil result after a full page does not imply that the range is exhausted and hence,
+// the rangeQueryInfo is left to cover the keys returned so far
 func (itr *resultsItr) updateRangeQueryInfo(queryResult statedb.QueryResult) {
 	if itr.rwSetBuilder == nil {
 		return
 	}
 
 	if queryResult == nil {
+		if itr.pageSize > 0 && itr.numRecsReturned >= itr.pageSize {
+			return
+		}
 		// caller scanned till the iterator got exhausted.
 		// So, set the endKey to the actual endKey supplied in the query
 		itr.rangeQueryInfo.ItrExhausted = true
@@ -204,6 +278,11 @@ func (itr *resultsItr) Close() {
 	itr.dbItr.Close()
 }
 
+// GetBookmarkAndClose implements method in interface ledger.QueryResultsIterator
+func (itr *resultsItr) GetBookmarkAndClose() string {
+	return getBookmarkAndClose(itr.dbItr)
+}
+
 type queryResultsItr struct {
 	DBItr        statedb.ResultsIterator
 	RWSetBuilder *rwsetutil.RWSetBuilder
@@ -233,6 +312,20 @@ func (itr *queryResultsItr) Close() {
 	itr.DBItr.Close()
 }
 
+// GetBookmarkAndClose implements method in interface ledger.QueryResultsIterator
+func (itr *queryResultsItr) GetBookmarkAndClose() string {
+	return getBookmarkAndClose(itr.DBItr)
+}
+
+// getBookmarkAndClose returns the bookmark from the db iterator, if it is a paginated one, and closes the iterator
+func getBookmarkAndClose(dbItr statedb.ResultsIterator) string {
+	if pagedItr, ok := dbItr.(statedb.QueryResultsIterator); ok {
+		return pagedItr.GetBookmarkAndClose()
+	}
+	dbItr.Close()
+	return ""
+}
+
 func decomposeVersionedValue(versionedValue *statedb.VersionedValue) ([]byte, *version.Height) {
 	var value []byte
 	var ver *version.Height
@@ -19,6 +19,8 @@ package lockbasedtxmgr
 import (
 	"github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/util"
+	coreledger "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 )
 
 // LockBasedQueryExecutor is a query executor used in `LockBasedTxMgr`
@@ -34,6 +36,30 @@ func newQueryExecutor(txmgr *LockBasedTxMgr) *lockBasedQueryExecutor {
 	return &lockBasedQueryExecutor{helper, id}
 }
 
+// readRecordingQueryExecutor is a query executor that records its reads in a read-write set, as
+// `lockBasedTxSimulator` does, but that has no method for writing
+type readRecordingQueryExecutor struct {
+	lockBasedQueryExecutor
+	rwsetBuilder *rwsetutil.RWSetBuilder
+}
+
+func newReadRecordingQueryExecutor(txmgr *LockBasedTxMgr) *readRecordingQueryExecutor {
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	helper := &queryHelper{txmgr: txmgr, rwsetBuilder: rwsetBuilder}
+	id := util.GenerateUUID()
+	logger.Debugf("constructing new read recording query executor [%s]", id)
+	return &readRecordingQueryExecutor{lockBasedQueryExecutor: lockBasedQueryExecutor{helper, id}, rwsetBuilder: rwsetBuilder}
+}
+
+// GetReadSet implements method in interface `ledger.ReadRecordingQueryExecutor`
+func (q *readRecordingQueryExecutor) GetReadSet() ([]byte, error) {
+	q.Done()
+	if q.helper.err != nil {
+		return nil, q.helper.err
+	}
+	return q.rwsetBuilder.GetTxReadWriteSet().ToProtoBytes()
+}
+
 // GetState implements method in interface `ledger.QueryExecutor`
 func (q *lockBasedQueryExecutor) GetState(ns string, key string) ([]byte, error) {
 	return q.helper.getState(ns, key)
@@ -44,6 +70,16 @@ func (q *lockBasedQueryExecutor) GetStateMultipleKeys(namespace string, keys []s
 	return q.helper.getStateMultipleKeys(namespace, keys)
 }
 
+// GetStateMetadata implements method in interface `ledger.QueryExecutor`
+func (q *lockBasedQueryExecutor) GetStateMetadata(namespace, key string) (map[string][]byte, error) {
+	return q.helper.getStateMetadata(namespace, key)
+}
+
+// GetPrivateData implements method in interface `ledger.QueryExecutor`
+func (q *lockBasedQueryExecutor) GetPrivateData(namespace, collection, key string) ([]byte, error) {
+	return q.helper.getPrivateData(namespace, collection, key)
+}
+
 // GetStateRangeScanIterator implements method in interface `ledger.QueryExecutor`
 // startKey is included in the results and endKey is excluded. An empty startKey refers to the first available key
 // and an empty endKey refers to the last available key. For scanning all the keys, both the startKey and the endKey
@@ -52,11 +88,21 @@ func (q *lockBasedQueryExecutor) GetStateRangeScanIterator(namespace string, sta
 	return q.helper.getStateRangeScanIterator(namespace, startKey, endKey)
 }
 
+// GetStateRangeScanIteratorWithPagination implements method in interface `ledger.QueryExecutor`
+func (q *lockBasedQueryExecutor) GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string, pageSize int32) (coreledger.QueryResultsIterator, error) {
+	return q.helper.getStateRangeScanIteratorWithPagination(namespace, startKey, endKey, pageSize)
+}
+
 // ExecuteQuery implements method in interface `ledger.QueryExecutor`
 func (q *lockBasedQueryExecutor) ExecuteQuery(namespace, query string) (ledger.ResultsIterator, error) {
 	return q.helper.executeQuery(namespace, query)
 }
 
+// ExecuteQueryWithPagination implements method in interface `ledger.QueryExecutor`
+func (q *lockBasedQueryExecutor) ExecuteQueryWithPagination(namespace, query, bookmark string, pageSize int32) (coreledger.QueryResultsIterator, error) {
+	return q.helper.executeQueryWithPagination(namespace, query, bookmark, pageSize)
+}
+
 // Done implements method in interface `ledger.QueryExecutor`
 func (q *lockBasedQueryExecutor) Done() {
 	logger.Debugf("Done with transaction simulation / query execution [%s]", q.id)
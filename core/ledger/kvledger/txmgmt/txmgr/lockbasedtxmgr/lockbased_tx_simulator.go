@@ -18,15 +18,20 @@ package lockbasedtxmgr
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
 )
 
 // LockBasedTxSimulator is a transaction simulator used in `LockBasedTxMgr`
 type lockBasedTxSimulator struct {
 	lockBasedQueryExecutor
-	rwsetBuilder *rwsetutil.RWSetBuilder
+	rwsetBuilder              *rwsetutil.RWSetBuilder
+	writePerformed            bool
+	paginatedQueriesPerformed bool
 }
 
 func newLockBasedTxSimulator(txmgr *LockBasedTxMgr) *lockBasedTxSimulator {
@@ -34,7 +39,7 @@ func newLockBasedTxSimulator(txmgr *LockBasedTxMgr) *lockBasedTxSimulator {
 	helper := &queryHelper{txmgr: txmgr, rwsetBuilder: rwsetBuilder}
 	id := util.GenerateUUID()
 	logger.Debugf("constructing new tx simulator [%s]", id)
-	return &lockBasedTxSimulator{lockBasedQueryExecutor{helper, id}, rwsetBuilder}
+	return &lockBasedTxSimulator{lockBasedQueryExecutor: lockBasedQueryExecutor{helper, id}, rwsetBuilder: rwsetBuilder}
 }
 
 // GetState implements method in interface `ledger.TxSimulator`
@@ -42,9 +47,29 @@ func (s *lockBasedTxSimulator) GetState(ns string, key string) ([]byte, error) {
 	return s.helper.getState(ns, key)
 }
 
+// GetStateRangeScanIteratorWithPagination implements method in interface `ledger.TxSimulator`
+func (s *lockBasedTxSimulator) GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string, pageSize int32) (ledger.QueryResultsIterator, error) {
+	if err := s.checkBeforePaginatedQuery(); err != nil {
+		return nil, err
+	}
+	return s.helper.getStateRangeScanIteratorWithPagination(namespace, startKey, endKey, pageSize)
+}
+
+// ExecuteQueryWithPagination implements method in interface `ledger.TxSimulator`
+func (s *lockBasedTxSimulator) ExecuteQueryWithPagination(namespace, query, bookmark string, pageSize int32) (ledger.QueryResultsIterator, error) {
+	if err := s.checkBeforePaginatedQuery(); err != nil {
+		return nil, err
+	}
+	return s.helper.executeQueryWithPagination(namespace, query, bookmark, pageSize)
+}
+
 // SetState implements method in interface `ledger.TxSimulator`
 func (s *lockBasedTxSimulator) SetState(ns string, key string, value []byte) error {
 	s.helper.checkDone()
+	if s.paginatedQueriesPerformed {
+		return fmt.Errorf("Transaction [%s] has performed paginated queries, writes are not allowed", s.id)
+	}
+	s.writePerformed = true
 	s.rwsetBuilder.AddToWriteSet(ns, key, value)
 	return nil
 }
@@ -54,6 +79,22 @@ func (s *lockBasedTxSimulator) DeleteState(ns string, key string) error {
 	return s.SetState(ns, key, nil)
 }
 
+// SetStateMetadata implements method in interface `ledger.TxSimulator`
+func (s *lockBasedTxSimulator) SetStateMetadata(ns, key string, metadata map[string][]byte) error {
+	s.helper.checkDone()
+	if s.paginatedQueriesPerformed {
+		return fmt.Errorf("Transaction [%s] has performed paginated queries, writes are not allowed", s.id)
+	}
+	s.writePerformed = true
+	s.rwsetBuilder.AddToMetadataWriteSet(ns, key, metadata)
+	return nil
+}
+
+// DeleteStateMetadata implements method in interface `ledger.TxSimulator`
+func (s *lockBasedTxSimulator) DeleteStateMetadata(ns, key string) error {
+	return s.SetStateMetadata(ns, key, nil)
+}
+
 // SetStateMultipleKeys implements method in interface `ledger.TxSimulator`
 func (s *lockBasedTxSimulator) SetStateMultipleKeys(namespace string, kvs map[string][]byte) error {
 	for k, v := range kvs {
@@ -64,14 +105,58 @@ func (s *lockBasedTxSimulator) SetStateMultipleKeys(namespace string, kvs map[st
 	return nil
 }
 
+// SetPrivateData implements method in interface `ledger.TxSimulator`
+func (s *lockBasedTxSimulator) SetPrivateData(ns, coll, key string, value []byte) error {
+	s.helper.checkDone()
+	if s.paginatedQueriesPerformed {
+		return fmt.Errorf("Transaction [%s] has performed paginated queries, writes are not allowed", s.id)
+	}
+	s.writePerformed = true
+	s.rwsetBuilder.AddToPvtAndHashedWriteSet(ns, coll, key, value)
+	return nil
+}
+
+// DeletePrivateData implements method in interface `ledger.TxSimulator`
+func (s *lockBasedTxSimulator) DeletePrivateData(ns, coll, key string) error {
+	return s.SetPrivateData(ns, coll, key, nil)
+}
+
 // GetTxSimulationResults implements method in interface `ledger.TxSimulator`
 func (s *lockBasedTxSimulator) GetTxSimulationResults() ([]byte, error) {
+	txRWSet, _, err := s.getTxSimulationResults()
+	if err != nil {
+		return nil, err
+	}
+	return txRWSet.ToProtoBytes()
+}
+
+// GetTxPvtSimulationResults implements method in interface `ledger.TxSimulator`
+func (s *lockBasedTxSimulator) GetTxPvtSimulationResults() (*rwset.TxPvtReadWriteSet, error) {
+	_, txPvtRWSet, err := s.getTxSimulationResults()
+	if err != nil || txPvtRWSet == nil {
+		return nil, err
+	}
+	return txPvtRWSet.ToProtoMsg()
+}
+
+func (s *lockBasedTxSimulator) getTxSimulationResults() (*rwsetutil.TxRwSet, *rwsetutil.TxPvtRwSet, error) {
 	logger.Debugf("Simulation completed, getting simulation results")
 	s.Done()
 	if s.helper.err != nil {
-		return nil, s.helper.err
+		return nil, nil, s.helper.err
 	}
-	return s.rwsetBuilder.GetTxReadWriteSet().ToProtoBytes()
+	return s.rwsetBuilder.GetTxSimulationResults()
+}
+
+// checkBeforePaginatedQuery makes sure that the paginated queries are not mixed with the writes in a transaction.
+// The results of a paginated query are not guaranteed to be validated for the phantom reads and hence, the
+// transactions that perform such queries are expected to be read-only
+func (s *lockBasedTxSimulator) checkBeforePaginatedQuery() error {
+	if s.writePerformed {
+		return fmt.Errorf("Transaction [%s] has performed writes, paginated queries are not allowed", s.id)
+	}
+	s.paginatedQueriesPerformed = true
+	return nil
 }
 
 // ExecuteUpdate implements method in interface `ledger.TxSimulator`
@@ -40,10 +40,11 @@ type LockBasedTxMgr struct {
 	commitRWLock sync.RWMutex
 }
 
-// NewLockBasedTxMgr constructs a new instance of NewLockBasedTxMgr
-func NewLockBasedTxMgr(db statedb.VersionedDB) *LockBasedTxMgr {
+// NewLockBasedTxMgr constructs a new instance of NewLockBasedTxMgr. The config blocks
+// of the ledger are retrieved with getBlock during the validation of the blocks
+func NewLockBasedTxMgr(db statedb.VersionedDB, getBlock statebasedval.BlockRetriever) *LockBasedTxMgr {
 	db.Open()
-	return &LockBasedTxMgr{db: db, validator: statebasedval.NewValidator(db)}
+	return &LockBasedTxMgr{db: db, validator: statebasedval.NewValidator(db, getBlock)}
 }
 
 // GetLastSavepoint returns the block num recorded in savepoint,
@@ -59,6 +60,13 @@ func (txmgr *LockBasedTxMgr) NewQueryExecutor() (ledger.QueryExecutor, error) {
 	return qe, nil
 }
 
+// NewReadRecordingQueryExecutor implements method in interface `txmgmt.TxMgr`
+func (txmgr *LockBasedTxMgr) NewReadRecordingQueryExecutor() (ledger.ReadRecordingQueryExecutor, error) {
+	qe := newReadRecordingQueryExecutor(txmgr)
+	txmgr.commitRWLock.RLock()
+	return qe, nil
+}
+
 // NewTxSimulator implements method in interface `txmgmt.TxMgr`
 func (txmgr *LockBasedTxMgr) NewTxSimulator() (ledger.TxSimulator, error) {
 	logger.Debugf("constructing new tx simulator")
@@ -69,8 +77,15 @@ func (txmgr *LockBasedTxMgr) NewTxSimulator() (ledger.TxSimulator, error) {
 
 // ValidateAndPrepare implements method in interface `txmgmt.TxMgr`
 func (txmgr *LockBasedTxMgr) ValidateAndPrepare(block *common.Block, doMVCCValidation bool) error {
-	logger.Debugf("Validating new block with num trans = [%d]", len(block.Data.Data))
-	batch, err := txmgr.validator.ValidateAndPrepareBatch(block, doMVCCValidation)
+	return txmgr.ValidateAndPrepareWithPvtData(&ledger.BlockAndPvtData{Block: block}, doMVCCValidation)
+}
+
+// ValidateAndPrepareWithPvtData implements method in interface `txmgmt.TxMgr`
+func (txmgr *LockBasedTxMgr) ValidateAndPrepareWithPvtData(blockAndPvtdata *ledger.BlockAndPvtData, doMVCCValidation bool) error {
+	block := blockAndPvtdata.Block
+	logger.Debugf("Validating new block with num trans = [%d] and private data of [%d] trans",
+		len(block.Data.Data), len(blockAndPvtdata.BlockPvtData))
+	batch, err := txmgr.validator.ValidateAndPrepareBatch(block, doMVCCValidation, blockAndPvtdata.BlockPvtData)
 	if err != nil {
 		return err
 	}
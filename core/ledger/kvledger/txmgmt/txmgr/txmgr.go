@@ -25,8 +25,10 @@ import (
 // TxMgr - an interface that a transaction manager should implement
 type TxMgr interface {
 	NewQueryExecutor() (ledger.QueryExecutor, error)
+	NewReadRecordingQueryExecutor() (ledger.ReadRecordingQueryExecutor, error)
 	NewTxSimulator() (ledger.TxSimulator, error)
 	ValidateAndPrepare(block *common.Block, doMVCCValidation bool) error
+	ValidateAndPrepareWithPvtData(blockAndPvtdata *ledger.BlockAndPvtData, doMVCCValidation bool) error
 	GetLastSavepoint() (*version.Height, error)
 	ShouldRecover(lastAvailableBlock uint64) (bool, uint64, error)
 	CommitLostBlock(block *common.Block) error
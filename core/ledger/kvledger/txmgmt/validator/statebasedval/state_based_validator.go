@@ -17,7 +17,11 @@ limitations under the License.
 package statebasedval
 
 import (
+	"bytes"
+
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
@@ -30,18 +34,25 @@ import (
 
 var logger = flogging.MustGetLogger("statevalidator")
 
+// BlockRetriever retrieves a block of the ledger by its number
+type BlockRetriever func(blockNum uint64) (*common.Block, error)
+
 // Validator validates a tx against the latest committed state
 // and preceding valid transactions with in the same block
 type Validator struct {
 	db statedb.VersionedDB
+	// getBlock retrieves the config blocks holding the channel configuration that applies to the validated blocks
+	getBlock     BlockRetriever
+	txReordering *txReorderingConfig
 }
 
-// NewValidator constructs StateValidator
-func NewValidator(db statedb.VersionedDB) *Validator {
-	return &Validator{db}
+// NewValidator constructs StateValidator. The config blocks are retrieved with getBlock,
+// the transactions are never reordered if getBlock is nil
+func NewValidator(db statedb.VersionedDB, getBlock BlockRetriever) *Validator {
+	return &Validator{db: db, getBlock: getBlock}
 }
 
-//validate endorser transaction
+// validate endorser transaction
 func (v *Validator) validateEndorserTX(envBytes []byte, doMVCCValidation bool, updates *statedb.UpdateBatch) (*rwsetutil.TxRwSet, peer.TxValidationCode, error) {
 	// extract actions from the envelope message
 	respPayload, err := putils.GetActionFromEnvelope(envBytes)
@@ -79,7 +90,7 @@ func (v *Validator) validateConfigTX(env *common.Envelope) (bool, error) {
 }
 
 // ValidateAndPrepareBatch implements method in Validator interface
-func (v *Validator) ValidateAndPrepareBatch(block *common.Block, doMVCCValidation bool) (*statedb.UpdateBatch, error) {
+func (v *Validator) ValidateAndPrepareBatch(block *common.Block, doMVCCValidation bool, pvtData map[uint64]*ledger.TxPvtData) (*statedb.UpdateBatch, error) {
 	logger.Debugf("New block arrived for validation:%#v, doMVCCValidation=%t", block, doMVCCValidation)
 	updates := statedb.NewUpdateBatch()
 	logger.Debugf("Validating a block with [%d] transactions", len(block.Data.Data))
@@ -93,78 +104,288 @@ func (v *Validator) ValidateAndPrepareBatch(block *common.Block, doMVCCValidatio
 		block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsFilter
 	}
 
-	for txIndex, envBytes := range block.Data.Data {
-		if txsFilter.IsInvalid(txIndex) {
-			// Skiping invalid transaction
-			logger.Warningf("Block [%d] Transaction index [%d] marked as invalid by committer. Reason code [%d]",
-				block.Header.Number, txIndex, txsFilter.Flag(txIndex))
-			continue
-		}
-
-		env, err := putils.GetEnvelopeFromBlock(envBytes)
-		if err != nil {
+	// Load the committed versions of all the keys accessed by the block in one go, if the db supports it
+	if bulkOptimizable, ok := v.db.(statedb.BulkOptimizable); ok {
+		if err := preLoadCommittedVersions(block, txsFilter, bulkOptimizable); err != nil {
 			return nil, err
 		}
+	}
 
-		payload, err := putils.GetPayload(env)
-		if err != nil {
+	commitOrder, err := v.getTxCommitOrder(block, txsFilter, doMVCCValidation)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, txIndex := range commitOrder {
+		if err := v.validateAndPrepareTx(block, int(txIndex), txsFilter, doMVCCValidation, pvtData, updates); err != nil {
 			return nil, err
 		}
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsFilter
+	return updates, nil
+}
+
+// validateAndPrepareTx validates the transaction at the given index of the block against the committed state
+// and the updates of the transactions of the block validated so far. If the transaction is valid, its writes
+// are added to the updates
+func (v *Validator) validateAndPrepareTx(block *common.Block, txIndex int, txsFilter util.TxValidationFlags,
+	doMVCCValidation bool, pvtData map[uint64]*ledger.TxPvtData, updates *statedb.UpdateBatch) error {
+	if txsFilter.IsInvalid(txIndex) {
+		// Skiping invalid transaction
+		logger.Warningf("Block [%d] Transaction index [%d] marked as invalid by committer. Reason code [%d]",
+			block.Header.Number, txIndex, txsFilter.Flag(txIndex))
+		return nil
+	}
+	envBytes := block.Data.Data[txIndex]
+
+	env, err := putils.GetEnvelopeFromBlock(envBytes)
+	if err != nil {
+		return err
+	}
+
+	payload, err := putils.GetPayload(env)
+	if err != nil {
+		return err
+	}
+
+	chdr, err := putils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return err
+	}
+
+	if common.HeaderType(chdr.Type) == common.HeaderType_ENDORSER_TRANSACTION {
+		txRWSet, txResult, err := v.validateEndorserTX(envBytes, doMVCCValidation, updates)
 
-		chdr, err := putils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		if common.HeaderType(chdr.Type) == common.HeaderType_ENDORSER_TRANSACTION {
-			txRWSet, txResult, err := v.validateEndorserTX(envBytes, doMVCCValidation, updates)
+		txsFilter.SetFlag(txIndex, txResult)
 
-			if err != nil {
-				return nil, err
+		//txRWSet != nil => t is valid
+		if txRWSet != nil {
+			committingTxHeight := version.NewHeight(block.Header.Number, uint64(txIndex))
+			if err := v.addWriteSetToBatch(txRWSet, committingTxHeight, updates); err != nil {
+				return err
+			}
+			if txPvtData, ok := pvtData[uint64(txIndex)]; ok {
+				addPvtWriteSetToBatch(txRWSet, txPvtData, committingTxHeight, updates)
 			}
+			txsFilter.SetFlag(txIndex, peer.TxValidationCode_VALID)
+		}
+	} else if common.HeaderType(chdr.Type) == common.HeaderType_CONFIG {
+		_, err := v.validateConfigTX(env)
 
-			txsFilter.SetFlag(txIndex, txResult)
+		if err != nil {
+			return err
+		}
+		txsFilter.SetFlag(txIndex, peer.TxValidationCode_VALID)
+	} else {
+		logger.Errorf("Skipping transaction %d that's not an endorsement or configuration %d", txIndex, chdr.Type)
+		txsFilter.SetFlag(txIndex, peer.TxValidationCode_UNKNOWN_TX_TYPE)
+	}
 
-			//txRWSet != nil => t is valid
-			if txRWSet != nil {
-				committingTxHeight := version.NewHeight(block.Header.Number, uint64(txIndex))
-				addWriteSetToBatch(txRWSet, committingTxHeight, updates)
-				txsFilter.SetFlag(txIndex, peer.TxValidationCode_VALID)
-			}
-		} else if common.HeaderType(chdr.Type) == common.HeaderType_CONFIG {
-			_, err := v.validateConfigTX(env)
+	if txsFilter.IsValid(txIndex) {
+		logger.Debugf("Block [%d] Transaction index [%d] TxId [%s] marked as valid by state validator",
+			block.Header.Number, txIndex, chdr.TxId)
+	} else {
+		logger.Warningf("Block [%d] Transaction index [%d] TxId [%s] marked as invalid by state validator. Reason code [%d]",
+			block.Header.Number, txIndex, chdr.TxId, txsFilter.Flag(txIndex))
+	}
+	return nil
+}
 
-			if err != nil {
-				return nil, err
+// preLoadCommittedVersions loads into the cache of the db the committed versions of the keys read or written by
+// the endorser transactions of the block that are not already marked as invalid. The transactions that cannot be
+// parsed are skipped here, they are marked as invalid during the validation
+func preLoadCommittedVersions(block *common.Block, txsFilter util.TxValidationFlags, db statedb.BulkOptimizable) error {
+	db.ClearCachedVersions()
+
+	keys := make(map[statedb.CompositeKey]bool)
+	for txIndex, envBytes := range block.Data.Data {
+		if txsFilter.IsInvalid(txIndex) {
+			continue
+		}
+		txRWSet := getEndorserTxRWSet(envBytes)
+		if txRWSet == nil {
+			continue
+		}
+		for _, nsRWSet := range txRWSet.NsRwSets {
+			for _, kvRead := range nsRWSet.KvRwSet.Reads {
+				keys[statedb.CompositeKey{Namespace: nsRWSet.NameSpace, Key: kvRead.Key}] = true
+			}
+			for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+				keys[statedb.CompositeKey{Namespace: nsRWSet.NameSpace, Key: kvWrite.Key}] = true
+			}
+			for _, kvMetadataWrite := range nsRWSet.KvRwSet.MetadataWrites {
+				keys[statedb.CompositeKey{Namespace: nsRWSet.NameSpace, Key: kvMetadataWrite.Key}] = true
+			}
+			for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+				hashedNs := statedb.DeriveHashedDataNs(nsRWSet.NameSpace, collHashedRWSet.CollectionName)
+				for _, kvReadHash := range collHashedRWSet.HashedRwSet.HashedReads {
+					keys[statedb.CompositeKey{Namespace: hashedNs, Key: statedb.EncodeHashedKey(kvReadHash.KeyHash)}] = true
+				}
+				for _, kvWriteHash := range collHashedRWSet.HashedRwSet.HashedWrites {
+					keys[statedb.CompositeKey{Namespace: hashedNs, Key: statedb.EncodeHashedKey(kvWriteHash.KeyHash)}] = true
+				}
 			}
-			txsFilter.SetFlag(txIndex, peer.TxValidationCode_VALID)
-		} else {
-			logger.Errorf("Skipping transaction %d that's not an endorsement or configuration %d", txIndex, chdr.Type)
-			txsFilter.SetFlag(txIndex, peer.TxValidationCode_UNKNOWN_TX_TYPE)
 		}
+	}
 
-		if txsFilter.IsValid(txIndex) {
-			logger.Debugf("Block [%d] Transaction index [%d] TxId [%s] marked as valid by state validator",
-				block.Header.Number, txIndex, chdr.TxId)
-		} else {
-			logger.Warningf("Block [%d] Transaction index [%d] TxId [%s] marked as invalid by state validator. Reason code [%d]",
-				block.Header.Number, txIndex, chdr.TxId, txsFilter.Flag(txIndex))
-		}
+	keysToLoad := make([]*statedb.CompositeKey, 0, len(keys))
+	for key := range keys {
+		k := key
+		keysToLoad = append(keysToLoad, &k)
+	}
+	logger.Debugf("Block [%d]: Loading the committed versions of %d key(s)", block.Header.Number, len(keysToLoad))
+	return db.LoadCommittedVersions(keysToLoad)
+}
 
+// getEndorserTxRWSet returns the read-write set of the given transaction envelope. It returns nil if the
+// envelope cannot be parsed or if the transaction is not an endorser transaction
+func getEndorserTxRWSet(envBytes []byte) *rwsetutil.TxRwSet {
+	env, err := putils.GetEnvelopeFromBlock(envBytes)
+	if err != nil {
+		return nil
 	}
-	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsFilter
-	return updates, nil
+	payload, err := putils.GetPayload(env)
+	if err != nil {
+		return nil
+	}
+	chdr, err := putils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil || common.HeaderType(chdr.Type) != common.HeaderType_ENDORSER_TRANSACTION {
+		return nil
+	}
+	respPayload, err := putils.GetActionFromEnvelope(envBytes)
+	if err != nil {
+		return nil
+	}
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err = txRWSet.FromProtoBytes(respPayload.Results); err != nil {
+		return nil
+	}
+	return txRWSet
 }
 
-func addWriteSetToBatch(txRWSet *rwsetutil.TxRwSet, txHeight *version.Height, batch *statedb.UpdateBatch) {
+// addWriteSetToBatch adds to the batch the public and the hashed writes of a valid transaction. A value write keeps
+// the existing metadata of the key, whereas a metadata write keeps the existing value of the key. The metadata writes
+// are applied after the value writes of the transaction, so that a transaction can both write a key and its metadata
+func (v *Validator) addWriteSetToBatch(txRWSet *rwsetutil.TxRwSet, txHeight *version.Height, batch *statedb.UpdateBatch) error {
 	for _, nsRWSet := range txRWSet.NsRwSets {
 		ns := nsRWSet.NameSpace
 		for _, kvWrite := range nsRWSet.KvRwSet.Writes {
 			if kvWrite.IsDelete {
 				batch.Delete(ns, kvWrite.Key, txHeight)
-			} else {
-				batch.Put(ns, kvWrite.Key, kvWrite.Value, txHeight)
+				continue
+			}
+			metadata, err := v.getLatestMetadata(ns, kvWrite.Key, batch)
+			if err != nil {
+				return err
+			}
+			batch.PutValAndMetadata(ns, kvWrite.Key, kvWrite.Value, metadata, txHeight)
+		}
+		for _, kvMetadataWrite := range nsRWSet.KvRwSet.MetadataWrites {
+			existing, err := v.getLatestValue(ns, kvMetadataWrite.Key, batch)
+			if err != nil {
+				return err
+			}
+			if existing == nil {
+				// the transactions writing the metadata of a non-existing key are invalidated by validateTx,
+				// this only happens when recommitting the blocks committed before that check
+				logger.Warningf("Skipping the metadata write on non-existing key [%s:%s]", ns, kvMetadataWrite.Key)
+				continue
+			}
+			metadata, err := rwsetutil.SerializeMetadata(rwsetutil.MetadataEntriesToMap(kvMetadataWrite.Entries))
+			if err != nil {
+				return err
+			}
+			batch.PutValAndMetadata(ns, kvMetadataWrite.Key, existing.Value, metadata, txHeight)
+		}
+		for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+			hashedNs := statedb.DeriveHashedDataNs(ns, collHashedRWSet.CollectionName)
+			for _, kvWriteHash := range collHashedRWSet.HashedRwSet.HashedWrites {
+				if kvWriteHash.IsDelete {
+					batch.Delete(hashedNs, statedb.EncodeHashedKey(kvWriteHash.KeyHash), txHeight)
+				} else {
+					batch.Put(hashedNs, statedb.EncodeHashedKey(kvWriteHash.KeyHash), kvWriteHash.ValueHash, txHeight)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// getLatestMetadata returns the metadata of a key as updated by the preceding transactions of the block, if any,
+// or else as committed in the db. The committed metadata is served from the cache of the db when the keys of the
+// block were loaded in bulk by preLoadCommittedVersions, so that writing a key does not cost a lookup
+func (v *Validator) getLatestMetadata(ns, key string, batch *statedb.UpdateBatch) ([]byte, error) {
+	if batch.Exists(ns, key) {
+		return batch.Get(ns, key).Metadata, nil
+	}
+	if bulkOptimizable, ok := v.db.(statedb.BulkOptimizable); ok {
+		if metadata, ok := bulkOptimizable.GetCachedMetadata(ns, key); ok {
+			return metadata, nil
+		}
+	}
+	vv, err := v.db.GetState(ns, key)
+	if err != nil || vv == nil {
+		return nil, err
+	}
+	return vv.Metadata, nil
+}
+
+// getLatestValue returns the value of a key as updated by the preceding transactions of the block, if any,
+// or else as committed in the db. It returns nil if the key does not exist or has been deleted
+func (v *Validator) getLatestValue(ns, key string, batch *statedb.UpdateBatch) (*statedb.VersionedValue, error) {
+	if batch.Exists(ns, key) {
+		vv := batch.Get(ns, key)
+		if vv.Value == nil {
+			return nil, nil
+		}
+		return vv, nil
+	}
+	return v.db.GetState(ns, key)
+}
+
+// addPvtWriteSetToBatch adds to the batch the private writes of a valid transaction. The private read-write set
+// of a collection is applied only if its hash matches the one present in the transaction. Otherwise, it is skipped
+// and the peer ends up not holding the private data, as if the data was never made available to it
+func addPvtWriteSetToBatch(txRWSet *rwsetutil.TxRwSet, txPvtData *ledger.TxPvtData, txHeight *version.Height, batch *statedb.UpdateBatch) {
+	if txPvtData.WriteSet == nil {
+		return
+	}
+	pvtRWSetHashes := make(map[string][]byte)
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+			pvtRWSetHashes[statedb.DerivePvtDataNs(nsRWSet.NameSpace, collHashedRWSet.CollectionName)] = collHashedRWSet.PvtRwSetHash
+		}
+	}
+	for _, nsPvtRWSet := range txPvtData.WriteSet.NsPvtRwset {
+		for _, collPvtRWSet := range nsPvtRWSet.CollectionPvtRwset {
+			pvtNs := statedb.DerivePvtDataNs(nsPvtRWSet.Namespace, collPvtRWSet.CollectionName)
+			expectedHash, ok := pvtRWSetHashes[pvtNs]
+			if !ok || !bytes.Equal(rwsetutil.ComputeHash(collPvtRWSet.Rwset), expectedHash) {
+				logger.Warningf("Private data for collection [%s:%s] of transaction [%d] does not match the hash in the transaction, skipping it",
+					nsPvtRWSet.Namespace, collPvtRWSet.CollectionName, txHeight.TxNum)
+				continue
+			}
+			kvRWSet := &kvrwset.KVRWSet{}
+			if err := proto.Unmarshal(collPvtRWSet.Rwset, kvRWSet); err != nil {
+				logger.Warningf("Private data for collection [%s:%s] of transaction [%d] could not be unmarshalled, skipping it: %s",
+					nsPvtRWSet.Namespace, collPvtRWSet.CollectionName, txHeight.TxNum, err)
+				continue
 			}
+			addKVWritesToBatch(pvtNs, kvRWSet.Writes, txHeight, batch)
+		}
+	}
+}
+
+func addKVWritesToBatch(ns string, kvWrites []*kvrwset.KVWrite, txHeight *version.Height, batch *statedb.UpdateBatch) {
+	for _, kvWrite := range kvWrites {
+		if kvWrite.IsDelete {
+			batch.Delete(ns, kvWrite.Key, txHeight)
+		} else {
+			batch.Put(ns, kvWrite.Key, kvWrite.Value, txHeight)
 		}
 	}
 }
@@ -185,10 +406,64 @@ func (v *Validator) validateTx(txRWSet *rwsetutil.TxRwSet, updates *statedb.Upda
 			}
 			return peer.TxValidationCode_PHANTOM_READ_CONFLICT, nil
 		}
+		if valid, err := v.validateMetadataWrites(ns, nsRWSet.KvRwSet, updates); !valid || err != nil {
+			if err != nil {
+				return peer.TxValidationCode(-1), err
+			}
+			return peer.TxValidationCode_INVALID_OTHER_REASON, nil
+		}
+		for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+			if valid, err := v.validateHashedReadSet(ns, collHashedRWSet.CollectionName,
+				collHashedRWSet.HashedRwSet.HashedReads, updates); !valid || err != nil {
+				if err != nil {
+					return peer.TxValidationCode(-1), err
+				}
+				return peer.TxValidationCode_MVCC_READ_CONFLICT, nil
+			}
+		}
 	}
 	return peer.TxValidationCode_VALID, nil
 }
 
+// validateMetadataWrites checks that the keys whose metadata is written exist, either because the transaction
+// writes them or because they are present in the updates of the preceding transactions or in the db. The versions
+// of the keys are loaded in bulk by preLoadCommittedVersions, for the dbs supporting it
+func (v *Validator) validateMetadataWrites(ns string, kvRWSet *kvrwset.KVRWSet, updates *statedb.UpdateBatch) (bool, error) {
+	if len(kvRWSet.MetadataWrites) == 0 {
+		return true, nil
+	}
+	writes := make(map[string]bool)
+	for _, kvWrite := range kvRWSet.Writes {
+		writes[kvWrite.Key] = !kvWrite.IsDelete
+	}
+	for _, kvMetadataWrite := range kvRWSet.MetadataWrites {
+		key := kvMetadataWrite.Key
+		if exists, ok := writes[key]; ok {
+			if !exists {
+				logger.Warningf("Metadata write on key [%s:%s] deleted by the same transaction", ns, key)
+				return false, nil
+			}
+			continue
+		}
+		if updates.Exists(ns, key) {
+			if updates.Get(ns, key).Value == nil {
+				logger.Warningf("Metadata write on key [%s:%s] deleted by a preceding transaction", ns, key)
+				return false, nil
+			}
+			continue
+		}
+		committedVersion, err := v.db.GetVersion(ns, key)
+		if err != nil {
+			return false, err
+		}
+		if committedVersion == nil {
+			logger.Warningf("Metadata write on non-existing key [%s:%s]", ns, key)
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 func (v *Validator) validateReadSet(ns string, kvReads []*kvrwset.KVRead, updates *statedb.UpdateBatch) (bool, error) {
 	for _, kvRead := range kvReads {
 		if valid, err := v.validateKVRead(ns, kvRead, updates); !valid || err != nil {
@@ -205,14 +480,10 @@ func (v *Validator) validateKVRead(ns string, kvRead *kvrwset.KVRead, updates *s
 	if updates.Exists(ns, kvRead.Key) {
 		return false, nil
 	}
-	versionedValue, err := v.db.GetState(ns, kvRead.Key)
+	committedVersion, err := v.db.GetVersion(ns, kvRead.Key)
 	if err != nil {
 		return false, nil
 	}
-	var committedVersion *version.Height
-	if versionedValue != nil {
-		committedVersion = versionedValue.Version
-	}
 
 	if !version.AreSame(committedVersion, rwsetutil.NewVersion(kvRead.Version)) {
 		logger.Debugf("Version mismatch for key [%s:%s]. Committed version = [%s], Version in readSet [%s]",
@@ -222,6 +493,20 @@ func (v *Validator) validateKVRead(ns string, kvRead *kvrwset.KVRead, updates *s
 	return true, nil
 }
 
+// validateHashedReadSet performs mvcc check for the hashes of the private data keys read during transaction simulation.
+// The check is performed against the hashes of the private data that are maintained by all the peers of the channel
+// and hence, the outcome does not depend on whether the peer holds the private data itself
+func (v *Validator) validateHashedReadSet(ns, coll string, kvReadHashes []*kvrwset.KVReadHash, updates *statedb.UpdateBatch) (bool, error) {
+	hashedNs := statedb.DeriveHashedDataNs(ns, coll)
+	for _, kvReadHash := range kvReadHashes {
+		kvRead := &kvrwset.KVRead{Key: statedb.EncodeHashedKey(kvReadHash.KeyHash), Version: kvReadHash.Version}
+		if valid, err := v.validateKVRead(hashedNs, kvRead, updates); !valid || err != nil {
+			return valid, err
+		}
+	}
+	return true, nil
+}
+
 func (v *Validator) validateRangeQueries(ns string, rangeQueriesInfo []*kvrwset.RangeQueryInfo, updates *statedb.UpdateBatch) (bool, error) {
 	for _, rqi := range rangeQueriesInfo {
 		if valid, err := v.validateRangeQuery(ns, rqi, updates); !valid || err != nil {
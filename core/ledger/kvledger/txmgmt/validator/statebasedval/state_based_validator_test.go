@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateleveldb"
@@ -28,6 +29,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/spf13/viper"
 )
 
@@ -52,7 +54,7 @@ func TestValidator(t *testing.T) {
 	batch.Put("ns1", "key5", []byte("value5"), version.NewHeight(1, 4))
 	db.ApplyUpdates(batch, version.NewHeight(1, 4))
 
-	validator := NewValidator(db)
+	validator := NewValidator(db, nil)
 
 	//rwset1 should be valid
 	rwsetBuilder1 := rwsetutil.NewRWSetBuilder()
@@ -97,7 +99,7 @@ func TestPhantomValidation(t *testing.T) {
 	batch.Put("ns1", "key5", []byte("value5"), version.NewHeight(1, 4))
 	db.ApplyUpdates(batch, version.NewHeight(1, 4))
 
-	validator := NewValidator(db)
+	validator := NewValidator(db, nil)
 
 	//rwset1 should be valid
 	rwsetBuilder1 := rwsetutil.NewRWSetBuilder()
@@ -175,7 +177,7 @@ func TestPhantomHashBasedValidation(t *testing.T) {
 	batch.Put("ns1", "key9", []byte("value9"), version.NewHeight(1, 8))
 	db.ApplyUpdates(batch, version.NewHeight(1, 8))
 
-	validator := NewValidator(db)
+	validator := NewValidator(db, nil)
 
 	rwsetBuilder1 := rwsetutil.NewRWSetBuilder()
 	rqi1 := &kvrwset.RangeQueryInfo{StartKey: "key2", EndKey: "key9", ItrExhausted: true}
@@ -210,6 +212,240 @@ func TestPhantomHashBasedValidation(t *testing.T) {
 	checkValidation(t, validator, []*rwsetutil.TxRwSet{rwsetBuilder2.GetTxReadWriteSet()}, []int{0})
 }
 
+// bulkOptimizableDB wraps a VersionedDB with an in-memory version and metadata cache, counting the db lookups
+type bulkOptimizableDB struct {
+	statedb.VersionedDB
+	cache          map[statedb.CompositeKey]*version.Height
+	metadataCache  map[statedb.CompositeKey][]byte
+	loadedKeys     []*statedb.CompositeKey
+	getVersionHits int
+	getStateHits   int
+}
+
+func (db *bulkOptimizableDB) LoadCommittedVersions(keys []*statedb.CompositeKey) error {
+	db.loadedKeys = keys
+	db.metadataCache = make(map[statedb.CompositeKey][]byte)
+	for _, key := range keys {
+		vv, err := db.VersionedDB.GetState(key.Namespace, key.Key)
+		if err != nil {
+			return err
+		}
+		db.cache[*key] = nil
+		if vv != nil {
+			db.cache[*key] = vv.Version
+			db.metadataCache[*key] = vv.Metadata
+		}
+	}
+	return nil
+}
+
+func (db *bulkOptimizableDB) GetCachedMetadata(namespace, key string) ([]byte, bool) {
+	compositeKey := statedb.CompositeKey{Namespace: namespace, Key: key}
+	if _, ok := db.cache[compositeKey]; !ok {
+		return nil, false
+	}
+	return db.metadataCache[compositeKey], true
+}
+
+func (db *bulkOptimizableDB) GetState(namespace, key string) (*statedb.VersionedValue, error) {
+	db.getStateHits++
+	return db.VersionedDB.GetState(namespace, key)
+}
+
+func (db *bulkOptimizableDB) GetCachedVersion(namespace, key string) (*version.Height, bool) {
+	ver, ok := db.cache[statedb.CompositeKey{Namespace: namespace, Key: key}]
+	return ver, ok
+}
+
+func (db *bulkOptimizableDB) ClearCachedVersions() {
+	db.cache = make(map[statedb.CompositeKey]*version.Height)
+}
+
+func (db *bulkOptimizableDB) GetVersion(namespace, key string) (*version.Height, error) {
+	if ver, ok := db.GetCachedVersion(namespace, key); ok {
+		return ver, nil
+	}
+	db.getVersionHits++
+	return db.VersionedDB.GetVersion(namespace, key)
+}
+
+func TestValidatorWithBulkOptimizableDB(t *testing.T) {
+	testDBEnv := stateleveldb.NewTestVDBEnv(t)
+	defer testDBEnv.Cleanup()
+
+	vdb, err := testDBEnv.DBProvider.GetDBHandle("TestDB")
+	testutil.AssertNoError(t, err, "")
+
+	//populate db with initial data
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 0))
+	batch.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 1))
+	vdb.ApplyUpdates(batch, version.NewHeight(1, 1))
+
+	db := &bulkOptimizableDB{VersionedDB: vdb, cache: make(map[statedb.CompositeKey]*version.Height)}
+	validator := NewValidator(db, nil)
+
+	//rwset1 is valid, rwset2 is invalid because of a stale read, rwset3 reads a key that does not exist
+	rwsetBuilder1 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder1.AddToReadSet("ns1", "key1", version.NewHeight(1, 0))
+	rwsetBuilder1.AddToWriteSet("ns1", "key3", []byte("value3"))
+	rwsetBuilder2 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder2.AddToReadSet("ns1", "key2", version.NewHeight(1, 0))
+	rwsetBuilder3 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder3.AddToReadSet("ns2", "key1", nil)
+	checkValidation(t, validator, []*rwsetutil.TxRwSet{rwsetBuilder1.GetTxReadWriteSet(),
+		rwsetBuilder2.GetTxReadWriteSet(), rwsetBuilder3.GetTxReadWriteSet()}, []int{1})
+
+	//all the keys read or written by the block are loaded in one go and no further lookup is performed
+	testutil.AssertEquals(t, len(db.loadedKeys), 4)
+	loadedKeys := []statedb.CompositeKey{}
+	for _, key := range db.loadedKeys {
+		loadedKeys = append(loadedKeys, *key)
+	}
+	testutil.AssertContainsAll(t, loadedKeys, []statedb.CompositeKey{
+		{Namespace: "ns1", Key: "key1"}, {Namespace: "ns1", Key: "key2"},
+		{Namespace: "ns1", Key: "key3"}, {Namespace: "ns2", Key: "key1"}})
+	testutil.AssertEquals(t, db.getVersionHits, 0)
+}
+
+func TestValidatorWithStateMetadata(t *testing.T) {
+	testDBEnv := stateleveldb.NewTestVDBEnv(t)
+	defer testDBEnv.Cleanup()
+
+	vdb, err := testDBEnv.DBProvider.GetDBHandle("TestDB")
+	testutil.AssertNoError(t, err, "")
+
+	//populate db with initial data, key1 has metadata
+	batch := statedb.NewUpdateBatch()
+	batch.PutValAndMetadata("ns1", "key1", []byte("value1"), []byte("metadata1"), version.NewHeight(1, 0))
+	batch.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 1))
+	vdb.ApplyUpdates(batch, version.NewHeight(1, 1))
+
+	db := &bulkOptimizableDB{VersionedDB: vdb, cache: make(map[statedb.CompositeKey]*version.Height)}
+	validator := NewValidator(db, nil)
+
+	//tx1 writes key1, tx2 writes the metadata of key2, tx3 writes the metadata of a non-existing key,
+	//tx4 writes key3 along with its metadata and tx5 deletes key3 and writes its metadata
+	rwsetBuilder1 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder1.AddToWriteSet("ns1", "key1", []byte("value1_1"))
+	rwsetBuilder2 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder2.AddToMetadataWriteSet("ns1", "key2", map[string][]byte{"entry2": []byte("metadata2")})
+	rwsetBuilder3 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder3.AddToMetadataWriteSet("ns1", "key4", map[string][]byte{"entry4": []byte("metadata4")})
+	rwsetBuilder4 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder4.AddToWriteSet("ns1", "key3", []byte("value3"))
+	rwsetBuilder4.AddToMetadataWriteSet("ns1", "key3", map[string][]byte{"entry3": []byte("metadata3")})
+	rwsetBuilder5 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder5.AddToWriteSet("ns1", "key3", nil)
+	rwsetBuilder5.AddToMetadataWriteSet("ns1", "key3", map[string][]byte{"entry3": []byte("metadata3")})
+
+	simulationResults := [][]byte{}
+	for _, rwsetBuilder := range []*rwsetutil.RWSetBuilder{rwsetBuilder1, rwsetBuilder2, rwsetBuilder3, rwsetBuilder4, rwsetBuilder5} {
+		sr, err := rwsetBuilder.GetTxReadWriteSet().ToProtoBytes()
+		testutil.AssertNoError(t, err, "")
+		simulationResults = append(simulationResults, sr)
+	}
+	block := testutil.ConstructBlock(t, 2, []byte("dummyPreviousHash"), simulationResults, false)
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = util.NewTxValidationFlags(len(block.Data.Data))
+	updates, err := validator.ValidateAndPrepareBatch(block, true, nil)
+	testutil.AssertNoError(t, err, "")
+
+	txsFltr := util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	testutil.AssertEquals(t, txsFltr.IsValid(0), true)
+	testutil.AssertEquals(t, txsFltr.IsValid(1), true)
+	testutil.AssertEquals(t, txsFltr.Flag(2), peer.TxValidationCode_INVALID_OTHER_REASON)
+	testutil.AssertEquals(t, txsFltr.IsValid(3), true)
+	testutil.AssertEquals(t, txsFltr.Flag(4), peer.TxValidationCode_INVALID_OTHER_REASON)
+
+	//the metadata of key1 is kept, the value of key2 is kept
+	vv := updates.Get("ns1", "key1")
+	testutil.AssertEquals(t, vv.Value, []byte("value1_1"))
+	testutil.AssertEquals(t, vv.Metadata, []byte("metadata1"))
+	vv = updates.Get("ns1", "key2")
+	testutil.AssertEquals(t, vv.Value, []byte("value2"))
+	testutil.AssertNotNil(t, vv.Metadata)
+	vv = updates.Get("ns1", "key3")
+	testutil.AssertEquals(t, vv.Value, []byte("value3"))
+	testutil.AssertNotNil(t, vv.Metadata)
+	testutil.AssertNil(t, updates.Get("ns1", "key4"))
+
+	//the committed metadata comes from the cache, only the value of key2 is looked up for its metadata write
+	testutil.AssertEquals(t, db.getStateHits, 1)
+	testutil.AssertEquals(t, db.getVersionHits, 0)
+}
+
+func TestValidatorWithPvtData(t *testing.T) {
+	testDBEnv := stateleveldb.NewTestVDBEnv(t)
+	defer testDBEnv.Cleanup()
+
+	db, err := testDBEnv.DBProvider.GetDBHandle("TestDB")
+	testutil.AssertNoError(t, err, "")
+
+	//populate db with the hash of a private key
+	batch := statedb.NewUpdateBatch()
+	batch.Put(statedb.DeriveHashedDataNs("ns1", "coll1"), statedb.EncodeHashedKey(rwsetutil.ComputeHash([]byte("key1"))),
+		rwsetutil.ComputeHash([]byte("value1")), version.NewHeight(1, 0))
+	db.ApplyUpdates(batch, version.NewHeight(1, 0))
+
+	validator := NewValidator(db, nil)
+
+	//tx1 reads the private key with the committed version and writes private data - valid
+	rwsetBuilder1 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder1.AddToHashedReadSet("ns1", "coll1", "key1", version.NewHeight(1, 0))
+	rwsetBuilder1.AddToPvtAndHashedWriteSet("ns1", "coll1", "key1", []byte("value1_new"))
+	txRWSet1, txPvtRWSet1, err := rwsetBuilder1.GetTxSimulationResults()
+	testutil.AssertNoError(t, err, "")
+
+	//tx2 reads the private key with the committed version - invalid because of tx1
+	rwsetBuilder2 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder2.AddToHashedReadSet("ns1", "coll1", "key1", version.NewHeight(1, 0))
+	rwsetBuilder2.AddToPvtAndHashedWriteSet("ns1", "coll1", "key2", []byte("value2"))
+	txRWSet2, txPvtRWSet2, err := rwsetBuilder2.GetTxSimulationResults()
+	testutil.AssertNoError(t, err, "")
+
+	//tx3 writes private data but the private data supplied does not match the hash - valid, but private data not applied
+	rwsetBuilder3 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder3.AddToPvtAndHashedWriteSet("ns1", "coll1", "key3", []byte("value3"))
+	txRWSet3, _, err := rwsetBuilder3.GetTxSimulationResults()
+	testutil.AssertNoError(t, err, "")
+	rwsetBuilder3Tampered := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder3Tampered.AddToPvtAndHashedWriteSet("ns1", "coll1", "key3", []byte("value3_tampered"))
+	_, txPvtRWSet3Tampered, err := rwsetBuilder3Tampered.GetTxSimulationResults()
+	testutil.AssertNoError(t, err, "")
+
+	simulationResults := [][]byte{}
+	for _, txRWSet := range []*rwsetutil.TxRwSet{txRWSet1, txRWSet2, txRWSet3} {
+		sr, err := txRWSet.ToProtoBytes()
+		testutil.AssertNoError(t, err, "")
+		simulationResults = append(simulationResults, sr)
+	}
+	pvtData := make(map[uint64]*ledger.TxPvtData)
+	for i, txPvtRWSet := range []*rwsetutil.TxPvtRwSet{txPvtRWSet1, txPvtRWSet2, txPvtRWSet3Tampered} {
+		writeSet, err := txPvtRWSet.ToProtoMsg()
+		testutil.AssertNoError(t, err, "")
+		pvtData[uint64(i)] = &ledger.TxPvtData{SeqInBlock: uint64(i), WriteSet: writeSet}
+	}
+	block := testutil.ConstructBlock(t, 2, []byte("dummyPreviousHash"), simulationResults, false)
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = util.NewTxValidationFlags(len(block.Data.Data))
+	updates, err := validator.ValidateAndPrepareBatch(block, true, pvtData)
+	testutil.AssertNoError(t, err, "")
+
+	txsFltr := util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	testutil.AssertEquals(t, txsFltr.IsValid(0), true)
+	testutil.AssertEquals(t, txsFltr.IsValid(1), false)
+	testutil.AssertEquals(t, txsFltr.IsValid(2), true)
+
+	pvtNs := statedb.DerivePvtDataNs("ns1", "coll1")
+	hashedNs := statedb.DeriveHashedDataNs("ns1", "coll1")
+	testutil.AssertEquals(t, updates.Get(pvtNs, "key1"),
+		&statedb.VersionedValue{Value: []byte("value1_new"), Version: version.NewHeight(2, 0)})
+	testutil.AssertEquals(t, updates.Get(hashedNs, statedb.EncodeHashedKey(rwsetutil.ComputeHash([]byte("key1")))),
+		&statedb.VersionedValue{Value: rwsetutil.ComputeHash([]byte("value1_new")), Version: version.NewHeight(2, 0)})
+	testutil.AssertNil(t, updates.Get(pvtNs, "key2"))
+	testutil.AssertNil(t, updates.Get(pvtNs, "key3"))
+	testutil.AssertEquals(t, updates.Exists(hashedNs, statedb.EncodeHashedKey(rwsetutil.ComputeHash([]byte("key3")))), true)
+}
+
 func checkValidation(t *testing.T, validator *Validator, rwsets []*rwsetutil.TxRwSet, invalidTxIndexes []int) {
 	simulationResults := [][]byte{}
 	for _, txRWS := range rwsets {
@@ -219,7 +455,7 @@ func checkValidation(t *testing.T, validator *Validator, rwsets []*rwsetutil.TxR
 	}
 	block := testutil.ConstructBlock(t, 1, []byte("dummyPreviousHash"), simulationResults, false)
 	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = util.NewTxValidationFlags(len(block.Data.Data))
-	_, err := validator.ValidateAndPrepareBatch(block, true)
+	_, err := validator.ValidateAndPrepareBatch(block, true, nil)
 	txsFltr := util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
 	invalidTxs := make([]int, 0)
 	for i := 0; i < len(block.Data.Data); i++ {
@@ -0,0 +1,514 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statebasedval
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/config"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
+)
+
+// txReorderingConfig records whether a config block enables the reordering of transactions
+type txReorderingConfig struct {
+	configBlockNum uint64
+	enabled        bool
+}
+
+// isTxReorderingEnabled returns whether the TxReordering value of the Application configuration that applies
+// to the block, i.e., the one of the last config block referenced by the metadata of the block, enables the
+// reordering of its transactions. The setting thereby is the same on all the peers of the channel. The
+// transactions are not reordered if the block does not reference a valid config block
+func (v *Validator) isTxReorderingEnabled(block *common.Block) (bool, error) {
+	if v.getBlock == nil || len(block.Metadata.Metadata) <= int(common.BlockMetadataIndex_LAST_CONFIG) ||
+		len(block.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG]) == 0 {
+		return false, nil
+	}
+	configBlockNum, err := putils.GetLastConfigIndexFromBlock(block)
+	if err != nil {
+		return false, fmt.Errorf("Block [%d]: failed reading the index of the last config block: %s", block.Header.Number, err)
+	}
+	if configBlockNum > block.Header.Number {
+		logger.Warningf("Block [%d]: Not reordering the transactions as the block references the later config block [%d]", block.Header.Number, configBlockNum)
+		return false, nil
+	}
+	if v.txReordering != nil && v.txReordering.configBlockNum == configBlockNum {
+		return v.txReordering.enabled, nil
+	}
+	configBlock := block
+	if configBlockNum != block.Header.Number {
+		if configBlock, err = v.getBlock(configBlockNum); err != nil {
+			return false, fmt.Errorf("Block [%d]: failed retrieving the config block [%d]: %s", block.Header.Number, configBlockNum, err)
+		}
+	}
+	enabled, err := getTxReorderingFromConfigBlock(configBlock)
+	if err != nil {
+		logger.Warningf("Block [%d]: Not reordering the transactions as the config block [%d] cannot be read: %s", block.Header.Number, configBlockNum, err)
+		enabled = false
+	}
+	logger.Debugf("Transaction reordering enabled by config block [%d]: %t", configBlockNum, enabled)
+	v.txReordering = &txReorderingConfig{configBlockNum, enabled}
+	return enabled, nil
+}
+
+func getTxReorderingFromConfigBlock(configBlock *common.Block) (bool, error) {
+	env, err := putils.ExtractEnvelope(configBlock, 0)
+	if err != nil {
+		return false, err
+	}
+	payload, err := putils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return false, err
+	}
+	if payload.Header == nil {
+		return false, fmt.Errorf("missing header")
+	}
+	chdr, err := putils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return false, err
+	}
+	if common.HeaderType(chdr.Type) != common.HeaderType_CONFIG {
+		return false, fmt.Errorf("not a config block")
+	}
+	configEnv := &common.ConfigEnvelope{}
+	if err = proto.Unmarshal(payload.Data, configEnv); err != nil {
+		return false, err
+	}
+	appGroup := configEnv.GetConfig().GetChannelGroup().GetGroups()[config.ApplicationGroupKey]
+	value := appGroup.GetValues()[config.TxReorderingKey]
+	if value == nil {
+		return false, nil
+	}
+	txReordering := &peer.TxReordering{}
+	if err = proto.Unmarshal(value.Value, txReordering); err != nil {
+		return false, err
+	}
+	return txReordering.Enabled, nil
+}
+
+// getTxCommitOrder returns the order in which the transactions of the block are validated and applied to the state.
+// If reordering is enabled by the channel configuration, the order is derived from the dependencies between the read-write sets of the transactions
+// of the block and it is recorded in the block metadata when it differs from the order of the block. The order recorded
+// in the metadata of a block received for commit does not come from this peer and is never trusted; it is only used
+// when the peer replays one of its own committed blocks on recovery (i.e., without MVCC validation)
+func (v *Validator) getTxCommitOrder(block *common.Block, txsFilter util.TxValidationFlags, doMVCCValidation bool) (util.TxCommitOrder, error) {
+	numTxs := len(block.Data.Data)
+	reorderTxs, err := v.isTxReorderingEnabled(block)
+	if err != nil {
+		return nil, err
+	}
+	if !doMVCCValidation {
+		if reorderTxs {
+			if recordedOrder := getRecordedTxCommitOrder(block); recordedOrder != nil {
+				if recordedOrder.IsPermutationOf(numTxs) {
+					logger.Debugf("Block [%d]: Using the transaction commit order recorded in the block metadata", block.Header.Number)
+					return recordedOrder, nil
+				}
+				logger.Warningf("Block [%d]: Ignoring the transaction commit order recorded in the block metadata as it does not cover the [%d] transactions of the block",
+					block.Header.Number, numTxs)
+			}
+		}
+		return newBlockTxOrder(numTxs), nil
+	}
+	clearTxCommitOrder(block)
+	if !reorderTxs {
+		return newBlockTxOrder(numTxs), nil
+	}
+	order, err := v.computeTxCommitOrder(block, txsFilter)
+	if err != nil {
+		return nil, err
+	}
+	if !isBlockTxOrder(order) {
+		logger.Debugf("Block [%d]: Transactions reordered for commit: %v", block.Header.Number, order)
+		setTxCommitOrder(block, order)
+	}
+	return order, nil
+}
+
+// computeTxCommitOrder derives an order of the transactions of the block such that as many of them as possible
+// remain valid. Each transaction that is valid against the committed state becomes a vertex of a dependency graph
+// with an edge from a transaction to another transaction when the former has to be applied first, i.e., when it reads a
+// key (or a range of keys) that the latter writes or when both of them write the same key and the former comes first in
+// the block. The cycles of the graph are broken by invalidating some of the transactions and the remaining transactions
+// are ordered topologically, preferring the order of the block. The transactions that are not part of the graph are
+// marked as invalid here and are placed at the end of the order
+func (v *Validator) computeTxCommitOrder(block *common.Block, txsFilter util.TxValidationFlags) (util.TxCommitOrder, error) {
+	numTxs := len(block.Data.Data)
+	graph := newTxDependencyGraph()
+	for txIndex, envBytes := range block.Data.Data {
+		if txsFilter.IsInvalid(txIndex) {
+			continue
+		}
+		txRWSet := getEndorserTxRWSet(envBytes)
+		if txRWSet == nil {
+			// configuration transactions and transactions that cannot be parsed are processed in the order of the block
+			logger.Debugf("Block [%d]: Transaction index [%d] is not an endorser transaction, keeping the order of the block",
+				block.Header.Number, txIndex)
+			return newBlockTxOrder(numTxs), nil
+		}
+		txResult, err := v.validateTx(txRWSet, statedb.NewUpdateBatch())
+		if err != nil {
+			return nil, err
+		}
+		if txResult != peer.TxValidationCode_VALID {
+			txsFilter.SetFlag(txIndex, txResult)
+			continue
+		}
+		graph.addTx(uint64(txIndex), txRWSet)
+	}
+
+	graph.addDependencies()
+	for _, txIndex := range graph.breakCycles() {
+		logger.Debugf("Block [%d]: Transaction index [%d] invalidated to break a cycle of dependencies", block.Header.Number, txIndex)
+		txsFilter.SetFlag(int(txIndex), peer.TxValidationCode_MVCC_READ_CONFLICT)
+	}
+
+	order := graph.topologicalOrder()
+	ordered := make(map[uint64]bool, len(order))
+	for _, txIndex := range order {
+		ordered[txIndex] = true
+	}
+	for txIndex := 0; txIndex < numTxs; txIndex++ {
+		if !ordered[uint64(txIndex)] {
+			order = append(order, uint64(txIndex))
+		}
+	}
+	return order, nil
+}
+
+func getRecordedTxCommitOrder(block *common.Block) util.TxCommitOrder {
+	if len(block.Metadata.Metadata) <= int(common.BlockMetadataIndex_TRANSACTIONS_COMMIT_ORDER) ||
+		len(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_COMMIT_ORDER]) == 0 {
+		return nil
+	}
+	order, err := util.NewTxCommitOrderFromBytes(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_COMMIT_ORDER])
+	if err != nil {
+		logger.Warningf("Block [%d]: Ignoring the transaction commit order recorded in the block metadata: %s", block.Header.Number, err)
+		return nil
+	}
+	return order
+}
+
+func setTxCommitOrder(block *common.Block, order util.TxCommitOrder) {
+	for len(block.Metadata.Metadata) <= int(common.BlockMetadataIndex_TRANSACTIONS_COMMIT_ORDER) {
+		block.Metadata.Metadata = append(block.Metadata.Metadata, []byte{})
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_COMMIT_ORDER] = order.Bytes()
+}
+
+func clearTxCommitOrder(block *common.Block) {
+	if len(block.Metadata.Metadata) > int(common.BlockMetadataIndex_TRANSACTIONS_COMMIT_ORDER) &&
+		len(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_COMMIT_ORDER]) != 0 {
+		block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_COMMIT_ORDER] = []byte{}
+	}
+}
+
+func newBlockTxOrder(numTxs int) util.TxCommitOrder {
+	order := make(util.TxCommitOrder, numTxs)
+	for i := range order {
+		order[i] = uint64(i)
+	}
+	return order
+}
+
+func isBlockTxOrder(order util.TxCommitOrder) bool {
+	for i, txIndex := range order {
+		if txIndex != uint64(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// txDependencyGraph is the graph of the dependencies between the transactions of a block. The vertices are
+// identified by their position in txIndexes, which follows the order of the block, so that comparing two vertices
+// is the same as comparing the indexes of the corresponding transactions
+type txDependencyGraph struct {
+	txIndexes    []uint64
+	txRWSets     []*rwsetutil.TxRwSet
+	successors   []map[int]bool
+	predecessors []map[int]bool
+	removed      []bool
+}
+
+func newTxDependencyGraph() *txDependencyGraph {
+	return &txDependencyGraph{}
+}
+
+func (g *txDependencyGraph) addTx(txIndex uint64, txRWSet *rwsetutil.TxRwSet) {
+	g.txIndexes = append(g.txIndexes, txIndex)
+	g.txRWSets = append(g.txRWSets, txRWSet)
+	g.successors = append(g.successors, make(map[int]bool))
+	g.predecessors = append(g.predecessors, make(map[int]bool))
+	g.removed = append(g.removed, false)
+}
+
+func (g *txDependencyGraph) addEdge(from, to int) {
+	if from == to {
+		return
+	}
+	g.successors[from][to] = true
+	g.predecessors[to][from] = true
+}
+
+// addDependencies adds the edges between the transactions that read or write the same keys
+func (g *txDependencyGraph) addDependencies() {
+	// writers of each key, in the order of the block
+	writers := make(map[statedb.CompositeKey][]int)
+	// keys written in each namespace, used for matching the range queries
+	writtenKeys := make(map[string][]string)
+	addWriter := func(ns, key string, vertex int) {
+		compositeKey := statedb.CompositeKey{Namespace: ns, Key: key}
+		keyWriters := writers[compositeKey]
+		if len(keyWriters) == 0 {
+			writtenKeys[ns] = append(writtenKeys[ns], key)
+		} else if keyWriters[len(keyWriters)-1] == vertex {
+			return
+		}
+		writers[compositeKey] = append(keyWriters, vertex)
+	}
+	for vertex, txRWSet := range g.txRWSets {
+		for _, nsRWSet := range txRWSet.NsRwSets {
+			ns := nsRWSet.NameSpace
+			for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+				addWriter(ns, kvWrite.Key, vertex)
+			}
+			// a metadata write bumps the version of the key like a write of its value
+			for _, kvMetadataWrite := range nsRWSet.KvRwSet.MetadataWrites {
+				addWriter(ns, kvMetadataWrite.Key, vertex)
+			}
+			for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+				hashedNs := statedb.DeriveHashedDataNs(ns, collHashedRWSet.CollectionName)
+				for _, kvWriteHash := range collHashedRWSet.HashedRwSet.HashedWrites {
+					key := statedb.CompositeKey{Namespace: hashedNs, Key: statedb.EncodeHashedKey(kvWriteHash.KeyHash)}
+					writers[key] = append(writers[key], vertex)
+				}
+			}
+		}
+	}
+
+	// the writers of a key are applied in the order of the block, so that the final value and
+	// version of the key are the same as if the transactions were committed in the order of the block
+	for _, keyWriters := range writers {
+		for i, from := range keyWriters {
+			for _, to := range keyWriters[i+1:] {
+				g.addEdge(from, to)
+			}
+		}
+	}
+
+	// a transaction that reads a key is applied before the transactions that write the key
+	for vertex, txRWSet := range g.txRWSets {
+		for _, nsRWSet := range txRWSet.NsRwSets {
+			ns := nsRWSet.NameSpace
+			for _, kvRead := range nsRWSet.KvRwSet.Reads {
+				for _, writer := range writers[statedb.CompositeKey{Namespace: ns, Key: kvRead.Key}] {
+					g.addEdge(vertex, writer)
+				}
+			}
+			for _, rqi := range nsRWSet.KvRwSet.RangeQueriesInfo {
+				for _, key := range writtenKeys[ns] {
+					if !isKeyInRange(key, rqi) {
+						continue
+					}
+					for _, writer := range writers[statedb.CompositeKey{Namespace: ns, Key: key}] {
+						g.addEdge(vertex, writer)
+					}
+				}
+			}
+			for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+				hashedNs := statedb.DeriveHashedDataNs(ns, collHashedRWSet.CollectionName)
+				for _, kvReadHash := range collHashedRWSet.HashedRwSet.HashedReads {
+					key := statedb.CompositeKey{Namespace: hashedNs, Key: statedb.EncodeHashedKey(kvReadHash.KeyHash)}
+					for _, writer := range writers[key] {
+						g.addEdge(vertex, writer)
+					}
+				}
+			}
+		}
+	}
+}
+
+// isKeyInRange checks whether the key falls in the range covered by the range query, following
+// the same semantics as the phantom read validation
+func isKeyInRange(key string, rqi *kvrwset.RangeQueryInfo) bool {
+	if key < rqi.StartKey {
+		return false
+	}
+	if rqi.EndKey == "" || key < rqi.EndKey {
+		return true
+	}
+	return key == rqi.EndKey && !rqi.ItrExhausted
+}
+
+// breakCycles removes vertices from the graph until it does not contain any cycle. In each strongly connected
+// component with more than one vertex, the vertex with the highest number of edges is removed, the ties being
+// resolved in favor of the vertex that comes last in the block. The indexes of the transactions corresponding
+// to the removed vertices are returned in the order of the block
+func (g *txDependencyGraph) breakCycles() []uint64 {
+	var removedVertices []int
+	for {
+		foundCycle := false
+		for _, component := range g.stronglyConnectedComponents() {
+			if len(component) < 2 {
+				continue
+			}
+			foundCycle = true
+			victim := component[0]
+			for _, vertex := range component[1:] {
+				if g.degree(vertex) > g.degree(victim) || (g.degree(vertex) == g.degree(victim) && vertex > victim) {
+					victim = vertex
+				}
+			}
+			g.remove(victim)
+			removedVertices = append(removedVertices, victim)
+		}
+		if !foundCycle {
+			break
+		}
+	}
+	sort.Ints(removedVertices)
+	removedTxIndexes := make([]uint64, len(removedVertices))
+	for i, vertex := range removedVertices {
+		removedTxIndexes[i] = g.txIndexes[vertex]
+	}
+	return removedTxIndexes
+}
+
+func (g *txDependencyGraph) degree(vertex int) int {
+	return len(g.successors[vertex]) + len(g.predecessors[vertex])
+}
+
+func (g *txDependencyGraph) remove(vertex int) {
+	for successor := range g.successors[vertex] {
+		delete(g.predecessors[successor], vertex)
+	}
+	for predecessor := range g.predecessors[vertex] {
+		delete(g.successors[predecessor], vertex)
+	}
+	g.successors[vertex] = make(map[int]bool)
+	g.predecessors[vertex] = make(map[int]bool)
+	g.removed[vertex] = true
+}
+
+// stronglyConnectedComponents returns the strongly connected components of the graph (Tarjan's algorithm)
+func (g *txDependencyGraph) stronglyConnectedComponents() [][]int {
+	numVertices := len(g.txIndexes)
+	index := make([]int, numVertices)
+	lowLink := make([]int, numVertices)
+	onStack := make([]bool, numVertices)
+	for i := range index {
+		index[i] = -1
+	}
+	var stack []int
+	var components [][]int
+	nextIndex := 0
+
+	var visit func(vertex int)
+	visit = func(vertex int) {
+		index[vertex] = nextIndex
+		lowLink[vertex] = nextIndex
+		nextIndex++
+		stack = append(stack, vertex)
+		onStack[vertex] = true
+		for successor := range g.successors[vertex] {
+			if index[successor] == -1 {
+				visit(successor)
+				if lowLink[successor] < lowLink[vertex] {
+					lowLink[vertex] = lowLink[successor]
+				}
+			} else if onStack[successor] && index[successor] < lowLink[vertex] {
+				lowLink[vertex] = index[successor]
+			}
+		}
+		if lowLink[vertex] != index[vertex] {
+			return
+		}
+		var component []int
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == vertex {
+				break
+			}
+		}
+		sort.Ints(component)
+		components = append(components, component)
+	}
+
+	for vertex := 0; vertex < numVertices; vertex++ {
+		if !g.removed[vertex] && index[vertex] == -1 {
+			visit(vertex)
+		}
+	}
+	return components
+}
+
+// topologicalOrder returns the indexes of the transactions of the (acyclic) graph in an order in which every
+// transaction comes after its predecessors. Among the transactions that can be applied next, the one that
+// comes first in the block is always chosen so that the order of the block is retained whenever possible
+func (g *txDependencyGraph) topologicalOrder() util.TxCommitOrder {
+	inDegree := make([]int, len(g.txIndexes))
+	ready := &intHeap{}
+	for vertex := range g.txIndexes {
+		if g.removed[vertex] {
+			continue
+		}
+		inDegree[vertex] = len(g.predecessors[vertex])
+		if inDegree[vertex] == 0 {
+			heap.Push(ready, vertex)
+		}
+	}
+	var order util.TxCommitOrder
+	for ready.Len() > 0 {
+		vertex := heap.Pop(ready).(int)
+		order = append(order, g.txIndexes[vertex])
+		for successor := range g.successors[vertex] {
+			inDegree[successor]--
+			if inDegree[successor] == 0 {
+				heap.Push(ready, successor)
+			}
+		}
+	}
+	return order
+}
+
+// intHeap is a min-heap of ints implementing heap.Interface
+type intHeap []int
+
+func (h intHeap) Len() int            { return len(h) }
+func (h intHeap) Less(i, j int) bool  { return h[i] < h[j] }
+func (h intHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *intHeap) Push(x interface{}) { *h = append(*h, x.(int)) }
+func (h *intHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
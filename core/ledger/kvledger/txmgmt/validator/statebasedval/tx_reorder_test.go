@@ -0,0 +1,310 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statebasedval
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/config"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateleveldb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
+)
+
+func TestValidatorWithTxReordering(t *testing.T) {
+	testDBEnv := stateleveldb.NewTestVDBEnv(t)
+	defer testDBEnv.Cleanup()
+	db, err := testDBEnv.DBProvider.GetDBHandle("TestDB")
+	testutil.AssertNoError(t, err, "")
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 0))
+	batch.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 1))
+	db.ApplyUpdates(batch, version.NewHeight(1, 1))
+
+	// tx0 writes key1 and key3, tx1 reads key1 and tx2 writes key3
+	rwsetBuilder0 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder0.AddToWriteSet("ns1", "key1", []byte("value1_tx0"))
+	rwsetBuilder0.AddToWriteSet("ns1", "key3", []byte("value3_tx0"))
+	rwsetBuilder1 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder1.AddToReadSet("ns1", "key1", version.NewHeight(1, 0))
+	rwsetBuilder1.AddToWriteSet("ns1", "key2", []byte("value2_tx1"))
+	rwsetBuilder2 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder2.AddToWriteSet("ns1", "key3", []byte("value3_tx2"))
+	rwsets := []*rwsetutil.TxRwSet{rwsetBuilder0.GetTxReadWriteSet(), rwsetBuilder1.GetTxReadWriteSet(), rwsetBuilder2.GetTxReadWriteSet()}
+
+	// without reordering, tx1 conflicts with tx0
+	block := constructTestBlockForValidation(t, rwsets)
+	_, err = newTestValidator(t, db, false).ValidateAndPrepareBatch(block, true, nil)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, getInvalidTxs(block), []int{1})
+	testutil.AssertNil(t, getRecordedTxCommitOrder(block))
+
+	// with reordering, tx1 is applied first and all the transactions are valid
+	block = constructTestBlockForValidation(t, rwsets)
+	updates, err := newTestValidator(t, db, true).ValidateAndPrepareBatch(block, true, nil)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, getInvalidTxs(block), []int{})
+	order, err := util.NewTxCommitOrderFromBytes(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_COMMIT_ORDER])
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, order, util.TxCommitOrder{1, 0, 2})
+	testutil.AssertEquals(t, updates.Get("ns1", "key1"), &statedb.VersionedValue{Value: []byte("value1_tx0"), Version: version.NewHeight(2, 0)})
+	testutil.AssertEquals(t, updates.Get("ns1", "key2"), &statedb.VersionedValue{Value: []byte("value2_tx1"), Version: version.NewHeight(2, 1)})
+	// the writes of the same key retain the order of the block
+	testutil.AssertEquals(t, updates.Get("ns1", "key3"), &statedb.VersionedValue{Value: []byte("value3_tx2"), Version: version.NewHeight(2, 2)})
+
+	// an order recorded in a block received for commit is ignored and removed
+	block = constructTestBlockForValidation(t, rwsets)
+	setTxCommitOrder(block, util.TxCommitOrder{1, 0, 2})
+	_, err = newTestValidator(t, db, false).ValidateAndPrepareBatch(block, true, nil)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, getInvalidTxs(block), []int{1})
+	testutil.AssertNil(t, getRecordedTxCommitOrder(block))
+
+	block = constructTestBlockForValidation(t, rwsets)
+	setTxCommitOrder(block, util.TxCommitOrder{2, 1, 0})
+	_, err = newTestValidator(t, db, true).ValidateAndPrepareBatch(block, true, nil)
+	testutil.AssertNoError(t, err, "")
+	order, err = util.NewTxCommitOrderFromBytes(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_COMMIT_ORDER])
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, order, util.TxCommitOrder{1, 0, 2})
+
+	// when a committed block is replayed on recovery, the recorded order is honored only if reordering is enabled
+	block = constructTestBlockForValidation(t, rwsets)
+	setTxCommitOrder(block, util.TxCommitOrder{2, 1, 0})
+	updates, err = newTestValidator(t, db, true).ValidateAndPrepareBatch(block, false, nil)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, updates.Get("ns1", "key3"), &statedb.VersionedValue{Value: []byte("value3_tx0"), Version: version.NewHeight(2, 0)})
+
+	block = constructTestBlockForValidation(t, rwsets)
+	setTxCommitOrder(block, util.TxCommitOrder{2, 1, 0})
+	updates, err = newTestValidator(t, db, false).ValidateAndPrepareBatch(block, false, nil)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, updates.Get("ns1", "key3"), &statedb.VersionedValue{Value: []byte("value3_tx2"), Version: version.NewHeight(2, 2)})
+}
+
+func TestValidatorWithTxReorderingCycles(t *testing.T) {
+	testDBEnv := stateleveldb.NewTestVDBEnv(t)
+	defer testDBEnv.Cleanup()
+	db, err := testDBEnv.DBProvider.GetDBHandle("TestDB")
+	testutil.AssertNoError(t, err, "")
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 0))
+	batch.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 1))
+	db.ApplyUpdates(batch, version.NewHeight(1, 1))
+
+	// tx0 and tx1 form a cycle, tx2 is invalid against the committed state and tx3 reads a range written by tx0.
+	// tx0 has the most dependencies and it is the one that gets invalidated for breaking the cycle
+	rwsetBuilder0 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder0.AddToReadSet("ns1", "key1", version.NewHeight(1, 0))
+	rwsetBuilder0.AddToWriteSet("ns1", "key2", []byte("value2_tx0"))
+	rwsetBuilder1 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder1.AddToReadSet("ns1", "key2", version.NewHeight(1, 1))
+	rwsetBuilder1.AddToWriteSet("ns1", "key1", []byte("value1_tx1"))
+	rwsetBuilder2 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder2.AddToReadSet("ns1", "key1", version.NewHeight(1, 5))
+	rwsetBuilder3 := rwsetutil.NewRWSetBuilder()
+	rqi := &kvrwset.RangeQueryInfo{StartKey: "key2", EndKey: "key3", ItrExhausted: true}
+	rqi.SetRawReads([]*kvrwset.KVRead{rwsetutil.NewKVRead("key2", version.NewHeight(1, 1))})
+	rwsetBuilder3.AddToRangeQuerySet("ns1", rqi)
+	rwsets := []*rwsetutil.TxRwSet{rwsetBuilder0.GetTxReadWriteSet(), rwsetBuilder1.GetTxReadWriteSet(),
+		rwsetBuilder2.GetTxReadWriteSet(), rwsetBuilder3.GetTxReadWriteSet()}
+
+	block := constructTestBlockForValidation(t, rwsets)
+	_, err = newTestValidator(t, db, true).ValidateAndPrepareBatch(block, true, nil)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, getInvalidTxs(block), []int{0, 2})
+	txsFilter := util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	testutil.AssertEquals(t, txsFilter.Flag(0), peer.TxValidationCode_MVCC_READ_CONFLICT)
+	testutil.AssertEquals(t, txsFilter.Flag(2), peer.TxValidationCode_MVCC_READ_CONFLICT)
+	order, err := util.NewTxCommitOrderFromBytes(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_COMMIT_ORDER])
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, order, util.TxCommitOrder{1, 3, 0, 2})
+}
+
+func TestValidatorWithTxReorderingMetadataWrites(t *testing.T) {
+	testDBEnv := stateleveldb.NewTestVDBEnv(t)
+	defer testDBEnv.Cleanup()
+	db, err := testDBEnv.DBProvider.GetDBHandle("TestDB")
+	testutil.AssertNoError(t, err, "")
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 0))
+	db.ApplyUpdates(batch, version.NewHeight(1, 0))
+
+	// tx0 writes the metadata of key1, which bumps its version, and tx1 reads key1
+	rwsetBuilder0 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder0.AddToMetadataWriteSet("ns1", "key1", map[string][]byte{"entry1": []byte("metadata1")})
+	rwsetBuilder1 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder1.AddToReadSet("ns1", "key1", version.NewHeight(1, 0))
+	rwsetBuilder1.AddToWriteSet("ns1", "key2", []byte("value2_tx1"))
+	rwsets := []*rwsetutil.TxRwSet{rwsetBuilder0.GetTxReadWriteSet(), rwsetBuilder1.GetTxReadWriteSet()}
+
+	// without reordering, tx1 conflicts with tx0
+	block := constructTestBlockForValidation(t, rwsets)
+	_, err = newTestValidator(t, db, false).ValidateAndPrepareBatch(block, true, nil)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, getInvalidTxs(block), []int{1})
+
+	// with reordering, tx1 is applied before the metadata write of tx0
+	block = constructTestBlockForValidation(t, rwsets)
+	updates, err := newTestValidator(t, db, true).ValidateAndPrepareBatch(block, true, nil)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, getInvalidTxs(block), []int{})
+	order, err := util.NewTxCommitOrderFromBytes(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_COMMIT_ORDER])
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, order, util.TxCommitOrder{1, 0})
+	testutil.AssertEquals(t, updates.Get("ns1", "key1").Version, version.NewHeight(2, 0))
+	testutil.AssertEquals(t, updates.Get("ns1", "key2").Version, version.NewHeight(2, 1))
+}
+
+func TestIsTxReorderingEnabled(t *testing.T) {
+	configBlocks := map[uint64]*common.Block{1: constructTestConfigBlock(1, true), 3: constructTestConfigBlock(3, false)}
+	retrieved := 0
+	v := NewValidator(nil, func(blockNum uint64) (*common.Block, error) {
+		retrieved++
+		if configBlock, ok := configBlocks[blockNum]; ok {
+			return configBlock, nil
+		}
+		return nil, fmt.Errorf("block %d not found", blockNum)
+	})
+
+	block := constructTestBlockForValidation(t, nil)
+	enabled, err := v.isTxReorderingEnabled(block)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, enabled, true)
+	// the config block is only read once
+	enabled, err = v.isTxReorderingEnabled(block)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, enabled, true)
+	testutil.AssertEquals(t, retrieved, 1)
+
+	// a config update disables the reordering for the following blocks
+	setLastConfigIndex(block, 3)
+	enabled, err = v.isTxReorderingEnabled(block)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, enabled, false)
+
+	// the config block which is being validated applies to itself
+	configBlock := constructTestConfigBlock(4, true)
+	enabled, err = v.isTxReorderingEnabled(configBlock)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, enabled, true)
+
+	// a config without TxReordering disables it
+	configBlock = constructTestConfigBlock(5, true)
+	configEnv := &common.ConfigEnvelope{Config: &common.Config{ChannelGroup: common.NewConfigGroup()}}
+	payload := putils.UnmarshalPayloadOrPanic(putils.ExtractEnvelopeOrPanic(configBlock, 0).Payload)
+	payload.Data = putils.MarshalOrPanic(configEnv)
+	configBlock.Data.Data = [][]byte{putils.MarshalOrPanic(&common.Envelope{Payload: putils.MarshalOrPanic(payload)})}
+	enabled, err = v.isTxReorderingEnabled(configBlock)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, enabled, false)
+
+	// a block referencing itself or a later block as config block is not reordered
+	block = constructTestBlockForValidation(t, nil)
+	setLastConfigIndex(block, 2)
+	enabled, err = v.isTxReorderingEnabled(block)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, enabled, false)
+	setLastConfigIndex(block, 6)
+	enabled, err = v.isTxReorderingEnabled(block)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, enabled, false)
+
+	// a block which does not reference a config block is not reordered
+	block = constructTestBlockForValidation(t, nil)
+	block.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG] = nil
+	enabled, err = v.isTxReorderingEnabled(block)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, enabled, false)
+
+	// the config block cannot be retrieved
+	delete(configBlocks, 1)
+	setLastConfigIndex(block, 1)
+	_, err = v.isTxReorderingEnabled(block)
+	testutil.AssertError(t, err, "")
+}
+
+func TestIsKeyInRange(t *testing.T) {
+	testutil.AssertEquals(t, isKeyInRange("key1", &kvrwset.RangeQueryInfo{StartKey: "key1", EndKey: "key3"}), true)
+	testutil.AssertEquals(t, isKeyInRange("key0", &kvrwset.RangeQueryInfo{StartKey: "key1", EndKey: "key3"}), false)
+	testutil.AssertEquals(t, isKeyInRange("key3", &kvrwset.RangeQueryInfo{StartKey: "key1", EndKey: "key3", ItrExhausted: true}), false)
+	testutil.AssertEquals(t, isKeyInRange("key3", &kvrwset.RangeQueryInfo{StartKey: "key1", EndKey: "key3", ItrExhausted: false}), true)
+	testutil.AssertEquals(t, isKeyInRange("key9", &kvrwset.RangeQueryInfo{StartKey: "key1", EndKey: ""}), true)
+}
+
+func constructTestBlockForValidation(t *testing.T, rwsets []*rwsetutil.TxRwSet) *common.Block {
+	simulationResults := [][]byte{}
+	for _, txRWS := range rwsets {
+		sr, err := txRWS.ToProtoBytes()
+		testutil.AssertNoError(t, err, "")
+		simulationResults = append(simulationResults, sr)
+	}
+	block := testutil.ConstructBlock(t, 2, []byte("dummyPreviousHash"), simulationResults, false)
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = util.NewTxValidationFlags(len(block.Data.Data))
+	setLastConfigIndex(block, 1)
+	return block
+}
+
+func setLastConfigIndex(block *common.Block, configBlockNum uint64) {
+	block.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG] = putils.MarshalOrPanic(&common.Metadata{
+		Value: putils.MarshalOrPanic(&common.LastConfig{Index: configBlockNum}),
+	})
+}
+
+func constructTestConfigBlock(blockNum uint64, reorderTxs bool) *common.Block {
+	configEnv := &common.ConfigEnvelope{Config: &common.Config{ChannelGroup: config.TemplateTxReordering(reorderTxs)}}
+	payload := &common.Payload{
+		Header: putils.MakePayloadHeader(putils.MakeChannelHeader(common.HeaderType_CONFIG, 0, "testchain", 0), &common.SignatureHeader{}),
+		Data:   putils.MarshalOrPanic(configEnv),
+	}
+	block := common.NewBlock(blockNum, nil)
+	block.Data.Data = [][]byte{putils.MarshalOrPanic(&common.Envelope{Payload: putils.MarshalOrPanic(payload)})}
+	setLastConfigIndex(block, blockNum)
+	return block
+}
+
+// newTestValidator returns a validator reading whether the transactions are reordered
+// from the config block 1, which is referenced by the blocks built for the tests
+func newTestValidator(t *testing.T, db statedb.VersionedDB, reorderTxs bool) *Validator {
+	configBlock := constructTestConfigBlock(1, reorderTxs)
+	return NewValidator(db, func(blockNum uint64) (*common.Block, error) {
+		testutil.AssertEquals(t, blockNum, uint64(1))
+		return configBlock, nil
+	})
+}
+
+func getInvalidTxs(block *common.Block) []int {
+	txsFilter := util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	invalidTxs := []int{}
+	for i := 0; i < len(block.Data.Data); i++ {
+		if txsFilter.IsInvalid(i) {
+			invalidTxs = append(invalidTxs, i)
+		}
+	}
+	return invalidTxs
+}
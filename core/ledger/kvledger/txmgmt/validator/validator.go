@@ -17,11 +17,14 @@ limitations under the License.
 package validator
 
 import (
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/protos/common"
 )
 
 // Validator validates a rwset
+// pvtData contains the private data of the transactions of the block that is available at the peer,
+// keyed by the sequence of the transaction in the block. It may be nil
 type Validator interface {
-	ValidateAndPrepareBatch(block *common.Block, doMVCCValidation bool) (*statedb.UpdateBatch, error)
+	ValidateAndPrepareBatch(block *common.Block, doMVCCValidation bool, pvtData map[uint64]*ledger.TxPvtData) (*statedb.UpdateBatch, error)
 }
@@ -18,7 +18,10 @@ package ledger
 
 import (
 	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/core/ledger/blobstore"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 	"github.com/hyperledger/fabric/protos/peer"
 )
 
@@ -28,8 +31,17 @@ type PeerLedgerProvider interface {
 	// This function guarentees that the creation of ledger and committing the genesis block would an atomic action
 	// The chain id retrieved from the genesis block is treated as a ledger id
 	Create(genesisBlock *common.Block) (PeerLedger, error)
+	// CreateFromSnapshot creates a new ledger from a snapshot exported by `PeerLedger.ExportSnapshot`.
+	// The created ledger starts at the height of the snapshot and the blocks prior to that height are not available.
+	// The ledger id is retrieved from the snapshot metadata
+	CreateFromSnapshot(snapshotDir string) (PeerLedger, error)
 	// Open opens an already created ledger
 	Open(ledgerID string) (PeerLedger, error)
+	// VerifyIntegrity verifies the block files of a ledger and cross-checks the block index, the state database
+	// and the history database against them. The ledger should not be open while verifying it
+	VerifyIntegrity(ledgerID string) (*IntegrityReport, error)
+	// Repair rebuilds the given stores of a ledger from its block files. The ledger should not be open while repairing it
+	Repair(ledgerID string, options *RepairOptions) error
 	// Exists tells whether the ledger with given id exists
 	Exists(ledgerID string) (bool, error)
 	// List lists the ids of the existing ledgers
@@ -38,6 +50,14 @@ type PeerLedgerProvider interface {
 	Close()
 }
 
+// StateListener is notified by a ledger of the committed writes to the namespace it is registered for
+type StateListener interface {
+	// HandleStateUpdates is invoked once the state changes of a block are committed to the ledger (or recommitted
+	// while recovering it), with the writes of the valid transactions of the block to the namespace. The listener
+	// may query the ledger and create indexes on it, but must not commit to it
+	HandleStateUpdates(ledgerID string, lgr PeerLedger, writes []*kvrwset.KVWrite) error
+}
+
 // PeerLedger differs from the OrdererLedger in that PeerLedger locally maintain a bitmask
 // that tells apart valid transactions from invalid ones
 type PeerLedger interface {
@@ -59,12 +79,30 @@ type PeerLedger interface {
 	// A client can obtain more than one 'QueryExecutor's for parallel execution.
 	// Any synchronization should be performed at the implementation level if required
 	NewQueryExecutor() (QueryExecutor, error)
+	// NewReadRecordingQueryExecutor gives handle to a query executor that records what it reads.
+	// It is used for reading the state of this ledger on behalf of a transaction of another channel
+	NewReadRecordingQueryExecutor() (ReadRecordingQueryExecutor, error)
 	// NewHistoryQueryExecutor gives handle to a history query executor.
 	// A client can obtain more than one 'HistoryQueryExecutor's for parallel execution.
 	// Any synchronization should be performed at the implementation level if required
 	NewHistoryQueryExecutor() (HistoryQueryExecutor, error)
 	//Prune prunes the blocks/transactions that satisfy the given policy
 	Prune(policy commonledger.PrunePolicy) error
+	// ExportSnapshot exports a consistent snapshot of the ledger at its current height into the given directory.
+	// The snapshot contains the state, the history (if enabled), and the last block of the chain.
+	// Commits to the ledger are blocked while the snapshot is being exported
+	ExportSnapshot(snapshotDir string) error
+	// CreateChaincodeIndexes creates in the state database the indexes packaged with a chaincode.
+	// indexes maps a type of state database (e.g., "couchdb") to the index definitions keyed by file name.
+	// The index definitions for a type other than the one of the state database in use are ignored
+	CreateChaincodeIndexes(namespace string, indexes map[string]map[string][]byte) error
+	// GetBlobStore returns the blob store holding the state values streamed by the chaincodes.
+	// The blobs referenced by the writes of the valid transactions are added to it when a block is committed
+	GetBlobStore() blobstore.Store
+	// CommitWithPvtData commits the block and the private data of its transactions.
+	// The private data is applied only for the valid transactions and only if it matches
+	// the hashes present in the transaction. The block itself never carries the private data
+	CommitWithPvtData(blockAndPvtdata *BlockAndPvtData) error
 }
 
 // ValidatedLedger represents the 'final ledger' after filtering out invalid transactions from PeerLedger.
@@ -83,38 +121,83 @@ type QueryExecutor interface {
 	GetState(namespace string, key string) ([]byte, error)
 	// GetStateMultipleKeys gets the values for multiple keys in a single call
 	GetStateMultipleKeys(namespace string, keys []string) ([][]byte, error)
+	// GetStateMetadata returns the metadata for given namespace and key. The metadata is nil if the key does not
+	// exist or does not carry any metadata
+	GetStateMetadata(namespace, key string) (map[string][]byte, error)
+	// GetPrivateData gets the value of a private data item identified by a tuple <namespace, collection, key>
+	GetPrivateData(namespace, collection, key string) ([]byte, error)
 	// GetStateRangeScanIterator returns an iterator that contains all the key-values between given key ranges.
 	// startKey is included in the results and endKey is excluded. An empty startKey refers to the first available key
 	// and an empty endKey refers to the last available key. For scanning all the keys, both the startKey and the endKey
 	// can be supplied as empty strings. However, a full scan shuold be used judiciously for performance reasons.
 	// The returned ResultsIterator contains results of type *KV which is defined in protos/ledger/queryresult.
 	GetStateRangeScanIterator(namespace string, startKey string, endKey string) (commonledger.ResultsIterator, error)
+	// GetStateRangeScanIteratorWithPagination is similar to GetStateRangeScanIterator except that the returned iterator
+	// contains at most pageSize results. The bookmark returned by the iterator can be supplied as the startKey for
+	// retrieving the next page.
+	GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string, pageSize int32) (QueryResultsIterator, error)
 	// ExecuteQuery executes the given query and returns an iterator that contains results of type specific to the underlying data store.
 	// Only used for state databases that support query
 	// For a chaincode, the namespace corresponds to the chaincodeId
 	// The returned ResultsIterator contains results of type *KV which is defined in protos/ledger/queryresult.
 	ExecuteQuery(namespace, query string) (commonledger.ResultsIterator, error)
+	// ExecuteQueryWithPagination is similar to ExecuteQuery except that the returned iterator contains at most pageSize results.
+	// The bookmark is expected to be either empty (for the first page) or the one returned by the iterator for the previous page.
+	ExecuteQueryWithPagination(namespace, query, bookmark string, pageSize int32) (QueryResultsIterator, error)
 	// Done releases resources occupied by the QueryExecutor
 	Done()
 }
 
+// ReadRecordingQueryExecutor is a QueryExecutor that records the keys it reads along with their
+// committed versions, as a TxSimulator does. Unlike a TxSimulator it cannot write
+type ReadRecordingQueryExecutor interface {
+	QueryExecutor
+	// GetReadSet returns the reads performed so far as a serialized TxReadWriteSet that has no writes.
+	// The query executor is done after this call
+	GetReadSet() ([]byte, error)
+}
+
+// QueryResultsIterator is an iterator over a single page of the results of a paginated query
+type QueryResultsIterator interface {
+	commonledger.ResultsIterator
+	// GetBookmarkAndClose returns the bookmark for retrieving the next page and releases the iterator.
+	// An empty bookmark indicates that there are no more results
+	GetBookmarkAndClose() string
+}
+
 // HistoryQueryExecutor executes the history queries
 type HistoryQueryExecutor interface {
 	// GetHistoryForKey retrieves the history of values for a key.
 	// The returned ResultsIterator contains results of type *KeyModification which is defined in protos/ledger/queryresult.
 	GetHistoryForKey(namespace string, key string) (commonledger.ResultsIterator, error)
+	// GetHistoryForKeyWithOptions retrieves the history of values for a key, restricted to the block range and
+	// to the time range of the options. The results are ordered from the oldest to the newest, unless the options
+	// ask for the reverse order, and are at most options.Limit (if not zero).
+	// The returned ResultsIterator contains results of type *KeyModification which is defined in protos/ledger/queryresult.
+	GetHistoryForKeyWithOptions(namespace string, key string, options *peer.HistoryQueryOptions) (commonledger.ResultsIterator, error)
 }
 
 // TxSimulator simulates a transaction on a consistent snapshot of the 'as recent state as possible'
 // Set* methods are for supporting KV-based data model. ExecuteUpdate method is for supporting a rich datamodel and query support
 type TxSimulator interface {
 	QueryExecutor
-	// SetState sets the given value for the given namespace and key. For a chaincode, the namespace corresponds to the chaincodeId
+	// SetState sets the given value for the given namespace and key. For a chaincode, the namespace corresponds to the chaincodeId.
+	// The paginated queries and the writes are not allowed in the same transaction
 	SetState(namespace string, key string, value []byte) error
 	// DeleteState deletes the given namespace and key
 	DeleteState(namespace string, key string) error
+	// SetStateMetadata sets the metadata associated with an existing key-tuple <namespace, key>.
+	// The metadata replaces the existing metadata of the key, if any
+	SetStateMetadata(namespace, key string, metadata map[string][]byte) error
+	// DeleteStateMetadata deletes the metadata (if any) associated with an existing key-tuple <namespace, key>
+	DeleteStateMetadata(namespace, key string) error
 	// SetMultipleKeys sets the values for multiple keys in a single call
 	SetStateMultipleKeys(namespace string, kvs map[string][]byte) error
+	// SetPrivateData sets the given value to a key in the private data state represented by the tuple <namespace, collection, key>.
+	// Only the hashes of the key and the value go into the results returned by GetTxSimulationResults
+	SetPrivateData(namespace, collection, key string, value []byte) error
+	// DeletePrivateData deletes the given tuple <namespace, collection, key> from private data
+	DeletePrivateData(namespace, collection, key string) error
 	// ExecuteUpdate for supporting rich data model (see comments on QueryExecutor above)
 	ExecuteUpdate(query string) error
 	// GetTxSimulationResults encapsulates the results of the transaction simulation.
@@ -125,4 +208,59 @@ type TxSimulator interface {
 	// Different ledger implementation (or configurations of a single implementation) may want to represent the above two pieces
 	// of information in different way in order to support different data-models or optimize the information representations.
 	GetTxSimulationResults() ([]byte, error)
+	// GetTxPvtSimulationResults returns the private read-write set of the transaction, i.e., the private data written
+	// by the transaction that is kept off the block. It returns nil if the transaction did not write any private data
+	GetTxPvtSimulationResults() (*rwset.TxPvtReadWriteSet, error)
+}
+
+// TxPvtData encapsulates the private read-write set of a transaction and the sequence of the transaction in the block
+type TxPvtData struct {
+	SeqInBlock uint64
+	WriteSet   *rwset.TxPvtReadWriteSet
+}
+
+// BlockAndPvtData encapsulates a block and the private data of its transactions keyed by the sequence of
+// the transaction in the block. The map is expected to contain entries only for the transactions whose
+// private data is available at the peer
+type BlockAndPvtData struct {
+	Block        *common.Block
+	BlockPvtData map[uint64]*TxPvtData
+}
+
+// IntegrityReport lists the inconsistencies found by `PeerLedgerProvider.VerifyIntegrity`
+type IntegrityReport struct {
+	LedgerID string
+	// Height is the height of the blockchain as found in the block files
+	Height uint64
+	// BlockIssues lists the blocks that cannot be read or that break the hash chain.
+	// These cannot be repaired from the block files of the ledger itself
+	BlockIssues []string
+	// BlockIndexIssues lists the block index entries that do not agree with the block files
+	BlockIndexIssues []string
+	// StateDBIssues lists the reasons for which the state database cannot be brought in sync with the block files
+	StateDBIssues []string
+	// HistoryDBIssues lists the reasons for which the history database cannot be brought in sync with the block files
+	HistoryDBIssues []string
+}
+
+// IsConsistent returns true if no inconsistency was found
+func (r *IntegrityReport) IsConsistent() bool {
+	return len(r.BlockIssues) == 0 && len(r.BlockIndexIssues) == 0 &&
+		len(r.StateDBIssues) == 0 && len(r.HistoryDBIssues) == 0
+}
+
+// RepairOptions returns the options for rebuilding the stores that are found inconsistent
+func (r *IntegrityReport) RepairOptions() *RepairOptions {
+	return &RepairOptions{
+		RebuildBlockIndex: len(r.BlockIndexIssues) > 0,
+		RebuildStateDB:    len(r.StateDBIssues) > 0,
+		RebuildHistoryDB:  len(r.HistoryDBIssues) > 0,
+	}
+}
+
+// RepairOptions specifies the stores of a ledger that are rebuilt from the block files by `PeerLedgerProvider.Repair`
+type RepairOptions struct {
+	RebuildBlockIndex bool
+	RebuildStateDB    bool
+	RebuildHistoryDB  bool
 }
@@ -17,19 +17,30 @@ limitations under the License.
 package ledgerconfig
 
 import (
+	"fmt"
 	"path/filepath"
+	"time"
 
+	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/core/config"
 	"github.com/spf13/viper"
 )
 
-//IsCouchDBEnabled exposes the useCouchDB variable
-func IsCouchDBEnabled() bool {
+// GetStateDatabase returns the name of the state database, i.e., the name under which
+// the VersionedDBProvider to be used by the ledger is registered. The ledger fails to
+// initialize if no VersionedDBProvider is registered under that name
+func GetStateDatabase() string {
 	stateDatabase := viper.GetString("ledger.state.stateDatabase")
-	if stateDatabase == "CouchDB" {
-		return true
+	// if stateDatabase was unset, default to goleveldb
+	if stateDatabase == "" {
+		stateDatabase = "goleveldb"
 	}
-	return false
+	return stateDatabase
+}
+
+//IsCouchDBEnabled exposes the useCouchDB variable
+func IsCouchDBEnabled() bool {
+	return GetStateDatabase() == "CouchDB"
 }
 
 // GetRootPath returns the filesystem path.
@@ -54,6 +65,23 @@ func GetHistoryLevelDBPath() string {
 	return filepath.Join(GetRootPath(), "historyLeveldb")
 }
 
+// GetTransientStorePath returns the filesystem path that is used to maintain the transient store of private data
+func GetTransientStorePath() string {
+	return filepath.Join(GetRootPath(), "transientStore")
+}
+
+// GetBlobStorePath returns the filesystem path that is used to maintain the blob store of the state values
+// streamed by the chaincodes
+func GetBlobStorePath() string {
+	return filepath.Join(GetRootPath(), "blobStore")
+}
+
+// GetBlobStoreIndexPath returns the filesystem path that is used to maintain the level db indexing the blobs
+// referenced by the state
+func GetBlobStoreIndexPath() string {
+	return filepath.Join(GetRootPath(), "blobStoreIndex")
+}
+
 // GetBlockStorePath returns the filesystem path that is used for the chain block stores
 func GetBlockStorePath() string {
 	return filepath.Join(GetRootPath(), "chains")
@@ -64,6 +92,49 @@ func GetMaxBlockfileSize() int {
 	return 64 * 1024 * 1024
 }
 
+// GetBlockPrunePolicy returns the policy for pruning the blocks of the chains.
+// A nil policy is returned if pruning is not enabled
+func GetBlockPrunePolicy() (commonledger.PrunePolicy, error) {
+	policy := viper.GetString("ledger.blockchain.pruning.policy")
+	switch policy {
+	case "", "none":
+		return nil, nil
+	case "keepLastNBlocks":
+		return commonledger.KeepLastNBlocks{NumBlocks: uint64(viper.GetInt("ledger.blockchain.pruning.numBlocks"))}, nil
+	case "keepBlocksNewerThan":
+		retentionPeriod := viper.GetDuration("ledger.blockchain.pruning.retentionPeriod")
+		if retentionPeriod <= 0 {
+			return nil, fmt.Errorf("Invalid retention period [%s] for prune policy [%s]", retentionPeriod, policy)
+		}
+		return commonledger.KeepBlocksNewerThan{Time: time.Now().Add(-retentionPeriod)}, nil
+	case "keepBlocksSinceLastConfig":
+		return commonledger.KeepBlocksSinceLastConfig{}, nil
+	default:
+		return nil, fmt.Errorf("Unknown prune policy [%s]", policy)
+	}
+}
+
+// GetBlockPruneInterval returns the number of blocks after which the pruning of the blocks is attempted
+func GetBlockPruneInterval() uint64 {
+	interval := viper.GetInt("ledger.blockchain.pruning.interval")
+	// if interval was unset, default to 100
+	if interval <= 0 {
+		interval = 100
+	}
+	return uint64(interval)
+}
+
+// GetTransientStoreMaxBlockRetention returns the number of blocks for which the private data of a
+// transaction is retained in the transient store when the transaction does not get committed
+func GetTransientStoreMaxBlockRetention() uint64 {
+	retention := viper.GetInt("ledger.pvtdata.transientStoreMaxBlockRetention")
+	// if retention was unset, default to 1000
+	if retention <= 0 {
+		retention = 1000
+	}
+	return uint64(retention)
+}
+
 //GetQueryLimit exposes the queryLimit variable
 func GetQueryLimit() int {
 	queryLimit := viper.GetInt("ledger.state.queryLimit")
@@ -18,7 +18,9 @@ package ledgerconfig
 
 import (
 	"testing"
+	"time"
 
+	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	ledgertestutil "github.com/hyperledger/fabric/core/ledger/testutil"
 	"github.com/spf13/viper"
@@ -68,7 +70,57 @@ func TestIsHistoryDBEnabledFalse(t *testing.T) {
 	testutil.AssertEquals(t, updatedValue, false) //test config returns false
 }
 
+func TestGetBlockPrunePolicy(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	policy, err := GetBlockPrunePolicy()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, policy) //test default config disables pruning
+	testutil.AssertEquals(t, GetBlockPruneInterval(), uint64(100))
+
+	viper.Set("ledger.blockchain.pruning.policy", "keepLastNBlocks")
+	viper.Set("ledger.blockchain.pruning.numBlocks", 50)
+	policy, err = GetBlockPrunePolicy()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, policy, commonledger.KeepLastNBlocks{NumBlocks: 50})
+
+	viper.Set("ledger.blockchain.pruning.policy", "keepBlocksNewerThan")
+	viper.Set("ledger.blockchain.pruning.retentionPeriod", "1h")
+	policy, err = GetBlockPrunePolicy()
+	testutil.AssertNoError(t, err, "")
+	newerThan := policy.(commonledger.KeepBlocksNewerThan)
+	testutil.AssertEquals(t, newerThan.Time.Before(time.Now().Add(-59*time.Minute)), true)
+
+	viper.Set("ledger.blockchain.pruning.policy", "keepBlocksSinceLastConfig")
+	policy, err = GetBlockPrunePolicy()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, policy, commonledger.KeepBlocksSinceLastConfig{})
+
+	viper.Set("ledger.blockchain.pruning.policy", "keepNothing")
+	_, err = GetBlockPrunePolicy()
+	testutil.AssertError(t, err, "Expected an error for an unknown policy")
+}
+
 func setUpCoreYAMLConfig() {
 	//call a helper method to load the core.yaml
 	ledgertestutil.SetupCoreYAMLConfig()
 }
+
+func TestGetTransientStoreMaxBlockRetention(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	testutil.AssertEquals(t, GetTransientStoreMaxBlockRetention(), uint64(1000))
+	viper.Set("ledger.pvtdata.transientStoreMaxBlockRetention", 10)
+	testutil.AssertEquals(t, GetTransientStoreMaxBlockRetention(), uint64(10))
+}
+
+func TestGetStateDatabase(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	testutil.AssertEquals(t, GetStateDatabase(), "goleveldb")
+	viper.Set("ledger.state.stateDatabase", "")
+	testutil.AssertEquals(t, GetStateDatabase(), "goleveldb")
+	viper.Set("ledger.state.stateDatabase", "mydb")
+	testutil.AssertEquals(t, GetStateDatabase(), "mydb")
+	testutil.AssertEquals(t, IsCouchDBEnabled(), false)
+}
@@ -18,6 +18,7 @@ package ledgermgmt
 
 import (
 	"errors"
+	"math"
 	"sync"
 
 	"fmt"
@@ -45,18 +46,24 @@ var once sync.Once
 
 // Initialize initializes ledgermgmt
 func Initialize() {
+	InitializeWithStateListeners(nil)
+}
+
+// InitializeWithStateListeners initializes ledgermgmt with the listeners, keyed by namespace,
+// that the ledgers notify of the committed writes to their namespace
+func InitializeWithStateListeners(stateListeners map[string]ledger.StateListener) {
 	once.Do(func() {
-		initialize()
+		initialize(stateListeners)
 	})
 }
 
-func initialize() {
+func initialize(stateListeners map[string]ledger.StateListener) {
 	logger.Info("Initializing ledger mgmt")
 	lock.Lock()
 	defer lock.Unlock()
 	initialized = true
 	openedLedgers = make(map[string]ledger.PeerLedger)
-	provider, err := kvledger.NewProvider()
+	provider, err := kvledger.NewProviderWithStateListeners(stateListeners)
 	if err != nil {
 		panic(fmt.Errorf("Error in instantiating ledger provider: %s", err))
 	}
@@ -89,6 +96,35 @@ func CreateLedger(genesisBlock *common.Block) (ledger.PeerLedger, error) {
 	return l, nil
 }
 
+// CreateLedgerFromSnapshot creates a new ledger from the snapshot present in the given directory.
+// The ledger id is retrieved from the snapshot
+func CreateLedgerFromSnapshot(snapshotDir string) (ledger.PeerLedger, error) {
+	lock.Lock()
+	defer lock.Unlock()
+	if !initialized {
+		return nil, ErrLedgerMgmtNotInitialized
+	}
+	logger.Infof("Creating ledger from snapshot [%s]", snapshotDir)
+	l, err := ledgerProvider.CreateFromSnapshot(snapshotDir)
+	if err != nil {
+		return nil, err
+	}
+	lastBlock, err := l.GetBlockByNumber(math.MaxUint64)
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	id, err := utils.GetChainIDFromBlock(lastBlock)
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	l = wrapLedger(id, l)
+	openedLedgers[id] = l
+	logger.Infof("Created ledger [%s] from snapshot at height [%d]", id, lastBlock.Header.Number+1)
+	return l, nil
+}
+
 // OpenLedger returns a ledger for the given id
 func OpenLedger(id string) (ledger.PeerLedger, error) {
 	logger.Infof("Opening ledger with id = %s", id)
@@ -111,6 +147,36 @@ func OpenLedger(id string) (ledger.PeerLedger, error) {
 	return l, nil
 }
 
+// VerifyLedger verifies the integrity of the stores of the ledger with the given id against its block files.
+// The ledger should not be opened
+func VerifyLedger(id string) (*ledger.IntegrityReport, error) {
+	lock.Lock()
+	defer lock.Unlock()
+	if !initialized {
+		return nil, ErrLedgerMgmtNotInitialized
+	}
+	if _, ok := openedLedgers[id]; ok {
+		return nil, ErrLedgerAlreadyOpened
+	}
+	logger.Infof("Verifying ledger [%s]", id)
+	return ledgerProvider.VerifyIntegrity(id)
+}
+
+// RepairLedger rebuilds the given stores of the ledger with the given id from its block files.
+// The ledger should not be opened
+func RepairLedger(id string, options *ledger.RepairOptions) error {
+	lock.Lock()
+	defer lock.Unlock()
+	if !initialized {
+		return ErrLedgerMgmtNotInitialized
+	}
+	if _, ok := openedLedgers[id]; ok {
+		return ErrLedgerAlreadyOpened
+	}
+	logger.Infof("Repairing ledger [%s] with options %+v", id, *options)
+	return ledgerProvider.Repair(id, options)
+}
+
 // GetLedgerIDs returns the ids of the ledgers created
 func GetLedgerIDs() ([]string, error) {
 	lock.Lock()
@@ -61,12 +61,37 @@ func TestLedgerMgmt(t *testing.T) {
 	// close all opened ledgers and ledger mgmt
 	Close()
 	// Restart ledger mgmt with existing ledgers
-	initialize()
+	initialize(nil)
 	l, err = OpenLedger(ledgerID)
 	testutil.AssertNoError(t, err, "")
 	Close()
 }
 
+func TestVerifyAndRepairLedger(t *testing.T) {
+	InitializeTestEnv()
+	defer CleanupTestEnv()
+
+	ledgerID := constructTestLedgerID(0)
+	gb, _ := test.MakeGenesisBlock(ledgerID)
+	l, err := CreateLedger(gb)
+	testutil.AssertNoError(t, err, "")
+	_, err = VerifyLedger(ledgerID)
+	testutil.AssertEquals(t, err, ErrLedgerAlreadyOpened)
+	testutil.AssertEquals(t, RepairLedger(ledgerID, &ledger.RepairOptions{RebuildBlockIndex: true}), ErrLedgerAlreadyOpened)
+	l.Close()
+
+	report, err := VerifyLedger(ledgerID)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, report.IsConsistent(), true)
+	testutil.AssertEquals(t, report.Height, uint64(1))
+	options := &ledger.RepairOptions{RebuildBlockIndex: true, RebuildStateDB: true, RebuildHistoryDB: true}
+	testutil.AssertNoError(t, RepairLedger(ledgerID, options), "")
+	report, err = VerifyLedger(ledgerID)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, report.IsConsistent(), true)
+	Close()
+}
+
 func constructTestLedgerID(i int) string {
 	return fmt.Sprintf("ledger_%06d", i)
 }
@@ -91,6 +91,7 @@ func ResetConfigToDefaultValues() {
 	//reset to defaults
 	viper.Set("ledger.state.stateDatabase", "goleveldb")
 	viper.Set("ledger.history.enableHistoryDatabase", false)
+	viper.Set("ledger.blockchain.pruning.policy", "none")
 }
 
 // SetLogLevel sets up log level
@@ -162,9 +162,10 @@ type Attachment struct {
 
 //DocMetadata returns the ID, version and revision for a couchdb document
 type DocMetadata struct {
-	ID      string
-	Rev     string
-	Version string
+	ID       string
+	Rev      string
+	Version  string
+	Metadata string
 }
 
 //FileDetails defines the structure needed to send an attachment to couchdb
@@ -185,9 +186,10 @@ type BatchRetrieveDocMedatadataResponse struct {
 	Rows []struct {
 		ID  string `json:"id"`
 		Doc struct {
-			ID      string `json:"_id"`
-			Rev     string `json:"_rev"`
-			Version string `json:"version"`
+			ID       string `json:"_id"`
+			Rev      string `json:"_rev"`
+			Version  string `json:"version"`
+			Metadata string `json:"~metadata"`
 		} `json:"doc"`
 	} `json:"rows"`
 }
@@ -1010,6 +1012,168 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, err
 
 }
 
+// IndexResult contains the definition for a couchdb index
+type IndexResult struct {
+	DesignDocument string `json:"designdoc"`
+	Name           string `json:"name"`
+	Definition     string `json:"definition"`
+}
+
+// CreateIndexResponse contains the index creation response from CouchDB
+type CreateIndexResponse struct {
+	Result string `json:"result"`
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+}
+
+//listIndexResponse is used for processing REST index list responses from CouchDB
+type listIndexResponse struct {
+	TotalRows int `json:"total_rows"`
+	Indexes   []struct {
+		DesignDocument string          `json:"ddoc"`
+		Name           string          `json:"name"`
+		Type           string          `json:"type"`
+		Definition     json.RawMessage `json:"def"`
+	} `json:"indexes"`
+}
+
+//ListIndex method lists the defined indexes for a database
+func (dbclient *CouchDatabase) ListIndex() ([]*IndexResult, error) {
+
+	logger.Debugf("Entering ListIndex()")
+
+	indexURL, err := url.Parse(dbclient.CouchInstance.conf.URL)
+	if err != nil {
+		logger.Errorf("URL parse error: %s", err.Error())
+		return nil, err
+	}
+
+	indexURL.Path = dbclient.DBName + "/_index/"
+
+	//get the number of retries
+	maxRetries := dbclient.CouchInstance.conf.MaxRetries
+
+	resp, _, err := dbclient.CouchInstance.handleRequest(http.MethodGet, indexURL.String(), nil, "", "", maxRetries)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+
+	//handle as JSON document
+	jsonResponseRaw, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var jsonResponse = &listIndexResponse{}
+
+	err = json.Unmarshal(jsonResponseRaw, jsonResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*IndexResult
+
+	for _, row := range jsonResponse.Indexes {
+
+		//the special index "_all_docs" has no design document and is maintained by couchdb
+		if row.DesignDocument == "" {
+			continue
+		}
+
+		//strip the "_design/" prefix from the design document name
+		designDoc := strings.TrimPrefix(row.DesignDocument, "_design/")
+
+		results = append(results, &IndexResult{DesignDocument: designDoc, Name: row.Name, Definition: string(row.Definition)})
+	}
+
+	logger.Debugf("Exiting ListIndex()")
+
+	return results, nil
+
+}
+
+//CreateIndex method provides a function creating an index
+func (dbclient *CouchDatabase) CreateIndex(indexdefinition string) (*CreateIndexResponse, error) {
+
+	logger.Debugf("Entering CreateIndex()  indexdefinition=%s", indexdefinition)
+
+	//Test to see if this is a valid JSON
+	if IsJSON(indexdefinition) != true {
+		return nil, fmt.Errorf("JSON format is not valid")
+	}
+
+	indexURL, err := url.Parse(dbclient.CouchInstance.conf.URL)
+	if err != nil {
+		logger.Errorf("URL parse error: %s", err.Error())
+		return nil, err
+	}
+
+	indexURL.Path = dbclient.DBName + "/_index"
+
+	//get the number of retries
+	maxRetries := dbclient.CouchInstance.conf.MaxRetries
+
+	resp, _, err := dbclient.CouchInstance.handleRequest(http.MethodPost, indexURL.String(), []byte(indexdefinition), "", "", maxRetries)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+
+	//Read the response body
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	couchDBReturn := &CreateIndexResponse{}
+
+	//Unmarshal the response
+	err = json.Unmarshal(respBody, couchDBReturn)
+	if err != nil {
+		return nil, err
+	}
+
+	if couchDBReturn.Result == "created" {
+		logger.Infof("Created CouchDB index [%s] in state database [%s] using design document [%s]", couchDBReturn.Name, dbclient.DBName, couchDBReturn.ID)
+	} else {
+		logger.Infof("Updated CouchDB index [%s] in state database [%s] using design document [%s]", couchDBReturn.Name, dbclient.DBName, couchDBReturn.ID)
+	}
+
+	logger.Debugf("Exiting CreateIndex()")
+
+	return couchDBReturn, nil
+
+}
+
+//DeleteIndex method provides a function deleting an index
+func (dbclient *CouchDatabase) DeleteIndex(designdoc, indexname string) error {
+
+	logger.Debugf("Entering DeleteIndex()  designdoc=%s  indexname=%s", designdoc, indexname)
+
+	indexURL, err := url.Parse(dbclient.CouchInstance.conf.URL)
+	if err != nil {
+		logger.Errorf("URL parse error: %s", err.Error())
+		return err
+	}
+
+	indexURL.Path = dbclient.DBName + "/_index/" + designdoc + "/json/" + indexname
+
+	//get the number of retries
+	maxRetries := dbclient.CouchInstance.conf.MaxRetries
+
+	resp, _, err := dbclient.CouchInstance.handleRequest(http.MethodDelete, indexURL.String(), nil, "", "", maxRetries)
+	if err != nil {
+		return err
+	}
+	defer closeResponseBody(resp)
+
+	logger.Debugf("Exiting DeleteIndex()")
+
+	return nil
+
+}
+
 //BatchRetrieveIDRevision - batch method to retrieve IDs and revisions
 func (dbclient *CouchDatabase) BatchRetrieveIDRevision(keys []string) ([]*DocMetadata, error) {
 
@@ -1064,7 +1228,7 @@ func (dbclient *CouchDatabase) BatchRetrieveIDRevision(keys []string) ([]*DocMet
 	revisionDocs := []*DocMetadata{}
 
 	for _, row := range jsonResponse.Rows {
-		revisionDoc := &DocMetadata{ID: row.ID, Rev: row.Doc.Rev, Version: row.Doc.Version}
+		revisionDoc := &DocMetadata{ID: row.ID, Rev: row.Doc.Rev, Version: row.Doc.Version, Metadata: row.Doc.Metadata}
 		revisionDocs = append(revisionDocs, revisionDoc)
 	}
 
@@ -1094,7 +1258,12 @@ func (dbclient *CouchDatabase) BatchUpdateDocuments(documents []*CouchDoc) ([]*B
 		var document = make(map[string]interface{})
 
 		//unmarshal the JSON component of the CouchDoc into the document
-		json.Unmarshal(jsonDocument.JSONValue, &document)
+		//numbers are kept as json.Number so as not to lose precision on large values
+		decoder := json.NewDecoder(bytes.NewBuffer(jsonDocument.JSONValue))
+		decoder.UseNumber()
+		if err := decoder.Decode(&document); err != nil {
+			return nil, err
+		}
 
 		//iterate through any attachments
 		if len(jsonDocument.Attachments) > 0 {
@@ -547,6 +547,73 @@ func TestDBDeleteNonExistingDocument(t *testing.T) {
 	}
 }
 
+func TestIndexOperations(t *testing.T) {
+
+	if ledgerconfig.IsCouchDBEnabled() {
+
+		database := "testindexoperations"
+		err := cleanup(database)
+		testutil.AssertNoError(t, err, fmt.Sprintf("Error when trying to cleanup  Error: %s", err))
+		defer cleanup(database)
+
+		//create a new instance and database object
+		couchInstance, err := CreateCouchInstance(couchDBDef.URL, couchDBDef.Username, couchDBDef.Password,
+			couchDBDef.MaxRetries, couchDBDef.MaxRetriesOnStartup, couchDBDef.RequestTimeout)
+		testutil.AssertNoError(t, err, fmt.Sprintf("Error when trying to create couch instance"))
+		db := CouchDatabase{CouchInstance: *couchInstance, DBName: database}
+
+		//create a new database
+		_, errdb := db.CreateDatabaseIfNotExist()
+		testutil.AssertNoError(t, errdb, fmt.Sprintf("Error when trying to create database"))
+
+		indexDefSize := `{"index":{"fields":[{"size":"desc"}]},"ddoc":"indexSizeSortDoc", "name":"indexSizeSortName","type":"json"}`
+		indexDefColor := `{"index":{"fields":[{"color":"desc"}]},"ddoc":"indexColorSortDoc", "name":"indexColorSortName","type":"json"}`
+
+		//create the indexes
+		resp, err := db.CreateIndex(indexDefSize)
+		testutil.AssertNoError(t, err, fmt.Sprintf("Error thrown while creating an index"))
+		testutil.AssertEquals(t, resp.Result, "created")
+		testutil.AssertEquals(t, resp.ID, "_design/indexSizeSortDoc")
+		testutil.AssertEquals(t, resp.Name, "indexSizeSortName")
+
+		_, err = db.CreateIndex(indexDefColor)
+		testutil.AssertNoError(t, err, fmt.Sprintf("Error thrown while creating an index"))
+
+		//re-creating the same index should report that the index exists
+		resp, err = db.CreateIndex(indexDefSize)
+		testutil.AssertNoError(t, err, fmt.Sprintf("Error thrown while creating a duplicate index"))
+		testutil.AssertEquals(t, resp.Result, "exists")
+
+		//an invalid index definition should be rejected
+		_, err = db.CreateIndex(`{"index"{"fields":[{"size":"desc"}]}}`)
+		testutil.AssertError(t, err, fmt.Sprintf("Error should have been thrown for an invalid index JSON"))
+
+		//list the indexes
+		listResult, err := db.ListIndex()
+		testutil.AssertNoError(t, err, fmt.Sprintf("Error thrown while retrieving indexes"))
+		testutil.AssertEquals(t, len(listResult), 2)
+		for _, elem := range listResult {
+			switch elem.DesignDocument {
+			case "indexSizeSortDoc":
+				testutil.AssertEquals(t, elem.Name, "indexSizeSortName")
+			case "indexColorSortDoc":
+				testutil.AssertEquals(t, elem.Name, "indexColorSortName")
+			default:
+				t.Fatalf("Unexpected design document %s", elem.DesignDocument)
+			}
+		}
+
+		//delete an index and verify it is no longer listed
+		err = db.DeleteIndex("indexSizeSortDoc", "indexSizeSortName")
+		testutil.AssertNoError(t, err, fmt.Sprintf("Error thrown while deleting an index"))
+
+		listResult, err = db.ListIndex()
+		testutil.AssertNoError(t, err, fmt.Sprintf("Error thrown while retrieving indexes"))
+		testutil.AssertEquals(t, len(listResult), 1)
+		testutil.AssertEquals(t, listResult[0].DesignDocument, "indexColorSortDoc")
+	}
+}
+
 func TestCouchDBVersion(t *testing.T) {
 
 	err := checkCouchDBVersion("2.0.0")
@@ -0,0 +1,67 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// TxCommitOrder is the order in which the transactions of a block are applied to the state,
+// expressed as the list of the indexes of the transactions in the block. It is stored in the
+// block metadata when the validator commits the transactions of a block in an order that differs
+// from the one in which they appear in the block
+type TxCommitOrder []uint64
+
+// NewTxCommitOrderFromBytes decodes a TxCommitOrder previously encoded via TxCommitOrder.Bytes
+func NewTxCommitOrderFromBytes(b []byte) (TxCommitOrder, error) {
+	order := TxCommitOrder{}
+	for len(b) > 0 {
+		txIndex, n := proto.DecodeVarint(b)
+		if n == 0 {
+			return nil, fmt.Errorf("Error decoding transaction commit order: malformed varint")
+		}
+		order = append(order, txIndex)
+		b = b[n:]
+	}
+	return order, nil
+}
+
+// Bytes encodes the TxCommitOrder as a sequence of varints
+func (order TxCommitOrder) Bytes() []byte {
+	buf := proto.NewBuffer(nil)
+	for _, txIndex := range order {
+		buf.EncodeVarint(txIndex)
+	}
+	return buf.Bytes()
+}
+
+// IsPermutationOf checks that the TxCommitOrder contains each of the indexes 0 to numTxs-1 exactly once
+func (order TxCommitOrder) IsPermutationOf(numTxs int) bool {
+	if len(order) != numTxs {
+		return false
+	}
+	seen := make([]bool, numTxs)
+	for _, txIndex := range order {
+		if txIndex >= uint64(numTxs) || seen[txIndex] {
+			return false
+		}
+		seen[txIndex] = true
+	}
+	return true
+}
@@ -0,0 +1,45 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxCommitOrderEncoding(t *testing.T) {
+	order := TxCommitOrder{2, 0, 300, 1}
+	decoded, err := NewTxCommitOrderFromBytes(order.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, order, decoded)
+
+	decoded, err = NewTxCommitOrderFromBytes(nil)
+	assert.NoError(t, err)
+	assert.Len(t, decoded, 0)
+
+	_, err = NewTxCommitOrderFromBytes([]byte{0x80})
+	assert.Error(t, err, "Expected an error for a truncated varint")
+}
+
+func TestTxCommitOrderIsPermutationOf(t *testing.T) {
+	assert.True(t, TxCommitOrder{2, 0, 1}.IsPermutationOf(3))
+	assert.True(t, TxCommitOrder{}.IsPermutationOf(0))
+	assert.False(t, TxCommitOrder{2, 0}.IsPermutationOf(3))
+	assert.False(t, TxCommitOrder{2, 0, 0}.IsPermutationOf(3))
+	assert.False(t, TxCommitOrder{3, 0, 1}.IsPermutationOf(3))
+}
@@ -57,17 +57,17 @@ func (c *mockCcProviderImpl) GetCCValidationInfoFromLSCC(ctxt context.Context, t
 }
 
 // ExecuteChaincode does nothing
-func (c *mockCcProviderImpl) ExecuteChaincode(ctxt context.Context, cccid interface{}, args [][]byte) (*peer.Response, *peer.ChaincodeEvent, error) {
+func (c *mockCcProviderImpl) ExecuteChaincode(ctxt context.Context, cccid interface{}, args [][]byte) (*peer.Response, []*peer.ChaincodeEvent, error) {
 	return nil, nil, nil
 }
 
 // Execute executes the chaincode given context and spec (invocation or deploy)
-func (c *mockCcProviderImpl) Execute(ctxt context.Context, cccid interface{}, spec interface{}) (*peer.Response, *peer.ChaincodeEvent, error) {
+func (c *mockCcProviderImpl) Execute(ctxt context.Context, cccid interface{}, spec interface{}) (*peer.Response, []*peer.ChaincodeEvent, error) {
 	return nil, nil, nil
 }
 
 // ExecuteWithErrorFilder executes the chaincode given context and spec and returns payload
-func (c *mockCcProviderImpl) ExecuteWithErrorFilter(ctxt context.Context, cccid interface{}, spec interface{}) ([]byte, *peer.ChaincodeEvent, error) {
+func (c *mockCcProviderImpl) ExecuteWithErrorFilter(ctxt context.Context, cccid interface{}, spec interface{}) ([]byte, []*peer.ChaincodeEvent, error) {
 	return nil, nil, nil
 }
 
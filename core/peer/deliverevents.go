@@ -0,0 +1,225 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package peer
+
+import (
+	"sync"
+
+	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/orderer/common/deliver"
+	ordererledger "github.com/hyperledger/fabric/orderer/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// deliverEventsServer serves the blocks, or filtered blocks, committed to the
+// peer's channels to the clients authorized by the channel Readers policy
+type deliverEventsServer struct {
+	dh deliver.Handler
+}
+
+// NewDeliverEventsServer creates a Deliver service over the peer's channels
+func NewDeliverEventsServer() pb.DeliverServer {
+	return &deliverEventsServer{
+		dh: deliver.NewHandlerImpl(deliverSupportManager{}),
+	}
+}
+
+// Deliver sends the requested blocks as they get committed
+func (s *deliverEventsServer) Deliver(srv pb.Deliver_DeliverServer) error {
+	peerLogger.Debugf("Starting new peer deliver loop")
+	return s.dh.HandleStream(&deliverStream{srv: srv})
+}
+
+// deliverStream adapts a peer Deliver stream to a deliver.Stream
+type deliverStream struct {
+	srv pb.Deliver_DeliverServer
+}
+
+func (s *deliverStream) Recv() (*common.Envelope, error) {
+	return s.srv.Recv()
+}
+
+func (s *deliverStream) SendStatus(status common.Status) error {
+	return s.srv.Send(&pb.DeliverResponse{
+		Type: &pb.DeliverResponse_Status{Status: status},
+	})
+}
+
+func (s *deliverStream) SendBlock(block *common.Block) error {
+	return s.srv.Send(&pb.DeliverResponse{
+		Type: &pb.DeliverResponse_Block{Block: block},
+	})
+}
+
+func (s *deliverStream) SendFilteredBlock(filteredBlock *pb.FilteredBlock) error {
+	return s.srv.Send(&pb.DeliverResponse{
+		Type: &pb.DeliverResponse_FilteredBlock{FilteredBlock: filteredBlock},
+	})
+}
+
+// deliverSupportManager looks up the channels the peer has joined
+type deliverSupportManager struct{}
+
+func (deliverSupportManager) GetChain(chainID string) (deliver.Support, bool) {
+	l := GetLedger(chainID)
+	if l == nil {
+		return nil, false
+	}
+	return &deliverSupport{chainID: chainID, reader: &peerLedgerReader{ledger: l}}, true
+}
+
+type deliverSupport struct {
+	chainID string
+	reader  ordererledger.Reader
+}
+
+func (s *deliverSupport) PolicyManager() policies.Manager {
+	return GetPolicyManager(s.chainID)
+}
+
+func (s *deliverSupport) Reader() ordererledger.Reader {
+	return s.reader
+}
+
+func (s *deliverSupport) Sequence() uint64 {
+	chains.RLock()
+	defer chains.RUnlock()
+	if c, ok := chains.list[s.chainID]; ok {
+		return c.cs.Sequence()
+	}
+	return 0
+}
+
+var closedChan = make(chan struct{})
+
+func init() {
+	close(closedChan)
+}
+
+// peerLedgerReader exposes a peer ledger as an orderer ledger.Reader
+type peerLedgerReader struct {
+	ledger ledger.PeerLedger
+}
+
+// Height returns the number of blocks on the ledger
+func (r *peerLedgerReader) Height() uint64 {
+	info, err := r.ledger.GetBlockchainInfo()
+	if err != nil {
+		peerLogger.Panicf("Could not retrieve the blockchain info: %s", err)
+	}
+	return info.Height
+}
+
+// Iterator returns an Iterator, as specified by a cb.SeekInfo message, and its
+// starting block number
+func (r *peerLedgerReader) Iterator(startPosition *ab.SeekPosition) (ordererledger.Iterator, uint64) {
+	var start uint64
+	switch seek := startPosition.Type.(type) {
+	case *ab.SeekPosition_Oldest:
+		start = 0
+	case *ab.SeekPosition_Newest:
+		start = r.Height() - 1
+	case *ab.SeekPosition_Specified:
+		start = seek.Specified.Number
+		if start > r.Height() {
+			return &ordererledger.NotFoundErrorIterator{}, 0
+		}
+	default:
+		return &ordererledger.NotFoundErrorIterator{}, 0
+	}
+
+	itr, err := r.ledger.GetBlocksIterator(start)
+	if err != nil {
+		peerLogger.Errorf("Could not get a blocks iterator from block %d: %s", start, err)
+		return &ordererledger.NotFoundErrorIterator{}, 0
+	}
+	return &peerLedgerIterator{reader: r, itr: itr, blockNumber: start}, start
+}
+
+// peerLedgerIterator fetches the next block of the blocking ledger iterator
+// in the background when it is not committed yet, so that readiness can be
+// signaled through a channel. Closing it ends any fetch still pending
+type peerLedgerIterator struct {
+	reader      *peerLedgerReader
+	itr         commonledger.ResultsIterator
+	blockNumber uint64
+
+	lock    sync.Mutex
+	pending chan struct{}
+	result  commonledger.QueryResult
+	err     error
+}
+
+// ReadyChan supplies a channel which will block until Next will not block
+func (i *peerLedgerIterator) ReadyChan() <-chan struct{} {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if i.pending == nil && i.blockNumber < i.reader.Height() {
+		return closedChan
+	}
+	return i.fetch()
+}
+
+// fetch starts fetching the next block unless already doing so and returns
+// the channel closed once it is fetched. It must be called with the lock held
+func (i *peerLedgerIterator) fetch() chan struct{} {
+	if i.pending == nil {
+		pending := make(chan struct{})
+		i.pending = pending
+		go func() {
+			result, err := i.itr.Next()
+			i.lock.Lock()
+			i.result, i.err = result, err
+			i.lock.Unlock()
+			close(pending)
+		}()
+	}
+	return i.pending
+}
+
+// Next blocks until there is a new block available, or returns an error if the
+// next block is no longer retrievable
+func (i *peerLedgerIterator) Next() (*common.Block, common.Status) {
+	i.lock.Lock()
+	pending := i.fetch()
+	i.lock.Unlock()
+
+	<-pending
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.pending = nil
+	if i.err != nil {
+		peerLogger.Errorf("Error reading from the ledger: %s", i.err)
+		return nil, common.Status_SERVICE_UNAVAILABLE
+	}
+	blockHolder, ok := i.result.(commonledger.BlockHolder)
+	if !ok || blockHolder == nil {
+		return nil, common.Status_SERVICE_UNAVAILABLE
+	}
+	i.blockNumber++
+	return blockHolder.GetBlock(), common.Status_SUCCESS
+}
+
+// Close releases the ledger iterator once the seek is done
+func (i *peerLedgerIterator) Close() {
+	i.itr.Close()
+}
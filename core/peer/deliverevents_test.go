@@ -0,0 +1,179 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package peer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+type mockDeliverStream struct {
+	grpc.ServerStream
+	recvChan chan *common.Envelope
+	sendChan chan *pb.DeliverResponse
+}
+
+func newMockDeliverStream() *mockDeliverStream {
+	return &mockDeliverStream{
+		recvChan: make(chan *common.Envelope),
+		sendChan: make(chan *pb.DeliverResponse),
+	}
+}
+
+func (m *mockDeliverStream) Send(resp *pb.DeliverResponse) error {
+	m.sendChan <- resp
+	return nil
+}
+
+func (m *mockDeliverStream) Recv() (*common.Envelope, error) {
+	msg, ok := <-m.recvChan
+	if !ok {
+		return msg, fmt.Errorf("Channel closed")
+	}
+	return msg, nil
+}
+
+func (m *mockDeliverStream) receive(t *testing.T) *pb.DeliverResponse {
+	select {
+	case resp := <-m.sendChan:
+		return resp
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for a deliver response")
+	}
+	return nil
+}
+
+func makeDeliverSeek(chainID string, seekInfo *ab.SeekInfo) *common.Envelope {
+	return &common.Envelope{
+		Payload: utils.MarshalOrPanic(&common.Payload{
+			Header: &common.Header{
+				ChannelHeader:   utils.MarshalOrPanic(&common.ChannelHeader{ChannelId: chainID}),
+				SignatureHeader: utils.MarshalOrPanic(&common.SignatureHeader{}),
+			},
+			Data: utils.MarshalOrPanic(seekInfo),
+		}),
+	}
+}
+
+func seekSpecified(number uint64) *ab.SeekPosition {
+	return &ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: number}}}
+}
+
+func TestDeliverEvents(t *testing.T) {
+	fsPath, err := ioutil.TempDir("", "deliverevents")
+	assert.NoError(t, err)
+	defer os.RemoveAll(fsPath)
+	viper.Set("peer.fileSystemPath", fsPath)
+	ledgermgmt.InitializeTestEnv()
+	defer ledgermgmt.CleanupTestEnv()
+
+	chainID := "deliverchain"
+	assert.NoError(t, MockCreateChain(chainID))
+	l := GetLedger(chainID)
+
+	bg, _ := testutil.NewBlockGenerator(t, chainID, false)
+	commit := func() *common.Block {
+		simulator, err := l.NewTxSimulator()
+		assert.NoError(t, err)
+		simulator.SetState("ns1", "key1", []byte("value1"))
+		simulator.Done()
+		simRes, err := simulator.GetTxSimulationResults()
+		assert.NoError(t, err)
+		block := bg.NextBlock([][]byte{simRes})
+		assert.NoError(t, l.Commit(block))
+		return block
+	}
+	block1 := commit()
+	env, err := utils.GetEnvelopeFromBlock(block1.Data.Data[0])
+	assert.NoError(t, err)
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	assert.NoError(t, err)
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	assert.NoError(t, err)
+
+	server := NewDeliverEventsServer()
+
+	t.Run("FilteredBlocks", func(t *testing.T) {
+		m := newMockDeliverStream()
+		defer close(m.recvChan)
+		go server.Deliver(m)
+
+		m.recvChan <- makeDeliverSeek(chainID, &ab.SeekInfo{Start: seekSpecified(1), Stop: seekSpecified(1), Behavior: ab.SeekInfo_FAIL_IF_NOT_READY, ContentType: ab.SeekInfo_FILTERED_BLOCK})
+		fb := m.receive(t).GetFilteredBlock()
+		if assert.NotNil(t, fb) {
+			assert.Equal(t, chainID, fb.ChannelId)
+			assert.Equal(t, uint64(1), fb.Number)
+			if assert.Len(t, fb.FilteredTransactions, 1) {
+				assert.Equal(t, chdr.TxId, fb.FilteredTransactions[0].Txid)
+				assert.Equal(t, common.HeaderType_ENDORSER_TRANSACTION, fb.FilteredTransactions[0].Type)
+				assert.Equal(t, pb.TxValidationCode_VALID, fb.FilteredTransactions[0].TxValidationCode)
+			}
+		}
+		assert.Equal(t, common.Status_SUCCESS, m.receive(t).GetStatus())
+	})
+
+	t.Run("FailIfNotReady", func(t *testing.T) {
+		m := newMockDeliverStream()
+		defer close(m.recvChan)
+		go server.Deliver(m)
+
+		m.recvChan <- makeDeliverSeek(chainID, &ab.SeekInfo{Start: seekSpecified(2), Stop: seekSpecified(2), Behavior: ab.SeekInfo_FAIL_IF_NOT_READY})
+		assert.Equal(t, common.Status_NOT_FOUND, m.receive(t).GetStatus())
+	})
+
+	t.Run("BlockUntilReady", func(t *testing.T) {
+		m := newMockDeliverStream()
+		defer close(m.recvChan)
+		go server.Deliver(m)
+
+		m.recvChan <- makeDeliverSeek(chainID, &ab.SeekInfo{Start: seekSpecified(2), Stop: seekSpecified(2), Behavior: ab.SeekInfo_BLOCK_UNTIL_READY})
+		select {
+		case <-m.sendChan:
+			t.Fatalf("Should not have delivered before block 2 was committed")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		block2 := commit()
+		block := m.receive(t).GetBlock()
+		if assert.NotNil(t, block) {
+			assert.Equal(t, block2.Header, block.Header)
+		}
+		assert.Equal(t, common.Status_SUCCESS, m.receive(t).GetStatus())
+	})
+
+	t.Run("UnknownChannel", func(t *testing.T) {
+		m := newMockDeliverStream()
+		defer close(m.recvChan)
+		go server.Deliver(m)
+
+		m.recvChan <- makeDeliverSeek("bogus", &ab.SeekInfo{Start: seekSpecified(0), Stop: seekSpecified(0), Behavior: ab.SeekInfo_FAIL_IF_NOT_READY})
+		assert.Equal(t, common.Status_NOT_FOUND, m.receive(t).GetStatus())
+	})
+}
@@ -35,8 +35,11 @@ import (
 	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/core/committer"
 	"github.com/hyperledger/fabric/core/committer/txvalidator"
+	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/blobstore"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/hyperledger/fabric/core/transientstore"
 	"github.com/hyperledger/fabric/gossip/api"
 	"github.com/hyperledger/fabric/gossip/service"
 	"github.com/hyperledger/fabric/msp"
@@ -66,6 +69,11 @@ func (cs *chainSupport) Ledger() ledger.PeerLedger {
 	return cs.ledger
 }
 
+// ChannelLedger returns the ledger of another channel joined by the peer
+func (cs *chainSupport) ChannelLedger(cid string) ledger.PeerLedger {
+	return GetLedger(cid)
+}
+
 func (cs *chainSupport) GetMSPIDs(cid string) []string {
 	return GetMSPIDs(cid)
 }
@@ -75,6 +83,7 @@ type chain struct {
 	cs        *chainSupport
 	cb        *common.Block
 	committer committer.Committer
+	pvtStore  transientstore.Store
 }
 
 // chains is a local map of chainID->chainObject
@@ -83,8 +92,31 @@ var chains = struct {
 	list map[string]*chain
 }{list: make(map[string]*chain)}
 
+// transientStores provides the transient stores holding the private data
+// of the transactions of each chain until they get committed
+var transientStores = struct {
+	sync.Mutex
+	provider transientstore.StoreProvider
+}{}
+
+// openTransientStore opens the transient store of the chain with chain ID
+func openTransientStore(cid string) (transientstore.Store, error) {
+	transientStores.Lock()
+	defer transientStores.Unlock()
+	if transientStores.provider == nil {
+		transientStores.provider = transientstore.NewStoreProvider()
+	}
+	return transientStores.provider.OpenStore(cid)
+}
+
 //MockInitialize resets chains for test env
 func MockInitialize() {
+	transientStores.Lock()
+	if transientStores.provider != nil {
+		transientStores.provider.Close()
+		transientStores.provider = nil
+	}
+	transientStores.Unlock()
 	ledgermgmt.InitializeTestEnv()
 	chains.list = nil
 	chains.list = make(map[string]*chain)
@@ -139,6 +171,11 @@ func InitChain(cid string) {
 }
 
 func getCurrConfigBlockFromLedger(ledger ledger.PeerLedger) (*common.Block, error) {
+	// A ledger created from a snapshot does not hold the blocks preceding the snapshot height,
+	// so look up the config block referred by the metadata of the last block first
+	if configBlock := getLastConfigBlockFromMetadata(ledger); configBlock != nil {
+		return configBlock, nil
+	}
 	// Config blocks contain only 1 transaction, so we look for 1-tx
 	// blocks and check the transaction type
 	var envelope *common.Envelope
@@ -176,6 +213,38 @@ func getCurrConfigBlockFromLedger(ledger ledger.PeerLedger) (*common.Block, erro
 	return nil, fmt.Errorf("Failed to find config block.")
 }
 
+// getLastConfigBlockFromMetadata returns the block referred by the last config index in the metadata
+// of the last block, provided that the referred block is a config block. Otherwise, it returns nil
+func getLastConfigBlockFromMetadata(ledger ledger.PeerLedger) *common.Block {
+	lastBlock, err := ledger.GetBlockByNumber(math.MaxUint64)
+	if err != nil || lastBlock.Metadata == nil ||
+		len(lastBlock.Metadata.Metadata) <= int(common.BlockMetadataIndex_LAST_CONFIG) ||
+		len(lastBlock.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG]) == 0 {
+		return nil
+	}
+	lastConfigIndex, err := utils.GetLastConfigIndexFromBlock(lastBlock)
+	if err != nil {
+		return nil
+	}
+	block, err := ledger.GetBlockByNumber(lastConfigIndex)
+	if err != nil || block.Data == nil || len(block.Data.Data) != 1 {
+		return nil
+	}
+	envelope, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return nil
+	}
+	tx, err := utils.ExtractPayload(envelope)
+	if err != nil {
+		return nil
+	}
+	chdr, err := utils.UnmarshalChannelHeader(tx.Header.ChannelHeader)
+	if err != nil || chdr.Type != int32(common.HeaderType_CONFIG) {
+		return nil
+	}
+	return block
+}
+
 // createChain creates a new chain object and insert it into the chains
 func createChain(cid string, ledger ledger.PeerLedger, cb *common.Block) error {
 
@@ -224,12 +293,24 @@ func createChain(cid string, ledger ledger.PeerLedger, cb *common.Block) error {
 		ledger:      ledger,
 	}
 
-	c := committer.NewLedgerCommitter(ledger, txvalidator.NewTxValidator(cs))
+	pvtStore, err := openTransientStore(cid)
+	if err != nil {
+		return err
+	}
+
+	fetchBlobs := func(hashes [][]byte) error {
+		return service.GetGossipService().FetchBlobs(cid, hashes)
+	}
+	c := committer.NewLedgerCommitterWithPvtData(ledger, txvalidator.NewTxValidator(cs), pvtStore, fetchBlobs)
 	ordererAddresses := configtxManager.ChannelConfig().OrdererAddresses()
 	if len(ordererAddresses) == 0 {
 		return errors.New("No orderering service endpoint provided in configuration block")
 	}
-	service.GetGossipService().InitializeChannel(cs.ChainID(), c, ordererAddresses)
+	service.GetGossipService().InitializeChannel(cs.ChainID(), c, ordererAddresses, service.Support{
+		Store: pvtStore,
+		Cs:    privdata.NewSimpleCollectionStore(ledger),
+		Blobs: ledger.GetBlobStore(),
+	})
 
 	chains.Lock()
 	defer chains.Unlock()
@@ -237,6 +318,7 @@ func createChain(cid string, ledger ledger.PeerLedger, cb *common.Block) error {
 		cs:        cs,
 		cb:        cb,
 		committer: c,
+		pvtStore:  pvtStore,
 	}
 	return nil
 }
@@ -284,6 +366,11 @@ func MockCreateChain(cid string) error {
 		Initializer: initializer,
 	}
 
+	pvtStore, err := openTransientStore(cid)
+	if err != nil {
+		return err
+	}
+
 	chains.Lock()
 	defer chains.Unlock()
 
@@ -291,6 +378,7 @@ func MockCreateChain(cid string) error {
 		cs: &chainSupport{
 			Manager: manager,
 			ledger:  ledger},
+		pvtStore: pvtStore,
 	}
 
 	return nil
@@ -307,6 +395,30 @@ func GetLedger(cid string) ledger.PeerLedger {
 	return nil
 }
 
+// GetTransientStore returns the transient store of the chain with chain ID, which holds
+// the private data of its transactions until they get committed. Note that this
+// call returns nil if chain cid has not been created.
+func GetTransientStore(cid string) transientstore.Store {
+	chains.RLock()
+	defer chains.RUnlock()
+	if c, ok := chains.list[cid]; ok {
+		return c.pvtStore
+	}
+	return nil
+}
+
+// GetBlobStore returns the blob store of the chain with chain ID, which holds
+// the state values streamed by its chaincodes. Note that this call returns nil
+// if chain cid has not been created.
+func GetBlobStore(cid string) blobstore.Store {
+	chains.RLock()
+	defer chains.RUnlock()
+	if c, ok := chains.list[cid]; ok {
+		return c.cs.ledger.GetBlobStore()
+	}
+	return nil
+}
+
 // GetPolicyManager returns the policy manager of the chain with chain ID. Note that this
 // call returns nil if chain cid has not been created.
 func GetPolicyManager(cid string) policies.Manager {
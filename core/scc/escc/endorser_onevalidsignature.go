@@ -48,7 +48,7 @@ func (e *EndorserOneValidSignature) Init(stub shim.ChaincodeStubInterface) pb.Re
 // policy specification to be coded as a transaction of the chaincode and Client
 // could select which policy to use for endorsement using parameter
 // @return a marshalled proposal response
-// Note that Peer calls this function with 4 mandatory arguments (and 2 optional ones):
+// Note that Peer calls this function with 4 mandatory arguments (and 3 optional ones):
 // args[0] - function name (not used now)
 // args[1] - serialized Header object
 // args[2] - serialized ChaincodeProposalPayload object
@@ -57,6 +57,7 @@ func (e *EndorserOneValidSignature) Init(stub shim.ChaincodeStubInterface) pb.Re
 // args[5] - binary blob of simulation results
 // args[6] - serialized events
 // args[7] - payloadVisibility
+// args[8] - serialized CrossChannelReadSets
 
 //
 // NOTE: this chaincode is meant to sign another chaincode's simulation
@@ -68,8 +69,8 @@ func (e *EndorserOneValidSignature) Invoke(stub shim.ChaincodeStubInterface) pb.
 	args := stub.GetArgs()
 	if len(args) < 6 {
 		return shim.Error(fmt.Sprintf("Incorrect number of arguments (expected a minimum of 5, provided %d)", len(args)))
-	} else if len(args) > 8 {
-		return shim.Error(fmt.Sprintf("Incorrect number of arguments (expected a maximum of 7, provided %d)", len(args)))
+	} else if len(args) > 9 {
+		return shim.Error(fmt.Sprintf("Incorrect number of arguments (expected a maximum of 8, provided %d)", len(args)))
 	}
 
 	logger.Debugf("ESCC starts: %d args", len(args))
@@ -144,6 +145,14 @@ func (e *EndorserOneValidSignature) Invoke(stub shim.ChaincodeStubInterface) pb.
 		visibility = args[7]
 	}
 
+	// Handle the read sets done on other channels (it's an optional argument)
+	// they are signed along with the results but are not validated against
+	// the ledger of this channel
+	var crossChannelReads []byte
+	if len(args) > 8 {
+		crossChannelReads = args[8]
+	}
+
 	// obtain the default signing identity for this peer; it will be used to sign this proposal response
 	localMsp := mspmgmt.GetLocalMSP()
 	if localMsp == nil {
@@ -156,7 +165,7 @@ func (e *EndorserOneValidSignature) Invoke(stub shim.ChaincodeStubInterface) pb.
 	}
 
 	// obtain a proposal response
-	presp, err := utils.CreateProposalResponse(hdr, payl, response, results, events, ccid, visibility, signingEndorser)
+	presp, err := utils.CreateProposalResponseWithCrossChannelReads(hdr, payl, response, results, events, crossChannelReads, ccid, visibility, signingEndorser)
 	if err != nil {
 		return shim.Error(err.Error())
 	}
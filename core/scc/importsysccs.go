@@ -18,8 +18,10 @@ package scc
 
 import (
 	//import system chain codes here
+	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/scc/cscc"
 	"github.com/hyperledger/fabric/core/scc/escc"
+	"github.com/hyperledger/fabric/core/scc/lifecycle"
 	"github.com/hyperledger/fabric/core/scc/lscc"
 	"github.com/hyperledger/fabric/core/scc/qscc"
 	"github.com/hyperledger/fabric/core/scc/vscc"
@@ -67,6 +69,14 @@ var systemChaincodes = []*SystemChaincode{
 		InvokableExternal: true, // qscc can be invoked to retrieve blocks
 		InvokableCC2CC:    true, // qscc can be invoked to retrieve blocks also by a cc
 	},
+	{
+		Enabled:           true,
+		Name:              ccprovider.LifecycleSysCC,
+		Path:              "github.com/hyperledger/fabric/core/scc/lifecycle",
+		InitArgs:          [][]byte{[]byte("")},
+		Chaincode:         &lifecycle.Lifecycle{},
+		InvokableExternal: true, // the lifecycle is invoked to approve and commit chaincode definitions
+	},
 }
 
 //RegisterSysCCs is the hook for system chaincodes where system chaincodes are registered with the fabric
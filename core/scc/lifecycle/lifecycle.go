@@ -0,0 +1,525 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/msp"
+	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
+	"github.com/hyperledger/fabric/protos/common"
+	mspproto "github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+//The lifecycle system chaincode lets the organizations of a channel agree
+//on the definition of a chaincode before the definition takes effect.
+//     "Args":["approveformyorg",<ChaincodeDefinition>]
+//     "Args":["checkcommitreadiness",<ChaincodeDefinition>]
+//     "Args":["commit",<ChaincodeDefinition>]
+//     "Args":["querychaincodedefinition",<chaincode name>]
+
+var logger = flogging.MustGetLogger("lifecycle")
+
+const (
+	//APPROVE approves a chaincode definition on behalf of the organization of the submitter
+	APPROVE = "approveformyorg"
+
+	//CHECKCOMMITREADINESS returns which organizations approved a chaincode definition
+	CHECKCOMMITREADINESS = "checkcommitreadiness"
+
+	//COMMIT commits a chaincode definition approved by enough organizations
+	COMMIT = "commit"
+
+	//QUERYDEFINITION returns the committed definition of a chaincode
+	QUERYDEFINITION = "querychaincodedefinition"
+
+	// LifecycleEndorsementPolicy is the channel policy that the approvals of a chaincode
+	// definition must satisfy for the definition to be committed. When the channel does
+	// not define it, the approvals must satisfy the channel application admins policy,
+	// which by default requires a majority of the organizations
+	LifecycleEndorsementPolicy = "/Channel/Application/LifecycleEndorsement"
+
+	allowedCharsChaincodeName = "[A-Za-z0-9_-]+"
+	allowedCharsVersion       = "[A-Za-z0-9_.-]+"
+)
+
+// Lifecycle implements a chaincode lifecycle in which each organization of a
+// channel approves the definition of a chaincode, and the definition is committed
+// only once the approvals satisfy the lifecycle endorsement policy of the channel.
+// An approval is the signed proposal with which an admin of the organization
+// approved the definition, so that the approvals are verified again on commit
+type Lifecycle struct {
+	// sccprovider is the interface with which we call
+	// methods of the system chaincode package without
+	// import cycles
+	sccprovider sysccprovider.SystemChaincodeProvider
+
+	// policyManagerGetter returns the policy manager of a
+	// channel, which holds the lifecycle endorsement policy
+	policyManagerGetter policies.ChannelPolicyManagerGetter
+
+	// deserializerGetter returns the identity deserializer of a channel
+	deserializerGetter func(chainID string) msp.IdentityDeserializer
+
+	// orgsGetter returns the MSP IDs of the application organizations of a channel
+	orgsGetter func(chainID string) []string
+}
+
+// New returns a lifecycle bound to the channels of the peer, with which the
+// validation system chaincode checks the transactions of the lifecycle
+func New() *Lifecycle {
+	lc := &Lifecycle{}
+	lc.setup()
+	return lc
+}
+
+func (lc *Lifecycle) setup() {
+	lc.sccprovider = sysccprovider.GetSystemChaincodeProvider()
+	lc.policyManagerGetter = peer.NewChannelPolicyManagerGetter()
+	lc.deserializerGetter = func(chainID string) msp.IdentityDeserializer {
+		return mspmgmt.GetManagerForChain(chainID)
+	}
+	lc.orgsGetter = peer.GetMSPIDs
+}
+
+// Init initializes the lifecycle system chaincode
+func (lc *Lifecycle) Init(stub shim.ChaincodeStubInterface) pb.Response {
+	lc.setup()
+
+	return shim.Success(nil)
+}
+
+// Invoke implements the functions "approveformyorg", "checkcommitreadiness",
+// "commit" and "querychaincodedefinition"
+func (lc *Lifecycle) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
+	args := stub.GetArgs()
+	if len(args) != 2 {
+		return shim.Error(fmt.Sprintf("invalid number of arguments to lifecycle: %d", len(args)))
+	}
+
+	function := string(args[0])
+
+	signedProp, err := stub.GetSignedProposal()
+	if err != nil {
+		return shim.Error(fmt.Sprintf("failed retrieving signed proposal on executing %s with error %s", function, err))
+	}
+	chainID, err := getChannelID(signedProp)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	if chainID == "" {
+		return shim.Error(fmt.Sprintf("%s must be invoked on a channel", function))
+	}
+
+	switch function {
+	case APPROVE, CHECKCOMMITREADINESS, COMMIT:
+		def := &pb.ChaincodeDefinition{}
+		if err = proto.Unmarshal(args[1], def); err != nil {
+			return shim.Error(fmt.Sprintf("invalid chaincode definition: %s", err))
+		}
+		if err = lc.checkDefinition(stub, chainID, def); err != nil {
+			return shim.Error(err.Error())
+		}
+
+		switch function {
+		case APPROVE:
+			err = lc.approve(stub, chainID, signedProp, def)
+			if err != nil {
+				return shim.Error(err.Error())
+			}
+			return shim.Success(nil)
+		case CHECKCOMMITREADINESS:
+			approvals, _, err := lc.getApprovals(&stubStateReader{stub}, chainID, def)
+			if err != nil {
+				return shim.Error(err.Error())
+			}
+			resBytes, err := proto.Marshal(&pb.CommitReadinessResponse{Approvals: approvals})
+			if err != nil {
+				return shim.Error(err.Error())
+			}
+			return shim.Success(resBytes)
+		default:
+			err = lc.commit(stub, chainID, def)
+			if err != nil {
+				return shim.Error(err.Error())
+			}
+			return shim.Success(nil)
+		}
+	case QUERYDEFINITION:
+		ccname := string(args[1])
+		defBytes, err := stub.GetState(ccprovider.ChaincodeDefinitionKey(ccname))
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		if defBytes == nil {
+			return shim.Error(fmt.Sprintf("chaincode %s has no committed definition", ccname))
+		}
+		return shim.Success(defBytes)
+	}
+
+	return shim.Error(fmt.Sprintf("invalid function to lifecycle: %s", function))
+}
+
+// checkDefinition checks that a chaincode definition is well formed and that
+// it is the definition following the one committed, if any
+func (lc *Lifecycle) checkDefinition(stub shim.ChaincodeStubInterface, chainID string, def *pb.ChaincodeDefinition) error {
+	if !isValidCCNameOrVersion(def.Name, allowedCharsChaincodeName) {
+		return fmt.Errorf("invalid chaincode name '%s'", def.Name)
+	}
+	if lc.sccprovider.IsSysCC(def.Name) {
+		return fmt.Errorf("%s is a system chaincode", def.Name)
+	}
+	if !isValidCCNameOrVersion(def.Version, allowedCharsVersion) {
+		return fmt.Errorf("invalid version '%s' of chaincode %s", def.Version, def.Name)
+	}
+	if len(def.PackageHash) == 0 {
+		return fmt.Errorf("the definition of chaincode %s has no package hash", def.Name)
+	}
+	if !lc.sccprovider.IsSysCC(def.Escc) {
+		return fmt.Errorf("%s is not a valid endorsement system chaincode", def.Escc)
+	}
+	if !lc.sccprovider.IsSysCC(def.Vscc) {
+		return fmt.Errorf("%s is not a valid validation system chaincode", def.Vscc)
+	}
+
+	deserializer := lc.deserializerGetter(chainID)
+	if deserializer == nil {
+		return fmt.Errorf("no identity deserializer for channel %s", chainID)
+	}
+	if _, _, err := cauthdsl.NewPolicyProvider(deserializer).NewPolicy(def.EndorsementPolicy); err != nil {
+		return fmt.Errorf("invalid endorsement policy of chaincode %s: %s", def.Name, err)
+	}
+
+	return checkSequence(&stubStateReader{stub}, def)
+}
+
+// checkSequence checks that a chaincode definition follows the committed one, if any
+func checkSequence(r ccprovider.StateReader, def *pb.ChaincodeDefinition) error {
+	committed, err := ccprovider.GetChaincodeDefinition(r, def.Name)
+	if err != nil {
+		return err
+	}
+	var sequence int64
+	if committed != nil {
+		sequence = committed.Sequence
+	}
+	if def.Sequence != sequence+1 {
+		return fmt.Errorf("the sequence of the definition of chaincode %s is %d, but the next definition must be sequence %d", def.Name, def.Sequence, sequence+1)
+	}
+
+	return nil
+}
+
+// approve records the approval of a chaincode definition by the organization of the
+// submitter, who must be one of its admins
+func (lc *Lifecycle) approve(stub shim.ChaincodeStubInterface, chainID string, signedProp *pb.SignedProposal, def *pb.ChaincodeDefinition) error {
+	sd, mspID, err := getSignedData(signedProp)
+	if err != nil {
+		return err
+	}
+
+	if !contains(lc.orgsGetter(chainID), mspID) {
+		return fmt.Errorf("%s is not an organization of channel %s", mspID, chainID)
+	}
+
+	// only the admins of an organization approve on its behalf
+	adminPolicy, _, err := cauthdsl.NewPolicyProvider(lc.deserializerGetter(chainID)).NewPolicy(utils.MarshalOrPanic(cauthdsl.SignedByMspAdmin(mspID)))
+	if err != nil {
+		return err
+	}
+	if err = adminPolicy.Evaluate([]*common.SignedData{sd}); err != nil {
+		return fmt.Errorf("the submitter is not an admin of organization %s: %s", mspID, err)
+	}
+
+	signedPropBytes, err := proto.Marshal(signedProp)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Organization %s approved sequence %d of chaincode %s:%s on channel %s", mspID, def.Sequence, def.Name, def.Version, chainID)
+
+	return stub.PutState(ccprovider.ChaincodeApprovalKey(def.Name, def.Sequence, mspID), signedPropBytes)
+}
+
+// commit commits a chaincode definition if its approvals satisfy the lifecycle endorsement policy of the channel
+func (lc *Lifecycle) commit(stub shim.ChaincodeStubInterface, chainID string, def *pb.ChaincodeDefinition) error {
+	if err := lc.checkApproved(&stubStateReader{stub}, chainID, def); err != nil {
+		return err
+	}
+
+	defBytes, err := proto.Marshal(def)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Committed sequence %d of chaincode %s:%s on channel %s", def.Sequence, def.Name, def.Version, chainID)
+
+	return stub.PutState(ccprovider.ChaincodeDefinitionKey(def.Name), defBytes)
+}
+
+// checkApproved checks that the approvals of a chaincode definition satisfy the lifecycle endorsement policy of the channel
+func (lc *Lifecycle) checkApproved(r ccprovider.StateReader, chainID string, def *pb.ChaincodeDefinition) error {
+	_, signatures, err := lc.getApprovals(r, chainID, def)
+	if err != nil {
+		return err
+	}
+
+	manager, _ := lc.policyManagerGetter.Manager(chainID)
+	if manager == nil {
+		return fmt.Errorf("no policy manager for channel %s", chainID)
+	}
+	policy, ok := manager.GetPolicy(LifecycleEndorsementPolicy)
+	if !ok {
+		policy, ok = manager.GetPolicy(policies.ChannelApplicationAdmins)
+		if !ok {
+			return fmt.Errorf("channel %s has no lifecycle endorsement policy", chainID)
+		}
+	}
+	if err = policy.Evaluate(signatures); err != nil {
+		return fmt.Errorf("the definition of chaincode %s is not approved by enough organizations: %s", def.Name, err)
+	}
+
+	return nil
+}
+
+// getApprovals returns, for each organization of the channel, whether it approved the chaincode
+// definition, and the signed data of the approvals
+func (lc *Lifecycle) getApprovals(r ccprovider.StateReader, chainID string, def *pb.ChaincodeDefinition) (map[string]bool, []*common.SignedData, error) {
+	orgs := lc.orgsGetter(chainID)
+	sort.Strings(orgs)
+
+	approvals := make(map[string]bool)
+	var signatures []*common.SignedData
+	for _, mspID := range orgs {
+		signedPropBytes, err := r.GetState(ccprovider.LifecycleSysCC, ccprovider.ChaincodeApprovalKey(def.Name, def.Sequence, mspID))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		approvals[mspID] = false
+		if signedPropBytes == nil {
+			continue
+		}
+
+		approved, sd, err := getApproval(signedPropBytes, chainID, mspID)
+		if err != nil {
+			logger.Warningf("Ignoring the invalid approval of organization %s for chaincode %s: %s", mspID, def.Name, err)
+			continue
+		}
+		if proto.Equal(approved, def) {
+			approvals[mspID] = true
+			signatures = append(signatures, sd)
+		}
+	}
+
+	return approvals, signatures, nil
+}
+
+// ValidateWrites checks the writes of a transaction to the state of the lifecycle system chaincode
+// against the committed state of the channel. An organization writes its own approvals only, and a
+// chaincode definition is written only if it follows the committed one and the approvals committed
+// for its sequence satisfy the lifecycle endorsement policy of the channel
+func (lc *Lifecycle) ValidateWrites(r ccprovider.StateReader, chainID string, creatorMSPID string, nsRWSet *rwsetutil.NsRwSet) error {
+	if len(nsRWSet.KvRwSet.MetadataWrites) > 0 || len(nsRWSet.CollHashedRwSets) > 0 {
+		return fmt.Errorf("the lifecycle state only holds public values")
+	}
+
+	for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+		if kvWrite.IsDelete {
+			return fmt.Errorf("key %s of the lifecycle state cannot be deleted", kvWrite.Key)
+		}
+
+		switch {
+		case strings.HasPrefix(kvWrite.Key, "approvals/"):
+			// approvals/<chaincode name>/<sequence>/<MSP ID>
+			parts := strings.Split(kvWrite.Key, "/")
+			if len(parts) != 4 {
+				return fmt.Errorf("invalid approval key %s", kvWrite.Key)
+			}
+			if parts[3] != creatorMSPID {
+				return fmt.Errorf("organization %s cannot write the approval of organization %s", creatorMSPID, parts[3])
+			}
+			def, _, err := getApproval(kvWrite.Value, chainID, creatorMSPID)
+			if err != nil {
+				return fmt.Errorf("invalid approval %s: %s", kvWrite.Key, err)
+			}
+			if kvWrite.Key != ccprovider.ChaincodeApprovalKey(def.Name, def.Sequence, creatorMSPID) {
+				return fmt.Errorf("the approval %s is for sequence %d of chaincode %s", kvWrite.Key, def.Sequence, def.Name)
+			}
+		case strings.HasPrefix(kvWrite.Key, "namespaces/"):
+			def := &pb.ChaincodeDefinition{}
+			if err := proto.Unmarshal(kvWrite.Value, def); err != nil {
+				return fmt.Errorf("invalid chaincode definition %s: %s", kvWrite.Key, err)
+			}
+			if kvWrite.Key != ccprovider.ChaincodeDefinitionKey(def.Name) {
+				return fmt.Errorf("the definition %s is the one of chaincode %s", kvWrite.Key, def.Name)
+			}
+			if err := checkSequence(r, def); err != nil {
+				return err
+			}
+			if err := lc.checkApproved(r, chainID, def); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("invalid key %s of the lifecycle state", kvWrite.Key)
+		}
+	}
+
+	return nil
+}
+
+// getApproval returns the chaincode definition approved by a stored signed proposal, after checking
+// that the proposal approved it on the channel for the organization, and the signed data of the proposal
+func getApproval(signedPropBytes []byte, chainID string, mspID string) (*pb.ChaincodeDefinition, *common.SignedData, error) {
+	signedProp := &pb.SignedProposal{}
+	if err := proto.Unmarshal(signedPropBytes, signedProp); err != nil {
+		return nil, nil, err
+	}
+
+	approvalChainID, err := getChannelID(signedProp)
+	if err != nil {
+		return nil, nil, err
+	}
+	if approvalChainID != chainID {
+		return nil, nil, fmt.Errorf("approval for channel %s", approvalChainID)
+	}
+
+	sd, approvalMSPID, err := getSignedData(signedProp)
+	if err != nil {
+		return nil, nil, err
+	}
+	if approvalMSPID != mspID {
+		return nil, nil, fmt.Errorf("approval submitted by organization %s", approvalMSPID)
+	}
+
+	prop, err := utils.GetProposal(signedProp.ProposalBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	cpp, err := utils.GetChaincodeProposalPayload(prop.Payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	cis := &pb.ChaincodeInvocationSpec{}
+	if err = proto.Unmarshal(cpp.Input, cis); err != nil {
+		return nil, nil, err
+	}
+	if cis.ChaincodeSpec == nil || cis.ChaincodeSpec.Input == nil || cis.ChaincodeSpec.ChaincodeId == nil {
+		return nil, nil, fmt.Errorf("the proposal has no chaincode input")
+	}
+	args := cis.ChaincodeSpec.Input.Args
+	if cis.ChaincodeSpec.ChaincodeId.Name != ccprovider.LifecycleSysCC || len(args) != 2 || string(args[0]) != APPROVE {
+		return nil, nil, fmt.Errorf("the proposal is not an approval")
+	}
+
+	def := &pb.ChaincodeDefinition{}
+	if err = proto.Unmarshal(args[1], def); err != nil {
+		return nil, nil, err
+	}
+
+	return def, sd, nil
+}
+
+// getChannelID returns the channel of a signed proposal
+func getChannelID(signedProp *pb.SignedProposal) (string, error) {
+	prop, err := utils.GetProposal(signedProp.ProposalBytes)
+	if err != nil {
+		return "", err
+	}
+	header, err := utils.GetHeader(prop.Header)
+	if err != nil {
+		return "", err
+	}
+	chdr, err := utils.UnmarshalChannelHeader(header.ChannelHeader)
+	if err != nil {
+		return "", err
+	}
+
+	return chdr.ChannelId, nil
+}
+
+// getSignedData returns the signed data of a signed proposal and the MSP ID of its creator
+func getSignedData(signedProp *pb.SignedProposal) (*common.SignedData, string, error) {
+	prop, err := utils.GetProposal(signedProp.ProposalBytes)
+	if err != nil {
+		return nil, "", err
+	}
+	header, err := utils.GetHeader(prop.Header)
+	if err != nil {
+		return nil, "", err
+	}
+	shdr, err := utils.GetSignatureHeader(header.SignatureHeader)
+	if err != nil {
+		return nil, "", err
+	}
+	creator := &mspproto.SerializedIdentity{}
+	if err = proto.Unmarshal(shdr.Creator, creator); err != nil {
+		return nil, "", fmt.Errorf("invalid creator of the proposal: %s", err)
+	}
+
+	return &common.SignedData{
+		Data:      signedProp.ProposalBytes,
+		Identity:  shdr.Creator,
+		Signature: signedProp.Signature,
+	}, creator.Mspid, nil
+}
+
+func isValidCCNameOrVersion(ccNameOrVersion string, regExp string) bool {
+	re, _ := regexp.Compile(regExp)
+
+	matched := re.FindString(ccNameOrVersion)
+	if len(matched) != len(ccNameOrVersion) {
+		return false
+	}
+
+	return ccNameOrVersion != ""
+}
+
+// stubStateReader reads the state of the lifecycle system chaincode through its stub
+type stubStateReader struct {
+	stub shim.ChaincodeStubInterface
+}
+
+func (r *stubStateReader) GetState(namespace string, key string) ([]byte, error) {
+	if namespace != ccprovider.LifecycleSysCC {
+		return nil, fmt.Errorf("the lifecycle cannot read namespace %s", namespace)
+	}
+	return r.stub.GetState(key)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,411 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	mspproto "github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const testChainID = "mychannel"
+
+// testIdentity is an identity of an organization, admin or not; its signatures are
+// valid unless they are "bad"
+type testIdentity struct {
+	mspID string
+	admin bool
+}
+
+func (id *testIdentity) SatisfiesPrincipal(p *mspproto.MSPPrincipal) error {
+	role := &mspproto.MSPRole{}
+	if err := proto.Unmarshal(p.Principal, role); err != nil {
+		return err
+	}
+	if role.MspIdentifier != id.mspID {
+		return fmt.Errorf("identity of %s, not of %s", id.mspID, role.MspIdentifier)
+	}
+	if role.Role == mspproto.MSPRole_ADMIN && !id.admin {
+		return fmt.Errorf("identity not an admin of %s", id.mspID)
+	}
+	return nil
+}
+
+func (id *testIdentity) GetIdentifier() *msp.IdentityIdentifier {
+	return &msp.IdentityIdentifier{Mspid: id.mspID, Id: "test"}
+}
+
+func (id *testIdentity) GetMSPIdentifier() string {
+	return id.mspID
+}
+
+func (id *testIdentity) Validate() error {
+	return nil
+}
+
+func (id *testIdentity) GetOrganizationalUnits() []mspproto.FabricOUIdentifier {
+	return nil
+}
+
+func (id *testIdentity) Verify(msg []byte, sig []byte) error {
+	if string(sig) == "bad" {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+func (id *testIdentity) VerifyOpts(msg []byte, sig []byte, opts msp.SignatureOpts) error {
+	return nil
+}
+
+func (id *testIdentity) VerifyAttributes(proof []byte, spec *msp.AttributeProofSpec) error {
+	return nil
+}
+
+func (id *testIdentity) Serialize() ([]byte, error) {
+	return serializeIdentity(id.mspID, id.admin), nil
+}
+
+type testDeserializer struct{}
+
+func (d *testDeserializer) DeserializeIdentity(serializedIdentity []byte) (msp.Identity, error) {
+	sid := &mspproto.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedIdentity, sid); err != nil {
+		return nil, err
+	}
+	return &testIdentity{mspID: sid.Mspid, admin: string(sid.IdBytes) == "admin"}, nil
+}
+
+func serializeIdentity(mspID string, admin bool) []byte {
+	idBytes := []byte("member")
+	if admin {
+		idBytes = []byte("admin")
+	}
+	return utils.MarshalOrPanic(&mspproto.SerializedIdentity{Mspid: mspID, IdBytes: idBytes})
+}
+
+type testPolicyManager struct {
+	policies map[string]policies.Policy
+}
+
+func (m *testPolicyManager) GetPolicy(id string) (policies.Policy, bool) {
+	p, ok := m.policies[id]
+	return p, ok
+}
+
+func (m *testPolicyManager) Manager(path []string) (policies.Manager, bool) {
+	return nil, false
+}
+
+func (m *testPolicyManager) BasePath() string {
+	return ""
+}
+
+func (m *testPolicyManager) PolicyNames() []string {
+	return nil
+}
+
+type testPolicyManagerGetter struct {
+	manager policies.Manager
+}
+
+func (g *testPolicyManagerGetter) Manager(channelID string) (policies.Manager, bool) {
+	return g.manager, true
+}
+
+type testSccProvider struct{}
+
+func (p *testSccProvider) IsSysCC(name string) bool {
+	return name == "escc" || name == "vscc" || name == "lscc"
+}
+
+func (p *testSccProvider) IsSysCCAndNotInvokableCC2CC(name string) bool {
+	return false
+}
+
+func (p *testSccProvider) GetQueryExecutorForLedger(cid string) (ledger.QueryExecutor, error) {
+	return nil, nil
+}
+
+func newTestLifecycle(t *testing.T, policyName string) *shim.MockStub {
+	return shim.NewMockStub(ccprovider.LifecycleSysCC, newLifecycle(t, policyName))
+}
+
+func newLifecycle(t *testing.T, policyName string) *Lifecycle {
+	p, err := cauthdsl.FromString("OR(AND('Org1MSP.admin', 'Org2MSP.admin'), AND('Org1MSP.admin', 'Org3MSP.admin'), AND('Org2MSP.admin', 'Org3MSP.admin'))")
+	assert.NoError(t, err)
+	policy, _, err := cauthdsl.NewPolicyProvider(&testDeserializer{}).NewPolicy(utils.MarshalOrPanic(p))
+	assert.NoError(t, err)
+
+	lc := &Lifecycle{
+		sccprovider: &testSccProvider{},
+		policyManagerGetter: &testPolicyManagerGetter{
+			manager: &testPolicyManager{policies: map[string]policies.Policy{policyName: policy}},
+		},
+		deserializerGetter: func(chainID string) msp.IdentityDeserializer {
+			return &testDeserializer{}
+		},
+		orgsGetter: func(chainID string) []string {
+			return []string{"Org3MSP", "Org1MSP", "Org2MSP"}
+		},
+	}
+
+	return lc
+}
+
+func newDefinition(version string, sequence int64) *pb.ChaincodeDefinition {
+	return &pb.ChaincodeDefinition{
+		Name:              "mycc",
+		Version:           version,
+		Sequence:          sequence,
+		EndorsementPolicy: cauthdsl.SignedByAnyMember([]string{"Org1MSP", "Org2MSP"}),
+		PackageHash:       []byte("hash"),
+		Escc:              "escc",
+		Vscc:              "vscc",
+	}
+}
+
+func invoke(stub *shim.MockStub, function string, def *pb.ChaincodeDefinition, mspID string, admin bool) pb.Response {
+	return invokeWithArgs(stub, [][]byte{[]byte(function), utils.MarshalOrPanic(def)}, mspID, admin)
+}
+
+func invokeWithArgs(stub *shim.MockStub, args [][]byte, mspID string, admin bool) pb.Response {
+	cis := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			ChaincodeId: &pb.ChaincodeID{Name: ccprovider.LifecycleSysCC},
+			Input:       &pb.ChaincodeInput{Args: args},
+		},
+	}
+	prop, _, err := utils.CreateChaincodeProposal(common.HeaderType_ENDORSER_TRANSACTION, testChainID, cis, serializeIdentity(mspID, admin))
+	if err != nil {
+		panic(err)
+	}
+	signedProp := &pb.SignedProposal{ProposalBytes: utils.MarshalOrPanic(prop), Signature: []byte("signature")}
+
+	return stub.MockInvokeWithSignedProposal("1", args, signedProp)
+}
+
+func checkCommitReadiness(t *testing.T, stub *shim.MockStub, def *pb.ChaincodeDefinition, expected map[string]bool) {
+	res := invoke(stub, CHECKCOMMITREADINESS, def, "Org1MSP", false)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	crr := &pb.CommitReadinessResponse{}
+	assert.NoError(t, proto.Unmarshal(res.Payload, crr))
+	assert.Equal(t, expected, crr.Approvals)
+}
+
+func TestApproveAndCommit(t *testing.T) {
+	stub := newTestLifecycle(t, LifecycleEndorsementPolicy)
+	def := newDefinition("1.0", 1)
+
+	// only the admins of the organizations of the channel approve
+	res := invoke(stub, APPROVE, def, "Org1MSP", false)
+	assert.NotEqual(t, int32(shim.OK), res.Status)
+	assert.Contains(t, res.Message, "not an admin of organization Org1MSP")
+	res = invoke(stub, APPROVE, def, "Org4MSP", true)
+	assert.NotEqual(t, int32(shim.OK), res.Status)
+	assert.Contains(t, res.Message, "Org4MSP is not an organization of channel mychannel")
+
+	res = invoke(stub, APPROVE, def, "Org1MSP", true)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	checkCommitReadiness(t, stub, def, map[string]bool{"Org1MSP": true, "Org2MSP": false, "Org3MSP": false})
+
+	// an approval of another definition does not count
+	res = invoke(stub, APPROVE, newDefinition("2.0", 1), "Org2MSP", true)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	checkCommitReadiness(t, stub, def, map[string]bool{"Org1MSP": true, "Org2MSP": false, "Org3MSP": false})
+
+	res = invoke(stub, COMMIT, def, "Org1MSP", false)
+	assert.NotEqual(t, int32(shim.OK), res.Status)
+	assert.Contains(t, res.Message, "not approved by enough organizations")
+
+	res = invokeWithArgs(stub, [][]byte{[]byte(QUERYDEFINITION), []byte("mycc")}, "Org1MSP", false)
+	assert.NotEqual(t, int32(shim.OK), res.Status)
+	assert.Contains(t, res.Message, "chaincode mycc has no committed definition")
+
+	res = invoke(stub, APPROVE, def, "Org3MSP", true)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	checkCommitReadiness(t, stub, def, map[string]bool{"Org1MSP": true, "Org2MSP": false, "Org3MSP": true})
+
+	res = invoke(stub, COMMIT, def, "Org2MSP", false)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+
+	res = invokeWithArgs(stub, [][]byte{[]byte(QUERYDEFINITION), []byte("mycc")}, "Org1MSP", false)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	committed := &pb.ChaincodeDefinition{}
+	assert.NoError(t, proto.Unmarshal(res.Payload, committed))
+	assert.True(t, proto.Equal(def, committed))
+
+	// the peers read the committed definition from the state of the lifecycle
+	cd, err := ccprovider.GetCommittedChaincodeData(&mockStateReader{stub}, "mycc")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0", cd.Version)
+	assert.Equal(t, []byte("hash"), cd.Id)
+	assert.Equal(t, def.EndorsementPolicy, cd.Policy)
+
+	// the committed sequence cannot be approved nor committed again
+	res = invoke(stub, APPROVE, def, "Org2MSP", true)
+	assert.NotEqual(t, int32(shim.OK), res.Status)
+	assert.Contains(t, res.Message, "the next definition must be sequence 2")
+	res = invoke(stub, COMMIT, def, "Org2MSP", true)
+	assert.NotEqual(t, int32(shim.OK), res.Status)
+
+	// nor can a single organization commit the next one
+	next := newDefinition("2.0", 2)
+	res = invoke(stub, APPROVE, next, "Org2MSP", true)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	res = invoke(stub, COMMIT, next, "Org2MSP", true)
+	assert.NotEqual(t, int32(shim.OK), res.Status)
+	assert.Contains(t, res.Message, "not approved by enough organizations")
+}
+
+func TestCommitDefaultsToApplicationAdminsPolicy(t *testing.T) {
+	stub := newTestLifecycle(t, policies.ChannelApplicationAdmins)
+	def := newDefinition("1.0", 1)
+
+	for _, mspID := range []string{"Org1MSP", "Org2MSP"} {
+		res := invoke(stub, APPROVE, def, mspID, true)
+		assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	}
+	res := invoke(stub, COMMIT, def, "Org1MSP", false)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+}
+
+func TestInvalidApprovals(t *testing.T) {
+	stub := newTestLifecycle(t, LifecycleEndorsementPolicy)
+	def := newDefinition("1.0", 1)
+
+	res := invoke(stub, APPROVE, def, "Org1MSP", true)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+
+	// an approval stored for another organization is ignored
+	stub.MockTransactionStart("2")
+	approval := stub.State[ccprovider.ChaincodeApprovalKey("mycc", 1, "Org1MSP")]
+	stub.PutState(ccprovider.ChaincodeApprovalKey("mycc", 1, "Org2MSP"), approval)
+	stub.MockTransactionEnd("2")
+	checkCommitReadiness(t, stub, def, map[string]bool{"Org1MSP": true, "Org2MSP": false, "Org3MSP": false})
+
+	res = invoke(stub, COMMIT, def, "Org1MSP", false)
+	assert.NotEqual(t, int32(shim.OK), res.Status)
+}
+
+func TestInvalidDefinitions(t *testing.T) {
+	stub := newTestLifecycle(t, LifecycleEndorsementPolicy)
+
+	for _, tc := range []struct {
+		update   func(def *pb.ChaincodeDefinition)
+		expected string
+	}{
+		{func(def *pb.ChaincodeDefinition) { def.Name = "my/cc" }, "invalid chaincode name"},
+		{func(def *pb.ChaincodeDefinition) { def.Name = "lscc" }, "lscc is a system chaincode"},
+		{func(def *pb.ChaincodeDefinition) { def.Version = "" }, "invalid version"},
+		{func(def *pb.ChaincodeDefinition) { def.PackageHash = nil }, "no package hash"},
+		{func(def *pb.ChaincodeDefinition) { def.Escc = "myescc" }, "myescc is not a valid endorsement system chaincode"},
+		{func(def *pb.ChaincodeDefinition) { def.Vscc = "myvscc" }, "myvscc is not a valid validation system chaincode"},
+		{func(def *pb.ChaincodeDefinition) { def.EndorsementPolicy = []byte("bad") }, "invalid endorsement policy"},
+		{func(def *pb.ChaincodeDefinition) { def.Sequence = 2 }, "the next definition must be sequence 1"},
+	} {
+		def := newDefinition("1.0", 1)
+		tc.update(def)
+		res := invoke(stub, APPROVE, def, "Org1MSP", true)
+		assert.NotEqual(t, int32(shim.OK), res.Status)
+		assert.Contains(t, res.Message, tc.expected)
+	}
+
+	res := invokeWithArgs(stub, [][]byte{[]byte("deploy"), []byte("mycc")}, "Org1MSP", true)
+	assert.NotEqual(t, int32(shim.OK), res.Status)
+	assert.Contains(t, res.Message, "invalid function to lifecycle: deploy")
+}
+
+func TestValidateWrites(t *testing.T) {
+	lc := newLifecycle(t, LifecycleEndorsementPolicy)
+	stub := shim.NewMockStub(ccprovider.LifecycleSysCC, lc)
+	committed := &mockStateReader{stub}
+	def := newDefinition("1.0", 1)
+
+	// the writes of an approval as they would be simulated
+	approve := func(def *pb.ChaincodeDefinition, mspID string) *kvrwset.KVWrite {
+		res := invoke(stub, APPROVE, def, mspID, true)
+		assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+		key := ccprovider.ChaincodeApprovalKey(def.Name, def.Sequence, mspID)
+		return &kvrwset.KVWrite{Key: key, Value: stub.State[key]}
+	}
+	validate := func(mspID string, kvWrites ...*kvrwset.KVWrite) error {
+		return lc.ValidateWrites(committed, testChainID, mspID, &rwsetutil.NsRwSet{
+			NameSpace: ccprovider.LifecycleSysCC,
+			KvRwSet:   &kvrwset.KVRWSet{Writes: kvWrites},
+		})
+	}
+
+	// an organization writes its own approvals only
+	approval := approve(def, "Org1MSP")
+	assert.NoError(t, validate("Org1MSP", approval))
+	err := validate("Org2MSP", approval)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "organization Org2MSP cannot write the approval of organization Org1MSP")
+	forged := &kvrwset.KVWrite{Key: ccprovider.ChaincodeApprovalKey("mycc", 1, "Org2MSP"), Value: approval.Value}
+	assert.Error(t, validate("Org2MSP", forged))
+	moved := &kvrwset.KVWrite{Key: ccprovider.ChaincodeApprovalKey("mycc", 2, "Org1MSP"), Value: approval.Value}
+	assert.Error(t, validate("Org1MSP", moved))
+
+	// a definition is written once the committed approvals satisfy the lifecycle policy
+	definition := &kvrwset.KVWrite{Key: ccprovider.ChaincodeDefinitionKey("mycc"), Value: utils.MarshalOrPanic(def)}
+	err = validate("Org1MSP", definition)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not approved by enough organizations")
+	approve(def, "Org2MSP")
+	assert.NoError(t, validate("Org1MSP", definition))
+	other := newDefinition("2.0", 1)
+	assert.Error(t, validate("Org1MSP", &kvrwset.KVWrite{Key: ccprovider.ChaincodeDefinitionKey("mycc"), Value: utils.MarshalOrPanic(other)}))
+	assert.Error(t, validate("Org1MSP", &kvrwset.KVWrite{Key: ccprovider.ChaincodeDefinitionKey("yourcc"), Value: utils.MarshalOrPanic(def)}))
+
+	// the definition must follow the committed one
+	res := invoke(stub, COMMIT, def, "Org1MSP", false)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	err = validate("Org1MSP", definition)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the next definition must be sequence 2")
+
+	// nor are the keys deleted or other keys written
+	assert.Error(t, validate("Org1MSP", &kvrwset.KVWrite{Key: approval.Key, IsDelete: true}))
+	assert.Error(t, validate("Org1MSP", &kvrwset.KVWrite{Key: "foo", Value: []byte("bar")}))
+}
+
+// mockStateReader reads the state of a mock stub as the state of the lifecycle namespace
+type mockStateReader struct {
+	stub *shim.MockStub
+}
+
+func (r *mockStateReader) GetState(namespace string, key string) ([]byte, error) {
+	if namespace != ccprovider.LifecycleSysCC {
+		return nil, nil
+	}
+	return r.stub.State[key], nil
+}
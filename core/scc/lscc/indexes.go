@@ -0,0 +1,164 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lscc
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// NewChaincodeIndexCreator returns the listener to register with the ledgers for the lscc namespace,
+// which creates the indexes packaged with a chaincode once its instantiation or upgrade is committed.
+// Creating the indexes at commit rather than during the simulation of the transaction ensures that
+// they are created on every peer of the channel that has the chaincode installed, and not only on
+// the endorsers
+func NewChaincodeIndexCreator() ledger.StateListener {
+	return &chaincodeIndexCreator{}
+}
+
+type chaincodeIndexCreator struct {
+}
+
+// HandleStateUpdates implements method in interface `ledger.StateListener`
+func (c *chaincodeIndexCreator) HandleStateUpdates(ledgerID string, lgr ledger.PeerLedger, writes []*kvrwset.KVWrite) error {
+	for _, write := range writes {
+		if write.IsDelete || privdata.IsCollectionConfigKey(write.Key) {
+			continue
+		}
+		cd := &ccprovider.ChaincodeData{}
+		if err := proto.Unmarshal(write.Value, cd); err != nil {
+			return fmt.Errorf("invalid chaincode data for %s: %s", write.Key, err)
+		}
+		if err := createInstalledChaincodeIndexes(ledgerID, lgr, cd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewLifecycleIndexCreator returns the listener to register with the ledgers for the namespace of the
+// lifecycle system chaincode, which creates the indexes packaged with a chaincode once its definition
+// is committed, as NewChaincodeIndexCreator does for the chaincodes instantiated with lscc
+func NewLifecycleIndexCreator() ledger.StateListener {
+	return &lifecycleIndexCreator{}
+}
+
+type lifecycleIndexCreator struct {
+}
+
+// HandleStateUpdates implements method in interface `ledger.StateListener`
+func (c *lifecycleIndexCreator) HandleStateUpdates(ledgerID string, lgr ledger.PeerLedger, writes []*kvrwset.KVWrite) error {
+	for _, write := range writes {
+		// the approvals of the organizations are written to the same namespace as the definitions
+		if write.IsDelete || !strings.HasPrefix(write.Key, ccprovider.ChaincodeDefinitionKey("")) {
+			continue
+		}
+		def := &pb.ChaincodeDefinition{}
+		if err := proto.Unmarshal(write.Value, def); err != nil {
+			return fmt.Errorf("invalid chaincode definition for %s: %s", write.Key, err)
+		}
+		if err := createInstalledChaincodeIndexes(ledgerID, lgr, ccprovider.NewChaincodeDataFromDefinition(def)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createInstalledChaincodeIndexes creates the indexes packaged with the chaincode if it is installed on this peer
+func createInstalledChaincodeIndexes(ledgerID string, lgr ledger.PeerLedger, cd *ccprovider.ChaincodeData) error {
+	ccpack, err := ccprovider.GetChaincodePackageForData(cd)
+	if err != nil {
+		// the indexes are created when the chaincode gets installed
+		logger.Debugf("Chaincode %s:%s is not installed, skipping the creation of its indexes on channel %s: %s",
+			cd.Name, cd.Version, ledgerID, err)
+		return nil
+	}
+	if err := createChaincodeIndexes(lgr, ccpack); err != nil {
+		return fmt.Errorf("failed to create the indexes of chaincode %s:%s: %s", cd.Name, cd.Version, err)
+	}
+	return nil
+}
+
+// createChaincodeIndexesOnChannels creates the indexes packaged with a chaincode that has just been installed
+// on the state database of the channels where that version of the chaincode is instantiated
+func createChaincodeIndexesOnChannels(ccpack ccprovider.CCPackage) {
+	ccid := ccpack.GetDepSpec().ChaincodeSpec.ChaincodeId
+	for _, ci := range peer.GetChannelsInfo() {
+		lgr := peer.GetLedger(ci.ChannelId)
+		if lgr == nil {
+			continue
+		}
+		cd, err := getCommittedChaincodeData(lgr, ccid.Name)
+		if err != nil {
+			logger.Errorf("Failed to get the definition of chaincode %s on channel %s: %s", ccid.Name, ci.ChannelId, err)
+			continue
+		}
+		if cd == nil || cd.Version != ccid.Version || !bytes.Equal(cd.Id, ccpack.GetId()) {
+			continue
+		}
+		if err = createChaincodeIndexes(lgr, ccpack); err != nil {
+			logger.Errorf("Failed to create the indexes of chaincode %s:%s on channel %s: %s", cd.Name, cd.Version, ci.ChannelId, err)
+		}
+	}
+}
+
+// getCommittedChaincodeData returns the chaincode data committed on the ledger for the chaincode, or nil
+// if the chaincode is not instantiated. A definition committed with the lifecycle system chaincode takes
+// precedence over the chaincode data of lscc
+func getCommittedChaincodeData(lgr ledger.PeerLedger, ccname string) (*ccprovider.ChaincodeData, error) {
+	qe, err := lgr.NewQueryExecutor()
+	if err != nil {
+		return nil, err
+	}
+	defer qe.Done()
+	if cd, err := ccprovider.GetCommittedChaincodeData(qe, ccname); err != nil || cd != nil {
+		return cd, err
+	}
+	cdbytes, err := qe.GetState("lscc", ccname)
+	if err != nil || cdbytes == nil {
+		return nil, err
+	}
+	cd := &ccprovider.ChaincodeData{}
+	if err = proto.Unmarshal(cdbytes, cd); err != nil {
+		return nil, err
+	}
+	return cd, nil
+}
+
+// createChaincodeIndexes creates on the channel state database the indexes packaged with the chaincode,
+// if any. The ledger ignores the index definitions if its state database does not support indexes
+func createChaincodeIndexes(lgr ledger.PeerLedger, ccpack ccprovider.CCPackage) error {
+	depSpec := ccpack.GetDepSpec()
+	indexes, err := ccprovider.ExtractStatedbIndexes(depSpec.CodePackage)
+	if err != nil {
+		return err
+	}
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	return lgr.CreateChaincodeIndexes(depSpec.ChaincodeSpec.ChaincodeId.Name, indexes)
+}
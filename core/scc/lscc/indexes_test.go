@@ -0,0 +1,176 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lscc
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockIndexLedger struct {
+	ledger.PeerLedger
+	indexes map[string]map[string]map[string][]byte
+	state   map[string][]byte
+}
+
+func (m *mockIndexLedger) CreateChaincodeIndexes(namespace string, indexes map[string]map[string][]byte) error {
+	m.indexes[namespace] = indexes
+	return nil
+}
+
+func (m *mockIndexLedger) NewQueryExecutor() (ledger.QueryExecutor, error) {
+	return &mockIndexQueryExecutor{state: m.state}, nil
+}
+
+type mockIndexQueryExecutor struct {
+	ledger.QueryExecutor
+	state map[string][]byte
+}
+
+func (m *mockIndexQueryExecutor) GetState(namespace string, key string) ([]byte, error) {
+	return m.state[namespace+"/"+key], nil
+}
+
+func (m *mockIndexQueryExecutor) Done() {
+}
+
+func installChaincodeWithIndexes(t *testing.T, name string, version string) ccprovider.CCPackage {
+	buf := bytes.NewBuffer(nil)
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	files := map[string]string{
+		"src/cc/cc.go": "package main",
+		"META-INF/statedb/couchdb/indexes/indexOwner.json": `{"index":{"fields":["owner"]}}`,
+	}
+	for fileName, content := range files {
+		assert.NoError(t, tw.WriteHeader(&tar.Header{Name: fileName, Size: int64(len(content)), Mode: 0100644}))
+		_, err := tw.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	tw.Close()
+	gw.Close()
+
+	cds := &pb.ChaincodeDeploymentSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{Type: 1, ChaincodeId: &pb.ChaincodeID{Name: name, Path: "cc", Version: version}},
+		CodePackage:   buf.Bytes(),
+	}
+	assert.NoError(t, ccprovider.PutChaincodeIntoFS(cds))
+	ccpack, err := ccprovider.GetChaincodeFromFS(name, version)
+	assert.NoError(t, err)
+	return ccpack
+}
+
+func chaincodeDataWrite(t *testing.T, cd *ccprovider.ChaincodeData) *kvrwset.KVWrite {
+	cdbytes, err := proto.Marshal(cd)
+	assert.NoError(t, err)
+	return &kvrwset.KVWrite{Key: cd.Name, Value: cdbytes}
+}
+
+func TestChaincodeIndexCreator(t *testing.T) {
+	ccpack := installChaincodeWithIndexes(t, "indexcc", "0")
+	defer os.Remove(lscctestpath + "/indexcc.0")
+	lgr := &mockIndexLedger{indexes: make(map[string]map[string]map[string][]byte)}
+	indexCreator := NewChaincodeIndexCreator()
+
+	err := indexCreator.HandleStateUpdates(chainid, lgr, []*kvrwset.KVWrite{
+		{Key: privdata.BuildCollectionKVSKey("indexcc"), Value: []byte("collections")},
+		{Key: "deletedcc", IsDelete: true},
+		chaincodeDataWrite(t, &ccprovider.ChaincodeData{Name: "notinstalledcc", Version: "0"}),
+		chaincodeDataWrite(t, &ccprovider.ChaincodeData{Name: "indexcc", Version: "0", Id: []byte("otherhash")}),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, lgr.indexes, 0, "No indexes are expected for chaincodes that are not installed or do not match the installed package")
+
+	err = indexCreator.HandleStateUpdates(chainid, lgr, []*kvrwset.KVWrite{
+		chaincodeDataWrite(t, &ccprovider.ChaincodeData{Name: "indexcc", Version: "0", Id: ccpack.GetId()}),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]map[string][]byte{
+		"indexcc": {"couchdb": {"indexOwner.json": []byte(`{"index":{"fields":["owner"]}}`)}},
+	}, lgr.indexes)
+
+	err = indexCreator.HandleStateUpdates(chainid, lgr, []*kvrwset.KVWrite{{Key: "badcc", Value: []byte("garbage")}})
+	assert.Error(t, err)
+}
+
+func chaincodeDefinitionWrite(t *testing.T, def *pb.ChaincodeDefinition) *kvrwset.KVWrite {
+	defBytes, err := proto.Marshal(def)
+	assert.NoError(t, err)
+	return &kvrwset.KVWrite{Key: ccprovider.ChaincodeDefinitionKey(def.Name), Value: defBytes}
+}
+
+func TestLifecycleIndexCreator(t *testing.T) {
+	ccpack := installChaincodeWithIndexes(t, "lifecycleindexcc", "0")
+	defer os.Remove(lscctestpath + "/lifecycleindexcc.0")
+	lgr := &mockIndexLedger{indexes: make(map[string]map[string]map[string][]byte)}
+	indexCreator := NewLifecycleIndexCreator()
+
+	err := indexCreator.HandleStateUpdates(chainid, lgr, []*kvrwset.KVWrite{
+		{Key: ccprovider.ChaincodeApprovalKey("lifecycleindexcc", 1, "Org1MSP"), Value: []byte("approval")},
+		{Key: ccprovider.ChaincodeDefinitionKey("deletedcc"), IsDelete: true},
+		chaincodeDefinitionWrite(t, &pb.ChaincodeDefinition{Name: "notinstalledcc", Version: "0"}),
+		chaincodeDefinitionWrite(t, &pb.ChaincodeDefinition{Name: "lifecycleindexcc", Version: "0", PackageHash: []byte("otherhash")}),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, lgr.indexes, 0, "No indexes are expected for chaincodes that are not installed or do not match the installed package")
+
+	err = indexCreator.HandleStateUpdates(chainid, lgr, []*kvrwset.KVWrite{
+		chaincodeDefinitionWrite(t, &pb.ChaincodeDefinition{Name: "lifecycleindexcc", Version: "0", PackageHash: ccpack.GetId()}),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]map[string][]byte{
+		"lifecycleindexcc": {"couchdb": {"indexOwner.json": []byte(`{"index":{"fields":["owner"]}}`)}},
+	}, lgr.indexes)
+
+	err = indexCreator.HandleStateUpdates(chainid, lgr, []*kvrwset.KVWrite{
+		{Key: ccprovider.ChaincodeDefinitionKey("badcc"), Value: []byte("garbage")},
+	})
+	assert.Error(t, err)
+}
+
+func TestGetCommittedChaincodeData(t *testing.T) {
+	lscccd := chaincodeDataWrite(t, &ccprovider.ChaincodeData{Name: "mycc", Version: "0"})
+	def := chaincodeDefinitionWrite(t, &pb.ChaincodeDefinition{Name: "mycc", Version: "1", PackageHash: []byte("hash")})
+	lgr := &mockIndexLedger{state: map[string][]byte{"lscc/mycc": lscccd.Value}}
+
+	// the chaincode data of lscc is used when no definition is committed
+	cd, err := getCommittedChaincodeData(lgr, "mycc")
+	assert.NoError(t, err)
+	assert.Equal(t, "0", cd.Version)
+
+	// a committed definition takes precedence
+	lgr.state[ccprovider.LifecycleSysCC+"/"+def.Key] = def.Value
+	cd, err = getCommittedChaincodeData(lgr, "mycc")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", cd.Version)
+	assert.Equal(t, []byte("hash"), cd.Id)
+
+	cd, err = getCommittedChaincodeData(lgr, "othercc")
+	assert.NoError(t, err)
+	assert.Nil(t, cd)
+}
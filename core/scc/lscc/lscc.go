@@ -26,6 +26,7 @@ import (
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/core/policy"
@@ -229,6 +230,27 @@ func (f InstantiationPolicyViolatedErr) Error() string {
 	return "chaincode instantiation policy violated"
 }
 
+//IndexCreationErr when the indexes packaged with the chaincode are invalid on instantiate or upgrade
+type IndexCreationErr string
+
+func (f IndexCreationErr) Error() string {
+	return fmt.Sprintf("invalid chaincode indexes %s", string(f))
+}
+
+//InvalidCollectionConfigErr when the collection configuration supplied on instantiate or upgrade is invalid
+type InvalidCollectionConfigErr string
+
+func (f InvalidCollectionConfigErr) Error() string {
+	return fmt.Sprintf("invalid collection configuration %s", string(f))
+}
+
+//InvalidResourceLimitsErr when the resource limits supplied on instantiate or upgrade are invalid
+type InvalidResourceLimitsErr string
+
+func (f InvalidResourceLimitsErr) Error() string {
+	return fmt.Sprintf("invalid resource limits %s", string(f))
+}
+
 //-------------- helper functions ------------------
 //create the chaincode on the given chain
 func (lscc *LifeCycleSysCC) createChaincode(stub shim.ChaincodeStubInterface, cd *ccprovider.ChaincodeData) error {
@@ -264,6 +286,41 @@ func (lscc *LifeCycleSysCC) putChaincodeData(stub shim.ChaincodeStubInterface, c
 	return err
 }
 
+//store the collection configuration supplied for the chaincode on the given chain
+func (lscc *LifeCycleSysCC) putChaincodeCollectionData(stub shim.ChaincodeStubInterface, cd *ccprovider.ChaincodeData, collectionConfigBytes []byte) error {
+	if cd == nil {
+		return fmt.Errorf("nil ChaincodeData")
+	}
+
+	if len(collectionConfigBytes) == 0 {
+		logger.Debugf("No collection configuration specified")
+		return nil
+	}
+
+	if _, err := privdata.ParseCollectionConfigPackage(collectionConfigBytes); err != nil {
+		return InvalidCollectionConfigErr(fmt.Sprintf("%s:%s", cd.Name, err))
+	}
+
+	return stub.PutState(privdata.BuildCollectionKVSKey(cd.Name), collectionConfigBytes)
+}
+
+//checks the resource limits before they are stored in the ChaincodeData
+func (lscc *LifeCycleSysCC) getResourceLimits(ccname string, resourceLimitsBytes []byte) ([]byte, error) {
+	if len(resourceLimitsBytes) == 0 {
+		return nil, nil
+	}
+
+	limits := &pb.ChaincodeResourceLimits{}
+	if err := proto.Unmarshal(resourceLimitsBytes, limits); err != nil {
+		return nil, InvalidResourceLimitsErr(fmt.Sprintf("%s:%s", ccname, err))
+	}
+	if limits.CpuShares < 0 || limits.Memory < 0 {
+		return nil, InvalidResourceLimitsErr(fmt.Sprintf("%s:negative cpu shares or memory", ccname))
+	}
+
+	return resourceLimitsBytes, nil
+}
+
 //checks for existence of chaincode on the given channel
 func (lscc *LifeCycleSysCC) getCCInstance(stub shim.ChaincodeStubInterface, ccname string) ([]byte, error) {
 	cdbytes, err := stub.GetState(ccname)
@@ -338,6 +395,11 @@ func (lscc *LifeCycleSysCC) getChaincodes(stub shim.ChaincodeStubInterface) pb.R
 			return shim.Error(err.Error())
 		}
 
+		// skip the collection configurations stored alongside the chaincode data
+		if privdata.IsCollectionConfigKey(response.Key) {
+			continue
+		}
+
 		ccdata := &ccprovider.ChaincodeData{}
 		if err = proto.Unmarshal(response.Value, ccdata); err != nil {
 			return shim.Error(err.Error())
@@ -354,7 +416,7 @@ func (lscc *LifeCycleSysCC) getChaincodes(stub shim.ChaincodeStubInterface) pb.R
 			input = ccpack.GetDepSpec().GetChaincodeSpec().Input.String()
 		}
 
-		ccInfo := &pb.ChaincodeInfo{Name: ccdata.Name, Version: ccdata.Version, Path: path, Input: input, Escc: ccdata.Escc, Vscc: ccdata.Vscc}
+		ccInfo := &pb.ChaincodeInfo{Name: ccdata.Name, Version: ccdata.Version, Path: path, Input: input, Escc: ccdata.Escc, Vscc: ccdata.Vscc, Id: ccdata.Id}
 
 		// add this specific chaincode's metadata to the array of all chaincodes
 		ccInfoArray = append(ccInfoArray, ccInfo)
@@ -468,6 +530,10 @@ func (lscc *LifeCycleSysCC) executeInstall(stub shim.ChaincodeStubInterface, ccb
 		return fmt.Errorf("Error installing chaincode code %s:%s(%s)", cds.ChaincodeSpec.ChaincodeId.Name, cds.ChaincodeSpec.ChaincodeId.Version, err)
 	}
 
+	// the chaincode may already be instantiated on channels of the peer, whose
+	// indexes could not be created when the instantiation was committed
+	createChaincodeIndexesOnChannels(ccpack)
+
 	return err
 }
 
@@ -528,8 +594,15 @@ func (lscc *LifeCycleSysCC) checkInstantiationPolicy(stub shim.ChaincodeStubInte
 	return nil
 }
 
+// validateChaincodeIndexes checks that the index definitions packaged with the chaincode can be extracted.
+// The indexes themselves are created once the transaction commits, see NewChaincodeIndexCreator
+func (lscc *LifeCycleSysCC) validateChaincodeIndexes(ccpack ccprovider.CCPackage) error {
+	_, err := ccprovider.ExtractStatedbIndexes(ccpack.GetDepSpec().CodePackage)
+	return err
+}
+
 // executeDeploy implements the "instantiate" Invoke transaction
-func (lscc *LifeCycleSysCC) executeDeploy(stub shim.ChaincodeStubInterface, chainname string, depSpec []byte, policy []byte, escc []byte, vscc []byte) (*ccprovider.ChaincodeData, error) {
+func (lscc *LifeCycleSysCC) executeDeploy(stub shim.ChaincodeStubInterface, chainname string, depSpec []byte, policy []byte, escc []byte, vscc []byte, collectionConfigBytes []byte, resourceLimitsBytes []byte) (*ccprovider.ChaincodeData, error) {
 	cds, err := utils.GetChaincodeDeploymentSpec(depSpec)
 
 	if err != nil {
@@ -568,6 +641,11 @@ func (lscc *LifeCycleSysCC) executeDeploy(stub shim.ChaincodeStubInterface, chai
 	cd.Vscc = string(vscc)
 	cd.Policy = policy
 
+	cd.ResourceLimits, err = lscc.getResourceLimits(cd.Name, resourceLimitsBytes)
+	if err != nil {
+		return nil, err
+	}
+
 	// retrieve and evaluate instantiation policy
 	cd.InstantiationPolicy, err = lscc.getInstantiationPolicy(stub, ccpack)
 	if err != nil {
@@ -581,12 +659,24 @@ func (lscc *LifeCycleSysCC) executeDeploy(stub shim.ChaincodeStubInterface, chai
 	}
 
 	err = lscc.createChaincode(stub, cd)
+	if err != nil {
+		return nil, err
+	}
+
+	err = lscc.putChaincodeCollectionData(stub, cd, collectionConfigBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = lscc.validateChaincodeIndexes(ccpack); err != nil {
+		return nil, IndexCreationErr(fmt.Sprintf("%s:%s", cd.Name, err))
+	}
 
-	return cd, err
+	return cd, nil
 }
 
 // executeUpgrade implements the "upgrade" Invoke transaction.
-func (lscc *LifeCycleSysCC) executeUpgrade(stub shim.ChaincodeStubInterface, chainName string, depSpec []byte, policy []byte, escc []byte, vscc []byte) (*ccprovider.ChaincodeData, error) {
+func (lscc *LifeCycleSysCC) executeUpgrade(stub shim.ChaincodeStubInterface, chainName string, depSpec []byte, policy []byte, escc []byte, vscc []byte, collectionConfigBytes []byte, resourceLimitsBytes []byte) (*ccprovider.ChaincodeData, error) {
 	cds, err := utils.GetChaincodeDeploymentSpec(depSpec)
 	if err != nil {
 		return nil, err
@@ -645,6 +735,11 @@ func (lscc *LifeCycleSysCC) executeUpgrade(stub shim.ChaincodeStubInterface, cha
 	cd.Vscc = string(vscc)
 	cd.Policy = policy
 
+	cd.ResourceLimits, err = lscc.getResourceLimits(cd.Name, resourceLimitsBytes)
+	if err != nil {
+		return nil, err
+	}
+
 	// retrieve and evaluate new instantiation policy
 	cd.InstantiationPolicy, err = lscc.getInstantiationPolicy(stub, ccpack)
 	if err != nil {
@@ -662,6 +757,15 @@ func (lscc *LifeCycleSysCC) executeUpgrade(stub shim.ChaincodeStubInterface, cha
 		return nil, err
 	}
 
+	err = lscc.putChaincodeCollectionData(stub, cd, collectionConfigBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = lscc.validateChaincodeIndexes(ccpack); err != nil {
+		return nil, IndexCreationErr(fmt.Sprintf("%s:%s", cd.Name, err))
+	}
+
 	return cd, nil
 }
 
@@ -716,7 +820,7 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 		}
 		return shim.Success([]byte("OK"))
 	case DEPLOY:
-		if len(args) < 3 || len(args) > 6 {
+		if len(args) < 3 || len(args) > 8 {
 			return shim.Error(InvalidArgsLenErr(len(args)).Error())
 		}
 
@@ -737,6 +841,8 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 		// args[3] is a marshalled SignaturePolicyEnvelope representing the endorsement policy
 		// args[4] is the name of escc
 		// args[5] is the name of vscc
+		// args[6] is a marshalled CollectionConfigPackage defining the private data collections
+		// args[7] is a marshalled ChaincodeResourceLimits limiting the resources of the chaincode
 		var policy []byte
 		if len(args) > 3 && len(args[3]) > 0 {
 			policy = args[3]
@@ -758,7 +864,17 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 			vscc = []byte("vscc")
 		}
 
-		cd, err := lscc.executeDeploy(stub, chainname, depSpec, policy, escc, vscc)
+		var collectionsConfig []byte
+		if len(args) > 6 && args[6] != nil {
+			collectionsConfig = args[6]
+		}
+
+		var resourceLimits []byte
+		if len(args) > 7 && args[7] != nil {
+			resourceLimits = args[7]
+		}
+
+		cd, err := lscc.executeDeploy(stub, chainname, depSpec, policy, escc, vscc, collectionsConfig, resourceLimits)
 		if err != nil {
 			return shim.Error(err.Error())
 		}
@@ -768,7 +884,7 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 		}
 		return shim.Success(cdbytes)
 	case UPGRADE:
-		if len(args) < 3 || len(args) > 6 {
+		if len(args) < 3 || len(args) > 8 {
 			return shim.Error(InvalidArgsLenErr(len(args)).Error())
 		}
 
@@ -786,6 +902,8 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 		// args[3] is a marshalled SignaturePolicyEnvelope representing the endorsement policy
 		// args[4] is the name of escc
 		// args[5] is the name of vscc
+		// args[6] is a marshalled CollectionConfigPackage defining the private data collections
+		// args[7] is a marshalled ChaincodeResourceLimits limiting the resources of the chaincode
 		var policy []byte
 		if len(args) > 3 && len(args[3]) > 0 {
 			policy = args[3]
@@ -807,7 +925,17 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 			vscc = []byte("vscc")
 		}
 
-		cd, err := lscc.executeUpgrade(stub, chainname, depSpec, policy, escc, vscc)
+		var collectionsConfig []byte
+		if len(args) > 6 && args[6] != nil {
+			collectionsConfig = args[6]
+		}
+
+		var resourceLimits []byte
+		if len(args) > 7 && args[7] != nil {
+			resourceLimits = args[7]
+		}
+
+		cd, err := lscc.executeUpgrade(stub, chainname, depSpec, policy, escc, vscc, collectionsConfig, resourceLimits)
 		if err != nil {
 			return shim.Error(err.Error())
 		}
@@ -29,6 +29,7 @@ import (
 	"github.com/hyperledger/fabric/core/common/ccpackage"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/ledger"
 	//"github.com/hyperledger/fabric/core/container"
 	"archive/tar"
 	"bytes"
@@ -67,6 +68,10 @@ func (c *mocksccProviderImpl) IsSysCCAndNotInvokableCC2CC(name string) bool {
 	return false
 }
 
+func (c *mocksccProviderImpl) GetQueryExecutorForLedger(cid string) (ledger.QueryExecutor, error) {
+	return nil, nil
+}
+
 func register(stub *shim.MockStub, ccname string) error {
 	args := [][]byte{[]byte("register"), []byte(ccname)}
 	if res := stub.MockInvoke("1", args); res.Status != shim.OK {
@@ -391,6 +396,133 @@ func TestRedeploy(t *testing.T) {
 	}
 }
 
+//TestDeployWithCollections tests deploying a chaincode along with its private data collections
+func TestDeployWithCollections(t *testing.T) {
+	scc := new(LifeCycleSysCC)
+	stub := shim.NewMockStub("lscc", scc)
+
+	if res := stub.MockInit("1", nil); res.Status != shim.OK {
+		fmt.Println("Init failed", string(res.Message))
+		t.FailNow()
+	}
+
+	identityDeserializer := &policy.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
+	policyManagerGetter := &policy.MockChannelPolicyManagerGetter{
+		Managers: map[string]policies.Manager{
+			"test": &policy.MockChannelPolicyManager{MockPolicy: &policy.MockPolicy{Deserializer: identityDeserializer}},
+		},
+	}
+	scc.policyChecker = policy.NewPolicyChecker(
+		policyManagerGetter,
+		identityDeserializer,
+		&policy.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+	)
+	sProp, _ := utils.MockSignedEndorserProposalOrPanic("", &pb.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
+	identityDeserializer.Msg = sProp.ProposalBytes
+	sProp.Signature = sProp.ProposalBytes
+
+	cds, err := constructDeploymentSpec("example02", "github.com/hyperledger/fabric/examples/chaincode/go/chaincode_example02", "0", [][]byte{[]byte("init"), []byte("a"), []byte("100"), []byte("b"), []byte("200")}, true)
+	if err != nil {
+		t.FailNow()
+	}
+	defer os.Remove(lscctestpath + "/example02.0")
+	var b []byte
+	if b, err = proto.Marshal(cds); err != nil || b == nil {
+		t.FailNow()
+	}
+
+	collections := constructCollectionConfigPackage("coll1", 1, 2)
+	args := [][]byte{[]byte(DEPLOY), []byte("test"), b, nil, nil, nil, collections}
+	if res := stub.MockInvoke("1", args); res.Status != shim.OK {
+		t.Logf("Deploy failed: %s", res.Message)
+		t.FailNow()
+	}
+	if !bytes.Equal(stub.State["example02~collection"], collections) {
+		t.Logf("Collection configuration not stored")
+		t.FailNow()
+	}
+
+	// the stored collection configuration is not reported as a chaincode
+	args = [][]byte{[]byte(GETCHAINCODES)}
+	res := stub.MockInvokeWithSignedProposal("1", args, sProp)
+	if res.Status != shim.OK {
+		t.FailNow()
+	}
+	cqr := &pb.ChaincodeQueryResponse{}
+	if err = proto.Unmarshal(res.Payload, cqr); err != nil || len(cqr.GetChaincodes()) != 1 {
+		t.Logf("Expected 1 chaincode, found %d", len(cqr.GetChaincodes()))
+		t.FailNow()
+	}
+
+	// a collection whose maximum peer count is lower than the required one is rejected
+	stub = shim.NewMockStub("lscc", scc)
+	invalidCollections := constructCollectionConfigPackage("coll1", 2, 1)
+	args = [][]byte{[]byte(DEPLOY), []byte("test"), b, nil, nil, nil, invalidCollections}
+	if res := stub.MockInvoke("1", args); res.Status == shim.OK {
+		t.Logf("Expected deploy with invalid collections to fail")
+		t.FailNow()
+	}
+}
+
+func TestDeployWithResourceLimits(t *testing.T) {
+	scc := new(LifeCycleSysCC)
+	stub := shim.NewMockStub("lscc", scc)
+
+	if res := stub.MockInit("1", nil); res.Status != shim.OK {
+		fmt.Println("Init failed", string(res.Message))
+		t.FailNow()
+	}
+
+	cds, err := constructDeploymentSpec("example02", "github.com/hyperledger/fabric/examples/chaincode/go/chaincode_example02", "0", [][]byte{[]byte("init"), []byte("a"), []byte("100"), []byte("b"), []byte("200")}, true)
+	if err != nil {
+		t.FailNow()
+	}
+	defer os.Remove(lscctestpath + "/example02.0")
+	var b []byte
+	if b, err = proto.Marshal(cds); err != nil || b == nil {
+		t.FailNow()
+	}
+
+	limits, _ := proto.Marshal(&pb.ChaincodeResourceLimits{Memory: 268435456, MaxConcurrency: 4, ExecuteTimeout: 5000})
+	args := [][]byte{[]byte(DEPLOY), []byte("test"), b, nil, nil, nil, nil, limits}
+	res := stub.MockInvoke("1", args)
+	if res.Status != shim.OK {
+		t.Logf("Deploy failed: %s", res.Message)
+		t.FailNow()
+	}
+	cd := &ccprovider.ChaincodeData{}
+	if err = proto.Unmarshal(res.Payload, cd); err != nil || !bytes.Equal(cd.ResourceLimits, limits) {
+		t.Logf("Resource limits not stored")
+		t.FailNow()
+	}
+
+	// negative limits are rejected
+	stub = shim.NewMockStub("lscc", scc)
+	invalidLimits, _ := proto.Marshal(&pb.ChaincodeResourceLimits{Memory: -1})
+	args = [][]byte{[]byte(DEPLOY), []byte("test"), b, nil, nil, nil, nil, invalidLimits}
+	if res := stub.MockInvoke("1", args); res.Status == shim.OK {
+		t.Logf("Expected deploy with invalid resource limits to fail")
+		t.FailNow()
+	}
+}
+
+func constructCollectionConfigPackage(name string, requiredPeerCount, maximumPeerCount int32) []byte {
+	policyEnvelope := &common.SignaturePolicyEnvelope{}
+	proto.Unmarshal(cauthdsl.SignedByAnyMember([]string{"Org1MSP"}), policyEnvelope)
+	ccp := &common.CollectionConfigPackage{Config: []*common.CollectionConfig{{
+		Payload: &common.CollectionConfig_StaticCollectionConfig{
+			StaticCollectionConfig: &common.StaticCollectionConfig{
+				Name:              name,
+				MemberOrgsPolicy:  &common.CollectionPolicyConfig{Payload: &common.CollectionPolicyConfig_SignaturePolicy{SignaturePolicy: policyEnvelope}},
+				RequiredPeerCount: requiredPeerCount,
+				MaximumPeerCount:  maximumPeerCount,
+			},
+		},
+	}}}
+	ccpBytes, _ := proto.Marshal(ccp)
+	return ccpBytes
+}
+
 //TestMultipleDeploy tests deploying multiple chaincodeschaincodes
 func TestMultipleDeploy(t *testing.T) {
 	scc := new(LifeCycleSysCC)
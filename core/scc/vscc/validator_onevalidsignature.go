@@ -23,9 +23,14 @@ import (
 	"github.com/hyperledger/fabric/common/cauthdsl"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/scc/lifecycle"
 	"github.com/hyperledger/fabric/core/scc/lscc"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/protos/common"
+	mspproto "github.com/hyperledger/fabric/protos/msp"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 )
@@ -51,13 +56,16 @@ func (vscc *ValidatorOneValidSignature) Init(stub shim.ChaincodeStubInterface) p
 // policy specification to be coded as a transaction of the chaincode and the client
 // selecting which policy to use for validation using parameter function
 // @return serialized Block of valid and invalid transactions indentified
-// Note that Peer calls this function with 3 arguments, where args[0] is the
-// function name, args[1] is the Envelope and args[2] is the validation policy
+// Note that Peer calls this function with 3 or 4 arguments, where args[0] is the
+// function name, args[1] is the Envelope, args[2] is the validation policy and
+// the optional args[3] carries the key-level endorsement policies of the keys
+// written by the transaction
 func (vscc *ValidatorOneValidSignature) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 	// TODO: document the argument in some white paper or design document
 	// args[0] - function name (not used now)
 	// args[1] - serialized Envelope
 	// args[2] - serialized policy
+	// args[3] - serialized KeyEndorsementPolicies (optional)
 	args := stub.GetArgs()
 	if len(args) < 3 {
 		return shim.Error("Incorrect number of arguments")
@@ -101,6 +109,15 @@ func (vscc *ValidatorOneValidSignature) Invoke(stub shim.ChaincodeStubInterface)
 		return shim.Error(err.Error())
 	}
 
+	// get the key-level policies, if any
+	keyPolicies := &pb.KeyEndorsementPolicies{}
+	if len(args) > 3 && args[3] != nil {
+		if err = proto.Unmarshal(args[3], keyPolicies); err != nil {
+			logger.Errorf("VSCC error: unmarshalling of the key-level endorsement policies failed, err %s", err)
+			return shim.Error(err.Error())
+		}
+	}
+
 	// validate the payload type
 	if common.HeaderType(chdr.Type) != common.HeaderType_ENDORSER_TRANSACTION {
 		logger.Errorf("Only Endorser Transactions are supported, provided type %d", chdr.Type)
@@ -139,18 +156,42 @@ func (vscc *ValidatorOneValidSignature) Invoke(stub shim.ChaincodeStubInterface)
 			}
 		}
 
-		// evaluate the signature set against the policy
-		err = policy.Evaluate(signatureSet)
-		if err != nil {
-			return shim.Error(fmt.Sprintf("VSCC error: policy evaluation failed, err %s", err))
-		}
-
 		hdrExt, err := utils.GetChaincodeHeaderExtension(payl.Header)
 		if err != nil {
 			logger.Errorf("VSCC error: GetChaincodeHeaderExtension failed, err %s", err)
 			return shim.Error(err.Error())
 		}
 
+		// the chaincode policy is evaluated unless every write is covered by a key-level policy
+		chaincodePolicyNeeded := true
+		if len(keyPolicies.Policies) > 0 {
+			txRWSet, err := getTxRWSet(prespBytes)
+			if err != nil {
+				logger.Errorf("VSCC error: getTxRWSet failed, err %s", err)
+				return shim.Error(err.Error())
+			}
+			var policiesToEvaluate [][]byte
+			policiesToEvaluate, chaincodePolicyNeeded = getPoliciesToEvaluate(txRWSet, hdrExt.ChaincodeId.Name, keyPolicies.Policies)
+			for _, keyPolicyBytes := range policiesToEvaluate {
+				keyPolicy, _, err := pProvider.NewPolicy(keyPolicyBytes)
+				if err != nil {
+					logger.Errorf("VSCC error: pProvider.NewPolicy failed for a key-level policy, err %s", err)
+					return shim.Error(err.Error())
+				}
+				if err = keyPolicy.Evaluate(signatureSet); err != nil {
+					return shim.Error(fmt.Sprintf("VSCC error: key-level policy evaluation failed, err %s", err))
+				}
+			}
+		}
+
+		// evaluate the signature set against the policy
+		if chaincodePolicyNeeded {
+			err = policy.Evaluate(signatureSet)
+			if err != nil {
+				return shim.Error(fmt.Sprintf("VSCC error: policy evaluation failed, err %s", err))
+			}
+		}
+
 		// do some extra validation that is specific to lscc
 		if hdrExt.ChaincodeId.Name == "lscc" {
 			err = vscc.ValidateLSCCInvocation(cap)
@@ -159,6 +200,13 @@ func (vscc *ValidatorOneValidSignature) Invoke(stub shim.ChaincodeStubInterface)
 				return shim.Error(err.Error())
 			}
 		}
+
+		// and to the state of the lifecycle system chaincode, whichever chaincode was invoked
+		err = vscc.ValidateLifecycleWrites(chdr.ChannelId, payl.Header, hdrExt.ChaincodeId.Name, prespBytes)
+		if err != nil {
+			logger.Errorf("VSCC error: ValidateLifecycleWrites failed, err %s", err)
+			return shim.Error(err.Error())
+		}
 	}
 
 	logger.Debugf("VSCC exists successfully")
@@ -166,6 +214,114 @@ func (vscc *ValidatorOneValidSignature) Invoke(stub shim.ChaincodeStubInterface)
 	return shim.Success(nil)
 }
 
+// getTxRWSet extracts the read-write set of the transaction from the proposal response payload
+func getTxRWSet(prespBytes []byte) (*rwsetutil.TxRwSet, error) {
+	presp, err := utils.GetProposalResponsePayload(prespBytes)
+	if err != nil {
+		return nil, err
+	}
+	ccAction, err := utils.GetChaincodeAction(presp.Extension)
+	if err != nil {
+		return nil, err
+	}
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err = txRWSet.FromProtoBytes(ccAction.Results); err != nil {
+		return nil, err
+	}
+	return txRWSet, nil
+}
+
+// getPoliciesToEvaluate returns the distinct key-level policies of the keys of the namespace written by the
+// transaction (either the value or the metadata), and whether the chaincode policy has to be evaluated as well.
+// The latter is the case if the transaction writes a key of the namespace that has no key-level policy, writes
+// private data or other namespaces, or does not write at all
+func getPoliciesToEvaluate(txRWSet *rwsetutil.TxRwSet, ns string, keyPolicies map[string][]byte) ([][]byte, bool) {
+	var policies [][]byte
+	seen := make(map[string]bool)
+	chaincodePolicyNeeded := false
+	numWrites := 0
+	addKey := func(key string) {
+		numWrites++
+		keyPolicy, ok := keyPolicies[key]
+		if !ok || len(keyPolicy) == 0 {
+			chaincodePolicyNeeded = true
+			return
+		}
+		if !seen[string(keyPolicy)] {
+			seen[string(keyPolicy)] = true
+			policies = append(policies, keyPolicy)
+		}
+	}
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if nsRWSet.NameSpace != ns {
+			if len(nsRWSet.KvRwSet.Writes) > 0 || len(nsRWSet.KvRwSet.MetadataWrites) > 0 {
+				chaincodePolicyNeeded = true
+			}
+			continue
+		}
+		for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+			addKey(kvWrite.Key)
+		}
+		for _, kvMetadataWrite := range nsRWSet.KvRwSet.MetadataWrites {
+			addKey(kvMetadataWrite.Key)
+		}
+		for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+			if len(collHashedRWSet.HashedRwSet.HashedWrites) > 0 {
+				chaincodePolicyNeeded = true
+			}
+		}
+	}
+	if numWrites == 0 {
+		chaincodePolicyNeeded = true
+	}
+	return policies, chaincodePolicyNeeded
+}
+
+// ValidateLifecycleWrites checks the writes of a transaction to the state of the lifecycle
+// system chaincode, which only the lifecycle system chaincode itself writes, against the
+// committed state of the channel
+func (vscc *ValidatorOneValidSignature) ValidateLifecycleWrites(chainID string, header *common.Header, ccname string, prespBytes []byte) error {
+	txRWSet, err := getTxRWSet(prespBytes)
+	if err != nil {
+		logger.Errorf("VSCC error: getTxRWSet failed, err %s", err)
+		return err
+	}
+
+	var nsRWSet *rwsetutil.NsRwSet
+	for _, ns := range txRWSet.NsRwSets {
+		if ns.NameSpace == ccprovider.LifecycleSysCC {
+			nsRWSet = ns
+		}
+	}
+	if nsRWSet == nil || (len(nsRWSet.KvRwSet.Writes) == 0 && len(nsRWSet.KvRwSet.MetadataWrites) == 0 && len(nsRWSet.CollHashedRwSets) == 0) {
+		return nil
+	}
+	if ccname != ccprovider.LifecycleSysCC {
+		return fmt.Errorf("VSCC error: chaincode %s cannot write the state of %s", ccname, ccprovider.LifecycleSysCC)
+	}
+
+	shdr, err := utils.GetSignatureHeader(header.SignatureHeader)
+	if err != nil {
+		return err
+	}
+	creator := &mspproto.SerializedIdentity{}
+	if err = proto.Unmarshal(shdr.Creator, creator); err != nil {
+		return fmt.Errorf("VSCC error: invalid creator of the transaction, err %s", err)
+	}
+
+	qe, err := sysccprovider.GetSystemChaincodeProvider().GetQueryExecutorForLedger(chainID)
+	if err != nil {
+		return err
+	}
+	defer qe.Done()
+
+	if err = lifecycle.New().ValidateWrites(qe, chainID, creator.Mspid, nsRWSet); err != nil {
+		return fmt.Errorf("VSCC error: invalid writes to the state of %s, err %s", ccprovider.LifecycleSysCC, err)
+	}
+
+	return nil
+}
+
 func (vscc *ValidatorOneValidSignature) ValidateLSCCInvocation(cap *pb.ChaincodeActionPayload) error {
 	cpp, err := utils.GetChaincodeProposalPayload(cap.ChaincodeProposalPayload)
 	if err != nil {
@@ -24,10 +24,13 @@ import (
 	"github.com/hyperledger/fabric/common/cauthdsl"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/msp"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/msp/mgmt/testtools"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 )
@@ -126,6 +129,68 @@ func TestInvoke(t *testing.T) {
 	}
 }
 
+func TestGetPoliciesToEvaluate(t *testing.T) {
+	newTxRWSet := func(nsRWSets ...*rwsetutil.NsRwSet) *rwsetutil.TxRwSet {
+		return &rwsetutil.TxRwSet{NsRwSets: nsRWSets}
+	}
+	keyPolicies := map[string][]byte{"key1": []byte("policy1"), "key2": []byte("policy1"), "key3": []byte("policy3")}
+
+	// all the writes are covered by the key-level policies, each distinct policy is evaluated once
+	txRWSet := newTxRWSet(&rwsetutil.NsRwSet{NameSpace: "foo", KvRwSet: &kvrwset.KVRWSet{
+		Writes:         []*kvrwset.KVWrite{{Key: "key1"}, {Key: "key2"}},
+		MetadataWrites: []*kvrwset.KVMetadataWrite{{Key: "key3"}},
+	}})
+	policies, ccPolicyNeeded := getPoliciesToEvaluate(txRWSet, "foo", keyPolicies)
+	if ccPolicyNeeded || len(policies) != 2 || string(policies[0]) != "policy1" || string(policies[1]) != "policy3" {
+		t.Fatalf("Unexpected policies %s (chaincode policy needed: %t)", policies, ccPolicyNeeded)
+	}
+
+	// a key without a key-level policy falls back to the chaincode policy
+	txRWSet = newTxRWSet(&rwsetutil.NsRwSet{NameSpace: "foo", KvRwSet: &kvrwset.KVRWSet{
+		Writes: []*kvrwset.KVWrite{{Key: "key1"}, {Key: "key4"}},
+	}})
+	if policies, ccPolicyNeeded = getPoliciesToEvaluate(txRWSet, "foo", keyPolicies); !ccPolicyNeeded || len(policies) != 1 {
+		t.Fatalf("Unexpected policies %s (chaincode policy needed: %t)", policies, ccPolicyNeeded)
+	}
+
+	// so do the read-only transactions and the writes to other namespaces
+	txRWSet = newTxRWSet(&rwsetutil.NsRwSet{NameSpace: "foo", KvRwSet: &kvrwset.KVRWSet{}})
+	if _, ccPolicyNeeded = getPoliciesToEvaluate(txRWSet, "foo", keyPolicies); !ccPolicyNeeded {
+		t.Fatal("Expected the chaincode policy to be evaluated for a read-only transaction")
+	}
+	txRWSet = newTxRWSet(
+		&rwsetutil.NsRwSet{NameSpace: "bar", KvRwSet: &kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{{Key: "key1"}}}},
+		&rwsetutil.NsRwSet{NameSpace: "foo", KvRwSet: &kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{{Key: "key1"}}}},
+	)
+	if _, ccPolicyNeeded = getPoliciesToEvaluate(txRWSet, "foo", keyPolicies); !ccPolicyNeeded {
+		t.Fatal("Expected the chaincode policy to be evaluated for a transaction writing other namespaces")
+	}
+}
+
+func TestValidateLifecycleWrites(t *testing.T) {
+	prespBytes := func(nsRWSets ...*rwsetutil.NsRwSet) []byte {
+		results, err := (&rwsetutil.TxRwSet{NsRwSets: nsRWSets}).ToProtoBytes()
+		if err != nil {
+			t.Fatalf("ToProtoBytes failed, err %s", err)
+		}
+		return utils.MarshalOrPanic(&peer.ProposalResponsePayload{Extension: utils.MarshalOrPanic(&peer.ChaincodeAction{Results: results})})
+	}
+	vscc := &ValidatorOneValidSignature{}
+
+	// the transactions not writing the lifecycle state are not checked
+	foo := &rwsetutil.NsRwSet{NameSpace: "foo", KvRwSet: &kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{{Key: "key1"}}}}
+	lifecycleReads := &rwsetutil.NsRwSet{NameSpace: ccprovider.LifecycleSysCC, KvRwSet: &kvrwset.KVRWSet{Reads: []*kvrwset.KVRead{{Key: "namespaces/foo"}}}}
+	if err := vscc.ValidateLifecycleWrites(chainId, nil, "foo", prespBytes(foo, lifecycleReads)); err != nil {
+		t.Fatalf("Unexpected failure, err %s", err)
+	}
+
+	// and only the lifecycle system chaincode writes it
+	lifecycleWrites := &rwsetutil.NsRwSet{NameSpace: ccprovider.LifecycleSysCC, KvRwSet: &kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{{Key: "namespaces/foo"}}}}
+	if err := vscc.ValidateLifecycleWrites(chainId, nil, "foo", prespBytes(foo, lifecycleWrites)); err == nil {
+		t.Fatal("Expected the writes of foo to the lifecycle state to be rejected")
+	}
+}
+
 var id msp.SigningIdentity
 var sid []byte
 var mspid string
@@ -0,0 +1,237 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transientstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+)
+
+var logger = flogging.MustGetLogger("transientstore")
+
+var (
+	compositeKeySep   = []byte{0x00}
+	lastKeyIndicator  = byte(0x01)
+	pvtDataKeyPrefix  = []byte("P")
+	heightIndexPrefix = []byte("H")
+	emptyValue        = []byte{}
+)
+
+// StoreProvider provides an instance of a transient store per ledger
+type StoreProvider interface {
+	// OpenStore returns a handle to the transient store of the given ledger
+	OpenStore(ledgerID string) (Store, error)
+	// Close closes all the stores and releases any resources held by StoreProvider
+	Close()
+}
+
+// Store holds the private read-write sets of the transactions from the time they are endorsed (or received from the
+// endorsing peers over gossip) until the time the transactions are committed. The private read-write sets are never
+// written to the blocks and are transferred from this store into the private state when a transaction is committed
+type Store interface {
+	// Persist stores the private read-write set of a transaction. endorsementBlkHt is the height of the ledger at the time
+	// the private read-write set is received; it is used for purging the data of the transactions that never get committed.
+	// Persisting the private read-write set of a collection that is already present for the transaction overwrites the same
+	Persist(txid string, endorsementBlkHt uint64, privateSimulationResults *rwset.TxPvtReadWriteSet) error
+	// GetTxPvtRWSetByTxid returns the private read-write set of the transaction, assembled from all the collections
+	// persisted for the transaction. It returns nil if nothing is persisted for the transaction
+	GetTxPvtRWSetByTxid(txid string) (*rwset.TxPvtReadWriteSet, error)
+	// PurgeByTxids removes the private read-write sets of the given transactions
+	PurgeByTxids(txids []string) error
+	// PurgeByHeight removes the private read-write sets that were persisted at an endorsement block height
+	// lower than the given height
+	PurgeByHeight(maxBlockNumToRetain uint64) error
+}
+
+// storeProvider implements interface StoreProvider
+type storeProvider struct {
+	dbProvider *leveldbhelper.Provider
+}
+
+// NewStoreProvider constructs a StoreProvider backed by a leveldb
+func NewStoreProvider() StoreProvider {
+	dbPath := ledgerconfig.GetTransientStorePath()
+	logger.Debugf("constructing transient StoreProvider dbPath=%s", dbPath)
+	return &storeProvider{leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: dbPath})}
+}
+
+// OpenStore implements method in interface StoreProvider
+func (provider *storeProvider) OpenStore(ledgerID string) (Store, error) {
+	return &store{provider.dbProvider.GetDBHandle(ledgerID), ledgerID}, nil
+}
+
+// Close implements method in interface StoreProvider
+func (provider *storeProvider) Close() {
+	provider.dbProvider.Close()
+}
+
+// store implements interface Store. For each collection of a transaction, two entries are maintained
+// <"P", txid, namespace, collection> -> <endorsementBlkHt, collection rwset> and
+// <"H", endorsementBlkHt, txid, namespace, collection> -> <empty>, the latter serving the purge by height
+type store struct {
+	db       *leveldbhelper.DBHandle
+	ledgerID string
+}
+
+// Persist implements method in interface Store
+func (s *store) Persist(txid string, endorsementBlkHt uint64, privateSimulationResults *rwset.TxPvtReadWriteSet) error {
+	logger.Debugf("Channel [%s]: Persisting private data of transaction [%s] at height [%d]", s.ledgerID, txid, endorsementBlkHt)
+	batch := leveldbhelper.NewUpdateBatch()
+	for _, nsPvtRWSet := range privateSimulationResults.NsPvtRwset {
+		for _, collPvtRWSet := range nsPvtRWSet.CollectionPvtRwset {
+			pvtDataKey := constructPvtDataKey(txid, nsPvtRWSet.Namespace, collPvtRWSet.CollectionName)
+			// remove the height index of an earlier entry for the same collection, if any
+			existingValue, err := s.db.Get(pvtDataKey)
+			if err != nil {
+				return err
+			}
+			if existingValue != nil {
+				existingHt, _ := decodePvtDataValue(existingValue)
+				batch.Delete(constructHeightIndexKey(existingHt, txid, nsPvtRWSet.Namespace, collPvtRWSet.CollectionName))
+			}
+			batch.Put(pvtDataKey, encodePvtDataValue(endorsementBlkHt, collPvtRWSet.Rwset))
+			batch.Put(constructHeightIndexKey(endorsementBlkHt, txid, nsPvtRWSet.Namespace, collPvtRWSet.CollectionName), emptyValue)
+		}
+	}
+	return s.db.WriteBatch(batch, true)
+}
+
+// GetTxPvtRWSetByTxid implements method in interface Store
+func (s *store) GetTxPvtRWSetByTxid(txid string) (*rwset.TxPvtReadWriteSet, error) {
+	startKey, endKey := constructTxRangeKeys(txid)
+	itr := s.db.GetIterator(startKey, endKey)
+	defer itr.Release()
+
+	var txPvtRWSet *rwset.TxPvtReadWriteSet
+	var nsPvtRWSet *rwset.NsPvtReadWriteSet
+	for itr.Next() {
+		ns, coll, err := splitPvtDataKey(txid, itr.Key())
+		if err != nil {
+			return nil, err
+		}
+		_, collRWSetBytes := decodePvtDataValue(itr.Value())
+		if txPvtRWSet == nil {
+			txPvtRWSet = &rwset.TxPvtReadWriteSet{DataModel: rwset.TxReadWriteSet_KV}
+		}
+		if nsPvtRWSet == nil || nsPvtRWSet.Namespace != ns {
+			nsPvtRWSet = &rwset.NsPvtReadWriteSet{Namespace: ns}
+			txPvtRWSet.NsPvtRwset = append(txPvtRWSet.NsPvtRwset, nsPvtRWSet)
+		}
+		nsPvtRWSet.CollectionPvtRwset = append(nsPvtRWSet.CollectionPvtRwset,
+			&rwset.CollectionPvtReadWriteSet{CollectionName: coll, Rwset: append([]byte(nil), collRWSetBytes...)})
+	}
+	return txPvtRWSet, nil
+}
+
+// PurgeByTxids implements method in interface Store
+func (s *store) PurgeByTxids(txids []string) error {
+	batch := leveldbhelper.NewUpdateBatch()
+	for _, txid := range txids {
+		startKey, endKey := constructTxRangeKeys(txid)
+		itr := s.db.GetIterator(startKey, endKey)
+		for itr.Next() {
+			pvtDataKey := append([]byte(nil), itr.Key()...)
+			ns, coll, err := splitPvtDataKey(txid, pvtDataKey)
+			if err != nil {
+				itr.Release()
+				return err
+			}
+			endorsementBlkHt, _ := decodePvtDataValue(itr.Value())
+			batch.Delete(pvtDataKey)
+			batch.Delete(constructHeightIndexKey(endorsementBlkHt, txid, ns, coll))
+		}
+		itr.Release()
+	}
+	return s.db.WriteBatch(batch, true)
+}
+
+// PurgeByHeight implements method in interface Store
+func (s *store) PurgeByHeight(maxBlockNumToRetain uint64) error {
+	logger.Debugf("Channel [%s]: Purging private data persisted below height [%d]", s.ledgerID, maxBlockNumToRetain)
+	startKey := heightIndexPrefix
+	endKey := append(append([]byte(nil), heightIndexPrefix...), encodeHeight(maxBlockNumToRetain)...)
+	itr := s.db.GetIterator(startKey, endKey)
+	defer itr.Release()
+	batch := leveldbhelper.NewUpdateBatch()
+	for itr.Next() {
+		heightIndexKey := append([]byte(nil), itr.Key()...)
+		txid, ns, coll, err := splitHeightIndexKey(heightIndexKey)
+		if err != nil {
+			return err
+		}
+		batch.Delete(heightIndexKey)
+		batch.Delete(constructPvtDataKey(txid, ns, coll))
+	}
+	return s.db.WriteBatch(batch, true)
+}
+
+func constructPvtDataKey(txid, ns, coll string) []byte {
+	return bytes.Join([][]byte{pvtDataKeyPrefix, []byte(txid), []byte(ns), []byte(coll)}, compositeKeySep)
+}
+
+func constructTxRangeKeys(txid string) ([]byte, []byte) {
+	startKey := bytes.Join([][]byte{pvtDataKeyPrefix, []byte(txid), {}}, compositeKeySep)
+	endKey := append([]byte(nil), startKey...)
+	endKey[len(endKey)-1] = lastKeyIndicator
+	return startKey, endKey
+}
+
+func splitPvtDataKey(txid string, pvtDataKey []byte) (string, string, error) {
+	prefixLen := len(pvtDataKeyPrefix) + len(compositeKeySep) + len(txid) + len(compositeKeySep)
+	split := bytes.SplitN(pvtDataKey[prefixLen:], compositeKeySep, 2)
+	if len(split) != 2 {
+		return "", "", fmt.Errorf("Invalid key [%#v] in transient store", pvtDataKey)
+	}
+	return string(split[0]), string(split[1]), nil
+}
+
+func constructHeightIndexKey(endorsementBlkHt uint64, txid, ns, coll string) []byte {
+	return bytes.Join([][]byte{append(append([]byte(nil), heightIndexPrefix...), encodeHeight(endorsementBlkHt)...),
+		[]byte(txid), []byte(ns), []byte(coll)}, compositeKeySep)
+}
+
+func splitHeightIndexKey(heightIndexKey []byte) (string, string, string, error) {
+	prefixLen := len(heightIndexPrefix) + 8 + len(compositeKeySep)
+	if len(heightIndexKey) < prefixLen {
+		return "", "", "", fmt.Errorf("Invalid key [%#v] in transient store", heightIndexKey)
+	}
+	split := bytes.SplitN(heightIndexKey[prefixLen:], compositeKeySep, 3)
+	if len(split) != 3 {
+		return "", "", "", fmt.Errorf("Invalid key [%#v] in transient store", heightIndexKey)
+	}
+	return string(split[0]), string(split[1]), string(split[2]), nil
+}
+
+func encodeHeight(height uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, height)
+	return b
+}
+
+func encodePvtDataValue(endorsementBlkHt uint64, collRWSetBytes []byte) []byte {
+	return append(encodeHeight(endorsementBlkHt), collRWSetBytes...)
+}
+
+func decodePvtDataValue(value []byte) (uint64, []byte) {
+	return binary.BigEndian.Uint64(value[:8]), value[8:]
+}
@@ -0,0 +1,135 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transientstore
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/spf13/viper"
+)
+
+func TestMain(m *testing.M) {
+	viper.Set("peer.fileSystemPath", "/tmp/fabric/core/transientstore")
+	os.Exit(m.Run())
+}
+
+type testEnv struct {
+	t             testing.TB
+	storeProvider StoreProvider
+}
+
+func newTestEnv(t testing.TB) *testEnv {
+	env := &testEnv{t: t}
+	env.removeStorePath()
+	env.storeProvider = NewStoreProvider()
+	return env
+}
+
+func (env *testEnv) cleanup() {
+	env.storeProvider.Close()
+	env.removeStorePath()
+}
+
+func (env *testEnv) removeStorePath() {
+	if err := os.RemoveAll(ledgerconfig.GetTransientStorePath()); err != nil {
+		env.t.Fatalf("Err: %s", err)
+	}
+}
+
+func samplePvtRWSet(collNames map[string][]string) *rwset.TxPvtReadWriteSet {
+	txPvtRWSet := &rwset.TxPvtReadWriteSet{DataModel: rwset.TxReadWriteSet_KV}
+	for _, ns := range []string{"ns1", "ns2"} {
+		colls, ok := collNames[ns]
+		if !ok {
+			continue
+		}
+		nsPvtRWSet := &rwset.NsPvtReadWriteSet{Namespace: ns}
+		for _, coll := range colls {
+			nsPvtRWSet.CollectionPvtRwset = append(nsPvtRWSet.CollectionPvtRwset,
+				&rwset.CollectionPvtReadWriteSet{CollectionName: coll, Rwset: []byte(ns + "-" + coll)})
+		}
+		txPvtRWSet.NsPvtRwset = append(txPvtRWSet.NsPvtRwset, nsPvtRWSet)
+	}
+	return txPvtRWSet
+}
+
+func TestStorePersistAndRetrieve(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	store, err := env.storeProvider.OpenStore("testledger")
+	testutil.AssertNoError(t, err, "")
+
+	pvtRWSet1 := samplePvtRWSet(map[string][]string{"ns1": {"coll1", "coll2"}, "ns2": {"coll1"}})
+	testutil.AssertNoError(t, store.Persist("txid1", 10, pvtRWSet1), "")
+	pvtRWSet2 := samplePvtRWSet(map[string][]string{"ns2": {"coll2"}})
+	testutil.AssertNoError(t, store.Persist("txid10", 10, pvtRWSet2), "")
+
+	retrievedPvtRWSet, err := store.GetTxPvtRWSetByTxid("txid1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, retrievedPvtRWSet, pvtRWSet1)
+
+	retrievedPvtRWSet, err = store.GetTxPvtRWSetByTxid("txid10")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, retrievedPvtRWSet, pvtRWSet2)
+
+	retrievedPvtRWSet, err = store.GetTxPvtRWSetByTxid("txid2")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, retrievedPvtRWSet)
+
+	// collections received separately for the same transaction are merged on retrieval
+	testutil.AssertNoError(t, store.Persist("txid2", 11, samplePvtRWSet(map[string][]string{"ns1": {"coll1"}})), "")
+	testutil.AssertNoError(t, store.Persist("txid2", 12, samplePvtRWSet(map[string][]string{"ns1": {"coll2"}})), "")
+	retrievedPvtRWSet, err = store.GetTxPvtRWSetByTxid("txid2")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, retrievedPvtRWSet, samplePvtRWSet(map[string][]string{"ns1": {"coll1", "coll2"}}))
+}
+
+func TestStorePurge(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	store, err := env.storeProvider.OpenStore("testledger")
+	testutil.AssertNoError(t, err, "")
+
+	pvtRWSet := samplePvtRWSet(map[string][]string{"ns1": {"coll1"}, "ns2": {"coll1"}})
+	testutil.AssertNoError(t, store.Persist("txid1", 5, pvtRWSet), "")
+	testutil.AssertNoError(t, store.Persist("txid2", 6, pvtRWSet), "")
+	testutil.AssertNoError(t, store.Persist("txid3", 7, pvtRWSet), "")
+	// persisting again at a higher height moves the entry out of reach of purge by a lower height
+	testutil.AssertNoError(t, store.Persist("txid4", 5, pvtRWSet), "")
+	testutil.AssertNoError(t, store.Persist("txid4", 8, pvtRWSet), "")
+
+	testutil.AssertNoError(t, store.PurgeByTxids([]string{"txid2"}), "")
+	assertPresence(t, store, map[string]bool{"txid1": true, "txid2": false, "txid3": true, "txid4": true})
+
+	testutil.AssertNoError(t, store.PurgeByHeight(7), "")
+	assertPresence(t, store, map[string]bool{"txid1": false, "txid2": false, "txid3": true, "txid4": true})
+
+	testutil.AssertNoError(t, store.PurgeByHeight(9), "")
+	assertPresence(t, store, map[string]bool{"txid1": false, "txid2": false, "txid3": false, "txid4": false})
+}
+
+func assertPresence(t *testing.T, store Store, expected map[string]bool) {
+	for txid, present := range expected {
+		pvtRWSet, err := store.GetTxPvtRWSetByTxid(txid)
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, pvtRWSet != nil, present)
+	}
+}
@@ -19,12 +19,14 @@ package producer
 import (
 	"fmt"
 
+	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 )
 
-// SendProducerBlockEvent sends block event to clients
+// SendProducerBlockEvent sends block event to clients, followed by the
+// chaincode events of the valid transactions in the block, in block order
 func SendProducerBlockEvent(block *common.Block) error {
 	logger.Debugf("Entry")
 	defer logger.Debugf("Exit")
@@ -33,7 +35,9 @@ func SendProducerBlockEvent(block *common.Block) error {
 	bevent.Metadata = block.Metadata
 	bevent.Data = &common.BlockData{}
 	var channelId string
-	for _, d := range block.Data.Data {
+	var ccevents []*pb.ChaincodeEvent
+	txsFltr := getTxValidationFlags(block)
+	for i, d := range block.Data.Data {
 		ebytes := d
 		if ebytes != nil {
 			if env, err := utils.GetEnvelopeFromBlock(ebytes); err != nil {
@@ -70,6 +74,13 @@ func SendProducerBlockEvent(block *common.Block) error {
 					if err != nil {
 						return fmt.Errorf("error unmarshalling chaincode action for block event: %s", err)
 					}
+					if isValidTx(txsFltr, i) {
+						events, err := utils.GetChaincodeEventList(caPayload.Events)
+						if err != nil {
+							logger.Errorf("Channel [%s]: could not extract chaincode events of transaction %s: %s", channelId, chdr.TxId, err)
+						}
+						ccevents = append(ccevents, events...)
+					}
 					// Drop read write set from transaction before sending block event
 					// Performance issue with chaincode deploy txs and causes nodejs grpc
 					// to hit max message size bug
@@ -104,7 +115,35 @@ func SendProducerBlockEvent(block *common.Block) error {
 
 	logger.Infof("Channel [%s]: Sending event for block number [%d]", channelId, block.Header.Number)
 
-	return Send(CreateBlockEvent(bevent))
+	if err := Send(CreateBlockEvent(bevent)); err != nil {
+		return err
+	}
+
+	for _, ccevent := range ccevents {
+		if err := Send(CreateChaincodeEvent(ccevent)); err != nil {
+			return fmt.Errorf("could not send chaincode event %s of transaction %s: %s", ccevent.EventName, ccevent.TxId, err)
+		}
+	}
+
+	return nil
+}
+
+//getTxValidationFlags returns the transaction validation flags set by the
+//committer, or nil if the block carries none
+func getTxValidationFlags(block *common.Block) ledgerUtil.TxValidationFlags {
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		return nil
+	}
+	return ledgerUtil.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+}
+
+//isValidTx tells whether the transaction at txIndex was marked valid. A
+//transaction without a flag has not been invalidated by the committer
+func isValidTx(txsFltr ledgerUtil.TxValidationFlags, txIndex int) bool {
+	if txIndex >= len(txsFltr) {
+		return true
+	}
+	return txsFltr.IsValid(txIndex)
 }
 
 //CreateBlockEvent creates a Event from a Block
@@ -18,6 +18,8 @@ package producer
 
 import (
 	"fmt"
+	"path"
+	"regexp"
 	"sync"
 	"time"
 
@@ -47,6 +49,50 @@ type genericHandlerList struct {
 type chaincodeHandlerList struct {
 	sync.RWMutex
 	handlers map[string]map[string]map[*handler]bool
+
+	//patternHandlers holds, per chaincode ID, the handlers registered with a
+	//GLOB or REGEX event name pattern, keyed by getPatternKey
+	patternHandlers map[string]map[string]*patternHandlerMap
+}
+
+//patternHandlerMap is the set of handlers registered with the same event name
+//pattern along with the compiled matcher for that pattern
+type patternHandlerMap struct {
+	match    func(eventName string) bool
+	handlers map[*handler]bool
+}
+
+func getPatternKey(ccReg *pb.ChaincodeReg) string {
+	return ccReg.MatchType.String() + "/" + ccReg.EventName
+}
+
+//newEventNameMatcher compiles the event name pattern of a GLOB or REGEX
+//registration. GLOB patterns follow path.Match, REGEX patterns must match the
+//whole event name
+func newEventNameMatcher(ccReg *pb.ChaincodeReg) (func(eventName string) bool, error) {
+	if ccReg.EventName == "" {
+		return nil, fmt.Errorf("event name pattern not provided for match type %s", ccReg.MatchType)
+	}
+
+	switch ccReg.MatchType {
+	case pb.ChaincodeReg_GLOB:
+		pattern := ccReg.EventName
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %s: %s", pattern, err)
+		}
+		return func(eventName string) bool {
+			matched, _ := path.Match(pattern, eventName)
+			return matched
+		}, nil
+	case pb.ChaincodeReg_REGEX:
+		re, err := regexp.Compile("^(?:" + ccReg.EventName + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %s: %s", ccReg.EventName, err)
+		}
+		return re.MatchString, nil
+	default:
+		return nil, fmt.Errorf("unknown match type %s", ccReg.MatchType)
+	}
 }
 
 func (hl *chaincodeHandlerList) add(ie *pb.Interest, h *handler) (bool, error) {
@@ -61,6 +107,9 @@ func (hl *chaincodeHandlerList) add(ie *pb.Interest, h *handler) (bool, error) {
 	if ie.GetChaincodeRegInfo().ChaincodeId == "" {
 		return false, fmt.Errorf("chaincode ID not provided for registering")
 	}
+	if ie.GetChaincodeRegInfo().MatchType != pb.ChaincodeReg_EXACT {
+		return hl.addPattern(ie.GetChaincodeRegInfo(), h)
+	}
 	//is there a event type map for the chaincode
 	emap, ok := hl.handlers[ie.GetChaincodeRegInfo().ChaincodeId]
 	if !ok {
@@ -82,6 +131,35 @@ func (hl *chaincodeHandlerList) add(ie *pb.Interest, h *handler) (bool, error) {
 
 	return true, nil
 }
+
+func (hl *chaincodeHandlerList) addPattern(ccReg *pb.ChaincodeReg, h *handler) (bool, error) {
+	pmap, ok := hl.patternHandlers[ccReg.ChaincodeId]
+	if !ok {
+		pmap = make(map[string]*patternHandlerMap)
+		hl.patternHandlers[ccReg.ChaincodeId] = pmap
+	}
+
+	key := getPatternKey(ccReg)
+	phm, ok := pmap[key]
+	if !ok {
+		match, err := newEventNameMatcher(ccReg)
+		if err != nil {
+			if len(pmap) == 0 {
+				delete(hl.patternHandlers, ccReg.ChaincodeId)
+			}
+			return false, err
+		}
+		phm = &patternHandlerMap{match: match, handlers: make(map[*handler]bool)}
+		pmap[key] = phm
+	} else if _, ok = phm.handlers[h]; ok {
+		return false, fmt.Errorf("handler exists for event name pattern")
+	}
+
+	phm.handlers[h] = true
+
+	return true, nil
+}
+
 func (hl *chaincodeHandlerList) del(ie *pb.Interest, h *handler) (bool, error) {
 	hl.Lock()
 	defer hl.Unlock()
@@ -95,6 +173,9 @@ func (hl *chaincodeHandlerList) del(ie *pb.Interest, h *handler) (bool, error) {
 	if ie.GetChaincodeRegInfo().ChaincodeId == "" {
 		return false, fmt.Errorf("chaincode ID not provided for de-registering")
 	}
+	if ie.GetChaincodeRegInfo().MatchType != pb.ChaincodeReg_EXACT {
+		return hl.delPattern(ie.GetChaincodeRegInfo(), h)
+	}
 
 	//if there's no event type map, nothing to do
 	emap, ok := hl.handlers[ie.GetChaincodeRegInfo().ChaincodeId]
@@ -127,6 +208,31 @@ func (hl *chaincodeHandlerList) del(ie *pb.Interest, h *handler) (bool, error) {
 	return true, nil
 }
 
+func (hl *chaincodeHandlerList) delPattern(ccReg *pb.ChaincodeReg, h *handler) (bool, error) {
+	pmap, ok := hl.patternHandlers[ccReg.ChaincodeId]
+	if !ok {
+		return false, fmt.Errorf("chaincode ID not registered")
+	}
+
+	key := getPatternKey(ccReg)
+	phm, ok := pmap[key]
+	if !ok {
+		return false, fmt.Errorf("event name pattern %s not registered for chaincode ID %s", key, ccReg.ChaincodeId)
+	} else if _, ok = phm.handlers[h]; !ok {
+		return false, fmt.Errorf("handler not registered for event name pattern %s for chaincode ID %s", key, ccReg.ChaincodeId)
+	}
+	delete(phm.handlers, h)
+
+	if len(phm.handlers) == 0 {
+		delete(pmap, key)
+		if len(pmap) == 0 {
+			delete(hl.patternHandlers, ccReg.ChaincodeId)
+		}
+	}
+
+	return true, nil
+}
+
 func (hl *chaincodeHandlerList) foreach(e *pb.Event, action func(h *handler)) {
 	hl.Lock()
 	defer hl.Unlock()
@@ -136,24 +242,40 @@ func (hl *chaincodeHandlerList) foreach(e *pb.Event, action func(h *handler)) {
 		return
 	}
 
+	//a handler whose interests overlap gets the event only once
+	sent := make(map[*handler]bool)
+	send := func(handlerMap map[*handler]bool) {
+		for h := range handlerMap {
+			if !sent[h] {
+				sent[h] = true
+				action(h)
+			}
+		}
+	}
+
+	eventName := e.GetChaincodeEvent().EventName
+
 	//get the event map for the chaincode
 	if emap := hl.handlers[e.GetChaincodeEvent().ChaincodeId]; emap != nil {
 		//get the handler map for the event
-		if handlerMap := emap[e.GetChaincodeEvent().EventName]; handlerMap != nil {
-			for h := range handlerMap {
-				action(h)
-			}
+		if handlerMap := emap[eventName]; handlerMap != nil {
+			send(handlerMap)
 		}
 		//send to handlers who want all events from the chaincode, but only if
 		//EventName is not already "" (chaincode should NOT send nameless events though)
-		if e.GetChaincodeEvent().EventName != "" {
+		if eventName != "" {
 			if handlerMap := emap[""]; handlerMap != nil {
-				for h := range handlerMap {
-					action(h)
-				}
+				send(handlerMap)
 			}
 		}
 	}
+
+	//send to handlers whose event name pattern matches
+	for _, phm := range hl.patternHandlers[e.GetChaincodeEvent().ChaincodeId] {
+		if phm.match(eventName) {
+			send(phm.handlers)
+		}
+	}
 }
 
 func (hl *genericHandlerList) add(ie *pb.Interest, h *handler) (bool, error) {
@@ -262,7 +384,7 @@ func AddEventType(eventType pb.EventType) error {
 	case pb.EventType_BLOCK:
 		gEventProcessor.eventConsumers[eventType] = &genericHandlerList{handlers: make(map[*handler]bool)}
 	case pb.EventType_CHAINCODE:
-		gEventProcessor.eventConsumers[eventType] = &chaincodeHandlerList{handlers: make(map[string]map[string]map[*handler]bool)}
+		gEventProcessor.eventConsumers[eventType] = &chaincodeHandlerList{handlers: make(map[string]map[string]map[*handler]bool), patternHandlers: make(map[string]map[string]*patternHandlerMap)}
 	case pb.EventType_REJECTION:
 		gEventProcessor.eventConsumers[eventType] = &genericHandlerList{handlers: make(map[*handler]bool)}
 	}
@@ -55,6 +55,9 @@ func getInterestKey(interest pb.Interest) string {
 		key = "/" + strconv.Itoa(int(pb.EventType_REJECTION))
 	case pb.EventType_CHAINCODE:
 		key = "/" + strconv.Itoa(int(pb.EventType_CHAINCODE)) + "/" + interest.GetChaincodeRegInfo().ChaincodeId + "/" + interest.GetChaincodeRegInfo().EventName
+		if interest.GetChaincodeRegInfo().MatchType != pb.ChaincodeReg_EXACT {
+			key += "/" + interest.GetChaincodeRegInfo().MatchType.String()
+		}
 	default:
 		logger.Errorf("unknown interest type %s", interest.EventType)
 	}
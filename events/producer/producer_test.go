@@ -117,6 +117,108 @@ func TestSignedEvent(t *testing.T) {
 	}
 }
 
+func ccInterest(ccID, eventName string, matchType peer.ChaincodeReg_MatchType) *peer.Interest {
+	return &peer.Interest{
+		EventType: peer.EventType_CHAINCODE,
+		RegInfo:   &peer.Interest_ChaincodeRegInfo{ChaincodeRegInfo: &peer.ChaincodeReg{ChaincodeId: ccID, EventName: eventName, MatchType: matchType}},
+	}
+}
+
+func ccEvent(ccID, eventName string) *peer.Event {
+	return CreateChaincodeEvent(&peer.ChaincodeEvent{ChaincodeId: ccID, EventName: eventName})
+}
+
+func TestChaincodeEventPatterns(t *testing.T) {
+	hl := &chaincodeHandlerList{handlers: make(map[string]map[string]map[*handler]bool), patternHandlers: make(map[string]map[string]*patternHandlerMap)}
+
+	exact, glob, regex, all := &handler{}, &handler{}, &handler{}, &handler{}
+	interests := map[*handler]*peer.Interest{
+		exact: ccInterest("mycc", "order.created", peer.ChaincodeReg_EXACT),
+		glob:  ccInterest("mycc", "order.*", peer.ChaincodeReg_GLOB),
+		regex: ccInterest("mycc", "(order|invoice)\\.paid", peer.ChaincodeReg_REGEX),
+		all:   ccInterest("mycc", "", peer.ChaincodeReg_EXACT),
+	}
+	for h, ie := range interests {
+		if _, err := hl.add(ie, h); err != nil {
+			t.Fatalf("add failed, err %s", err)
+		}
+	}
+	// the same pattern twice for the same handler is rejected
+	if _, err := hl.add(interests[glob], glob); err == nil {
+		t.Fatal("add should have failed for a duplicate registration")
+	}
+	// a handler registered for both an exact name and a matching pattern gets the event once
+	if _, err := hl.add(ccInterest("mycc", "order.*", peer.ChaincodeReg_GLOB), exact); err != nil {
+		t.Fatalf("add failed, err %s", err)
+	}
+
+	received := func(e *peer.Event) map[*handler]int {
+		got := make(map[*handler]int)
+		hl.foreach(e, func(h *handler) { got[h]++ })
+		return got
+	}
+
+	got := received(ccEvent("mycc", "order.created"))
+	if len(got) != 3 || got[exact] != 1 || got[glob] != 1 || got[all] != 1 {
+		t.Fatalf("unexpected handlers for order.created: %v", got)
+	}
+	got = received(ccEvent("mycc", "invoice.paid"))
+	if len(got) != 2 || got[regex] != 1 || got[all] != 1 {
+		t.Fatalf("unexpected handlers for invoice.paid: %v", got)
+	}
+	// regular expressions must match the whole event name
+	got = received(ccEvent("mycc", "invoice.paid.late"))
+	if len(got) != 1 || got[all] != 1 {
+		t.Fatalf("unexpected handlers for invoice.paid.late: %v", got)
+	}
+	got = received(ccEvent("othercc", "order.created"))
+	if len(got) != 0 {
+		t.Fatalf("unexpected handlers for othercc: %v", got)
+	}
+
+	// invalid or empty patterns are rejected
+	if _, err := hl.add(ccInterest("mycc", "order.[", peer.ChaincodeReg_GLOB), all); err == nil {
+		t.Fatal("add should have failed for an invalid glob pattern")
+	}
+	if _, err := hl.add(ccInterest("mycc", "order.(", peer.ChaincodeReg_REGEX), all); err == nil {
+		t.Fatal("add should have failed for an invalid regular expression")
+	}
+	if _, err := hl.add(ccInterest("badcc", "", peer.ChaincodeReg_GLOB), all); err == nil {
+		t.Fatal("add should have failed for an empty pattern")
+	}
+	if _, ok := hl.patternHandlers["badcc"]; ok {
+		t.Fatal("a failed registration should not leave a pattern map behind")
+	}
+
+	// deregistering removes the pattern handlers
+	if _, err := hl.del(interests[regex], glob); err == nil {
+		t.Fatal("del should have failed for a handler that is not registered")
+	}
+	for _, h := range []*handler{glob, exact} {
+		if _, err := hl.del(ccInterest("mycc", "order.*", peer.ChaincodeReg_GLOB), h); err != nil {
+			t.Fatalf("del failed, err %s", err)
+		}
+	}
+	if _, err := hl.del(interests[regex], regex); err != nil {
+		t.Fatalf("del failed, err %s", err)
+	}
+	if len(hl.patternHandlers) != 0 {
+		t.Fatal("all pattern handlers should have been removed")
+	}
+	got = received(ccEvent("mycc", "order.created"))
+	if len(got) != 2 || got[exact] != 1 || got[all] != 1 {
+		t.Fatalf("unexpected handlers for order.created: %v", got)
+	}
+}
+
+func TestInterestKey(t *testing.T) {
+	exactKey := getInterestKey(*ccInterest("mycc", "order.*", peer.ChaincodeReg_EXACT))
+	globKey := getInterestKey(*ccInterest("mycc", "order.*", peer.ChaincodeReg_GLOB))
+	if exactKey == globKey {
+		t.Fatalf("exact and glob interests should have different keys, got %s", exactKey)
+	}
+}
+
 var signer msp.SigningIdentity
 var signerSerialized []byte
 
@@ -36,7 +36,13 @@ under the License.
 //   peer chaincode query -C myc1 -n marbles -c '{"Args":["queryMarblesByOwner","tom"]}'
 //   peer chaincode query -C myc1 -n marbles -c '{"Args":["queryMarbles","{\"selector\":{\"owner\":\"tom\"}}"]}'
 
-//The following examples demonstrate creating indexes on CouchDB
+//Indexes to support the rich queries below are packaged with this chaincode under
+//META-INF/statedb/couchdb/indexes. They are created on the channel state database when the
+//chaincode is instantiated or upgraded (only if CouchDB is used as state database).
+//Fields in the packaged index definitions are the fields of the marble JSON and are
+//automatically prefixed with the "data" wrapper, chaincodeid is left as is.
+//
+//The following examples demonstrate creating the same indexes manually on CouchDB
 //Example hostname:port configurations
 //
 //Docker or vagrant environments:
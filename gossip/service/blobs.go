@@ -0,0 +1,278 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/core/ledger/blobstore"
+	"github.com/hyperledger/fabric/gossip/comm"
+	gossipCommon "github.com/hyperledger/fabric/gossip/common"
+	"github.com/hyperledger/fabric/gossip/util"
+	proto "github.com/hyperledger/fabric/protos/gossip"
+)
+
+// blobChunkSize is the largest part of a blob sent in a single message,
+// which keeps the messages well below the maximum size of a gRPC message
+const blobChunkSize = 1024 * 1024
+
+// blobResponseTimeout is the time to wait for a peer to answer a BlobRequest
+// before the blob is requested from the next peer
+var blobResponseTimeout = 3 * time.Second
+
+// blobHandler sends the blobs streamed by the chaincodes on this peer to the other peers
+// of a channel, and retrieves from them the blobs referenced by a block before it is committed.
+// The blobs are sent apart from the transactions, which only hold their hash
+type blobHandler struct {
+	chainID string
+	gossip  privateDataGossip
+	store   blobstore.Store
+	lock    sync.Mutex
+	// pending holds the channels awaiting the response to a BlobRequest, by nonce of the request
+	pending map[uint64]chan *proto.BlobChunk
+	stopCh  chan struct{}
+}
+
+func newBlobHandler(chainID string, g privateDataGossip, store blobstore.Store) *blobHandler {
+	h := &blobHandler{
+		chainID: chainID,
+		gossip:  g,
+		store:   store,
+		pending: make(map[uint64]chan *proto.BlobChunk),
+		stopCh:  make(chan struct{}),
+	}
+	_, commChan := g.Accept(func(message interface{}) bool {
+		msg := message.(proto.ReceivedMessage).GetGossipMessage()
+		return msg.IsBlobMsg() && bytes.Equal(msg.Channel, []byte(chainID))
+	}, true)
+	go h.receive(commChan)
+	return h
+}
+
+// distribute pushes in the background the staged blobs with the given hashes to the other peers of the
+// channel. A peer which does not receive a blob entirely retrieves the rest of it before committing the
+// block referencing it
+func (h *blobHandler) distribute(hashes [][]byte) error {
+	for _, hash := range hashes {
+		if !h.store.Has(hash) {
+			return fmt.Errorf("Blob %x is not available on this peer", hash)
+		}
+	}
+	peers := h.channelPeers()
+	if len(hashes) == 0 || len(peers) == 0 {
+		return nil
+	}
+	go func() {
+		for _, hash := range hashes {
+			if err := h.push(hash, peers); err != nil {
+				logger.Warningf("Failed sending blob %x on channel %s: %s", hash, h.chainID, err)
+			}
+		}
+	}()
+	return nil
+}
+
+func (h *blobHandler) push(hash []byte, peers []*comm.RemotePeer) error {
+	logger.Debugf("Sending blob %x to %d peers of channel %s", hash, len(peers), h.chainID)
+	for offset := uint64(0); ; {
+		data, size, err := h.store.ReadChunk(hash, offset, blobChunkSize)
+		if err != nil {
+			return err
+		}
+		h.gossip.Send(h.chunkMessage(util.RandomUInt64(), hash, offset, size, data), peers...)
+		offset += uint64(len(data))
+		if offset >= size {
+			return nil
+		}
+	}
+}
+
+// fetch retrieves from the other peers of the channel the blobs with the given hashes
+// which are not available on this peer
+func (h *blobHandler) fetch(hashes [][]byte) error {
+	var missing [][]byte
+	for _, hash := range hashes {
+		if h.store.Has(hash) {
+			continue
+		}
+		if err := h.pull(hash); err != nil {
+			logger.Warningf("Failed retrieving blob %x on channel %s: %s", hash, h.chainID, err)
+			missing = append(missing, hash)
+		}
+	}
+	if len(missing) != 0 {
+		return fmt.Errorf("Blobs %x could not be retrieved from the peers of channel %s", missing, h.chainID)
+	}
+	return nil
+}
+
+// pull requests the blob from the peers of the channel in turn, chunk after chunk, until its content is
+// complete. The content already received from a peer is kept when the next peer is asked for the rest
+func (h *blobHandler) pull(hash []byte) error {
+	var offset uint64
+	for _, peer := range h.channelPeers() {
+		for {
+			chunk, err := h.request(hash, offset, peer)
+			if err != nil {
+				logger.Debugf("Peer %s did not send blob %x: %s", peer.Endpoint, hash, err)
+				break
+			}
+			received, err := h.store.StageChunk(hash, chunk.Offset, chunk.Size, chunk.Data)
+			if err != nil {
+				logger.Warningf("Discarding blob %x received from peer %s: %s", hash, peer.Endpoint, err)
+				offset = 0
+				break
+			}
+			if received == chunk.Size {
+				return nil
+			}
+			if received == offset {
+				// the peer sent no part of the blob following the content received so far
+				break
+			}
+			offset = received
+		}
+	}
+	return fmt.Errorf("no peer sent the blob")
+}
+
+// request asks a peer for the part of a blob starting at offset
+func (h *blobHandler) request(hash []byte, offset uint64, peer *comm.RemotePeer) (*proto.BlobChunk, error) {
+	nonce := util.RandomUInt64()
+	responseCh := make(chan *proto.BlobChunk, 1)
+	h.lock.Lock()
+	h.pending[nonce] = responseCh
+	h.lock.Unlock()
+	defer func() {
+		h.lock.Lock()
+		delete(h.pending, nonce)
+		h.lock.Unlock()
+	}()
+
+	h.gossip.Send(&proto.GossipMessage{
+		Nonce:   nonce,
+		Tag:     proto.GossipMessage_CHAN_ONLY,
+		Channel: []byte(h.chainID),
+		Content: &proto.GossipMessage_BlobRequest{
+			BlobRequest: &proto.BlobRequest{Hash: hash, Offset: offset},
+		},
+	}, peer)
+
+	select {
+	case chunk := <-responseCh:
+		if !bytes.Equal(chunk.Hash, hash) {
+			return nil, fmt.Errorf("received a chunk of blob %x instead", chunk.Hash)
+		}
+		return chunk, nil
+	case <-time.After(blobResponseTimeout):
+		return nil, fmt.Errorf("timed out waiting for the response")
+	case <-h.stopCh:
+		return nil, fmt.Errorf("blob handler of channel %s is stopped", h.chainID)
+	}
+}
+
+// receive handles the blob messages sent by the peers of the channel. The chunks pushed by
+// other peers are staged in the order they are received, since a chunk is only staged if it
+// follows the content of the blob received so far
+func (h *blobHandler) receive(commChan <-chan proto.ReceivedMessage) {
+	for {
+		select {
+		case msg := <-commChan:
+			if msg == nil {
+				return
+			}
+			if err := h.handleBlobMsg(msg); err != nil {
+				logger.Warning("Discarding blob message on channel", h.chainID, ":", err)
+			}
+		case <-h.stopCh:
+			return
+		}
+	}
+}
+
+func (h *blobHandler) handleBlobMsg(msg proto.ReceivedMessage) error {
+	connInfo := msg.GetConnectionInfo()
+	if connInfo == nil || !h.isChannelPeer(connInfo.ID) {
+		return fmt.Errorf("Sender is not a peer of the channel")
+	}
+	gossipMsg := msg.GetGossipMessage()
+
+	if request := gossipMsg.GetBlobRequest(); request != nil {
+		data, size, err := h.store.ReadChunk(request.Hash, request.Offset, blobChunkSize)
+		if err != nil {
+			// the requesting peer asks the next peer once its request times out
+			return err
+		}
+		msg.Respond(h.chunkMessage(gossipMsg.Nonce, request.Hash, request.Offset, size, data))
+		return nil
+	}
+
+	chunk := gossipMsg.GetBlobChunk()
+	h.lock.Lock()
+	responseCh, isResponse := h.pending[gossipMsg.Nonce]
+	h.lock.Unlock()
+	if isResponse {
+		select {
+		case responseCh <- chunk:
+		default:
+		}
+		return nil
+	}
+	_, err := h.store.StageChunk(chunk.Hash, chunk.Offset, chunk.Size, chunk.Data)
+	return err
+}
+
+func (h *blobHandler) chunkMessage(nonce uint64, hash []byte, offset uint64, size uint64, data []byte) *proto.GossipMessage {
+	return &proto.GossipMessage{
+		Nonce:   nonce,
+		Tag:     proto.GossipMessage_CHAN_ONLY,
+		Channel: []byte(h.chainID),
+		Content: &proto.GossipMessage_BlobChunk{
+			BlobChunk: &proto.BlobChunk{Hash: hash, Offset: offset, Size: size, Data: data},
+		},
+	}
+}
+
+// channelPeers returns the alive peers of the channel, in a random order so that
+// the load of serving the blobs is spread among them
+func (h *blobHandler) channelPeers() []*comm.RemotePeer {
+	var peers []*comm.RemotePeer
+	for _, member := range h.gossip.PeersOfChannel(gossipCommon.ChainID(h.chainID)) {
+		peers = append(peers, &comm.RemotePeer{Endpoint: member.PreferredEndpoint(), PKIID: member.PKIid})
+	}
+	for i := len(peers) - 1; i > 0; i-- {
+		j := util.RandomInt(i + 1)
+		peers[i], peers[j] = peers[j], peers[i]
+	}
+	return peers
+}
+
+func (h *blobHandler) isChannelPeer(pkiID gossipCommon.PKIidType) bool {
+	for _, member := range h.gossip.PeersOfChannel(gossipCommon.ChainID(h.chainID)) {
+		if bytes.Equal(member.PKIid, pkiID) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *blobHandler) stop() {
+	close(h.stopCh)
+}
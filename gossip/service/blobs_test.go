@@ -0,0 +1,214 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/ledger/blobstore"
+	"github.com/hyperledger/fabric/gossip/api"
+	"github.com/hyperledger/fabric/gossip/comm"
+	gossipCommon "github.com/hyperledger/fabric/gossip/common"
+	"github.com/hyperledger/fabric/gossip/discovery"
+	proto "github.com/hyperledger/fabric/protos/gossip"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+const testBlobStorePath = "/tmp/fabric/gossip/service/blobs"
+
+func newTestBlobStoreProvider() blobstore.StoreProvider {
+	viper.Set("peer.fileSystemPath", testBlobStorePath)
+	os.RemoveAll(testBlobStorePath)
+	return blobstore.NewStoreProvider()
+}
+
+func newTestBlobHandler(t *testing.T, provider blobstore.StoreProvider, ledgerID string) (*blobHandler, *mockPrivateDataGossip) {
+	g := &mockPrivateDataGossip{sent: make(map[string][]*proto.GossipMessage), commChan: make(chan proto.ReceivedMessage)}
+	for i := 0; i < 3; i++ {
+		g.members = append(g.members, discovery.NetworkMember{Endpoint: fmt.Sprintf("p%d", i), PKIid: gossipCommon.PKIidType(fmt.Sprintf("p%d", i))})
+	}
+	store, err := provider.OpenStore(ledgerID)
+	assert.NoError(t, err)
+	return newBlobHandler(ledgerID, g, store), g
+}
+
+// sampleBlob returns the content of a blob sent in two chunks and its hash
+func sampleBlob() ([]byte, []byte) {
+	content := bytes.Repeat([]byte("a"), blobChunkSize+10)
+	hash := sha256.Sum256(content)
+	return content, hash[:]
+}
+
+func stageSampleBlob(t *testing.T, store blobstore.Store) []byte {
+	content, hash := sampleBlob()
+	received, err := store.StageChunk(hash, 0, uint64(len(content)), content)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(len(content)), received)
+	return hash
+}
+
+func blobRequestMsg(nonce uint64, hash []byte, offset uint64) *proto.GossipMessage {
+	return &proto.GossipMessage{
+		Nonce:   nonce,
+		Tag:     proto.GossipMessage_CHAN_ONLY,
+		Channel: []byte("testchain"),
+		Content: &proto.GossipMessage_BlobRequest{BlobRequest: &proto.BlobRequest{Hash: hash, Offset: offset}},
+	}
+}
+
+func TestDistributeBlobs(t *testing.T) {
+	defer os.RemoveAll(testBlobStorePath)
+	provider := newTestBlobStoreProvider()
+	defer provider.Close()
+	h, g := newTestBlobHandler(t, provider, "testchain")
+	defer h.stop()
+
+	hash := stageSampleBlob(t, h.store)
+	assert.NoError(t, h.distribute([][]byte{hash}))
+
+	// each peer of the channel receives the blob in two chunks
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		g.Lock()
+		done := len(g.sent["p2"]) == 2
+		g.Unlock()
+		if done {
+			break
+		}
+	}
+	g.Lock()
+	defer g.Unlock()
+	assert.Len(t, g.sent, 3)
+	for _, msgs := range g.sent {
+		assert.Len(t, msgs, 2)
+		var offset uint64
+		for _, msg := range msgs {
+			chunk := msg.GetBlobChunk()
+			assert.Equal(t, proto.GossipMessage_CHAN_ONLY, msg.Tag)
+			assert.Equal(t, hash, chunk.Hash)
+			assert.Equal(t, offset, chunk.Offset)
+			assert.Equal(t, uint64(blobChunkSize+10), chunk.Size)
+			offset += uint64(len(chunk.Data))
+		}
+		assert.Equal(t, uint64(blobChunkSize+10), offset)
+	}
+
+	// a blob which is not available on this peer cannot be distributed
+	assert.Error(t, h.distribute([][]byte{[]byte("missing")}))
+}
+
+func TestReceiveBlobMessages(t *testing.T) {
+	defer os.RemoveAll(testBlobStorePath)
+	provider := newTestBlobStoreProvider()
+	defer provider.Close()
+	h, g := newTestBlobHandler(t, provider, "testchain")
+	defer h.stop()
+
+	hash := stageSampleBlob(t, h.store)
+	responses := make(chan *proto.GossipMessage, 2)
+	request := func(sender string, offset uint64) {
+		g.commChan <- &mockReceivedMessage{
+			msg:     blobRequestMsg(42, hash, offset).NoopSign(),
+			sender:  api.PeerIdentityType(sender),
+			respond: func(msg *proto.GossipMessage) { responses <- msg },
+		}
+	}
+
+	// a peer of the channel is sent the part of the blob it requests
+	request("p1", blobChunkSize)
+	select {
+	case msg := <-responses:
+		assert.Equal(t, uint64(42), msg.Nonce)
+		assert.Equal(t, uint64(blobChunkSize), msg.GetBlobChunk().Offset)
+		assert.Len(t, msg.GetBlobChunk().Data, 10)
+	case <-time.After(time.Second):
+		assert.Fail(t, "no response to the blob request")
+	}
+
+	// a peer which is not a member of the channel is not answered, nor are its chunks staged
+	request("p5", 0)
+	content := []byte("content")
+	contentHash := sha256.Sum256(content)
+	g.commChan <- &mockReceivedMessage{
+		msg:    h.chunkMessage(1, contentHash[:], 0, uint64(len(content)), content).NoopSign(),
+		sender: api.PeerIdentityType("p5"),
+	}
+	// the handler processes messages in order, so once this chunk is staged the previous messages have been handled
+	otherContent := []byte("other content")
+	otherHash := sha256.Sum256(otherContent)
+	g.commChan <- &mockReceivedMessage{
+		msg:    h.chunkMessage(2, otherHash[:], 0, uint64(len(otherContent)), otherContent).NoopSign(),
+		sender: api.PeerIdentityType("p0"),
+	}
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if h.store.Has(otherHash[:]) {
+			break
+		}
+	}
+	assert.True(t, h.store.Has(otherHash[:]))
+	assert.False(t, h.store.Has(contentHash[:]))
+	assert.Len(t, responses, 0)
+}
+
+func TestFetchBlobs(t *testing.T) {
+	defer os.RemoveAll(testBlobStorePath)
+	defer func(timeout time.Duration) { blobResponseTimeout = timeout }(blobResponseTimeout)
+	blobResponseTimeout = 100 * time.Millisecond
+
+	provider := newTestBlobStoreProvider()
+	defer provider.Close()
+	serving, _ := newTestBlobHandler(t, provider, "otherchain")
+	defer serving.stop()
+	hash := stageSampleBlob(t, serving.store)
+
+	h, g := newTestBlobHandler(t, provider, "testchain")
+	defer h.stop()
+
+	// p1 does not answer, the other peers send the part of the blob they are asked for
+	g.onSend = func(msg *proto.GossipMessage, peer *comm.RemotePeer) {
+		request := msg.GetBlobRequest()
+		if request == nil || peer.Endpoint == "p1" {
+			return
+		}
+		data, size, err := serving.store.ReadChunk(request.Hash, request.Offset, blobChunkSize)
+		if err != nil {
+			return
+		}
+		go func() {
+			g.commChan <- &mockReceivedMessage{
+				msg:    h.chunkMessage(msg.Nonce, request.Hash, request.Offset, size, data).NoopSign(),
+				sender: api.PeerIdentityType(peer.Endpoint),
+			}
+		}()
+	}
+
+	assert.NoError(t, h.fetch([][]byte{hash}))
+	assert.True(t, h.store.Has(hash))
+	content, _ := sampleBlob()
+	data, _, err := h.store.ReadChunk(hash, 0, len(content))
+	assert.NoError(t, err)
+	assert.Equal(t, content, data)
+
+	// no peer of the channel holds the blob
+	missing := sha256.Sum256([]byte("missing"))
+	assert.Error(t, h.fetch([][]byte{missing[:]}))
+}
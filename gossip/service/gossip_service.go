@@ -17,6 +17,7 @@ limitations under the License.
 package service
 
 import (
+	"fmt"
 	"sync"
 
 	peerComm "github.com/hyperledger/fabric/core/comm"
@@ -34,6 +35,7 @@ import (
 	"github.com/hyperledger/fabric/peer/gossip/sa"
 	"github.com/hyperledger/fabric/protos/common"
 	proto "github.com/hyperledger/fabric/protos/gossip"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	"github.com/spf13/viper"
 	"google.golang.org/grpc"
 )
@@ -52,7 +54,13 @@ type GossipService interface {
 	// NewConfigEventer creates a ConfigProcessor which the configtx.Manager can ultimately route config updates to
 	NewConfigEventer() ConfigProcessor
 	// InitializeChannel allocates the state provider and should be invoked once per channel per execution
-	InitializeChannel(chainID string, committer committer.Committer, endpoints []string)
+	InitializeChannel(chainID string, committer committer.Committer, endpoints []string, support Support)
+	// DistributePrivateData distributes the private data of a transaction to the peers authorized for its collections
+	DistributePrivateData(chainID string, txID string, privData *rwset.TxPvtReadWriteSet) error
+	// DistributeBlobs sends the blobs with the given hashes, staged on this peer, to the other peers of the channel
+	DistributeBlobs(chainID string, hashes [][]byte) error
+	// FetchBlobs retrieves from the other peers of the channel the blobs with the given hashes missing on this peer
+	FetchBlobs(chainID string, hashes [][]byte) error
 	// GetBlock returns block for given chain
 	GetBlock(chainID string, index uint64) *common.Block
 	// AddPayload appends message payload to for given chain
@@ -82,6 +90,8 @@ func (*deliveryFactoryImpl) Service(g GossipService, endpoints []string, mcs api
 type gossipServiceImpl struct {
 	gossipSvc
 	chains          map[string]state.GossipStateProvider
+	privateHandlers map[string]*privateDataHandler
+	blobHandlers    map[string]*blobHandler
 	leaderElection  map[string]election.LeaderElectionService
 	deliveryService deliverclient.DeliverService
 	deliveryFactory DeliveryServiceFactory
@@ -155,6 +165,8 @@ func InitGossipServiceCustomDeliveryFactory(peerIdentity []byte, endpoint string
 			mcs:             mcs,
 			gossipSvc:       gossip,
 			chains:          make(map[string]state.GossipStateProvider),
+			privateHandlers: make(map[string]*privateDataHandler),
+			blobHandlers:    make(map[string]*blobHandler),
 			leaderElection:  make(map[string]election.LeaderElectionService),
 			deliveryFactory: factory,
 			idMapper:        idMapper,
@@ -175,12 +187,23 @@ func (g *gossipServiceImpl) NewConfigEventer() ConfigProcessor {
 }
 
 // InitializeChannel allocates the state provider and should be invoked once per channel per execution
-func (g *gossipServiceImpl) InitializeChannel(chainID string, committer committer.Committer, endpoints []string) {
+func (g *gossipServiceImpl) InitializeChannel(chainID string, committer committer.Committer, endpoints []string, support Support) {
 	g.lock.Lock()
 	defer g.lock.Unlock()
 	// Initialize new state provider for given committer
 	logger.Debug("Creating state provider for chainID", chainID)
 	g.chains[chainID] = state.NewGossipStateProvider(chainID, g, committer, g.mcs)
+	if support.Store != nil && support.Cs != nil {
+		logger.Debug("Creating private data handler for chainID", chainID)
+		myOrg := string(g.secAdv.OrgByPeerIdentity(api.PeerIdentityType(g.peerIdentity)))
+		g.privateHandlers[chainID] = newPrivateDataHandler(chainID, myOrg, g, support, committer.LedgerHeight,
+			func(identity api.PeerIdentityType) string { return string(g.secAdv.OrgByPeerIdentity(identity)) },
+			g.idMapper.Get)
+	}
+	if support.Blobs != nil {
+		logger.Debug("Creating blob handler for chainID", chainID)
+		g.blobHandlers[chainID] = newBlobHandler(chainID, g, support.Blobs)
+	}
 	if g.deliveryService == nil {
 		var err error
 		g.deliveryService, err = g.deliveryFactory.Service(gossipServiceInstance, endpoints, g.mcs)
@@ -258,6 +281,39 @@ func (g *gossipServiceImpl) AddPayload(chainID string, payload *proto.Payload) e
 	return g.chains[chainID].AddPayload(payload)
 }
 
+// DistributePrivateData distributes the private data of a transaction to the peers authorized for its collections
+func (g *gossipServiceImpl) DistributePrivateData(chainID string, txID string, privData *rwset.TxPvtReadWriteSet) error {
+	g.lock.RLock()
+	handler, exists := g.privateHandlers[chainID]
+	g.lock.RUnlock()
+	if !exists {
+		return fmt.Errorf("No private data handler for channel %s", chainID)
+	}
+	return handler.distribute(txID, privData)
+}
+
+// DistributeBlobs sends the blobs with the given hashes, staged on this peer, to the other peers of the channel
+func (g *gossipServiceImpl) DistributeBlobs(chainID string, hashes [][]byte) error {
+	g.lock.RLock()
+	handler, exists := g.blobHandlers[chainID]
+	g.lock.RUnlock()
+	if !exists {
+		return fmt.Errorf("No blob handler for channel %s", chainID)
+	}
+	return handler.distribute(hashes)
+}
+
+// FetchBlobs retrieves from the other peers of the channel the blobs with the given hashes missing on this peer
+func (g *gossipServiceImpl) FetchBlobs(chainID string, hashes [][]byte) error {
+	g.lock.RLock()
+	handler, exists := g.blobHandlers[chainID]
+	g.lock.RUnlock()
+	if !exists {
+		return fmt.Errorf("No blob handler for channel %s", chainID)
+	}
+	return handler.fetch(hashes)
+}
+
 // Stop stops the gossip component
 func (g *gossipServiceImpl) Stop() {
 	g.lock.Lock()
@@ -267,6 +323,14 @@ func (g *gossipServiceImpl) Stop() {
 		ch.Stop()
 	}
 
+	for _, handler := range g.privateHandlers {
+		handler.stop()
+	}
+
+	for _, handler := range g.blobHandlers {
+		handler.stop()
+	}
+
 	for chainID, electionService := range g.leaderElection {
 		logger.Info("Stopping leader election for %s", chainID)
 		electionService.Stop()
@@ -0,0 +1,231 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/ledger/blobstore"
+	"github.com/hyperledger/fabric/core/transientstore"
+	"github.com/hyperledger/fabric/gossip/api"
+	"github.com/hyperledger/fabric/gossip/comm"
+	gossipCommon "github.com/hyperledger/fabric/gossip/common"
+	"github.com/hyperledger/fabric/gossip/discovery"
+	"github.com/hyperledger/fabric/gossip/util"
+	proto "github.com/hyperledger/fabric/protos/gossip"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+)
+
+// Support aggregates the private data and blob facilities of a channel
+// that gossip needs in order to disseminate and receive private data and blobs
+type Support struct {
+	// Store holds the private data received until the corresponding transactions are committed
+	Store transientstore.Store
+	// Cs resolves the collections of the chaincodes deployed on the channel
+	Cs privdata.CollectionStore
+	// Blobs stages the blobs received until the transactions referencing them are committed
+	Blobs blobstore.Store
+}
+
+// privateDataGossip defines the gossip capabilities the private data handler relies on
+type privateDataGossip interface {
+	// Send sends a message to remote peers
+	Send(msg *proto.GossipMessage, peers ...*comm.RemotePeer)
+
+	// PeersOfChannel returns the NetworkMembers considered alive in a channel
+	PeersOfChannel(gossipCommon.ChainID) []discovery.NetworkMember
+
+	// Accept returns a channel that emits messages that fit the given predicate
+	Accept(acceptor gossipCommon.MessageAcceptor, passThrough bool) (<-chan *proto.GossipMessage, <-chan proto.ReceivedMessage)
+}
+
+// ledgerHeight returns the current height of the ledger of the channel
+type ledgerHeight func() (uint64, error)
+
+// privateDataHandler disseminates the private data of the transactions endorsed by this peer
+// to the authorized peers of a channel, and stores the private data received from other peers
+type privateDataHandler struct {
+	chainID string
+	myOrg   string
+	gossip  privateDataGossip
+	support Support
+	height  ledgerHeight
+	// orgOfPeer returns the MSP ID of the organization of the peer with the given identity
+	orgOfPeer func(api.PeerIdentityType) string
+	// identityOf returns the identity of the peer with the given PKI-ID
+	identityOf func(gossipCommon.PKIidType) (api.PeerIdentityType, error)
+	stopCh     chan struct{}
+}
+
+func newPrivateDataHandler(chainID string, myOrg string, g privateDataGossip, support Support, height ledgerHeight,
+	orgOfPeer func(api.PeerIdentityType) string, identityOf func(gossipCommon.PKIidType) (api.PeerIdentityType, error)) *privateDataHandler {
+	h := &privateDataHandler{
+		chainID:    chainID,
+		myOrg:      myOrg,
+		gossip:     g,
+		support:    support,
+		height:     height,
+		orgOfPeer:  orgOfPeer,
+		identityOf: identityOf,
+		stopCh:     make(chan struct{}),
+	}
+	_, commChan := g.Accept(func(message interface{}) bool {
+		msg := message.(proto.ReceivedMessage).GetGossipMessage()
+		return msg.IsPrivateDataMsg() && bytes.Equal(msg.Channel, []byte(chainID))
+	}, true)
+	go h.receive(commChan)
+	return h
+}
+
+// distribute sends the private data of each collection written by the transaction to
+// at least the required and at most the maximum number of peers of the collection's member orgs
+func (h *privateDataHandler) distribute(txID string, privData *rwset.TxPvtReadWriteSet) error {
+	for _, nsPvtRWSet := range privData.NsPvtRwset {
+		for _, collPvtRWSet := range nsPvtRWSet.CollectionPvtRwset {
+			coll, err := h.support.Cs.RetrieveCollection(nsPvtRWSet.Namespace, collPvtRWSet.CollectionName)
+			if err != nil {
+				return err
+			}
+			if coll == nil {
+				return fmt.Errorf("Collection [%s] is not defined for chaincode [%s] on channel [%s]",
+					collPvtRWSet.CollectionName, nsPvtRWSet.Namespace, h.chainID)
+			}
+			peers := h.eligiblePeers(coll)
+			if len(peers) < coll.RequiredPeerCount() {
+				return fmt.Errorf("Required to disseminate the private data of collection [%s:%s] to %d peers, but only %d are eligible",
+					nsPvtRWSet.Namespace, collPvtRWSet.CollectionName, coll.RequiredPeerCount(), len(peers))
+			}
+			if len(peers) > coll.MaximumPeerCount() {
+				peers = peers[:coll.MaximumPeerCount()]
+			}
+			if len(peers) == 0 {
+				continue
+			}
+			msg := &proto.GossipMessage{
+				Nonce:   util.RandomUInt64(),
+				Tag:     proto.GossipMessage_CHAN_ONLY,
+				Channel: []byte(h.chainID),
+				Content: &proto.GossipMessage_PrivateData{
+					PrivateData: &proto.PrivateDataMessage{
+						Payload: &proto.PrivatePayload{
+							CollectionName: collPvtRWSet.CollectionName,
+							Namespace:      nsPvtRWSet.Namespace,
+							TxId:           txID,
+							PrivateRwset:   collPvtRWSet.Rwset,
+						},
+					},
+				},
+			}
+			logger.Debugf("Sending private data of transaction %s, collection [%s:%s] to %d peers",
+				txID, nsPvtRWSet.Namespace, collPvtRWSet.CollectionName, len(peers))
+			h.gossip.Send(msg, peers...)
+		}
+	}
+	return nil
+}
+
+// eligiblePeers returns the alive peers of the channel that belong to the member orgs of the collection,
+// in a random order so that the dissemination load is spread among them
+func (h *privateDataHandler) eligiblePeers(coll privdata.Collection) []*comm.RemotePeer {
+	var peers []*comm.RemotePeer
+	for _, member := range h.gossip.PeersOfChannel(gossipCommon.ChainID(h.chainID)) {
+		identity, err := h.identityOf(member.PKIid)
+		if err != nil {
+			logger.Debug("Unable to resolve the identity of", member.PreferredEndpoint(), ":", err)
+			continue
+		}
+		if isMemberOrg(h.orgOfPeer(identity), coll) {
+			peers = append(peers, &comm.RemotePeer{Endpoint: member.PreferredEndpoint(), PKIID: member.PKIid})
+		}
+	}
+	for i := len(peers) - 1; i > 0; i-- {
+		j := util.RandomInt(i + 1)
+		peers[i], peers[j] = peers[j], peers[i]
+	}
+	return peers
+}
+
+// receive persists into the transient store the private data sent by other peers,
+// as long as both the sender and this peer are authorized for the collection
+func (h *privateDataHandler) receive(commChan <-chan proto.ReceivedMessage) {
+	for {
+		select {
+		case msg := <-commChan:
+			if msg == nil {
+				return
+			}
+			if err := h.handlePrivateDataMsg(msg); err != nil {
+				logger.Warning("Discarding private data message on channel", h.chainID, ":", err)
+			}
+		case <-h.stopCh:
+			return
+		}
+	}
+}
+
+func (h *privateDataHandler) handlePrivateDataMsg(msg proto.ReceivedMessage) error {
+	payload := msg.GetGossipMessage().GetPrivateData().Payload
+	if payload == nil {
+		return fmt.Errorf("Empty private data payload")
+	}
+	coll, err := h.support.Cs.RetrieveCollection(payload.Namespace, payload.CollectionName)
+	if err != nil {
+		return err
+	}
+	if coll == nil {
+		return fmt.Errorf("Collection [%s:%s] is not defined", payload.Namespace, payload.CollectionName)
+	}
+	if !isMemberOrg(h.myOrg, coll) {
+		return fmt.Errorf("This peer is not a member of collection [%s:%s]", payload.Namespace, payload.CollectionName)
+	}
+	if connInfo := msg.GetConnectionInfo(); connInfo != nil && connInfo.Identity != nil {
+		if !isMemberOrg(h.orgOfPeer(connInfo.Identity), coll) {
+			return fmt.Errorf("Sender is not a member of collection [%s:%s]", payload.Namespace, payload.CollectionName)
+		}
+	}
+	height, err := h.height()
+	if err != nil {
+		return err
+	}
+	privData := &rwset.TxPvtReadWriteSet{
+		DataModel: rwset.TxReadWriteSet_KV,
+		NsPvtRwset: []*rwset.NsPvtReadWriteSet{{
+			Namespace: payload.Namespace,
+			CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{{
+				CollectionName: payload.CollectionName,
+				Rwset:          payload.PrivateRwset,
+			}},
+		}},
+	}
+	logger.Debugf("Storing private data of transaction %s, collection [%s:%s]", payload.TxId, payload.Namespace, payload.CollectionName)
+	return h.support.Store.Persist(payload.TxId, height, privData)
+}
+
+func (h *privateDataHandler) stop() {
+	close(h.stopCh)
+}
+
+func isMemberOrg(org string, coll privdata.Collection) bool {
+	for _, memberOrg := range coll.MemberOrgs() {
+		if memberOrg == org {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,231 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/gossip/api"
+	"github.com/hyperledger/fabric/gossip/comm"
+	gossipCommon "github.com/hyperledger/fabric/gossip/common"
+	"github.com/hyperledger/fabric/gossip/discovery"
+	"github.com/hyperledger/fabric/protos/common"
+	proto "github.com/hyperledger/fabric/protos/gossip"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPrivateDataGossip struct {
+	sync.Mutex
+	members  []discovery.NetworkMember
+	sent     map[string][]*proto.GossipMessage
+	commChan chan proto.ReceivedMessage
+	// onSend, if set, is called with each message sent to a peer
+	onSend func(msg *proto.GossipMessage, peer *comm.RemotePeer)
+}
+
+func (g *mockPrivateDataGossip) Send(msg *proto.GossipMessage, peers ...*comm.RemotePeer) {
+	g.Lock()
+	defer g.Unlock()
+	for _, p := range peers {
+		g.sent[p.Endpoint] = append(g.sent[p.Endpoint], msg)
+		if g.onSend != nil {
+			g.onSend(msg, p)
+		}
+	}
+}
+
+func (g *mockPrivateDataGossip) PeersOfChannel(gossipCommon.ChainID) []discovery.NetworkMember {
+	return g.members
+}
+
+func (g *mockPrivateDataGossip) Accept(acceptor gossipCommon.MessageAcceptor, passThrough bool) (<-chan *proto.GossipMessage, <-chan proto.ReceivedMessage) {
+	return nil, g.commChan
+}
+
+type mockReceivedMessage struct {
+	msg     *proto.SignedGossipMessage
+	sender  api.PeerIdentityType
+	respond func(msg *proto.GossipMessage)
+}
+
+func (m *mockReceivedMessage) Respond(msg *proto.GossipMessage) {
+	if m.respond != nil {
+		m.respond(msg)
+	}
+}
+
+func (m *mockReceivedMessage) GetGossipMessage() *proto.SignedGossipMessage {
+	return m.msg
+}
+
+func (m *mockReceivedMessage) GetSourceEnvelope() *proto.Envelope {
+	return nil
+}
+
+func (m *mockReceivedMessage) GetConnectionInfo() *proto.ConnectionInfo {
+	return &proto.ConnectionInfo{ID: gossipCommon.PKIidType(m.sender), Identity: m.sender}
+}
+
+type mockCollection struct {
+	name              string
+	orgs              []string
+	required, maximum int
+}
+
+func (c *mockCollection) CollectionID() string   { return c.name }
+func (c *mockCollection) MemberOrgs() []string   { return c.orgs }
+func (c *mockCollection) RequiredPeerCount() int { return c.required }
+func (c *mockCollection) MaximumPeerCount() int  { return c.maximum }
+
+type mockCollectionStore map[string]privdata.Collection
+
+func (cs mockCollectionStore) RetrieveCollection(namespace, collection string) (privdata.Collection, error) {
+	return cs[namespace+"/"+collection], nil
+}
+
+func (cs mockCollectionStore) RetrieveCollectionConfigPackage(namespace string) (*common.CollectionConfigPackage, error) {
+	return nil, nil
+}
+
+type mockTransientStore struct {
+	sync.Mutex
+	persisted map[string]*rwset.TxPvtReadWriteSet
+}
+
+func (s *mockTransientStore) Persist(txid string, endorsementBlkHt uint64, privateSimulationResults *rwset.TxPvtReadWriteSet) error {
+	s.Lock()
+	defer s.Unlock()
+	s.persisted[txid] = privateSimulationResults
+	return nil
+}
+
+func (s *mockTransientStore) GetTxPvtRWSetByTxid(txid string) (*rwset.TxPvtReadWriteSet, error) {
+	s.Lock()
+	defer s.Unlock()
+	return s.persisted[txid], nil
+}
+
+func (s *mockTransientStore) PurgeByTxids(txids []string) error {
+	return nil
+}
+
+func (s *mockTransientStore) PurgeByHeight(maxBlockNumToRetain uint64) error {
+	return nil
+}
+
+// newTestPrivateDataHandler creates a handler on a channel of 4 peers, the ones with an even index
+// belong to Org1MSP and the others to Org2MSP. This peer belongs to Org1MSP
+func newTestPrivateDataHandler(cs mockCollectionStore) (*privateDataHandler, *mockPrivateDataGossip, *mockTransientStore) {
+	g := &mockPrivateDataGossip{sent: make(map[string][]*proto.GossipMessage), commChan: make(chan proto.ReceivedMessage)}
+	for i := 0; i < 4; i++ {
+		g.members = append(g.members, discovery.NetworkMember{Endpoint: fmt.Sprintf("p%d", i), PKIid: gossipCommon.PKIidType(fmt.Sprintf("p%d", i))})
+	}
+	store := &mockTransientStore{persisted: make(map[string]*rwset.TxPvtReadWriteSet)}
+	orgOfPeer := func(identity api.PeerIdentityType) string {
+		if (identity[len(identity)-1]-'0')%2 == 0 {
+			return "Org1MSP"
+		}
+		return "Org2MSP"
+	}
+	identityOf := func(pkiID gossipCommon.PKIidType) (api.PeerIdentityType, error) {
+		return api.PeerIdentityType(pkiID), nil
+	}
+	h := newPrivateDataHandler("testchain", "Org1MSP", g, Support{Store: store, Cs: cs},
+		func() (uint64, error) { return 10, nil }, orgOfPeer, identityOf)
+	return h, g, store
+}
+
+func samplePrivateData(ns, coll string) *rwset.TxPvtReadWriteSet {
+	return &rwset.TxPvtReadWriteSet{
+		DataModel: rwset.TxReadWriteSet_KV,
+		NsPvtRwset: []*rwset.NsPvtReadWriteSet{{
+			Namespace:          ns,
+			CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{{CollectionName: coll, Rwset: []byte("rwset")}},
+		}},
+	}
+}
+
+func TestDistributePrivateData(t *testing.T) {
+	cs := mockCollectionStore{
+		"mycc/coll1": &mockCollection{name: "coll1", orgs: []string{"Org1MSP"}, required: 1, maximum: 1},
+		"mycc/coll2": &mockCollection{name: "coll2", orgs: []string{"Org2MSP"}, required: 3, maximum: 3},
+	}
+	h, g, _ := newTestPrivateDataHandler(cs)
+	defer h.stop()
+
+	// only one of the peers of Org1MSP receives the private data of coll1
+	assert.NoError(t, h.distribute("tx1", samplePrivateData("mycc", "coll1")))
+	assert.Len(t, g.sent, 1)
+	for endpoint, msgs := range g.sent {
+		assert.Contains(t, []string{"p0", "p2"}, endpoint)
+		assert.Len(t, msgs, 1)
+		assert.Equal(t, "tx1", msgs[0].GetPrivateData().Payload.TxId)
+		assert.Equal(t, proto.GossipMessage_CHAN_ONLY, msgs[0].Tag)
+	}
+
+	// Org2MSP has only 2 peers while 3 are required
+	assert.Error(t, h.distribute("tx2", samplePrivateData("mycc", "coll2")))
+	// undefined collection
+	assert.Error(t, h.distribute("tx3", samplePrivateData("mycc", "coll3")))
+}
+
+func TestReceivePrivateData(t *testing.T) {
+	cs := mockCollectionStore{
+		"mycc/coll1": &mockCollection{name: "coll1", orgs: []string{"Org1MSP"}, required: 0, maximum: 1},
+		"mycc/coll2": &mockCollection{name: "coll2", orgs: []string{"Org2MSP"}, required: 0, maximum: 1},
+	}
+	h, g, store := newTestPrivateDataHandler(cs)
+	defer h.stop()
+
+	newMsg := func(txID, coll string, sender string) proto.ReceivedMessage {
+		msg := &proto.GossipMessage{
+			Tag:     proto.GossipMessage_CHAN_ONLY,
+			Channel: []byte("testchain"),
+			Content: &proto.GossipMessage_PrivateData{PrivateData: &proto.PrivateDataMessage{
+				Payload: &proto.PrivatePayload{CollectionName: coll, Namespace: "mycc", TxId: txID, PrivateRwset: []byte("rwset")},
+			}},
+		}
+		return &mockReceivedMessage{msg: msg.NoopSign(), sender: api.PeerIdentityType(sender)}
+	}
+
+	g.commChan <- newMsg("tx1", "coll1", "p2")
+	// the sender is not a member of the collection
+	g.commChan <- newMsg("tx2", "coll1", "p1")
+	// this peer is not a member of the collection
+	g.commChan <- newMsg("tx3", "coll2", "p1")
+	// the handler processes messages in order, so once this one is stored the previous ones have been handled
+	g.commChan <- newMsg("tx4", "coll1", "p0")
+
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if pvtData, _ := store.GetTxPvtRWSetByTxid("tx4"); pvtData != nil {
+			break
+		}
+	}
+	pvtData, _ := store.GetTxPvtRWSetByTxid("tx4")
+	assert.NotNil(t, pvtData)
+	pvtData, _ = store.GetTxPvtRWSetByTxid("tx1")
+	assert.Equal(t, samplePrivateData("mycc", "coll1"), pvtData)
+	for _, txID := range []string{"tx2", "tx3"} {
+		pvtData, _ = store.GetTxPvtRWSetByTxid(txID)
+		assert.Nil(t, pvtData)
+	}
+}
@@ -0,0 +1,276 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/msp/mgmt"
+	"github.com/hyperledger/fabric/orderer/kafka"
+	"github.com/hyperledger/fabric/orderer/ledger"
+	"github.com/hyperledger/fabric/orderer/multichain"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+
+	"github.com/op/go-logging"
+	"golang.org/x/net/context"
+)
+
+var logger = logging.MustGetLogger("orderer/admin")
+
+// maxClockSkew is the largest difference between the timestamp of a request
+// and the local time for the request to be accepted, which bounds the window
+// in which a captured request can be replayed
+const maxClockSkew = 15 * time.Minute
+
+type server struct {
+	manager         multichain.Manager
+	localMSP        msp.MSP
+	principalGetter mgmt.MSPPrincipalGetter
+	policy          string
+}
+
+// NewServer creates an ab.AdminServer reporting the status of the channels of
+// the manager to the requests signed by an identity of the local MSP which
+// holds the role named by policy, either mgmt.Admins or mgmt.Members. Removing
+// and restoring channels always requires the mgmt.Admins role
+func NewServer(manager multichain.Manager, localMSP msp.MSP, principalGetter mgmt.MSPPrincipalGetter, policy string) ab.AdminServer {
+	return &server{
+		manager:         manager,
+		localMSP:        localMSP,
+		principalGetter: principalGetter,
+		policy:          policy,
+	}
+}
+
+// Status returns the status of the channel named in the request, or of all
+// the channels if none is named
+func (s *server) Status(ctx context.Context, env *cb.Envelope) (*ab.StatusResponse, error) {
+	payload, status := s.checkRequest(env, s.policy)
+	if status != cb.Status_SUCCESS {
+		return &ab.StatusResponse{Status: status}, nil
+	}
+
+	request := &ab.StatusRequest{}
+	if err := proto.Unmarshal(payload.Data, request); err != nil {
+		logger.Warningf("Received a status request with malformed data: %s", err)
+		return &ab.StatusResponse{Status: cb.Status_BAD_REQUEST}, nil
+	}
+
+	chainIDs := []string{request.ChannelId}
+	if request.ChannelId == "" {
+		chainIDs = s.manager.ChannelIDs()
+	}
+
+	response := &ab.StatusResponse{Status: cb.Status_SUCCESS}
+	for _, chainID := range chainIDs {
+		cs, ok := s.manager.GetChain(chainID)
+		if !ok {
+			if request.ChannelId != "" {
+				logger.Debugf("Status requested for channel %s, which is not serviced", chainID)
+				return &ab.StatusResponse{Status: cb.Status_NOT_FOUND}, nil
+			}
+			// The channel was removed since it was listed
+			continue
+		}
+
+		status, err := s.channelStatus(cs)
+		if err != nil {
+			logger.Errorf("Failed to retrieve the status of channel %s: %s", chainID, err)
+			return &ab.StatusResponse{Status: cb.Status_INTERNAL_SERVER_ERROR}, nil
+		}
+		response.Channels = append(response.Channels, status)
+	}
+
+	if request.ChannelId == "" {
+		response.ArchivedChannels = s.manager.ArchivedChannelIDs()
+	}
+
+	return response, nil
+}
+
+// RemoveChannel halts the channel named in the request, stops servicing it
+// and moves its ledger to the archive
+func (s *server) RemoveChannel(ctx context.Context, env *cb.Envelope) (*ab.ChannelResponse, error) {
+	chainID, status := s.checkChannelRequest(env)
+	if status != cb.Status_SUCCESS {
+		return &ab.ChannelResponse{Status: status}, nil
+	}
+
+	if chainID == s.manager.SystemChannelID() {
+		return &ab.ChannelResponse{Status: cb.Status_BAD_REQUEST, Info: "the system channel cannot be removed"}, nil
+	}
+	if _, ok := s.manager.GetChain(chainID); !ok {
+		return &ab.ChannelResponse{Status: cb.Status_NOT_FOUND, Info: fmt.Sprintf("channel %s is not serviced", chainID)}, nil
+	}
+
+	if err := s.manager.RemoveChannel(chainID); err != nil {
+		logger.Errorf("Failed to remove channel %s: %s", chainID, err)
+		return &ab.ChannelResponse{Status: cb.Status_INTERNAL_SERVER_ERROR, Info: err.Error()}, nil
+	}
+	logger.Infof("Removed channel %s", chainID)
+	return &ab.ChannelResponse{Status: cb.Status_SUCCESS}, nil
+}
+
+// RestoreChannel services again the archived channel named in the request
+func (s *server) RestoreChannel(ctx context.Context, env *cb.Envelope) (*ab.ChannelResponse, error) {
+	chainID, status := s.checkChannelRequest(env)
+	if status != cb.Status_SUCCESS {
+		return &ab.ChannelResponse{Status: status}, nil
+	}
+
+	if _, ok := s.manager.GetChain(chainID); ok {
+		return &ab.ChannelResponse{Status: cb.Status_BAD_REQUEST, Info: fmt.Sprintf("channel %s is serviced", chainID)}, nil
+	}
+	archived := false
+	for _, archivedID := range s.manager.ArchivedChannelIDs() {
+		if archivedID == chainID {
+			archived = true
+			break
+		}
+	}
+	if !archived {
+		return &ab.ChannelResponse{Status: cb.Status_NOT_FOUND, Info: fmt.Sprintf("channel %s is not archived", chainID)}, nil
+	}
+
+	if err := s.manager.RestoreChannel(chainID); err != nil {
+		logger.Errorf("Failed to restore channel %s: %s", chainID, err)
+		return &ab.ChannelResponse{Status: cb.Status_INTERNAL_SERVER_ERROR, Info: err.Error()}, nil
+	}
+	logger.Infof("Restored channel %s", chainID)
+	return &ab.ChannelResponse{Status: cb.Status_SUCCESS}, nil
+}
+
+// checkChannelRequest checks a request changing the channels serviced, which
+// always requires the Admins principal, and returns the channel it names
+func (s *server) checkChannelRequest(env *cb.Envelope) (string, cb.Status) {
+	payload, status := s.checkRequest(env, mgmt.Admins)
+	if status != cb.Status_SUCCESS {
+		return "", status
+	}
+
+	request := &ab.ChannelRequest{}
+	if err := proto.Unmarshal(payload.Data, request); err != nil {
+		logger.Warningf("Received a channel request with malformed data: %s", err)
+		return "", cb.Status_BAD_REQUEST
+	}
+	if request.ChannelId == "" {
+		logger.Warningf("Received a channel request naming no channel")
+		return "", cb.Status_BAD_REQUEST
+	}
+	return request.ChannelId, cb.Status_SUCCESS
+}
+
+// checkRequest checks that the envelope is a recent message signed by an
+// identity holding the role named by policy, and returns its payload
+func (s *server) checkRequest(env *cb.Envelope, policy string) (*cb.Payload, cb.Status) {
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		logger.Warningf("Received an envelope with no payload: %s", err)
+		return nil, cb.Status_BAD_REQUEST
+	}
+
+	if payload.Header == nil {
+		logger.Warningf("Malformed envelope received with bad header")
+		return nil, cb.Status_BAD_REQUEST
+	}
+
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		logger.Warningf("Failed to unmarshal channel header: %s", err)
+		return nil, cb.Status_BAD_REQUEST
+	}
+
+	if chdr.Type != int32(cb.HeaderType_MESSAGE) || chdr.Timestamp == nil {
+		logger.Warningf("Received an envelope of type %d, or without a timestamp", chdr.Type)
+		return nil, cb.Status_BAD_REQUEST
+	}
+
+	shdr, err := utils.GetSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		logger.Warningf("Failed to unmarshal signature header: %s", err)
+		return nil, cb.Status_BAD_REQUEST
+	}
+
+	timestamp := time.Unix(chdr.Timestamp.Seconds, int64(chdr.Timestamp.Nanos))
+	if skew := time.Since(timestamp); skew > maxClockSkew || skew < -maxClockSkew {
+		logger.Warningf("Rejecting request with timestamp %s, too far from the local time", timestamp)
+		return nil, cb.Status_FORBIDDEN
+	}
+
+	if err := s.authorize(policy, shdr.Creator, env.Payload, env.Signature); err != nil {
+		logger.Warningf("Rejecting unauthorized request: %s", err)
+		return nil, cb.Status_FORBIDDEN
+	}
+
+	return payload, cb.Status_SUCCESS
+}
+
+// authorize checks that the creator holds the role of the policy in the local
+// MSP and has signed the payload
+func (s *server) authorize(policy string, creator []byte, payload []byte, signature []byte) error {
+	principal, err := s.principalGetter.Get(policy)
+	if err != nil {
+		return fmt.Errorf("failed getting local MSP principal [%s]: %s", policy, err)
+	}
+
+	id, err := s.localMSP.DeserializeIdentity(creator)
+	if err != nil {
+		return fmt.Errorf("failed deserializing the creator: %s", err)
+	}
+
+	if err := id.SatisfiesPrincipal(principal); err != nil {
+		return fmt.Errorf("the creator does not satisfy the local MSP's [%s] principal: %s", policy, err)
+	}
+
+	if err := id.Verify(payload, signature); err != nil {
+		return fmt.Errorf("failed verifying the signature: %s", err)
+	}
+
+	return nil
+}
+
+func (s *server) channelStatus(cs multichain.ChainSupport) (*ab.ChannelStatus, error) {
+	status := &ab.ChannelStatus{
+		ChannelId:        cs.ChainID(),
+		SystemChannel:    cs.ChainID() == s.manager.SystemChannelID(),
+		Height:           cs.Height(),
+		ConsensusType:    cs.SharedConfig().ConsensusType(),
+		PendingBatchSize: uint32(cs.BlockCutter().PendingCount()),
+	}
+
+	if status.ConsensusType == "kafka" && status.Height > 0 {
+		lastBlock := ledger.GetBlock(cs.Reader(), status.Height-1)
+		if lastBlock == nil {
+			return nil, fmt.Errorf("could not retrieve block %d", status.Height-1)
+		}
+		metadata, err := utils.GetMetadataFromBlock(lastBlock, cb.BlockMetadataIndex_ORDERER)
+		if err != nil {
+			return nil, fmt.Errorf("could not extract the orderer metadata of block %d: %s", status.Height-1, err)
+		}
+		status.KafkaLastOffsetPersisted, err = kafka.LastOffsetPersisted(metadata)
+		if err != nil {
+			return nil, fmt.Errorf("could not unmarshal the Kafka metadata of block %d: %s", status.Height-1, err)
+		}
+	}
+
+	return status, nil
+}
@@ -0,0 +1,258 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	genesisconfig "github.com/hyperledger/fabric/common/configtx/tool/localconfig"
+	"github.com/hyperledger/fabric/common/configtx/tool/provisional"
+	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/common/localmsp"
+	mockcrypto "github.com/hyperledger/fabric/common/mocks/crypto"
+	"github.com/hyperledger/fabric/msp/mgmt"
+	"github.com/hyperledger/fabric/orderer/ledger"
+	ramledger "github.com/hyperledger/fabric/orderer/ledger/ram"
+	"github.com/hyperledger/fabric/orderer/multichain"
+	cb "github.com/hyperledger/fabric/protos/common"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+
+	"github.com/Shopify/sarama"
+	logging "github.com/op/go-logging"
+	"github.com/stretchr/testify/assert"
+)
+
+const channelID = "testchannel"
+
+var genesisBlock, channelGenesisBlock *cb.Block
+
+func init() {
+	logging.SetLevel(logging.DEBUG, "")
+	conf := genesisconfig.Load(genesisconfig.SampleInsecureProfile)
+	conf.Orderer.OrdererType = provisional.ConsensusTypeKafka
+	genesisBlock = provisional.New(conf).GenesisBlock()
+	conf.Consortiums = nil
+	channelGenesisBlock = provisional.New(conf).GenesisBlockForChannel(channelID)
+	if err := mgmt.LoadDevMsp(); err != nil {
+		panic(err)
+	}
+}
+
+type mockConsenter struct{}
+
+func (mc *mockConsenter) HandleChain(support multichain.ConsenterSupport, metadata *cb.Metadata) (multichain.Chain, error) {
+	return &mockChain{}, nil
+}
+
+type mockChain struct{}
+
+func (mch *mockChain) Enqueue(env *cb.Envelope) bool { return true }
+
+func (mch *mockChain) Start() {}
+
+func (mch *mockChain) Halt() {}
+
+func newTestServer(t *testing.T) (ab.AdminServer, multichain.Manager, ledger.ReadWriter) {
+	lf := ramledger.New(10)
+	rl, err := lf.GetOrCreate(provisional.TestChainID)
+	assert.NoError(t, err)
+	assert.NoError(t, rl.Append(genesisBlock))
+	// The ledger of the test channel starts in the archive
+	channelLedger, err := lf.GetOrCreate(channelID)
+	assert.NoError(t, err)
+	assert.NoError(t, channelLedger.Append(channelGenesisBlock))
+	assert.NoError(t, lf.(ledger.Archiver).Archive(channelID))
+
+	consenters := map[string]multichain.Consenter{provisional.ConsensusTypeKafka: &mockConsenter{}}
+	manager := multichain.NewManagerImpl(lf, consenters, mockcrypto.FakeLocalSigner)
+	return NewServer(manager, mgmt.GetLocalMSP(), mgmt.NewLocalMSPPrincipalGetter(), mgmt.Admins), manager, rl
+}
+
+func makeRequest(t *testing.T, signer crypto.LocalSigner, headerType cb.HeaderType, ts time.Time, request proto.Message) *cb.Envelope {
+	shdr, err := signer.NewSignatureHeader()
+	assert.NoError(t, err)
+	chdr := utils.MakeChannelHeader(headerType, 0, "", 0)
+	chdr.Timestamp = &timestamp.Timestamp{Seconds: ts.Unix()}
+	payloadBytes := utils.MarshalOrPanic(&cb.Payload{
+		Header: utils.MakePayloadHeader(chdr, shdr),
+		Data:   utils.MarshalOrPanic(request),
+	})
+	signature, err := signer.Sign(payloadBytes)
+	assert.NoError(t, err)
+	return &cb.Envelope{Payload: payloadBytes, Signature: signature}
+}
+
+// makeCreator returns a serialized identity of the local MSP's ID whose
+// certificate is not one of its admins
+func makeCreator(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "notanadmin"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	mspID, err := mgmt.GetLocalMSP().GetIdentifier()
+	assert.NoError(t, err)
+	return utils.MarshalOrPanic(&mspprotos.SerializedIdentity{
+		Mspid:   mspID,
+		IdBytes: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	})
+}
+
+func TestStatus(t *testing.T) {
+	server, manager, rl := newTestServer(t)
+	signer := localmsp.NewSigner()
+
+	response, err := server.Status(nil, makeRequest(t, signer, cb.HeaderType_MESSAGE, time.Now(), &ab.StatusRequest{}))
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_SUCCESS, response.Status)
+	assert.Len(t, response.Channels, 1)
+	assert.Equal(t, &ab.ChannelStatus{
+		ChannelId:                provisional.TestChainID,
+		SystemChannel:            true,
+		Height:                   1,
+		ConsensusType:            provisional.ConsensusTypeKafka,
+		KafkaLastOffsetPersisted: sarama.OffsetOldest - 1,
+	}, response.Channels[0])
+
+	block := ledger.CreateNextBlock(rl, []*cb.Envelope{{Payload: []byte("tx")}})
+	block.Metadata.Metadata[cb.BlockMetadataIndex_ORDERER] = utils.MarshalOrPanic(&cb.Metadata{
+		Value: utils.MarshalOrPanic(&ab.KafkaMetadata{LastOffsetPersisted: 42}),
+	})
+	assert.NoError(t, rl.Append(block))
+	cs, _ := manager.GetChain(provisional.TestChainID)
+	cs.BlockCutter().Ordered(&cb.Envelope{Payload: utils.MarshalOrPanic(&cb.Payload{
+		Header: &cb.Header{
+			ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{
+				Type:      int32(cb.HeaderType_ENDORSER_TRANSACTION),
+				ChannelId: provisional.TestChainID,
+			}),
+			SignatureHeader: utils.MarshalOrPanic(&cb.SignatureHeader{}),
+		},
+	})})
+
+	response, err = server.Status(nil, makeRequest(t, signer, cb.HeaderType_MESSAGE, time.Now(), &ab.StatusRequest{ChannelId: provisional.TestChainID}))
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_SUCCESS, response.Status)
+	assert.Len(t, response.Channels, 1)
+	assert.Equal(t, uint64(2), response.Channels[0].Height)
+	assert.Equal(t, int64(42), response.Channels[0].KafkaLastOffsetPersisted)
+	assert.Equal(t, uint32(1), response.Channels[0].PendingBatchSize)
+
+	response, err = server.Status(nil, makeRequest(t, signer, cb.HeaderType_MESSAGE, time.Now(), &ab.StatusRequest{ChannelId: "bogus"}))
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_NOT_FOUND, response.Status)
+}
+
+func TestStatusRejectedRequests(t *testing.T) {
+	server, _, _ := newTestServer(t)
+	signer := localmsp.NewSigner()
+
+	response, err := server.Status(nil, &cb.Envelope{Payload: []byte("garbage")})
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_BAD_REQUEST, response.Status, "Malformed payloads should be rejected")
+
+	response, err = server.Status(nil, makeRequest(t, signer, cb.HeaderType_ENDORSER_TRANSACTION, time.Now(), &ab.StatusRequest{}))
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_BAD_REQUEST, response.Status, "Requests of other types than MESSAGE should be rejected")
+
+	response, err = server.Status(nil, makeRequest(t, signer, cb.HeaderType_MESSAGE, time.Now().Add(-2*maxClockSkew), &ab.StatusRequest{}))
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_FORBIDDEN, response.Status, "Stale requests should be rejected")
+
+	env := makeRequest(t, signer, cb.HeaderType_MESSAGE, time.Now(), &ab.StatusRequest{})
+	env.Signature[len(env.Signature)-1] ^= 0xff
+	response, err = server.Status(nil, env)
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_FORBIDDEN, response.Status, "Requests with a bad signature should be rejected")
+
+	notAdmin := &mockcrypto.LocalSigner{Identity: makeCreator(t), Nonce: []byte("nonce")}
+	response, err = server.Status(nil, makeRequest(t, notAdmin, cb.HeaderType_MESSAGE, time.Now(), &ab.StatusRequest{}))
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_FORBIDDEN, response.Status, "Requests of identities which are not admins of the local MSP should be rejected")
+}
+
+func TestRemoveAndRestoreChannel(t *testing.T) {
+	server, manager, _ := newTestServer(t)
+	signer := localmsp.NewSigner()
+
+	response, err := server.RemoveChannel(nil, makeRequest(t, signer, cb.HeaderType_MESSAGE, time.Now(), &ab.ChannelRequest{ChannelId: channelID}))
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_NOT_FOUND, response.Status, "Archived channels cannot be removed")
+	response, err = server.RestoreChannel(nil, makeRequest(t, signer, cb.HeaderType_MESSAGE, time.Now(), &ab.ChannelRequest{ChannelId: "bogus"}))
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_NOT_FOUND, response.Status, "Channels which were not archived cannot be restored")
+
+	response, err = server.RestoreChannel(nil, makeRequest(t, signer, cb.HeaderType_MESSAGE, time.Now(), &ab.ChannelRequest{ChannelId: channelID}))
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_SUCCESS, response.Status)
+	_, ok := manager.GetChain(channelID)
+	assert.True(t, ok, "Restored channel should be serviced")
+	assert.Empty(t, manager.ArchivedChannelIDs())
+
+	response, err = server.RestoreChannel(nil, makeRequest(t, signer, cb.HeaderType_MESSAGE, time.Now(), &ab.ChannelRequest{ChannelId: channelID}))
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_BAD_REQUEST, response.Status, "Serviced channels cannot be restored")
+	response, err = server.RemoveChannel(nil, makeRequest(t, signer, cb.HeaderType_MESSAGE, time.Now(), &ab.ChannelRequest{ChannelId: provisional.TestChainID}))
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_BAD_REQUEST, response.Status, "The system channel cannot be removed")
+
+	response, err = server.RemoveChannel(nil, makeRequest(t, signer, cb.HeaderType_MESSAGE, time.Now(), &ab.ChannelRequest{ChannelId: channelID}))
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_SUCCESS, response.Status)
+	_, ok = manager.GetChain(channelID)
+	assert.False(t, ok, "Removed channel should no longer be serviced")
+	assert.Equal(t, []string{channelID}, manager.ArchivedChannelIDs())
+}
+
+func TestChannelRequestsRequireAdmins(t *testing.T) {
+	lf := ramledger.New(10)
+	rl, err := lf.GetOrCreate(provisional.TestChainID)
+	assert.NoError(t, err)
+	assert.NoError(t, rl.Append(genesisBlock))
+	consenters := map[string]multichain.Consenter{provisional.ConsensusTypeKafka: &mockConsenter{}}
+	manager := multichain.NewManagerImpl(lf, consenters, mockcrypto.FakeLocalSigner)
+	server := NewServer(manager, mgmt.GetLocalMSP(), mgmt.NewLocalMSPPrincipalGetter(), mgmt.Members)
+
+	notAdmin := &mockcrypto.LocalSigner{Identity: makeCreator(t), Nonce: []byte("nonce")}
+	response, err := server.RemoveChannel(nil, makeRequest(t, notAdmin, cb.HeaderType_MESSAGE, time.Now(), &ab.ChannelRequest{ChannelId: provisional.TestChainID}))
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_FORBIDDEN, response.Status, "Channels should only be removed by admins whatever the policy of the server")
+	response, err = server.RestoreChannel(nil, makeRequest(t, notAdmin, cb.HeaderType_MESSAGE, time.Now(), &ab.ChannelRequest{ChannelId: provisional.TestChainID}))
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_FORBIDDEN, response.Status, "Channels should only be restored by admins whatever the policy of the server")
+
+	response, err = server.RemoveChannel(nil, makeRequest(t, localmsp.NewSigner(), cb.HeaderType_MESSAGE, time.Now(), &ab.ChannelRequest{}))
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_BAD_REQUEST, response.Status, "Requests should name a channel")
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package blockcutter
 
 import (
+	"sync/atomic"
+
 	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/orderer/common/filter"
 	cb "github.com/hyperledger/fabric/protos/common"
@@ -49,6 +51,10 @@ type Receiver interface {
 
 	// Cut returns the current batch and starts a new one
 	Cut() ([]*cb.Envelope, []filter.Committer)
+
+	// PendingCount returns the number of messages in the current batch
+	// Unlike Ordered and Cut, it may be invoked concurrently
+	PendingCount() int
 }
 
 type receiver struct {
@@ -57,6 +63,7 @@ type receiver struct {
 	pendingBatch          []*cb.Envelope
 	pendingBatchSizeBytes uint32
 	pendingCommitters     []filter.Committer
+	pendingCount          int32
 }
 
 // NewReceiverImpl creates a Receiver implementation based on the given configtxorderer manager and filters
@@ -136,6 +143,7 @@ func (r *receiver) Ordered(msg *cb.Envelope) ([][]*cb.Envelope, [][]filter.Commi
 	r.pendingBatch = append(r.pendingBatch, msg)
 	r.pendingBatchSizeBytes += messageSizeBytes
 	r.pendingCommitters = append(r.pendingCommitters, committer)
+	atomic.StoreInt32(&r.pendingCount, int32(len(r.pendingBatch)))
 
 	if uint32(len(r.pendingBatch)) >= r.sharedConfigManager.BatchSize().MaxMessageCount {
 		logger.Debugf("Batch size met, cutting batch")
@@ -160,9 +168,15 @@ func (r *receiver) Cut() ([]*cb.Envelope, []filter.Committer) {
 	committers := r.pendingCommitters
 	r.pendingCommitters = nil
 	r.pendingBatchSizeBytes = 0
+	atomic.StoreInt32(&r.pendingCount, 0)
 	return batch, committers
 }
 
+// PendingCount returns the number of messages in the current batch
+func (r *receiver) PendingCount() int {
+	return int(atomic.LoadInt32(&r.pendingCount))
+}
+
 func messageSizeBytes(message *cb.Envelope) uint32 {
 	return uint32(len(message.Payload) + len(message.Signature))
 }
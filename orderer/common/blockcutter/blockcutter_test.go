@@ -340,3 +340,32 @@ func TestBatchSizePreferredMaxBytesOverflowNoPending(t *testing.T) {
 	}
 
 }
+
+func TestPendingCount(t *testing.T) {
+	filters := getFilters()
+	maxMessageCount := uint32(3)
+	absoluteMaxBytes := uint32(1000)
+	preferredMaxBytes := uint32(100)
+	r := NewReceiverImpl(&mockconfig.Orderer{BatchSizeVal: &ab.BatchSize{MaxMessageCount: maxMessageCount, AbsoluteMaxBytes: absoluteMaxBytes, PreferredMaxBytes: preferredMaxBytes}}, filters)
+
+	if r.PendingCount() != 0 {
+		t.Fatalf("Should have no pending messages")
+	}
+
+	r.Ordered(goodTx)
+	r.Ordered(goodTx)
+	if r.PendingCount() != 2 {
+		t.Fatalf("Should have 2 pending messages, got %d", r.PendingCount())
+	}
+
+	r.Ordered(goodTx)
+	if r.PendingCount() != 0 {
+		t.Fatalf("Should have no pending messages after the batch was cut, got %d", r.PendingCount())
+	}
+
+	r.Ordered(goodTx)
+	r.Cut()
+	if r.PendingCount() != 0 {
+		t.Fatalf("Should have no pending messages after Cut, got %d", r.PendingCount())
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deliver
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/orderer/common/filter"
+	"github.com/hyperledger/fabric/orderer/common/sigfilter"
+	cb "github.com/hyperledger/fabric/protos/common"
+)
+
+// sessionAC checks that the client of a deliver session remains authorized
+// to read the chain for as long as blocks are delivered to it. The Readers
+// policy is re-evaluated against the seek request whenever the chain config
+// changes, and the session is no longer authorized once the client's
+// certificate expires
+type sessionAC struct {
+	chain     Support
+	envelope  *cb.Envelope
+	expiresAt time.Time
+	sequence  uint64
+	evaluated bool
+}
+
+func newSessionAC(chain Support, envelope *cb.Envelope, expiresAt time.Time) *sessionAC {
+	return &sessionAC{
+		chain:     chain,
+		envelope:  envelope,
+		expiresAt: expiresAt,
+	}
+}
+
+// evaluate returns an error if the client is not, or no longer, authorized
+func (ac *sessionAC) evaluate() error {
+	if !ac.expiresAt.IsZero() && !time.Now().Before(ac.expiresAt) {
+		return fmt.Errorf("client identity expired at %s", ac.expiresAt)
+	}
+
+	sequence := ac.chain.Sequence()
+	if ac.evaluated && sequence == ac.sequence {
+		return nil
+	}
+
+	sf := sigfilter.New(policies.ChannelReaders, ac.chain.PolicyManager())
+	if result, _ := sf.Apply(ac.envelope); result != filter.Forward {
+		return fmt.Errorf("client is not authorized by the %s policy at config sequence %d", policies.ChannelReaders, sequence)
+	}
+	ac.sequence = sequence
+	ac.evaluated = true
+	return nil
+}
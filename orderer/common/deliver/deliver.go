@@ -17,12 +17,14 @@ limitations under the License.
 package deliver
 
 import (
+	"time"
+
+	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/policies"
-	"github.com/hyperledger/fabric/orderer/common/filter"
-	"github.com/hyperledger/fabric/orderer/common/sigfilter"
 	"github.com/hyperledger/fabric/orderer/ledger"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/op/go-logging"
 
 	"github.com/golang/protobuf/proto"
@@ -34,6 +36,25 @@ var logger = logging.MustGetLogger("orderer/common/deliver")
 // Handler defines an interface which handles Deliver requests
 type Handler interface {
 	Handle(srv ab.AtomicBroadcast_DeliverServer) error
+
+	// HandleStream handles Deliver requests received over any Stream
+	HandleStream(stream Stream) error
+}
+
+// Stream is the server side of a Deliver stream, abstracted from the service
+// exposing it so that the orderer and the peer deliver blocks the same way
+type Stream interface {
+	// Recv returns the next seek request of the client
+	Recv() (*cb.Envelope, error)
+
+	// SendStatus sends the status terminating a seek request
+	SendStatus(status cb.Status) error
+
+	// SendBlock sends a full block
+	SendBlock(block *cb.Block) error
+
+	// SendFilteredBlock sends a filtered block
+	SendFilteredBlock(filteredBlock *pb.FilteredBlock) error
 }
 
 // SupportManager provides a way for the Handler to look up the Support for a chain
@@ -48,6 +69,9 @@ type Support interface {
 
 	// Reader returns the chain Reader for the chain
 	Reader() ledger.Reader
+
+	// Sequence returns the current config sequence number of the chain
+	Sequence() uint64
 }
 
 type deliverServer struct {
@@ -62,6 +86,10 @@ func NewHandlerImpl(sm SupportManager) Handler {
 }
 
 func (ds *deliverServer) Handle(srv ab.AtomicBroadcast_DeliverServer) error {
+	return ds.HandleStream(&abStream{srv: srv})
+}
+
+func (ds *deliverServer) HandleStream(srv Stream) error {
 	logger.Debugf("Starting new deliver loop")
 	for {
 		logger.Debugf("Attempting to read seek info message")
@@ -77,14 +105,14 @@ func (ds *deliverServer) Handle(srv ab.AtomicBroadcast_DeliverServer) error {
 			if logger.IsEnabledFor(logging.WARNING) {
 				logger.Warningf("Received an envelope with no payload: %s", err)
 			}
-			return sendStatusReply(srv, cb.Status_BAD_REQUEST)
+			return srv.SendStatus(cb.Status_BAD_REQUEST)
 		}
 
 		if payload.Header == nil {
 			if logger.IsEnabledFor(logging.WARNING) {
 				logger.Warningf("Malformed envelope received with bad header")
 			}
-			return sendStatusReply(srv, cb.Status_BAD_REQUEST)
+			return srv.SendStatus(cb.Status_BAD_REQUEST)
 		}
 
 		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
@@ -100,16 +128,23 @@ func (ds *deliverServer) Handle(srv ab.AtomicBroadcast_DeliverServer) error {
 			if logger.IsEnabledFor(logging.DEBUG) {
 				logger.Debugf("Client request for channel %s not found", chdr.ChannelId)
 			}
-			return sendStatusReply(srv, cb.Status_NOT_FOUND)
+			return srv.SendStatus(cb.Status_NOT_FOUND)
 		}
 
-		sf := sigfilter.New(policies.ChannelReaders, chain.PolicyManager())
-		result, _ := sf.Apply(envelope)
-		if result != filter.Forward {
+		shdr, err := utils.GetSignatureHeader(payload.Header.SignatureHeader)
+		if err != nil {
+			if logger.IsEnabledFor(logging.WARNING) {
+				logger.Warningf("Received a deliver request with a malformed signature header: %s", err)
+			}
+			return srv.SendStatus(cb.Status_BAD_REQUEST)
+		}
+
+		ac := newSessionAC(chain, envelope, crypto.ExpiresAt(shdr.Creator))
+		if err := ac.evaluate(); err != nil {
 			if logger.IsEnabledFor(logging.WARNING) {
-				logger.Warningf("Received unauthorized deliver request for channel %s", chdr.ChannelId)
+				logger.Warningf("Received unauthorized deliver request for channel %s: %s", chdr.ChannelId, err)
 			}
-			return sendStatusReply(srv, cb.Status_FORBIDDEN)
+			return srv.SendStatus(cb.Status_FORBIDDEN)
 		}
 
 		seekInfo := &ab.SeekInfo{}
@@ -117,14 +152,21 @@ func (ds *deliverServer) Handle(srv ab.AtomicBroadcast_DeliverServer) error {
 			if logger.IsEnabledFor(logging.WARNING) {
 				logger.Warningf("Received a signed deliver request with malformed seekInfo payload: %s", err)
 			}
-			return sendStatusReply(srv, cb.Status_BAD_REQUEST)
+			return srv.SendStatus(cb.Status_BAD_REQUEST)
+		}
+
+		if _, ok := ab.SeekInfo_SeekContentType_name[int32(seekInfo.ContentType)]; !ok {
+			if logger.IsEnabledFor(logging.WARNING) {
+				logger.Warningf("Received seekInfo message with unknown content type %d", seekInfo.ContentType)
+			}
+			return srv.SendStatus(cb.Status_BAD_REQUEST)
 		}
 
 		if seekInfo.Start == nil || seekInfo.Stop == nil {
 			if logger.IsEnabledFor(logging.WARNING) {
 				logger.Warningf("Received seekInfo message with missing start or stop %v, %v", seekInfo.Start, seekInfo.Stop)
 			}
-			return sendStatusReply(srv, cb.Status_BAD_REQUEST)
+			return srv.SendStatus(cb.Status_BAD_REQUEST)
 		}
 
 		if logger.IsEnabledFor(logging.DEBUG) {
@@ -142,53 +184,171 @@ func (ds *deliverServer) Handle(srv ab.AtomicBroadcast_DeliverServer) error {
 			stopNum = stop.Specified.Number
 		}
 
-		for {
-			if seekInfo.Behavior == ab.SeekInfo_BLOCK_UNTIL_READY {
-				<-cursor.ReadyChan()
-			} else {
-				select {
-				case <-cursor.ReadyChan():
-				default:
-					return sendStatusReply(srv, cb.Status_NOT_FOUND)
-				}
-			}
+		status, err := deliverBlocks(srv, chdr.ChannelId, seekInfo, ac, cursor, stopNum)
+		if c, ok := cursor.(closer); ok {
+			c.Close()
+		}
+		if err != nil {
+			return err
+		}
+		if status != cb.Status_SUCCESS {
+			return srv.SendStatus(status)
+		}
 
-			block, status := cursor.Next()
-			if status != cb.Status_SUCCESS {
-				logger.Errorf("Error reading from channel, cause was: %v", status)
-				return sendStatusReply(srv, status)
-			}
+		if err := srv.SendStatus(cb.Status_SUCCESS); err != nil {
+			return err
+		}
+		if logger.IsEnabledFor(logging.DEBUG) {
+			logger.Debugf("Done delivering for (%p), waiting for new SeekInfo", seekInfo)
+		}
+	}
+}
 
-			if logger.IsEnabledFor(logging.DEBUG) {
-				logger.Debugf("Delivering block for (%p) channel: %s", seekInfo, chdr.ChannelId)
+// closer is implemented by the Iterators holding resources which must be
+// released once the seek is done
+type closer interface {
+	Close()
+}
+
+// deliverBlocks sends the blocks of cursor up to block stopNum for as long as
+// the client remains authorized, returning the status to reply with if the
+// seek cannot be completed
+func deliverBlocks(srv Stream, chainID string, seekInfo *ab.SeekInfo, ac *sessionAC, cursor ledger.Iterator, stopNum uint64) (cb.Status, error) {
+	var expired <-chan time.Time
+	if !ac.expiresAt.IsZero() {
+		timer := time.NewTimer(ac.expiresAt.Sub(time.Now()))
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	for {
+		if seekInfo.Behavior == ab.SeekInfo_BLOCK_UNTIL_READY {
+			select {
+			case <-cursor.ReadyChan():
+			case <-expired:
+				logger.Warningf("Terminating deliver for (%p) channel %s: client identity expired at %s", seekInfo, chainID, ac.expiresAt)
+				return cb.Status_FORBIDDEN, nil
 			}
-			if err := sendBlockReply(srv, block); err != nil {
-				return err
+		} else {
+			select {
+			case <-cursor.ReadyChan():
+			default:
+				return cb.Status_NOT_FOUND, nil
 			}
+		}
 
-			if stopNum == block.Header.Number {
-				break
-			}
+		block, status := cursor.Next()
+		if status != cb.Status_SUCCESS {
+			logger.Errorf("Error reading from channel, cause was: %v", status)
+			return status, nil
 		}
 
-		if err := sendStatusReply(srv, cb.Status_SUCCESS); err != nil {
-			return err
+		if err := ac.evaluate(); err != nil {
+			logger.Warningf("Terminating deliver for (%p) channel %s: %s", seekInfo, chainID, err)
+			return cb.Status_FORBIDDEN, nil
 		}
+
 		if logger.IsEnabledFor(logging.DEBUG) {
-			logger.Debugf("Done delivering for (%p), waiting for new SeekInfo", seekInfo)
+			logger.Debugf("Delivering block for (%p) channel: %s", seekInfo, chainID)
+		}
+		if err := sendBlock(srv, chainID, seekInfo.ContentType, block); err != nil {
+			return cb.Status_SUCCESS, err
+		}
+
+		if stopNum == block.Header.Number {
+			return cb.Status_SUCCESS, nil
+		}
+	}
+}
+
+func sendBlock(srv Stream, channelID string, contentType ab.SeekInfo_SeekContentType, block *cb.Block) error {
+	if contentType == ab.SeekInfo_BLOCK {
+		return srv.SendBlock(block)
+	}
+	return srv.SendFilteredBlock(filterBlock(channelID, block, contentType == ab.SeekInfo_FILTERED_BLOCK_WITH_EVENTS))
+}
+
+// filterBlock strips a block of channel channelID down to the id, type and validation code of its
+// transactions, plus their chaincode events if withEvents is set. Blocks
+// which were not validated by a committer, as the orderer's are, report their
+// transactions as NOT_VALIDATED
+func filterBlock(channelID string, block *cb.Block, withEvents bool) *pb.FilteredBlock {
+	var txsFilter []byte
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txsFilter = block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER]
+	}
+
+	filteredBlock := &pb.FilteredBlock{ChannelId: channelID, Number: block.Header.Number}
+	if block.Data == nil {
+		return filteredBlock
+	}
+
+	for i, data := range block.Data.Data {
+		env, err := utils.GetEnvelopeFromBlock(data)
+		if err != nil {
+			logger.Warningf("Skipping malformed transaction %d of block %d: %s", i, block.Header.Number, err)
+			continue
+		}
+		payload, err := utils.UnmarshalPayload(env.Payload)
+		if err != nil || payload.Header == nil {
+			logger.Warningf("Skipping transaction %d of block %d with malformed payload", i, block.Header.Number)
+			continue
 		}
+		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			logger.Warningf("Skipping transaction %d of block %d with malformed channel header: %s", i, block.Header.Number, err)
+			continue
+		}
+
+		filteredTx := &pb.FilteredTransaction{
+			Txid:             chdr.TxId,
+			Type:             cb.HeaderType(chdr.Type),
+			TxValidationCode: pb.TxValidationCode_NOT_VALIDATED,
+		}
+		if i < len(txsFilter) {
+			filteredTx.TxValidationCode = pb.TxValidationCode(txsFilter[i])
+		}
+
+		if withEvents && filteredTx.Type == cb.HeaderType_ENDORSER_TRANSACTION &&
+			(filteredTx.TxValidationCode == pb.TxValidationCode_VALID || filteredTx.TxValidationCode == pb.TxValidationCode_NOT_VALIDATED) {
+			action, err := utils.GetActionFromEnvelope(data)
+			if err == nil && action != nil {
+				filteredTx.ChaincodeEvents, err = utils.GetChaincodeEventList(action.Events)
+			}
+			if err != nil {
+				logger.Warningf("Could not extract the chaincode events of transaction %s: %s", chdr.TxId, err)
+			}
+		}
+
+		filteredBlock.FilteredTransactions = append(filteredBlock.FilteredTransactions, filteredTx)
 	}
+
+	return filteredBlock
+}
+
+// abStream adapts an AtomicBroadcast Deliver stream to a Stream
+type abStream struct {
+	srv ab.AtomicBroadcast_DeliverServer
+}
+
+func (s *abStream) Recv() (*cb.Envelope, error) {
+	return s.srv.Recv()
 }
 
-func sendStatusReply(srv ab.AtomicBroadcast_DeliverServer, status cb.Status) error {
-	return srv.Send(&ab.DeliverResponse{
+func (s *abStream) SendStatus(status cb.Status) error {
+	return s.srv.Send(&ab.DeliverResponse{
 		Type: &ab.DeliverResponse_Status{Status: status},
 	})
-
 }
 
-func sendBlockReply(srv ab.AtomicBroadcast_DeliverServer, block *cb.Block) error {
-	return srv.Send(&ab.DeliverResponse{
+func (s *abStream) SendBlock(block *cb.Block) error {
+	return s.srv.Send(&ab.DeliverResponse{
 		Type: &ab.DeliverResponse_Block{Block: block},
 	})
 }
+
+func (s *abStream) SendFilteredBlock(filteredBlock *pb.FilteredBlock) error {
+	return s.srv.Send(&ab.DeliverResponse{
+		Type: &ab.DeliverResponse_FilteredBlock{FilteredBlock: filteredBlock},
+	})
+}
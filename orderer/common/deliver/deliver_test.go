@@ -17,7 +17,15 @@ limitations under the License.
 package deliver
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"math/big"
+	"sync"
 	"testing"
 	"time"
 
@@ -27,7 +35,9 @@ import (
 	"github.com/hyperledger/fabric/orderer/ledger"
 	ramledger "github.com/hyperledger/fabric/orderer/ledger/ram"
 	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
 	ab "github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 	logging "github.com/op/go-logging"
 	"google.golang.org/grpc"
@@ -80,13 +90,31 @@ func (mm *mockSupportManager) GetChain(chainID string) (Support, bool) {
 
 type mockSupport struct {
 	ledger        ledger.ReadWriter
+	lock          sync.Mutex
 	policyManager *mockpolicies.Manager
+	sequence      uint64
 }
 
 func (mcs *mockSupport) PolicyManager() policies.Manager {
+	mcs.lock.Lock()
+	defer mcs.lock.Unlock()
 	return mcs.policyManager
 }
 
+func (mcs *mockSupport) Sequence() uint64 {
+	mcs.lock.Lock()
+	defer mcs.lock.Unlock()
+	return mcs.sequence
+}
+
+// updateConfig mimics a config update replacing the policy manager
+func (mcs *mockSupport) updateConfig(policyManager *mockpolicies.Manager) {
+	mcs.lock.Lock()
+	defer mcs.lock.Unlock()
+	mcs.policyManager = policyManager
+	mcs.sequence++
+}
+
 func (mcs *mockSupport) Reader() ledger.Reader {
 	return mcs.ledger
 }
@@ -118,13 +146,17 @@ func seekSpecified(number uint64) *ab.SeekPosition {
 }
 
 func makeSeek(chainID string, seekInfo *ab.SeekInfo) *cb.Envelope {
+	return makeSignedSeek(chainID, seekInfo, nil)
+}
+
+func makeSignedSeek(chainID string, seekInfo *ab.SeekInfo, creator []byte) *cb.Envelope {
 	return &cb.Envelope{
 		Payload: utils.MarshalOrPanic(&cb.Payload{
 			Header: &cb.Header{
 				ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{
 					ChannelId: chainID,
 				}),
-				SignatureHeader: utils.MarshalOrPanic(&cb.SignatureHeader{}),
+				SignatureHeader: utils.MarshalOrPanic(&cb.SignatureHeader{Creator: creator}),
 			},
 			Data: utils.MarshalOrPanic(seekInfo),
 		}),
@@ -373,3 +405,264 @@ func TestBlockingSeek(t *testing.T) {
 		t.Fatalf("Timed out waiting to get all blocks")
 	}
 }
+
+func makeEndorserTx(chainID string, txID string, event *pb.ChaincodeEvent) *cb.Envelope {
+	action := &pb.ChaincodeAction{Events: utils.MarshalOrPanic(event)}
+	prp := &pb.ProposalResponsePayload{Extension: utils.MarshalOrPanic(action)}
+	ccap := &pb.ChaincodeActionPayload{Action: &pb.ChaincodeEndorsedAction{ProposalResponsePayload: utils.MarshalOrPanic(prp)}}
+	tx := &pb.Transaction{Actions: []*pb.TransactionAction{&pb.TransactionAction{Payload: utils.MarshalOrPanic(ccap)}}}
+	return &cb.Envelope{
+		Payload: utils.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{
+					Type:      int32(cb.HeaderType_ENDORSER_TRANSACTION),
+					ChannelId: chainID,
+					TxId:      txID,
+				}),
+			},
+			Data: utils.MarshalOrPanic(tx),
+		}),
+	}
+}
+
+func TestFilteredSeek(t *testing.T) {
+	mm := newMockMultichainManager()
+	l := mm.chains[systemChainID].ledger
+	block := ledger.CreateNextBlock(l, []*cb.Envelope{
+		makeEndorserTx(systemChainID, "tx1", &pb.ChaincodeEvent{ChaincodeId: "mycc", TxId: "tx1", EventName: "ev1"}),
+		makeEndorserTx(systemChainID, "tx2", &pb.ChaincodeEvent{ChaincodeId: "mycc", TxId: "tx2", EventName: "ev2"}),
+	})
+	l.Append(block)
+	validatedBlock := ledger.CreateNextBlock(l, []*cb.Envelope{
+		makeEndorserTx(systemChainID, "tx3", &pb.ChaincodeEvent{ChaincodeId: "mycc", TxId: "tx3", EventName: "ev3"}),
+		makeEndorserTx(systemChainID, "tx4", &pb.ChaincodeEvent{ChaincodeId: "mycc", TxId: "tx4", EventName: "ev4"}),
+	})
+	validatedBlock.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = []byte{
+		byte(pb.TxValidationCode_VALID),
+		byte(pb.TxValidationCode_MVCC_READ_CONFLICT),
+	}
+	l.Append(validatedBlock)
+
+	for _, contentType := range []ab.SeekInfo_SeekContentType{ab.SeekInfo_FILTERED_BLOCK, ab.SeekInfo_FILTERED_BLOCK_WITH_EVENTS} {
+		withEvents := contentType == ab.SeekInfo_FILTERED_BLOCK_WITH_EVENTS
+
+		m := newMockD()
+		ds := NewHandlerImpl(mm)
+
+		go ds.Handle(m)
+
+		m.recvChan <- makeSeek(systemChainID, &ab.SeekInfo{Start: seekSpecified(1), Stop: seekSpecified(2), Behavior: ab.SeekInfo_BLOCK_UNTIL_READY, ContentType: contentType})
+
+		expected := []struct {
+			txID   string
+			code   pb.TxValidationCode
+			events int
+		}{
+			{"tx1", pb.TxValidationCode_NOT_VALIDATED, 1},
+			{"tx2", pb.TxValidationCode_NOT_VALIDATED, 1},
+			{"tx3", pb.TxValidationCode_VALID, 1},
+			{"tx4", pb.TxValidationCode_MVCC_READ_CONFLICT, 0},
+		}
+		for number := uint64(1); number <= 2; number++ {
+			select {
+			case deliverReply := <-m.sendChan:
+				fb := deliverReply.GetFilteredBlock()
+				if fb == nil {
+					t.Fatalf("Expected a filtered block but got %v", deliverReply)
+				}
+				if fb.Number != number || fb.ChannelId != systemChainID {
+					t.Fatalf("Expected block %d of channel %s but got block %d of channel %s", number, systemChainID, fb.Number, fb.ChannelId)
+				}
+				if len(fb.FilteredTransactions) != 2 {
+					t.Fatalf("Expected 2 transactions but got %d", len(fb.FilteredTransactions))
+				}
+				for _, ftx := range fb.FilteredTransactions {
+					exp := expected[0]
+					expected = expected[1:]
+					if ftx.Txid != exp.txID || ftx.Type != cb.HeaderType_ENDORSER_TRANSACTION || ftx.TxValidationCode != exp.code {
+						t.Fatalf("Expected transaction %s with code %s but got %v", exp.txID, exp.code, ftx)
+					}
+					expEvents := 0
+					if withEvents {
+						expEvents = exp.events
+					}
+					if len(ftx.ChaincodeEvents) != expEvents {
+						t.Fatalf("Expected %d chaincode events for %s but got %d", expEvents, exp.txID, len(ftx.ChaincodeEvents))
+					}
+					if expEvents > 0 && ftx.ChaincodeEvents[0].TxId != exp.txID {
+						t.Fatalf("Expected the chaincode event of %s but got %v", exp.txID, ftx.ChaincodeEvents[0])
+					}
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("Timed out waiting to get filtered blocks")
+			}
+		}
+
+		select {
+		case deliverReply := <-m.sendChan:
+			if deliverReply.GetStatus() != cb.Status_SUCCESS {
+				t.Fatalf("Expected delivery to complete")
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for the delivery to complete")
+		}
+		close(m.recvChan)
+	}
+}
+
+func TestBadContentType(t *testing.T) {
+	mm := newMockMultichainManager()
+
+	m := newMockD()
+	defer close(m.recvChan)
+	ds := NewHandlerImpl(mm)
+
+	go ds.Handle(m)
+
+	m.recvChan <- makeSeek(systemChainID, &ab.SeekInfo{Start: seekOldest, Stop: seekNewest, Behavior: ab.SeekInfo_BLOCK_UNTIL_READY, ContentType: ab.SeekInfo_SeekContentType(42)})
+
+	select {
+	case deliverReply := <-m.sendChan:
+		if deliverReply.GetStatus() != cb.Status_BAD_REQUEST {
+			t.Fatalf("Expected a bad request status but got %v", deliverReply)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting for the reply")
+	}
+}
+
+func TestConfigChangeReauthorization(t *testing.T) {
+	mm := newMockMultichainManager()
+	cs := mm.chains[systemChainID]
+
+	m := newMockD()
+	defer close(m.recvChan)
+	ds := NewHandlerImpl(mm)
+
+	go ds.Handle(m)
+
+	m.recvChan <- makeSeek(systemChainID, &ab.SeekInfo{Start: seekNewest, Stop: seekSpecified(3), Behavior: ab.SeekInfo_BLOCK_UNTIL_READY})
+
+	select {
+	case deliverReply := <-m.sendChan:
+		if deliverReply.GetBlock() == nil {
+			t.Fatalf("Expected to receive the genesis block")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting to get the genesis block")
+	}
+
+	// A config change which still authorizes the client does not end the session
+	cs.updateConfig(&mockpolicies.Manager{Policy: &mockpolicies.Policy{}})
+	cs.ledger.Append(ledger.CreateNextBlock(cs.ledger, []*cb.Envelope{&cb.Envelope{Payload: []byte("1")}}))
+
+	select {
+	case deliverReply := <-m.sendChan:
+		if deliverReply.GetBlock() == nil {
+			t.Fatalf("Expected to receive block 1 but got %v", deliverReply)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting to get block 1")
+	}
+
+	// A policy change without a config change is not noticed
+	cs.lock.Lock()
+	cs.policyManager.Policy.Err = fmt.Errorf("Fail to evaluate")
+	cs.lock.Unlock()
+	cs.ledger.Append(ledger.CreateNextBlock(cs.ledger, []*cb.Envelope{&cb.Envelope{Payload: []byte("2")}}))
+
+	select {
+	case deliverReply := <-m.sendChan:
+		if deliverReply.GetBlock() == nil {
+			t.Fatalf("Expected to receive block 2 but got %v", deliverReply)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting to get block 2")
+	}
+
+	cs.updateConfig(&mockpolicies.Manager{Policy: &mockpolicies.Policy{Err: fmt.Errorf("Fail to evaluate")}})
+	cs.ledger.Append(ledger.CreateNextBlock(cs.ledger, []*cb.Envelope{&cb.Envelope{Payload: []byte("3")}}))
+
+	select {
+	case deliverReply := <-m.sendChan:
+		if deliverReply.GetStatus() != cb.Status_FORBIDDEN {
+			t.Fatalf("Expected the session to be terminated as forbidden but got %v", deliverReply)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting for the session to be terminated")
+	}
+}
+
+func makeCreator(t *testing.T, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Could not generate a key: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Could not create a certificate: %s", err)
+	}
+	return utils.MarshalOrPanic(&msp.SerializedIdentity{
+		Mspid:   "SampleOrg",
+		IdBytes: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	})
+}
+
+func TestExpiredIdentity(t *testing.T) {
+	mm := newMockMultichainManager()
+
+	m := newMockD()
+	defer close(m.recvChan)
+	ds := NewHandlerImpl(mm)
+
+	go ds.Handle(m)
+
+	m.recvChan <- makeSignedSeek(systemChainID, &ab.SeekInfo{Start: seekOldest, Stop: seekOldest, Behavior: ab.SeekInfo_BLOCK_UNTIL_READY}, makeCreator(t, time.Now().Add(-time.Minute)))
+
+	select {
+	case deliverReply := <-m.sendChan:
+		if deliverReply.GetStatus() != cb.Status_FORBIDDEN {
+			t.Fatalf("Expected an expired identity to be forbidden but got %v", deliverReply)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting for the reply")
+	}
+}
+
+func TestIdentityExpiresDuringSession(t *testing.T) {
+	mm := newMockMultichainManager()
+
+	m := newMockD()
+	defer close(m.recvChan)
+	ds := NewHandlerImpl(mm)
+
+	go ds.Handle(m)
+
+	// Certificates only carry whole seconds
+	m.recvChan <- makeSignedSeek(systemChainID, &ab.SeekInfo{Start: seekOldest, Stop: seekSpecified(1), Behavior: ab.SeekInfo_BLOCK_UNTIL_READY}, makeCreator(t, time.Now().Truncate(time.Second).Add(2*time.Second)))
+
+	select {
+	case deliverReply := <-m.sendChan:
+		if deliverReply.GetBlock() == nil {
+			t.Fatalf("Expected to receive the genesis block but got %v", deliverReply)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting to get the genesis block")
+	}
+
+	// The session is waiting for block 1 when the certificate expires
+	select {
+	case deliverReply := <-m.sendChan:
+		if deliverReply.GetStatus() != cb.Status_FORBIDDEN {
+			t.Fatalf("Expected the session to be terminated as forbidden but got %v", deliverReply)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("Timed out waiting for the session to be terminated")
+	}
+}
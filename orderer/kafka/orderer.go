@@ -84,16 +84,26 @@ func (co *consenterImpl) HandleChain(cs multichain.ConsenterSupport, metadata *c
 }
 
 func getLastOffsetPersisted(metadata *cb.Metadata, chainID string) int64 {
+	offset, err := LastOffsetPersisted(metadata)
+	if err != nil {
+		logger.Panicf("[channel: %s] Ledger may be corrupted:"+
+			"cannot unmarshal orderer metadata in most recent block", chainID)
+	}
+	return offset
+}
+
+// LastOffsetPersisted returns the offset of the last Kafka message persisted
+// to the ledger, as recorded in the orderer metadata of its most recent block
+func LastOffsetPersisted(metadata *cb.Metadata) (int64, error) {
 	if metadata.Value != nil {
 		// Extract orderer-related metadata from the tip of the ledger first
 		kafkaMetadata := &ab.KafkaMetadata{}
 		if err := proto.Unmarshal(metadata.Value, kafkaMetadata); err != nil {
-			logger.Panicf("[channel: %s] Ledger may be corrupted:"+
-				"cannot unmarshal orderer metadata in most recent block", chainID)
+			return 0, err
 		}
-		return kafkaMetadata.LastOffsetPersisted
+		return kafkaMetadata.LastOffsetPersisted, nil
 	}
-	return (sarama.OffsetOldest - 1) // default
+	return (sarama.OffsetOldest - 1), nil // default
 }
 
 // When testing we need to inject our own broker/producer/consumer.
@@ -17,15 +17,24 @@ limitations under the License.
 package fileledger
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/orderer/ledger"
 )
 
+// archiveDir is the directory, under the ledger directory, holding the block
+// files of the archived chains
+const archiveDir = "archive"
+
 type fileLedgerFactory struct {
 	blkstorageProvider blkstorage.BlockStoreProvider
+	directory          string
 	ledgers            map[string]ledger.ReadWriter
 	mutex              sync.Mutex
 }
@@ -60,6 +69,67 @@ func (flf *fileLedgerFactory) ChainIDs() []string {
 	return chainIDs
 }
 
+// Archive closes the ledger of the chain and moves its block files to the
+// archive. The blocks index is left in place so that it is still in sync with
+// the block files once they are restored
+func (flf *fileLedgerFactory) Archive(chainID string) error {
+	flf.mutex.Lock()
+	defer flf.mutex.Unlock()
+
+	chainDir := filepath.Join(flf.directory, fsblkstorage.ChainsDir, chainID)
+	if exists, _, err := util.FileExists(chainDir); err != nil || !exists {
+		return fmt.Errorf("No ledger for chain %s", chainID)
+	}
+	archivedDir := filepath.Join(flf.directory, archiveDir, chainID)
+	if exists, _, _ := util.FileExists(archivedDir); exists {
+		return fmt.Errorf("Chain %s is already archived", chainID)
+	}
+
+	if l, ok := flf.ledgers[chainID]; ok {
+		l.(*fileLedger).blockStore.Shutdown()
+		delete(flf.ledgers, chainID)
+	}
+
+	if err := os.MkdirAll(filepath.Join(flf.directory, archiveDir), 0755); err != nil {
+		return err
+	}
+	return os.Rename(chainDir, archivedDir)
+}
+
+// Restore moves the block files of the archived chain back and opens its ledger
+func (flf *fileLedgerFactory) Restore(chainID string) (ledger.ReadWriter, error) {
+	flf.mutex.Lock()
+	chainDir := filepath.Join(flf.directory, fsblkstorage.ChainsDir, chainID)
+	archivedDir := filepath.Join(flf.directory, archiveDir, chainID)
+	if exists, _, err := util.FileExists(archivedDir); err != nil || !exists {
+		flf.mutex.Unlock()
+		return nil, fmt.Errorf("Chain %s is not archived", chainID)
+	}
+	if exists, _, _ := util.FileExists(chainDir); exists {
+		flf.mutex.Unlock()
+		return nil, fmt.Errorf("Chain %s already has a ledger", chainID)
+	}
+	err := os.Rename(archivedDir, chainDir)
+	flf.mutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return flf.GetOrCreate(chainID)
+}
+
+// ArchivedChainIDs returns the IDs of the chains in the archive
+func (flf *fileLedgerFactory) ArchivedChainIDs() []string {
+	dir := filepath.Join(flf.directory, archiveDir)
+	if exists, _, _ := util.FileExists(dir); !exists {
+		return nil
+	}
+	chainIDs, err := util.ListSubdirs(dir)
+	if err != nil {
+		logger.Panic(err)
+	}
+	return chainIDs
+}
+
 // Close releases all resources acquired by the factory
 func (flf *fileLedgerFactory) Close() {
 	flf.blkstorageProvider.Close()
@@ -73,6 +143,7 @@ func New(directory string) ledger.Factory {
 			&blkstorage.IndexConfig{
 				AttrsToIndex: []blkstorage.IndexableAttr{blkstorage.IndexableAttrBlockNum}},
 		),
-		ledgers: make(map[string]ledger.ReadWriter),
+		directory: directory,
+		ledgers:   make(map[string]ledger.ReadWriter),
 	}
 }
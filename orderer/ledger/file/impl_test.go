@@ -235,3 +235,60 @@ func TestBlockedRetrieval(t *testing.T) {
 		t.Fatalf("Expected to successfully retrieve the second block")
 	}
 }
+
+func TestArchiveAndRestore(t *testing.T) {
+	tev, fl := initialize(t)
+	defer tev.tearDown()
+
+	b1 := ledger.CreateNextBlock(fl, []*cb.Envelope{&cb.Envelope{Payload: []byte("My Data")}})
+	fl.Append(b1)
+
+	archiver := tev.flf.(ledger.Archiver)
+	if err := archiver.Archive("bogus"); err == nil {
+		t.Fatalf("Should not have archived an unknown chain")
+	}
+	if err := archiver.Archive(provisional.TestChainID); err != nil {
+		t.Fatalf("Error archiving the chain: %s", err)
+	}
+	if err := archiver.Archive(provisional.TestChainID); err == nil {
+		t.Fatalf("Should not have archived the chain twice")
+	}
+
+	// the archive survives re-initializing the ledger provider
+	tev.shutDown()
+	tev.flf = New(tev.location)
+	archiver = tev.flf.(ledger.Archiver)
+
+	if len(tev.flf.ChainIDs()) != 0 {
+		t.Fatalf("Archived chain should not be listed, got %v", tev.flf.ChainIDs())
+	}
+	if archived := archiver.ArchivedChainIDs(); len(archived) != 1 || archived[0] != provisional.TestChainID {
+		t.Fatalf("Expected the chain to be archived, got %v", archived)
+	}
+
+	if _, err := archiver.Restore("bogus"); err == nil {
+		t.Fatalf("Should not have restored a chain which is not archived")
+	}
+	restored, err := archiver.Restore(provisional.TestChainID)
+	if err != nil {
+		t.Fatalf("Error restoring the chain: %s", err)
+	}
+	if restored.Height() != 2 {
+		t.Fatalf("Block height should be 2. Got %v", restored.Height())
+	}
+	block := ledger.GetBlock(restored, 1)
+	if block == nil || !bytes.Equal(block.Header.Hash(), b1.Header.Hash()) {
+		t.Fatalf("Error retrieving block 1 of the restored chain")
+	}
+	if len(archiver.ArchivedChainIDs()) != 0 || len(tev.flf.ChainIDs()) != 1 {
+		t.Fatalf("Restored chain should no longer be archived")
+	}
+
+	b2 := ledger.CreateNextBlock(restored, []*cb.Envelope{&cb.Envelope{Payload: []byte("More Data")}})
+	if err := restored.Append(b2); err != nil {
+		t.Fatalf("Error appending to the restored chain: %s", err)
+	}
+	if restored.Height() != 3 {
+		t.Fatalf("Block height should be 3. Got %v", restored.Height())
+	}
+}
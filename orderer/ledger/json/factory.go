@@ -27,6 +27,10 @@ import (
 	"github.com/hyperledger/fabric/orderer/ledger"
 )
 
+// archiveDir is the directory, under the ledger directory, holding the chain
+// directories of the archived chains
+const archiveDir = "archive"
+
 type jsonLedgerFactory struct {
 	directory string
 	ledgers   map[string]ledger.ReadWriter
@@ -121,6 +125,74 @@ func (jlf *jsonLedgerFactory) ChainIDs() []string {
 	return ids
 }
 
+// Archive moves the directory of the chain to the archive
+func (jlf *jsonLedgerFactory) Archive(chainID string) error {
+	jlf.mutex.Lock()
+	defer jlf.mutex.Unlock()
+
+	if _, ok := jlf.ledgers[chainID]; !ok {
+		return fmt.Errorf("No ledger for chain %s", chainID)
+	}
+	chainDir := fmt.Sprintf(chainDirectoryFormatString, chainID)
+	archivedDir := filepath.Join(jlf.directory, archiveDir, chainDir)
+	if _, err := os.Stat(archivedDir); err == nil {
+		return fmt.Errorf("Chain %s is already archived", chainID)
+	}
+
+	if err := os.MkdirAll(filepath.Join(jlf.directory, archiveDir), 0700); err != nil {
+		return err
+	}
+	if err := os.Rename(filepath.Join(jlf.directory, chainDir), archivedDir); err != nil {
+		return err
+	}
+	delete(jlf.ledgers, chainID)
+	return nil
+}
+
+// Restore moves the directory of the archived chain back and opens its ledger
+func (jlf *jsonLedgerFactory) Restore(chainID string) (ledger.ReadWriter, error) {
+	jlf.mutex.Lock()
+	defer jlf.mutex.Unlock()
+
+	if _, ok := jlf.ledgers[chainID]; ok {
+		return nil, fmt.Errorf("Chain %s already has a ledger", chainID)
+	}
+	chainDir := fmt.Sprintf(chainDirectoryFormatString, chainID)
+	archivedDir := filepath.Join(jlf.directory, archiveDir, chainDir)
+	if _, err := os.Stat(archivedDir); err != nil {
+		return nil, fmt.Errorf("Chain %s is not archived", chainID)
+	}
+
+	directory := filepath.Join(jlf.directory, chainDir)
+	if err := os.Rename(archivedDir, directory); err != nil {
+		return nil, err
+	}
+	ch := newChain(directory)
+	jlf.ledgers[chainID] = ch
+	return ch, nil
+}
+
+// ArchivedChainIDs returns the IDs of the chains in the archive
+func (jlf *jsonLedgerFactory) ArchivedChainIDs() []string {
+	infos, err := ioutil.ReadDir(filepath.Join(jlf.directory, archiveDir))
+	if err != nil {
+		return nil
+	}
+
+	var ids []string
+	for _, info := range infos {
+		if !info.IsDir() {
+			continue
+		}
+		var chainID string
+		if _, err := fmt.Sscanf(info.Name(), chainDirectoryFormatString, &chainID); err != nil {
+			continue
+		}
+		ids = append(ids, chainID)
+	}
+	return ids
+}
+
 // Close is a no-op for the JSON ledger
 func (jlf *jsonLedgerFactory) Close() {
 	return // nothing to do
@@ -208,3 +208,48 @@ func TestBlockedRetrieval(t *testing.T) {
 		t.Fatalf("Expected to successfully retrieve the second block")
 	}
 }
+
+func TestArchiveAndRestore(t *testing.T) {
+	tev, fl := initialize(t)
+	defer tev.tearDown()
+
+	b1 := ledger.CreateNextBlock(fl, []*cb.Envelope{&cb.Envelope{Payload: []byte("My Data")}})
+	fl.Append(b1)
+
+	archiver := New(tev.location).(ledger.Archiver)
+	if err := archiver.Archive("bogus"); err == nil {
+		t.Fatalf("Should not have archived an unknown chain")
+	}
+	if err := archiver.Archive(provisional.TestChainID); err != nil {
+		t.Fatalf("Error archiving the chain: %s", err)
+	}
+
+	// the archive survives re-initializing the ledger factory
+	jlf := New(tev.location)
+	archiver = jlf.(ledger.Archiver)
+
+	if len(jlf.ChainIDs()) != 0 {
+		t.Fatalf("Archived chain should not be listed, got %v", jlf.ChainIDs())
+	}
+	if archived := archiver.ArchivedChainIDs(); len(archived) != 1 || archived[0] != provisional.TestChainID {
+		t.Fatalf("Expected the chain to be archived, got %v", archived)
+	}
+
+	if _, err := archiver.Restore("bogus"); err == nil {
+		t.Fatalf("Should not have restored a chain which is not archived")
+	}
+	restored, err := archiver.Restore(provisional.TestChainID)
+	if err != nil {
+		t.Fatalf("Error restoring the chain: %s", err)
+	}
+	if restored.Height() != 2 {
+		t.Fatalf("Block height should be 2. Got %v", restored.Height())
+	}
+	block := ledger.GetBlock(restored, 1)
+	if block == nil || !bytes.Equal(block.Header.Hash(), b1.Header.Hash()) {
+		t.Fatalf("Error retrieving block 1 of the restored chain")
+	}
+	if len(archiver.ArchivedChainIDs()) != 0 || len(jlf.ChainIDs()) != 1 {
+		t.Fatalf("Restored chain should no longer be archived")
+	}
+}
@@ -34,6 +34,19 @@ type Factory interface {
 	Close()
 }
 
+// Archiver is implemented by the Factories able to set aside the ledger of a
+// chain which is no longer serviced, and to bring it back later on
+type Archiver interface {
+	// Archive closes the ledger of the chain and moves it to the archive
+	Archive(chainID string) error
+
+	// Restore moves the archived ledger of the chain back and returns it
+	Restore(chainID string) (ReadWriter, error)
+
+	// ArchivedChainIDs returns the IDs of the chains in the archive
+	ArchivedChainIDs() []string
+}
+
 // Iterator is useful for a chain Reader to stream blocks as they are created
 type Iterator interface {
 	// Next blocks until there is a new block available, or returns an error if
@@ -17,6 +17,7 @@ limitations under the License.
 package ramledger
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/hyperledger/fabric/orderer/ledger"
@@ -24,9 +25,10 @@ import (
 )
 
 type ramLedgerFactory struct {
-	maxSize int
-	ledgers map[string]ledger.ReadWriter
-	mutex   sync.Mutex
+	maxSize  int
+	ledgers  map[string]ledger.ReadWriter
+	archived map[string]ledger.ReadWriter
+	mutex    sync.Mutex
 }
 
 // GetOrCreate gets an existing ledger (if it exists) or creates it if it does not
@@ -81,6 +83,55 @@ func (rlf *ramLedgerFactory) ChainIDs() []string {
 	return ids
 }
 
+// Archive sets the ledger of the chain aside
+func (rlf *ramLedgerFactory) Archive(chainID string) error {
+	rlf.mutex.Lock()
+	defer rlf.mutex.Unlock()
+
+	l, ok := rlf.ledgers[chainID]
+	if !ok {
+		return fmt.Errorf("No ledger for chain %s", chainID)
+	}
+	if _, ok := rlf.archived[chainID]; ok {
+		return fmt.Errorf("Chain %s is already archived", chainID)
+	}
+	rlf.archived[chainID] = l
+	delete(rlf.ledgers, chainID)
+	return nil
+}
+
+// Restore brings the archived ledger of the chain back
+func (rlf *ramLedgerFactory) Restore(chainID string) (ledger.ReadWriter, error) {
+	rlf.mutex.Lock()
+	defer rlf.mutex.Unlock()
+
+	l, ok := rlf.archived[chainID]
+	if !ok {
+		return nil, fmt.Errorf("Chain %s is not archived", chainID)
+	}
+	if _, ok := rlf.ledgers[chainID]; ok {
+		return nil, fmt.Errorf("Chain %s already has a ledger", chainID)
+	}
+	rlf.ledgers[chainID] = l
+	delete(rlf.archived, chainID)
+	return l, nil
+}
+
+// ArchivedChainIDs returns the IDs of the chains set aside
+func (rlf *ramLedgerFactory) ArchivedChainIDs() []string {
+	rlf.mutex.Lock()
+	defer rlf.mutex.Unlock()
+	ids := make([]string, len(rlf.archived))
+
+	i := 0
+	for key := range rlf.archived {
+		ids[i] = key
+		i++
+	}
+
+	return ids
+}
+
 // Close is a no-op for the RAM ledger
 func (rlf *ramLedgerFactory) Close() {
 	return // nothing to do
@@ -89,8 +140,9 @@ func (rlf *ramLedgerFactory) Close() {
 // New creates a new ledger factory
 func New(maxSize int) ledger.Factory {
 	rlf := &ramLedgerFactory{
-		maxSize: maxSize,
-		ledgers: make(map[string]ledger.ReadWriter),
+		maxSize:  maxSize,
+		ledgers:  make(map[string]ledger.ReadWriter),
+		archived: make(map[string]ledger.ReadWriter),
 	}
 
 	return rlf
@@ -17,6 +17,7 @@ limitations under the License.
 package config
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
@@ -64,6 +65,8 @@ type TopLevel struct {
 	FileLedger FileLedger
 	RAMLedger  RAMLedger
 	Kafka      Kafka
+	Raft       Raft
+	Admin      Admin
 	Genesis    Genesis
 	SbftLocal  SbftLocal
 }
@@ -125,6 +128,26 @@ type Retry struct {
 	Stop   time.Duration
 }
 
+// Raft contains configuration for the Raft-based orderer.
+type Raft struct {
+	ListenAddress     string
+	ListenPort        uint16
+	Address           string
+	DataDir           string
+	HeartbeatTimeout  time.Duration
+	ElectionTimeout   time.Duration
+	SnapshotThreshold uint64
+	SnapshotRetain    int
+}
+
+// Admin contains configuration for the orderer administration service.
+type Admin struct {
+	Enabled       bool
+	ListenAddress string
+	ListenPort    uint16
+	Policy        string
+}
+
 // Genesis is a deprecated structure which was used to put
 // values into the genesis block, but this is now handled elsewhere.
 // SBFT did not reference these values via the genesis block however
@@ -186,6 +209,20 @@ var defaults = TopLevel{
 			Enabled: false,
 		},
 	},
+	Raft: Raft{
+		ListenAddress:     "127.0.0.1",
+		ListenPort:        7090,
+		HeartbeatTimeout:  time.Second,
+		ElectionTimeout:   time.Second,
+		SnapshotThreshold: 1024,
+		SnapshotRetain:    2,
+	},
+	Admin: Admin{
+		Enabled:       false,
+		ListenAddress: "127.0.0.1",
+		ListenPort:    7055,
+		Policy:        "Admins",
+	},
 	Genesis: Genesis{
 		SbftShared: SbftShared{
 			N:                  1,
@@ -238,6 +275,9 @@ func (c *TopLevel) completeInitialization(configDir string) {
 		cf.TranslatePathInPlace(configDir, &c.General.TLS.Certificate)
 		cf.TranslatePathInPlace(configDir, &c.General.GenesisFile)
 		cf.TranslatePathInPlace(configDir, &c.General.LocalMSPDir)
+		if c.Raft.DataDir != "" {
+			cf.TranslatePathInPlace(configDir, &c.Raft.DataDir)
+		}
 	}()
 
 	for {
@@ -284,6 +324,36 @@ func (c *TopLevel) completeInitialization(configDir string) {
 		case c.Kafka.Retry.Stop == 0*time.Second:
 			logger.Infof("Kafka.Retry.Stop unset, setting to %v", defaults.Kafka.Retry.Stop)
 			c.Kafka.Retry.Stop = defaults.Kafka.Retry.Stop
+		case c.Raft.ListenAddress == "":
+			logger.Infof("Raft.ListenAddress unset, setting to %s", defaults.Raft.ListenAddress)
+			c.Raft.ListenAddress = defaults.Raft.ListenAddress
+		case c.Raft.ListenPort == 0:
+			logger.Infof("Raft.ListenPort unset, setting to %d", defaults.Raft.ListenPort)
+			c.Raft.ListenPort = defaults.Raft.ListenPort
+		case c.Raft.Address == "":
+			c.Raft.Address = fmt.Sprintf("%s:%d", c.Raft.ListenAddress, c.Raft.ListenPort)
+			logger.Infof("Raft.Address unset, setting to %s", c.Raft.Address)
+		case c.Raft.HeartbeatTimeout == 0*time.Second:
+			logger.Infof("Raft.HeartbeatTimeout unset, setting to %v", defaults.Raft.HeartbeatTimeout)
+			c.Raft.HeartbeatTimeout = defaults.Raft.HeartbeatTimeout
+		case c.Raft.ElectionTimeout == 0*time.Second:
+			logger.Infof("Raft.ElectionTimeout unset, setting to %v", defaults.Raft.ElectionTimeout)
+			c.Raft.ElectionTimeout = defaults.Raft.ElectionTimeout
+		case c.Raft.SnapshotThreshold == 0:
+			logger.Infof("Raft.SnapshotThreshold unset, setting to %d", defaults.Raft.SnapshotThreshold)
+			c.Raft.SnapshotThreshold = defaults.Raft.SnapshotThreshold
+		case c.Raft.SnapshotRetain == 0:
+			logger.Infof("Raft.SnapshotRetain unset, setting to %d", defaults.Raft.SnapshotRetain)
+			c.Raft.SnapshotRetain = defaults.Raft.SnapshotRetain
+		case c.Admin.Enabled && c.Admin.ListenAddress == "":
+			logger.Infof("Admin.ListenAddress unset, setting to %s", defaults.Admin.ListenAddress)
+			c.Admin.ListenAddress = defaults.Admin.ListenAddress
+		case c.Admin.Enabled && c.Admin.ListenPort == 0:
+			logger.Infof("Admin.ListenPort unset, setting to %d", defaults.Admin.ListenPort)
+			c.Admin.ListenPort = defaults.Admin.ListenPort
+		case c.Admin.Enabled && c.Admin.Policy == "":
+			logger.Infof("Admin.Policy unset, setting to %s", defaults.Admin.Policy)
+			c.Admin.Policy = defaults.Admin.Policy
 		default:
 			// A bit hacky, but its type makes it impossible to test for a nil value.
 			// This may be overwritten by the Kafka orderer upon instantiation.
@@ -29,10 +29,12 @@ import (
 	"github.com/hyperledger/fabric/common/configtx/tool/provisional"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/orderer/admin"
 	"github.com/hyperledger/fabric/orderer/common/bootstrap/file"
 	"github.com/hyperledger/fabric/orderer/kafka"
 	"github.com/hyperledger/fabric/orderer/localconfig"
 	"github.com/hyperledger/fabric/orderer/multichain"
+	"github.com/hyperledger/fabric/orderer/raft"
 	"github.com/hyperledger/fabric/orderer/sbft"
 	"github.com/hyperledger/fabric/orderer/solo"
 	cb "github.com/hyperledger/fabric/protos/common"
@@ -128,7 +130,7 @@ func main() {
 		logger.Panic("Failed to initialize local MSP:", err)
 	}
 
-	lf, _ := createLedgerFactory(conf)
+	lf, ld := createLedgerFactory(conf)
 
 	// Are we bootstrapping?
 	if len(lf.ChainIDs()) == 0 {
@@ -168,6 +170,7 @@ func main() {
 	consenters["solo"] = solo.New()
 	consenters["kafka"] = kafka.New(conf.Kafka.Version, conf.Kafka.Retry, conf.Kafka.TLS)
 	consenters["sbft"] = sbft.New(makeSbftConsensusConfig(conf), makeSbftStackConfig(conf))
+	consenters["raft"] = raft.New(conf.Raft, secureConfig, raftDataDir(conf, ld))
 
 	signer := localmsp.NewSigner()
 
@@ -178,7 +181,29 @@ func main() {
 		signer,
 	)
 
+	if conf.Admin.Enabled {
+		startAdminServer(conf, secureConfig, manager)
+	}
+
 	ab.RegisterAtomicBroadcastServer(grpcServer.Server(), server)
 	logger.Info("Beginning to serve requests")
 	grpcServer.Start()
 }
+
+// startAdminServer serves the administration service on a listener of its
+// own, so that it need not be reachable by the clients of the orderer
+func startAdminServer(conf *config.TopLevel, secureConfig comm.SecureServerConfig, manager multichain.Manager) {
+	principalGetter := mspmgmt.NewLocalMSPPrincipalGetter()
+	if _, err := principalGetter.Get(conf.Admin.Policy); err != nil {
+		logger.Panicf("Invalid Admin.Policy %s: %s", conf.Admin.Policy, err)
+	}
+
+	adminServer, err := comm.NewGRPCServer(fmt.Sprintf("%s:%d", conf.Admin.ListenAddress, conf.Admin.ListenPort), secureConfig)
+	if err != nil {
+		logger.Panic("Failed to create the admin GRPC server:", err)
+	}
+
+	ab.RegisterAdminServer(adminServer.Server(), admin.NewServer(manager, mspmgmt.GetLocalMSP(), principalGetter, conf.Admin.Policy))
+	logger.Infof("Serving admin requests on %s", adminServer.Address())
+	go adminServer.Start()
+}
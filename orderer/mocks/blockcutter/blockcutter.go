@@ -102,3 +102,8 @@ func (mbc *Receiver) Cut() ([]*cb.Envelope, []filter.Committer) {
 	mbc.CurBatch = nil
 	return res, noopCommitters(len(res))
 }
+
+// PendingCount returns the number of messages in CurBatch
+func (mbc *Receiver) PendingCount() int {
+	return len(mbc.CurBatch)
+}
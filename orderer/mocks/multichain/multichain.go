@@ -21,6 +21,7 @@ import (
 	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/filter"
+	"github.com/hyperledger/fabric/orderer/ledger"
 	mockblockcutter "github.com/hyperledger/fabric/orderer/mocks/blockcutter"
 	cb "github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -48,6 +49,9 @@ type ConsenterSupport struct {
 	// HeightVal is the value returned by Height()
 	HeightVal uint64
 
+	// ReaderVal is the value returned by Reader()
+	ReaderVal ledger.Reader
+
 	// NextBlockVal stores the block created by the most recent CreateNextBlock() call
 	NextBlockVal *cb.Block
 
@@ -104,6 +108,11 @@ func (mcs *ConsenterSupport) Height() uint64 {
 	return mcs.HeightVal
 }
 
+// Reader returns ReaderVal
+func (mcs *ConsenterSupport) Reader() ledger.Reader {
+	return mcs.ReaderVal
+}
+
 // Sign returns the bytes passed in
 func (mcs *ConsenterSupport) Sign(message []byte) ([]byte, error) {
 	return message, nil
@@ -17,6 +17,8 @@ limitations under the License.
 package multichain
 
 import (
+	"sync"
+
 	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/policies"
@@ -69,8 +71,9 @@ type ConsenterSupport interface {
 	SharedConfig() config.Orderer
 	CreateNextBlock(messages []*cb.Envelope) *cb.Block
 	WriteBlock(block *cb.Block, committers []filter.Committer, encodedMetadataValue []byte) *cb.Block
-	ChainID() string // ChainID returns the chain ID this specific consenter instance is associated with
-	Height() uint64  // Returns the number of blocks on the chain this specific consenter instance is associated with
+	ChainID() string       // ChainID returns the chain ID this specific consenter instance is associated with
+	Height() uint64        // Returns the number of blocks on the chain this specific consenter instance is associated with
+	Reader() ledger.Reader // Returns the chain Reader for the chain this specific consenter instance is associated with
 }
 
 // ChainSupport provides a wrapper for the resources backing a chain
@@ -81,8 +84,8 @@ type ChainSupport interface {
 	// PolicyManager returns the current policy manager as specified by the chain config
 	PolicyManager() policies.Manager
 
-	// Reader returns the chain Reader for the chain
-	Reader() ledger.Reader
+	// Sequence returns the current config sequence number of the chain
+	Sequence() uint64
 
 	broadcast.Support
 	ConsenterSupport
@@ -99,6 +102,11 @@ type chainSupport struct {
 	signer        crypto.LocalSigner
 	lastConfig    uint64
 	lastConfigSeq uint64
+
+	// writeLock serializes the block writes with halting the chain, after
+	// which no more blocks are written
+	writeLock sync.Mutex
+	halted    bool
 }
 
 func newChainSupport(
@@ -169,6 +177,15 @@ func (cs *chainSupport) start() {
 	cs.chain.Start()
 }
 
+// halt stops the chain and returns once no more blocks can be written to its
+// ledger
+func (cs *chainSupport) halt() {
+	cs.writeLock.Lock()
+	cs.halted = true
+	cs.writeLock.Unlock()
+	cs.chain.Halt()
+}
+
 func (cs *chainSupport) NewSignatureHeader() (*cb.SignatureHeader, error) {
 	return cs.signer.NewSignatureHeader()
 }
@@ -243,6 +260,13 @@ func (cs *chainSupport) addLastConfigSignature(block *cb.Block) {
 }
 
 func (cs *chainSupport) WriteBlock(block *cb.Block, committers []filter.Committer, encodedMetadataValue []byte) *cb.Block {
+	cs.writeLock.Lock()
+	defer cs.writeLock.Unlock()
+	if cs.halted {
+		logger.Warningf("[channel: %s] Dropping block %d written after the chain was halted", cs.ChainID(), block.Header.Number)
+		return block
+	}
+
 	for _, committer := range committers {
 		committer.Commit()
 	}
@@ -18,6 +18,8 @@ package multichain
 
 import (
 	"fmt"
+	"sort"
+	"sync"
 
 	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/configtx"
@@ -49,6 +51,20 @@ type Manager interface {
 	// NewChannelConfig returns a bare bones configuration ready for channel
 	// creation request to be applied on top of it
 	NewChannelConfig(envConfigUpdate *cb.Envelope) (configtxapi.Manager, error)
+
+	// RemoveChannel halts the chain of a channel other than the system
+	// channel, stops servicing it and moves its ledger to the archive
+	RemoveChannel(chainID string) error
+
+	// RestoreChannel services again a channel from its archived ledger
+	RestoreChannel(chainID string) error
+
+	// ChannelIDs returns the sorted IDs of the channels being serviced
+	ChannelIDs() []string
+
+	// ArchivedChannelIDs returns the sorted IDs of the channels whose ledger
+	// is in the archive
+	ArchivedChannelIDs() []string
 }
 
 type configResources struct {
@@ -65,6 +81,9 @@ type ledgerResources struct {
 }
 
 type multiLedger struct {
+	// lock serializes the changes to the chains map, which is copied and
+	// replaced to allow concurrent reads from broadcast/deliver
+	lock            sync.Mutex
 	chains          map[string]*chainSupport
 	consenters      map[string]Consenter
 	ledgerFactory   ledger.Factory
@@ -152,6 +171,28 @@ func (ml *multiLedger) GetChain(chainID string) (ChainSupport, bool) {
 	return cs, ok
 }
 
+// ChannelIDs returns the sorted IDs of the channels being serviced
+func (ml *multiLedger) ChannelIDs() []string {
+	chains := ml.chains
+	chainIDs := make([]string, 0, len(chains))
+	for chainID := range chains {
+		chainIDs = append(chainIDs, chainID)
+	}
+	sort.Strings(chainIDs)
+	return chainIDs
+}
+
+// ArchivedChannelIDs returns the sorted IDs of the channels whose ledger is in the archive
+func (ml *multiLedger) ArchivedChannelIDs() []string {
+	archiver, ok := ml.ledgerFactory.(ledger.Archiver)
+	if !ok {
+		return nil
+	}
+	chainIDs := archiver.ArchivedChainIDs()
+	sort.Strings(chainIDs)
+	return chainIDs
+}
+
 func (ml *multiLedger) newLedgerResources(configTx *cb.Envelope) *ledgerResources {
 	initializer := configtx.NewInitializer()
 	configManager, err := configtx.NewManagerImpl(configTx, initializer, nil)
@@ -173,6 +214,9 @@ func (ml *multiLedger) newLedgerResources(configTx *cb.Envelope) *ledgerResource
 }
 
 func (ml *multiLedger) newChain(configtx *cb.Envelope) {
+	ml.lock.Lock()
+	defer ml.lock.Unlock()
+
 	ledgerResources := ml.newLedgerResources(configtx)
 	ledgerResources.ledger.Append(ledger.CreateNextBlock(ledgerResources.ledger, []*cb.Envelope{configtx}))
 
@@ -193,6 +237,115 @@ func (ml *multiLedger) newChain(configtx *cb.Envelope) {
 	ml.chains = newChains
 }
 
+func (ml *multiLedger) RemoveChannel(chainID string) error {
+	ml.lock.Lock()
+	defer ml.lock.Unlock()
+
+	if chainID == ml.systemChannelID {
+		return fmt.Errorf("The system channel %s cannot be removed", chainID)
+	}
+	cs, ok := ml.chains[chainID]
+	if !ok {
+		return fmt.Errorf("Channel %s does not exist", chainID)
+	}
+	archiver, ok := ml.ledgerFactory.(ledger.Archiver)
+	if !ok {
+		return fmt.Errorf("The ledger type does not support archiving channels")
+	}
+	if ml.isArchived(chainID) {
+		return fmt.Errorf("Channel %s already has an archived ledger", chainID)
+	}
+
+	newChains := make(map[string]*chainSupport)
+	for key, value := range ml.chains {
+		if key != chainID {
+			newChains[key] = value
+		}
+	}
+	ml.chains = newChains
+
+	logger.Infof("Halting and archiving channel %s", chainID)
+	cs.halt()
+	err := archiver.Archive(chainID)
+	if err == nil {
+		return nil
+	}
+
+	// The chain is halted and its ledger may have been closed, so service the
+	// channel again from a newly opened ledger
+	logger.Errorf("Failed to archive channel %s, starting it again: %s", chainID, err)
+	rl, openErr := ml.ledgerFactory.GetOrCreate(chainID)
+	if openErr != nil {
+		return fmt.Errorf("Channel %s is halted but not archived: %s, and its ledger could not be opened again: %s", chainID, err, openErr)
+	}
+	if startErr := ml.startChain(chainID, rl); startErr != nil {
+		return fmt.Errorf("Channel %s is halted but not archived: %s, and it could not be started again: %s", chainID, err, startErr)
+	}
+	return fmt.Errorf("Failed to archive channel %s, it is serviced again: %s", chainID, err)
+}
+
+func (ml *multiLedger) RestoreChannel(chainID string) error {
+	ml.lock.Lock()
+	defer ml.lock.Unlock()
+
+	if _, ok := ml.chains[chainID]; ok {
+		return fmt.Errorf("Channel %s already exists", chainID)
+	}
+	archiver, ok := ml.ledgerFactory.(ledger.Archiver)
+	if !ok {
+		return fmt.Errorf("The ledger type does not support archiving channels")
+	}
+
+	rl, err := archiver.Restore(chainID)
+	if err != nil {
+		return err
+	}
+	if err := ml.startChain(chainID, rl); err != nil {
+		return err
+	}
+	logger.Infof("Restored and started channel %s", chainID)
+	return nil
+}
+
+// startChain starts servicing a channel from its ledger, the caller must hold
+// the lock of the manager
+func (ml *multiLedger) startChain(chainID string, rl ledger.ReadWriter) error {
+	configTx := getConfigTx(rl)
+	if configTx == nil {
+		return fmt.Errorf("Could not find config transaction for channel %s", chainID)
+	}
+	ledgerResources := ml.newLedgerResources(configTx)
+	if ledgerResources.ChainID() != chainID {
+		return fmt.Errorf("Ledger of channel %s holds the config of channel %s", chainID, ledgerResources.ChainID())
+	}
+
+	newChains := make(map[string]*chainSupport)
+	for key, value := range ml.chains {
+		newChains[key] = value
+	}
+
+	cs := newChainSupport(createStandardFilters(ledgerResources), ledgerResources, ml.consenters, ml.signer)
+	newChains[chainID] = cs
+	cs.start()
+
+	ml.chains = newChains
+	return nil
+}
+
+// isArchived returns whether the ledger of a channel is in the archive
+func (ml *multiLedger) isArchived(chainID string) bool {
+	archiver, ok := ml.ledgerFactory.(ledger.Archiver)
+	if !ok {
+		return false
+	}
+	for _, archivedID := range archiver.ArchivedChainIDs() {
+		if archivedID == chainID {
+			return true
+		}
+	}
+	return false
+}
+
 func (ml *multiLedger) channelsCount() int {
 	return len(ml.chains)
 }
@@ -213,6 +366,10 @@ func (ml *multiLedger) NewChannelConfig(envConfigUpdate *cb.Envelope) (configtxa
 		return nil, fmt.Errorf("Failing initial channel config creation because of config update unmarshaling error: %s", err)
 	}
 
+	if ml.isArchived(configUpdate.ChannelId) {
+		return nil, fmt.Errorf("Channel %s is archived and must be restored rather than created", configUpdate.ChannelId)
+	}
+
 	if configUpdate.WriteSet == nil {
 		return nil, fmt.Errorf("Config update has an empty writeset")
 	}
@@ -323,3 +323,130 @@ func TestNewChain(t *testing.T) {
 		t.Fatalf("Block 1 not produced after timeout on new chain")
 	}
 }
+
+// createChannel creates a channel through the system channel and waits for it
+func createChannel(t *testing.T, manager Manager, rl ledger.Reader, channelID string) {
+	envConfigUpdate, err := configtx.MakeChainCreationTransaction(channelID, genesisconfig.SampleConsortiumName, mockSigningIdentity)
+	assert.NoError(t, err, "Constructing chain creation tx")
+	cm, err := manager.NewChannelConfig(envConfigUpdate)
+	assert.NoError(t, err, "Constructing initial channel config")
+	configEnv, err := cm.ProposeConfigUpdate(envConfigUpdate)
+	assert.NoError(t, err, "Proposing initial update")
+	ingressTx, err := utils.CreateSignedEnvelope(cb.HeaderType_CONFIG, channelID, mockCrypto(), configEnv, msgVersion, epoch)
+	assert.NoError(t, err, "Creating ingresstx")
+
+	systemChain, _ := manager.GetChain(manager.SystemChannelID())
+	it, _ := rl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: rl.Height()}}})
+	systemChain.Enqueue(wrapConfigTx(ingressTx))
+	select {
+	case <-it.ReadyChan():
+	case <-time.After(time.Second):
+		t.Fatalf("Channel %s not created after timeout", channelID)
+	}
+}
+
+func TestRemoveAndRestoreChannel(t *testing.T) {
+	lf, rl := NewRAMLedgerAndFactory(10)
+
+	consenters := make(map[string]Consenter)
+	consenters[conf.Orderer.OrdererType] = &mockConsenter{}
+
+	manager := NewManagerImpl(lf, consenters, mockCrypto())
+	channelID := "TestRemoveChannel"
+	createChannel(t, manager, rl, channelID)
+	_, ok := manager.GetChain(channelID)
+	assert.True(t, ok, "Should have created the channel")
+
+	assert.Error(t, manager.RemoveChannel(manager.SystemChannelID()), "The system channel cannot be removed")
+	assert.Error(t, manager.RemoveChannel("bogus"), "Unknown channels cannot be removed")
+	assert.Error(t, manager.RestoreChannel(channelID), "Serviced channels cannot be restored")
+
+	assert.NoError(t, manager.RemoveChannel(channelID))
+	_, ok = manager.GetChain(channelID)
+	assert.False(t, ok, "Removed channel should no longer be serviced")
+	assert.NotContains(t, lf.ChainIDs(), channelID)
+	assert.Equal(t, []string{channelID}, lf.(ledger.Archiver).ArchivedChainIDs())
+	assert.Equal(t, []string{manager.SystemChannelID()}, manager.ChannelIDs())
+	assert.Equal(t, []string{channelID}, manager.ArchivedChannelIDs())
+
+	envConfigUpdate, err := configtx.MakeChainCreationTransaction(channelID, genesisconfig.SampleConsortiumName, mockSigningIdentity)
+	assert.NoError(t, err, "Constructing chain creation tx")
+	_, err = manager.NewChannelConfig(envConfigUpdate)
+	assert.Error(t, err, "Archived channels cannot be created again")
+
+	assert.Error(t, manager.RestoreChannel("bogus"), "Channels which were not archived cannot be restored")
+	assert.NoError(t, manager.RestoreChannel(channelID))
+	cs, ok := manager.GetChain(channelID)
+	assert.True(t, ok, "Restored channel should be serviced")
+	assert.Equal(t, uint64(1), cs.Height())
+	assert.Empty(t, lf.(ledger.Archiver).ArchivedChainIDs())
+	assert.Len(t, manager.ChannelIDs(), 2)
+	assert.Empty(t, manager.ArchivedChannelIDs())
+
+	cs.Enqueue(makeNormalTx(channelID, 0))
+	for i := 1; i < int(conf.Orderer.BatchSize.MaxMessageCount); i++ {
+		cs.Enqueue(makeNormalTx(channelID, i))
+	}
+	it, _ := cs.Reader().Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: 1}}})
+	select {
+	case <-it.ReadyChan():
+	case <-time.After(time.Second):
+		t.Fatalf("Block 1 not produced after timeout on restored channel")
+	}
+}
+
+// failingArchiver is a ledger factory whose ledgers cannot be archived
+type failingArchiver struct {
+	ledger.Factory
+	ledger.Archiver
+}
+
+func (fa *failingArchiver) Archive(chainID string) error {
+	return errors.New("archive failure")
+}
+
+func TestRemoveChannelArchiveFailure(t *testing.T) {
+	ramLF, rl := NewRAMLedgerAndFactory(10)
+	lf := &failingArchiver{Factory: ramLF, Archiver: ramLF.(ledger.Archiver)}
+
+	consenters := make(map[string]Consenter)
+	consenters[conf.Orderer.OrdererType] = &mockConsenter{}
+
+	manager := NewManagerImpl(lf, consenters, mockCrypto())
+	channelID := "TestRemoveChannel"
+	createChannel(t, manager, rl, channelID)
+	before, ok := manager.GetChain(channelID)
+	assert.True(t, ok, "Should have created the channel")
+
+	assert.Error(t, manager.RemoveChannel(channelID))
+	cs, ok := manager.GetChain(channelID)
+	assert.True(t, ok, "Channel which could not be archived should be serviced again")
+	assert.False(t, before == cs, "Channel should be serviced by a new chain")
+	assert.Equal(t, uint64(1), cs.Height())
+	assert.Empty(t, manager.ArchivedChannelIDs())
+
+	cs.Enqueue(makeNormalTx(channelID, 0))
+	for i := 1; i < int(conf.Orderer.BatchSize.MaxMessageCount); i++ {
+		cs.Enqueue(makeNormalTx(channelID, i))
+	}
+	it, _ := cs.Reader().Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: 1}}})
+	select {
+	case <-it.ReadyChan():
+	case <-time.After(time.Second):
+		t.Fatalf("Block 1 not produced after timeout on channel serviced again")
+	}
+}
+
+func TestRemovedChainWritesNoBlock(t *testing.T) {
+	lf, rl := NewRAMLedgerAndFactory(10)
+
+	consenters := make(map[string]Consenter)
+	consenters[conf.Orderer.OrdererType] = &mockConsenter{}
+
+	manager := NewManagerImpl(lf, consenters, mockCrypto())
+	cs, _ := manager.GetChain(manager.SystemChannelID())
+	cs.(*chainSupport).halt()
+
+	cs.WriteBlock(cs.CreateNextBlock([]*cb.Envelope{makeNormalTx(provisional.TestChainID, 0)}), nil, nil)
+	assert.Equal(t, uint64(1), rl.Height(), "No block should be written once the chain is halted")
+}
@@ -0,0 +1,720 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package raft
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/raft"
+	"github.com/hyperledger/fabric/orderer/common/filter"
+	config "github.com/hyperledger/fabric/orderer/localconfig"
+	"github.com/hyperledger/fabric/orderer/multichain"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+const (
+	dialTimeout          = 5 * time.Second
+	transportTimeout     = 10 * time.Second
+	forwardTimeout       = 10 * time.Second
+	forwardRetryInterval = 100 * time.Millisecond
+	pullRetryInterval    = time.Second
+)
+
+// proposal remembers the committers of a block this consenter proposed as the
+// leader, so that they need not be computed again once the block is committed
+type proposal struct {
+	dataHash   []byte
+	committers []filter.Committer
+}
+
+type chain struct {
+	support      multichain.ConsenterSupport
+	conf         config.Raft
+	cluster      *cluster
+	dataDir      string
+	batchTimeout time.Duration
+
+	store      *logStore
+	stream     *streamLayer
+	transport  *raft.NetworkTransport
+	raft       *raft.Raft
+	leaderChan chan bool
+
+	sendChan     chan *cb.Envelope
+	reconfigChan chan struct{}
+	exitChan     chan struct{}
+
+	// consenters is the consenter set of the last block written to the ledger
+	consenters []string
+
+	lock      sync.Mutex
+	proposals map[uint64]*proposal
+
+	forwardLock sync.Mutex
+	forwardAddr raft.ServerAddress
+	forwardConn net.Conn
+}
+
+func newChain(support multichain.ConsenterSupport, conf config.Raft, cl *cluster, dataDir string) (*chain, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("Failed to create the Raft data dir: %s", err)
+	}
+
+	return &chain{
+		support:      support,
+		conf:         conf,
+		cluster:      cl,
+		dataDir:      dataDir,
+		batchTimeout: support.SharedConfig().BatchTimeout(),
+		store:        newLogStore(filepath.Join(dataDir, "wal")),
+		leaderChan:   make(chan bool, 1),
+		sendChan:     make(chan *cb.Envelope),
+		reconfigChan: make(chan struct{}, 1),
+		exitChan:     make(chan struct{}),
+		consenters:   support.SharedConfig().RaftConsenters(),
+		proposals:    make(map[uint64]*proposal),
+	}, nil
+}
+
+func (ch *chain) Start() {
+	if err := ch.startRaft(); err != nil {
+		logger.Panicf("[channel: %s] Failed to start Raft: %s", ch.support.ChainID(), err)
+	}
+	go ch.main()
+}
+
+func (ch *chain) startRaft() error {
+	chainID := ch.support.ChainID()
+	hlog := hclog.New(&hclog.LoggerOptions{
+		Name:   "raft",
+		Level:  hclog.Debug,
+		Output: &logWriter{chainID: chainID},
+	})
+	stdlog := hlog.StandardLogger(&hclog.StandardLoggerOptions{InferLevels: true})
+
+	rconf := raft.DefaultConfig()
+	rconf.LocalID = raft.ServerID(ch.conf.Address)
+	rconf.HeartbeatTimeout = ch.conf.HeartbeatTimeout
+	rconf.ElectionTimeout = ch.conf.ElectionTimeout
+	if rconf.LeaderLeaseTimeout > rconf.HeartbeatTimeout {
+		rconf.LeaderLeaseTimeout = rconf.HeartbeatTimeout
+	}
+	rconf.SnapshotThreshold = ch.conf.SnapshotThreshold
+	rconf.TrailingLogs = ch.conf.SnapshotThreshold
+	rconf.NotifyCh = ch.leaderChan
+	rconf.Logger = hlog
+
+	snapshots, err := raft.NewFileSnapshotStoreWithLogger(ch.dataDir, ch.conf.SnapshotRetain, stdlog)
+	if err != nil {
+		return err
+	}
+
+	ch.stream = newStreamLayer(ch.cluster, chainID)
+	ch.cluster.register(chainID, ch)
+	ch.transport = raft.NewNetworkTransportWithLogger(ch.stream, 3, transportTimeout, stdlog)
+
+	existing, err := raft.HasExistingState(ch.store, ch.store, snapshots)
+	if err != nil {
+		return err
+	}
+	if !existing {
+		// Every consenter bootstraps the channel with the same configuration
+		configuration := raft.Configuration{}
+		for _, consenter := range ch.support.SharedConfig().RaftConsenters() {
+			configuration.Servers = append(configuration.Servers, raft.Server{
+				ID:      raft.ServerID(consenter),
+				Address: raft.ServerAddress(consenter),
+			})
+		}
+		if err := raft.BootstrapCluster(rconf, ch.store, ch.store, snapshots, ch.transport, configuration); err != nil {
+			return err
+		}
+	}
+
+	ch.raft, err = raft.NewRaft(rconf, &fsm{ch: ch}, ch.store, ch.store, snapshots, ch.transport)
+	return err
+}
+
+func (ch *chain) Halt() {
+	select {
+	case <-ch.exitChan:
+		// Allow multiple halts without panic
+		return
+	default:
+		close(ch.exitChan)
+	}
+
+	if ch.raft != nil {
+		if err := ch.raft.Shutdown().Error(); err != nil {
+			logger.Warningf("[channel: %s] Error shutting down Raft: %s", ch.support.ChainID(), err)
+		}
+	}
+	if ch.transport != nil {
+		ch.transport.Close()
+	}
+	ch.cluster.unregister(ch.support.ChainID())
+	ch.closeForward()
+	ch.store.close()
+}
+
+// Enqueue accepts a message and returns true on acceptance, or false on shutdown
+// or if it could not be forwarded to the leader
+func (ch *chain) Enqueue(env *cb.Envelope) bool {
+	if ch.raft.State() != raft.Leader {
+		return ch.forward(env)
+	}
+	select {
+	case ch.sendChan <- env:
+		return true
+	case <-ch.exitChan:
+		return false
+	}
+}
+
+func (ch *chain) main() {
+	var timer <-chan time.Time
+	isLeader := false
+
+	for {
+		select {
+		case leader := <-ch.leaderChan:
+			if leader {
+				// Make sure the blocks replicated by the previous leaders are
+				// all in the ledger before cutting new ones
+				for ch.raft.State() == raft.Leader {
+					err := ch.raft.Barrier(0).Error()
+					if err == nil {
+						logger.Infof("[channel: %s] Elected leader", ch.support.ChainID())
+						isLeader = true
+						// The previous leader may not have applied the last change of the consenter set
+						ch.reconcileConsenters()
+						break
+					}
+					logger.Warningf("[channel: %s] Failed to catch up as the new leader: %s", ch.support.ChainID(), err)
+				}
+				continue
+			}
+
+			if isLeader {
+				logger.Infof("[channel: %s] Lost leadership", ch.support.ChainID())
+			}
+			isLeader = false
+			timer = nil
+			if batch, _ := ch.support.BlockCutter().Cut(); len(batch) > 0 {
+				go ch.forwardAll(batch)
+			}
+		case msg := <-ch.sendChan:
+			if !isLeader {
+				go ch.forward(msg)
+				continue
+			}
+			batches, committers, ok := ch.support.BlockCutter().Ordered(msg)
+			if ok && len(batches) == 0 && timer == nil {
+				timer = time.After(ch.batchTimeout)
+				continue
+			}
+			for i, batch := range batches {
+				ch.propose(batch, committers[i])
+			}
+			if len(batches) > 0 {
+				timer = nil
+			}
+		case <-timer:
+			//clear the timer
+			timer = nil
+
+			batch, committers := ch.support.BlockCutter().Cut()
+			if len(batch) == 0 {
+				logger.Warningf("Batch timer expired with no pending requests, this might indicate a bug")
+				continue
+			}
+			logger.Debugf("Batch timer expired, creating block")
+			ch.propose(batch, committers)
+		case <-ch.reconfigChan:
+			if isLeader {
+				ch.reconcileConsenters()
+			}
+		case <-ch.exitChan:
+			logger.Debugf("Exiting")
+			return
+		}
+	}
+}
+
+// propose replicates the next block and returns once it is in the ledger,
+// or once it failed to be replicated. The messages of a block that failed to
+// be replicated, e.g. because this consenter lost its leadership meanwhile, are
+// enqueued again unless the block made it to the ledger nonetheless. Note that
+// a block whose replication failed may still be committed by the next leader,
+// in which case its messages are ordered twice; the peers invalidate the
+// transactions ordered twice as duplicates
+func (ch *chain) propose(batch []*cb.Envelope, committers []filter.Committer) {
+	block := ch.support.CreateNextBlock(batch)
+
+	ch.lock.Lock()
+	ch.proposals[block.Header.Number] = &proposal{dataHash: block.Header.DataHash, committers: committers}
+	ch.lock.Unlock()
+
+	if err := ch.raft.Apply(utils.MarshalOrPanic(block), 0).Error(); err != nil {
+		ch.lock.Lock()
+		delete(ch.proposals, block.Header.Number)
+		ch.lock.Unlock()
+		if ch.isCommitted(block) {
+			return
+		}
+		logger.Warningf("[channel: %s] Failed to replicate block %d, enqueuing its %d message(s) again: %s",
+			ch.support.ChainID(), block.Header.Number, len(batch), err)
+		go ch.forwardAll(batch)
+	}
+}
+
+// isCommitted returns true if the ledger holds the given block
+func (ch *chain) isCommitted(block *cb.Block) bool {
+	if ch.support.Height() <= block.Header.Number {
+		return false
+	}
+	committed, err := ch.getBlock(block.Header.Number)
+	return err == nil && bytes.Equal(committed.Header.DataHash, block.Header.DataHash)
+}
+
+// reconcileConsenters applies the changes of the consenter set of the channel
+// to the Raft configuration, adding the consenters before removing any so that
+// the cluster keeps its quorum. Only the leader changes the Raft configuration
+func (ch *chain) reconcileConsenters() {
+	consenters := ch.support.SharedConfig().RaftConsenters()
+	if len(consenters) == 0 {
+		logger.Warningf("[channel: %s] Ignoring an empty consenter set", ch.support.ChainID())
+		return
+	}
+	future := ch.raft.GetConfiguration()
+	if err := future.Error(); err != nil {
+		logger.Warningf("[channel: %s] Failed to read the Raft configuration: %s", ch.support.ChainID(), err)
+		return
+	}
+	current := make(map[raft.ServerID]bool)
+	for _, server := range future.Configuration().Servers {
+		current[server.ID] = true
+	}
+
+	wanted := make(map[raft.ServerID]bool)
+	for _, consenter := range consenters {
+		id := raft.ServerID(consenter)
+		wanted[id] = true
+		if current[id] {
+			continue
+		}
+		logger.Infof("[channel: %s] Adding consenter %s", ch.support.ChainID(), consenter)
+		if err := ch.raft.AddVoter(id, raft.ServerAddress(consenter), 0, 0).Error(); err != nil {
+			logger.Warningf("[channel: %s] Failed to add consenter %s: %s", ch.support.ChainID(), consenter, err)
+			return
+		}
+	}
+	for id := range current {
+		if wanted[id] {
+			continue
+		}
+		logger.Infof("[channel: %s] Removing consenter %s", ch.support.ChainID(), id)
+		if err := ch.raft.RemoveServer(id, 0, 0).Error(); err != nil {
+			logger.Warningf("[channel: %s] Failed to remove consenter %s: %s", ch.support.ChainID(), id, err)
+			return
+		}
+	}
+}
+
+// commit writes a replicated block to the ledger, blocks the ledger already
+// holds are skipped as the Raft log is replayed from the last snapshot on
+// restart
+func (ch *chain) commit(block *cb.Block) {
+	height := ch.support.Height()
+	if block.Header.Number < height {
+		logger.Debugf("[channel: %s] Block %d is already in the ledger", ch.support.ChainID(), block.Header.Number)
+		return
+	}
+	if block.Header.Number > height {
+		logger.Panicf("[channel: %s] Cannot commit block %d, the ledger height is %d", ch.support.ChainID(), block.Header.Number, height)
+	}
+	ch.support.WriteBlock(block, ch.committersFor(block), nil)
+
+	// A config block may change the consenter set, which the leader applies to
+	// the Raft configuration
+	if consenters := ch.support.SharedConfig().RaftConsenters(); !sameConsenters(consenters, ch.consenters) {
+		ch.consenters = consenters
+		select {
+		case ch.reconfigChan <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func sameConsenters(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// committersFor returns the committers of a block, they are recomputed by
+// passing its messages through the block cutter unless this consenter
+// proposed the block
+func (ch *chain) committersFor(block *cb.Block) []filter.Committer {
+	ch.lock.Lock()
+	p, ok := ch.proposals[block.Header.Number]
+	for number := range ch.proposals {
+		if number <= block.Header.Number {
+			delete(ch.proposals, number)
+		}
+	}
+	ch.lock.Unlock()
+	if ok && bytes.Equal(p.dataHash, block.Header.DataHash) {
+		return p.committers
+	}
+
+	var committers []filter.Committer
+	cutter := ch.support.BlockCutter()
+	for i, data := range block.Data.Data {
+		env, err := utils.UnmarshalEnvelope(data)
+		if err != nil {
+			logger.Warningf("[channel: %s] Message %d of block %d is not an envelope: %s", ch.support.ChainID(), i, block.Header.Number, err)
+			continue
+		}
+		_, batchCommitters, ok := cutter.Ordered(env)
+		if !ok {
+			logger.Warningf("[channel: %s] Message %d of block %d was rejected locally", ch.support.ChainID(), i, block.Header.Number)
+			continue
+		}
+		for _, c := range batchCommitters {
+			committers = append(committers, c...)
+		}
+	}
+	_, pending := cutter.Cut()
+	return append(committers, pending...)
+}
+
+// forward relays a message to the leader, waiting for one to be elected if needed
+func (ch *chain) forward(env *cb.Envelope) bool {
+	data := utils.MarshalOrPanic(env)
+	deadline := time.Now().Add(forwardTimeout)
+	for {
+		leader := ch.raft.Leader()
+		switch leader {
+		case "":
+		case raft.ServerAddress(ch.conf.Address):
+			select {
+			case ch.sendChan <- env:
+				return true
+			case <-ch.exitChan:
+				return false
+			}
+		default:
+			accepted, err := ch.sendForward(leader, data)
+			if err == nil {
+				return accepted
+			}
+			logger.Warningf("[channel: %s] Failed to forward message to leader %s: %s", ch.support.ChainID(), leader, err)
+		}
+
+		if time.Now().After(deadline) {
+			logger.Warningf("[channel: %s] Dropping message, no leader could be reached", ch.support.ChainID())
+			return false
+		}
+		select {
+		case <-time.After(forwardRetryInterval):
+		case <-ch.exitChan:
+			return false
+		}
+	}
+}
+
+func (ch *chain) forwardAll(batch []*cb.Envelope) {
+	for _, env := range batch {
+		ch.forward(env)
+	}
+}
+
+// sendForward sends a message on the forwarding stream to the leader and
+// returns whether the leader accepted it
+func (ch *chain) sendForward(leader raft.ServerAddress, data []byte) (bool, error) {
+	ch.forwardLock.Lock()
+	defer ch.forwardLock.Unlock()
+
+	if ch.forwardConn != nil && ch.forwardAddr != leader {
+		ch.forwardConn.Close()
+		ch.forwardConn = nil
+	}
+	if ch.forwardConn == nil {
+		conn, err := ch.cluster.dial(string(leader), streamForward, ch.support.ChainID(), dialTimeout)
+		if err != nil {
+			return false, err
+		}
+		ch.forwardAddr, ch.forwardConn = leader, conn
+	}
+
+	conn := ch.forwardConn
+	conn.SetDeadline(time.Now().Add(forwardTimeout))
+	ack := make([]byte, 1)
+	err := writeFrame(conn, data)
+	if err == nil {
+		_, err = io.ReadFull(conn, ack)
+	}
+	if err != nil {
+		conn.Close()
+		ch.forwardConn = nil
+		return false, err
+	}
+	conn.SetDeadline(time.Time{})
+	return ack[0] == 1, nil
+}
+
+func (ch *chain) closeForward() {
+	ch.forwardLock.Lock()
+	defer ch.forwardLock.Unlock()
+	if ch.forwardConn != nil {
+		ch.forwardConn.Close()
+		ch.forwardConn = nil
+	}
+}
+
+// handleStream serves a stream opened by another consenter of the channel. When
+// TLS is enabled, the streams of the dialers whose certificate is not issued to
+// the host of a consenter of the channel are rejected
+func (ch *chain) handleStream(kind byte, conn net.Conn, cert *x509.Certificate) {
+	if cert != nil {
+		if _, ok := ch.consenterOf(cert); !ok {
+			logger.Warningf("[channel: %s] Rejecting stream from %s, its certificate does not belong to a consenter of the channel", ch.support.ChainID(), conn.RemoteAddr())
+			conn.Close()
+			return
+		}
+	}
+	switch kind {
+	case streamRaft:
+		ch.stream.push(conn)
+	case streamForward:
+		ch.serveForward(conn)
+	case streamPull:
+		ch.servePull(conn)
+	default:
+		logger.Warningf("[channel: %s] Rejecting stream of unknown kind %d from %s", ch.support.ChainID(), kind, conn.RemoteAddr())
+		conn.Close()
+	}
+}
+
+// consenterOf returns the consenter of the channel the certificate is issued to,
+// i.e. the consenter whose host is one of the subject alternative names of the
+// certificate
+func (ch *chain) consenterOf(cert *x509.Certificate) (string, bool) {
+	for _, consenter := range ch.support.SharedConfig().RaftConsenters() {
+		host, _, err := net.SplitHostPort(consenter)
+		if err != nil {
+			continue
+		}
+		if cert.VerifyHostname(host) == nil {
+			return consenter, true
+		}
+	}
+	return "", false
+}
+
+// serveForward enqueues the messages forwarded by the followers and
+// acknowledges each of them
+func (ch *chain) serveForward(conn net.Conn) {
+	defer conn.Close()
+	for {
+		data, err := readFrame(conn)
+		if err != nil {
+			if err != io.EOF {
+				logger.Debugf("[channel: %s] Closing forwarding stream from %s: %s", ch.support.ChainID(), conn.RemoteAddr(), err)
+			}
+			return
+		}
+
+		ack := []byte{0}
+		env, err := utils.UnmarshalEnvelope(data)
+		if err != nil {
+			logger.Warningf("[channel: %s] Received a malformed message from %s: %s", ch.support.ChainID(), conn.RemoteAddr(), err)
+		} else if ch.Enqueue(env) {
+			ack[0] = 1
+		}
+		if _, err := conn.Write(ack); err != nil {
+			return
+		}
+	}
+}
+
+// servePull streams the blocks of the ledger from the requested number up to
+// the current height, followed by an empty frame
+func (ch *chain) servePull(conn net.Conn) {
+	defer conn.Close()
+	req, err := readFrame(conn)
+	if err != nil || len(req) != 8 {
+		logger.Warningf("[channel: %s] Received a malformed pull request from %s", ch.support.ChainID(), conn.RemoteAddr())
+		return
+	}
+
+	start := binary.BigEndian.Uint64(req)
+	height := ch.support.Height()
+	if start < height {
+		itr, _ := ch.support.Reader().Iterator(seekTo(start))
+		for number := start; number < height; number++ {
+			block, status := itr.Next()
+			if status != cb.Status_SUCCESS {
+				logger.Warningf("[channel: %s] Failed to read block %d: %s", ch.support.ChainID(), number, status)
+				return
+			}
+			if err := writeFrame(conn, utils.MarshalOrPanic(block)); err != nil {
+				return
+			}
+		}
+	}
+	writeFrame(conn, nil)
+}
+
+// catchUp pulls the blocks up to height from the other consenters, the last
+// one must have the given header hash
+func (ch *chain) catchUp(height uint64, hash []byte) error {
+	if ch.support.Height() >= height {
+		return nil
+	}
+	logger.Infof("[channel: %s] Pulling blocks %d to %d from the other consenters", ch.support.ChainID(), ch.support.Height(), height-1)
+
+	for {
+		for _, consenter := range ch.support.SharedConfig().RaftConsenters() {
+			if consenter == ch.conf.Address {
+				continue
+			}
+			err := ch.pull(consenter, height, hash)
+			if err == nil {
+				return nil
+			}
+			logger.Warningf("[channel: %s] Failed to pull blocks from %s: %s", ch.support.ChainID(), consenter, err)
+		}
+
+		select {
+		case <-time.After(pullRetryInterval):
+		case <-ch.exitChan:
+			return fmt.Errorf("Halted while pulling blocks")
+		}
+	}
+}
+
+func (ch *chain) pull(consenter string, height uint64, hash []byte) error {
+	conn, err := ch.cluster.dial(consenter, streamPull, ch.support.ChainID(), dialTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	start := ch.support.Height()
+	req := make([]byte, 8)
+	binary.BigEndian.PutUint64(req, start)
+	if err := writeFrame(conn, req); err != nil {
+		return err
+	}
+
+	prev, err := ch.getBlock(start - 1)
+	if err != nil {
+		return err
+	}
+	prevHash := prev.Header.Hash()
+
+	for {
+		data, err := readFrame(conn)
+		if err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			return fmt.Errorf("No blocks past %d", ch.support.Height()-1)
+		}
+
+		block, err := utils.GetBlockFromBlockBytes(data)
+		if err != nil {
+			return err
+		}
+		if block.Header == nil || block.Data == nil {
+			return fmt.Errorf("Received a malformed block")
+		}
+		if block.Header.Number != ch.support.Height() {
+			return fmt.Errorf("Expected block %d, got block %d", ch.support.Height(), block.Header.Number)
+		}
+		if !bytes.Equal(block.Header.PreviousHash, prevHash) || !bytes.Equal(block.Header.DataHash, block.Data.Hash()) {
+			return fmt.Errorf("Block %d is not chained to the ledger", block.Header.Number)
+		}
+		prevHash = block.Header.Hash()
+		if block.Header.Number == height-1 && !bytes.Equal(prevHash, hash) {
+			return fmt.Errorf("Block %d does not match the snapshot", block.Header.Number)
+		}
+
+		ch.commit(block)
+		if block.Header.Number == height-1 {
+			return nil
+		}
+	}
+}
+
+func (ch *chain) getBlock(number uint64) (*cb.Block, error) {
+	itr, _ := ch.support.Reader().Iterator(seekTo(number))
+	block, status := itr.Next()
+	if status != cb.Status_SUCCESS {
+		return nil, fmt.Errorf("Failed to read block %d: %s", number, status)
+	}
+	return block, nil
+}
+
+func seekTo(number uint64) *ab.SeekPosition {
+	return &ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: number}}}
+}
+
+// logWriter routes the output of the Raft library to the orderer logger
+type logWriter struct {
+	chainID string
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	msg := strings.TrimSpace(string(p))
+	// Drop the timestamp of the Raft library, the orderer logger has its own
+	if i := strings.Index(msg, " ["); i >= 0 {
+		msg = msg[i+1:]
+	}
+	switch {
+	case strings.Contains(msg, "[ERROR]"):
+		logger.Errorf("[channel: %s] %s", w.chainID, msg)
+	case strings.Contains(msg, "[WARN]"):
+		logger.Warningf("[channel: %s] %s", w.chainID, msg)
+	case strings.Contains(msg, "[INFO]"):
+		logger.Infof("[channel: %s] %s", w.chainID, msg)
+	default:
+		logger.Debugf("[channel: %s] %s", w.chainID, msg)
+	}
+	return len(p), nil
+}
@@ -0,0 +1,350 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package raft
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/orderer/common/blockcutter"
+	"github.com/hyperledger/fabric/orderer/common/filter"
+	"github.com/hyperledger/fabric/orderer/ledger"
+	ramledger "github.com/hyperledger/fabric/orderer/ledger/ram"
+	config "github.com/hyperledger/fabric/orderer/localconfig"
+	mockmultichain "github.com/hyperledger/fabric/orderer/mocks/multichain"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/op/go-logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	// Keep the test output readable
+	logging.SetLevel(logging.WARNING, "orderer/raft")
+}
+
+const testChainID = "testchain"
+
+// testSupport writes the blocks to a RAM ledger and cuts them with a real
+// block cutter
+type testSupport struct {
+	*mockmultichain.ConsenterSupport
+	cutter blockcutter.Receiver
+
+	// The RAM ledger is not safe for concurrent appends and reads of its height
+	lock   sync.Mutex
+	ledger ledger.ReadWriter
+}
+
+func newTestSupport(consenters []string) *testSupport {
+	sharedConfig := &mockconfig.Orderer{
+		BatchTimeoutVal:   10 * time.Millisecond,
+		BatchSizeVal:      &ab.BatchSize{MaxMessageCount: 2, AbsoluteMaxBytes: 1024 * 1024, PreferredMaxBytes: 1024 * 1024},
+		RaftConsentersVal: consenters,
+	}
+	rl, _ := ramledger.New(100).GetOrCreate(testChainID)
+	genesis := cb.NewBlock(0, nil)
+	genesis.Header.DataHash = genesis.Data.Hash()
+	rl.Append(genesis)
+
+	return &testSupport{
+		ConsenterSupport: &mockmultichain.ConsenterSupport{
+			SharedConfigVal: sharedConfig,
+			ChainIDVal:      testChainID,
+		},
+		ledger: rl,
+		cutter: blockcutter.NewReceiverImpl(sharedConfig, filter.NewRuleSet([]filter.Rule{filter.EmptyRejectRule, filter.AcceptRule})),
+	}
+}
+
+func (ts *testSupport) BlockCutter() blockcutter.Receiver {
+	return ts.cutter
+}
+
+func (ts *testSupport) Reader() ledger.Reader {
+	return ts.ledger
+}
+
+func (ts *testSupport) Height() uint64 {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	return ts.ledger.Height()
+}
+
+func (ts *testSupport) CreateNextBlock(messages []*cb.Envelope) *cb.Block {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	return ledger.CreateNextBlock(ts.ledger, messages)
+}
+
+func (ts *testSupport) WriteBlock(block *cb.Block, committers []filter.Committer, encodedMetadataValue []byte) *cb.Block {
+	for _, committer := range committers {
+		committer.Commit()
+	}
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	if err := ts.ledger.Append(block); err != nil {
+		panic(err)
+	}
+	return block
+}
+
+type testNode struct {
+	consenter *consenter
+	support   *testSupport
+	chain     *chain
+}
+
+func newTestNodes(t *testing.T, dir string, count int) []*testNode {
+	nodes := make([]*testNode, count)
+	var consenters []string
+	for i := range nodes {
+		cl := newTestCluster(t, comm.SecureServerConfig{})
+		consenters = append(consenters, cl.address)
+		nodes[i] = &testNode{consenter: &consenter{
+			conf: config.Raft{
+				Address:           cl.address,
+				HeartbeatTimeout:  200 * time.Millisecond,
+				ElectionTimeout:   200 * time.Millisecond,
+				SnapshotThreshold: 4,
+				SnapshotRetain:    1,
+			},
+			dataDir: filepath.Join(dir, fmt.Sprintf("node%d", i)),
+			cluster: cl,
+		}}
+	}
+	for _, node := range nodes {
+		node.start(t, newTestSupport(consenters))
+	}
+	return nodes
+}
+
+func (node *testNode) start(t *testing.T, support *testSupport) {
+	ch, err := node.consenter.HandleChain(support, nil)
+	if err != nil {
+		t.Fatalf("Failed to handle chain: %s", err)
+	}
+	node.support = support
+	node.chain = ch.(*chain)
+	node.chain.Start()
+}
+
+func (node *testNode) halt() {
+	node.chain.Halt()
+}
+
+func waitForLeader(t *testing.T, nodes []*testNode) *testNode {
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, node := range nodes {
+			if node.chain.raft.State() == raft.Leader {
+				return node
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("No leader was elected")
+	return nil
+}
+
+func waitForHeight(t *testing.T, nodes []*testNode, height uint64) {
+	deadline := time.Now().Add(10 * time.Second)
+	for _, node := range nodes {
+		for node.support.Height() < height {
+			if time.Now().After(deadline) {
+				t.Fatalf("Ledger of %s has height %d instead of %d", node.consenter.conf.Address, node.support.Height(), height)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+// assertSameLedgers checks that the ledgers of the nodes hold the same blocks
+func assertSameLedgers(t *testing.T, nodes []*testNode) {
+	height := nodes[0].support.Height()
+	for _, node := range nodes[1:] {
+		assert.Equal(t, height, node.support.Height(), "Ledgers have different heights")
+	}
+	for number := uint64(0); number < height; number++ {
+		expected, _ := nodes[0].chain.getBlock(number)
+		for _, node := range nodes[1:] {
+			block, err := node.chain.getBlock(number)
+			if assert.NoError(t, err) {
+				assert.True(t, bytes.Equal(expected.Header.Hash(), block.Header.Hash()), "Block %d differs", number)
+			}
+		}
+	}
+}
+
+func enqueue(t *testing.T, node *testNode, count int) {
+	for i := 0; i < count; i++ {
+		env := &cb.Envelope{Payload: []byte(fmt.Sprintf("%s message %d", node.consenter.conf.Address, i))}
+		if !node.chain.Enqueue(env) {
+			t.Fatalf("Message was not accepted")
+		}
+	}
+}
+
+func followers(nodes []*testNode, leader *testNode) []*testNode {
+	var result []*testNode
+	for _, node := range nodes {
+		if node != leader {
+			result = append(result, node)
+		}
+	}
+	return result
+}
+
+func TestNotAConsenter(t *testing.T) {
+	c := New(config.Raft{Address: "127.0.0.1:7090"}, comm.SecureServerConfig{}, "")
+	_, err := c.HandleChain(newTestSupport([]string{"127.0.0.1:7091"}), nil)
+	assert.Error(t, err, "Orderers which are not consenters of the channel cannot order it")
+}
+
+func TestReplication(t *testing.T) {
+	dir, err := ioutil.TempDir("", "raft-chain")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	nodes := newTestNodes(t, dir, 3)
+	defer func() {
+		for _, node := range nodes {
+			node.halt()
+			node.consenter.cluster.close()
+		}
+	}()
+
+	// Messages are ordered by the leader, whichever consenter receives them
+	leader := waitForLeader(t, nodes)
+	enqueue(t, leader, 4)
+	enqueue(t, followers(nodes, leader)[0], 4)
+	waitForHeight(t, nodes, 5)
+	assertSameLedgers(t, nodes)
+
+	// A consenter restarting replays its log on top of its ledger
+	follower := followers(nodes, leader)[0]
+	follower.halt()
+	enqueue(t, leader, 2)
+	waitForHeight(t, []*testNode{leader}, 6)
+	follower.start(t, follower.support)
+	waitForHeight(t, nodes, 6)
+	assertSameLedgers(t, nodes)
+
+	// A consenter which lost its ledger and log pulls the blocks from the
+	// others once the log it needs is compacted into a snapshot
+	follower = followers(nodes, leader)[1]
+	follower.halt()
+	enqueue(t, leader, 10)
+	waitForHeight(t, []*testNode{leader}, 11)
+	if err := leader.chain.raft.Snapshot().Error(); err != nil {
+		t.Fatalf("Failed to take a snapshot: %s", err)
+	}
+	os.RemoveAll(follower.consenter.dataDir)
+	follower.start(t, newTestSupport(follower.support.SharedConfigVal.RaftConsentersVal))
+	waitForHeight(t, nodes, 11)
+	assertSameLedgers(t, nodes)
+
+	// The cluster tolerates the loss of a minority
+	leader.halt()
+	leader = waitForLeader(t, followers(nodes, leader))
+	enqueue(t, leader, 2)
+	waitForHeight(t, []*testNode{leader}, 12)
+}
+
+func TestReconfiguration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "raft-chain")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	nodes := newTestNodes(t, dir, 2)
+	defer func() {
+		for _, node := range nodes {
+			node.halt()
+			node.consenter.cluster.close()
+		}
+	}()
+	leader := waitForLeader(t, nodes)
+	enqueue(t, leader, 2)
+	waitForHeight(t, nodes, 2)
+
+	// A consenter added to the channel config is added to the Raft configuration
+	// by the leader once the config is committed
+	added := &testNode{consenter: &consenter{
+		conf:    nodes[0].consenter.conf,
+		dataDir: filepath.Join(dir, "added"),
+		cluster: newTestCluster(t, comm.SecureServerConfig{}),
+	}}
+	added.consenter.conf.Address = added.consenter.cluster.address
+	consenters := append(append([]string{}, nodes[0].support.SharedConfigVal.RaftConsentersVal...), added.consenter.conf.Address)
+	added.start(t, newTestSupport(consenters))
+	for _, node := range nodes {
+		node.support.SharedConfigVal.RaftConsentersVal = consenters
+	}
+	nodes = append(nodes, added)
+	enqueue(t, leader, 2)
+	waitForHeight(t, nodes, 3)
+	assertSameLedgers(t, nodes)
+	assertRaftServers(t, leader, consenters)
+
+	// A consenter removed from the channel config is removed from the Raft configuration
+	removed := followers(nodes, leader)[0]
+	var remaining []string
+	for _, consenter := range consenters {
+		if consenter != removed.consenter.conf.Address {
+			remaining = append(remaining, consenter)
+		}
+	}
+	for _, node := range nodes {
+		node.support.SharedConfigVal.RaftConsentersVal = remaining
+	}
+	enqueue(t, leader, 2)
+	waitForHeight(t, []*testNode{leader}, 4)
+	assertRaftServers(t, leader, remaining)
+}
+
+// assertRaftServers waits for the Raft configuration of the node to hold the given consenters
+func assertRaftServers(t *testing.T, node *testNode, consenters []string) {
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		future := node.chain.raft.GetConfiguration()
+		assert.NoError(t, future.Error())
+		var servers []string
+		for _, server := range future.Configuration().Servers {
+			servers = append(servers, string(server.ID))
+		}
+		if len(servers) == len(consenters) && assert.ObjectsAreEqual(consenters, servers) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Raft configuration holds %v instead of %v", servers, consenters)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
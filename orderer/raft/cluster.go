@@ -0,0 +1,315 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package raft
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/hyperledger/fabric/core/comm"
+)
+
+// The kinds of streams opened between the consenters of a channel
+const (
+	streamRaft byte = iota + 1
+	streamForward
+	streamPull
+)
+
+const (
+	// handshakeTimeout bounds the time a dialer takes to send its stream header
+	handshakeTimeout = 10 * time.Second
+
+	// maxFrameSize bounds the size of the envelopes and blocks exchanged
+	maxFrameSize = 1 << 30
+)
+
+var errStreamLayerClosed = errors.New("Stream layer is closed")
+
+// streamHandler serves the streams opened by the other consenters of a channel.
+// cert is the TLS client certificate of the dialer, or nil if TLS is disabled
+type streamHandler interface {
+	handleStream(kind byte, conn net.Conn, cert *x509.Certificate)
+}
+
+// cluster multiplexes the Raft, envelope forwarding and block pulling streams
+// of all the channels of the orderer on a single listener. When TLS is enabled
+// the consenters authenticate each other with the TLS certificates of the
+// orderer, which must chain to its server or client root CAs; the handler of
+// a channel then checks that the certificate of the dialer belongs to one of
+// the consenters of the channel
+type cluster struct {
+	address   string
+	listener  net.Listener
+	clientTLS *tls.Config
+
+	lock     sync.Mutex
+	handlers map[string]streamHandler
+}
+
+func newCluster(address string, listenAddress string, secureConfig comm.SecureServerConfig) (*cluster, error) {
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to listen on %s: %s", listenAddress, err)
+	}
+
+	c := &cluster{
+		address:  address,
+		handlers: make(map[string]streamHandler),
+	}
+
+	if secureConfig.UseTLS {
+		serverTLS, clientTLS, err := clusterTLSConfig(secureConfig)
+		if err != nil {
+			listener.Close()
+			return nil, err
+		}
+		listener = tls.NewListener(listener, serverTLS)
+		c.clientTLS = clientTLS
+	} else {
+		logger.Warningf("TLS is disabled, the Raft consenters listening on %s will not authenticate each other", listenAddress)
+	}
+	c.listener = listener
+
+	go c.serve()
+	return c, nil
+}
+
+func clusterTLSConfig(secureConfig comm.SecureServerConfig) (*tls.Config, *tls.Config, error) {
+	cert, err := tls.X509KeyPair(secureConfig.ServerCertificate, secureConfig.ServerKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to load the TLS key pair: %s", err)
+	}
+
+	rootCAs := x509.NewCertPool()
+	clientCAs := x509.NewCertPool()
+	for _, root := range secureConfig.ServerRootCAs {
+		if !rootCAs.AppendCertsFromPEM(root) {
+			return nil, nil, fmt.Errorf("Failed to load a server root CA certificate")
+		}
+		clientCAs.AppendCertsFromPEM(root)
+	}
+	for _, root := range secureConfig.ClientRootCAs {
+		if !clientCAs.AppendCertsFromPEM(root) {
+			return nil, nil, fmt.Errorf("Failed to load a client root CA certificate")
+		}
+	}
+
+	serverTLS := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	clientTLS := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      rootCAs,
+	}
+	return serverTLS, clientTLS, nil
+}
+
+func (c *cluster) serve() {
+	for {
+		conn, err := c.listener.Accept()
+		if err != nil {
+			logger.Debugf("Stopped accepting cluster connections: %s", err)
+			return
+		}
+		go c.handleConn(conn)
+	}
+}
+
+// handleConn reads the header of a stream, i.e. its kind and the channel it
+// belongs to, and hands the stream over to the handler of the channel
+func (c *cluster) handleConn(conn net.Conn) {
+	conn.SetDeadline(time.Now().Add(handshakeTimeout))
+	header := make([]byte, 3)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		logger.Warningf("Failed to read stream header from %s: %s", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+	chainID := make([]byte, binary.BigEndian.Uint16(header[1:]))
+	if _, err := io.ReadFull(conn, chainID); err != nil {
+		logger.Warningf("Failed to read stream header from %s: %s", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+	conn.SetDeadline(time.Time{})
+
+	// The TLS handshake is complete once the header is read
+	var cert *x509.Certificate
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		certs := tlsConn.ConnectionState().PeerCertificates
+		if len(certs) == 0 {
+			logger.Warningf("Rejecting stream from %s without a client certificate", conn.RemoteAddr())
+			conn.Close()
+			return
+		}
+		cert = certs[0]
+	}
+
+	c.lock.Lock()
+	handler, ok := c.handlers[string(chainID)]
+	c.lock.Unlock()
+	if !ok {
+		logger.Debugf("Rejecting stream from %s for unknown channel %s", conn.RemoteAddr(), chainID)
+		conn.Close()
+		return
+	}
+	handler.handleStream(header[0], conn, cert)
+}
+
+// dial opens a stream of the given kind to the consenter of the channel
+// listening on address
+func (c *cluster) dial(address string, kind byte, chainID string, timeout time.Duration) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	var err error
+	if c.clientTLS != nil {
+		host, _, splitErr := net.SplitHostPort(address)
+		if splitErr != nil {
+			return nil, splitErr
+		}
+		tlsConfig := c.clientTLS.Clone()
+		tlsConfig.ServerName = host
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 3+len(chainID))
+	header[0] = kind
+	binary.BigEndian.PutUint16(header[1:], uint16(len(chainID)))
+	copy(header[3:], chainID)
+	conn.SetWriteDeadline(time.Now().Add(handshakeTimeout))
+	if _, err := conn.Write(header); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetWriteDeadline(time.Time{})
+	return conn, nil
+}
+
+func (c *cluster) register(chainID string, handler streamHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.handlers[chainID] = handler
+}
+
+func (c *cluster) unregister(chainID string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.handlers, chainID)
+}
+
+func (c *cluster) close() error {
+	return c.listener.Close()
+}
+
+// streamLayer carries the Raft traffic of a channel over the cluster
+type streamLayer struct {
+	cluster *cluster
+	chainID string
+	conns   chan net.Conn
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func newStreamLayer(c *cluster, chainID string) *streamLayer {
+	return &streamLayer{
+		cluster: c,
+		chainID: chainID,
+		conns:   make(chan net.Conn),
+		closed:  make(chan struct{}),
+	}
+}
+
+// push hands an incoming Raft stream over to the transport
+func (sl *streamLayer) push(conn net.Conn) {
+	select {
+	case sl.conns <- conn:
+	case <-sl.closed:
+		conn.Close()
+	}
+}
+
+// Accept waits for and returns the next Raft stream of the channel
+func (sl *streamLayer) Accept() (net.Conn, error) {
+	select {
+	case conn := <-sl.conns:
+		return conn, nil
+	case <-sl.closed:
+		return nil, errStreamLayerClosed
+	}
+}
+
+// Close stops accepting Raft streams, the cluster listener is left open
+func (sl *streamLayer) Close() error {
+	sl.closeOnce.Do(func() { close(sl.closed) })
+	return nil
+}
+
+// Addr returns the address this consenter is known by to the others
+func (sl *streamLayer) Addr() net.Addr {
+	return clusterAddr(sl.cluster.address)
+}
+
+// Dial opens a Raft stream to the consenter listening on address
+func (sl *streamLayer) Dial(address raft.ServerAddress, timeout time.Duration) (net.Conn, error) {
+	return sl.cluster.dial(string(address), streamRaft, sl.chainID, timeout)
+}
+
+type clusterAddr string
+
+func (a clusterAddr) Network() string { return "tcp" }
+func (a clusterAddr) String() string  { return string(a) }
+
+func writeFrame(w io.Writer, data []byte) error {
+	buf := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(buf, uint32(len(data)))
+	copy(buf[4:], data)
+	_, err := w.Write(buf)
+	return err
+}
+
+func readFrame(r io.Reader) ([]byte, error) {
+	size := make([]byte, 4)
+	if _, err := io.ReadFull(r, size); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(size)
+	if n > maxFrameSize {
+		return nil, fmt.Errorf("Frame of %d bytes exceeds the maximum of %d", n, maxFrameSize)
+	}
+	data := make([]byte, n)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,221 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package raft
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create CA certificate: %s", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// secureConfig issues a certificate for 127.0.0.1, usable both as a server and
+// a client certificate
+func (ca *testCA) secureConfig(t *testing.T) comm.SecureServerConfig {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "orderer"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %s", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %s", err)
+	}
+	return comm.SecureServerConfig{
+		UseTLS:            true,
+		ServerCertificate: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		ServerKey:         pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		ServerRootCAs:     [][]byte{ca.pem},
+	}
+}
+
+type recordingHandler struct {
+	kinds chan byte
+	conns chan net.Conn
+	certs chan *x509.Certificate
+}
+
+func newRecordingHandler() *recordingHandler {
+	return &recordingHandler{kinds: make(chan byte, 1), conns: make(chan net.Conn, 1), certs: make(chan *x509.Certificate, 1)}
+}
+
+func (h *recordingHandler) handleStream(kind byte, conn net.Conn, cert *x509.Certificate) {
+	h.kinds <- kind
+	h.conns <- conn
+	h.certs <- cert
+}
+
+func newTestCluster(t *testing.T, secureConfig comm.SecureServerConfig) *cluster {
+	cl, err := newCluster("", "127.0.0.1:0", secureConfig)
+	if err != nil {
+		t.Fatalf("Failed to create cluster: %s", err)
+	}
+	cl.address = cl.listener.Addr().String()
+	return cl
+}
+
+func TestClusterStreams(t *testing.T) {
+	ca := newTestCA(t)
+	a := newTestCluster(t, ca.secureConfig(t))
+	defer a.close()
+	b := newTestCluster(t, ca.secureConfig(t))
+	defer b.close()
+
+	handler := newRecordingHandler()
+	a.register("foo", handler)
+
+	conn, err := b.dial(a.address, streamPull, "foo", time.Second)
+	if err != nil {
+		t.Fatalf("Failed to dial: %s", err)
+	}
+	defer conn.Close()
+	assert.NoError(t, writeFrame(conn, []byte("hello")))
+
+	select {
+	case kind := <-handler.kinds:
+		assert.Equal(t, streamPull, kind)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Stream was not handed over to the channel")
+	}
+	served := <-handler.conns
+	defer served.Close()
+	cert := <-handler.certs
+	if assert.NotNil(t, cert, "The client certificate should have been handed over to the channel") {
+		assert.NoError(t, cert.VerifyHostname("127.0.0.1"))
+	}
+	data, err := readFrame(served)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hello"), data)
+
+	// Streams of channels not served by the consenter are closed
+	conn, err = b.dial(a.address, streamPull, "bar", time.Second)
+	if err != nil {
+		t.Fatalf("Failed to dial: %s", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err = readFrame(conn)
+	assert.Error(t, err, "Stream of unknown channel should have been closed")
+}
+
+func TestClusterAuthentication(t *testing.T) {
+	caA := newTestCA(t)
+	a := newTestCluster(t, caA.secureConfig(t))
+	defer a.close()
+	handler := newRecordingHandler()
+	a.register("foo", handler)
+
+	// The certificate of a is not trusted by c
+	c := newTestCluster(t, newTestCA(t).secureConfig(t))
+	defer c.close()
+	_, err := c.dial(a.address, streamRaft, "foo", time.Second)
+	assert.Error(t, err, "Server certificate should not have been trusted")
+
+	// The certificate of d is not trusted by a, even though d trusts a
+	dConfig := newTestCA(t).secureConfig(t)
+	dConfig.ServerRootCAs = [][]byte{caA.pem}
+	d := newTestCluster(t, dConfig)
+	defer d.close()
+	conn, err := d.dial(a.address, streamRaft, "foo", time.Second)
+	if err == nil {
+		defer conn.Close()
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, err = readFrame(conn)
+	}
+	assert.Error(t, err, "Client certificate should not have been trusted")
+
+	select {
+	case <-handler.kinds:
+		t.Fatalf("Stream of an untrusted consenter should not have been handed over to the channel")
+	default:
+	}
+}
+
+func TestConsenterAuthorization(t *testing.T) {
+	secureConfig := newTestCA(t).secureConfig(t)
+	block, _ := pem.Decode(secureConfig.ServerCertificate)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %s", err)
+	}
+
+	// The certificate is issued to 127.0.0.1, the host of the second consenter
+	ch := &chain{support: newTestSupport([]string{"orderer0.example.com:7050", "127.0.0.1:7050"})}
+	consenter, ok := ch.consenterOf(cert)
+	assert.True(t, ok)
+	assert.Equal(t, "127.0.0.1:7050", consenter)
+
+	// Streams of dialers which are not consenters of the channel are closed
+	ch = &chain{support: newTestSupport([]string{"orderer0.example.com:7050"})}
+	_, ok = ch.consenterOf(cert)
+	assert.False(t, ok)
+	client, server := net.Pipe()
+	defer client.Close()
+	ch.handleStream(streamForward, server, cert)
+	client.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err = readFrame(client)
+	assert.Error(t, err, "Stream of a dialer which is not a consenter should have been closed")
+}
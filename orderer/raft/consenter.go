@@ -0,0 +1,88 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package raft
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/hyperledger/fabric/core/comm"
+	config "github.com/hyperledger/fabric/orderer/localconfig"
+	"github.com/hyperledger/fabric/orderer/multichain"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/op/go-logging"
+)
+
+var logger = logging.MustGetLogger("orderer/raft")
+
+type consenter struct {
+	conf         config.Raft
+	secureConfig comm.SecureServerConfig
+	dataDir      string
+
+	lock    sync.Mutex
+	cluster *cluster
+}
+
+// New creates a new consenter for the Raft consensus scheme.
+// The leader of each channel's Raft cluster orders the messages and cuts them into blocks,
+// which are replicated to the other consenters of the channel listed in its config before
+// being written to the ledgers. The Raft log and snapshots of each channel are kept under
+// dataDir, and the consenters authenticate each other with the orderer TLS certificates.
+func New(conf config.Raft, secureConfig comm.SecureServerConfig, dataDir string) multichain.Consenter {
+	return &consenter{
+		conf:         conf,
+		secureConfig: secureConfig,
+		dataDir:      dataDir,
+	}
+}
+
+func (c *consenter) HandleChain(support multichain.ConsenterSupport, metadata *cb.Metadata) (multichain.Chain, error) {
+	consenters := support.SharedConfig().RaftConsenters()
+	member := false
+	for _, consenter := range consenters {
+		if consenter == c.conf.Address {
+			member = true
+			break
+		}
+	}
+	if !member {
+		return nil, fmt.Errorf("This orderer (%s) is not one of the Raft consenters %v of channel %s", c.conf.Address, consenters, support.ChainID())
+	}
+
+	cl, err := c.getCluster()
+	if err != nil {
+		return nil, err
+	}
+	return newChain(support, c.conf, cl, filepath.Join(c.dataDir, support.ChainID()))
+}
+
+// getCluster starts listening for the other consenters when the first
+// channel ordered with Raft is handled
+func (c *consenter) getCluster() (*cluster, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.cluster == nil {
+		cl, err := newCluster(c.conf.Address, fmt.Sprintf("%s:%d", c.conf.ListenAddress, c.conf.ListenPort), c.secureConfig)
+		if err != nil {
+			return nil, err
+		}
+		c.cluster = cl
+	}
+	return c.cluster, nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package raft
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/hashicorp/raft"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// fsm writes the blocks replicated by Raft to the ledger of the channel
+type fsm struct {
+	ch *chain
+}
+
+// Apply commits the block held by a Raft log entry
+func (f *fsm) Apply(log *raft.Log) interface{} {
+	block, err := utils.GetBlockFromBlockBytes(log.Data)
+	if err != nil {
+		logger.Panicf("[channel: %s] Raft log entry %d does not hold a block: %s", f.ch.support.ChainID(), log.Index, err)
+	}
+	f.ch.commit(block)
+	return nil
+}
+
+// Snapshot captures the height and the tip of the ledger, the blocks
+// themselves are kept by the ledger and pulled by the consenters restoring
+// the snapshot
+func (f *fsm) Snapshot() (raft.FSMSnapshot, error) {
+	height := f.ch.support.Height()
+	block, err := f.ch.getBlock(height - 1)
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot{height: height, hash: block.Header.Hash()}, nil
+}
+
+// Restore brings the ledger up to the height of a snapshot
+func (f *fsm) Restore(rc io.ReadCloser) error {
+	defer rc.Close()
+	data, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return err
+	}
+	if len(data) < 8 {
+		return fmt.Errorf("Snapshot is truncated")
+	}
+	return f.ch.catchUp(binary.BigEndian.Uint64(data), data[8:])
+}
+
+type snapshot struct {
+	height uint64
+	hash   []byte
+}
+
+func (s *snapshot) Persist(sink raft.SnapshotSink) error {
+	data := make([]byte, 8, 8+len(s.hash))
+	binary.BigEndian.PutUint64(data, s.height)
+	data = append(data, s.hash...)
+	if _, err := sink.Write(data); err != nil {
+		sink.Cancel()
+		return err
+	}
+	return sink.Close()
+}
+
+func (s *snapshot) Release() {}
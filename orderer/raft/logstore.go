@@ -0,0 +1,205 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package raft
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/raft"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+var (
+	logKeyPrefix    = []byte{'l'}
+	logKeyEnd       = []byte{'l' + 1}
+	stableKeyPrefix = []byte{'s'}
+
+	// errKeyNotFound is matched by its message by the raft library
+	errKeyNotFound = errors.New("not found")
+)
+
+// logStore is the write-ahead log of a channel's Raft instance, it implements
+// both raft.LogStore and raft.StableStore on top of a leveldb database
+type logStore struct {
+	db *leveldbhelper.DB
+}
+
+func newLogStore(dbPath string) *logStore {
+	db := leveldbhelper.CreateDB(&leveldbhelper.Conf{DBPath: dbPath})
+	db.Open()
+	return &logStore{db: db}
+}
+
+func (ls *logStore) close() {
+	ls.db.Close()
+}
+
+func logKey(index uint64) []byte {
+	key := make([]byte, len(logKeyPrefix)+8)
+	copy(key, logKeyPrefix)
+	binary.BigEndian.PutUint64(key[len(logKeyPrefix):], index)
+	return key
+}
+
+func stableKey(key []byte) []byte {
+	return append(append([]byte{}, stableKeyPrefix...), key...)
+}
+
+// FirstIndex returns the first index written, or 0 for no entries
+func (ls *logStore) FirstIndex() (uint64, error) {
+	itr := ls.db.GetIterator(logKeyPrefix, logKeyEnd)
+	defer itr.Release()
+	if !itr.First() {
+		return 0, itr.Error()
+	}
+	return binary.BigEndian.Uint64(itr.Key()[len(logKeyPrefix):]), nil
+}
+
+// LastIndex returns the last index written, or 0 for no entries
+func (ls *logStore) LastIndex() (uint64, error) {
+	itr := ls.db.GetIterator(logKeyPrefix, logKeyEnd)
+	defer itr.Release()
+	if !itr.Last() {
+		return 0, itr.Error()
+	}
+	return binary.BigEndian.Uint64(itr.Key()[len(logKeyPrefix):]), nil
+}
+
+// GetLog gets the log entry at the given index
+func (ls *logStore) GetLog(index uint64, log *raft.Log) error {
+	value, err := ls.db.Get(logKey(index))
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		return raft.ErrLogNotFound
+	}
+	return decodeLog(value, log)
+}
+
+// StoreLog stores a log entry
+func (ls *logStore) StoreLog(log *raft.Log) error {
+	return ls.StoreLogs([]*raft.Log{log})
+}
+
+// StoreLogs stores multiple log entries
+func (ls *logStore) StoreLogs(logs []*raft.Log) error {
+	batch := &leveldb.Batch{}
+	for _, log := range logs {
+		batch.Put(logKey(log.Index), encodeLog(log))
+	}
+	return ls.db.WriteBatch(batch, true)
+}
+
+// DeleteRange deletes the log entries in the range [min, max], both inclusive
+func (ls *logStore) DeleteRange(min, max uint64) error {
+	batch := &leveldb.Batch{}
+	itr := ls.db.GetIterator(logKey(min), logKeyEnd)
+	for itr.Next() {
+		if binary.BigEndian.Uint64(itr.Key()[len(logKeyPrefix):]) > max {
+			break
+		}
+		batch.Delete(append([]byte{}, itr.Key()...))
+	}
+	itr.Release()
+	if err := itr.Error(); err != nil {
+		return err
+	}
+	return ls.db.WriteBatch(batch, true)
+}
+
+// Set stores the value of the given key
+func (ls *logStore) Set(key []byte, val []byte) error {
+	return ls.db.Put(stableKey(key), val, true)
+}
+
+// Get returns the value of the given key, or an error if it was never set
+func (ls *logStore) Get(key []byte) ([]byte, error) {
+	value, err := ls.db.Get(stableKey(key))
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, errKeyNotFound
+	}
+	return value, nil
+}
+
+// SetUint64 stores the value of the given key as an uint64
+func (ls *logStore) SetUint64(key []byte, val uint64) error {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, val)
+	return ls.Set(key, buf)
+}
+
+// GetUint64 returns the uint64 value of the given key
+func (ls *logStore) GetUint64(key []byte) (uint64, error) {
+	value, err := ls.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	if len(value) != 8 {
+		return 0, fmt.Errorf("Value of key %s is not an uint64", key)
+	}
+	return binary.BigEndian.Uint64(value), nil
+}
+
+// A log entry is serialized as its index, term and type followed by its
+// length prefixed data and extensions
+func encodeLog(log *raft.Log) []byte {
+	buf := make([]byte, 8+8+1+4+len(log.Data)+4+len(log.Extensions))
+	binary.BigEndian.PutUint64(buf[0:], log.Index)
+	binary.BigEndian.PutUint64(buf[8:], log.Term)
+	buf[16] = byte(log.Type)
+	offset := 17
+	for _, field := range [][]byte{log.Data, log.Extensions} {
+		binary.BigEndian.PutUint32(buf[offset:], uint32(len(field)))
+		offset += 4
+		offset += copy(buf[offset:], field)
+	}
+	return buf
+}
+
+func decodeLog(buf []byte, log *raft.Log) error {
+	if len(buf) < 17 {
+		return fmt.Errorf("Log entry is truncated")
+	}
+	log.Index = binary.BigEndian.Uint64(buf[0:])
+	log.Term = binary.BigEndian.Uint64(buf[8:])
+	log.Type = raft.LogType(buf[16])
+	buf = buf[17:]
+	fields := make([][]byte, 2)
+	for i := range fields {
+		if len(buf) < 4 {
+			return fmt.Errorf("Log entry %d is truncated", log.Index)
+		}
+		size := binary.BigEndian.Uint32(buf)
+		buf = buf[4:]
+		if uint32(len(buf)) < size {
+			return fmt.Errorf("Log entry %d is truncated", log.Index)
+		}
+		if size > 0 {
+			fields[i] = append([]byte{}, buf[:size]...)
+		}
+		buf = buf[size:]
+	}
+	log.Data, log.Extensions = fields[0], fields[1]
+	return nil
+}
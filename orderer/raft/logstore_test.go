@@ -0,0 +1,80 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package raft
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "raft-logstore")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := newLogStore(dir)
+	first, err := store.FirstIndex()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), first, "Empty log")
+	last, err := store.LastIndex()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), last, "Empty log")
+
+	assert.NoError(t, store.StoreLogs([]*raft.Log{
+		{Index: 1, Term: 1, Type: raft.LogConfiguration, Data: []byte("config")},
+		{Index: 2, Term: 1, Type: raft.LogCommand, Data: []byte("block"), Extensions: []byte("ext")},
+		{Index: 3, Term: 2, Type: raft.LogNoop},
+	}))
+	assert.NoError(t, store.StoreLog(&raft.Log{Index: 4, Term: 2, Type: raft.LogCommand, Data: []byte("another block")}))
+	assert.NoError(t, store.SetUint64([]byte("CurrentTerm"), 2))
+
+	// The log survives a restart
+	store.close()
+	store = newLogStore(dir)
+	defer store.close()
+
+	first, _ = store.FirstIndex()
+	last, _ = store.LastIndex()
+	assert.Equal(t, uint64(1), first)
+	assert.Equal(t, uint64(4), last)
+
+	log := &raft.Log{}
+	assert.NoError(t, store.GetLog(2, log))
+	assert.Equal(t, raft.Log{Index: 2, Term: 1, Type: raft.LogCommand, Data: []byte("block"), Extensions: []byte("ext")}, *log)
+	assert.NoError(t, store.GetLog(3, log))
+	assert.Equal(t, raft.Log{Index: 3, Term: 2, Type: raft.LogNoop}, *log)
+	assert.Equal(t, raft.ErrLogNotFound, store.GetLog(5, log))
+
+	term, err := store.GetUint64([]byte("CurrentTerm"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), term)
+	_, err = store.Get([]byte("LastVoteCand"))
+	assert.EqualError(t, err, "not found", "The raft library expects this exact error for missing keys")
+
+	assert.NoError(t, store.DeleteRange(1, 2))
+	first, _ = store.FirstIndex()
+	last, _ = store.LastIndex()
+	assert.Equal(t, uint64(3), first)
+	assert.Equal(t, uint64(4), last)
+	assert.Equal(t, raft.ErrLogNotFound, store.GetLog(1, log))
+}
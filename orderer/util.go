@@ -64,6 +64,18 @@ func createLedgerFactory(conf *config.TopLevel) (ledger.Factory, string) {
 	return lf, ld
 }
 
+// raftDataDir returns the directory the Raft consenter keeps its log and
+// snapshots in, which defaults to a sub-directory of the ledger directory
+func raftDataDir(conf *config.TopLevel, ledgerDir string) string {
+	if conf.Raft.DataDir != "" {
+		return conf.Raft.DataDir
+	}
+	if ledgerDir == "" {
+		ledgerDir = createTempDir(conf.FileLedger.Prefix)
+	}
+	return filepath.Join(ledgerDir, "raft")
+}
+
 func createTempDir(dirPrefix string) string {
 	dirPath, err := ioutil.TempDir("", dirPrefix)
 	if err != nil {
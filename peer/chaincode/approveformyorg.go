@@ -0,0 +1,69 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincode
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var chaincodeApproveForMyOrgCmd *cobra.Command
+
+const approveformyorg_cmdname = "approveformyorg"
+
+// approveForMyOrgCmd returns the cobra command for Chaincode ApproveForMyOrg
+func approveForMyOrgCmd(cf *ChaincodeCmdFactory) *cobra.Command {
+	chaincodeApproveForMyOrgCmd = &cobra.Command{
+		Use:   approveformyorg_cmdname,
+		Short: "Approve a chaincode definition for your organization.",
+		Long:  "Approve, as an admin of your organization, the definition of a chaincode on a channel. The definition is committed once enough organizations of the channel approved it.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return approveForMyOrg(cmd, cf)
+		},
+	}
+
+	return chaincodeApproveForMyOrgCmd
+}
+
+// approveForMyOrg approves the chaincode definition via Endorser and sends the transaction to the orderer
+func approveForMyOrg(cmd *cobra.Command, cf *ChaincodeCmdFactory) error {
+	var err error
+	if cf == nil {
+		cf, err = InitCmdFactory(true, true)
+		if err != nil {
+			return err
+		}
+	}
+	defer cf.BroadcastClient.Close()
+
+	def, err := getChaincodeDefinition(cmd, cf)
+	if err != nil {
+		return err
+	}
+
+	spec, err := lifecycleInvocation(approveformyorg_cmdname, def)
+	if err != nil {
+		return err
+	}
+
+	if err = lifecycleTransaction(cf, chainID, spec); err != nil {
+		return fmt.Errorf("Error approving chaincode definition: %s", err)
+	}
+
+	return nil
+}
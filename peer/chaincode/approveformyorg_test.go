@@ -0,0 +1,108 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincode
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/peer/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// resetLifecycleFlags resets the flags of the lifecycle commands, which other commands reject
+func resetLifecycleFlags() {
+	policy = common.UndefinedParamValue
+	sequence = 0
+	packageHash = ""
+}
+
+func TestApproveForMyOrgCmd(t *testing.T) {
+	InitMSP()
+	defer resetLifecycleFlags()
+
+	signer, err := common.GetDefaultSigner()
+	if err != nil {
+		t.Fatalf("Get default signer error: %v", err)
+	}
+
+	// the installed chaincodes returned when the package hash is not provided
+	cqr := &pb.ChaincodeQueryResponse{Chaincodes: []*pb.ChaincodeInfo{{Name: "example02", Version: "1.0", Id: []byte("hash")}}}
+	cqrBytes, err := proto.Marshal(cqr)
+	assert.NoError(t, err)
+
+	mockResponse := &pb.ProposalResponse{
+		Response:    &pb.Response{Status: 200, Payload: cqrBytes},
+		Endorsement: &pb.Endorsement{},
+	}
+
+	mockCF := &ChaincodeCmdFactory{
+		EndorserClient:  common.GetMockEndorserClient(mockResponse, nil),
+		Signer:          signer,
+		BroadcastClient: common.GetMockBroadcastClient(nil),
+	}
+
+	for _, tc := range []struct {
+		args        []string
+		expectedErr string
+	}{
+		{[]string{"-n", "example02", "-v", "1.0", "--sequence", "1", "-P", "OR('Org1MSP.member')", "--package-hash", "68617368"}, ""},
+		{[]string{"-n", "example02", "-v", "1.0", "--sequence", "1", "-P", "OR('Org1MSP.member')"}, ""},
+		{[]string{"-n", "example02", "-v", "1.0", "--sequence", "1", "-P", "OR('Org1MSP.member')", "--package-hash", "6861736"}, "Invalid package hash"},
+		{[]string{"-n", "example02", "-v", "2.0", "--sequence", "1", "-P", "OR('Org1MSP.member')"}, "Chaincode example02:2.0 is not installed on the peer"},
+		{[]string{"-n", "example02", "-v", "1.0", "-P", "OR('Org1MSP.member')"}, "Chaincode definition sequence is not provided"},
+		{[]string{"-n", "example02", "-v", "1.0", "--sequence", "1"}, "Endorsement policy is not provided"},
+	} {
+		cmd := approveForMyOrgCmd(mockCF)
+		AddFlags(cmd)
+		cmd.SetArgs(tc.args)
+		err = cmd.Execute()
+		if tc.expectedErr == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedErr)
+		}
+	}
+}
+
+func TestApproveForMyOrgCmdEndorseFail(t *testing.T) {
+	InitMSP()
+	defer resetLifecycleFlags()
+
+	signer, err := common.GetDefaultSigner()
+	if err != nil {
+		t.Fatalf("Get default signer error: %v", err)
+	}
+
+	mockResponse := &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: "not an admin"}}
+
+	mockCF := &ChaincodeCmdFactory{
+		EndorserClient:  common.GetMockEndorserClient(mockResponse, nil),
+		Signer:          signer,
+		BroadcastClient: common.GetMockBroadcastClient(nil),
+	}
+
+	cmd := approveForMyOrgCmd(mockCF)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"-n", "example02", "-v", "1.0", "--sequence", "1", "-P", "OR('Org1MSP.member')", "--package-hash", "68617368"})
+
+	err = cmd.Execute()
+	assert.Error(t, err)
+	assert.Equal(t, "Error approving chaincode definition: Could not assemble transaction, err Proposal response was not successful, error code 500, msg not an admin", err.Error())
+}
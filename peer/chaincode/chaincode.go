@@ -56,6 +56,10 @@ func AddFlags(cmd *cobra.Command) {
 		fmt.Sprint("The name of the endorsement system chaincode to be used for this chaincode"))
 	flags.StringVarP(&vscc, "vscc", "V", common.UndefinedParamValue,
 		fmt.Sprint("The name of the verification system chaincode to be used for this chaincode"))
+	flags.Int64VarP(&sequence, "sequence", "", 0,
+		fmt.Sprint("The sequence number of the chaincode definition, starting at 1 and incremented with each definition committed"))
+	flags.StringVarP(&packageHash, "package-hash", "", "",
+		fmt.Sprint("The hex-encoded hash of the chaincode package of the chaincode definition, defaults to the one of the package installed on the peer"))
 	flags.StringVarP(&orderingEndpoint, "orderer", "o", "", "Ordering service endpoint")
 	flags.BoolVarP(&tls, "tls", "", false, "Use TLS when communicating with the orderer endpoint")
 	flags.StringVarP(&caFile, "cafile", "", "", "Path to file containing PEM-encoded trusted certificate(s) for the ordering endpoint")
@@ -72,6 +76,9 @@ func Cmd(cf *ChaincodeCmdFactory) *cobra.Command {
 	chaincodeCmd.AddCommand(packageCmd(cf, nil))
 	chaincodeCmd.AddCommand(installCmd(cf))
 	chaincodeCmd.AddCommand(signpackageCmd(cf))
+	chaincodeCmd.AddCommand(approveForMyOrgCmd(cf))
+	chaincodeCmd.AddCommand(checkCommitReadinessCmd(cf))
+	chaincodeCmd.AddCommand(commitCmd(cf))
 
 	return chaincodeCmd
 }
@@ -92,6 +99,8 @@ var (
 	escc              string
 	vscc              string
 	policyMarhsalled  []byte
+	sequence          int64
+	packageHash       string
 	orderingEndpoint  string
 	tls               bool
 	caFile            string
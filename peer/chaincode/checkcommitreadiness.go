@@ -0,0 +1,89 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincode
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/cobra"
+)
+
+var chaincodeCheckCommitReadinessCmd *cobra.Command
+
+const checkcommitreadiness_cmdname = "checkcommitreadiness"
+
+// checkCommitReadinessCmd returns the cobra command for Chaincode CheckCommitReadiness
+func checkCommitReadinessCmd(cf *ChaincodeCmdFactory) *cobra.Command {
+	chaincodeCheckCommitReadinessCmd = &cobra.Command{
+		Use:   checkcommitreadiness_cmdname,
+		Short: "Check which organizations approved a chaincode definition.",
+		Long:  "Check which organizations of a channel approved the definition of a chaincode, before committing it.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkCommitReadiness(cmd, cf)
+		},
+	}
+
+	return chaincodeCheckCommitReadinessCmd
+}
+
+// checkCommitReadiness queries the approvals of the chaincode definition via Endorser. On success,
+// the approval status of each organization is printed to STDOUT
+func checkCommitReadiness(cmd *cobra.Command, cf *ChaincodeCmdFactory) error {
+	var err error
+	if cf == nil {
+		cf, err = InitCmdFactory(true, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	def, err := getChaincodeDefinition(cmd, cf)
+	if err != nil {
+		return err
+	}
+
+	spec, err := lifecycleInvocation(checkcommitreadiness_cmdname, def)
+	if err != nil {
+		return err
+	}
+
+	proposalResponse, err := lifecycleProposal(cf, chainID, spec)
+	if err != nil {
+		return fmt.Errorf("Error checking commit readiness: %s", err)
+	}
+
+	crr := &pb.CommitReadinessResponse{}
+	if err = proto.Unmarshal(proposalResponse.Response.Payload, crr); err != nil {
+		return fmt.Errorf("Error unmarshalling commit readiness: %s", err)
+	}
+
+	orgs := make([]string, 0, len(crr.Approvals))
+	for org := range crr.Approvals {
+		orgs = append(orgs, org)
+	}
+	sort.Strings(orgs)
+
+	fmt.Printf("Chaincode definition for chaincode '%s', version '%s', sequence '%d' on channel '%s' approval status by org:\n", def.Name, def.Version, def.Sequence, chainID)
+	for _, org := range orgs {
+		fmt.Printf("%s: %t\n", org, crr.Approvals[org])
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincode
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/peer/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckCommitReadinessCmd(t *testing.T) {
+	InitMSP()
+	defer resetLifecycleFlags()
+
+	signer, err := common.GetDefaultSigner()
+	if err != nil {
+		t.Fatalf("Get default signer error: %v", err)
+	}
+
+	crr := &pb.CommitReadinessResponse{Approvals: map[string]bool{"Org1MSP": true, "Org2MSP": false}}
+	crrBytes, err := proto.Marshal(crr)
+	assert.NoError(t, err)
+
+	mockCF := &ChaincodeCmdFactory{
+		EndorserClient: common.GetMockEndorserClient(&pb.ProposalResponse{Response: &pb.Response{Status: 200, Payload: crrBytes}}, nil),
+		Signer:         signer,
+	}
+
+	cmd := checkCommitReadinessCmd(mockCF)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"-n", "example02", "-v", "1.0", "--sequence", "1", "-P", "OR('Org1MSP.member')", "--package-hash", "68617368"})
+	assert.NoError(t, cmd.Execute())
+
+	mockCF.EndorserClient = common.GetMockEndorserClient(&pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: "invalid sequence"}}, nil)
+	cmd = checkCommitReadinessCmd(mockCF)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"-n", "example02", "-v", "1.0", "--sequence", "2", "-P", "OR('Org1MSP.member')", "--package-hash", "68617368"})
+	err = cmd.Execute()
+	assert.Error(t, err)
+	assert.Equal(t, "Error checking commit readiness: Proposal response was not successful, error code 500, msg invalid sequence", err.Error())
+}
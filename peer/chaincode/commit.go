@@ -0,0 +1,69 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincode
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var chaincodeCommitCmd *cobra.Command
+
+const commit_cmdname = "commit"
+
+// commitCmd returns the cobra command for Chaincode Commit
+func commitCmd(cf *ChaincodeCmdFactory) *cobra.Command {
+	chaincodeCommitCmd = &cobra.Command{
+		Use:   commit_cmdname,
+		Short: "Commit a chaincode definition.",
+		Long:  "Commit the definition of a chaincode on a channel once enough organizations of the channel approved it. The definition takes effect upon the transaction committed.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return commit(cmd, cf)
+		},
+	}
+
+	return chaincodeCommitCmd
+}
+
+// commit commits the chaincode definition via Endorser and sends the transaction to the orderer
+func commit(cmd *cobra.Command, cf *ChaincodeCmdFactory) error {
+	var err error
+	if cf == nil {
+		cf, err = InitCmdFactory(true, true)
+		if err != nil {
+			return err
+		}
+	}
+	defer cf.BroadcastClient.Close()
+
+	def, err := getChaincodeDefinition(cmd, cf)
+	if err != nil {
+		return err
+	}
+
+	spec, err := lifecycleInvocation(commit_cmdname, def)
+	if err != nil {
+		return err
+	}
+
+	if err = lifecycleTransaction(cf, chainID, spec); err != nil {
+		return fmt.Errorf("Error committing chaincode definition: %s", err)
+	}
+
+	return nil
+}
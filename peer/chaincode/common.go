@@ -17,16 +17,18 @@ limitations under the License.
 package chaincode
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"strings"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/cauthdsl"
 	"github.com/hyperledger/fabric/core/chaincode"
 	"github.com/hyperledger/fabric/core/chaincode/platforms"
-	"github.com/hyperledger/fabric/core/container"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/peer/common"
 	pcommon "github.com/hyperledger/fabric/protos/common"
@@ -54,19 +56,20 @@ func checkSpec(spec *pb.ChaincodeSpec) error {
 // getChaincodeDeploymentSpec get chaincode deployment spec given the chaincode spec
 func getChaincodeDeploymentSpec(spec *pb.ChaincodeSpec, crtPkg bool) (*pb.ChaincodeDeploymentSpec, error) {
 	var codePackageBytes []byte
+	var dependencies []*pb.ChaincodeDependency
 	if chaincode.IsDevMode() == false && crtPkg {
 		var err error
 		if err = checkSpec(spec); err != nil {
 			return nil, err
 		}
 
-		codePackageBytes, err = container.GetChaincodePackageBytes(spec)
+		codePackageBytes, dependencies, err = platforms.GetDeploymentPayloadWithDependencies(spec)
 		if err != nil {
 			err = fmt.Errorf("Error getting chaincode package bytes: %s", err)
 			return nil, err
 		}
 	}
-	chaincodeDeploymentSpec := &pb.ChaincodeDeploymentSpec{ChaincodeSpec: spec, CodePackage: codePackageBytes}
+	chaincodeDeploymentSpec := &pb.ChaincodeDeploymentSpec{ChaincodeSpec: spec, CodePackage: codePackageBytes, Dependencies: dependencies}
 	return chaincodeDeploymentSpec, nil
 }
 
@@ -306,3 +309,151 @@ func ChaincodeInvokeOrQuery(spec *pb.ChaincodeSpec, cID string, invoke bool, sig
 
 	return proposalResp, nil
 }
+
+// getChaincodeDefinition get the chaincode definition of the lifecycle commands from the cli cmd parameters
+func getChaincodeDefinition(cmd *cobra.Command, cf *ChaincodeCmdFactory) (*pb.ChaincodeDefinition, error) {
+	if chaincodeName == common.UndefinedParamValue {
+		return nil, fmt.Errorf("Must supply value for %s name parameter.", chainFuncName)
+	}
+	if chaincodeVersion == common.UndefinedParamValue {
+		return nil, fmt.Errorf("Chaincode version is not provided for %s", cmd.Name())
+	}
+	if sequence < 1 {
+		return nil, fmt.Errorf("Chaincode definition sequence is not provided for %s", cmd.Name())
+	}
+	if policy == common.UndefinedParamValue {
+		return nil, fmt.Errorf("Endorsement policy is not provided for %s", cmd.Name())
+	}
+	p, err := cauthdsl.FromString(policy)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid policy %s", policy)
+	}
+
+	def := &pb.ChaincodeDefinition{
+		Name:              chaincodeName,
+		Version:           chaincodeVersion,
+		Sequence:          sequence,
+		EndorsementPolicy: putils.MarshalOrPanic(p),
+		Escc:              "escc",
+		Vscc:              "vscc",
+	}
+	if escc != common.UndefinedParamValue {
+		def.Escc = escc
+	}
+	if vscc != common.UndefinedParamValue {
+		def.Vscc = vscc
+	}
+
+	if packageHash != "" {
+		def.PackageHash, err = hex.DecodeString(packageHash)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid package hash %s: %s", packageHash, err)
+		}
+	} else {
+		def.PackageHash, err = getInstalledPackageHash(cf)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return def, nil
+}
+
+// getInstalledPackageHash returns the hash of the package of the chaincode installed on the peer
+func getInstalledPackageHash(cf *ChaincodeCmdFactory) ([]byte, error) {
+	spec := &pb.ChaincodeSpec{
+		Type:        pb.ChaincodeSpec_GOLANG,
+		ChaincodeId: &pb.ChaincodeID{Name: "lscc"},
+		Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte("getinstalledchaincodes")}},
+	}
+	proposalResponse, err := lifecycleProposal(cf, "", spec)
+	if err != nil {
+		return nil, err
+	}
+
+	cqr := &pb.ChaincodeQueryResponse{}
+	if err = proto.Unmarshal(proposalResponse.Response.Payload, cqr); err != nil {
+		return nil, fmt.Errorf("Error unmarshalling the installed chaincodes: %s", err)
+	}
+	for _, ccInfo := range cqr.Chaincodes {
+		if ccInfo.Name == chaincodeName && ccInfo.Version == chaincodeVersion {
+			return ccInfo.Id, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Chaincode %s:%s is not installed on the peer, the package hash must be provided", chaincodeName, chaincodeVersion)
+}
+
+// lifecycleInvocation returns the spec of the invocation of a function of the lifecycle system chaincode on a definition
+func lifecycleInvocation(function string, def *pb.ChaincodeDefinition) (*pb.ChaincodeSpec, error) {
+	defBytes, err := proto.Marshal(def)
+	if err != nil {
+		return nil, fmt.Errorf("Error marshalling chaincode definition: %s", err)
+	}
+
+	return &pb.ChaincodeSpec{
+		Type:        pb.ChaincodeSpec_GOLANG,
+		ChaincodeId: &pb.ChaincodeID{Name: ccprovider.LifecycleSysCC},
+		Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte(function), defBytes}},
+	}, nil
+}
+
+// lifecycleProposal sends a proposal invoking a lifecycle system chaincode to the peer, and returns its
+// successful response
+func lifecycleProposal(cf *ChaincodeCmdFactory, cID string, spec *pb.ChaincodeSpec) (*pb.ProposalResponse, error) {
+	_, proposalResponse, err := lifecycleEndorse(cf, cID, spec)
+	if err != nil {
+		return nil, err
+	}
+	if proposalResponse == nil || proposalResponse.Response == nil {
+		return nil, errors.New("Received an empty proposal response")
+	}
+	if proposalResponse.Response.Status != 200 {
+		return nil, fmt.Errorf("Proposal response was not successful, error code %d, msg %s", proposalResponse.Response.Status, proposalResponse.Response.Message)
+	}
+
+	return proposalResponse, nil
+}
+
+// lifecycleTransaction endorses a proposal invoking a lifecycle system chaincode and sends the
+// resulting transaction for ordering
+func lifecycleTransaction(cf *ChaincodeCmdFactory, cID string, spec *pb.ChaincodeSpec) error {
+	prop, proposalResponse, err := lifecycleEndorse(cf, cID, spec)
+	if err != nil {
+		return err
+	}
+
+	// assemble a signed transaction (it's an Envelope message)
+	env, err := putils.CreateSignedTx(prop, cf.Signer, proposalResponse)
+	if err != nil {
+		return fmt.Errorf("Could not assemble transaction, err %s", err)
+	}
+
+	logger.Debug("Send signed envelope to orderer")
+	return cf.BroadcastClient.Send(env)
+}
+
+func lifecycleEndorse(cf *ChaincodeCmdFactory, cID string, spec *pb.ChaincodeSpec) (*pb.Proposal, *pb.ProposalResponse, error) {
+	creator, err := cf.Signer.Serialize()
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error serializing identity for %s: %s", cf.Signer.GetIdentifier(), err)
+	}
+
+	funcName := string(spec.Input.Args[0])
+	prop, _, err := putils.CreateProposalFromCIS(pcommon.HeaderType_ENDORSER_TRANSACTION, cID, &pb.ChaincodeInvocationSpec{ChaincodeSpec: spec}, creator)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error creating proposal %s: %s", funcName, err)
+	}
+
+	signedProp, err := putils.GetSignedProposal(prop, cf.Signer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error creating signed proposal %s: %s", funcName, err)
+	}
+
+	proposalResponse, err := cf.EndorserClient.ProcessProposal(context.Background(), signedProp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error endorsing %s: %s", funcName, err)
+	}
+
+	return prop, proposalResponse, nil
+}
@@ -35,6 +35,8 @@ func Cmd() *cobra.Command {
 	nodeCmd.AddCommand(startCmd())
 	nodeCmd.AddCommand(statusCmd())
 	nodeCmd.AddCommand(stopCmd())
+	nodeCmd.AddCommand(snapshotCmd())
+	nodeCmd.AddCommand(verifyLedgerCmd())
 
 	return nodeCmd
 }
@@ -0,0 +1,98 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	snapshotChainID string
+	snapshotDir     string
+)
+
+func snapshotCmd() *cobra.Command {
+	flags := nodeSnapshotCmd.PersistentFlags()
+	flags.StringVarP(&snapshotChainID, "chain", "c", "", "The chain ID of the ledger to export")
+	flags.StringVarP(&snapshotDir, "snapshotdir", "d", "", "Directory to export the snapshot to or to create the ledger from")
+
+	nodeSnapshotCmd.AddCommand(nodeSnapshotExportCmd)
+	nodeSnapshotCmd.AddCommand(nodeSnapshotBootstrapCmd)
+	return nodeSnapshotCmd
+}
+
+var nodeSnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Exports a ledger snapshot or creates a ledger from a snapshot.",
+	Long: `Exports a snapshot of a ledger or creates a ledger from a snapshot.
+These are offline operations and the peer should be stopped while performing them.`,
+}
+
+var nodeSnapshotExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exports a snapshot of the ledger at its current height.",
+	Long:  `Exports a snapshot of the state, history and last block of the ledger at its current height.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exportSnapshot()
+	},
+}
+
+var nodeSnapshotBootstrapCmd = &cobra.Command{
+	Use:   "bootstrap",
+	Short: "Creates a ledger from a snapshot.",
+	Long: `Creates a ledger from a snapshot exported by another peer.
+Once the peer is started, the ledger resumes committing blocks from the snapshot height.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return bootstrapFromSnapshot()
+	},
+}
+
+func exportSnapshot() error {
+	if snapshotChainID == "" {
+		return fmt.Errorf("Must supply the chain ID of the ledger to export")
+	}
+	if snapshotDir == "" {
+		return fmt.Errorf("Must supply the snapshot directory")
+	}
+	ledgermgmt.Initialize()
+	defer ledgermgmt.Close()
+	l, err := ledgermgmt.OpenLedger(snapshotChainID)
+	if err != nil {
+		return fmt.Errorf("Error opening ledger [%s]: %s", snapshotChainID, err)
+	}
+	if err = l.ExportSnapshot(snapshotDir); err != nil {
+		return fmt.Errorf("Error exporting snapshot of ledger [%s]: %s", snapshotChainID, err)
+	}
+	logger.Infof("Exported snapshot of ledger [%s] to [%s]", snapshotChainID, snapshotDir)
+	return nil
+}
+
+func bootstrapFromSnapshot() error {
+	if snapshotDir == "" {
+		return fmt.Errorf("Must supply the snapshot directory")
+	}
+	ledgermgmt.Initialize()
+	defer ledgermgmt.Close()
+	if _, err := ledgermgmt.CreateLedgerFromSnapshot(snapshotDir); err != nil {
+		return fmt.Errorf("Error creating ledger from snapshot [%s]: %s", snapshotDir, err)
+	}
+	logger.Infof("Created ledger from snapshot [%s]", snapshotDir)
+	return nil
+}
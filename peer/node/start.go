@@ -36,11 +36,14 @@ import (
 	"github.com/hyperledger/fabric/core"
 	"github.com/hyperledger/fabric/core/chaincode"
 	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/config"
 	"github.com/hyperledger/fabric/core/endorser"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/core/scc"
+	"github.com/hyperledger/fabric/core/scc/lscc"
 	"github.com/hyperledger/fabric/events/producer"
 	"github.com/hyperledger/fabric/gossip/service"
 	"github.com/hyperledger/fabric/msp/mgmt"
@@ -93,7 +96,10 @@ func initSysCCs() {
 }
 
 func serve(args []string) error {
-	ledgermgmt.Initialize()
+	ledgermgmt.InitializeWithStateListeners(map[string]ledger.StateListener{
+		"lscc":                    lscc.NewChaincodeIndexCreator(),
+		ccprovider.LifecycleSysCC: lscc.NewLifecycleIndexCreator(),
+	})
 	// Parameter overrides must be processed before any paramaters are
 	// cached. Failures to cache cause the server to terminate immediately.
 	if chaincodeDevMode {
@@ -149,6 +155,9 @@ func serve(args []string) error {
 	serverEndorser := endorser.NewEndorserServer()
 	pb.RegisterEndorserServer(peerServer.Server(), serverEndorser)
 
+	// Register the Deliver server serving the committed blocks
+	pb.RegisterDeliverServer(peerServer.Server(), peer.NewDeliverEventsServer())
+
 	// Initialize gossip component
 	bootstrap := viper.GetStringSlice("peer.gossip.bootstrap")
 
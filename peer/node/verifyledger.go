@@ -0,0 +1,134 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package node
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	verifyChainID    string
+	repair           bool
+	rebuildIndex     bool
+	rebuildStateDB   bool
+	rebuildHistoryDB bool
+)
+
+func verifyLedgerCmd() *cobra.Command {
+	flags := nodeVerifyLedgerCmd.Flags()
+	flags.StringVarP(&verifyChainID, "chain", "c", "", "The chain ID of the ledger to verify. All the ledgers are verified if not specified")
+	flags.BoolVar(&repair, "repair", false, "Rebuild the stores that are found inconsistent from the block files")
+	flags.BoolVar(&rebuildIndex, "rebuild-index", false, "Rebuild the block index from the block files")
+	flags.BoolVar(&rebuildStateDB, "rebuild-statedb", false, "Rebuild the state database from the block files, keeping the private data of the collections")
+	flags.BoolVar(&rebuildHistoryDB, "rebuild-historydb", false, "Rebuild the history database from the block files")
+	return nodeVerifyLedgerCmd
+}
+
+var nodeVerifyLedgerCmd = &cobra.Command{
+	Use:   "verify-ledger",
+	Short: "Verifies the integrity of the ledgers and optionally repairs them.",
+	Long: `Verifies the hash chaining and the data hashes of the blocks in the block files of the ledgers and
+cross-checks the block index, the state database and the history database against the block files.
+The stores that are found inconsistent, or the ones that are explicitly requested, can be rebuilt from the block files.
+This is an offline operation and the peer should be stopped while performing it.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return verifyLedgers()
+	},
+}
+
+func verifyLedgers() error {
+	ledgermgmt.Initialize()
+	defer ledgermgmt.Close()
+	ledgerIDs := []string{verifyChainID}
+	if verifyChainID == "" {
+		var err error
+		if ledgerIDs, err = ledgermgmt.GetLedgerIDs(); err != nil {
+			return fmt.Errorf("Error retrieving the ids of the ledgers: %s", err)
+		}
+	}
+	var inconsistentLedgerIDs []string
+	for _, ledgerID := range ledgerIDs {
+		consistent, err := verifyLedger(ledgerID)
+		if err != nil {
+			return err
+		}
+		if !consistent {
+			inconsistentLedgerIDs = append(inconsistentLedgerIDs, ledgerID)
+		}
+	}
+	if len(inconsistentLedgerIDs) > 0 {
+		return fmt.Errorf("Ledger(s) %v are inconsistent", inconsistentLedgerIDs)
+	}
+	return nil
+}
+
+// verifyLedger verifies the ledger and repairs it, if requested. It returns whether the ledger is consistent at the end
+func verifyLedger(ledgerID string) (bool, error) {
+	report, err := ledgermgmt.VerifyLedger(ledgerID)
+	if err != nil {
+		return false, fmt.Errorf("Error verifying ledger [%s]: %s", ledgerID, err)
+	}
+	printIntegrityReport(report)
+	options := &ledger.RepairOptions{
+		RebuildBlockIndex: rebuildIndex,
+		RebuildStateDB:    rebuildStateDB,
+		RebuildHistoryDB:  rebuildHistoryDB,
+	}
+	if repair {
+		reportOptions := report.RepairOptions()
+		options.RebuildBlockIndex = options.RebuildBlockIndex || reportOptions.RebuildBlockIndex
+		options.RebuildStateDB = options.RebuildStateDB || reportOptions.RebuildStateDB
+		options.RebuildHistoryDB = options.RebuildHistoryDB || reportOptions.RebuildHistoryDB
+	}
+	if !options.RebuildBlockIndex && !options.RebuildStateDB && !options.RebuildHistoryDB {
+		return report.IsConsistent(), nil
+	}
+	if len(report.BlockIssues) > 0 {
+		return false, fmt.Errorf("Cannot repair ledger [%s] because its block files are inconsistent. "+
+			"The ledger needs to be recovered from another peer", ledgerID)
+	}
+	if err = ledgermgmt.RepairLedger(ledgerID, options); err != nil {
+		return false, fmt.Errorf("Error repairing ledger [%s]: %s", ledgerID, err)
+	}
+	if report, err = ledgermgmt.VerifyLedger(ledgerID); err != nil {
+		return false, fmt.Errorf("Error verifying ledger [%s] after repair: %s", ledgerID, err)
+	}
+	fmt.Printf("After repair:\n")
+	printIntegrityReport(report)
+	return report.IsConsistent(), nil
+}
+
+func printIntegrityReport(report *ledger.IntegrityReport) {
+	if report.IsConsistent() {
+		fmt.Printf("Ledger [%s] at height [%d] is consistent\n", report.LedgerID, report.Height)
+		return
+	}
+	fmt.Printf("Ledger [%s] at height [%d] is inconsistent\n", report.LedgerID, report.Height)
+	printIssues("Block files", report.BlockIssues)
+	printIssues("Block index", report.BlockIndexIssues)
+	printIssues("State database", report.StateDBIssues)
+	printIssues("History database", report.HistoryDBIssues)
+}
+
+func printIssues(store string, issues []string) {
+	for _, issue := range issues {
+		fmt.Printf("  %s: %s\n", store, issue)
+	}
+}
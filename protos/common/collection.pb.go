@@ -0,0 +1,289 @@
+// Code generated by protoc-gen-go.
+// source: common/collection.proto
+// DO NOT EDIT!
+
+package common
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// CollectionConfigPackage represents an array of CollectionConfig
+// messages; the extra struct is required because repeated oneof is
+// forbidden by the protobuf syntax
+type CollectionConfigPackage struct {
+	Config []*CollectionConfig `protobuf:"bytes,1,rep,name=config" json:"config,omitempty"`
+}
+
+func (m *CollectionConfigPackage) Reset()                    { *m = CollectionConfigPackage{} }
+func (m *CollectionConfigPackage) String() string            { return proto.CompactTextString(m) }
+func (*CollectionConfigPackage) ProtoMessage()               {}
+func (*CollectionConfigPackage) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{0} }
+
+func (m *CollectionConfigPackage) GetConfig() []*CollectionConfig {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+// CollectionConfig defines the configuration of a collection object;
+// it currently contains a single, static type.
+// Dynamic collections are deferred.
+type CollectionConfig struct {
+	// Types that are valid to be assigned to Payload:
+	//	*CollectionConfig_StaticCollectionConfig
+	Payload isCollectionConfig_Payload `protobuf_oneof:"payload"`
+}
+
+func (m *CollectionConfig) Reset()                    { *m = CollectionConfig{} }
+func (m *CollectionConfig) String() string            { return proto.CompactTextString(m) }
+func (*CollectionConfig) ProtoMessage()               {}
+func (*CollectionConfig) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{1} }
+
+type isCollectionConfig_Payload interface{ isCollectionConfig_Payload() }
+
+type CollectionConfig_StaticCollectionConfig struct {
+	StaticCollectionConfig *StaticCollectionConfig `protobuf:"bytes,1,opt,name=static_collection_config,json=staticCollectionConfig,oneof"`
+}
+
+func (*CollectionConfig_StaticCollectionConfig) isCollectionConfig_Payload() {}
+
+func (m *CollectionConfig) GetPayload() isCollectionConfig_Payload {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *CollectionConfig) GetStaticCollectionConfig() *StaticCollectionConfig {
+	if x, ok := m.GetPayload().(*CollectionConfig_StaticCollectionConfig); ok {
+		return x.StaticCollectionConfig
+	}
+	return nil
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*CollectionConfig) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _CollectionConfig_OneofMarshaler, _CollectionConfig_OneofUnmarshaler, _CollectionConfig_OneofSizer, []interface{}{
+		(*CollectionConfig_StaticCollectionConfig)(nil),
+	}
+}
+
+func _CollectionConfig_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*CollectionConfig)
+	// payload
+	switch x := m.Payload.(type) {
+	case *CollectionConfig_StaticCollectionConfig:
+		b.EncodeVarint(1<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.StaticCollectionConfig); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		return fmt.Errorf("CollectionConfig.Payload has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _CollectionConfig_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*CollectionConfig)
+	switch tag {
+	case 1: // payload.static_collection_config
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(StaticCollectionConfig)
+		err := b.DecodeMessage(msg)
+		m.Payload = &CollectionConfig_StaticCollectionConfig{msg}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _CollectionConfig_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*CollectionConfig)
+	// payload
+	switch x := m.Payload.(type) {
+	case *CollectionConfig_StaticCollectionConfig:
+		s := proto.Size(x.StaticCollectionConfig)
+		n += proto.SizeVarint(1<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+// StaticCollectionConfig constitutes the configuration parameters of a
+// static collection object. Static collections are collections that are
+// known at chaincode instantiation time, and that cannot be changed.
+// Dynamic collections are deferred.
+type StaticCollectionConfig struct {
+	// the name of the collection inside the denoted chaincode
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// a reference to a policy residing / managed in the config block
+	// to define which orgs have access to this collection’s private data
+	MemberOrgsPolicy *CollectionPolicyConfig `protobuf:"bytes,2,opt,name=member_orgs_policy,json=memberOrgsPolicy" json:"member_orgs_policy,omitempty"`
+	// The minimum number of peers private data will be sent to upon
+	// endorsement. The endorsement would fail if dissemination to at least
+	// this number of peers is not achieved.
+	RequiredPeerCount int32 `protobuf:"varint,3,opt,name=required_peer_count,json=requiredPeerCount" json:"required_peer_count,omitempty"`
+	// The maximum number of peers that private data will be sent to
+	// upon endorsement. This number has to be bigger than required_peer_count.
+	MaximumPeerCount int32 `protobuf:"varint,4,opt,name=maximum_peer_count,json=maximumPeerCount" json:"maximum_peer_count,omitempty"`
+}
+
+func (m *StaticCollectionConfig) Reset()                    { *m = StaticCollectionConfig{} }
+func (m *StaticCollectionConfig) String() string            { return proto.CompactTextString(m) }
+func (*StaticCollectionConfig) ProtoMessage()               {}
+func (*StaticCollectionConfig) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{2} }
+
+func (m *StaticCollectionConfig) GetMemberOrgsPolicy() *CollectionPolicyConfig {
+	if m != nil {
+		return m.MemberOrgsPolicy
+	}
+	return nil
+}
+
+// Collection policy configuration. Initially, the configuration can only
+// contain a SignaturePolicy. In the future, the SignaturePolicy may be a
+// more general Policy. Instead of containing the actual policy, the
+// configuration may in the future contain a string reference to a policy.
+type CollectionPolicyConfig struct {
+	// Types that are valid to be assigned to Payload:
+	//	*CollectionPolicyConfig_SignaturePolicy
+	Payload isCollectionPolicyConfig_Payload `protobuf_oneof:"payload"`
+}
+
+func (m *CollectionPolicyConfig) Reset()                    { *m = CollectionPolicyConfig{} }
+func (m *CollectionPolicyConfig) String() string            { return proto.CompactTextString(m) }
+func (*CollectionPolicyConfig) ProtoMessage()               {}
+func (*CollectionPolicyConfig) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{3} }
+
+type isCollectionPolicyConfig_Payload interface{ isCollectionPolicyConfig_Payload() }
+
+type CollectionPolicyConfig_SignaturePolicy struct {
+	SignaturePolicy *SignaturePolicyEnvelope `protobuf:"bytes,1,opt,name=signature_policy,json=signaturePolicy,oneof"`
+}
+
+func (*CollectionPolicyConfig_SignaturePolicy) isCollectionPolicyConfig_Payload() {}
+
+func (m *CollectionPolicyConfig) GetPayload() isCollectionPolicyConfig_Payload {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *CollectionPolicyConfig) GetSignaturePolicy() *SignaturePolicyEnvelope {
+	if x, ok := m.GetPayload().(*CollectionPolicyConfig_SignaturePolicy); ok {
+		return x.SignaturePolicy
+	}
+	return nil
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*CollectionPolicyConfig) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _CollectionPolicyConfig_OneofMarshaler, _CollectionPolicyConfig_OneofUnmarshaler, _CollectionPolicyConfig_OneofSizer, []interface{}{
+		(*CollectionPolicyConfig_SignaturePolicy)(nil),
+	}
+}
+
+func _CollectionPolicyConfig_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*CollectionPolicyConfig)
+	// payload
+	switch x := m.Payload.(type) {
+	case *CollectionPolicyConfig_SignaturePolicy:
+		b.EncodeVarint(1<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.SignaturePolicy); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		return fmt.Errorf("CollectionPolicyConfig.Payload has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _CollectionPolicyConfig_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*CollectionPolicyConfig)
+	switch tag {
+	case 1: // payload.signature_policy
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(SignaturePolicyEnvelope)
+		err := b.DecodeMessage(msg)
+		m.Payload = &CollectionPolicyConfig_SignaturePolicy{msg}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _CollectionPolicyConfig_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*CollectionPolicyConfig)
+	// payload
+	switch x := m.Payload.(type) {
+	case *CollectionPolicyConfig_SignaturePolicy:
+		s := proto.Size(x.SignaturePolicy)
+		n += proto.SizeVarint(1<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+func init() {
+	proto.RegisterType((*CollectionConfigPackage)(nil), "common.CollectionConfigPackage")
+	proto.RegisterType((*CollectionConfig)(nil), "common.CollectionConfig")
+	proto.RegisterType((*StaticCollectionConfig)(nil), "common.StaticCollectionConfig")
+	proto.RegisterType((*CollectionPolicyConfig)(nil), "common.CollectionPolicyConfig")
+}
+
+func init() { proto.RegisterFile("common/collection.proto", fileDescriptor5) }
+
+var fileDescriptor5 = []byte{
+	// 362 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x6c, 0x92, 0xcd, 0x8e, 0xda, 0x30,
+	0x14, 0x85, 0x49, 0xa1, 0x54, 0x98, 0x45, 0x53, 0x57, 0x85, 0xa8, 0x8b, 0x16, 0x45, 0x5d, 0x20,
+	0xb5, 0x4a, 0x2a, 0xe6, 0x0d, 0x40, 0x23, 0x21, 0x0d, 0xd2, 0xa0, 0xb0, 0x63, 0x13, 0x39, 0xe6,
+	0x62, 0xac, 0x89, 0xe3, 0x60, 0x27, 0xa3, 0xc9, 0x9b, 0xce, 0xe3, 0x8c, 0xb0, 0x93, 0xf0, 0x23,
+	0x76, 0xd8, 0xdf, 0x77, 0x0e, 0xd7, 0x57, 0x41, 0x63, 0x2a, 0x85, 0x90, 0x59, 0x48, 0x65, 0x9a,
+	0x02, 0x2d, 0xb8, 0xcc, 0x82, 0x5c, 0xc9, 0x42, 0xe2, 0xbe, 0x05, 0x3f, 0x7f, 0xd4, 0x42, 0x2e,
+	0x53, 0x4e, 0x39, 0x68, 0x8b, 0xfd, 0x27, 0x34, 0x5e, 0xb4, 0x91, 0x85, 0xcc, 0xf6, 0x9c, 0xad,
+	0x09, 0x7d, 0x21, 0x0c, 0xf0, 0x7f, 0xd4, 0xa7, 0xe6, 0xc2, 0x73, 0x26, 0xdd, 0xe9, 0x70, 0xe6,
+	0x05, 0xb6, 0x22, 0xb8, 0x0d, 0x44, 0xb5, 0xe7, 0x57, 0xc8, 0xbd, 0x65, 0x78, 0x8b, 0x3c, 0x5d,
+	0x90, 0x82, 0xd3, 0xf8, 0x3c, 0x5a, 0xdc, 0xf6, 0x3a, 0xd3, 0xe1, 0xec, 0x57, 0xd3, 0xbb, 0x31,
+	0xde, 0x6d, 0xc3, 0xb2, 0x13, 0x8d, 0xf4, 0x5d, 0x32, 0x1f, 0xa0, 0x2f, 0x39, 0xa9, 0x52, 0x49,
+	0x76, 0xfe, 0xbb, 0x83, 0x46, 0xf7, 0xf3, 0x18, 0xa3, 0x5e, 0x46, 0x04, 0x98, 0x7f, 0x1b, 0x44,
+	0xe6, 0x37, 0x5e, 0x21, 0x2c, 0x40, 0x24, 0xa0, 0x62, 0xa9, 0x98, 0x8e, 0xcd, 0x52, 0x2a, 0xef,
+	0xd3, 0xf5, 0x3c, 0xe7, 0xa6, 0xb5, 0xe1, 0xf5, 0x6b, 0x5d, 0x9b, 0x7c, 0x56, 0x4c, 0xdb, 0x7b,
+	0x1c, 0xa0, 0xef, 0x0a, 0x8e, 0x25, 0x57, 0xb0, 0x8b, 0x73, 0x00, 0x15, 0x53, 0x59, 0x66, 0x85,
+	0xd7, 0x9d, 0x38, 0xd3, 0xcf, 0xd1, 0xb7, 0x06, 0xad, 0x01, 0xd4, 0xe2, 0x04, 0xf0, 0x3f, 0x84,
+	0x05, 0x79, 0xe3, 0xa2, 0x14, 0x97, 0x7a, 0xcf, 0xe8, 0x6e, 0x4d, 0x5a, 0xdb, 0x3f, 0xa2, 0xd1,
+	0xfd, 0x49, 0xf0, 0x0a, 0xb9, 0x9a, 0xb3, 0x8c, 0x14, 0xa5, 0x82, 0xe6, 0x0d, 0x76, 0xa7, 0xbf,
+	0xdb, 0x9d, 0x36, 0xdc, 0x06, 0x1f, 0xb3, 0x57, 0x48, 0x65, 0x0e, 0xcb, 0x4e, 0xf4, 0x55, 0x5f,
+	0xa3, 0x8b, 0x6d, 0xce, 0x37, 0xe8, 0x8f, 0x54, 0x2c, 0x38, 0x54, 0x39, 0xa8, 0x14, 0x76, 0x0c,
+	0x54, 0xb0, 0x27, 0x89, 0xe2, 0xd4, 0x7e, 0x35, 0xba, 0x6e, 0xdf, 0xfe, 0x65, 0xbc, 0x38, 0x94,
+	0xc9, 0xe9, 0x18, 0x5e, 0xc8, 0xa1, 0x95, 0x43, 0x2b, 0x87, 0x56, 0x4e, 0xfa, 0xe6, 0xf8, 0xf0,
+	0x31, 0x00, 0xc4, 0x26, 0x44, 0x11, 0xab, 0x02, 0x00, 0x00,
+}
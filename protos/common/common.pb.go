@@ -6,6 +6,7 @@
 Package common is a generated protocol buffer package.
 
 It is generated from these files:
+	common/collection.proto
 	common/common.proto
 	common/configtx.proto
 	common/configuration.proto
@@ -13,6 +14,10 @@ It is generated from these files:
 	common/policies.proto
 
 It has these top-level messages:
+	CollectionConfigPackage
+	CollectionConfig
+	StaticCollectionConfig
+	CollectionPolicyConfig
 	LastConfig
 	Metadata
 	MetadataSignature
@@ -148,6 +153,8 @@ const (
 	BlockMetadataIndex_LAST_CONFIG         BlockMetadataIndex = 1
 	BlockMetadataIndex_TRANSACTIONS_FILTER BlockMetadataIndex = 2
 	BlockMetadataIndex_ORDERER             BlockMetadataIndex = 3
+	// e.g. For Kafka, this is where we store the last offset written to the local ledger.
+	BlockMetadataIndex_TRANSACTIONS_COMMIT_ORDER BlockMetadataIndex = 4
 )
 
 var BlockMetadataIndex_name = map[int32]string{
@@ -155,12 +162,14 @@ var BlockMetadataIndex_name = map[int32]string{
 	1: "LAST_CONFIG",
 	2: "TRANSACTIONS_FILTER",
 	3: "ORDERER",
+	4: "TRANSACTIONS_COMMIT_ORDER",
 }
 var BlockMetadataIndex_value = map[string]int32{
-	"SIGNATURES":          0,
-	"LAST_CONFIG":         1,
-	"TRANSACTIONS_FILTER": 2,
-	"ORDERER":             3,
+	"SIGNATURES":                0,
+	"LAST_CONFIG":               1,
+	"TRANSACTIONS_FILTER":       2,
+	"ORDERER":                   3,
+	"TRANSACTIONS_COMMIT_ORDER": 4,
 }
 
 func (x BlockMetadataIndex) String() string {
@@ -391,62 +400,62 @@ func init() {
 func init() { proto.RegisterFile("common/common.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 900 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x84, 0x55, 0xdf, 0x6e, 0xe3, 0xc4,
-	0x1b, 0xad, 0xe3, 0xfc, 0x69, 0xbe, 0x34, 0xad, 0x3b, 0xd9, 0xfe, 0xd6, 0xbf, 0xc2, 0x6a, 0x23,
-	0xc3, 0xa2, 0xd2, 0x4a, 0x89, 0x28, 0x37, 0x70, 0xe9, 0xd8, 0x93, 0xd6, 0x6a, 0xd6, 0x2e, 0x63,
-	0x67, 0x11, 0xbb, 0x48, 0x96, 0x93, 0x4c, 0x13, 0x8b, 0xc4, 0x8e, 0x6c, 0xa7, 0x6a, 0x5f, 0x02,
-	0x21, 0xc1, 0x0d, 0x17, 0xbc, 0x00, 0x4f, 0xc2, 0x5b, 0xf0, 0x12, 0x48, 0xdc, 0xa2, 0xf1, 0x8c,
-	0xbd, 0x49, 0x59, 0x89, 0xab, 0xcc, 0x39, 0x73, 0x3c, 0xdf, 0x99, 0xf3, 0x7d, 0xb1, 0xa1, 0x33,
-	0x8d, 0x57, 0xab, 0x38, 0xea, 0xf3, 0x9f, 0xde, 0x3a, 0x89, 0xb3, 0x18, 0xd5, 0x39, 0x3a, 0x7d,
-	0x39, 0x8f, 0xe3, 0xf9, 0x92, 0xf6, 0x73, 0x76, 0xb2, 0xb9, 0xeb, 0x67, 0xe1, 0x8a, 0xa6, 0x59,
-	0xb0, 0x5a, 0x73, 0xa1, 0xa6, 0x01, 0x8c, 0x82, 0x34, 0x33, 0xe2, 0xe8, 0x2e, 0x9c, 0xa3, 0x67,
-	0x50, 0x0b, 0xa3, 0x19, 0x7d, 0x50, 0xa5, 0xae, 0x74, 0x56, 0x25, 0x1c, 0x68, 0xef, 0x60, 0xff,
-	0x35, 0xcd, 0x82, 0x59, 0x90, 0x05, 0x4c, 0x71, 0x1f, 0x2c, 0x37, 0x34, 0x57, 0x1c, 0x10, 0x0e,
-	0xd0, 0xd7, 0x00, 0x69, 0x38, 0x8f, 0x82, 0x6c, 0x93, 0xd0, 0x54, 0xad, 0x74, 0xe5, 0xb3, 0xd6,
-	0xe5, 0xff, 0x7b, 0xc2, 0x51, 0xf1, 0xac, 0x5b, 0x28, 0xc8, 0x96, 0x58, 0xfb, 0x1e, 0x8e, 0xff,
-	0x25, 0x40, 0x9f, 0x83, 0x52, 0x4a, 0xfc, 0x05, 0x0d, 0x66, 0x34, 0x11, 0x05, 0x8f, 0x4a, 0xfe,
-	0x3a, 0xa7, 0xd1, 0xc7, 0xd0, 0x2c, 0x29, 0xb5, 0x92, 0x6b, 0xde, 0x13, 0xda, 0x5b, 0xa8, 0x0b,
-	0xdd, 0x2b, 0x38, 0x9c, 0x2e, 0x82, 0x28, 0xa2, 0xcb, 0xdd, 0x03, 0xdb, 0x82, 0x15, 0xb2, 0x0f,
-	0x55, 0xae, 0x7c, 0xb0, 0xb2, 0xf6, 0xa7, 0x04, 0x6d, 0x63, 0xe7, 0x61, 0x04, 0xd5, 0xec, 0x71,
-	0xcd, 0xb3, 0xa9, 0x91, 0x7c, 0x8d, 0x54, 0x68, 0xdc, 0xd3, 0x24, 0x0d, 0xe3, 0x28, 0x3f, 0xa7,
-	0x46, 0x0a, 0x88, 0xbe, 0x82, 0x66, 0xd9, 0x0d, 0x55, 0xee, 0x4a, 0x67, 0xad, 0xcb, 0xd3, 0x1e,
-	0xef, 0x57, 0xaf, 0xe8, 0x57, 0xcf, 0x2b, 0x14, 0xe4, 0xbd, 0x18, 0xbd, 0x00, 0x28, 0xee, 0x12,
-	0xce, 0xd4, 0x6a, 0x57, 0x3a, 0x6b, 0x92, 0xa6, 0x60, 0xac, 0x19, 0xea, 0x40, 0x2d, 0x7b, 0x60,
-	0x3b, 0xb5, 0x7c, 0xa7, 0x9a, 0x3d, 0x58, 0x33, 0xd6, 0x38, 0xba, 0x8e, 0xa7, 0x0b, 0xb5, 0xce,
-	0x5b, 0x9b, 0x03, 0x96, 0x1e, 0x7d, 0xc8, 0x68, 0x94, 0xfb, 0x6b, 0xf0, 0xf4, 0x4a, 0x42, 0xd3,
-	0xe1, 0xc8, 0x7d, 0x12, 0xb7, 0x0a, 0x8d, 0x69, 0x42, 0x83, 0x2c, 0x2e, 0xf2, 0x2b, 0x20, 0x2b,
-	0x10, 0xc5, 0xd1, 0xb4, 0x68, 0x02, 0x07, 0x1a, 0x86, 0xc6, 0x6d, 0xf0, 0xb8, 0x8c, 0x83, 0x19,
-	0xfa, 0x0c, 0xea, 0x5b, 0xc9, 0xb7, 0x2e, 0x0f, 0x8b, 0x01, 0xe1, 0x47, 0x13, 0xb1, 0xcb, 0x52,
-	0x64, 0xd3, 0x20, 0xce, 0xc9, 0xd7, 0xda, 0x00, 0xf6, 0x71, 0x74, 0x4f, 0x97, 0x31, 0x4f, 0x74,
-	0xcd, 0x8f, 0x2c, 0x2c, 0x08, 0xf8, 0x1f, 0xb3, 0xf0, 0xa3, 0x04, 0xb5, 0xc1, 0x32, 0x9e, 0xfe,
-	0x80, 0x2e, 0x9e, 0x38, 0xe9, 0x14, 0x4e, 0xf2, 0xed, 0x27, 0x76, 0x5e, 0x6d, 0xd9, 0x69, 0x5d,
-	0x1e, 0xef, 0x48, 0xcd, 0x20, 0x0b, 0xb8, 0x43, 0xf4, 0x05, 0xec, 0xaf, 0xc4, 0x1c, 0x8b, 0x66,
-	0x9e, 0xec, 0x48, 0x8b, 0x21, 0x27, 0xa5, 0x4c, 0x9b, 0x43, 0x6b, 0xab, 0x20, 0xfa, 0x1f, 0xd4,
-	0xa3, 0xcd, 0x6a, 0x22, 0x5c, 0x55, 0x89, 0x40, 0xe8, 0x13, 0x68, 0xaf, 0x13, 0x7a, 0x1f, 0xc6,
-	0x9b, 0xd4, 0x5f, 0x04, 0xe9, 0x42, 0xdc, 0xec, 0xa0, 0x20, 0xaf, 0x83, 0x74, 0x81, 0x3e, 0x82,
-	0x26, 0x3b, 0x93, 0x0b, 0xe4, 0x5c, 0xb0, 0xcf, 0x08, 0xb6, 0xa9, 0xbd, 0x84, 0x66, 0x69, 0xb7,
-	0x8c, 0x57, 0xea, 0xca, 0x65, 0xbc, 0x17, 0xd0, 0xde, 0x31, 0x89, 0x4e, 0xb7, 0x6e, 0xc3, 0x85,
-	0x25, 0x3e, 0xff, 0x5d, 0x82, 0xba, 0x9b, 0x05, 0xd9, 0x26, 0x45, 0x2d, 0x68, 0x8c, 0xed, 0x1b,
-	0xdb, 0xf9, 0xd6, 0x56, 0xf6, 0xd0, 0x01, 0x34, 0xdc, 0xb1, 0x61, 0x60, 0xd7, 0x55, 0xfe, 0x90,
-	0x90, 0x02, 0xad, 0x81, 0x6e, 0xfa, 0x04, 0x7f, 0x33, 0xc6, 0xae, 0xa7, 0xfc, 0x24, 0xa3, 0x43,
-	0x68, 0x0e, 0x1d, 0x32, 0xb0, 0x4c, 0x13, 0xdb, 0xca, 0xcf, 0x39, 0xb6, 0x1d, 0xcf, 0x1f, 0x3a,
-	0x63, 0xdb, 0x54, 0x7e, 0x91, 0xd1, 0x0b, 0x50, 0x85, 0xda, 0xc7, 0xb6, 0x67, 0x79, 0xdf, 0xf9,
-	0x9e, 0xe3, 0xf8, 0x23, 0x9d, 0x5c, 0x61, 0xe5, 0x37, 0x19, 0x9d, 0xc2, 0x89, 0x65, 0x7b, 0x98,
-	0xd8, 0xfa, 0xc8, 0x77, 0x31, 0x79, 0x83, 0x89, 0x8f, 0x09, 0x71, 0x88, 0xf2, 0x97, 0x8c, 0x54,
-	0xe8, 0x30, 0xca, 0x32, 0xb0, 0x3f, 0xb6, 0xf5, 0x37, 0xba, 0x35, 0xd2, 0x07, 0x23, 0xac, 0xfc,
-	0x2d, 0x9f, 0xff, 0x2a, 0x01, 0xf0, 0x7c, 0x3d, 0xf6, 0x6f, 0x6c, 0x41, 0xe3, 0x35, 0x76, 0x5d,
-	0xfd, 0x0a, 0x2b, 0x7b, 0x08, 0xa0, 0x6e, 0x38, 0xf6, 0xd0, 0xba, 0x52, 0x24, 0x74, 0x0c, 0x6d,
-	0xbe, 0xf6, 0xc7, 0xb7, 0xa6, 0xee, 0x61, 0xa5, 0x82, 0x54, 0x78, 0x86, 0x6d, 0xd3, 0x21, 0x2e,
-	0x26, 0xbe, 0x47, 0x74, 0xdb, 0xd5, 0x0d, 0xcf, 0x72, 0x6c, 0x45, 0x46, 0xcf, 0xa1, 0xe3, 0x10,
-	0x13, 0x93, 0x27, 0x1b, 0x55, 0x74, 0x02, 0xc7, 0x26, 0x1e, 0x59, 0xcc, 0x9b, 0x8b, 0xf1, 0x8d,
-	0x6f, 0xd9, 0x43, 0x47, 0xa9, 0x31, 0xda, 0xb8, 0xd6, 0x2d, 0xdb, 0x70, 0x4c, 0xec, 0xdf, 0xea,
-	0xc6, 0x0d, 0xab, 0x5f, 0x3f, 0x7f, 0x07, 0x68, 0x27, 0x75, 0x8b, 0xbd, 0x6d, 0xd1, 0x21, 0x80,
-	0x6b, 0x5d, 0xd9, 0xba, 0x37, 0x26, 0xd8, 0x55, 0xf6, 0xd0, 0x11, 0xb4, 0x46, 0xba, 0xeb, 0xf9,
-	0xa5, 0xd5, 0xe7, 0xd0, 0xd9, 0xaa, 0xea, 0xfa, 0x43, 0x6b, 0xe4, 0x61, 0xa2, 0x54, 0xd8, 0xe5,
-	0x84, 0x2d, 0x45, 0x1e, 0xb8, 0xf0, 0x69, 0x9c, 0xcc, 0x7b, 0x8b, 0xc7, 0x35, 0x4d, 0x96, 0x74,
-	0x36, 0xa7, 0x49, 0xef, 0x2e, 0x98, 0x24, 0xe1, 0x94, 0xbf, 0x5b, 0x52, 0x31, 0x9c, 0x6f, 0x2f,
-	0xe6, 0x61, 0xb6, 0xd8, 0x4c, 0x18, 0xec, 0x6f, 0x89, 0xfb, 0x5c, 0xcc, 0x3f, 0x1c, 0xa9, 0xf8,
-	0xb8, 0x4c, 0xea, 0x39, 0xfc, 0xf2, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xa1, 0xcb, 0xe1, 0xb4,
-	0x74, 0x06, 0x00, 0x00,
+	// 908 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x84, 0x55, 0xd1, 0x6e, 0xe3, 0x44,
+	0x14, 0xad, 0xeb, 0xc4, 0x69, 0x6e, 0x9a, 0x76, 0x3a, 0xd9, 0xb2, 0xde, 0xc2, 0x6a, 0x23, 0xc3,
+	0xa2, 0xd2, 0x4a, 0x89, 0x28, 0x2f, 0xf0, 0xe8, 0xd8, 0x93, 0xd6, 0x6a, 0x6a, 0x97, 0xb1, 0xb3,
+	0x88, 0x05, 0xc9, 0x72, 0x92, 0x69, 0x12, 0x91, 0xd8, 0x91, 0xed, 0x54, 0x2d, 0x1f, 0x81, 0x90,
+	0xe0, 0x85, 0x07, 0x7e, 0x80, 0x2f, 0xe1, 0x2f, 0xf8, 0x09, 0x24, 0x5e, 0xd1, 0x78, 0x6c, 0x6f,
+	0x52, 0x56, 0xe2, 0x29, 0x73, 0xce, 0x1c, 0xcf, 0x3d, 0x73, 0xee, 0x8d, 0x0d, 0xad, 0x71, 0xb4,
+	0x5c, 0x46, 0x61, 0x57, 0xfc, 0x74, 0x56, 0x71, 0x94, 0x46, 0x58, 0x11, 0xe8, 0xe4, 0xd5, 0x34,
+	0x8a, 0xa6, 0x0b, 0xd6, 0xcd, 0xd8, 0xd1, 0xfa, 0xae, 0x9b, 0xce, 0x97, 0x2c, 0x49, 0x83, 0xe5,
+	0x4a, 0x08, 0x35, 0x0d, 0x60, 0x10, 0x24, 0xa9, 0x11, 0x85, 0x77, 0xf3, 0x29, 0x7e, 0x06, 0xd5,
+	0x79, 0x38, 0x61, 0x0f, 0xaa, 0xd4, 0x96, 0x4e, 0x2b, 0x54, 0x00, 0xed, 0x3b, 0xd8, 0xbb, 0x61,
+	0x69, 0x30, 0x09, 0xd2, 0x80, 0x2b, 0xee, 0x83, 0xc5, 0x9a, 0x65, 0x8a, 0x7d, 0x2a, 0x00, 0xfe,
+	0x0a, 0x20, 0x99, 0x4f, 0xc3, 0x20, 0x5d, 0xc7, 0x2c, 0x51, 0x77, 0xdb, 0xf2, 0x69, 0xe3, 0xe2,
+	0x45, 0x27, 0x77, 0x54, 0x3c, 0xeb, 0x16, 0x0a, 0xba, 0x21, 0xd6, 0xbe, 0x87, 0xa3, 0xff, 0x08,
+	0xf0, 0x67, 0x80, 0x4a, 0x89, 0x3f, 0x63, 0xc1, 0x84, 0xc5, 0x79, 0xc1, 0xc3, 0x92, 0xbf, 0xca,
+	0x68, 0xfc, 0x11, 0xd4, 0x4b, 0x4a, 0xdd, 0xcd, 0x34, 0xef, 0x08, 0xed, 0x2d, 0x28, 0xb9, 0xee,
+	0x35, 0x1c, 0x8c, 0x67, 0x41, 0x18, 0xb2, 0xc5, 0xf6, 0x81, 0xcd, 0x9c, 0xcd, 0x65, 0xef, 0xab,
+	0xbc, 0xfb, 0xde, 0xca, 0xda, 0x5f, 0x12, 0x34, 0x8d, 0xad, 0x87, 0x31, 0x54, 0xd2, 0xc7, 0x95,
+	0xc8, 0xa6, 0x4a, 0xb3, 0x35, 0x56, 0xa1, 0x76, 0xcf, 0xe2, 0x64, 0x1e, 0x85, 0xd9, 0x39, 0x55,
+	0x5a, 0x40, 0xfc, 0x25, 0xd4, 0xcb, 0x6e, 0xa8, 0x72, 0x5b, 0x3a, 0x6d, 0x5c, 0x9c, 0x74, 0x44,
+	0xbf, 0x3a, 0x45, 0xbf, 0x3a, 0x5e, 0xa1, 0xa0, 0xef, 0xc4, 0xf8, 0x25, 0x40, 0x71, 0x97, 0xf9,
+	0x44, 0xad, 0xb4, 0xa5, 0xd3, 0x3a, 0xad, 0xe7, 0x8c, 0x35, 0xc1, 0x2d, 0xa8, 0xa6, 0x0f, 0x7c,
+	0xa7, 0x9a, 0xed, 0x54, 0xd2, 0x07, 0x6b, 0xc2, 0x1b, 0xc7, 0x56, 0xd1, 0x78, 0xa6, 0x2a, 0xa2,
+	0xb5, 0x19, 0xe0, 0xe9, 0xb1, 0x87, 0x94, 0x85, 0x99, 0xbf, 0x9a, 0x48, 0xaf, 0x24, 0x34, 0x1d,
+	0x0e, 0xdd, 0x27, 0x71, 0xab, 0x50, 0x1b, 0xc7, 0x2c, 0x48, 0xa3, 0x22, 0xbf, 0x02, 0xf2, 0x02,
+	0x61, 0x14, 0x8e, 0x8b, 0x26, 0x08, 0xa0, 0x11, 0xa8, 0xdd, 0x06, 0x8f, 0x8b, 0x28, 0x98, 0xe0,
+	0x4f, 0x41, 0xd9, 0x48, 0xbe, 0x71, 0x71, 0x50, 0x0c, 0x88, 0x38, 0x9a, 0x2a, 0xb3, 0x32, 0x45,
+	0x3e, 0x0d, 0xf9, 0x39, 0xd9, 0x5a, 0xeb, 0xc1, 0x1e, 0x09, 0xef, 0xd9, 0x22, 0x12, 0x89, 0xae,
+	0xc4, 0x91, 0x85, 0x85, 0x1c, 0xfe, 0xcf, 0x2c, 0xfc, 0x24, 0x41, 0xb5, 0xb7, 0x88, 0xc6, 0x3f,
+	0xe0, 0xf3, 0x27, 0x4e, 0x5a, 0x85, 0x93, 0x6c, 0xfb, 0x89, 0x9d, 0xd7, 0x1b, 0x76, 0x1a, 0x17,
+	0x47, 0x5b, 0x52, 0x33, 0x48, 0x03, 0xe1, 0x10, 0x7f, 0x0e, 0x7b, 0xcb, 0x7c, 0x8e, 0xf3, 0x66,
+	0x1e, 0x6f, 0x49, 0x8b, 0x21, 0xa7, 0xa5, 0x4c, 0x9b, 0x42, 0x63, 0xa3, 0x20, 0xfe, 0x00, 0x94,
+	0x70, 0xbd, 0x1c, 0xe5, 0xae, 0x2a, 0x34, 0x47, 0xf8, 0x63, 0x68, 0xae, 0x62, 0x76, 0x3f, 0x8f,
+	0xd6, 0x89, 0x3f, 0x0b, 0x92, 0x59, 0x7e, 0xb3, 0xfd, 0x82, 0xbc, 0x0a, 0x92, 0x19, 0xfe, 0x10,
+	0xea, 0xfc, 0x4c, 0x21, 0x90, 0x33, 0xc1, 0x1e, 0x27, 0xf8, 0xa6, 0xf6, 0x0a, 0xea, 0xa5, 0xdd,
+	0x32, 0x5e, 0xa9, 0x2d, 0x97, 0xf1, 0x9e, 0x43, 0x73, 0xcb, 0x24, 0x3e, 0xd9, 0xb8, 0x8d, 0x10,
+	0x96, 0xf8, 0xec, 0x0f, 0x09, 0x14, 0x37, 0x0d, 0xd2, 0x75, 0x82, 0x1b, 0x50, 0x1b, 0xda, 0xd7,
+	0xb6, 0xf3, 0x8d, 0x8d, 0x76, 0xf0, 0x3e, 0xd4, 0xdc, 0xa1, 0x61, 0x10, 0xd7, 0x45, 0x7f, 0x4a,
+	0x18, 0x41, 0xa3, 0xa7, 0x9b, 0x3e, 0x25, 0x5f, 0x0f, 0x89, 0xeb, 0xa1, 0x9f, 0x65, 0x7c, 0x00,
+	0xf5, 0xbe, 0x43, 0x7b, 0x96, 0x69, 0x12, 0x1b, 0xfd, 0x92, 0x61, 0xdb, 0xf1, 0xfc, 0xbe, 0x33,
+	0xb4, 0x4d, 0xf4, 0xab, 0x8c, 0x5f, 0x82, 0x9a, 0xab, 0x7d, 0x62, 0x7b, 0x96, 0xf7, 0xad, 0xef,
+	0x39, 0x8e, 0x3f, 0xd0, 0xe9, 0x25, 0x41, 0xbf, 0xcb, 0xf8, 0x04, 0x8e, 0x2d, 0xdb, 0x23, 0xd4,
+	0xd6, 0x07, 0xbe, 0x4b, 0xe8, 0x1b, 0x42, 0x7d, 0x42, 0xa9, 0x43, 0xd1, 0xdf, 0x32, 0x56, 0xa1,
+	0xc5, 0x29, 0xcb, 0x20, 0xfe, 0xd0, 0xd6, 0xdf, 0xe8, 0xd6, 0x40, 0xef, 0x0d, 0x08, 0xfa, 0x47,
+	0x3e, 0xfb, 0x4d, 0x02, 0x10, 0xf9, 0x7a, 0xfc, 0xdf, 0xd8, 0x80, 0xda, 0x0d, 0x71, 0x5d, 0xfd,
+	0x92, 0xa0, 0x1d, 0x0c, 0xa0, 0x18, 0x8e, 0xdd, 0xb7, 0x2e, 0x91, 0x84, 0x8f, 0xa0, 0x29, 0xd6,
+	0xfe, 0xf0, 0xd6, 0xd4, 0x3d, 0x82, 0x76, 0xb1, 0x0a, 0xcf, 0x88, 0x6d, 0x3a, 0xd4, 0x25, 0xd4,
+	0xf7, 0xa8, 0x6e, 0xbb, 0xba, 0xe1, 0x59, 0x8e, 0x8d, 0x64, 0xfc, 0x1c, 0x5a, 0x0e, 0x35, 0x09,
+	0x7d, 0xb2, 0x51, 0xc1, 0xc7, 0x70, 0x64, 0x92, 0x81, 0xc5, 0xbd, 0xb9, 0x84, 0x5c, 0xfb, 0x96,
+	0xdd, 0x77, 0x50, 0x95, 0xd3, 0xc6, 0x95, 0x6e, 0xd9, 0x86, 0x63, 0x12, 0xff, 0x56, 0x37, 0xae,
+	0x79, 0x7d, 0xe5, 0xec, 0x47, 0xc0, 0x5b, 0xa9, 0x5b, 0xfc, 0x6d, 0x8b, 0x0f, 0x00, 0x5c, 0xeb,
+	0xd2, 0xd6, 0xbd, 0x21, 0x25, 0x2e, 0xda, 0xc1, 0x87, 0xd0, 0x18, 0xe8, 0xae, 0xe7, 0x97, 0x56,
+	0x9f, 0x43, 0x6b, 0xa3, 0xaa, 0xeb, 0xf7, 0xad, 0x81, 0x47, 0x28, 0xda, 0xe5, 0x97, 0xcb, 0x6d,
+	0x21, 0x9e, 0xe6, 0x8b, 0x2d, 0x95, 0xe1, 0xdc, 0xdc, 0x58, 0x9e, 0x9f, 0x09, 0x50, 0xa5, 0xe7,
+	0xc2, 0x27, 0x51, 0x3c, 0xed, 0xcc, 0x1e, 0x57, 0x2c, 0x5e, 0xb0, 0xc9, 0x94, 0xc5, 0x9d, 0xbb,
+	0x60, 0x14, 0xcf, 0xc7, 0xe2, 0xd5, 0x93, 0xe4, 0xb3, 0xfb, 0xf6, 0x7c, 0x3a, 0x4f, 0x67, 0xeb,
+	0x11, 0x87, 0xdd, 0x0d, 0x71, 0x57, 0x88, 0xc5, 0x77, 0x25, 0xc9, 0xbf, 0x3d, 0x23, 0x25, 0x83,
+	0x5f, 0xfc, 0x3b, 0x00, 0x6c, 0x1e, 0x82, 0x31, 0x93, 0x06, 0x00, 0x00,
 }
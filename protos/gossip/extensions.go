@@ -170,6 +170,16 @@ func (m *GossipMessage) IsRemoteStateMessage() bool {
 	return m.GetStateRequest() != nil || m.GetStateResponse() != nil
 }
 
+// IsPrivateDataMsg returns whether this GossipMessage carries the private data of a transaction
+func (m *GossipMessage) IsPrivateDataMsg() bool {
+	return m.GetPrivateData() != nil
+}
+
+// IsBlobMsg returns whether this GossipMessage carries or requests a part of a blob
+func (m *GossipMessage) IsBlobMsg() bool {
+	return m.GetBlobChunk() != nil || m.GetBlobRequest() != nil
+}
+
 // GetPullMsgType returns the phase of the pull mechanism this GossipMessage belongs to
 // for example: Hello, Digest, etc.
 // If this isn't a pull message, PullMsgType_UNDEFINED is returned.
@@ -292,6 +302,13 @@ func (m *GossipMessage) IsTagLegal() error {
 		return nil
 	}
 
+	if m.IsPrivateDataMsg() || m.IsBlobMsg() {
+		if m.Tag != GossipMessage_CHAN_ONLY {
+			return fmt.Errorf("Tag should be %s", GossipMessage_Tag_name[int32(GossipMessage_CHAN_ONLY)])
+		}
+		return nil
+	}
+
 	if m.IsLeadershipMsg() {
 		if m.Tag != GossipMessage_CHAN_AND_ORG {
 			return fmt.Errorf("Tag should be %s", GossipMessage_Tag_name[int32(GossipMessage_CHAN_AND_ORG)])
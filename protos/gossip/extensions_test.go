@@ -147,3 +147,46 @@ func envelopes() []*Envelope {
 		},
 	}
 }
+
+func TestPrivateDataMsgTag(t *testing.T) {
+	msg := &GossipMessage{
+		Tag:     GossipMessage_CHAN_ONLY,
+		Channel: []byte("A"),
+		Content: &GossipMessage_PrivateData{
+			PrivateData: &PrivateDataMessage{
+				Payload: &PrivatePayload{Namespace: "cc", CollectionName: "coll", TxId: "tx1"},
+			},
+		},
+	}
+	assert.True(t, msg.IsPrivateDataMsg())
+	assert.False(t, msg.IsDataMsg())
+	assert.NoError(t, msg.IsTagLegal())
+
+	msg.Tag = GossipMessage_CHAN_AND_ORG
+	assert.Error(t, msg.IsTagLegal())
+}
+
+func TestBlobMsgTag(t *testing.T) {
+	chunk := &GossipMessage{
+		Tag:     GossipMessage_CHAN_ONLY,
+		Channel: []byte("A"),
+		Content: &GossipMessage_BlobChunk{
+			BlobChunk: &BlobChunk{Hash: []byte("hash"), Size: 4, Data: []byte("blob")},
+		},
+	}
+	request := &GossipMessage{
+		Tag:     GossipMessage_CHAN_ONLY,
+		Channel: []byte("A"),
+		Content: &GossipMessage_BlobRequest{
+			BlobRequest: &BlobRequest{Hash: []byte("hash"), Offset: 2},
+		},
+	}
+	for _, msg := range []*GossipMessage{chunk, request} {
+		assert.True(t, msg.IsBlobMsg())
+		assert.False(t, msg.IsPrivateDataMsg())
+		assert.NoError(t, msg.IsTagLegal())
+
+		msg.Tag = GossipMessage_CHAN_AND_ORG
+		assert.Error(t, msg.IsTagLegal())
+	}
+}
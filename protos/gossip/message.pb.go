@@ -6,9 +6,11 @@
 Package gossip is a generated protocol buffer package.
 
 It is generated from these files:
+
 	gossip/message.proto
 
 It has these top-level messages:
+
 	Envelope
 	SecretEnvelope
 	Secret
@@ -24,6 +26,10 @@ It has these top-level messages:
 	DataDigest
 	DataMessage
 	Payload
+	PrivateDataMessage
+	PrivatePayload
+	BlobChunk
+	BlobRequest
 	AliveMessage
 	LeadershipMessage
 	PeerTime
@@ -164,9 +170,7 @@ func (m *Secret) String() string            { return proto.CompactTextString(m)
 func (*Secret) ProtoMessage()               {}
 func (*Secret) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
 
-type isSecret_Content interface {
-	isSecret_Content()
-}
+type isSecret_Content interface{ isSecret_Content() }
 
 type Secret_InternalEndpoint struct {
 	InternalEndpoint string `protobuf:"bytes,1,opt,name=internalEndpoint,oneof"`
@@ -269,6 +273,9 @@ type GossipMessage struct {
 	//	*GossipMessage_StateResponse
 	//	*GossipMessage_LeadershipMsg
 	//	*GossipMessage_PeerIdentity
+	//	*GossipMessage_PrivateData
+	//	*GossipMessage_BlobChunk
+	//	*GossipMessage_BlobRequest
 	Content isGossipMessage_Content `protobuf_oneof:"content"`
 }
 
@@ -277,9 +284,7 @@ func (m *GossipMessage) String() string            { return proto.CompactTextStr
 func (*GossipMessage) ProtoMessage()               {}
 func (*GossipMessage) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
-type isGossipMessage_Content interface {
-	isGossipMessage_Content()
-}
+type isGossipMessage_Content interface{ isGossipMessage_Content() }
 
 type GossipMessage_AliveMsg struct {
 	AliveMsg *AliveMessage `protobuf:"bytes,5,opt,name=alive_msg,json=aliveMsg,oneof"`
@@ -332,6 +337,15 @@ type GossipMessage_LeadershipMsg struct {
 type GossipMessage_PeerIdentity struct {
 	PeerIdentity *PeerIdentity `protobuf:"bytes,21,opt,name=peer_identity,json=peerIdentity,oneof"`
 }
+type GossipMessage_PrivateData struct {
+	PrivateData *PrivateDataMessage `protobuf:"bytes,22,opt,name=private_data,json=privateData,oneof"`
+}
+type GossipMessage_BlobChunk struct {
+	BlobChunk *BlobChunk `protobuf:"bytes,23,opt,name=blob_chunk,json=blobChunk,oneof"`
+}
+type GossipMessage_BlobRequest struct {
+	BlobRequest *BlobRequest `protobuf:"bytes,24,opt,name=blob_request,json=blobRequest,oneof"`
+}
 
 func (*GossipMessage_AliveMsg) isGossipMessage_Content()         {}
 func (*GossipMessage_MemReq) isGossipMessage_Content()           {}
@@ -350,6 +364,9 @@ func (*GossipMessage_StateRequest) isGossipMessage_Content()     {}
 func (*GossipMessage_StateResponse) isGossipMessage_Content()    {}
 func (*GossipMessage_LeadershipMsg) isGossipMessage_Content()    {}
 func (*GossipMessage_PeerIdentity) isGossipMessage_Content()     {}
+func (*GossipMessage_PrivateData) isGossipMessage_Content()      {}
+func (*GossipMessage_BlobChunk) isGossipMessage_Content()        {}
+func (*GossipMessage_BlobRequest) isGossipMessage_Content()      {}
 
 func (m *GossipMessage) GetContent() isGossipMessage_Content {
 	if m != nil {
@@ -477,6 +494,27 @@ func (m *GossipMessage) GetPeerIdentity() *PeerIdentity {
 	return nil
 }
 
+func (m *GossipMessage) GetPrivateData() *PrivateDataMessage {
+	if x, ok := m.GetContent().(*GossipMessage_PrivateData); ok {
+		return x.PrivateData
+	}
+	return nil
+}
+
+func (m *GossipMessage) GetBlobChunk() *BlobChunk {
+	if x, ok := m.GetContent().(*GossipMessage_BlobChunk); ok {
+		return x.BlobChunk
+	}
+	return nil
+}
+
+func (m *GossipMessage) GetBlobRequest() *BlobRequest {
+	if x, ok := m.GetContent().(*GossipMessage_BlobRequest); ok {
+		return x.BlobRequest
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*GossipMessage) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _GossipMessage_OneofMarshaler, _GossipMessage_OneofUnmarshaler, _GossipMessage_OneofSizer, []interface{}{
@@ -497,6 +535,9 @@ func (*GossipMessage) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer)
 		(*GossipMessage_StateResponse)(nil),
 		(*GossipMessage_LeadershipMsg)(nil),
 		(*GossipMessage_PeerIdentity)(nil),
+		(*GossipMessage_PrivateData)(nil),
+		(*GossipMessage_BlobChunk)(nil),
+		(*GossipMessage_BlobRequest)(nil),
 	}
 }
 
@@ -589,6 +630,21 @@ func _GossipMessage_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.PeerIdentity); err != nil {
 			return err
 		}
+	case *GossipMessage_PrivateData:
+		b.EncodeVarint(22<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.PrivateData); err != nil {
+			return err
+		}
+	case *GossipMessage_BlobChunk:
+		b.EncodeVarint(23<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.BlobChunk); err != nil {
+			return err
+		}
+	case *GossipMessage_BlobRequest:
+		b.EncodeVarint(24<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.BlobRequest); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("GossipMessage.Content has unexpected type %T", x)
@@ -735,6 +791,30 @@ func _GossipMessage_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.
 		err := b.DecodeMessage(msg)
 		m.Content = &GossipMessage_PeerIdentity{msg}
 		return true, err
+	case 22: // content.private_data
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(PrivateDataMessage)
+		err := b.DecodeMessage(msg)
+		m.Content = &GossipMessage_PrivateData{msg}
+		return true, err
+	case 23: // content.blob_chunk
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(BlobChunk)
+		err := b.DecodeMessage(msg)
+		m.Content = &GossipMessage_BlobChunk{msg}
+		return true, err
+	case 24: // content.blob_request
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(BlobRequest)
+		err := b.DecodeMessage(msg)
+		m.Content = &GossipMessage_BlobRequest{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -829,6 +909,21 @@ func _GossipMessage_OneofSizer(msg proto.Message) (n int) {
 		n += proto.SizeVarint(21<<3 | proto.WireBytes)
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *GossipMessage_PrivateData:
+		s := proto.Size(x.PrivateData)
+		n += proto.SizeVarint(22<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *GossipMessage_BlobChunk:
+		s := proto.Size(x.BlobChunk)
+		n += proto.SizeVarint(23<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *GossipMessage_BlobRequest:
+		s := proto.Size(x.BlobRequest)
+		n += proto.SizeVarint(24<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -1013,6 +1108,65 @@ func (m *Payload) String() string            { return proto.CompactTextString(m)
 func (*Payload) ProtoMessage()               {}
 func (*Payload) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
 
+// PrivateDataMessage is the message that contains the private data
+// of a transaction, sent to the peers eligible for its collection
+type PrivateDataMessage struct {
+	Payload *PrivatePayload `protobuf:"bytes,1,opt,name=payload" json:"payload,omitempty"`
+}
+
+func (m *PrivateDataMessage) Reset()                    { *m = PrivateDataMessage{} }
+func (m *PrivateDataMessage) String() string            { return proto.CompactTextString(m) }
+func (*PrivateDataMessage) ProtoMessage()               {}
+func (*PrivateDataMessage) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *PrivateDataMessage) GetPayload() *PrivatePayload {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+// PrivatePayload contains the private read-write set of a collection
+// within a transaction
+type PrivatePayload struct {
+	CollectionName string `protobuf:"bytes,1,opt,name=collection_name,json=collectionName" json:"collection_name,omitempty"`
+	Namespace      string `protobuf:"bytes,2,opt,name=namespace" json:"namespace,omitempty"`
+	TxId           string `protobuf:"bytes,3,opt,name=tx_id,json=txId" json:"tx_id,omitempty"`
+	PrivateRwset   []byte `protobuf:"bytes,4,opt,name=private_rwset,json=privateRwset,proto3" json:"private_rwset,omitempty"`
+}
+
+func (m *PrivatePayload) Reset()                    { *m = PrivatePayload{} }
+func (m *PrivatePayload) String() string            { return proto.CompactTextString(m) }
+func (*PrivatePayload) ProtoMessage()               {}
+func (*PrivatePayload) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+// BlobChunk contains a part of the content of a blob streamed by a chaincode,
+// which the state only references by its hash. It is pushed by the endorsing
+// peer, or sent in response to a BlobRequest
+type BlobChunk struct {
+	Hash   []byte `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Offset uint64 `protobuf:"varint,2,opt,name=offset" json:"offset,omitempty"`
+	Size   uint64 `protobuf:"varint,3,opt,name=size" json:"size,omitempty"`
+	Data   []byte `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *BlobChunk) Reset()                    { *m = BlobChunk{} }
+func (m *BlobChunk) String() string            { return proto.CompactTextString(m) }
+func (*BlobChunk) ProtoMessage()               {}
+func (*BlobChunk) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+// BlobRequest asks a remote peer for a part of the content of a blob
+// starting at offset
+type BlobRequest struct {
+	Hash   []byte `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Offset uint64 `protobuf:"varint,2,opt,name=offset" json:"offset,omitempty"`
+}
+
+func (m *BlobRequest) Reset()                    { *m = BlobRequest{} }
+func (m *BlobRequest) String() string            { return proto.CompactTextString(m) }
+func (*BlobRequest) ProtoMessage()               {}
+func (*BlobRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
 // AliveMessage is sent to inform remote peers
 // of a peer's existence and activity
 type AliveMessage struct {
@@ -1024,7 +1178,7 @@ type AliveMessage struct {
 func (m *AliveMessage) Reset()                    { *m = AliveMessage{} }
 func (m *AliveMessage) String() string            { return proto.CompactTextString(m) }
 func (*AliveMessage) ProtoMessage()               {}
-func (*AliveMessage) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+func (*AliveMessage) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
 
 func (m *AliveMessage) GetMembership() *Member {
 	if m != nil {
@@ -1051,7 +1205,7 @@ type LeadershipMessage struct {
 func (m *LeadershipMessage) Reset()                    { *m = LeadershipMessage{} }
 func (m *LeadershipMessage) String() string            { return proto.CompactTextString(m) }
 func (*LeadershipMessage) ProtoMessage()               {}
-func (*LeadershipMessage) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+func (*LeadershipMessage) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
 
 func (m *LeadershipMessage) GetTimestamp() *PeerTime {
 	if m != nil {
@@ -1069,7 +1223,7 @@ type PeerTime struct {
 func (m *PeerTime) Reset()                    { *m = PeerTime{} }
 func (m *PeerTime) String() string            { return proto.CompactTextString(m) }
 func (*PeerTime) ProtoMessage()               {}
-func (*PeerTime) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+func (*PeerTime) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
 
 // MembershipRequest is used to ask membership information
 // from a remote peer
@@ -1081,7 +1235,7 @@ type MembershipRequest struct {
 func (m *MembershipRequest) Reset()                    { *m = MembershipRequest{} }
 func (m *MembershipRequest) String() string            { return proto.CompactTextString(m) }
 func (*MembershipRequest) ProtoMessage()               {}
-func (*MembershipRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+func (*MembershipRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
 
 func (m *MembershipRequest) GetSelfInformation() *Envelope {
 	if m != nil {
@@ -1099,7 +1253,7 @@ type MembershipResponse struct {
 func (m *MembershipResponse) Reset()                    { *m = MembershipResponse{} }
 func (m *MembershipResponse) String() string            { return proto.CompactTextString(m) }
 func (*MembershipResponse) ProtoMessage()               {}
-func (*MembershipResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+func (*MembershipResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
 
 func (m *MembershipResponse) GetAlive() []*Envelope {
 	if m != nil {
@@ -1126,7 +1280,7 @@ type Member struct {
 func (m *Member) Reset()                    { *m = Member{} }
 func (m *Member) String() string            { return proto.CompactTextString(m) }
 func (*Member) ProtoMessage()               {}
-func (*Member) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+func (*Member) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
 
 // Empty is used for pinging and in tests
 type Empty struct {
@@ -1135,7 +1289,7 @@ type Empty struct {
 func (m *Empty) Reset()                    { *m = Empty{} }
 func (m *Empty) String() string            { return proto.CompactTextString(m) }
 func (*Empty) ProtoMessage()               {}
-func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
 
 // RemoteStateRequest is used to ask a set of blocks
 // from a remote peer
@@ -1147,7 +1301,7 @@ type RemoteStateRequest struct {
 func (m *RemoteStateRequest) Reset()                    { *m = RemoteStateRequest{} }
 func (m *RemoteStateRequest) String() string            { return proto.CompactTextString(m) }
 func (*RemoteStateRequest) ProtoMessage()               {}
-func (*RemoteStateRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+func (*RemoteStateRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
 
 // RemoteStateResponse is used to send a set of blocks
 // to a remote peer
@@ -1158,7 +1312,7 @@ type RemoteStateResponse struct {
 func (m *RemoteStateResponse) Reset()                    { *m = RemoteStateResponse{} }
 func (m *RemoteStateResponse) String() string            { return proto.CompactTextString(m) }
 func (*RemoteStateResponse) ProtoMessage()               {}
-func (*RemoteStateResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+func (*RemoteStateResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
 
 func (m *RemoteStateResponse) GetPayloads() []*Payload {
 	if m != nil {
@@ -1183,6 +1337,10 @@ func init() {
 	proto.RegisterType((*DataDigest)(nil), "gossip.DataDigest")
 	proto.RegisterType((*DataMessage)(nil), "gossip.DataMessage")
 	proto.RegisterType((*Payload)(nil), "gossip.Payload")
+	proto.RegisterType((*PrivateDataMessage)(nil), "gossip.PrivateDataMessage")
+	proto.RegisterType((*PrivatePayload)(nil), "gossip.PrivatePayload")
+	proto.RegisterType((*BlobChunk)(nil), "gossip.BlobChunk")
+	proto.RegisterType((*BlobRequest)(nil), "gossip.BlobRequest")
 	proto.RegisterType((*AliveMessage)(nil), "gossip.AliveMessage")
 	proto.RegisterType((*LeadershipMessage)(nil), "gossip.LeadershipMessage")
 	proto.RegisterType((*PeerTime)(nil), "gossip.PeerTime")
@@ -1341,90 +1499,102 @@ var _Gossip_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("gossip/message.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1352 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xb4, 0x57, 0xdd, 0x6f, 0xe3, 0x44,
-	0x10, 0x8f, 0x9b, 0x4f, 0x4f, 0x3e, 0x9a, 0x6e, 0x7b, 0x60, 0xca, 0x71, 0xaa, 0x2c, 0xee, 0x54,
-	0xe8, 0x91, 0x9e, 0x7a, 0x80, 0x4e, 0x3a, 0x81, 0x94, 0x36, 0xa1, 0x09, 0x5c, 0xd2, 0xca, 0xcd,
-	0x09, 0x8e, 0x17, 0x6b, 0x13, 0x6f, 0x1d, 0x73, 0xf6, 0xda, 0xf5, 0x6e, 0x0e, 0xfa, 0x08, 0x8f,
-	0x3c, 0xf0, 0xcc, 0x9f, 0x8b, 0xbc, 0x6b, 0x3b, 0x76, 0xdd, 0x9e, 0xd4, 0x93, 0x78, 0xf3, 0x7c,
-	0xfc, 0x66, 0x66, 0x67, 0x67, 0x67, 0xc6, 0xb0, 0x63, 0xfb, 0x8c, 0x39, 0xc1, 0xa1, 0x47, 0x18,
-	0xc3, 0x36, 0xe9, 0x05, 0xa1, 0xcf, 0x7d, 0x54, 0x93, 0x5c, 0xfd, 0x2f, 0x05, 0x1a, 0x43, 0xfa,
-	0x8e, 0xb8, 0x7e, 0x40, 0x90, 0x06, 0xf5, 0x00, 0x5f, 0xbb, 0x3e, 0xb6, 0x34, 0x65, 0x4f, 0xd9,
-	0x6f, 0x19, 0x09, 0x89, 0x1e, 0x82, 0xca, 0x1c, 0x9b, 0x62, 0xbe, 0x0a, 0x89, 0xb6, 0x21, 0x64,
-	0x6b, 0x06, 0xfa, 0x1e, 0x3a, 0x8c, 0x2c, 0x42, 0xc2, 0x13, 0x4b, 0x5a, 0x79, 0x4f, 0xd9, 0x6f,
-	0x1e, 0x7d, 0xd4, 0x93, 0x5e, 0x7a, 0x17, 0x39, 0xa9, 0x71, 0x43, 0x5b, 0x1f, 0x41, 0x27, 0xaf,
-	0xf1, 0xa1, 0x91, 0xe8, 0x7d, 0xa8, 0x49, 0x4b, 0xe8, 0x29, 0x74, 0x1d, 0xca, 0x49, 0x48, 0xb1,
-	0x3b, 0xa4, 0x56, 0xe0, 0x3b, 0x94, 0x0b, 0x53, 0xea, 0xa8, 0x64, 0x14, 0x24, 0xc7, 0x2a, 0xd4,
-	0x17, 0x3e, 0xe5, 0x84, 0x72, 0xfd, 0x5f, 0x15, 0xda, 0xa7, 0x22, 0xec, 0x89, 0xcc, 0x18, 0xda,
-	0x81, 0x2a, 0xf5, 0xe9, 0x82, 0x08, 0x7c, 0xc5, 0x90, 0x44, 0x14, 0xe2, 0x62, 0x89, 0x29, 0x25,
-	0x6e, 0x1c, 0x46, 0x42, 0xa2, 0x03, 0x28, 0x73, 0x6c, 0x8b, 0x1c, 0x74, 0x8e, 0x3e, 0x49, 0x72,
-	0x90, 0xb3, 0xd9, 0x9b, 0x61, 0xdb, 0x88, 0xb4, 0xd0, 0x73, 0x50, 0xb1, 0xeb, 0xbc, 0x23, 0xa6,
-	0xc7, 0x6c, 0xad, 0x2a, 0xd2, 0xb6, 0x93, 0x40, 0xfa, 0x91, 0x20, 0x46, 0x8c, 0x4a, 0x46, 0x43,
-	0x28, 0x4e, 0x98, 0x8d, 0xbe, 0x86, 0xba, 0x47, 0x3c, 0x33, 0x24, 0x57, 0x5a, 0x4d, 0x40, 0x52,
-	0x2f, 0x13, 0xe2, 0xcd, 0x49, 0xc8, 0x96, 0x4e, 0x60, 0x90, 0xab, 0x15, 0x61, 0x7c, 0x54, 0x32,
-	0x6a, 0x1e, 0xf1, 0x0c, 0x72, 0x85, 0xbe, 0x49, 0x50, 0x4c, 0xab, 0x0b, 0xd4, 0xee, 0x6d, 0x28,
-	0x16, 0xf8, 0x94, 0x91, 0x14, 0xc6, 0xd0, 0x33, 0x68, 0x58, 0x98, 0x63, 0x11, 0x60, 0x43, 0xe0,
-	0xb6, 0x13, 0xdc, 0x00, 0x73, 0xbc, 0x8e, 0xaf, 0x1e, 0xa9, 0x45, 0xe1, 0x1d, 0x40, 0x75, 0x49,
-	0x5c, 0xd7, 0xd7, 0xd4, 0xbc, 0xba, 0x4c, 0xc1, 0x28, 0x12, 0x8d, 0x4a, 0x86, 0xd4, 0x41, 0x87,
-	0xb1, 0x79, 0xcb, 0xb1, 0x35, 0x10, 0xfa, 0x28, 0x6b, 0x7e, 0xe0, 0xd8, 0xf2, 0x14, 0xc2, 0xfa,
-	0xc0, 0xb1, 0xd3, 0x78, 0xa2, 0xd3, 0x37, 0x8b, 0xf1, 0xac, 0xcf, 0x2d, 0x10, 0xf2, 0xe0, 0x4d,
-	0x81, 0x58, 0x05, 0x16, 0xe6, 0x44, 0x6b, 0x15, 0xbd, 0xbc, 0x16, 0x92, 0x51, 0xc9, 0x00, 0x2b,
-	0xa5, 0xd0, 0x63, 0xa8, 0x12, 0x2f, 0xe0, 0xd7, 0x5a, 0x5b, 0x00, 0xda, 0x09, 0x60, 0x18, 0x31,
-	0xa3, 0x03, 0x08, 0x29, 0x3a, 0x80, 0xca, 0xc2, 0xa7, 0x54, 0xeb, 0x08, 0xad, 0x07, 0x89, 0xd6,
-	0x89, 0x4f, 0xe9, 0x90, 0x71, 0x3c, 0x77, 0x1d, 0xb6, 0x1c, 0x95, 0x0c, 0xa1, 0x84, 0x8e, 0x00,
-	0x18, 0xc7, 0x9c, 0x98, 0x0e, 0xbd, 0xf4, 0xb5, 0x4d, 0x01, 0xd9, 0x4a, 0x9f, 0x49, 0x24, 0x19,
-	0xd3, 0xcb, 0x28, 0x3b, 0x2a, 0x4b, 0x08, 0x74, 0x0c, 0x1d, 0x89, 0x61, 0x14, 0x07, 0x6c, 0xe9,
-	0x73, 0xad, 0x9b, 0xbf, 0xf4, 0x14, 0x77, 0x11, 0x2b, 0x8c, 0x4a, 0x46, 0x5b, 0x40, 0x12, 0x06,
-	0x9a, 0xc0, 0xf6, 0xda, 0xaf, 0x19, 0xac, 0x5c, 0x57, 0xe4, 0x6f, 0x4b, 0x18, 0x7a, 0x58, 0x30,
-	0x74, 0xbe, 0x72, 0xdd, 0x75, 0x22, 0xbb, 0xec, 0x06, 0x1f, 0xf5, 0x41, 0xda, 0x8f, 0x8c, 0x44,
-	0x4a, 0x1a, 0xca, 0x17, 0x94, 0x41, 0x3c, 0x9f, 0x13, 0x61, 0x6e, 0x6d, 0xa6, 0xc5, 0x32, 0x34,
-	0x1a, 0x24, 0xa7, 0x0a, 0xe3, 0x92, 0xd3, 0xb6, 0x85, 0x8d, 0x4f, 0x6f, 0xb5, 0x91, 0x56, 0x65,
-	0x9b, 0x65, 0x19, 0x51, 0x6e, 0x5c, 0x82, 0x2d, 0x59, 0xbc, 0xa2, 0x44, 0x77, 0xf2, 0xb9, 0x79,
-	0x95, 0x4a, 0xd7, 0x85, 0xda, 0x5e, 0x43, 0xa2, 0x72, 0x7d, 0x09, 0xed, 0x80, 0x90, 0xd0, 0x74,
-	0x2c, 0x42, 0xb9, 0xc3, 0xaf, 0xb5, 0x07, 0xf9, 0x67, 0x78, 0x4e, 0x48, 0x38, 0x8e, 0x65, 0xd1,
-	0x31, 0x82, 0x0c, 0xad, 0x9b, 0x50, 0x9e, 0x61, 0x1b, 0xb5, 0x41, 0x7d, 0x3d, 0x1d, 0x0c, 0x7f,
-	0x18, 0x4f, 0x87, 0x83, 0x6e, 0x09, 0xa9, 0x50, 0x1d, 0x4e, 0xce, 0x67, 0x6f, 0xba, 0x0a, 0x6a,
-	0x41, 0xe3, 0xcc, 0x38, 0x35, 0xcf, 0xa6, 0xaf, 0xde, 0x74, 0x37, 0x22, 0xbd, 0x93, 0x51, 0x7f,
-	0x2a, 0xc9, 0x32, 0xea, 0x42, 0x4b, 0x90, 0xfd, 0xe9, 0xc0, 0x3c, 0x33, 0x4e, 0xbb, 0x15, 0xb4,
-	0x09, 0x4d, 0xa9, 0x60, 0x08, 0x46, 0x35, 0xdb, 0x9a, 0xfe, 0x51, 0x40, 0x4d, 0xaf, 0x08, 0xed,
-	0x42, 0xc3, 0x23, 0x1c, 0x47, 0x05, 0x1b, 0x37, 0xc9, 0x94, 0x46, 0x3d, 0x50, 0xb9, 0xe3, 0x11,
-	0xc6, 0xb1, 0x17, 0x88, 0xf6, 0xd4, 0x3c, 0xea, 0x66, 0x8f, 0x33, 0x73, 0x3c, 0x62, 0xac, 0x55,
-	0xd0, 0x03, 0xa8, 0x05, 0x6f, 0x1d, 0xd3, 0xb1, 0x44, 0xd7, 0x6a, 0x19, 0xd5, 0xe0, 0xad, 0x33,
-	0xb6, 0xd0, 0x23, 0x80, 0xb8, 0xa9, 0x4d, 0xfa, 0x27, 0x5a, 0x45, 0x88, 0x32, 0x1c, 0xbd, 0x0f,
-	0x5b, 0x85, 0xda, 0x43, 0x4f, 0xa1, 0x41, 0x5c, 0xe2, 0x11, 0xca, 0x99, 0xa6, 0xec, 0x95, 0xb3,
-	0xae, 0xd3, 0x09, 0x90, 0x6a, 0xe8, 0xdf, 0xc2, 0xce, 0x6d, 0x55, 0x77, 0xc3, 0xb5, 0x52, 0x70,
-	0x3d, 0x85, 0x76, 0xee, 0x85, 0x65, 0x8e, 0xa0, 0x64, 0x8f, 0x80, 0xa0, 0xb2, 0x20, 0x21, 0x8f,
-	0x7b, 0xb4, 0xf8, 0x8e, 0x78, 0x4b, 0xcc, 0x96, 0xf1, 0x59, 0xc5, 0xb7, 0xfe, 0x1a, 0x5a, 0xd9,
-	0x7b, 0xbe, 0x8f, 0xb9, 0xec, 0x45, 0x94, 0xf3, 0x17, 0xa1, 0x7b, 0xd0, 0xcc, 0x34, 0xa5, 0xbb,
-	0x47, 0x89, 0x25, 0xda, 0x1c, 0xd3, 0x36, 0xf6, 0xca, 0xfb, 0xaa, 0x91, 0x90, 0xa8, 0x07, 0x0d,
-	0x8f, 0xd9, 0x26, 0xbf, 0x8e, 0x67, 0x6a, 0x67, 0xdd, 0xeb, 0xa2, 0x64, 0x4d, 0x98, 0x3d, 0xbb,
-	0x0e, 0x88, 0x51, 0xf7, 0xe4, 0x87, 0xee, 0x43, 0x33, 0xd3, 0x64, 0xef, 0x70, 0x97, 0x8d, 0x77,
-	0xa3, 0x50, 0x38, 0xf7, 0x73, 0xf8, 0x07, 0xc0, 0xba, 0x7f, 0xde, 0xe1, 0xef, 0x73, 0xa8, 0xc4,
-	0xbe, 0x6e, 0x2f, 0x86, 0xca, 0x07, 0x79, 0x76, 0xa5, 0x67, 0x39, 0x1f, 0xfe, 0xf7, 0xc4, 0xbe,
-	0x90, 0xf7, 0x98, 0xac, 0x04, 0x5f, 0xe4, 0xf7, 0x93, 0xe6, 0xd1, 0x66, 0x8a, 0x96, 0xec, 0x74,
-	0x61, 0xd1, 0x7f, 0x84, 0x7a, 0xcc, 0x43, 0x1f, 0x43, 0x9d, 0x91, 0x2b, 0x93, 0xae, 0xbc, 0x38,
-	0xcc, 0x1a, 0x23, 0x57, 0xd3, 0x95, 0x97, 0x16, 0x64, 0x74, 0x1b, 0xaa, 0x2c, 0xc8, 0x88, 0x97,
-	0xa9, 0x28, 0xf1, 0xad, 0xff, 0xad, 0x40, 0x2b, 0xbb, 0x14, 0xa0, 0x1e, 0x80, 0x97, 0xce, 0xee,
-	0x38, 0x94, 0x4e, 0x7e, 0xaa, 0x1b, 0x19, 0x8d, 0x7b, 0xf7, 0x85, 0x5d, 0x68, 0xa4, 0x5d, 0x51,
-	0x3e, 0xff, 0x94, 0xd6, 0xff, 0x54, 0x60, 0xab, 0xd0, 0x5d, 0xef, 0x7a, 0x37, 0xf7, 0x75, 0xfc,
-	0x18, 0x3a, 0x0e, 0x33, 0x2d, 0xb2, 0x70, 0x71, 0x88, 0xb9, 0xe3, 0x53, 0x91, 0x87, 0x86, 0xd1,
-	0x76, 0xd8, 0x60, 0xcd, 0xd4, 0x8f, 0xa1, 0x91, 0xa0, 0xd1, 0x67, 0x00, 0x0e, 0x5d, 0x44, 0xd9,
-	0x9d, 0x93, 0x30, 0x4e, 0xb0, 0xea, 0xd0, 0xc5, 0x54, 0x30, 0xb2, 0xc9, 0xdf, 0xc8, 0x26, 0x5f,
-	0xbf, 0x84, 0xad, 0xc2, 0xd6, 0x84, 0x5e, 0x42, 0x97, 0x11, 0xf7, 0x52, 0x8c, 0xcb, 0xd0, 0x93,
-	0x11, 0x28, 0xf9, 0xb0, 0xd3, 0xfa, 0xdd, 0x8c, 0x34, 0xc7, 0x6b, 0xc5, 0xa8, 0x18, 0xdf, 0x52,
-	0xff, 0x77, 0x2a, 0x8a, 0xae, 0x65, 0x48, 0x42, 0x9f, 0x03, 0x2a, 0xee, 0x59, 0xe8, 0x09, 0x54,
-	0xc5, 0x5a, 0x77, 0x67, 0xab, 0x94, 0x62, 0xf1, 0x88, 0x08, 0xb6, 0xde, 0xf3, 0x88, 0x08, 0xb6,
-	0xf4, 0x9f, 0xa1, 0x26, 0x7d, 0x44, 0x37, 0x47, 0x72, 0x7b, 0xaf, 0x91, 0xd2, 0xef, 0x6d, 0x00,
-	0xb7, 0x4f, 0x02, 0xbd, 0x0e, 0x55, 0xb1, 0xf6, 0xe8, 0xbf, 0x00, 0x2a, 0x0e, 0x77, 0xa4, 0x8b,
-	0x7d, 0x20, 0xe4, 0x66, 0xbe, 0xbe, 0x9b, 0x82, 0x79, 0x21, 0x8b, 0xfc, 0x11, 0x34, 0x09, 0xb5,
-	0xcc, 0xfc, 0x25, 0xa8, 0x84, 0x5a, 0x52, 0xae, 0x1f, 0xc3, 0xf6, 0x2d, 0x23, 0x1f, 0x1d, 0x40,
-	0x23, 0x7e, 0x4a, 0xc9, 0x38, 0x29, 0xbc, 0xb5, 0x54, 0xe1, 0xcb, 0xef, 0xa0, 0x99, 0x79, 0xbe,
-	0x37, 0xa7, 0x72, 0x1b, 0xd4, 0xe3, 0x57, 0x67, 0x27, 0x3f, 0x99, 0x93, 0x8b, 0xd3, 0xae, 0x12,
-	0x0d, 0xdf, 0xf1, 0x60, 0x38, 0x9d, 0x8d, 0x67, 0x6f, 0x04, 0x67, 0xe3, 0xe8, 0x37, 0xa8, 0xc9,
-	0xf6, 0x89, 0x5e, 0x40, 0x4b, 0x7e, 0x5d, 0xf0, 0x90, 0x60, 0x0f, 0x15, 0x12, 0xbe, 0x5b, 0xe0,
-	0xe8, 0xa5, 0x7d, 0xe5, 0x99, 0x82, 0x9e, 0x40, 0xe5, 0xdc, 0xa1, 0x36, 0xca, 0xaf, 0x8b, 0xbb,
-	0x79, 0x52, 0x2f, 0x1d, 0x7f, 0xf5, 0xeb, 0x81, 0xed, 0xf0, 0xe5, 0x6a, 0xde, 0x5b, 0xf8, 0xde,
-	0xe1, 0xf2, 0x3a, 0x20, 0xa1, 0x4b, 0x2c, 0x9b, 0x84, 0x87, 0x97, 0x78, 0x1e, 0x3a, 0x8b, 0x43,
-	0xf1, 0xa3, 0xc6, 0x0e, 0x25, 0x6c, 0x5e, 0x13, 0xe4, 0xf3, 0xff, 0x02, 0x00, 0x00, 0xff, 0xff,
-	0xd6, 0x2d, 0x62, 0x5a, 0xcf, 0x0d, 0x00, 0x00,
+	// 1546 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xb4, 0x57, 0x5b, 0x6f, 0xdb, 0xc8,
+	0x15, 0x16, 0x6d, 0xdd, 0x78, 0x74, 0xb1, 0x3c, 0x76, 0x1c, 0xd6, 0x4d, 0x03, 0x83, 0x6d, 0x52,
+	0xb7, 0x4e, 0xe5, 0xc0, 0x69, 0x8b, 0x14, 0x41, 0x5b, 0xd8, 0x96, 0x62, 0xa9, 0x8d, 0x64, 0x83,
+	0x76, 0xd0, 0xa6, 0x2f, 0x04, 0x25, 0x8e, 0x29, 0xd6, 0xe4, 0x90, 0xe6, 0x8c, 0x92, 0x78, 0xdf,
+	0x76, 0x1f, 0xf7, 0x61, 0x81, 0xfd, 0x55, 0xfb, 0xb7, 0x16, 0x33, 0xc3, 0xab, 0x69, 0x07, 0x70,
+	0x80, 0x7d, 0xe3, 0xb9, 0x5f, 0xe6, 0xcc, 0x37, 0x87, 0xb0, 0xe9, 0x04, 0x94, 0xba, 0xe1, 0xbe,
+	0x8f, 0x29, 0xb5, 0x1c, 0xdc, 0x0f, 0xa3, 0x80, 0x05, 0xa8, 0x2e, 0xb9, 0xfa, 0x77, 0x0a, 0x34,
+	0x87, 0xe4, 0x23, 0xf6, 0x82, 0x10, 0x23, 0x0d, 0x1a, 0xa1, 0x75, 0xe3, 0x05, 0x96, 0xad, 0x29,
+	0x3b, 0xca, 0x6e, 0xdb, 0x48, 0x48, 0xf4, 0x04, 0x54, 0xea, 0x3a, 0xc4, 0x62, 0xcb, 0x08, 0x6b,
+	0x2b, 0x42, 0x96, 0x31, 0xd0, 0x3f, 0xa0, 0x4b, 0xf1, 0x3c, 0xc2, 0x2c, 0xf1, 0xa4, 0xad, 0xee,
+	0x28, 0xbb, 0xad, 0x83, 0xad, 0xbe, 0x8c, 0xd2, 0x3f, 0x2f, 0x48, 0x8d, 0x5b, 0xda, 0xfa, 0x08,
+	0xba, 0x45, 0x8d, 0xaf, 0xcd, 0x44, 0x3f, 0x84, 0xba, 0xf4, 0x84, 0x5e, 0x40, 0xcf, 0x25, 0x0c,
+	0x47, 0xc4, 0xf2, 0x86, 0xc4, 0x0e, 0x03, 0x97, 0x30, 0xe1, 0x4a, 0x1d, 0x55, 0x8c, 0x92, 0xe4,
+	0x48, 0x85, 0xc6, 0x3c, 0x20, 0x0c, 0x13, 0xa6, 0xff, 0x04, 0xd0, 0x39, 0x11, 0x69, 0x4f, 0x64,
+	0xc7, 0xd0, 0x26, 0xd4, 0x48, 0x40, 0xe6, 0x58, 0xd8, 0x57, 0x0d, 0x49, 0xf0, 0x14, 0xe7, 0x0b,
+	0x8b, 0x10, 0xec, 0xc5, 0x69, 0x24, 0x24, 0xda, 0x83, 0x55, 0x66, 0x39, 0xa2, 0x07, 0xdd, 0x83,
+	0x5f, 0x25, 0x3d, 0x28, 0xf8, 0xec, 0x5f, 0x58, 0x8e, 0xc1, 0xb5, 0xd0, 0x2b, 0x50, 0x2d, 0xcf,
+	0xfd, 0x88, 0x4d, 0x9f, 0x3a, 0x5a, 0x4d, 0xb4, 0x6d, 0x33, 0x31, 0x39, 0xe4, 0x82, 0xd8, 0x62,
+	0x54, 0x31, 0x9a, 0x42, 0x71, 0x42, 0x1d, 0xf4, 0x67, 0x68, 0xf8, 0xd8, 0x37, 0x23, 0x7c, 0xad,
+	0xd5, 0x85, 0x49, 0x1a, 0x65, 0x82, 0xfd, 0x19, 0x8e, 0xe8, 0xc2, 0x0d, 0x0d, 0x7c, 0xbd, 0xc4,
+	0x94, 0x8d, 0x2a, 0x46, 0xdd, 0xc7, 0xbe, 0x81, 0xaf, 0xd1, 0x5f, 0x12, 0x2b, 0xaa, 0x35, 0x84,
+	0xd5, 0xf6, 0x5d, 0x56, 0x34, 0x0c, 0x08, 0xc5, 0xa9, 0x19, 0x45, 0x2f, 0xa1, 0x69, 0x5b, 0xcc,
+	0x12, 0x09, 0x36, 0x85, 0xdd, 0x46, 0x62, 0x37, 0xb0, 0x98, 0x95, 0xe5, 0xd7, 0xe0, 0x6a, 0x3c,
+	0xbd, 0x3d, 0xa8, 0x2d, 0xb0, 0xe7, 0x05, 0x9a, 0x5a, 0x54, 0x97, 0x2d, 0x18, 0x71, 0xd1, 0xa8,
+	0x62, 0x48, 0x1d, 0xb4, 0x1f, 0xbb, 0xb7, 0x5d, 0x47, 0x03, 0xa1, 0x8f, 0xf2, 0xee, 0x07, 0xae,
+	0x23, 0xab, 0x10, 0xde, 0x07, 0xae, 0x93, 0xe6, 0xc3, 0xab, 0x6f, 0x95, 0xf3, 0xc9, 0xea, 0x16,
+	0x16, 0xb2, 0xf0, 0x96, 0xb0, 0x58, 0x86, 0xb6, 0xc5, 0xb0, 0xd6, 0x2e, 0x47, 0x79, 0x2f, 0x24,
+	0xa3, 0x8a, 0x01, 0x76, 0x4a, 0xa1, 0x67, 0x50, 0xc3, 0x7e, 0xc8, 0x6e, 0xb4, 0x8e, 0x30, 0xe8,
+	0x24, 0x06, 0x43, 0xce, 0xe4, 0x05, 0x08, 0x29, 0xda, 0x83, 0xea, 0x3c, 0x20, 0x44, 0xeb, 0x0a,
+	0xad, 0x47, 0x89, 0xd6, 0x71, 0x40, 0xc8, 0x90, 0x32, 0x6b, 0xe6, 0xb9, 0x74, 0x31, 0xaa, 0x18,
+	0x42, 0x09, 0x1d, 0x00, 0x50, 0x66, 0x31, 0x6c, 0xba, 0xe4, 0x32, 0xd0, 0xd6, 0x84, 0xc9, 0x7a,
+	0x7a, 0x4d, 0xb8, 0x64, 0x4c, 0x2e, 0x79, 0x77, 0x54, 0x9a, 0x10, 0xe8, 0x08, 0xba, 0xd2, 0x86,
+	0x12, 0x2b, 0xa4, 0x8b, 0x80, 0x69, 0xbd, 0xe2, 0xa1, 0xa7, 0x76, 0xe7, 0xb1, 0xc2, 0xa8, 0x62,
+	0x74, 0x84, 0x49, 0xc2, 0x40, 0x13, 0xd8, 0xc8, 0xe2, 0x9a, 0xe1, 0xd2, 0xf3, 0x44, 0xff, 0xd6,
+	0x85, 0xa3, 0x27, 0x25, 0x47, 0x67, 0x4b, 0xcf, 0xcb, 0x1a, 0xd9, 0xa3, 0xb7, 0xf8, 0xe8, 0x10,
+	0xa4, 0x7f, 0x33, 0x92, 0x4a, 0x1a, 0x2a, 0x0e, 0x94, 0x81, 0xfd, 0x80, 0x61, 0xe1, 0x2e, 0x73,
+	0xd3, 0xa6, 0x39, 0x1a, 0x0d, 0x92, 0xaa, 0xa2, 0x78, 0xe4, 0xb4, 0x0d, 0xe1, 0xe3, 0xd7, 0x77,
+	0xfa, 0x48, 0xa7, 0xb2, 0x43, 0xf3, 0x0c, 0xde, 0x1b, 0x0f, 0x5b, 0xb6, 0x1c, 0x5e, 0x31, 0xa2,
+	0x9b, 0xc5, 0xde, 0xbc, 0x4b, 0xa5, 0xd9, 0xa0, 0x76, 0x32, 0x13, 0x3e, 0xae, 0x6f, 0xa0, 0x13,
+	0x62, 0x1c, 0x99, 0xae, 0x8d, 0x09, 0x73, 0xd9, 0x8d, 0xf6, 0xa8, 0x78, 0x0d, 0xcf, 0x30, 0x8e,
+	0xc6, 0xb1, 0x8c, 0x97, 0x11, 0xe6, 0x68, 0xf4, 0x4f, 0x68, 0x87, 0x91, 0xfb, 0x91, 0x17, 0xc2,
+	0x47, 0x47, 0xdb, 0x2a, 0x36, 0xe2, 0x4c, 0xca, 0x8a, 0x17, 0xa5, 0x15, 0x66, 0x5c, 0x3e, 0x11,
+	0x33, 0x2f, 0x98, 0x99, 0xf3, 0xc5, 0x92, 0x5c, 0x69, 0x8f, 0x8b, 0x13, 0x71, 0xe4, 0x05, 0xb3,
+	0x63, 0x2e, 0xe0, 0x13, 0x31, 0x4b, 0x08, 0xf4, 0x1a, 0xda, 0xc2, 0x26, 0xe9, 0xbe, 0x56, 0xbc,
+	0x06, 0xdc, 0x2a, 0x6b, 0x7b, 0x6b, 0x96, 0x91, 0xba, 0x09, 0xab, 0x17, 0x96, 0x83, 0x3a, 0xa0,
+	0xbe, 0x9f, 0x0e, 0x86, 0x6f, 0xc7, 0xd3, 0xe1, 0xa0, 0x57, 0x41, 0x2a, 0xd4, 0x86, 0x93, 0xb3,
+	0x8b, 0x0f, 0x3d, 0x05, 0xb5, 0xa1, 0x79, 0x6a, 0x9c, 0x98, 0xa7, 0xd3, 0x77, 0x1f, 0x7a, 0x2b,
+	0x5c, 0xef, 0x78, 0x74, 0x38, 0x95, 0xe4, 0x2a, 0xea, 0x41, 0x5b, 0x90, 0x87, 0xd3, 0x81, 0x79,
+	0x6a, 0x9c, 0xf4, 0xaa, 0x68, 0x0d, 0x5a, 0x52, 0xc1, 0x10, 0x8c, 0x5a, 0x1e, 0x49, 0x7f, 0x50,
+	0x40, 0x4d, 0x27, 0x0a, 0x6d, 0x43, 0xd3, 0xc7, 0xcc, 0x12, 0x4d, 0x92, 0x98, 0x9e, 0xd2, 0xa8,
+	0x0f, 0x2a, 0x73, 0x7d, 0x4c, 0x99, 0xe5, 0x87, 0x02, 0x4d, 0x5b, 0x07, 0xbd, 0x7c, 0xf7, 0x2f,
+	0x5c, 0x1f, 0x1b, 0x99, 0x0a, 0x7a, 0x04, 0xf5, 0xf0, 0xca, 0x35, 0x5d, 0x5b, 0x80, 0x6c, 0xdb,
+	0xa8, 0x85, 0x57, 0xee, 0xd8, 0x46, 0x4f, 0x01, 0x62, 0x0c, 0x9e, 0x1c, 0x1e, 0x6b, 0x55, 0x21,
+	0xca, 0x71, 0xf4, 0x43, 0x58, 0x2f, 0x5d, 0x15, 0xf4, 0x02, 0x9a, 0xd8, 0xc3, 0x3e, 0x26, 0x8c,
+	0x6a, 0xca, 0xce, 0x6a, 0x3e, 0x74, 0xfa, 0x60, 0xa5, 0x1a, 0xfa, 0x5f, 0x61, 0xf3, 0xae, 0x4b,
+	0x72, 0x2b, 0xb4, 0x52, 0x0a, 0x3d, 0x85, 0x4e, 0x01, 0x10, 0x72, 0x25, 0x28, 0xf9, 0x12, 0x10,
+	0x54, 0xe7, 0x38, 0x62, 0xf1, 0x93, 0x22, 0xbe, 0x39, 0x6f, 0x61, 0xd1, 0x45, 0x5c, 0xab, 0xf8,
+	0xd6, 0xdf, 0x43, 0x3b, 0x3f, 0x96, 0x0f, 0x71, 0x97, 0x3f, 0x88, 0xd5, 0xe2, 0x41, 0xe8, 0x3e,
+	0xb4, 0x72, 0x18, 0x7a, 0xff, 0xcb, 0x67, 0x0b, 0x54, 0xa6, 0xda, 0xca, 0xce, 0xea, 0xae, 0x6a,
+	0x24, 0x24, 0xea, 0x43, 0xd3, 0xa7, 0x8e, 0xc9, 0x6e, 0xe2, 0x15, 0xa0, 0x9b, 0xcd, 0x24, 0x6f,
+	0xd6, 0x84, 0x3a, 0x17, 0x37, 0x21, 0x36, 0x1a, 0xbe, 0xfc, 0xd0, 0x03, 0x68, 0xe5, 0xde, 0x84,
+	0x7b, 0xc2, 0xe5, 0xf3, 0x5d, 0x29, 0x0d, 0xce, 0xc3, 0x02, 0x7e, 0x06, 0xc8, 0xe0, 0xfe, 0x9e,
+	0x78, 0xbf, 0x83, 0x6a, 0x1c, 0xeb, 0xee, 0x61, 0xa8, 0x7e, 0x55, 0x64, 0x0f, 0x20, 0x7b, 0xce,
+	0x7e, 0xf1, 0xc6, 0xbe, 0x86, 0x56, 0x0e, 0x72, 0xd0, 0x1f, 0x8a, 0xeb, 0x54, 0xeb, 0x60, 0x2d,
+	0xb5, 0x96, 0xec, 0x74, 0xbf, 0xd2, 0xff, 0x05, 0x8d, 0x98, 0x87, 0x1e, 0x43, 0x83, 0xe2, 0x6b,
+	0x93, 0x2c, 0xfd, 0x38, 0xcd, 0x3a, 0xc5, 0xd7, 0xd3, 0xa5, 0x9f, 0x0e, 0x24, 0x3f, 0x0d, 0x55,
+	0x0e, 0x24, 0xe7, 0xe5, 0x26, 0x4a, 0x7c, 0xeb, 0x6f, 0x01, 0x95, 0xf1, 0x0f, 0xbd, 0xbc, 0x9d,
+	0xcc, 0xd6, 0x2d, 0xb0, 0x2c, 0xe5, 0xf4, 0xa3, 0x02, 0xdd, 0xa2, 0x0c, 0xfd, 0x1e, 0xd6, 0xe6,
+	0x81, 0xe7, 0xe1, 0x39, 0x73, 0x03, 0x62, 0x12, 0xcb, 0x97, 0xad, 0x54, 0x8d, 0x6e, 0xc6, 0x9e,
+	0x5a, 0x3e, 0xe6, 0xfb, 0x22, 0x97, 0xd2, 0xd0, 0x9a, 0xe3, 0x38, 0xe1, 0x8c, 0x81, 0x36, 0xa0,
+	0xc6, 0x3e, 0x27, 0x38, 0xa2, 0x1a, 0x55, 0xf6, 0x79, 0x6c, 0xa3, 0xdf, 0x42, 0x27, 0x81, 0xf4,
+	0xe8, 0x13, 0xc5, 0x2c, 0x46, 0x92, 0x04, 0xe7, 0x0d, 0xce, 0xd3, 0x4d, 0x50, 0x53, 0x70, 0x4e,
+	0x1b, 0xa2, 0x64, 0x37, 0x14, 0x6d, 0x41, 0x3d, 0xb8, 0xbc, 0xe4, 0xe6, 0x2b, 0xb2, 0x79, 0x92,
+	0xe2, 0xba, 0xd4, 0xfd, 0x46, 0x1e, 0x63, 0xd5, 0x10, 0xdf, 0x69, 0xf3, 0xaa, 0xb9, 0xe6, 0xfd,
+	0x0d, 0x5a, 0x39, 0x1c, 0x7f, 0x48, 0x08, 0xfd, 0x7b, 0x05, 0xda, 0xf9, 0xdd, 0x11, 0xf5, 0x01,
+	0xfc, 0x74, 0xc5, 0x8b, 0xbb, 0xde, 0x2d, 0x2e, 0x7f, 0x46, 0x4e, 0xe3, 0xc1, 0x78, 0xbc, 0x0d,
+	0xcd, 0xf4, 0xf1, 0x94, 0x35, 0xa4, 0xb4, 0xfe, 0xad, 0x02, 0xeb, 0xa5, 0x47, 0xf8, 0x3e, 0xbc,
+	0x7a, 0x68, 0xe0, 0x67, 0xd0, 0x75, 0xa9, 0x69, 0xe3, 0xb9, 0x67, 0x45, 0x16, 0x3f, 0x73, 0xd1,
+	0xd6, 0xa6, 0xd1, 0x71, 0xe9, 0x20, 0x63, 0xea, 0x47, 0xd0, 0x4c, 0xac, 0xd1, 0x6f, 0x00, 0x5c,
+	0x32, 0xe7, 0x53, 0x3d, 0xc3, 0x51, 0x3c, 0xd8, 0xaa, 0x4b, 0xe6, 0x53, 0xc1, 0xc8, 0x0f, 0xfd,
+	0x4a, 0x7e, 0xe8, 0xf5, 0x4b, 0x58, 0x2f, 0x2d, 0xd7, 0xe8, 0x0d, 0xf4, 0x28, 0xf6, 0x2e, 0xc5,
+	0x56, 0x15, 0xf9, 0x32, 0x03, 0x65, 0x47, 0xb9, 0x13, 0x37, 0xd6, 0xb8, 0xe6, 0x38, 0x53, 0xe4,
+	0x20, 0x70, 0x45, 0x82, 0x4f, 0x44, 0x5c, 0xf6, 0xb6, 0x21, 0x09, 0x7d, 0x06, 0xa8, 0xbc, 0x8e,
+	0xa3, 0xe7, 0x50, 0x13, 0xdb, 0xff, 0xbd, 0x4f, 0x94, 0x14, 0x0b, 0xf0, 0xc2, 0x96, 0xfd, 0x05,
+	0xf0, 0xc2, 0x96, 0xad, 0xff, 0x07, 0xea, 0x32, 0x06, 0x3f, 0x39, 0x5c, 0xf8, 0x3d, 0x32, 0x52,
+	0xfa, 0x8b, 0xc0, 0x7b, 0xf7, 0x0b, 0xac, 0x37, 0xa0, 0x26, 0xb6, 0x63, 0xfd, 0xbf, 0x80, 0xca,
+	0x3b, 0x20, 0xd2, 0xc5, 0xda, 0x18, 0x31, 0xb3, 0x88, 0x2b, 0x2d, 0xc1, 0x3c, 0x97, 0xe0, 0xf2,
+	0x14, 0x5a, 0x98, 0xd8, 0x66, 0xf1, 0x10, 0x54, 0x4c, 0x6c, 0x29, 0xd7, 0x8f, 0x60, 0xe3, 0x8e,
+	0xcd, 0x10, 0xed, 0x41, 0x33, 0x86, 0x8b, 0xe4, 0x19, 0x2f, 0x61, 0x5c, 0xaa, 0xf0, 0xc7, 0xbf,
+	0x43, 0x2b, 0x07, 0x9b, 0xb7, 0xb7, 0xa1, 0x0e, 0xa8, 0x47, 0xef, 0x4e, 0x8f, 0xff, 0x6d, 0x4e,
+	0xce, 0x4f, 0x7a, 0x0a, 0x5f, 0x7a, 0xc6, 0x83, 0xe1, 0xf4, 0x62, 0x7c, 0xf1, 0x41, 0x70, 0x56,
+	0x0e, 0xfe, 0x0f, 0x75, 0xf9, 0x6c, 0xf1, 0x45, 0x4c, 0x7e, 0x9d, 0xb3, 0x08, 0x5b, 0x3e, 0x2a,
+	0x35, 0x7c, 0xbb, 0xc4, 0xd1, 0x2b, 0xbb, 0xca, 0x4b, 0x05, 0x3d, 0x87, 0xea, 0x99, 0x4b, 0x1c,
+	0x54, 0xfc, 0xab, 0xd8, 0x2e, 0x92, 0x7a, 0xe5, 0xe8, 0x4f, 0xff, 0xdb, 0x73, 0x5c, 0xb6, 0x58,
+	0xce, 0xfa, 0xf3, 0xc0, 0xdf, 0x5f, 0xdc, 0x84, 0x38, 0xf2, 0xb0, 0xed, 0xe0, 0x68, 0xff, 0xd2,
+	0x9a, 0x45, 0xee, 0x7c, 0x5f, 0xfc, 0xcf, 0xd3, 0x7d, 0x69, 0x36, 0xab, 0x0b, 0xf2, 0xd5, 0xcf,
+	0x03, 0x00, 0xa8, 0x2a, 0x6f, 0xda, 0xf6, 0x0f, 0x00, 0x00,
 }
@@ -10,8 +10,13 @@ It is generated from these files:
 
 It has these top-level messages:
 	KVRWSet
+	HashedRWSet
 	KVRead
 	KVWrite
+	KVMetadataWrite
+	KVMetadataEntry
+	KVReadHash
+	KVWriteHash
 	Version
 	RangeQueryInfo
 	QueryReads
@@ -36,9 +41,10 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 // KVRWSet encapsulates the read-write set for a chaincode that operates upon a KV or Document data model
 type KVRWSet struct {
-	Reads            []*KVRead         `protobuf:"bytes,1,rep,name=reads" json:"reads,omitempty"`
-	RangeQueriesInfo []*RangeQueryInfo `protobuf:"bytes,2,rep,name=range_queries_info,json=rangeQueriesInfo" json:"range_queries_info,omitempty"`
-	Writes           []*KVWrite        `protobuf:"bytes,3,rep,name=writes" json:"writes,omitempty"`
+	Reads            []*KVRead          `protobuf:"bytes,1,rep,name=reads" json:"reads,omitempty"`
+	RangeQueriesInfo []*RangeQueryInfo  `protobuf:"bytes,2,rep,name=range_queries_info,json=rangeQueriesInfo" json:"range_queries_info,omitempty"`
+	Writes           []*KVWrite         `protobuf:"bytes,3,rep,name=writes" json:"writes,omitempty"`
+	MetadataWrites   []*KVMetadataWrite `protobuf:"bytes,4,rep,name=metadata_writes,json=metadataWrites" json:"metadata_writes,omitempty"`
 }
 
 func (m *KVRWSet) Reset()                    { *m = KVRWSet{} }
@@ -67,6 +73,38 @@ func (m *KVRWSet) GetWrites() []*KVWrite {
 	return nil
 }
 
+func (m *KVRWSet) GetMetadataWrites() []*KVMetadataWrite {
+	if m != nil {
+		return m.MetadataWrites
+	}
+	return nil
+}
+
+// HashedRWSet encapsulates hashed representation of a private read-write set for KV or Document data model
+type HashedRWSet struct {
+	HashedReads  []*KVReadHash  `protobuf:"bytes,1,rep,name=hashed_reads,json=hashedReads" json:"hashed_reads,omitempty"`
+	HashedWrites []*KVWriteHash `protobuf:"bytes,2,rep,name=hashed_writes,json=hashedWrites" json:"hashed_writes,omitempty"`
+}
+
+func (m *HashedRWSet) Reset()                    { *m = HashedRWSet{} }
+func (m *HashedRWSet) String() string            { return proto.CompactTextString(m) }
+func (*HashedRWSet) ProtoMessage()               {}
+func (*HashedRWSet) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *HashedRWSet) GetHashedReads() []*KVReadHash {
+	if m != nil {
+		return m.HashedReads
+	}
+	return nil
+}
+
+func (m *HashedRWSet) GetHashedWrites() []*KVWriteHash {
+	if m != nil {
+		return m.HashedWrites
+	}
+	return nil
+}
+
 // KVRead captures a read operation performed during transaction simulation
 // A 'nil' version indicates a non-existing key read by the transaction
 type KVRead struct {
@@ -77,7 +115,7 @@ type KVRead struct {
 func (m *KVRead) Reset()                    { *m = KVRead{} }
 func (m *KVRead) String() string            { return proto.CompactTextString(m) }
 func (*KVRead) ProtoMessage()               {}
-func (*KVRead) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+func (*KVRead) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
 
 func (m *KVRead) GetVersion() *Version {
 	if m != nil {
@@ -96,7 +134,69 @@ type KVWrite struct {
 func (m *KVWrite) Reset()                    { *m = KVWrite{} }
 func (m *KVWrite) String() string            { return proto.CompactTextString(m) }
 func (*KVWrite) ProtoMessage()               {}
-func (*KVWrite) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*KVWrite) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+// KVMetadataWrite captures all the entries in the metadata associated with a key.
+// The entries replace the existing metadata of the key; no entries removes the metadata
+type KVMetadataWrite struct {
+	Key     string             `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Entries []*KVMetadataEntry `protobuf:"bytes,2,rep,name=entries" json:"entries,omitempty"`
+}
+
+func (m *KVMetadataWrite) Reset()                    { *m = KVMetadataWrite{} }
+func (m *KVMetadataWrite) String() string            { return proto.CompactTextString(m) }
+func (*KVMetadataWrite) ProtoMessage()               {}
+func (*KVMetadataWrite) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *KVMetadataWrite) GetEntries() []*KVMetadataEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+// KVMetadataEntry captures a named entry in the metadata of a key
+type KVMetadataEntry struct {
+	Name  string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *KVMetadataEntry) Reset()                    { *m = KVMetadataEntry{} }
+func (m *KVMetadataEntry) String() string            { return proto.CompactTextString(m) }
+func (*KVMetadataEntry) ProtoMessage()               {}
+func (*KVMetadataEntry) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+// KVReadHash is similar to the KVRead in spirit. However, it captures the hash of the key instead of the key itself
+// version is kept as is for now. However, if the version also needs to be privacy-protected, it would need to be the
+// hash of the version and hence of 'bytes' type
+type KVReadHash struct {
+	KeyHash []byte   `protobuf:"bytes,1,opt,name=key_hash,json=keyHash,proto3" json:"key_hash,omitempty"`
+	Version *Version `protobuf:"bytes,2,opt,name=version" json:"version,omitempty"`
+}
+
+func (m *KVReadHash) Reset()                    { *m = KVReadHash{} }
+func (m *KVReadHash) String() string            { return proto.CompactTextString(m) }
+func (*KVReadHash) ProtoMessage()               {}
+func (*KVReadHash) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *KVReadHash) GetVersion() *Version {
+	if m != nil {
+		return m.Version
+	}
+	return nil
+}
+
+// KVWriteHash is similar to the KVWrite. It captures a write (update/delete) operation performed during transaction simulation
+type KVWriteHash struct {
+	KeyHash   []byte `protobuf:"bytes,1,opt,name=key_hash,json=keyHash,proto3" json:"key_hash,omitempty"`
+	IsDelete  bool   `protobuf:"varint,2,opt,name=is_delete,json=isDelete" json:"is_delete,omitempty"`
+	ValueHash []byte `protobuf:"bytes,3,opt,name=value_hash,json=valueHash,proto3" json:"value_hash,omitempty"`
+}
+
+func (m *KVWriteHash) Reset()                    { *m = KVWriteHash{} }
+func (m *KVWriteHash) String() string            { return proto.CompactTextString(m) }
+func (*KVWriteHash) ProtoMessage()               {}
+func (*KVWriteHash) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
 // Version encapsulates the version of a Key
 // A version of a committed key is maintained as the height of the transaction that committed the key.
@@ -110,7 +210,7 @@ type Version struct {
 func (m *Version) Reset()                    { *m = Version{} }
 func (m *Version) String() string            { return proto.CompactTextString(m) }
 func (*Version) ProtoMessage()               {}
-func (*Version) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*Version) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
 
 // RangeQueryInfo encapsulates the details of a range query performed by a transaction during simulation.
 // This helps protect transactions from phantom reads by varifying during validation whether any new items
@@ -131,11 +231,9 @@ type RangeQueryInfo struct {
 func (m *RangeQueryInfo) Reset()                    { *m = RangeQueryInfo{} }
 func (m *RangeQueryInfo) String() string            { return proto.CompactTextString(m) }
 func (*RangeQueryInfo) ProtoMessage()               {}
-func (*RangeQueryInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (*RangeQueryInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
 
-type isRangeQueryInfo_ReadsInfo interface {
-	isRangeQueryInfo_ReadsInfo()
-}
+type isRangeQueryInfo_ReadsInfo interface{ isRangeQueryInfo_ReadsInfo() }
 
 type RangeQueryInfo_RawReads struct {
 	RawReads *QueryReads `protobuf:"bytes,4,opt,name=raw_reads,json=rawReads,oneof"`
@@ -250,7 +348,7 @@ type QueryReads struct {
 func (m *QueryReads) Reset()                    { *m = QueryReads{} }
 func (m *QueryReads) String() string            { return proto.CompactTextString(m) }
 func (*QueryReads) ProtoMessage()               {}
-func (*QueryReads) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (*QueryReads) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
 
 func (m *QueryReads) GetKvReads() []*KVRead {
 	if m != nil {
@@ -273,12 +371,17 @@ type QueryReadsMerkleSummary struct {
 func (m *QueryReadsMerkleSummary) Reset()                    { *m = QueryReadsMerkleSummary{} }
 func (m *QueryReadsMerkleSummary) String() string            { return proto.CompactTextString(m) }
 func (*QueryReadsMerkleSummary) ProtoMessage()               {}
-func (*QueryReadsMerkleSummary) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*QueryReadsMerkleSummary) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
 
 func init() {
 	proto.RegisterType((*KVRWSet)(nil), "kvrwset.KVRWSet")
+	proto.RegisterType((*HashedRWSet)(nil), "kvrwset.HashedRWSet")
 	proto.RegisterType((*KVRead)(nil), "kvrwset.KVRead")
 	proto.RegisterType((*KVWrite)(nil), "kvrwset.KVWrite")
+	proto.RegisterType((*KVMetadataWrite)(nil), "kvrwset.KVMetadataWrite")
+	proto.RegisterType((*KVMetadataEntry)(nil), "kvrwset.KVMetadataEntry")
+	proto.RegisterType((*KVReadHash)(nil), "kvrwset.KVReadHash")
+	proto.RegisterType((*KVWriteHash)(nil), "kvrwset.KVWriteHash")
 	proto.RegisterType((*Version)(nil), "kvrwset.Version")
 	proto.RegisterType((*RangeQueryInfo)(nil), "kvrwset.RangeQueryInfo")
 	proto.RegisterType((*QueryReads)(nil), "kvrwset.QueryReads")
@@ -288,40 +391,50 @@ func init() {
 func init() { proto.RegisterFile("ledger/rwset/kvrwset/kv_rwset.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 552 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x7c, 0x53, 0x4d, 0x6b, 0xdb, 0x40,
-	0x10, 0x8d, 0xec, 0xd8, 0x92, 0xa7, 0x4e, 0xea, 0x6e, 0x5a, 0x22, 0x28, 0x05, 0xa3, 0x50, 0x30,
-	0x39, 0x48, 0x90, 0x5e, 0xda, 0x43, 0x2f, 0x25, 0x29, 0x29, 0x69, 0x03, 0xdd, 0x80, 0x03, 0xbd,
-	0x88, 0xb5, 0x35, 0xb6, 0x85, 0xf5, 0x91, 0xee, 0xae, 0x6c, 0xf9, 0x54, 0xfa, 0x53, 0xfa, 0x4f,
-	0xcb, 0x8e, 0x24, 0x3b, 0x01, 0xb7, 0x27, 0xed, 0xbc, 0x37, 0x6f, 0x76, 0xe6, 0x69, 0x16, 0xce,
-	0x12, 0x8c, 0xe6, 0x28, 0x03, 0xb9, 0x56, 0xa8, 0x83, 0xe5, 0xaa, 0xf9, 0x86, 0x74, 0xf0, 0x1f,
-	0x64, 0xae, 0x73, 0x66, 0xd7, 0xb8, 0xf7, 0xc7, 0x02, 0xfb, 0x66, 0xcc, 0xef, 0xef, 0x50, 0xb3,
-	0xb7, 0xd0, 0x91, 0x28, 0x22, 0xe5, 0x5a, 0xc3, 0xf6, 0xe8, 0xd9, 0xc5, 0x73, 0xbf, 0x4e, 0xf2,
-	0x6f, 0xc6, 0x1c, 0x45, 0xc4, 0x2b, 0x96, 0x5d, 0x01, 0x93, 0x22, 0x9b, 0x63, 0xf8, 0xb3, 0x40,
-	0x19, 0xa3, 0x0a, 0xe3, 0x6c, 0x96, 0xbb, 0x2d, 0xd2, 0x9c, 0x6e, 0x35, 0xdc, 0xa4, 0x7c, 0x2f,
-	0x50, 0x6e, 0xbe, 0x64, 0xb3, 0x9c, 0x0f, 0x64, 0x13, 0xc7, 0xa8, 0x0c, 0xc2, 0x46, 0xd0, 0x5d,
-	0xcb, 0x58, 0xa3, 0x72, 0xdb, 0x24, 0x1d, 0x3c, 0xba, 0xee, 0xde, 0x10, 0xbc, 0xe6, 0xbd, 0xcf,
-	0xd0, 0xad, 0x3a, 0x60, 0x03, 0x68, 0x2f, 0x71, 0xe3, 0x5a, 0x43, 0x6b, 0xd4, 0xe3, 0xe6, 0xc8,
-	0xce, 0xc1, 0x5e, 0xa1, 0x54, 0x71, 0x9e, 0xb9, 0xad, 0xa1, 0xf5, 0xa4, 0xcc, 0xb8, 0xc2, 0x79,
-	0x93, 0xe0, 0xdd, 0x9a, 0x51, 0xa9, 0xf4, 0x9e, 0x42, 0xaf, 0xa1, 0x17, 0xab, 0x30, 0xc2, 0x04,
-	0x35, 0x52, 0x29, 0x87, 0x3b, 0xb1, 0xba, 0xa4, 0x98, 0xbd, 0x84, 0xce, 0x4a, 0x24, 0x05, 0xba,
-	0xed, 0xa1, 0x35, 0xea, 0xf3, 0x2a, 0xf0, 0x3e, 0x82, 0x5d, 0xdf, 0x61, 0xd4, 0x93, 0x24, 0x9f,
-	0x2e, 0xc3, 0xac, 0x48, 0xa9, 0xea, 0x21, 0x77, 0x08, 0xb8, 0x2d, 0x52, 0xf6, 0x0a, 0xba, 0xba,
-	0x24, 0xa6, 0x45, 0x4c, 0x47, 0x97, 0xb7, 0x45, 0xea, 0xfd, 0x6e, 0xc1, 0xf1, 0x53, 0x97, 0x4c,
-	0x19, 0xa5, 0x85, 0xd4, 0xe1, 0xae, 0x39, 0x87, 0x80, 0x1b, 0xdc, 0xb0, 0x53, 0xb0, 0x31, 0x8b,
-	0x88, 0x6a, 0x11, 0xd5, 0xc5, 0x2c, 0x32, 0xc4, 0x19, 0x1c, 0xc5, 0x5a, 0x86, 0x58, 0x2e, 0x44,
-	0xa1, 0x34, 0x46, 0xd4, 0xa5, 0xc3, 0xfb, 0xb1, 0x96, 0x57, 0x0d, 0xc6, 0x2e, 0xa0, 0x27, 0xc5,
-	0x3a, 0xac, 0x7e, 0xf0, 0x21, 0x59, 0x75, 0xb2, 0xb5, 0x8a, 0x3a, 0x30, 0x0e, 0xab, 0xeb, 0x03,
-	0xee, 0x48, 0xb1, 0xa6, 0x33, 0xe3, 0x70, 0x42, 0xf9, 0x61, 0x8a, 0x72, 0x99, 0x60, 0xb8, 0x10,
-	0x6a, 0x81, 0xca, 0xed, 0x90, 0x7a, 0xb8, 0x47, 0xfd, 0x8d, 0xf2, 0xee, 0x8a, 0x34, 0x15, 0x72,
-	0x73, 0x7d, 0xc0, 0x5f, 0xc8, 0x1d, 0x7a, 0x4d, 0xe2, 0x4f, 0x7d, 0x80, 0xaa, 0xa6, 0xd9, 0x1a,
-	0xef, 0x3d, 0xc0, 0x4e, 0xcd, 0xce, 0xc1, 0x31, 0x7b, 0xfa, 0xbf, 0x1d, 0xb4, 0x97, 0x2b, 0xca,
-	0xf5, 0x7e, 0xc1, 0xe9, 0x3f, 0xee, 0x65, 0x6f, 0x00, 0x52, 0x51, 0x86, 0x11, 0xce, 0x25, 0x22,
-	0xd9, 0x78, 0xc4, 0x7b, 0xa9, 0x28, 0x2f, 0x09, 0x30, 0x26, 0x1b, 0x3a, 0xc1, 0x15, 0x26, 0xe4,
-	0xe4, 0x11, 0x77, 0x52, 0x51, 0x7e, 0x35, 0x31, 0x1b, 0xc1, 0x60, 0x4b, 0x36, 0xf3, 0x9a, 0xfd,
-	0xec, 0xf3, 0xe3, 0x26, 0xa7, 0x1e, 0x24, 0x87, 0x8b, 0x5c, 0xce, 0xfd, 0xc5, 0xe6, 0x01, 0x65,
-	0xf5, 0xe4, 0xfc, 0x99, 0x98, 0xc8, 0x78, 0x5a, 0x3d, 0x31, 0xe5, 0xd7, 0x60, 0xd5, 0x7e, 0x3d,
-	0xc6, 0x8f, 0x0f, 0xf3, 0x58, 0x2f, 0x8a, 0x89, 0x3f, 0xcd, 0xd3, 0xe0, 0x91, 0x34, 0xa8, 0xa4,
-	0x41, 0x25, 0x0d, 0xf6, 0x3d, 0xe1, 0x49, 0x97, 0xc8, 0x77, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff,
-	0x7e, 0x2f, 0xc5, 0xa9, 0xe1, 0x03, 0x00, 0x00,
+	// 705 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x94, 0x54, 0xdf, 0x6b, 0xdb, 0x40,
+	0x0c, 0xae, 0xf3, 0xd3, 0x51, 0x92, 0x26, 0xbb, 0x76, 0xd4, 0x63, 0x0c, 0x82, 0xcb, 0x20, 0xf4,
+	0x21, 0x81, 0x0c, 0xc6, 0xca, 0xd8, 0xc3, 0x46, 0x3b, 0x3a, 0xba, 0x16, 0x76, 0x85, 0x16, 0xf6,
+	0x62, 0x2e, 0xb5, 0x9a, 0x98, 0xc4, 0x76, 0x77, 0x3e, 0x27, 0xf1, 0xd3, 0xb6, 0xff, 0x75, 0x7f,
+	0xc8, 0x38, 0x9d, 0xd3, 0xa4, 0x21, 0x2b, 0xec, 0xc9, 0x27, 0x7d, 0xfa, 0x74, 0xd2, 0x27, 0x9f,
+	0xe0, 0x70, 0x8a, 0xfe, 0x08, 0x65, 0x5f, 0xce, 0x13, 0x54, 0xfd, 0xc9, 0x6c, 0xf9, 0xf5, 0xe8,
+	0xd0, 0xbb, 0x97, 0xb1, 0x8a, 0x59, 0x35, 0xf7, 0xbb, 0x7f, 0x2c, 0xa8, 0x9e, 0x5f, 0xf3, 0x9b,
+	0x2b, 0x54, 0xec, 0x35, 0x94, 0x25, 0x0a, 0x3f, 0x71, 0xac, 0x4e, 0xb1, 0x5b, 0x1f, 0xb4, 0x7a,
+	0x79, 0x50, 0xef, 0xfc, 0x9a, 0xa3, 0xf0, 0xb9, 0x41, 0xd9, 0x29, 0x30, 0x29, 0xa2, 0x11, 0x7a,
+	0x3f, 0x52, 0x94, 0x01, 0x26, 0x5e, 0x10, 0xdd, 0xc5, 0x4e, 0x81, 0x38, 0x07, 0x0f, 0x1c, 0xae,
+	0x43, 0xbe, 0xa5, 0x28, 0xb3, 0x2f, 0xd1, 0x5d, 0xcc, 0xdb, 0x72, 0x69, 0x07, 0x98, 0x68, 0x0f,
+	0xeb, 0x42, 0x65, 0x2e, 0x03, 0x85, 0x89, 0x53, 0x24, 0x6a, 0x7b, 0xed, 0xba, 0x1b, 0x0d, 0xf0,
+	0x1c, 0x67, 0x1f, 0xa1, 0x15, 0xa2, 0x12, 0xbe, 0x50, 0xc2, 0xcb, 0x29, 0x25, 0xa2, 0x38, 0x6b,
+	0x94, 0x8b, 0x3c, 0xc2, 0x50, 0x77, 0xc3, 0x75, 0x33, 0x71, 0x7f, 0x59, 0x50, 0x3f, 0x13, 0xc9,
+	0x18, 0x7d, 0xd3, 0xea, 0x5b, 0x68, 0x8c, 0xc9, 0xf4, 0xd6, 0x3b, 0xde, 0xdb, 0xe8, 0x58, 0x33,
+	0x78, 0xdd, 0x04, 0x72, 0xea, 0xfd, 0x18, 0x9a, 0x39, 0x2f, 0x2f, 0xc4, 0xb4, 0xbd, 0xbf, 0x59,
+	0x3b, 0x31, 0xf3, 0x2b, 0xf2, 0x12, 0x3e, 0x43, 0xc5, 0x64, 0x65, 0x6d, 0x28, 0x4e, 0x30, 0x73,
+	0xac, 0x8e, 0xd5, 0xad, 0x71, 0x7d, 0x64, 0x47, 0x50, 0x9d, 0xa1, 0x4c, 0x82, 0x38, 0x72, 0x0a,
+	0x1d, 0xeb, 0x91, 0x18, 0xd7, 0xc6, 0xcf, 0x97, 0x01, 0xee, 0xa5, 0x1e, 0x18, 0xe5, 0xdc, 0x92,
+	0xe8, 0x25, 0xd4, 0x82, 0xc4, 0xf3, 0x71, 0x8a, 0x0a, 0x29, 0x95, 0xcd, 0xed, 0x20, 0x39, 0x21,
+	0x9b, 0xed, 0x43, 0x79, 0x26, 0xa6, 0x29, 0x3a, 0xc5, 0x8e, 0xd5, 0x6d, 0x70, 0x63, 0xb8, 0x37,
+	0xd0, 0xda, 0x50, 0x6f, 0x4b, 0xde, 0x01, 0x54, 0x31, 0x52, 0x32, 0x78, 0xe8, 0x78, 0x9b, 0xf4,
+	0xa7, 0x91, 0x92, 0x19, 0x5f, 0x06, 0xba, 0xef, 0xa1, 0xb5, 0x81, 0x31, 0x06, 0xa5, 0x48, 0x84,
+	0x98, 0x67, 0xa6, 0xf3, 0xaa, 0xaa, 0xc2, 0x7a, 0x55, 0x57, 0x00, 0xab, 0x19, 0xb0, 0x17, 0x60,
+	0x4f, 0x30, 0xf3, 0xb4, 0x9e, 0xc4, 0x6d, 0xf0, 0xea, 0x04, 0x33, 0x82, 0xfe, 0x47, 0x3a, 0x1f,
+	0xea, 0x6b, 0xf3, 0x79, 0x2a, 0xeb, 0x93, 0x3a, 0xbe, 0x02, 0xa0, 0x22, 0x0d, 0xd3, 0x88, 0x59,
+	0x23, 0x8f, 0xe6, 0xba, 0x1f, 0xa0, 0x9a, 0xdf, 0xac, 0xd3, 0x0c, 0xa7, 0xf1, 0xed, 0xc4, 0x8b,
+	0xd2, 0x90, 0xae, 0x28, 0x71, 0x9b, 0x1c, 0x97, 0x69, 0xc8, 0x9e, 0x43, 0x45, 0x2d, 0x08, 0x29,
+	0x10, 0x52, 0x56, 0x8b, 0xcb, 0x34, 0x74, 0x7f, 0x17, 0x60, 0xf7, 0xf1, 0xe3, 0xd1, 0x69, 0x12,
+	0x25, 0xa4, 0xf2, 0x56, 0x53, 0xb1, 0xc9, 0x71, 0x8e, 0x19, 0x3b, 0xd0, 0xa3, 0xf1, 0x09, 0x2a,
+	0x10, 0x54, 0xc1, 0xc8, 0xd7, 0xc0, 0x21, 0x34, 0x03, 0x25, 0x3d, 0x5c, 0x8c, 0x45, 0x9a, 0x28,
+	0xf4, 0xa9, 0x52, 0x9b, 0x37, 0x02, 0x25, 0x4f, 0x97, 0x3e, 0x36, 0x80, 0x9a, 0x14, 0xf3, 0xfc,
+	0x15, 0x94, 0x3a, 0xd6, 0xa3, 0x57, 0x40, 0x15, 0xd0, 0x8f, 0x7f, 0xb6, 0xc3, 0x6d, 0x29, 0xe6,
+	0x74, 0x66, 0x1c, 0xf6, 0x28, 0xde, 0x0b, 0x51, 0x4e, 0xa6, 0x46, 0x06, 0x4c, 0x9c, 0x32, 0xb1,
+	0x3b, 0x5b, 0xd8, 0x17, 0x14, 0x77, 0x95, 0x86, 0xa1, 0x90, 0xd9, 0xd9, 0x0e, 0x7f, 0x26, 0x57,
+	0x5e, 0x7a, 0x95, 0xc9, 0xa7, 0x06, 0x80, 0xc9, 0xa9, 0x97, 0x89, 0xfb, 0x0e, 0x60, 0xc5, 0x66,
+	0x47, 0x60, 0xeb, 0xf5, 0xf5, 0xd4, 0x6a, 0xaa, 0x4e, 0x66, 0x14, 0xeb, 0xfe, 0x84, 0x83, 0x7f,
+	0xdc, 0xab, 0xc7, 0x16, 0x8a, 0x85, 0xe7, 0xe3, 0x48, 0xa2, 0xf9, 0x05, 0x9b, 0xbc, 0x16, 0x8a,
+	0xc5, 0x09, 0x39, 0xb4, 0xc8, 0x1a, 0x9e, 0xe2, 0x0c, 0xa7, 0xa4, 0x64, 0x93, 0xdb, 0xa1, 0x58,
+	0x7c, 0xd5, 0x36, 0xeb, 0x42, 0xfb, 0x01, 0x5c, 0xf6, 0xab, 0xd7, 0x56, 0x83, 0xef, 0x2e, 0x63,
+	0xf2, 0x46, 0x62, 0x18, 0xc4, 0x72, 0xd4, 0x1b, 0x67, 0xf7, 0x28, 0xcd, 0x26, 0xee, 0xdd, 0x89,
+	0xa1, 0x0c, 0x6e, 0xcd, 0xe6, 0x4d, 0x7a, 0xb9, 0xd3, 0x94, 0x9f, 0xb7, 0xf1, 0xfd, 0x78, 0x14,
+	0xa8, 0x71, 0x3a, 0xec, 0xdd, 0xc6, 0x61, 0x7f, 0x8d, 0xda, 0x37, 0xd4, 0xbe, 0xa1, 0xf6, 0xb7,
+	0x6d, 0xf6, 0x61, 0x85, 0xc0, 0x37, 0x7f, 0x07, 0x00, 0xd4, 0xc6, 0x7b, 0x5d, 0xf8, 0x05, 0x00,
+	0x00,
 }
@@ -7,6 +7,7 @@ Package orderer is a generated protocol buffer package.
 
 It is generated from these files:
 	orderer/ab.proto
+	orderer/admin.proto
 	orderer/configuration.proto
 	orderer/kafka.proto
 
@@ -18,10 +19,14 @@ It has these top-level messages:
 	SeekPosition
 	SeekInfo
 	DeliverResponse
+	StatusRequest
+	ChannelStatus
+	StatusResponse
 	ConsensusType
 	BatchSize
 	BatchTimeout
 	KafkaBrokers
+	RaftConsenters
 	ChannelRestrictions
 	KafkaMessage
 	KafkaMessageRegular
@@ -35,6 +40,7 @@ import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
 import common "github.com/hyperledger/fabric/protos/common"
+import protos3 "github.com/hyperledger/fabric/protos/peer"
 
 import (
 	context "golang.org/x/net/context"
@@ -73,6 +79,31 @@ func (x SeekInfo_SeekBehavior) String() string {
 }
 func (SeekInfo_SeekBehavior) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{5, 0} }
 
+// SeekContentType indicates what is delivered for each block
+type SeekInfo_SeekContentType int32
+
+const (
+	SeekInfo_BLOCK                      SeekInfo_SeekContentType = 0
+	SeekInfo_FILTERED_BLOCK             SeekInfo_SeekContentType = 1
+	SeekInfo_FILTERED_BLOCK_WITH_EVENTS SeekInfo_SeekContentType = 2
+)
+
+var SeekInfo_SeekContentType_name = map[int32]string{
+	0: "BLOCK",
+	1: "FILTERED_BLOCK",
+	2: "FILTERED_BLOCK_WITH_EVENTS",
+}
+var SeekInfo_SeekContentType_value = map[string]int32{
+	"BLOCK":                      0,
+	"FILTERED_BLOCK":             1,
+	"FILTERED_BLOCK_WITH_EVENTS": 2,
+}
+
+func (x SeekInfo_SeekContentType) String() string {
+	return proto.EnumName(SeekInfo_SeekContentType_name, int32(x))
+}
+func (SeekInfo_SeekContentType) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{5, 1} }
+
 type BroadcastResponse struct {
 	Status common.Status `protobuf:"varint,1,opt,name=status,enum=common.Status" json:"status,omitempty"`
 }
@@ -268,9 +299,10 @@ func _SeekPosition_OneofSizer(msg proto.Message) (n int) {
 // as they are created, behavior should be set to BLOCK_UNTIL_READY and the stop should be set to
 // specified with a number of MAX_UINT64
 type SeekInfo struct {
-	Start    *SeekPosition         `protobuf:"bytes,1,opt,name=start" json:"start,omitempty"`
-	Stop     *SeekPosition         `protobuf:"bytes,2,opt,name=stop" json:"stop,omitempty"`
-	Behavior SeekInfo_SeekBehavior `protobuf:"varint,3,opt,name=behavior,enum=orderer.SeekInfo_SeekBehavior" json:"behavior,omitempty"`
+	Start       *SeekPosition            `protobuf:"bytes,1,opt,name=start" json:"start,omitempty"`
+	Stop        *SeekPosition            `protobuf:"bytes,2,opt,name=stop" json:"stop,omitempty"`
+	Behavior    SeekInfo_SeekBehavior    `protobuf:"varint,3,opt,name=behavior,enum=orderer.SeekInfo_SeekBehavior" json:"behavior,omitempty"`
+	ContentType SeekInfo_SeekContentType `protobuf:"varint,4,opt,name=content_type,json=contentType,enum=orderer.SeekInfo_SeekContentType" json:"content_type,omitempty"`
 }
 
 func (m *SeekInfo) Reset()                    { *m = SeekInfo{} }
@@ -296,6 +328,7 @@ type DeliverResponse struct {
 	// Types that are valid to be assigned to Type:
 	//	*DeliverResponse_Status
 	//	*DeliverResponse_Block
+	//	*DeliverResponse_FilteredBlock
 	Type isDeliverResponse_Type `protobuf_oneof:"Type"`
 }
 
@@ -314,9 +347,13 @@ type DeliverResponse_Status struct {
 type DeliverResponse_Block struct {
 	Block *common.Block `protobuf:"bytes,2,opt,name=block,oneof"`
 }
+type DeliverResponse_FilteredBlock struct {
+	FilteredBlock *protos3.FilteredBlock `protobuf:"bytes,3,opt,name=filtered_block,json=filteredBlock,oneof"`
+}
 
-func (*DeliverResponse_Status) isDeliverResponse_Type() {}
-func (*DeliverResponse_Block) isDeliverResponse_Type()  {}
+func (*DeliverResponse_Status) isDeliverResponse_Type()        {}
+func (*DeliverResponse_Block) isDeliverResponse_Type()         {}
+func (*DeliverResponse_FilteredBlock) isDeliverResponse_Type() {}
 
 func (m *DeliverResponse) GetType() isDeliverResponse_Type {
 	if m != nil {
@@ -339,11 +376,19 @@ func (m *DeliverResponse) GetBlock() *common.Block {
 	return nil
 }
 
+func (m *DeliverResponse) GetFilteredBlock() *protos3.FilteredBlock {
+	if x, ok := m.GetType().(*DeliverResponse_FilteredBlock); ok {
+		return x.FilteredBlock
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*DeliverResponse) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _DeliverResponse_OneofMarshaler, _DeliverResponse_OneofUnmarshaler, _DeliverResponse_OneofSizer, []interface{}{
 		(*DeliverResponse_Status)(nil),
 		(*DeliverResponse_Block)(nil),
+		(*DeliverResponse_FilteredBlock)(nil),
 	}
 }
 
@@ -359,6 +404,11 @@ func _DeliverResponse_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.Block); err != nil {
 			return err
 		}
+	case *DeliverResponse_FilteredBlock:
+		b.EncodeVarint(3<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.FilteredBlock); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("DeliverResponse.Type has unexpected type %T", x)
@@ -384,6 +434,14 @@ func _DeliverResponse_OneofUnmarshaler(msg proto.Message, tag, wire int, b *prot
 		err := b.DecodeMessage(msg)
 		m.Type = &DeliverResponse_Block{msg}
 		return true, err
+	case 3: // Type.filtered_block
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(protos3.FilteredBlock)
+		err := b.DecodeMessage(msg)
+		m.Type = &DeliverResponse_FilteredBlock{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -401,6 +459,11 @@ func _DeliverResponse_OneofSizer(msg proto.Message) (n int) {
 		n += proto.SizeVarint(2<<3 | proto.WireBytes)
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *DeliverResponse_FilteredBlock:
+		s := proto.Size(x.FilteredBlock)
+		n += proto.SizeVarint(3<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -417,6 +480,7 @@ func init() {
 	proto.RegisterType((*SeekInfo)(nil), "orderer.SeekInfo")
 	proto.RegisterType((*DeliverResponse)(nil), "orderer.DeliverResponse")
 	proto.RegisterEnum("orderer.SeekInfo_SeekBehavior", SeekInfo_SeekBehavior_name, SeekInfo_SeekBehavior_value)
+	proto.RegisterEnum("orderer.SeekInfo_SeekContentType", SeekInfo_SeekContentType_name, SeekInfo_SeekContentType_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -595,36 +659,43 @@ var _AtomicBroadcast_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("orderer/ab.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 492 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x7c, 0x93, 0xdb, 0x6e, 0xd3, 0x4c,
-	0x10, 0xc7, 0xe3, 0xef, 0x4b, 0xd3, 0x76, 0x68, 0xd3, 0x74, 0xab, 0x56, 0x51, 0x2e, 0x10, 0xb2,
-	0x54, 0x08, 0x02, 0x6c, 0x14, 0x24, 0x2e, 0x28, 0x12, 0x8a, 0x69, 0xab, 0x44, 0x44, 0x09, 0x72,
-	0xd2, 0x0b, 0xb8, 0x89, 0x7c, 0x98, 0x24, 0xa6, 0x8e, 0xd7, 0xda, 0xdd, 0x04, 0xf5, 0x29, 0x78,
-	0x11, 0x1e, 0x89, 0x87, 0x41, 0x7b, 0xb0, 0x43, 0xa0, 0x70, 0x65, 0xcf, 0xcc, 0xef, 0x3f, 0x27,
-	0xcd, 0x42, 0x83, 0xb2, 0x18, 0x19, 0x32, 0x37, 0x08, 0x9d, 0x9c, 0x51, 0x41, 0xc9, 0xae, 0xf1,
-	0xb4, 0x4e, 0x22, 0xba, 0x5c, 0xd2, 0xcc, 0xd5, 0x1f, 0x1d, 0xb5, 0x2f, 0xe0, 0xd8, 0x63, 0x34,
-	0x88, 0xa3, 0x80, 0x0b, 0x1f, 0x79, 0x4e, 0x33, 0x8e, 0xe4, 0x31, 0xd4, 0xb8, 0x08, 0xc4, 0x8a,
-	0x37, 0xad, 0x47, 0x56, 0xbb, 0xde, 0xa9, 0x3b, 0x46, 0x33, 0x56, 0x5e, 0xdf, 0x44, 0xed, 0x03,
-	0x80, 0x31, 0xe2, 0xed, 0x10, 0xbf, 0x22, 0x17, 0x85, 0x35, 0x4a, 0x63, 0x69, 0x3d, 0x81, 0x43,
-	0x69, 0x8d, 0x73, 0x8c, 0x92, 0x59, 0x82, 0x31, 0x39, 0x83, 0x5a, 0xb6, 0x5a, 0x86, 0xc8, 0x54,
-	0xd2, 0xaa, 0x6f, 0x2c, 0xfb, 0xbb, 0x05, 0x07, 0x92, 0xfc, 0x48, 0x79, 0x22, 0x12, 0x9a, 0x91,
-	0x17, 0x50, 0xcb, 0x54, 0x46, 0x05, 0x3e, 0xe8, 0x9c, 0x38, 0x66, 0x02, 0x67, 0x53, 0xac, 0x57,
-	0xf1, 0x0d, 0x24, 0x71, 0xaa, 0x4a, 0x36, 0xff, 0xbb, 0x07, 0xd7, 0xdd, 0x48, 0x5c, 0x43, 0xe4,
-	0x35, 0xec, 0xf3, 0xa2, 0xa7, 0xe6, 0xff, 0x4a, 0x71, 0xb6, 0xa5, 0x28, 0x3b, 0xee, 0x55, 0xfc,
-	0x0d, 0xea, 0xd5, 0xa0, 0x3a, 0xb9, 0xcb, 0xd1, 0xfe, 0x61, 0xc1, 0x9e, 0xc4, 0xfa, 0xd9, 0x8c,
-	0x92, 0x67, 0xb0, 0xc3, 0x45, 0xc0, 0x8a, 0x4e, 0x4f, 0xb7, 0x12, 0x15, 0x03, 0xf9, 0x9a, 0x21,
-	0x4f, 0xa1, 0xca, 0x05, 0xcd, 0x4d, 0x9b, 0x7f, 0x61, 0x15, 0x42, 0xde, 0xc0, 0x5e, 0x88, 0x8b,
-	0x60, 0x9d, 0x50, 0xa6, 0x7a, 0xac, 0x77, 0x1e, 0x6e, 0xe1, 0xb2, 0xb8, 0xfa, 0xf1, 0x0c, 0xe5,
-	0x97, 0xbc, 0xfd, 0x56, 0xaf, 0xb3, 0x88, 0x90, 0x53, 0x38, 0xf6, 0x06, 0xa3, 0xf7, 0x1f, 0xa6,
-	0x37, 0xc3, 0x49, 0x7f, 0x30, 0xf5, 0xaf, 0xba, 0x97, 0x9f, 0x1a, 0x15, 0xe9, 0xbe, 0xee, 0xf6,
-	0x07, 0xd3, 0xfe, 0xf5, 0x74, 0x38, 0x9a, 0x18, 0xb7, 0x65, 0x7f, 0x81, 0xa3, 0x4b, 0x4c, 0x93,
-	0x35, 0xb2, 0xf2, 0x1a, 0xda, 0xff, 0xbe, 0x06, 0xb9, 0x5b, 0x1d, 0x27, 0xe7, 0xb0, 0x13, 0xa6,
-	0x34, 0xba, 0x35, 0x23, 0x1e, 0x16, 0xa0, 0x27, 0x9d, 0xbd, 0x8a, 0xaf, 0xa3, 0xc5, 0x2a, 0x3b,
-	0xdf, 0x2c, 0x38, 0xea, 0x0a, 0xba, 0x4c, 0xa2, 0xf2, 0x04, 0xc9, 0x3b, 0xd8, 0xdf, 0x18, 0x8d,
-	0x22, 0xc1, 0x55, 0xb6, 0xc6, 0x94, 0xe6, 0xd8, 0x6a, 0x95, 0x6b, 0xf8, 0xe3, 0x6a, 0xed, 0x4a,
-	0xdb, 0x7a, 0x69, 0x91, 0x0b, 0xd8, 0x35, 0x03, 0xdc, 0x23, 0x6f, 0x96, 0xf2, 0xdf, 0x86, 0xd4,
-	0x62, 0xef, 0x06, 0xce, 0x29, 0x9b, 0x3b, 0x8b, 0xbb, 0x1c, 0x59, 0x8a, 0xf1, 0x1c, 0x99, 0x33,
-	0x0b, 0x42, 0x96, 0x44, 0xfa, 0xb5, 0xf0, 0x42, 0xfe, 0xf9, 0xf9, 0x3c, 0x11, 0x8b, 0x55, 0x28,
-	0x0b, 0xb8, 0xbf, 0xd0, 0xae, 0xa6, 0x5d, 0x4d, 0xbb, 0x86, 0x0e, 0x6b, 0xca, 0x7e, 0xf5, 0x33,
-	0x00, 0x00, 0xff, 0xff, 0xee, 0xc5, 0xfc, 0x14, 0x9d, 0x03, 0x00, 0x00,
+	// 598 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x7c, 0x93, 0xdf, 0x4e, 0x1a, 0x4f,
+	0x14, 0xc7, 0x59, 0x44, 0xd4, 0x23, 0x22, 0x8e, 0xd1, 0x10, 0x2e, 0xcc, 0xef, 0xb7, 0x89, 0xad,
+	0x4d, 0xdb, 0xdd, 0x86, 0x26, 0xbd, 0xa8, 0x4d, 0x1b, 0x11, 0x08, 0xa4, 0x04, 0xcd, 0x82, 0x6d,
+	0xda, 0x9b, 0xcd, 0xee, 0x72, 0xd0, 0x8d, 0xb0, 0xb3, 0x99, 0x19, 0x69, 0x7c, 0x8a, 0xbe, 0x47,
+	0xd3, 0xc7, 0xeb, 0x03, 0x34, 0xf3, 0x67, 0x41, 0x2c, 0xf1, 0x6a, 0xf7, 0x7c, 0xcf, 0xe7, 0x7b,
+	0xe6, 0x9c, 0xf9, 0x03, 0x15, 0xca, 0x46, 0xc8, 0x90, 0xb9, 0x41, 0xe8, 0xa4, 0x8c, 0x0a, 0x4a,
+	0x36, 0x8c, 0x52, 0xdb, 0x8f, 0xe8, 0x74, 0x4a, 0x13, 0x57, 0x7f, 0x74, 0xb6, 0xb6, 0x97, 0x22,
+	0x32, 0x17, 0x67, 0x98, 0x08, 0xae, 0x25, 0xfb, 0x14, 0xf6, 0x1a, 0x8c, 0x06, 0xa3, 0x28, 0xe0,
+	0xc2, 0x43, 0x9e, 0xd2, 0x84, 0x23, 0x79, 0x06, 0x45, 0x2e, 0x02, 0x71, 0xc7, 0xab, 0xd6, 0x7f,
+	0xd6, 0x49, 0xb9, 0x5e, 0x76, 0x4c, 0x99, 0x81, 0x52, 0x3d, 0x93, 0xb5, 0x4b, 0x00, 0x03, 0xc4,
+	0xdb, 0x3e, 0xfe, 0x40, 0x2e, 0xb2, 0xe8, 0x62, 0x32, 0x92, 0xd1, 0x73, 0xd8, 0x91, 0xd1, 0x20,
+	0xc5, 0x28, 0x1e, 0xc7, 0x38, 0x22, 0x87, 0x50, 0x4c, 0xee, 0xa6, 0x21, 0x32, 0x55, 0xb4, 0xe0,
+	0x99, 0xc8, 0xfe, 0x6d, 0x41, 0x49, 0x92, 0x97, 0x94, 0xc7, 0x22, 0xa6, 0x09, 0x79, 0x0d, 0xc5,
+	0x44, 0x55, 0x54, 0xe0, 0x76, 0x7d, 0xdf, 0x31, 0x43, 0x39, 0x8b, 0xc5, 0x3a, 0x39, 0xcf, 0x40,
+	0x12, 0xa7, 0x6a, 0xc9, 0x6a, 0x7e, 0x05, 0xae, 0xbb, 0x91, 0xb8, 0x86, 0xc8, 0x3b, 0xd8, 0xe2,
+	0x59, 0x4f, 0xd5, 0x35, 0xe5, 0x38, 0x5c, 0x72, 0xcc, 0x3b, 0xee, 0xe4, 0xbc, 0x05, 0xda, 0x28,
+	0x42, 0x61, 0x78, 0x9f, 0xa2, 0xfd, 0x27, 0x0f, 0x9b, 0x12, 0xeb, 0x26, 0x63, 0x4a, 0x5e, 0xc2,
+	0x3a, 0x17, 0x01, 0xcb, 0x3a, 0x3d, 0x58, 0x2a, 0x94, 0x0d, 0xe4, 0x69, 0x86, 0xbc, 0x80, 0x02,
+	0x17, 0x34, 0xad, 0xe6, 0x9f, 0x62, 0x15, 0x42, 0xde, 0xc3, 0x66, 0x88, 0x37, 0xc1, 0x2c, 0xa6,
+	0x4c, 0xf5, 0x58, 0xae, 0x1f, 0x2d, 0xe1, 0x72, 0x71, 0xf5, 0xd3, 0x30, 0x94, 0x37, 0xe7, 0x49,
+	0x13, 0x4a, 0x11, 0x4d, 0x04, 0x26, 0xc2, 0x17, 0xf7, 0x29, 0x56, 0x0b, 0xca, 0xff, 0xff, 0x6a,
+	0xff, 0xb9, 0x26, 0xe5, 0x64, 0xde, 0x76, 0xb4, 0x08, 0xec, 0x0f, 0x50, 0x7a, 0x58, 0x9f, 0x1c,
+	0xc0, 0x5e, 0xa3, 0x77, 0x71, 0xfe, 0xd9, 0xbf, 0xea, 0x0f, 0xbb, 0x3d, 0xdf, 0x6b, 0x9d, 0x35,
+	0xbf, 0x55, 0x72, 0x52, 0x6e, 0x9f, 0x75, 0x7b, 0x7e, 0xb7, 0xed, 0xf7, 0x2f, 0x86, 0x46, 0xb6,
+	0xec, 0x4b, 0xd8, 0x7d, 0x54, 0x9d, 0x6c, 0xc1, 0xba, 0x2a, 0x50, 0xc9, 0x11, 0x02, 0xe5, 0x76,
+	0xb7, 0x37, 0x6c, 0x79, 0xad, 0xa6, 0xaf, 0x35, 0x8b, 0x1c, 0x41, 0x6d, 0x59, 0xf3, 0xbf, 0x76,
+	0x87, 0x1d, 0xbf, 0xf5, 0xa5, 0xd5, 0x1f, 0x0e, 0x2a, 0x79, 0xfb, 0x97, 0x05, 0xbb, 0x4d, 0x9c,
+	0xc4, 0x33, 0x64, 0xf3, 0x6b, 0x7a, 0xf2, 0xf4, 0x35, 0x95, 0x87, 0xae, 0xf3, 0xe4, 0x18, 0xd6,
+	0xc3, 0x09, 0x8d, 0x6e, 0xcd, 0xde, 0xef, 0x64, 0x60, 0x43, 0x8a, 0x9d, 0x9c, 0xa7, 0xb3, 0xe4,
+	0x23, 0x94, 0xc7, 0xf1, 0x44, 0x20, 0xc3, 0x91, 0xaf, 0xf9, 0x35, 0x73, 0x56, 0xea, 0xb1, 0x70,
+	0xa7, 0x6d, 0xb2, 0x99, 0x6f, 0x67, 0xfc, 0x50, 0xc8, 0xee, 0x48, 0xfd, 0xa7, 0x05, 0xbb, 0x67,
+	0x82, 0x4e, 0xe3, 0x68, 0xfe, 0xb6, 0xc8, 0x27, 0xd8, 0x5a, 0x04, 0x95, 0xac, 0x81, 0x56, 0x32,
+	0xc3, 0x09, 0x4d, 0xb1, 0x56, 0x9b, 0x9f, 0xcf, 0x3f, 0xcf, 0xd1, 0xce, 0x9d, 0x58, 0x6f, 0x2c,
+	0x72, 0x0a, 0x1b, 0x66, 0x03, 0x56, 0xd8, 0xab, 0x73, 0xfb, 0xa3, 0x4d, 0xd2, 0xe6, 0xc6, 0x15,
+	0x1c, 0x53, 0x76, 0xed, 0xdc, 0xdc, 0xa7, 0xc8, 0x26, 0x38, 0xba, 0x46, 0xe6, 0x8c, 0x83, 0x90,
+	0xc5, 0x51, 0x36, 0x99, 0xb1, 0x7f, 0x7f, 0x75, 0x1d, 0x8b, 0x9b, 0xbb, 0x50, 0x2e, 0xe0, 0x3e,
+	0xa0, 0x5d, 0x4d, 0xbb, 0x9a, 0x76, 0x0d, 0x1d, 0x16, 0x55, 0xfc, 0xf6, 0xef, 0x00, 0x31, 0x8d,
+	0xe4, 0xb4, 0x89, 0x04, 0x00, 0x00,
 }
@@ -0,0 +1,279 @@
+// Code generated by protoc-gen-go.
+// source: orderer/admin.proto
+// DO NOT EDIT!
+
+package orderer
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import common "github.com/hyperledger/fabric/protos/common"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// StatusRequest is carried as the Payload data of the Envelope sent to the
+// Status method of the Admin service
+type StatusRequest struct {
+	ChannelId string `protobuf:"bytes,1,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+}
+
+func (m *StatusRequest) Reset()                    { *m = StatusRequest{} }
+func (m *StatusRequest) String() string            { return proto.CompactTextString(m) }
+func (*StatusRequest) ProtoMessage()               {}
+func (*StatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{0} }
+
+// ChannelStatus reports the state of a channel serviced by the orderer
+type ChannelStatus struct {
+	ChannelId                string `protobuf:"bytes,1,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+	SystemChannel            bool   `protobuf:"varint,2,opt,name=system_channel,json=systemChannel" json:"system_channel,omitempty"`
+	Height                   uint64 `protobuf:"varint,3,opt,name=height" json:"height,omitempty"`
+	ConsensusType            string `protobuf:"bytes,4,opt,name=consensus_type,json=consensusType" json:"consensus_type,omitempty"`
+	KafkaLastOffsetPersisted int64  `protobuf:"varint,5,opt,name=kafka_last_offset_persisted,json=kafkaLastOffsetPersisted" json:"kafka_last_offset_persisted,omitempty"`
+	PendingBatchSize         uint32 `protobuf:"varint,6,opt,name=pending_batch_size,json=pendingBatchSize" json:"pending_batch_size,omitempty"`
+}
+
+func (m *ChannelStatus) Reset()                    { *m = ChannelStatus{} }
+func (m *ChannelStatus) String() string            { return proto.CompactTextString(m) }
+func (*ChannelStatus) ProtoMessage()               {}
+func (*ChannelStatus) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{1} }
+
+type StatusResponse struct {
+	Status           common.Status    `protobuf:"varint,1,opt,name=status,enum=common.Status" json:"status,omitempty"`
+	Channels         []*ChannelStatus `protobuf:"bytes,2,rep,name=channels" json:"channels,omitempty"`
+	ArchivedChannels []string         `protobuf:"bytes,3,rep,name=archived_channels,json=archivedChannels" json:"archived_channels,omitempty"`
+}
+
+func (m *StatusResponse) Reset()                    { *m = StatusResponse{} }
+func (m *StatusResponse) String() string            { return proto.CompactTextString(m) }
+func (*StatusResponse) ProtoMessage()               {}
+func (*StatusResponse) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{2} }
+
+func (m *StatusResponse) GetChannels() []*ChannelStatus {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+// ChannelRequest is carried as the Payload data of the Envelope sent to the
+// RemoveChannel and RestoreChannel methods of the Admin service
+type ChannelRequest struct {
+	ChannelId string `protobuf:"bytes,1,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+}
+
+func (m *ChannelRequest) Reset()                    { *m = ChannelRequest{} }
+func (m *ChannelRequest) String() string            { return proto.CompactTextString(m) }
+func (*ChannelRequest) ProtoMessage()               {}
+func (*ChannelRequest) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{3} }
+
+type ChannelResponse struct {
+	Status common.Status `protobuf:"varint,1,opt,name=status,enum=common.Status" json:"status,omitempty"`
+	Info   string        `protobuf:"bytes,2,opt,name=info" json:"info,omitempty"`
+}
+
+func (m *ChannelResponse) Reset()                    { *m = ChannelResponse{} }
+func (m *ChannelResponse) String() string            { return proto.CompactTextString(m) }
+func (*ChannelResponse) ProtoMessage()               {}
+func (*ChannelResponse) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{4} }
+
+func init() {
+	proto.RegisterType((*StatusRequest)(nil), "orderer.StatusRequest")
+	proto.RegisterType((*ChannelStatus)(nil), "orderer.ChannelStatus")
+	proto.RegisterType((*StatusResponse)(nil), "orderer.StatusResponse")
+	proto.RegisterType((*ChannelRequest)(nil), "orderer.ChannelRequest")
+	proto.RegisterType((*ChannelResponse)(nil), "orderer.ChannelResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion3
+
+// Client API for Admin service
+
+type AdminClient interface {
+	// Status requires an Envelope of type MESSAGE signed by an identity satisfying the admin policy of
+	// the orderer's local MSP, with Payload data as a marshaled StatusRequest
+	Status(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (*StatusResponse, error)
+	// RemoveChannel requires an Envelope of type MESSAGE signed by an identity satisfying the Admins
+	// principal of the orderer's local MSP, with Payload data as a marshaled ChannelRequest. The channel
+	// is halted, no longer serviced and its ledger is moved to the archive
+	RemoveChannel(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (*ChannelResponse, error)
+	// RestoreChannel requires an Envelope as for RemoveChannel, and services again the archived channel
+	RestoreChannel(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (*ChannelResponse, error)
+}
+
+type adminClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAdminClient(cc *grpc.ClientConn) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) Status(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (*StatusResponse, error) {
+	out := new(StatusResponse)
+	err := grpc.Invoke(ctx, "/orderer.Admin/Status", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) RemoveChannel(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (*ChannelResponse, error) {
+	out := new(ChannelResponse)
+	err := grpc.Invoke(ctx, "/orderer.Admin/RemoveChannel", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) RestoreChannel(ctx context.Context, in *common.Envelope, opts ...grpc.CallOption) (*ChannelResponse, error) {
+	out := new(ChannelResponse)
+	err := grpc.Invoke(ctx, "/orderer.Admin/RestoreChannel", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Admin service
+
+type AdminServer interface {
+	// Status requires an Envelope of type MESSAGE signed by an identity satisfying the admin policy of
+	// the orderer's local MSP, with Payload data as a marshaled StatusRequest
+	Status(context.Context, *common.Envelope) (*StatusResponse, error)
+	// RemoveChannel requires an Envelope of type MESSAGE signed by an identity satisfying the Admins
+	// principal of the orderer's local MSP, with Payload data as a marshaled ChannelRequest. The channel
+	// is halted, no longer serviced and its ledger is moved to the archive
+	RemoveChannel(context.Context, *common.Envelope) (*ChannelResponse, error)
+	// RestoreChannel requires an Envelope as for RemoveChannel, and services again the archived channel
+	RestoreChannel(context.Context, *common.Envelope) (*ChannelResponse, error)
+}
+
+func RegisterAdminServer(s *grpc.Server, srv AdminServer) {
+	s.RegisterService(&_Admin_serviceDesc, srv)
+}
+
+func _Admin_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(common.Envelope)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).Status(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/orderer.Admin/Status",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).Status(ctx, req.(*common.Envelope))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_RemoveChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(common.Envelope)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).RemoveChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/orderer.Admin/RemoveChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).RemoveChannel(ctx, req.(*common.Envelope))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_RestoreChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(common.Envelope)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).RestoreChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/orderer.Admin/RestoreChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).RestoreChannel(ctx, req.(*common.Envelope))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Admin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "orderer.Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Status",
+			Handler:    _Admin_Status_Handler,
+		},
+		{
+			MethodName: "RemoveChannel",
+			Handler:    _Admin_RemoveChannel_Handler,
+		},
+		{
+			MethodName: "RestoreChannel",
+			Handler:    _Admin_RestoreChannel_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: fileDescriptor1,
+}
+
+func init() { proto.RegisterFile("orderer/admin.proto", fileDescriptor1) }
+
+var fileDescriptor1 = []byte{
+	// 468 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x9c, 0x93, 0x41, 0x8b, 0xd4, 0x30,
+	0x1c, 0xc5, 0xed, 0xcc, 0xec, 0xb8, 0xf3, 0x97, 0xd6, 0x31, 0x0b, 0x6b, 0x59, 0x11, 0x4a, 0x61,
+	0xa5, 0xe0, 0xd2, 0xc2, 0x88, 0xc7, 0x15, 0x5c, 0xf1, 0x20, 0x28, 0x4a, 0x56, 0x2f, 0x5e, 0x4a,
+	0xa6, 0xfd, 0xb7, 0x0d, 0x3b, 0x4d, 0x6a, 0x92, 0x19, 0x98, 0xbd, 0xf9, 0x31, 0xfc, 0x30, 0x7e,
+	0x37, 0x69, 0x9b, 0x96, 0x5d, 0x3d, 0xa8, 0x7b, 0x6a, 0xf2, 0xde, 0xfb, 0xa5, 0xcd, 0x4b, 0x0a,
+	0x47, 0x52, 0xe5, 0xa8, 0x50, 0x25, 0x2c, 0xaf, 0xb9, 0x88, 0x1b, 0x25, 0x8d, 0x24, 0xf7, 0xad,
+	0x78, 0x72, 0x94, 0xc9, 0xba, 0x96, 0x22, 0xe9, 0x1f, 0xbd, 0x1b, 0xc6, 0xe0, 0x5e, 0x1a, 0x66,
+	0xb6, 0x9a, 0xe2, 0xb7, 0x2d, 0x6a, 0x43, 0x9e, 0x02, 0x64, 0x15, 0x13, 0x02, 0x37, 0x29, 0xcf,
+	0x7d, 0x27, 0x70, 0xa2, 0x05, 0x5d, 0x58, 0xe5, 0x5d, 0x1e, 0x7e, 0x9f, 0x80, 0xfb, 0xa6, 0x9f,
+	0xf5, 0xdc, 0x5f, 0x00, 0x72, 0x0a, 0x9e, 0xde, 0x6b, 0x83, 0x75, 0x6a, 0x35, 0x7f, 0x12, 0x38,
+	0xd1, 0x21, 0x75, 0x7b, 0xd5, 0xae, 0x45, 0x8e, 0x61, 0x5e, 0x21, 0x2f, 0x2b, 0xe3, 0x4f, 0x03,
+	0x27, 0x9a, 0x51, 0x3b, 0x6b, 0xf1, 0x4c, 0x0a, 0x8d, 0x42, 0x6f, 0x75, 0x6a, 0xf6, 0x0d, 0xfa,
+	0xb3, 0xee, 0x0d, 0xee, 0xa8, 0x7e, 0xde, 0x37, 0x48, 0xce, 0xe1, 0xc9, 0x15, 0x2b, 0xae, 0x58,
+	0xba, 0x61, 0xda, 0xa4, 0xb2, 0x28, 0x34, 0x9a, 0xb4, 0x41, 0xa5, 0xb9, 0x36, 0x98, 0xfb, 0x07,
+	0x81, 0x13, 0x4d, 0xa9, 0xdf, 0x45, 0xde, 0x33, 0x6d, 0x3e, 0x76, 0x81, 0x4f, 0x83, 0x4f, 0xce,
+	0x80, 0x34, 0x28, 0x72, 0x2e, 0xca, 0x74, 0xcd, 0x4c, 0x56, 0xa5, 0x9a, 0x5f, 0xa3, 0x3f, 0x0f,
+	0x9c, 0xc8, 0xa5, 0x4b, 0xeb, 0x5c, 0xb4, 0xc6, 0x25, 0xbf, 0xc6, 0xf0, 0x87, 0x03, 0xde, 0x50,
+	0x9a, 0x6e, 0xda, 0xef, 0x20, 0xcf, 0x60, 0xae, 0x3b, 0xa5, 0x2b, 0xc0, 0x5b, 0x79, 0xb1, 0x6d,
+	0xd9, 0xe6, 0xac, 0x4b, 0x56, 0x70, 0x68, 0x6b, 0xd0, 0xfe, 0x24, 0x98, 0x46, 0x0f, 0x56, 0xc7,
+	0xb1, 0x3d, 0x9f, 0xf8, 0x56, 0xad, 0x74, 0xcc, 0x91, 0xe7, 0xf0, 0x88, 0xa9, 0xac, 0xe2, 0x3b,
+	0xcc, 0xd3, 0x11, 0x9e, 0x06, 0xd3, 0x68, 0x41, 0x97, 0x83, 0x61, 0x59, 0x1d, 0x26, 0xe0, 0xd9,
+	0xf1, 0x3f, 0x1e, 0xe8, 0x07, 0x78, 0x38, 0x02, 0xff, 0xb9, 0x19, 0x02, 0x33, 0x2e, 0x0a, 0xd9,
+	0x1d, 0xe8, 0x82, 0x76, 0xe3, 0xd5, 0x4f, 0x07, 0x0e, 0x5e, 0xb7, 0xb7, 0x8f, 0xbc, 0x84, 0xb9,
+	0xbd, 0x21, 0xcb, 0x81, 0x7f, 0x2b, 0x76, 0xb8, 0x91, 0x0d, 0x9e, 0x3c, 0x1e, 0x37, 0x7d, 0xbb,
+	0xc7, 0xf0, 0x1e, 0x39, 0x07, 0x97, 0x62, 0x2d, 0x77, 0x38, 0xdc, 0x8c, 0x3f, 0x69, 0xff, 0xf7,
+	0xca, 0x6e, 0xe0, 0xaf, 0xc0, 0xa3, 0xa8, 0x8d, 0x54, 0x77, 0xe3, 0x2f, 0xbe, 0xc0, 0xa9, 0x54,
+	0x65, 0x5c, 0xed, 0x1b, 0x54, 0x1b, 0xcc, 0x4b, 0x54, 0x71, 0xc1, 0xd6, 0x8a, 0x67, 0xfd, 0xff,
+	0xa2, 0x07, 0xf4, 0xeb, 0x59, 0xc9, 0x4d, 0xb5, 0x5d, 0xb7, 0x8b, 0x27, 0x37, 0xd2, 0x49, 0x9f,
+	0x4e, 0xfa, 0x74, 0x62, 0xd3, 0xeb, 0x79, 0x37, 0x7f, 0xf1, 0x6b, 0x00, 0x85, 0x12, 0x86, 0x37,
+	0xa2, 0x03, 0x00, 0x00,
+}
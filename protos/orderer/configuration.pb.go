@@ -20,7 +20,7 @@ type ConsensusType struct {
 func (m *ConsensusType) Reset()                    { *m = ConsensusType{} }
 func (m *ConsensusType) String() string            { return proto.CompactTextString(m) }
 func (*ConsensusType) ProtoMessage()               {}
-func (*ConsensusType) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{0} }
+func (*ConsensusType) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{0} }
 
 type BatchSize struct {
 	// Simply specified as number of messages for now, in the future
@@ -37,7 +37,7 @@ type BatchSize struct {
 func (m *BatchSize) Reset()                    { *m = BatchSize{} }
 func (m *BatchSize) String() string            { return proto.CompactTextString(m) }
 func (*BatchSize) ProtoMessage()               {}
-func (*BatchSize) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{1} }
+func (*BatchSize) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{1} }
 
 type BatchTimeout struct {
 	// Any duration string parseable by ParseDuration():
@@ -48,7 +48,7 @@ type BatchTimeout struct {
 func (m *BatchTimeout) Reset()                    { *m = BatchTimeout{} }
 func (m *BatchTimeout) String() string            { return proto.CompactTextString(m) }
 func (*BatchTimeout) ProtoMessage()               {}
-func (*BatchTimeout) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{2} }
+func (*BatchTimeout) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{2} }
 
 // Carries a list of bootstrap brokers, i.e. this is not the exclusive set of
 // brokers an ordering service
@@ -61,7 +61,19 @@ type KafkaBrokers struct {
 func (m *KafkaBrokers) Reset()                    { *m = KafkaBrokers{} }
 func (m *KafkaBrokers) String() string            { return proto.CompactTextString(m) }
 func (*KafkaBrokers) ProtoMessage()               {}
-func (*KafkaBrokers) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{3} }
+func (*KafkaBrokers) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{3} }
+
+// Carries the orderer nodes replicating the blocks of the channels with Raft
+type RaftConsenters struct {
+	// Each consenter is identified by the (IP|host):port address on which it
+	// listens for the other consenters, e.g. orderer0.example.com:7090
+	Consenters []string `protobuf:"bytes,1,rep,name=consenters" json:"consenters,omitempty"`
+}
+
+func (m *RaftConsenters) Reset()                    { *m = RaftConsenters{} }
+func (m *RaftConsenters) String() string            { return proto.CompactTextString(m) }
+func (*RaftConsenters) ProtoMessage()               {}
+func (*RaftConsenters) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{4} }
 
 // ChannelRestrictions is the mssage which conveys restrictions on channel creation for an orderer
 type ChannelRestrictions struct {
@@ -71,37 +83,39 @@ type ChannelRestrictions struct {
 func (m *ChannelRestrictions) Reset()                    { *m = ChannelRestrictions{} }
 func (m *ChannelRestrictions) String() string            { return proto.CompactTextString(m) }
 func (*ChannelRestrictions) ProtoMessage()               {}
-func (*ChannelRestrictions) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{4} }
+func (*ChannelRestrictions) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{5} }
 
 func init() {
 	proto.RegisterType((*ConsensusType)(nil), "orderer.ConsensusType")
 	proto.RegisterType((*BatchSize)(nil), "orderer.BatchSize")
 	proto.RegisterType((*BatchTimeout)(nil), "orderer.BatchTimeout")
 	proto.RegisterType((*KafkaBrokers)(nil), "orderer.KafkaBrokers")
+	proto.RegisterType((*RaftConsenters)(nil), "orderer.RaftConsenters")
 	proto.RegisterType((*ChannelRestrictions)(nil), "orderer.ChannelRestrictions")
 }
 
-func init() { proto.RegisterFile("orderer/configuration.proto", fileDescriptor1) }
-
-var fileDescriptor1 = []byte{
-	// 299 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x64, 0xd0, 0xcd, 0x4a, 0xc3, 0x40,
-	0x10, 0x07, 0x70, 0x62, 0x8b, 0xb5, 0x8b, 0x45, 0x5d, 0x2f, 0x81, 0x5e, 0x4a, 0x44, 0x28, 0x52,
-	0x12, 0xd0, 0x37, 0x48, 0x8f, 0xd2, 0x4b, 0xac, 0x17, 0x2f, 0xb2, 0x49, 0x27, 0xc9, 0xd2, 0x64,
-	0x27, 0xcc, 0xee, 0x42, 0xe2, 0x4b, 0xf8, 0xca, 0x92, 0x4d, 0x2c, 0x62, 0x6f, 0xf3, 0xf1, 0x3b,
-	0xcc, 0xfc, 0xd9, 0x12, 0xe9, 0x00, 0x04, 0x14, 0x65, 0xa8, 0x72, 0x59, 0x58, 0x12, 0x46, 0xa2,
-	0x0a, 0x1b, 0x42, 0x83, 0x7c, 0x36, 0x2e, 0x83, 0x07, 0xb6, 0xd8, 0xa2, 0xd2, 0xa0, 0xb4, 0xd5,
-	0xfb, 0xae, 0x01, 0xce, 0xd9, 0xd4, 0x74, 0x0d, 0xf8, 0xde, 0xca, 0x5b, 0xcf, 0x13, 0x57, 0x07,
-	0xdf, 0x1e, 0x9b, 0xc7, 0xc2, 0x64, 0xe5, 0x9b, 0xfc, 0x02, 0xbe, 0x66, 0x37, 0xb5, 0x68, 0x77,
-	0xa0, 0xb5, 0x28, 0x60, 0x8b, 0x56, 0x19, 0x87, 0x17, 0xc9, 0xff, 0x31, 0x7f, 0x62, 0xb7, 0x22,
-	0xd5, 0x58, 0x59, 0x03, 0x3b, 0xd1, 0xc6, 0x9d, 0x01, 0xed, 0x5f, 0x38, 0x7a, 0x36, 0xe7, 0x1b,
-	0x76, 0xd7, 0x10, 0xe4, 0x40, 0x04, 0x87, 0x13, 0x9e, 0x38, 0x7c, 0xbe, 0x08, 0xd6, 0xec, 0xda,
-	0x1d, 0xb4, 0x97, 0x35, 0xa0, 0x35, 0xdc, 0x67, 0x33, 0x33, 0x94, 0xe3, 0xe1, 0xbf, 0x6d, 0x2f,
-	0x5f, 0x45, 0x7e, 0x14, 0x31, 0xe1, 0x11, 0x48, 0xf7, 0x32, 0x1d, 0x4a, 0xdf, 0x5b, 0x4d, 0x7a,
-	0x39, 0xb6, 0xc1, 0x33, 0xbb, 0xdf, 0x96, 0x42, 0x29, 0xa8, 0x12, 0xd0, 0x86, 0x64, 0xd6, 0xe7,
-	0xa5, 0xf9, 0x92, 0xcd, 0x6b, 0xd1, 0x7e, 0x66, 0xa7, 0x47, 0xa7, 0xc9, 0x55, 0x2d, 0x5a, 0xf7,
-	0x61, 0xfc, 0xce, 0x1e, 0x91, 0x8a, 0xb0, 0xec, 0x1a, 0xa0, 0x0a, 0x0e, 0x05, 0x50, 0x98, 0x8b,
-	0x94, 0x64, 0x36, 0xe4, 0xac, 0xc3, 0x31, 0xe7, 0x8f, 0x4d, 0x21, 0x4d, 0x69, 0xd3, 0x30, 0xc3,
-	0x3a, 0xfa, 0xa3, 0xa3, 0x41, 0x47, 0x83, 0x8e, 0x46, 0x9d, 0x5e, 0xba, 0xfe, 0xe5, 0x27, 0x00,
-	0x00, 0xff, 0xff, 0x13, 0xe7, 0x5d, 0xdb, 0xc4, 0x01, 0x00, 0x00,
+func init() { proto.RegisterFile("orderer/configuration.proto", fileDescriptor2) }
+
+var fileDescriptor2 = []byte{
+	// 318 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x64, 0xd1, 0xcf, 0x6a, 0xe3, 0x30,
+	0x10, 0x06, 0x70, 0xbc, 0x09, 0x9b, 0xcd, 0xb0, 0xe9, 0x1f, 0xf5, 0x62, 0x08, 0x94, 0xe0, 0x52,
+	0x08, 0x25, 0xd8, 0xa5, 0x7d, 0x03, 0xe7, 0x58, 0x72, 0x71, 0xd3, 0x4b, 0x2f, 0x45, 0x56, 0xc6,
+	0xb6, 0x48, 0x2c, 0x99, 0x91, 0x0c, 0x76, 0x5f, 0xa2, 0xaf, 0x5c, 0x2c, 0xbb, 0x21, 0x34, 0xb7,
+	0xf9, 0x46, 0xbf, 0x83, 0xf8, 0x06, 0xe6, 0x9a, 0x76, 0x48, 0x48, 0x91, 0xd0, 0x2a, 0x93, 0x79,
+	0x4d, 0xdc, 0x4a, 0xad, 0xc2, 0x8a, 0xb4, 0xd5, 0x6c, 0x32, 0x3c, 0x06, 0x77, 0x30, 0x5b, 0x6b,
+	0x65, 0x50, 0x99, 0xda, 0x6c, 0xdb, 0x0a, 0x19, 0x83, 0xb1, 0x6d, 0x2b, 0xf4, 0xbd, 0x85, 0xb7,
+	0x9c, 0x26, 0x6e, 0x0e, 0xbe, 0x3c, 0x98, 0xc6, 0xdc, 0x8a, 0xe2, 0x55, 0x7e, 0x22, 0x5b, 0xc2,
+	0x65, 0xc9, 0x9b, 0x0d, 0x1a, 0xc3, 0x73, 0x5c, 0xeb, 0x5a, 0x59, 0x87, 0x67, 0xc9, 0xef, 0x35,
+	0x7b, 0x80, 0x2b, 0x9e, 0x1a, 0x7d, 0xa8, 0x2d, 0x6e, 0x78, 0x13, 0xb7, 0x16, 0x8d, 0xff, 0xc7,
+	0xd1, 0xb3, 0x3d, 0x5b, 0xc1, 0x75, 0x45, 0x98, 0x21, 0x11, 0xee, 0x8e, 0x78, 0xe4, 0xf0, 0xf9,
+	0x43, 0xb0, 0x84, 0xff, 0xee, 0x43, 0x5b, 0x59, 0xa2, 0xae, 0x2d, 0xf3, 0x61, 0x62, 0xfb, 0x71,
+	0xf8, 0xf8, 0x4f, 0xec, 0xe4, 0x0b, 0xcf, 0xf6, 0x3c, 0x26, 0xbd, 0x47, 0x32, 0x9d, 0x4c, 0xfb,
+	0xd1, 0xf7, 0x16, 0xa3, 0x4e, 0x0e, 0x31, 0x78, 0x84, 0x8b, 0x84, 0x67, 0xb6, 0xaf, 0xc3, 0x76,
+	0xf6, 0x16, 0x40, 0x1c, 0xd3, 0xc0, 0x4f, 0x36, 0xc1, 0x13, 0xdc, 0xac, 0x0b, 0xae, 0x14, 0x1e,
+	0x12, 0x34, 0x96, 0xa4, 0xe8, 0x1a, 0x36, 0x6c, 0x0e, 0xd3, 0x92, 0x37, 0x1f, 0xe2, 0x58, 0xcd,
+	0x38, 0xf9, 0x57, 0xf2, 0xc6, 0x75, 0x12, 0xbf, 0xc1, 0xbd, 0xa6, 0x3c, 0x2c, 0xda, 0x0a, 0xe9,
+	0x80, 0xbb, 0x1c, 0x29, 0xcc, 0x78, 0x4a, 0x52, 0xf4, 0x97, 0x31, 0xe1, 0x70, 0x99, 0xf7, 0x55,
+	0x2e, 0x6d, 0x51, 0xa7, 0xa1, 0xd0, 0x65, 0x74, 0xa2, 0xa3, 0x5e, 0x47, 0xbd, 0x8e, 0x06, 0x9d,
+	0xfe, 0x75, 0xf9, 0xf9, 0x7b, 0x00, 0x96, 0x38, 0xcc, 0x12, 0xf6, 0x01, 0x00, 0x00,
 }
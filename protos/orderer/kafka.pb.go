@@ -26,7 +26,7 @@ type KafkaMessage struct {
 func (m *KafkaMessage) Reset()                    { *m = KafkaMessage{} }
 func (m *KafkaMessage) String() string            { return proto.CompactTextString(m) }
 func (*KafkaMessage) ProtoMessage()               {}
-func (*KafkaMessage) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{0} }
+func (*KafkaMessage) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{0} }
 
 type isKafkaMessage_Type interface {
 	isKafkaMessage_Type()
@@ -175,7 +175,7 @@ type KafkaMessageRegular struct {
 func (m *KafkaMessageRegular) Reset()                    { *m = KafkaMessageRegular{} }
 func (m *KafkaMessageRegular) String() string            { return proto.CompactTextString(m) }
 func (*KafkaMessageRegular) ProtoMessage()               {}
-func (*KafkaMessageRegular) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{1} }
+func (*KafkaMessageRegular) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{1} }
 
 // KafkaMessageTimeToCut is used to signal to the orderers
 // that it is time to cut block <block_number>.
@@ -186,7 +186,7 @@ type KafkaMessageTimeToCut struct {
 func (m *KafkaMessageTimeToCut) Reset()                    { *m = KafkaMessageTimeToCut{} }
 func (m *KafkaMessageTimeToCut) String() string            { return proto.CompactTextString(m) }
 func (*KafkaMessageTimeToCut) ProtoMessage()               {}
-func (*KafkaMessageTimeToCut) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{2} }
+func (*KafkaMessageTimeToCut) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{2} }
 
 // KafkaMessageConnect is posted by an orderer upon booting up.
 // It is used to prevent the panic that would be caused if we
@@ -199,7 +199,7 @@ type KafkaMessageConnect struct {
 func (m *KafkaMessageConnect) Reset()                    { *m = KafkaMessageConnect{} }
 func (m *KafkaMessageConnect) String() string            { return proto.CompactTextString(m) }
 func (*KafkaMessageConnect) ProtoMessage()               {}
-func (*KafkaMessageConnect) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{3} }
+func (*KafkaMessageConnect) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{3} }
 
 // LastOffsetPersisted is the encoded value for the Metadata message
 // which is encoded in the ORDERER block metadata index for the case
@@ -211,7 +211,7 @@ type KafkaMetadata struct {
 func (m *KafkaMetadata) Reset()                    { *m = KafkaMetadata{} }
 func (m *KafkaMetadata) String() string            { return proto.CompactTextString(m) }
 func (*KafkaMetadata) ProtoMessage()               {}
-func (*KafkaMetadata) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{4} }
+func (*KafkaMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{4} }
 
 func init() {
 	proto.RegisterType((*KafkaMessage)(nil), "orderer.KafkaMessage")
@@ -221,9 +221,9 @@ func init() {
 	proto.RegisterType((*KafkaMetadata)(nil), "orderer.KafkaMetadata")
 }
 
-func init() { proto.RegisterFile("orderer/kafka.proto", fileDescriptor2) }
+func init() { proto.RegisterFile("orderer/kafka.proto", fileDescriptor3) }
 
-var fileDescriptor2 = []byte{
+var fileDescriptor3 = []byte{
 	// 316 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x7c, 0x91, 0x4f, 0x6b, 0xc2, 0x40,
 	0x10, 0xc5, 0xb5, 0x8a, 0xd2, 0xd1, 0x5e, 0x22, 0x42, 0x0e, 0xa5, 0xb4, 0x42, 0xa1, 0x87, 0x92,
@@ -27,17 +27,34 @@ It has these top-level messages:
 	ChaincodeInput
 	ChaincodeSpec
 	ChaincodeDeploymentSpec
+	ChaincodeDependency
 	ChaincodeInvocationSpec
+	ChaincodeResourceLimits
+	ChaincodeDefinition
 	ChaincodeEvent
+	ChaincodeEvents
 	ChaincodeMessage
 	PutStateInfo
+	GetPrivateData
+	PutPrivateData
+	DelPrivateData
+	GetStateMetadata
+	PutStateMetadata
+	StateMetadata
+	StateMetadataResult
+	PutStateChunk
+	GetStateChunk
+	StateChunk
 	GetStateByRange
 	GetQueryResult
+	QueryMetadata
 	GetHistoryForKey
+	HistoryQueryOptions
 	QueryStateNext
 	QueryStateClose
 	QueryResultBytes
 	QueryResponse
+	QueryResponseMetadata
 	AnchorPeers
 	AnchorPeer
 	ChaincodeReg
@@ -47,6 +64,9 @@ It has these top-level messages:
 	Unregister
 	SignedEvent
 	Event
+	FilteredBlock
+	FilteredTransaction
+	DeliverResponse
 	PeerID
 	PeerEndpoint
 	SignedProposal
@@ -54,6 +74,8 @@ It has these top-level messages:
 	ChaincodeHeaderExtension
 	ChaincodeProposalPayload
 	ChaincodeAction
+	CrossChannelReadSets
+	CrossChannelReadSet
 	ProposalResponse
 	Response
 	ProposalResponsePayload
@@ -62,6 +84,7 @@ It has these top-level messages:
 	ChaincodeInfo
 	ChannelQueryResponse
 	ChannelInfo
+	CommitReadinessResponse
 	SignedChaincodeDeploymentSpec
 	SignedTransaction
 	ProcessedTransaction
@@ -69,6 +92,7 @@ It has these top-level messages:
 	TransactionAction
 	ChaincodeActionPayload
 	ChaincodeEndorsedAction
+	KeyEndorsementPolicies
 */
 package peer
 
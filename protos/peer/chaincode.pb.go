@@ -159,6 +159,9 @@ type ChaincodeDeploymentSpec struct {
 	EffectiveDate *google_protobuf1.Timestamp                  `protobuf:"bytes,2,opt,name=effective_date,json=effectiveDate" json:"effective_date,omitempty"`
 	CodePackage   []byte                                       `protobuf:"bytes,3,opt,name=code_package,json=codePackage,proto3" json:"code_package,omitempty"`
 	ExecEnv       ChaincodeDeploymentSpec_ExecutionEnvironment `protobuf:"varint,4,opt,name=exec_env,json=execEnv,enum=protos.ChaincodeDeploymentSpec_ExecutionEnvironment" json:"exec_env,omitempty"`
+	// The dependencies packaged with the code, as resolved when packaging it.
+	// Only set by the platforms pinning the versions of the dependencies.
+	Dependencies []*ChaincodeDependency `protobuf:"bytes,5,rep,name=dependencies" json:"dependencies,omitempty"`
 }
 
 func (m *ChaincodeDeploymentSpec) Reset()                    { *m = ChaincodeDeploymentSpec{} }
@@ -180,6 +183,31 @@ func (m *ChaincodeDeploymentSpec) GetEffectiveDate() *google_protobuf1.Timestamp
 	return nil
 }
 
+func (m *ChaincodeDeploymentSpec) GetDependencies() []*ChaincodeDependency {
+	if m != nil {
+		return m.Dependencies
+	}
+	return nil
+}
+
+// A dependency of a chaincode, packaged with its code.
+type ChaincodeDependency struct {
+	// import path of the dependency, as pinned by the chaincode
+	Path    string `protobuf:"bytes,1,opt,name=path" json:"path,omitempty"`
+	Version string `protobuf:"bytes,2,opt,name=version" json:"version,omitempty"`
+	// whether the dependency was packaged from the vendor directory of the
+	// chaincode rather than from the environment of the packager
+	Vendored bool `protobuf:"varint,3,opt,name=vendored" json:"vendored,omitempty"`
+	// hex encoded content hash of the files packaged for the dependency, as
+	// pinned by the chaincode and verified when packaging it
+	Hash string `protobuf:"bytes,4,opt,name=hash" json:"hash,omitempty"`
+}
+
+func (m *ChaincodeDependency) Reset()                    { *m = ChaincodeDependency{} }
+func (m *ChaincodeDependency) String() string            { return proto.CompactTextString(m) }
+func (*ChaincodeDependency) ProtoMessage()               {}
+func (*ChaincodeDependency) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{4} }
+
 // Carries the chaincode function and its arguments.
 type ChaincodeInvocationSpec struct {
 	ChaincodeSpec *ChaincodeSpec `protobuf:"bytes,1,opt,name=chaincode_spec,json=chaincodeSpec" json:"chaincode_spec,omitempty"`
@@ -196,7 +224,7 @@ type ChaincodeInvocationSpec struct {
 func (m *ChaincodeInvocationSpec) Reset()                    { *m = ChaincodeInvocationSpec{} }
 func (m *ChaincodeInvocationSpec) String() string            { return proto.CompactTextString(m) }
 func (*ChaincodeInvocationSpec) ProtoMessage()               {}
-func (*ChaincodeInvocationSpec) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{4} }
+func (*ChaincodeInvocationSpec) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{5} }
 
 func (m *ChaincodeInvocationSpec) GetChaincodeSpec() *ChaincodeSpec {
 	if m != nil {
@@ -205,12 +233,59 @@ func (m *ChaincodeInvocationSpec) GetChaincodeSpec() *ChaincodeSpec {
 	return nil
 }
 
+// Limits on the resources a chaincode may use on a peer, set when
+// instantiating or upgrading it. Zero values leave the resource unlimited,
+// unless the peer configuration limits it.
+type ChaincodeResourceLimits struct {
+	// relative weight of the chaincode container CPU usage
+	CpuShares int64 `protobuf:"varint,1,opt,name=cpu_shares,json=cpuShares" json:"cpu_shares,omitempty"`
+	// memory limit of the chaincode container, in bytes
+	Memory int64 `protobuf:"varint,2,opt,name=memory" json:"memory,omitempty"`
+	// maximum number of invocations the chaincode executes concurrently,
+	// the invocations beyond it are rejected
+	MaxConcurrency uint32 `protobuf:"varint,3,opt,name=max_concurrency,json=maxConcurrency" json:"max_concurrency,omitempty"`
+	// timeout of an invocation, in milliseconds
+	ExecuteTimeout uint64 `protobuf:"varint,4,opt,name=execute_timeout,json=executeTimeout" json:"execute_timeout,omitempty"`
+}
+
+func (m *ChaincodeResourceLimits) Reset()                    { *m = ChaincodeResourceLimits{} }
+func (m *ChaincodeResourceLimits) String() string            { return proto.CompactTextString(m) }
+func (*ChaincodeResourceLimits) ProtoMessage()               {}
+func (*ChaincodeResourceLimits) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{6} }
+
+// The definition of a chaincode agreed upon by the organizations of a
+// channel through the lifecycle system chaincode. Each organization approves
+// a definition, which takes effect once it is committed.
+type ChaincodeDefinition struct {
+	Name    string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Version string `protobuf:"bytes,2,opt,name=version" json:"version,omitempty"`
+	// incremented each time the definition of the chaincode is committed,
+	// starting at 1
+	Sequence int64 `protobuf:"varint,3,opt,name=sequence" json:"sequence,omitempty"`
+	// marshalled SignaturePolicyEnvelope of the transactions of the chaincode
+	EndorsementPolicy []byte `protobuf:"bytes,4,opt,name=endorsement_policy,json=endorsementPolicy,proto3" json:"endorsement_policy,omitempty"`
+	// hash of the installed chaincode package, as computed by the peers
+	PackageHash []byte `protobuf:"bytes,5,opt,name=package_hash,json=packageHash,proto3" json:"package_hash,omitempty"`
+	// name of the endorsement system chaincode of the chaincode
+	Escc string `protobuf:"bytes,6,opt,name=escc" json:"escc,omitempty"`
+	// name of the validation system chaincode of the chaincode
+	Vscc string `protobuf:"bytes,7,opt,name=vscc" json:"vscc,omitempty"`
+}
+
+func (m *ChaincodeDefinition) Reset()                    { *m = ChaincodeDefinition{} }
+func (m *ChaincodeDefinition) String() string            { return proto.CompactTextString(m) }
+func (*ChaincodeDefinition) ProtoMessage()               {}
+func (*ChaincodeDefinition) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{7} }
+
 func init() {
 	proto.RegisterType((*ChaincodeID)(nil), "protos.ChaincodeID")
 	proto.RegisterType((*ChaincodeInput)(nil), "protos.ChaincodeInput")
 	proto.RegisterType((*ChaincodeSpec)(nil), "protos.ChaincodeSpec")
 	proto.RegisterType((*ChaincodeDeploymentSpec)(nil), "protos.ChaincodeDeploymentSpec")
+	proto.RegisterType((*ChaincodeDependency)(nil), "protos.ChaincodeDependency")
 	proto.RegisterType((*ChaincodeInvocationSpec)(nil), "protos.ChaincodeInvocationSpec")
+	proto.RegisterType((*ChaincodeResourceLimits)(nil), "protos.ChaincodeResourceLimits")
+	proto.RegisterType((*ChaincodeDefinition)(nil), "protos.ChaincodeDefinition")
 	proto.RegisterEnum("protos.ConfidentialityLevel", ConfidentialityLevel_name, ConfidentialityLevel_value)
 	proto.RegisterEnum("protos.ChaincodeSpec_Type", ChaincodeSpec_Type_name, ChaincodeSpec_Type_value)
 	proto.RegisterEnum("protos.ChaincodeDeploymentSpec_ExecutionEnvironment", ChaincodeDeploymentSpec_ExecutionEnvironment_name, ChaincodeDeploymentSpec_ExecutionEnvironment_value)
@@ -219,42 +294,57 @@ func init() {
 func init() { proto.RegisterFile("peer/chaincode.proto", fileDescriptor1) }
 
 var fileDescriptor1 = []byte{
-	// 591 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xac, 0x93, 0x5d, 0x6f, 0xd3, 0x3e,
-	0x14, 0xc6, 0x97, 0xb5, 0x7b, 0x3b, 0x7d, 0xf9, 0xe7, 0x6f, 0x06, 0x54, 0xbb, 0x61, 0x44, 0x5c,
-	0x8c, 0x09, 0xa5, 0x52, 0x99, 0xb8, 0x42, 0x48, 0x59, 0x93, 0x4d, 0x81, 0xd2, 0x4c, 0x59, 0x87,
-	0x04, 0x37, 0x95, 0x9b, 0x9c, 0xa6, 0x16, 0xa9, 0x1d, 0x25, 0x6e, 0xb4, 0x5e, 0xf3, 0xbd, 0xf8,
-	0x6a, 0x20, 0x3b, 0x6b, 0xb7, 0xa9, 0xbb, 0xe4, 0x2a, 0xf6, 0x93, 0xe7, 0xd8, 0xcf, 0xf9, 0xe9,
-	0x18, 0x0e, 0x33, 0xc4, 0xbc, 0x1b, 0xcd, 0x28, 0xe3, 0x91, 0x88, 0xd1, 0xce, 0x72, 0x21, 0x05,
-	0xd9, 0xd5, 0x9f, 0xe2, 0xe8, 0x55, 0x22, 0x44, 0x92, 0x62, 0x57, 0x6f, 0x27, 0x8b, 0x69, 0x57,
-	0xb2, 0x39, 0x16, 0x92, 0xce, 0xb3, 0xca, 0x68, 0x05, 0xd0, 0xe8, 0xaf, 0x6a, 0x7d, 0x97, 0x10,
-	0xa8, 0x67, 0x54, 0xce, 0x3a, 0xc6, 0xb1, 0x71, 0x72, 0x10, 0xea, 0xb5, 0xd2, 0x38, 0x9d, 0x63,
-	0x67, 0xbb, 0xd2, 0xd4, 0x9a, 0x74, 0x60, 0xaf, 0xc4, 0xbc, 0x60, 0x82, 0x77, 0x6a, 0x5a, 0x5e,
-	0x6d, 0xad, 0x37, 0xd0, 0xbe, 0x3f, 0x90, 0x67, 0x0b, 0xa9, 0xea, 0x69, 0x9e, 0x14, 0x1d, 0xe3,
-	0xb8, 0x76, 0xd2, 0x0c, 0xf5, 0xda, 0xfa, 0x63, 0x40, 0x6b, 0x6d, 0xbb, 0xce, 0x30, 0x22, 0x36,
-	0xd4, 0xe5, 0x32, 0x43, 0x7d, 0x73, 0xbb, 0x77, 0x54, 0xc5, 0x2b, 0xec, 0x47, 0x26, 0x7b, 0xb4,
-	0xcc, 0x30, 0xd4, 0x3e, 0xf2, 0x01, 0x9a, 0xeb, 0xa6, 0xc7, 0x2c, 0xd6, 0xe9, 0x1a, 0xbd, 0x67,
-	0x1b, 0x75, 0xbe, 0x1b, 0x36, 0xd6, 0x46, 0x3f, 0x26, 0xef, 0x60, 0x87, 0xa9, 0x58, 0x3a, 0x77,
-	0xa3, 0xf7, 0x62, 0xb3, 0x40, 0xfd, 0x0d, 0x2b, 0x93, 0xea, 0x53, 0x11, 0x13, 0x0b, 0xd9, 0xa9,
-	0x1f, 0x1b, 0x27, 0x3b, 0xe1, 0x6a, 0x6b, 0x7d, 0x82, 0xba, 0x4a, 0x43, 0x5a, 0x70, 0x70, 0x33,
-	0x74, 0xbd, 0x0b, 0x7f, 0xe8, 0xb9, 0xe6, 0x16, 0x01, 0xd8, 0xbd, 0x0c, 0x06, 0xce, 0xf0, 0xd2,
-	0x34, 0xc8, 0x3e, 0xd4, 0x87, 0x81, 0xeb, 0x99, 0xdb, 0x64, 0x0f, 0x6a, 0x7d, 0x27, 0x34, 0x6b,
-	0x4a, 0xfa, 0xec, 0x7c, 0x73, 0xcc, 0xba, 0xf5, 0x7b, 0x1b, 0x5e, 0xae, 0xef, 0x74, 0x31, 0x4b,
-	0xc5, 0x72, 0x8e, 0x5c, 0x6a, 0x16, 0x1f, 0xa1, 0x7d, 0xdf, 0x5b, 0x91, 0x61, 0xa4, 0xa9, 0x34,
-	0x7a, 0xcf, 0x9f, 0xa4, 0x12, 0xb6, 0xa2, 0x47, 0x24, 0x1d, 0x68, 0xe3, 0x74, 0x8a, 0x91, 0x64,
-	0x25, 0x8e, 0x63, 0x2a, 0xf1, 0x8e, 0xcd, 0x91, 0x5d, 0x0d, 0x83, 0xbd, 0x1a, 0x06, 0x7b, 0xb4,
-	0x1a, 0x86, 0xb0, 0xb5, 0xae, 0x70, 0xa9, 0x44, 0xf2, 0x1a, 0x9a, 0xfa, 0xee, 0x8c, 0x46, 0x3f,
-	0x69, 0x82, 0x9a, 0x55, 0x33, 0x6c, 0x28, 0xed, 0xaa, 0x92, 0x48, 0x00, 0xfb, 0x78, 0x8b, 0xd1,
-	0x18, 0x79, 0xa9, 0xd1, 0xb4, 0x7b, 0x67, 0x1b, 0xe9, 0x1e, 0xb7, 0x65, 0x7b, 0xb7, 0x18, 0x2d,
-	0x24, 0x13, 0xdc, 0xe3, 0x25, 0xcb, 0x05, 0x57, 0x3f, 0xc2, 0x3d, 0x75, 0x8a, 0xc7, 0x4b, 0xcb,
-	0x86, 0xc3, 0xa7, 0x0c, 0x8a, 0xa8, 0x1b, 0xf4, 0xbf, 0x78, 0x61, 0x45, 0xf7, 0xfa, 0xfb, 0xf5,
-	0xc8, 0xfb, 0x6a, 0x1a, 0xd6, 0x2f, 0xe3, 0x01, 0x40, 0x9f, 0x97, 0x22, 0xa2, 0xaa, 0xf4, 0x1f,
-	0x00, 0x3c, 0x85, 0xff, 0x59, 0x3c, 0x4e, 0x90, 0x63, 0xae, 0x8f, 0x1c, 0xd3, 0x34, 0xb9, 0x9b,
-	0xfe, 0xff, 0x58, 0x7c, 0xb9, 0xd6, 0x9d, 0x34, 0x39, 0x3d, 0x83, 0xc3, 0xbe, 0xe0, 0x53, 0x16,
-	0x23, 0x97, 0x8c, 0xa6, 0x4c, 0x2e, 0x07, 0x58, 0x62, 0xaa, 0x92, 0x5e, 0xdd, 0x9c, 0x0f, 0xfc,
-	0xbe, 0xb9, 0x45, 0x4c, 0x68, 0xf6, 0x83, 0xe1, 0x85, 0xef, 0x7a, 0xc3, 0x91, 0xef, 0x0c, 0x4c,
-	0xe3, 0x3c, 0x00, 0x4b, 0xe4, 0x89, 0x3d, 0x5b, 0x66, 0x98, 0xa7, 0x18, 0x27, 0x98, 0xdb, 0x53,
-	0x3a, 0xc9, 0x59, 0xb4, 0xca, 0xa7, 0x1e, 0xf5, 0x8f, 0xb7, 0x09, 0x93, 0xb3, 0xc5, 0xc4, 0x8e,
-	0xc4, 0xbc, 0xfb, 0xc0, 0xda, 0xad, 0xac, 0xd5, 0x9b, 0x2e, 0xba, 0xca, 0x3a, 0xa9, 0xde, 0xfb,
-	0xfb, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x05, 0x18, 0x06, 0x44, 0x0e, 0x04, 0x00, 0x00,
+	// 828 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xac, 0x55, 0x5f, 0x8f, 0xe3, 0x34,
+	0x10, 0xbf, 0x6c, 0xbb, 0xff, 0xdc, 0x3f, 0x97, 0xf3, 0x2d, 0x47, 0xb5, 0x08, 0xb1, 0x44, 0x48,
+	0x2c, 0x27, 0x48, 0xa5, 0x72, 0xe2, 0x09, 0x81, 0x7a, 0x4d, 0x6e, 0x29, 0x94, 0x76, 0xe5, 0xed,
+	0x21, 0xc1, 0x4b, 0xe4, 0x75, 0xa6, 0xa9, 0x45, 0x63, 0x07, 0x3b, 0x89, 0xb6, 0xcf, 0x7c, 0x13,
+	0xbe, 0x15, 0x9f, 0xe6, 0x90, 0x9d, 0x36, 0xdb, 0x6a, 0xfb, 0xc8, 0x53, 0x67, 0x7e, 0x9e, 0x19,
+	0xff, 0xfc, 0x9b, 0x99, 0x14, 0x5d, 0x64, 0x00, 0xaa, 0xcf, 0x96, 0x94, 0x0b, 0x26, 0x63, 0xf0,
+	0x33, 0x25, 0x73, 0x89, 0x4f, 0xec, 0x8f, 0xbe, 0xfc, 0x2c, 0x91, 0x32, 0x59, 0x41, 0xdf, 0xba,
+	0xf7, 0xc5, 0xa2, 0x9f, 0xf3, 0x14, 0x74, 0x4e, 0xd3, 0xac, 0x0a, 0xf4, 0x66, 0xa8, 0x35, 0xda,
+	0xe6, 0x8e, 0x03, 0x8c, 0x51, 0x33, 0xa3, 0xf9, 0xb2, 0xe7, 0x5c, 0x39, 0xd7, 0xe7, 0xc4, 0xda,
+	0x06, 0x13, 0x34, 0x85, 0xde, 0x51, 0x85, 0x19, 0x1b, 0xf7, 0xd0, 0x69, 0x09, 0x4a, 0x73, 0x29,
+	0x7a, 0x0d, 0x0b, 0x6f, 0x5d, 0xef, 0x0b, 0xd4, 0x7d, 0x2c, 0x28, 0xb2, 0x22, 0x37, 0xf9, 0x54,
+	0x25, 0xba, 0xe7, 0x5c, 0x35, 0xae, 0xdb, 0xc4, 0xda, 0xde, 0x07, 0x07, 0x75, 0xea, 0xb0, 0xbb,
+	0x0c, 0x18, 0xf6, 0x51, 0x33, 0x5f, 0x67, 0x60, 0x6f, 0xee, 0x0e, 0x2e, 0x2b, 0x7a, 0xda, 0xdf,
+	0x0b, 0xf2, 0xe7, 0xeb, 0x0c, 0x88, 0x8d, 0xc3, 0xdf, 0xa1, 0x76, 0xfd, 0xe8, 0x88, 0xc7, 0x96,
+	0x5d, 0x6b, 0xf0, 0xf2, 0x49, 0xde, 0x38, 0x20, 0xad, 0x3a, 0x70, 0x1c, 0xe3, 0xaf, 0xd1, 0x31,
+	0x37, 0xb4, 0x2c, 0xef, 0xd6, 0xe0, 0xd5, 0xd3, 0x04, 0x73, 0x4a, 0xaa, 0x20, 0xf3, 0x4e, 0xa3,
+	0x98, 0x2c, 0xf2, 0x5e, 0xf3, 0xca, 0xb9, 0x3e, 0x26, 0x5b, 0xd7, 0xfb, 0x01, 0x35, 0x0d, 0x1b,
+	0xdc, 0x41, 0xe7, 0xef, 0xa7, 0x41, 0xf8, 0x6e, 0x3c, 0x0d, 0x03, 0xf7, 0x19, 0x46, 0xe8, 0xe4,
+	0x66, 0x36, 0x19, 0x4e, 0x6f, 0x5c, 0x07, 0x9f, 0xa1, 0xe6, 0x74, 0x16, 0x84, 0xee, 0x11, 0x3e,
+	0x45, 0x8d, 0xd1, 0x90, 0xb8, 0x0d, 0x03, 0xfd, 0x3c, 0xfc, 0x6d, 0xe8, 0x36, 0xbd, 0x0f, 0x47,
+	0xe8, 0xe3, 0xfa, 0xce, 0x00, 0xb2, 0x95, 0x5c, 0xa7, 0x20, 0x72, 0xab, 0xc5, 0xf7, 0xa8, 0xfb,
+	0xf8, 0x36, 0x9d, 0x01, 0xb3, 0xaa, 0xb4, 0x06, 0x1f, 0x1d, 0x54, 0x85, 0x74, 0xd8, 0xae, 0x8b,
+	0x87, 0xa8, 0x0b, 0x8b, 0x05, 0xb0, 0x9c, 0x97, 0x10, 0xc5, 0x34, 0x87, 0x8d, 0x36, 0x97, 0x7e,
+	0x35, 0x0c, 0xfe, 0x76, 0x18, 0xfc, 0xf9, 0x76, 0x18, 0x48, 0xa7, 0xce, 0x08, 0x68, 0x0e, 0xf8,
+	0x73, 0xd4, 0xb6, 0x77, 0x67, 0x94, 0xfd, 0x49, 0x13, 0xb0, 0x5a, 0xb5, 0x49, 0xcb, 0x60, 0xb7,
+	0x15, 0x84, 0x67, 0xe8, 0x0c, 0x1e, 0x80, 0x45, 0x20, 0x4a, 0x2b, 0x4d, 0x77, 0xf0, 0xe6, 0x09,
+	0xbb, 0xfd, 0x67, 0xf9, 0xe1, 0x03, 0xb0, 0x22, 0xe7, 0x52, 0x84, 0xa2, 0xe4, 0x4a, 0x0a, 0x73,
+	0x40, 0x4e, 0x4d, 0x95, 0x50, 0x94, 0xf8, 0x47, 0xd4, 0x8e, 0x21, 0x03, 0x11, 0x83, 0x60, 0x1c,
+	0x74, 0xef, 0xf8, 0xaa, 0x71, 0xdd, 0x1a, 0x7c, 0x72, 0xa8, 0x68, 0x15, 0xb4, 0x26, 0x7b, 0x09,
+	0x9e, 0x8f, 0x2e, 0x0e, 0xdd, 0x60, 0x5a, 0x12, 0xcc, 0x46, 0xbf, 0x84, 0xa4, 0x6a, 0xcf, 0xdd,
+	0xef, 0x77, 0xf3, 0xf0, 0x57, 0xd7, 0xf1, 0x34, 0x7a, 0x79, 0xa0, 0xe8, 0xc1, 0x15, 0xd8, 0x19,
+	0xf7, 0xa3, 0xbd, 0x71, 0xc7, 0x97, 0xe8, 0xac, 0x04, 0x11, 0x4b, 0x05, 0xb1, 0x55, 0xe9, 0x8c,
+	0xd4, 0xbe, 0xa9, 0xb4, 0xa4, 0x7a, 0x69, 0xe5, 0x39, 0x27, 0xd6, 0xf6, 0xfe, 0x76, 0x76, 0xda,
+	0x3e, 0x16, 0xa5, 0x64, 0xd4, 0xf0, 0xfd, 0x1f, 0xda, 0xfe, 0x1a, 0xbd, 0xe0, 0x71, 0x94, 0x80,
+	0x00, 0x65, 0x4b, 0x46, 0x74, 0x95, 0x6c, 0xd8, 0x3e, 0xe7, 0xf1, 0x4d, 0x8d, 0x0f, 0x57, 0x89,
+	0xf7, 0xcf, 0x2e, 0x0b, 0x02, 0x5a, 0x16, 0x8a, 0xc1, 0x84, 0xa7, 0x3c, 0xd7, 0xf8, 0x53, 0x84,
+	0x58, 0x56, 0x44, 0x7a, 0x49, 0x15, 0x68, 0xcb, 0xa0, 0x41, 0xce, 0x59, 0x56, 0xdc, 0x59, 0x00,
+	0xbf, 0x42, 0x27, 0x29, 0xa4, 0x52, 0xad, 0x6d, 0xed, 0x06, 0xd9, 0x78, 0xf8, 0x4b, 0xf4, 0x3c,
+	0xa5, 0x0f, 0x11, 0x93, 0x82, 0x15, 0x4a, 0x19, 0x25, 0xad, 0x1e, 0x1d, 0xd2, 0x4d, 0xe9, 0xc3,
+	0xe8, 0x11, 0x35, 0x81, 0x60, 0xdb, 0x04, 0xd1, 0xee, 0x6a, 0x35, 0x49, 0x77, 0x03, 0xcf, 0x37,
+	0x1b, 0xf6, 0xaf, 0xb3, 0xd7, 0xa0, 0x05, 0x17, 0xdc, 0xf0, 0xaf, 0xbf, 0x47, 0xce, 0xe1, 0xef,
+	0xd1, 0xd3, 0x06, 0x69, 0xf8, 0xab, 0x00, 0xc1, 0xaa, 0x31, 0x6e, 0x90, 0xda, 0xc7, 0xdf, 0x20,
+	0x6c, 0x7b, 0xa5, 0xc1, 0x0c, 0x4a, 0x94, 0xc9, 0x15, 0x67, 0x6b, 0xcb, 0xa6, 0x4d, 0x5e, 0xec,
+	0x9c, 0xdc, 0xda, 0x03, 0xb3, 0x15, 0x9b, 0x85, 0x88, 0x6c, 0x5f, 0x8f, 0xab, 0xad, 0xd8, 0x60,
+	0x3f, 0x51, 0x6d, 0xbf, 0x95, 0xa0, 0x19, 0xeb, 0x9d, 0x54, 0xdc, 0x8c, 0x6d, 0xb0, 0xd2, 0x60,
+	0xa7, 0x15, 0x66, 0xec, 0xd7, 0x6f, 0xd0, 0xc5, 0x48, 0x8a, 0x05, 0x8f, 0x41, 0xe4, 0x9c, 0xae,
+	0x78, 0xbe, 0x9e, 0x40, 0x09, 0x2b, 0x33, 0x9f, 0xb7, 0xef, 0xdf, 0x4e, 0xc6, 0x23, 0xf7, 0x19,
+	0x76, 0x51, 0x7b, 0x34, 0x9b, 0xbe, 0x1b, 0x07, 0xe1, 0x74, 0x3e, 0x1e, 0x4e, 0x5c, 0xe7, 0xed,
+	0x0c, 0x79, 0x52, 0x25, 0xfe, 0x72, 0x9d, 0x81, 0x5a, 0x41, 0x9c, 0x80, 0xf2, 0x17, 0xf4, 0x5e,
+	0x71, 0xb6, 0x1d, 0x90, 0x0c, 0x40, 0xfd, 0xf1, 0x55, 0xc2, 0xf3, 0x65, 0x71, 0xef, 0x33, 0x99,
+	0xf6, 0x77, 0x42, 0xfb, 0x55, 0x68, 0xf5, 0x57, 0xa0, 0xfb, 0x26, 0xf4, 0xbe, 0xfa, 0x9b, 0xf8,
+	0xf6, 0xbf, 0x01, 0x00, 0x84, 0xb6, 0xd3, 0x20, 0x45, 0x06, 0x00, 0x00,
 }
@@ -27,26 +27,50 @@ func (m *ChaincodeEvent) String() string            { return proto.CompactTextSt
 func (*ChaincodeEvent) ProtoMessage()               {}
 func (*ChaincodeEvent) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{0} }
 
+// ChaincodeEvents holds the events set by a chaincode in a transaction that
+// set more than one, in the order they were set. Its field number follows
+// those of ChaincodeEvent so that the two messages are never mistaken for
+// each other: a transaction that set a single event still carries a
+// ChaincodeEvent
+type ChaincodeEvents struct {
+	Events []*ChaincodeEvent `protobuf:"bytes,5,rep,name=events" json:"events,omitempty"`
+}
+
+func (m *ChaincodeEvents) Reset()                    { *m = ChaincodeEvents{} }
+func (m *ChaincodeEvents) String() string            { return proto.CompactTextString(m) }
+func (*ChaincodeEvents) ProtoMessage()               {}
+func (*ChaincodeEvents) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{1} }
+
+func (m *ChaincodeEvents) GetEvents() []*ChaincodeEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*ChaincodeEvent)(nil), "protos.ChaincodeEvent")
+	proto.RegisterType((*ChaincodeEvents)(nil), "protos.ChaincodeEvents")
 }
 
 func init() { proto.RegisterFile("peer/chaincode_event.proto", fileDescriptor2) }
 
 var fileDescriptor2 = []byte{
-	// 218 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xe2, 0x92, 0x2a, 0x48, 0x4d, 0x2d,
-	0xd2, 0x4f, 0xce, 0x48, 0xcc, 0xcc, 0x4b, 0xce, 0x4f, 0x49, 0x8d, 0x4f, 0x2d, 0x4b, 0xcd, 0x2b,
-	0xd1, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x03, 0x53, 0xc5, 0x4a, 0x8d, 0x8c, 0x5c, 0x7c,
-	0xce, 0x30, 0x15, 0xae, 0x20, 0x05, 0x42, 0x8a, 0x5c, 0x3c, 0x08, 0x3d, 0x99, 0x29, 0x12, 0x8c,
-	0x0a, 0x8c, 0x1a, 0x9c, 0x41, 0xdc, 0x70, 0x31, 0xcf, 0x14, 0x21, 0x61, 0x2e, 0xd6, 0x92, 0x0a,
-	0x90, 0x1c, 0x13, 0x58, 0x8e, 0xa5, 0xa4, 0xc2, 0x33, 0x45, 0x48, 0x96, 0x8b, 0x0b, 0x6c, 0x43,
-	0x7c, 0x5e, 0x62, 0x6e, 0xaa, 0x04, 0x33, 0x58, 0x86, 0x13, 0x2c, 0xe2, 0x97, 0x98, 0x9b, 0x2a,
-	0x24, 0xc1, 0xc5, 0x5e, 0x90, 0x58, 0x99, 0x93, 0x9f, 0x98, 0x22, 0xc1, 0xa2, 0xc0, 0xa8, 0xc1,
-	0x13, 0x04, 0xe3, 0x3a, 0xa5, 0x71, 0x29, 0xe5, 0x17, 0xa5, 0xeb, 0x65, 0x54, 0x16, 0xa4, 0x16,
-	0xe5, 0xa4, 0xa6, 0xa4, 0xa7, 0x16, 0xe9, 0xa5, 0x25, 0x26, 0x15, 0x65, 0x26, 0x43, 0xdc, 0x5a,
-	0xac, 0x07, 0xf2, 0x87, 0x93, 0x28, 0xaa, 0x33, 0x03, 0x12, 0x93, 0xb3, 0x13, 0xd3, 0x53, 0xa3,
-	0x34, 0xd3, 0x33, 0x4b, 0x32, 0x4a, 0x93, 0xf4, 0x92, 0xf3, 0x73, 0xf5, 0x91, 0x4c, 0xd0, 0x87,
-	0x98, 0xa0, 0x0f, 0x31, 0x41, 0x1f, 0x64, 0x42, 0x12, 0xc4, 0xcf, 0xc6, 0x80, 0x00, 0x00, 0x00,
-	0xff, 0xff, 0x4a, 0x9d, 0xa8, 0x17, 0x18, 0x01, 0x00, 0x00,
+	// 243 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x54, 0x90, 0x4f, 0x4b, 0xc4, 0x30,
+	0x10, 0xc5, 0xa9, 0xfb, 0x47, 0x76, 0x76, 0x51, 0x88, 0x28, 0x41, 0x10, 0x6a, 0x4f, 0xf5, 0x92,
+	0x80, 0x7e, 0x02, 0x57, 0x3c, 0xec, 0x45, 0xa4, 0x47, 0x2f, 0xcb, 0x34, 0x99, 0x6d, 0x8b, 0xdb,
+	0xa6, 0xa4, 0x51, 0x76, 0x8f, 0x7e, 0x73, 0x69, 0x62, 0xd5, 0x9e, 0x42, 0xe6, 0xbd, 0xf7, 0x9b,
+	0xe1, 0xc1, 0x75, 0x4b, 0x64, 0xa5, 0x2a, 0xb1, 0x6a, 0x94, 0xd1, 0xb4, 0xa5, 0x4f, 0x6a, 0x9c,
+	0x68, 0xad, 0x71, 0x86, 0xcd, 0xfd, 0xd3, 0x25, 0x5f, 0x11, 0x9c, 0x3d, 0x0d, 0x8e, 0xe7, 0xde,
+	0xc0, 0x6e, 0x61, 0xf5, 0x97, 0xa9, 0x34, 0x8f, 0xe2, 0x28, 0x5d, 0x64, 0xcb, 0xdf, 0xd9, 0x46,
+	0xb3, 0x0b, 0x98, 0xb9, 0x43, 0xaf, 0x9d, 0x78, 0x6d, 0xea, 0x0e, 0x1b, 0xcd, 0x6e, 0x00, 0xfc,
+	0x86, 0x6d, 0x83, 0x35, 0xf1, 0x89, 0x57, 0x16, 0x7e, 0xf2, 0x82, 0x35, 0x31, 0x0e, 0xa7, 0x2d,
+	0x1e, 0xf7, 0x06, 0x35, 0x9f, 0xc6, 0x51, 0xba, 0xca, 0x86, 0x6f, 0xf2, 0x08, 0xe7, 0xe3, 0x13,
+	0x3a, 0x26, 0x60, 0xee, 0x93, 0x1d, 0x9f, 0xc5, 0x93, 0x74, 0x79, 0x7f, 0x15, 0xce, 0xee, 0xc4,
+	0xd8, 0x98, 0xfd, 0xb8, 0xd6, 0x3b, 0x48, 0x8c, 0x2d, 0x44, 0x79, 0x6c, 0xc9, 0xee, 0x49, 0x17,
+	0x64, 0xc5, 0x0e, 0x73, 0x5b, 0xa9, 0x21, 0xd7, 0x57, 0xb1, 0xbe, 0x1c, 0xa7, 0x5f, 0x51, 0xbd,
+	0x63, 0x41, 0x6f, 0x77, 0x45, 0xe5, 0xca, 0x8f, 0x5c, 0x28, 0x53, 0xcb, 0x7f, 0x04, 0x19, 0x08,
+	0x32, 0x10, 0x64, 0x4f, 0xc8, 0x43, 0x6d, 0x0f, 0xdf, 0x03, 0x00, 0xc5, 0x78, 0x4a, 0xf3, 0x5b,
+	0x01, 0x00, 0x00,
 }
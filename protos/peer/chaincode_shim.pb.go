@@ -41,6 +41,14 @@ const (
 	ChaincodeMessage_QUERY_STATE_CLOSE   ChaincodeMessage_Type = 17
 	ChaincodeMessage_KEEPALIVE           ChaincodeMessage_Type = 18
 	ChaincodeMessage_GET_HISTORY_FOR_KEY ChaincodeMessage_Type = 19
+	ChaincodeMessage_GET_PRIVATE_DATA    ChaincodeMessage_Type = 20
+	ChaincodeMessage_PUT_PRIVATE_DATA    ChaincodeMessage_Type = 21
+	ChaincodeMessage_DEL_PRIVATE_DATA    ChaincodeMessage_Type = 22
+	ChaincodeMessage_GET_STATE_METADATA  ChaincodeMessage_Type = 23
+	ChaincodeMessage_PUT_STATE_METADATA  ChaincodeMessage_Type = 24
+	ChaincodeMessage_QUERY_CHAINCODE     ChaincodeMessage_Type = 25
+	ChaincodeMessage_PUT_STATE_CHUNK     ChaincodeMessage_Type = 26
+	ChaincodeMessage_GET_STATE_CHUNK     ChaincodeMessage_Type = 27
 )
 
 var ChaincodeMessage_Type_name = map[int32]string{
@@ -63,6 +71,14 @@ var ChaincodeMessage_Type_name = map[int32]string{
 	17: "QUERY_STATE_CLOSE",
 	18: "KEEPALIVE",
 	19: "GET_HISTORY_FOR_KEY",
+	20: "GET_PRIVATE_DATA",
+	21: "PUT_PRIVATE_DATA",
+	22: "DEL_PRIVATE_DATA",
+	23: "GET_STATE_METADATA",
+	24: "PUT_STATE_METADATA",
+	25: "QUERY_CHAINCODE",
+	26: "PUT_STATE_CHUNK",
+	27: "GET_STATE_CHUNK",
 }
 var ChaincodeMessage_Type_value = map[string]int32{
 	"UNDEFINED":           0,
@@ -84,6 +100,14 @@ var ChaincodeMessage_Type_value = map[string]int32{
 	"QUERY_STATE_CLOSE":   17,
 	"KEEPALIVE":           18,
 	"GET_HISTORY_FOR_KEY": 19,
+	"GET_PRIVATE_DATA":    20,
+	"PUT_PRIVATE_DATA":    21,
+	"DEL_PRIVATE_DATA":    22,
+	"GET_STATE_METADATA":  23,
+	"PUT_STATE_METADATA":  24,
+	"QUERY_CHAINCODE":     25,
+	"PUT_STATE_CHUNK":     26,
+	"GET_STATE_CHUNK":     27,
 }
 
 func (x ChaincodeMessage_Type) String() string {
@@ -101,6 +125,9 @@ type ChaincodeMessage struct {
 	// This event is then stored (currently)
 	// with Block.NonHashData.TransactionResult
 	ChaincodeEvent *ChaincodeEvent `protobuf:"bytes,6,opt,name=chaincode_event,json=chaincodeEvent" json:"chaincode_event,omitempty"`
+	// events emitted by chaincodes that set more than one, in the order they
+	// were set. chaincode_event is used when a single event is set
+	ChaincodeEvents []*ChaincodeEvent `protobuf:"bytes,7,rep,name=chaincode_events,json=chaincodeEvents" json:"chaincode_events,omitempty"`
 }
 
 func (m *ChaincodeMessage) Reset()                    { *m = ChaincodeMessage{} }
@@ -129,6 +156,13 @@ func (m *ChaincodeMessage) GetChaincodeEvent() *ChaincodeEvent {
 	return nil
 }
 
+func (m *ChaincodeMessage) GetChaincodeEvents() []*ChaincodeEvent {
+	if m != nil {
+		return m.ChaincodeEvents
+	}
+	return nil
+}
+
 type PutStateInfo struct {
 	Key   string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
 	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
@@ -139,33 +173,221 @@ func (m *PutStateInfo) String() string            { return proto.CompactTextStri
 func (*PutStateInfo) ProtoMessage()               {}
 func (*PutStateInfo) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{1} }
 
+// GetPrivateData is sent by the chaincode to read a key from a private data collection
+type GetPrivateData struct {
+	Collection string `protobuf:"bytes,1,opt,name=collection" json:"collection,omitempty"`
+	Key        string `protobuf:"bytes,2,opt,name=key" json:"key,omitempty"`
+}
+
+func (m *GetPrivateData) Reset()                    { *m = GetPrivateData{} }
+func (m *GetPrivateData) String() string            { return proto.CompactTextString(m) }
+func (*GetPrivateData) ProtoMessage()               {}
+func (*GetPrivateData) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{2} }
+
+// PutPrivateData is sent by the chaincode to write a key to a private data collection
+type PutPrivateData struct {
+	Collection string `protobuf:"bytes,1,opt,name=collection" json:"collection,omitempty"`
+	Key        string `protobuf:"bytes,2,opt,name=key" json:"key,omitempty"`
+	Value      []byte `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *PutPrivateData) Reset()                    { *m = PutPrivateData{} }
+func (m *PutPrivateData) String() string            { return proto.CompactTextString(m) }
+func (*PutPrivateData) ProtoMessage()               {}
+func (*PutPrivateData) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{3} }
+
+// DelPrivateData is sent by the chaincode to delete a key from a private data collection
+type DelPrivateData struct {
+	Collection string `protobuf:"bytes,1,opt,name=collection" json:"collection,omitempty"`
+	Key        string `protobuf:"bytes,2,opt,name=key" json:"key,omitempty"`
+}
+
+func (m *DelPrivateData) Reset()                    { *m = DelPrivateData{} }
+func (m *DelPrivateData) String() string            { return proto.CompactTextString(m) }
+func (*DelPrivateData) ProtoMessage()               {}
+func (*DelPrivateData) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{4} }
+
+// GetStateMetadata is sent by the chaincode to read the metadata of a key
+type GetStateMetadata struct {
+	Key string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+}
+
+func (m *GetStateMetadata) Reset()                    { *m = GetStateMetadata{} }
+func (m *GetStateMetadata) String() string            { return proto.CompactTextString(m) }
+func (*GetStateMetadata) ProtoMessage()               {}
+func (*GetStateMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{5} }
+
+// PutStateMetadata is sent by the chaincode to set an entry of the metadata of a key
+type PutStateMetadata struct {
+	Key      string         `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Metadata *StateMetadata `protobuf:"bytes,2,opt,name=metadata" json:"metadata,omitempty"`
+}
+
+func (m *PutStateMetadata) Reset()                    { *m = PutStateMetadata{} }
+func (m *PutStateMetadata) String() string            { return proto.CompactTextString(m) }
+func (*PutStateMetadata) ProtoMessage()               {}
+func (*PutStateMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{6} }
+
+func (m *PutStateMetadata) GetMetadata() *StateMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// StateMetadata is a named entry of the metadata of a key
+type StateMetadata struct {
+	Metakey string `protobuf:"bytes,1,opt,name=metakey" json:"metakey,omitempty"`
+	Value   []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *StateMetadata) Reset()                    { *m = StateMetadata{} }
+func (m *StateMetadata) String() string            { return proto.CompactTextString(m) }
+func (*StateMetadata) ProtoMessage()               {}
+func (*StateMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{7} }
+
+// StateMetadataResult is the response to a GetStateMetadata request
+type StateMetadataResult struct {
+	Entries []*StateMetadata `protobuf:"bytes,1,rep,name=entries" json:"entries,omitempty"`
+}
+
+func (m *StateMetadataResult) Reset()                    { *m = StateMetadataResult{} }
+func (m *StateMetadataResult) String() string            { return proto.CompactTextString(m) }
+func (*StateMetadataResult) ProtoMessage()               {}
+func (*StateMetadataResult) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{8} }
+
+func (m *StateMetadataResult) GetEntries() []*StateMetadata {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+// PutStateChunk is sent by the chaincode to stream a part of the value of a key. The chunks
+// of a value are sent in order; the value is written to the key when the last chunk is received
+type PutStateChunk struct {
+	Key  string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	Last bool   `protobuf:"varint,3,opt,name=last" json:"last,omitempty"`
+}
+
+func (m *PutStateChunk) Reset()                    { *m = PutStateChunk{} }
+func (m *PutStateChunk) String() string            { return proto.CompactTextString(m) }
+func (*PutStateChunk) ProtoMessage()               {}
+func (*PutStateChunk) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{9} }
+
+// GetStateChunk is sent by the chaincode to read the part of the value of a key that starts at offset
+type GetStateChunk struct {
+	Key    string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Offset int64  `protobuf:"varint,2,opt,name=offset" json:"offset,omitempty"`
+}
+
+func (m *GetStateChunk) Reset()                    { *m = GetStateChunk{} }
+func (m *GetStateChunk) String() string            { return proto.CompactTextString(m) }
+func (*GetStateChunk) ProtoMessage()               {}
+func (*GetStateChunk) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{10} }
+
+// StateChunk is the response to a GetStateChunk request. size is the size of the whole value and
+// hash the SHA-256 hash of its content, which identifies the value the chunks are read from
+type StateChunk struct {
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Size int64  `protobuf:"varint,2,opt,name=size" json:"size,omitempty"`
+	Hash []byte `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`
+}
+
+func (m *StateChunk) Reset()                    { *m = StateChunk{} }
+func (m *StateChunk) String() string            { return proto.CompactTextString(m) }
+func (*StateChunk) ProtoMessage()               {}
+func (*StateChunk) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{11} }
+
 type GetStateByRange struct {
 	StartKey string `protobuf:"bytes,1,opt,name=startKey" json:"startKey,omitempty"`
 	EndKey   string `protobuf:"bytes,2,opt,name=endKey" json:"endKey,omitempty"`
+	Metadata []byte `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
 }
 
 func (m *GetStateByRange) Reset()                    { *m = GetStateByRange{} }
 func (m *GetStateByRange) String() string            { return proto.CompactTextString(m) }
 func (*GetStateByRange) ProtoMessage()               {}
-func (*GetStateByRange) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{2} }
+func (*GetStateByRange) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{12} }
 
 type GetQueryResult struct {
-	Query string `protobuf:"bytes,1,opt,name=query" json:"query,omitempty"`
+	Query    string `protobuf:"bytes,1,opt,name=query" json:"query,omitempty"`
+	Metadata []byte `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
 }
 
 func (m *GetQueryResult) Reset()                    { *m = GetQueryResult{} }
 func (m *GetQueryResult) String() string            { return proto.CompactTextString(m) }
 func (*GetQueryResult) ProtoMessage()               {}
-func (*GetQueryResult) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{3} }
+func (*GetQueryResult) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{13} }
+
+// QueryMetadata is sent as the metadata of GetStateByRange and GetQueryResult
+// requests to fetch a single page of the results
+type QueryMetadata struct {
+	PageSize int32  `protobuf:"varint,1,opt,name=pageSize" json:"pageSize,omitempty"`
+	Bookmark string `protobuf:"bytes,2,opt,name=bookmark" json:"bookmark,omitempty"`
+}
+
+func (m *QueryMetadata) Reset()                    { *m = QueryMetadata{} }
+func (m *QueryMetadata) String() string            { return proto.CompactTextString(m) }
+func (*QueryMetadata) ProtoMessage()               {}
+func (*QueryMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{14} }
 
 type GetHistoryForKey struct {
-	Key string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Key     string               `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Options *HistoryQueryOptions `protobuf:"bytes,2,opt,name=options" json:"options,omitempty"`
 }
 
 func (m *GetHistoryForKey) Reset()                    { *m = GetHistoryForKey{} }
 func (m *GetHistoryForKey) String() string            { return proto.CompactTextString(m) }
 func (*GetHistoryForKey) ProtoMessage()               {}
-func (*GetHistoryForKey) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{4} }
+func (*GetHistoryForKey) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{15} }
+
+func (m *GetHistoryForKey) GetOptions() *HistoryQueryOptions {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+// HistoryQueryOptions restricts and orders the key modifications returned
+// by a GetHistoryForKey request
+type HistoryQueryOptions struct {
+	// lowest block number of the key modifications returned
+	StartBlock uint64 `protobuf:"varint,1,opt,name=startBlock" json:"startBlock,omitempty"`
+	// highest block number of the key modifications returned, considered
+	// only if hasEndBlock is set
+	EndBlock uint64 `protobuf:"varint,2,opt,name=endBlock" json:"endBlock,omitempty"`
+	// whether endBlock bounds the key modifications returned
+	HasEndBlock bool `protobuf:"varint,7,opt,name=hasEndBlock" json:"hasEndBlock,omitempty"`
+	// earliest transaction timestamp of the key modifications returned (inclusive)
+	StartTime *google_protobuf1.Timestamp `protobuf:"bytes,3,opt,name=startTime" json:"startTime,omitempty"`
+	// transaction timestamp before which the key modifications are returned (exclusive)
+	EndTime *google_protobuf1.Timestamp `protobuf:"bytes,4,opt,name=endTime" json:"endTime,omitempty"`
+	// return the key modifications from the newest to the oldest
+	Reverse bool `protobuf:"varint,5,opt,name=reverse" json:"reverse,omitempty"`
+	// maximum number of key modifications returned, 0 means no limit
+	Limit int32 `protobuf:"varint,6,opt,name=limit" json:"limit,omitempty"`
+}
+
+func (m *HistoryQueryOptions) Reset()                    { *m = HistoryQueryOptions{} }
+func (m *HistoryQueryOptions) String() string            { return proto.CompactTextString(m) }
+func (*HistoryQueryOptions) ProtoMessage()               {}
+func (*HistoryQueryOptions) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{16} }
+
+func (m *HistoryQueryOptions) GetStartTime() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.StartTime
+	}
+	return nil
+}
+
+func (m *HistoryQueryOptions) GetEndTime() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.EndTime
+	}
+	return nil
+}
 
 type QueryStateNext struct {
 	Id string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
@@ -174,7 +396,7 @@ type QueryStateNext struct {
 func (m *QueryStateNext) Reset()                    { *m = QueryStateNext{} }
 func (m *QueryStateNext) String() string            { return proto.CompactTextString(m) }
 func (*QueryStateNext) ProtoMessage()               {}
-func (*QueryStateNext) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{5} }
+func (*QueryStateNext) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{17} }
 
 type QueryStateClose struct {
 	Id string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
@@ -183,7 +405,7 @@ type QueryStateClose struct {
 func (m *QueryStateClose) Reset()                    { *m = QueryStateClose{} }
 func (m *QueryStateClose) String() string            { return proto.CompactTextString(m) }
 func (*QueryStateClose) ProtoMessage()               {}
-func (*QueryStateClose) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{6} }
+func (*QueryStateClose) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{18} }
 
 type QueryResultBytes struct {
 	ResultBytes []byte `protobuf:"bytes,1,opt,name=resultBytes,proto3" json:"resultBytes,omitempty"`
@@ -192,18 +414,19 @@ type QueryResultBytes struct {
 func (m *QueryResultBytes) Reset()                    { *m = QueryResultBytes{} }
 func (m *QueryResultBytes) String() string            { return proto.CompactTextString(m) }
 func (*QueryResultBytes) ProtoMessage()               {}
-func (*QueryResultBytes) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{7} }
+func (*QueryResultBytes) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{19} }
 
 type QueryResponse struct {
-	Results []*QueryResultBytes `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
-	HasMore bool                `protobuf:"varint,2,opt,name=has_more,json=hasMore" json:"has_more,omitempty"`
-	Id      string              `protobuf:"bytes,3,opt,name=id" json:"id,omitempty"`
+	Results  []*QueryResultBytes `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
+	HasMore  bool                `protobuf:"varint,2,opt,name=has_more,json=hasMore" json:"has_more,omitempty"`
+	Id       string              `protobuf:"bytes,3,opt,name=id" json:"id,omitempty"`
+	Metadata []byte              `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`
 }
 
 func (m *QueryResponse) Reset()                    { *m = QueryResponse{} }
 func (m *QueryResponse) String() string            { return proto.CompactTextString(m) }
 func (*QueryResponse) ProtoMessage()               {}
-func (*QueryResponse) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{8} }
+func (*QueryResponse) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{20} }
 
 func (m *QueryResponse) GetResults() []*QueryResultBytes {
 	if m != nil {
@@ -212,16 +435,40 @@ func (m *QueryResponse) GetResults() []*QueryResultBytes {
 	return nil
 }
 
+// QueryResponseMetadata is sent as the metadata of a QueryResponse for paginated queries
+type QueryResponseMetadata struct {
+	FetchedRecordsCount int32  `protobuf:"varint,1,opt,name=fetched_records_count,json=fetchedRecordsCount" json:"fetched_records_count,omitempty"`
+	Bookmark            string `protobuf:"bytes,2,opt,name=bookmark" json:"bookmark,omitempty"`
+}
+
+func (m *QueryResponseMetadata) Reset()                    { *m = QueryResponseMetadata{} }
+func (m *QueryResponseMetadata) String() string            { return proto.CompactTextString(m) }
+func (*QueryResponseMetadata) ProtoMessage()               {}
+func (*QueryResponseMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{21} }
+
 func init() {
 	proto.RegisterType((*ChaincodeMessage)(nil), "protos.ChaincodeMessage")
 	proto.RegisterType((*PutStateInfo)(nil), "protos.PutStateInfo")
+	proto.RegisterType((*GetPrivateData)(nil), "protos.GetPrivateData")
+	proto.RegisterType((*PutPrivateData)(nil), "protos.PutPrivateData")
+	proto.RegisterType((*DelPrivateData)(nil), "protos.DelPrivateData")
+	proto.RegisterType((*GetStateMetadata)(nil), "protos.GetStateMetadata")
+	proto.RegisterType((*PutStateMetadata)(nil), "protos.PutStateMetadata")
+	proto.RegisterType((*StateMetadata)(nil), "protos.StateMetadata")
+	proto.RegisterType((*StateMetadataResult)(nil), "protos.StateMetadataResult")
+	proto.RegisterType((*PutStateChunk)(nil), "protos.PutStateChunk")
+	proto.RegisterType((*GetStateChunk)(nil), "protos.GetStateChunk")
+	proto.RegisterType((*StateChunk)(nil), "protos.StateChunk")
 	proto.RegisterType((*GetStateByRange)(nil), "protos.GetStateByRange")
 	proto.RegisterType((*GetQueryResult)(nil), "protos.GetQueryResult")
+	proto.RegisterType((*QueryMetadata)(nil), "protos.QueryMetadata")
 	proto.RegisterType((*GetHistoryForKey)(nil), "protos.GetHistoryForKey")
+	proto.RegisterType((*HistoryQueryOptions)(nil), "protos.HistoryQueryOptions")
 	proto.RegisterType((*QueryStateNext)(nil), "protos.QueryStateNext")
 	proto.RegisterType((*QueryStateClose)(nil), "protos.QueryStateClose")
 	proto.RegisterType((*QueryResultBytes)(nil), "protos.QueryResultBytes")
 	proto.RegisterType((*QueryResponse)(nil), "protos.QueryResponse")
+	proto.RegisterType((*QueryResponseMetadata)(nil), "protos.QueryResponseMetadata")
 	proto.RegisterEnum("protos.ChaincodeMessage_Type", ChaincodeMessage_Type_name, ChaincodeMessage_Type_value)
 }
 
@@ -329,57 +576,183 @@ var _ChaincodeSupport_serviceDesc = grpc.ServiceDesc{
 	Metadata: fileDescriptor3,
 }
 
+// Client API for Chaincode service
+
+type ChaincodeClient interface {
+	Connect(ctx context.Context, opts ...grpc.CallOption) (Chaincode_ConnectClient, error)
+}
+
+type chaincodeClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewChaincodeClient(cc *grpc.ClientConn) ChaincodeClient {
+	return &chaincodeClient{cc}
+}
+
+func (c *chaincodeClient) Connect(ctx context.Context, opts ...grpc.CallOption) (Chaincode_ConnectClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Chaincode_serviceDesc.Streams[0], c.cc, "/protos.Chaincode/Connect", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &chaincodeConnectClient{stream}
+	return x, nil
+}
+
+type Chaincode_ConnectClient interface {
+	Send(*ChaincodeMessage) error
+	Recv() (*ChaincodeMessage, error)
+	grpc.ClientStream
+}
+
+type chaincodeConnectClient struct {
+	grpc.ClientStream
+}
+
+func (x *chaincodeConnectClient) Send(m *ChaincodeMessage) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *chaincodeConnectClient) Recv() (*ChaincodeMessage, error) {
+	m := new(ChaincodeMessage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for Chaincode service
+
+type ChaincodeServer interface {
+	Connect(Chaincode_ConnectServer) error
+}
+
+func RegisterChaincodeServer(s *grpc.Server, srv ChaincodeServer) {
+	s.RegisterService(&_Chaincode_serviceDesc, srv)
+}
+
+func _Chaincode_Connect_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ChaincodeServer).Connect(&chaincodeConnectServer{stream})
+}
+
+type Chaincode_ConnectServer interface {
+	Send(*ChaincodeMessage) error
+	Recv() (*ChaincodeMessage, error)
+	grpc.ServerStream
+}
+
+type chaincodeConnectServer struct {
+	grpc.ServerStream
+}
+
+func (x *chaincodeConnectServer) Send(m *ChaincodeMessage) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *chaincodeConnectServer) Recv() (*ChaincodeMessage, error) {
+	m := new(ChaincodeMessage)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _Chaincode_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "protos.Chaincode",
+	HandlerType: (*ChaincodeServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Connect",
+			Handler:       _Chaincode_Connect_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: fileDescriptor3,
+}
+
 func init() { proto.RegisterFile("peer/chaincode_shim.proto", fileDescriptor3) }
 
 var fileDescriptor3 = []byte{
-	// 771 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x74, 0x94, 0x6f, 0x6f, 0xe2, 0x46,
-	0x10, 0xc6, 0x8f, 0x3f, 0x49, 0x60, 0x20, 0xb0, 0xb7, 0xb9, 0xa6, 0x3e, 0xa4, 0xaa, 0xd4, 0xaa,
-	0x2a, 0xfa, 0xc6, 0xb4, 0xb4, 0xaa, 0xfa, 0xae, 0x22, 0xb0, 0x21, 0x56, 0x88, 0xcd, 0xad, 0x9d,
-	0xd3, 0xd1, 0x37, 0x96, 0x03, 0x1b, 0x63, 0x15, 0x58, 0x77, 0x77, 0x39, 0x9d, 0x3f, 0x61, 0xbf,
-	0x4c, 0x3f, 0xc4, 0x69, 0x6d, 0x4c, 0xb8, 0x9c, 0xf2, 0xca, 0x7e, 0x66, 0x7e, 0xf3, 0xcc, 0xec,
-	0x6a, 0x35, 0xf0, 0x36, 0x61, 0x4c, 0xf4, 0x17, 0xab, 0x30, 0xde, 0x2e, 0xf8, 0x92, 0x05, 0x72,
-	0x15, 0x6f, 0xac, 0x44, 0x70, 0xc5, 0xf1, 0x69, 0xf6, 0x91, 0x9d, 0xce, 0x33, 0x84, 0x7d, 0x64,
-	0x5b, 0x95, 0x33, 0x9d, 0x8b, 0x2c, 0x97, 0x08, 0x9e, 0x70, 0x19, 0xae, 0xf7, 0xc1, 0xef, 0x23,
-	0xce, 0xa3, 0x35, 0xeb, 0x67, 0xea, 0x61, 0xf7, 0xd8, 0x57, 0xf1, 0x86, 0x49, 0x15, 0x6e, 0x92,
-	0x1c, 0x30, 0xff, 0xaf, 0x02, 0x1a, 0x15, 0x7e, 0x77, 0x4c, 0xca, 0x30, 0x62, 0xf8, 0x57, 0xa8,
-	0xaa, 0x34, 0x61, 0x46, 0xa9, 0x5b, 0xea, 0xb5, 0x06, 0xdf, 0xe5, 0xa8, 0xb4, 0x9e, 0x73, 0x96,
-	0x9f, 0x26, 0x8c, 0x66, 0x28, 0xfe, 0x13, 0xea, 0x07, 0x6b, 0xa3, 0xdc, 0x2d, 0xf5, 0x1a, 0x83,
-	0x8e, 0x95, 0x37, 0xb7, 0x8a, 0xe6, 0x96, 0x5f, 0x10, 0xf4, 0x09, 0xc6, 0x06, 0x9c, 0x25, 0x61,
-	0xba, 0xe6, 0xe1, 0xd2, 0xa8, 0x74, 0x4b, 0xbd, 0x26, 0x2d, 0x24, 0xc6, 0x50, 0x55, 0x9f, 0xe2,
-	0xa5, 0x51, 0xed, 0x96, 0x7a, 0x75, 0x9a, 0xfd, 0xe3, 0x01, 0xd4, 0x8a, 0x23, 0x1a, 0x27, 0x59,
-	0x9b, 0xcb, 0x62, 0x3c, 0x2f, 0x8e, 0xb6, 0x6c, 0x39, 0xdb, 0x67, 0xe9, 0x81, 0xc3, 0x7f, 0x41,
-	0xfb, 0xd9, 0x95, 0x19, 0xa7, 0x5f, 0x96, 0x1e, 0x4e, 0x46, 0x74, 0x96, 0xb6, 0x16, 0x5f, 0x68,
-	0xf3, 0xbf, 0x32, 0x54, 0xf5, 0x59, 0xf1, 0x39, 0xd4, 0xef, 0x9d, 0x31, 0xb9, 0xb6, 0x1d, 0x32,
-	0x46, 0xaf, 0x70, 0x13, 0x6a, 0x94, 0x4c, 0x6c, 0xcf, 0x27, 0x14, 0x95, 0x70, 0x0b, 0xa0, 0x50,
-	0x64, 0x8c, 0xca, 0xb8, 0x06, 0x55, 0xdb, 0xb1, 0x7d, 0x54, 0xc1, 0x75, 0x38, 0xa1, 0x64, 0x38,
-	0x9e, 0xa3, 0x2a, 0x6e, 0x43, 0xc3, 0xa7, 0x43, 0xc7, 0x1b, 0x8e, 0x7c, 0xdb, 0x75, 0xd0, 0x89,
-	0xb6, 0x1c, 0xb9, 0x77, 0xb3, 0x29, 0xf1, 0xc9, 0x18, 0x9d, 0x6a, 0x94, 0x50, 0xea, 0x52, 0x74,
-	0xa6, 0x33, 0x13, 0xe2, 0x07, 0x9e, 0x3f, 0xf4, 0x09, 0xaa, 0x69, 0x39, 0xbb, 0x2f, 0x64, 0x5d,
-	0xcb, 0x31, 0x99, 0xee, 0x25, 0xe0, 0x37, 0x80, 0x6c, 0xe7, 0xbd, 0x7b, 0x4b, 0x82, 0xd1, 0xcd,
-	0xd0, 0x76, 0x46, 0xee, 0x98, 0xa0, 0x46, 0x3e, 0xa0, 0x37, 0x73, 0x1d, 0x8f, 0xa0, 0x73, 0x7c,
-	0x09, 0xf8, 0x60, 0x18, 0x5c, 0xcd, 0x03, 0x3a, 0x74, 0x26, 0x04, 0xb5, 0x74, 0xad, 0x8e, 0xbf,
-	0xbb, 0x27, 0x74, 0x1e, 0x50, 0xe2, 0xdd, 0x4f, 0x7d, 0xd4, 0xd6, 0xd1, 0x3c, 0x92, 0xf3, 0x0e,
-	0xf9, 0xe0, 0x23, 0x84, 0xbf, 0x81, 0xd7, 0xc7, 0xd1, 0xd1, 0xd4, 0xf5, 0x08, 0x7a, 0xad, 0xa7,
-	0xb9, 0x25, 0x64, 0x36, 0x9c, 0xda, 0xef, 0x09, 0xc2, 0xf8, 0x5b, 0xb8, 0xd0, 0x8e, 0x37, 0xb6,
-	0xe7, 0xbb, 0x74, 0x1e, 0x5c, 0xbb, 0x34, 0xb8, 0x25, 0x73, 0x74, 0x61, 0xfe, 0x01, 0xcd, 0xd9,
-	0x4e, 0x79, 0x2a, 0x54, 0xcc, 0xde, 0x3e, 0x72, 0x8c, 0xa0, 0xf2, 0x0f, 0x4b, 0xb3, 0x87, 0x56,
-	0xa7, 0xfa, 0x17, 0xbf, 0x81, 0x93, 0x8f, 0xe1, 0x7a, 0xc7, 0xb2, 0x47, 0xd4, 0xa4, 0xb9, 0x30,
-	0x09, 0xb4, 0x27, 0x2c, 0xaf, 0xbb, 0x4a, 0x69, 0xb8, 0x8d, 0x18, 0xee, 0x40, 0x4d, 0xaa, 0x50,
-	0xa8, 0xdb, 0x43, 0xfd, 0x41, 0xe3, 0x4b, 0x38, 0x65, 0xdb, 0xa5, 0xce, 0x94, 0xb3, 0xcc, 0x5e,
-	0x99, 0x3f, 0x41, 0x6b, 0xc2, 0xd4, 0xbb, 0x1d, 0x13, 0x29, 0x65, 0x72, 0xb7, 0x56, 0xba, 0xdd,
-	0xbf, 0x5a, 0xee, 0x2d, 0x72, 0x61, 0xfe, 0x08, 0x68, 0xc2, 0xd4, 0x4d, 0x2c, 0x15, 0x17, 0xe9,
-	0x35, 0x17, 0xda, 0xf3, 0xab, 0x51, 0xcd, 0x2e, 0xb4, 0x32, 0xab, 0x6c, 0x2c, 0x87, 0x7d, 0x52,
-	0xb8, 0x05, 0xe5, 0x78, 0xb9, 0x47, 0xca, 0xf1, 0xd2, 0xfc, 0x01, 0xda, 0x4f, 0xc4, 0x68, 0xcd,
-	0x25, 0xfb, 0x0a, 0xf9, 0x1d, 0xd0, 0xd1, 0x3c, 0x57, 0xa9, 0x62, 0x12, 0x77, 0xa1, 0x21, 0x9e,
-	0x64, 0x06, 0x37, 0xe9, 0x71, 0xc8, 0xdc, 0xc2, 0x79, 0x51, 0x95, 0xf0, 0xad, 0x64, 0x78, 0x00,
-	0x67, 0x79, 0x5e, 0xe3, 0x95, 0x5e, 0x63, 0x60, 0x14, 0x6f, 0xfb, 0xb9, 0x3b, 0x2d, 0x40, 0xfc,
-	0x16, 0x6a, 0xab, 0x50, 0x06, 0x1b, 0x2e, 0xf2, 0xdb, 0xae, 0xd1, 0xb3, 0x55, 0x28, 0xef, 0xb8,
-	0x28, 0xa6, 0xac, 0x14, 0x53, 0x0e, 0x3e, 0x1c, 0x6d, 0x09, 0x6f, 0x97, 0x24, 0x5c, 0x28, 0x3c,
-	0x86, 0x1a, 0x65, 0x51, 0x2c, 0x15, 0x13, 0xd8, 0x78, 0x69, 0x47, 0x74, 0x5e, 0xcc, 0x98, 0xaf,
-	0x7a, 0xa5, 0x5f, 0x4a, 0x57, 0x2e, 0x98, 0x5c, 0x44, 0xd6, 0x2a, 0x4d, 0x98, 0x58, 0xb3, 0x65,
-	0xc4, 0x84, 0xf5, 0x18, 0x3e, 0x88, 0x78, 0x51, 0xd4, 0xe9, 0xb5, 0xf6, 0xf7, 0xcf, 0x51, 0xac,
-	0x56, 0xbb, 0x07, 0x6b, 0xc1, 0x37, 0xfd, 0x23, 0xb4, 0x9f, 0xa3, 0xf9, 0x7a, 0x93, 0x7d, 0x8d,
-	0x3e, 0xe4, 0xbb, 0xf2, 0xb7, 0xcf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xad, 0x9f, 0x80, 0x68, 0x4f,
-	0x05, 0x00, 0x00,
+	// 1263 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xa4, 0x56, 0x4d, 0x73, 0x1a, 0xc7,
+	0x16, 0x35, 0x5f, 0x02, 0xae, 0x24, 0x68, 0x37, 0x92, 0x3c, 0xc6, 0xf5, 0xde, 0xe3, 0x4d, 0xbd,
+	0x85, 0xde, 0x06, 0x62, 0xe2, 0xa4, 0x92, 0x95, 0x0b, 0x41, 0x0b, 0x51, 0x92, 0x00, 0x37, 0x83,
+	0x63, 0x25, 0x0b, 0x6a, 0x04, 0x2d, 0x98, 0x12, 0x4c, 0x4f, 0xa6, 0x1b, 0x95, 0xc9, 0x4f, 0xc8,
+	0x36, 0x7f, 0x30, 0x3f, 0x23, 0xcb, 0x54, 0xf7, 0x7c, 0xf0, 0x21, 0xcb, 0xae, 0x8a, 0x57, 0xcc,
+	0x39, 0xf7, 0xdc, 0xd3, 0xb7, 0x6f, 0xdf, 0x66, 0x06, 0x5e, 0x7a, 0x8c, 0xf9, 0xb5, 0xf1, 0xcc,
+	0x76, 0xdc, 0x31, 0x9f, 0xb0, 0x91, 0x98, 0x39, 0x8b, 0xaa, 0xe7, 0x73, 0xc9, 0xf1, 0x9e, 0xfe,
+	0x11, 0xe5, 0xf2, 0x8e, 0x84, 0x3d, 0x30, 0x57, 0x06, 0x9a, 0x72, 0x49, 0xc7, 0x3c, 0x9f, 0x7b,
+	0x5c, 0xd8, 0xf3, 0x90, 0xfc, 0xcf, 0x94, 0xf3, 0xe9, 0x9c, 0xd5, 0x34, 0xba, 0x5d, 0xde, 0xd5,
+	0xa4, 0xb3, 0x60, 0x42, 0xda, 0x0b, 0x2f, 0x10, 0x98, 0x7f, 0xee, 0x01, 0x6a, 0x46, 0x7e, 0xd7,
+	0x4c, 0x08, 0x7b, 0xca, 0xf0, 0x6b, 0x48, 0xcb, 0x95, 0xc7, 0x8c, 0x44, 0x25, 0x71, 0x5a, 0xa8,
+	0xff, 0x2b, 0x90, 0x8a, 0xea, 0xae, 0xae, 0x6a, 0xad, 0x3c, 0x46, 0xb5, 0x14, 0xff, 0x00, 0xf9,
+	0xd8, 0xda, 0x48, 0x56, 0x12, 0xa7, 0xfb, 0xf5, 0x72, 0x35, 0x58, 0xbc, 0x1a, 0x2d, 0x5e, 0xb5,
+	0x22, 0x05, 0x5d, 0x8b, 0xb1, 0x01, 0x59, 0xcf, 0x5e, 0xcd, 0xb9, 0x3d, 0x31, 0x52, 0x95, 0xc4,
+	0xe9, 0x01, 0x8d, 0x20, 0xc6, 0x90, 0x96, 0x1f, 0x9d, 0x89, 0x91, 0xae, 0x24, 0x4e, 0xf3, 0x54,
+	0x3f, 0xe3, 0x3a, 0xe4, 0xa2, 0x2d, 0x1a, 0x19, 0xbd, 0xcc, 0x49, 0x54, 0xde, 0xc0, 0x99, 0xba,
+	0x6c, 0xd2, 0x0f, 0xa3, 0x34, 0xd6, 0xe1, 0xb7, 0x50, 0xdc, 0x69, 0x99, 0xb1, 0xb7, 0x9d, 0x1a,
+	0xef, 0x8c, 0xa8, 0x28, 0x2d, 0x8c, 0xb7, 0x30, 0x6e, 0x00, 0xda, 0x31, 0x10, 0x46, 0xb6, 0x92,
+	0xfa, 0x8c, 0x43, 0x71, 0xdb, 0x41, 0x98, 0x7f, 0xa5, 0x20, 0xad, 0xda, 0x85, 0x0f, 0x21, 0x3f,
+	0xec, 0xb6, 0xc8, 0x79, 0xa7, 0x4b, 0x5a, 0xe8, 0x19, 0x3e, 0x80, 0x1c, 0x25, 0xed, 0xce, 0xc0,
+	0x22, 0x14, 0x25, 0x70, 0x01, 0x20, 0x42, 0xa4, 0x85, 0x92, 0x38, 0x07, 0xe9, 0x4e, 0xb7, 0x63,
+	0xa1, 0x14, 0xce, 0x43, 0x86, 0x92, 0x46, 0xeb, 0x06, 0xa5, 0x71, 0x11, 0xf6, 0x2d, 0xda, 0xe8,
+	0x0e, 0x1a, 0x4d, 0xab, 0xd3, 0xeb, 0xa2, 0x8c, 0xb2, 0x6c, 0xf6, 0xae, 0xfb, 0x57, 0xc4, 0x22,
+	0x2d, 0xb4, 0xa7, 0xa4, 0x84, 0xd2, 0x1e, 0x45, 0x59, 0x15, 0x69, 0x13, 0x6b, 0x34, 0xb0, 0x1a,
+	0x16, 0x41, 0x39, 0x05, 0xfb, 0xc3, 0x08, 0xe6, 0x15, 0x6c, 0x91, 0xab, 0x10, 0x02, 0x3e, 0x02,
+	0xd4, 0xe9, 0xbe, 0xef, 0x5d, 0x92, 0x51, 0xf3, 0xa2, 0xd1, 0xe9, 0x36, 0x7b, 0x2d, 0x82, 0xf6,
+	0x83, 0x02, 0x07, 0xfd, 0x5e, 0x77, 0x40, 0xd0, 0x21, 0x3e, 0x01, 0x1c, 0x1b, 0x8e, 0xce, 0x6e,
+	0x46, 0xb4, 0xd1, 0x6d, 0x13, 0x54, 0x50, 0xb9, 0x8a, 0x7f, 0x37, 0x24, 0xf4, 0x66, 0x44, 0xc9,
+	0x60, 0x78, 0x65, 0xa1, 0xa2, 0x62, 0x03, 0x26, 0xd0, 0x77, 0xc9, 0x07, 0x0b, 0x21, 0x7c, 0x0c,
+	0xcf, 0x37, 0xd9, 0xe6, 0x55, 0x6f, 0x40, 0xd0, 0x73, 0x55, 0xcd, 0x25, 0x21, 0xfd, 0xc6, 0x55,
+	0xe7, 0x3d, 0x41, 0x18, 0xbf, 0x80, 0x92, 0x72, 0xbc, 0xe8, 0x0c, 0xac, 0x1e, 0xbd, 0x19, 0x9d,
+	0xf7, 0xe8, 0xe8, 0x92, 0xdc, 0xa0, 0x52, 0xb4, 0x54, 0x9f, 0x76, 0xde, 0xab, 0xf4, 0x56, 0xc3,
+	0x6a, 0xa0, 0x23, 0xc5, 0xf6, 0x87, 0x3b, 0xec, 0xb1, 0x62, 0xd5, 0x0e, 0xb7, 0xd8, 0x93, 0xed,
+	0x4d, 0x5c, 0x13, 0xab, 0xa1, 0xf9, 0x17, 0x8a, 0xef, 0x0f, 0x1f, 0xf1, 0x06, 0x2e, 0x41, 0x31,
+	0x28, 0x78, 0xdd, 0x97, 0x97, 0x8a, 0x5c, 0x8b, 0x9b, 0x17, 0xc3, 0xee, 0x25, 0x2a, 0x2b, 0xb2,
+	0x4d, 0xb6, 0xc9, 0x57, 0xe6, 0xf7, 0x70, 0xd0, 0x5f, 0xca, 0x81, 0xb4, 0x25, 0xeb, 0xb8, 0x77,
+	0x1c, 0x23, 0x48, 0xdd, 0xb3, 0x95, 0xbe, 0x5c, 0x79, 0xaa, 0x1e, 0xf1, 0x11, 0x64, 0x1e, 0xec,
+	0xf9, 0x92, 0xe9, 0x8b, 0x73, 0x40, 0x03, 0x60, 0x9e, 0x41, 0xa1, 0xcd, 0x64, 0xdf, 0x77, 0x1e,
+	0x6c, 0xc9, 0x5a, 0xb6, 0xb4, 0xf1, 0xbf, 0x01, 0xc6, 0x7c, 0x3e, 0x67, 0x63, 0xe9, 0x70, 0x37,
+	0x34, 0xd8, 0x60, 0x22, 0xe7, 0x64, 0xec, 0x6c, 0x7e, 0x80, 0x42, 0x7f, 0xf9, 0x75, 0x1e, 0xeb,
+	0xea, 0x52, 0x3b, 0xd5, 0xb5, 0xd8, 0xfc, 0xeb, 0xaa, 0xfb, 0x1f, 0xa0, 0x36, 0x0b, 0x3a, 0x73,
+	0xcd, 0xa4, 0x3d, 0x51, 0x2e, 0x8f, 0xba, 0x63, 0xfe, 0x04, 0xa8, 0xbf, 0xfc, 0x92, 0x0a, 0xbf,
+	0x86, 0xdc, 0x22, 0x8c, 0x86, 0xff, 0x3f, 0xc7, 0xf1, 0x1f, 0xc3, 0x66, 0x2a, 0x8d, 0x65, 0xe6,
+	0x5b, 0x38, 0xdc, 0x76, 0x35, 0x20, 0xab, 0x82, 0x6b, 0xe7, 0x08, 0x3e, 0x71, 0x42, 0xe7, 0x50,
+	0xda, 0xf6, 0x66, 0x62, 0x39, 0x97, 0xb8, 0x06, 0x59, 0xe6, 0x4a, 0xdf, 0x61, 0xc2, 0x48, 0x54,
+	0x52, 0x4f, 0x57, 0x12, 0xa9, 0xcc, 0x0e, 0x1c, 0x46, 0x3b, 0x6c, 0xce, 0x96, 0xee, 0xfd, 0x27,
+	0xb6, 0x87, 0x21, 0x1d, 0x6f, 0xed, 0x80, 0xea, 0x67, 0xc5, 0xcd, 0x6d, 0x21, 0xf5, 0xb9, 0xe4,
+	0xa8, 0x7e, 0x36, 0x7f, 0x84, 0xc3, 0x36, 0xfb, 0xbc, 0xd5, 0x09, 0xec, 0xf1, 0xbb, 0x3b, 0xc1,
+	0xa4, 0x36, 0x4b, 0xd1, 0x10, 0x99, 0x17, 0x00, 0x1b, 0x79, 0xd1, 0x82, 0x89, 0xed, 0x05, 0x85,
+	0xf3, 0x1b, 0x0b, 0xf3, 0xf4, 0xb3, 0xe2, 0x66, 0xb6, 0x98, 0x85, 0xc3, 0xa1, 0x9f, 0x4d, 0x1b,
+	0x8a, 0x51, 0x11, 0x67, 0x2b, 0x6a, 0xbb, 0x53, 0x86, 0xcb, 0x90, 0x13, 0xd2, 0xf6, 0xe5, 0x65,
+	0x5c, 0x4b, 0x8c, 0x55, 0x41, 0xcc, 0x9d, 0x5c, 0xc6, 0xb3, 0x11, 0x22, 0x95, 0x13, 0x1f, 0x69,
+	0x60, 0xbf, 0x3e, 0xbb, 0xe0, 0x72, 0xbc, 0x5b, 0x32, 0x7f, 0x15, 0x76, 0xfd, 0x08, 0x32, 0xbf,
+	0x2a, 0x18, 0xda, 0x07, 0x60, 0xcb, 0x23, 0xb9, 0xe3, 0xd1, 0x86, 0x43, 0x6d, 0x10, 0x9f, 0x7f,
+	0x19, 0x72, 0x9e, 0x3d, 0x65, 0x03, 0xb5, 0x47, 0xe5, 0x92, 0xa1, 0x31, 0x56, 0xb1, 0x5b, 0xce,
+	0xef, 0x17, 0xb6, 0x7f, 0x1f, 0x96, 0x19, 0x63, 0xf3, 0x17, 0x3d, 0xc7, 0x17, 0x8e, 0x90, 0xdc,
+	0x5f, 0x9d, 0x73, 0x5f, 0x15, 0xff, 0xb8, 0xef, 0xdf, 0x41, 0x96, 0x7b, 0xea, 0x26, 0x88, 0x70,
+	0x40, 0x5f, 0x45, 0x63, 0x11, 0x66, 0xea, 0x62, 0x7a, 0x81, 0x84, 0x46, 0x5a, 0xf3, 0x8f, 0x24,
+	0x94, 0x3e, 0x21, 0x50, 0xd7, 0x4d, 0x77, 0xf0, 0x6c, 0xce, 0xc7, 0xf7, 0x7a, 0x9d, 0x34, 0xdd,
+	0x60, 0x54, 0xc1, 0xcc, 0x9d, 0x04, 0xd1, 0xa4, 0x8e, 0xc6, 0x18, 0x57, 0x60, 0x7f, 0x66, 0x0b,
+	0x12, 0x85, 0xb3, 0x7a, 0x80, 0x36, 0x29, 0xf5, 0x3e, 0xd7, 0x5e, 0xea, 0x95, 0x6d, 0xa4, 0xbe,
+	0xfc, 0x3e, 0x8f, 0xc5, 0xf8, 0x8d, 0x9a, 0xfe, 0x89, 0xce, 0x4b, 0x7f, 0x31, 0x2f, 0x92, 0xaa,
+	0xab, 0xe7, 0xb3, 0x07, 0xe6, 0x0b, 0xa6, 0x5f, 0xeb, 0x39, 0x1a, 0x41, 0x75, 0xae, 0x73, 0x67,
+	0xe1, 0x04, 0xef, 0xec, 0x0c, 0x0d, 0x80, 0x59, 0x81, 0x82, 0xee, 0x86, 0x1e, 0xb2, 0x2e, 0xfb,
+	0x28, 0x71, 0x01, 0x92, 0xce, 0x24, 0xec, 0x77, 0xd2, 0x99, 0x98, 0xff, 0x85, 0xe2, 0x5a, 0xd1,
+	0x9c, 0x73, 0xc1, 0x1e, 0x49, 0xde, 0x00, 0xda, 0x98, 0xa0, 0xb3, 0x95, 0x64, 0x42, 0xb5, 0xc6,
+	0x5f, 0xc3, 0x70, 0xfc, 0x37, 0x29, 0xf3, 0xf7, 0x44, 0x38, 0x37, 0x94, 0x09, 0x8f, 0xbb, 0x82,
+	0xe1, 0x3a, 0x64, 0x03, 0x41, 0x74, 0xe1, 0x8d, 0xe8, 0x64, 0x77, 0xed, 0x69, 0x24, 0xc4, 0x2f,
+	0x21, 0x37, 0xb3, 0xc5, 0x68, 0xc1, 0xfd, 0xe0, 0x3e, 0xe5, 0x68, 0x76, 0x66, 0x8b, 0x6b, 0xee,
+	0x47, 0x65, 0xa6, 0xa2, 0x32, 0xb7, 0x66, 0x38, 0xbd, 0x33, 0xc3, 0x53, 0x38, 0xde, 0xaa, 0x25,
+	0x9e, 0xe5, 0x3a, 0x1c, 0xdf, 0x31, 0x39, 0x9e, 0xb1, 0xc9, 0xc8, 0x67, 0x63, 0xee, 0x4f, 0xc4,
+	0x68, 0xcc, 0x97, 0xae, 0x0c, 0x07, 0xbb, 0x14, 0x06, 0x69, 0x10, 0x6b, 0xaa, 0xd0, 0xe7, 0x66,
+	0xbc, 0xfe, 0x61, 0xe3, 0x3b, 0x71, 0xb0, 0xf4, 0x3c, 0xee, 0x4b, 0xdc, 0x82, 0x1c, 0x65, 0x53,
+	0x47, 0x48, 0xe6, 0x63, 0xe3, 0xa9, 0xaf, 0xc4, 0xf2, 0x93, 0x11, 0xf3, 0xd9, 0x69, 0xe2, 0x9b,
+	0x44, 0xbd, 0x0f, 0xf9, 0x38, 0x82, 0x9b, 0x90, 0x6d, 0x72, 0xd7, 0x65, 0x63, 0xf9, 0xcf, 0x1d,
+	0xcf, 0x7a, 0x60, 0x72, 0x7f, 0x5a, 0x9d, 0xad, 0x3c, 0xe6, 0xcf, 0xd9, 0x64, 0xca, 0xfc, 0xea,
+	0x9d, 0x7d, 0xeb, 0x3b, 0xe3, 0x28, 0x4f, 0x7d, 0x2a, 0xff, 0xfc, 0xff, 0xa9, 0x23, 0x67, 0xcb,
+	0xdb, 0xea, 0x98, 0x2f, 0x6a, 0x1b, 0xd2, 0x5a, 0x20, 0x0d, 0x3e, 0x99, 0x45, 0x4d, 0x49, 0x6f,
+	0x83, 0xef, 0xef, 0x6f, 0xff, 0x1e, 0x00, 0xc0, 0xb4, 0x17, 0x50, 0xa3, 0x0b, 0x00, 0x00,
 }
@@ -44,25 +44,39 @@ func (m *AnchorPeer) String() string            { return proto.CompactTextString
 func (*AnchorPeer) ProtoMessage()               {}
 func (*AnchorPeer) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{1} }
 
+// TxReordering is the Application config value which enables the reordering of the conflicting
+// transactions of a block during validation, so that as many of them as possible remain valid
+type TxReordering struct {
+	Enabled bool `protobuf:"varint,1,opt,name=enabled" json:"enabled,omitempty"`
+}
+
+func (m *TxReordering) Reset()                    { *m = TxReordering{} }
+func (m *TxReordering) String() string            { return proto.CompactTextString(m) }
+func (*TxReordering) ProtoMessage()               {}
+func (*TxReordering) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{2} }
+
 func init() {
 	proto.RegisterType((*AnchorPeers)(nil), "protos.AnchorPeers")
 	proto.RegisterType((*AnchorPeer)(nil), "protos.AnchorPeer")
+	proto.RegisterType((*TxReordering)(nil), "protos.TxReordering")
 }
 
 func init() { proto.RegisterFile("peer/configuration.proto", fileDescriptor4) }
 
 var fileDescriptor4 = []byte{
-	// 192 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x4c, 0x8f, 0x3d, 0x8f, 0xc3, 0x20,
-	0x0c, 0x86, 0xc5, 0x7d, 0x49, 0x47, 0x6e, 0x62, 0x62, 0x8c, 0x32, 0xe5, 0x16, 0x90, 0xee, 0xe3,
-	0x07, 0xb4, 0xea, 0xde, 0x2a, 0x63, 0x97, 0x8a, 0x50, 0x02, 0x48, 0x6d, 0x8c, 0x0c, 0x19, 0xfa,
-	0xef, 0x2b, 0x40, 0x55, 0x3a, 0xf1, 0x62, 0x3f, 0x8f, 0x6c, 0x53, 0x1e, 0x8c, 0x41, 0xa9, 0x61,
-	0x9e, 0xbc, 0x5d, 0x50, 0x25, 0x0f, 0xb3, 0x08, 0x08, 0x09, 0xd8, 0x47, 0x79, 0x62, 0xb7, 0xa3,
-	0xcd, 0x66, 0xd6, 0x0e, 0xf0, 0x60, 0x0c, 0x46, 0xf6, 0x4f, 0xbf, 0x54, 0xf9, 0x9e, 0xb2, 0x19,
-	0x39, 0x69, 0x5f, 0xfb, 0xe6, 0x87, 0x55, 0x29, 0x8a, 0x15, 0x1d, 0x1a, 0xb5, 0x6a, 0xdd, 0x1f,
-	0xa5, 0x6b, 0x8b, 0x31, 0xfa, 0xe6, 0x20, 0x26, 0x4e, 0x5a, 0xd2, 0x7f, 0x0e, 0x25, 0xe7, 0x5a,
-	0x00, 0x4c, 0xfc, 0xa5, 0x25, 0xfd, 0xfb, 0x50, 0xf2, 0x76, 0x4f, 0x3b, 0x40, 0x2b, 0xdc, 0x2d,
-	0x18, 0xbc, 0x98, 0xb3, 0x35, 0x28, 0x26, 0x35, 0xa2, 0xd7, 0x8f, 0x71, 0x79, 0x87, 0xe3, 0xb7,
-	0xf5, 0xc9, 0x2d, 0xa3, 0xd0, 0x70, 0x95, 0x4f, 0xa8, 0xac, 0xa8, 0xac, 0xa8, 0xcc, 0xe8, 0x58,
-	0x8f, 0xfa, 0xbd, 0x07, 0x00, 0x00, 0xff, 0xff, 0x6d, 0xf2, 0x7b, 0x6f, 0xf7, 0x00, 0x00, 0x00,
+	// 214 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x4c, 0x8f, 0x4d, 0x4b, 0xc4, 0x30,
+	0x10, 0x86, 0xa9, 0xdf, 0x4e, 0xf7, 0x94, 0x53, 0x8e, 0xa5, 0xa7, 0x7a, 0x49, 0xc0, 0x8f, 0x1f,
+	0xa0, 0x78, 0x57, 0x82, 0x27, 0x2f, 0x92, 0xb6, 0xb3, 0x69, 0x60, 0xcd, 0x84, 0x49, 0x16, 0xf4,
+	0xdf, 0x4b, 0x13, 0x96, 0x7a, 0xca, 0xfb, 0x66, 0x9e, 0x07, 0x66, 0x40, 0x46, 0x44, 0xd6, 0x13,
+	0x85, 0xbd, 0x77, 0x47, 0xb6, 0xd9, 0x53, 0x50, 0x91, 0x29, 0x93, 0xb8, 0x2a, 0x4f, 0xea, 0x5f,
+	0xa1, 0x7d, 0x0e, 0xd3, 0x42, 0xfc, 0x8e, 0xc8, 0x49, 0x3c, 0xc1, 0xce, 0x96, 0xfa, 0xb5, 0x9a,
+	0x49, 0x36, 0xdd, 0xf9, 0xd0, 0xde, 0x8b, 0x2a, 0x25, 0xb5, 0xa1, 0xa6, 0xb5, 0x9b, 0xd6, 0x3f,
+	0x02, 0x6c, 0x23, 0x21, 0xe0, 0x62, 0xa1, 0x94, 0x65, 0xd3, 0x35, 0xc3, 0xad, 0x29, 0x79, 0xfd,
+	0x8b, 0xc4, 0x59, 0x9e, 0x75, 0xcd, 0x70, 0x69, 0x4a, 0xee, 0x07, 0xd8, 0x7d, 0xfc, 0x18, 0x24,
+	0x9e, 0x91, 0x7d, 0x70, 0x42, 0xc2, 0x35, 0x06, 0x3b, 0x1e, 0x70, 0x2e, 0xea, 0x8d, 0x39, 0xd5,
+	0x97, 0x37, 0xe8, 0x89, 0x9d, 0x5a, 0x7e, 0x23, 0xf2, 0x01, 0x67, 0x87, 0xac, 0xf6, 0x76, 0x64,
+	0x3f, 0x9d, 0x16, 0x8b, 0x88, 0xfc, 0x79, 0xe7, 0x7c, 0x5e, 0x8e, 0xa3, 0x9a, 0xe8, 0x5b, 0xff,
+	0x43, 0x75, 0x45, 0x75, 0x45, 0xf5, 0x8a, 0x8e, 0xf5, 0xfc, 0x87, 0xbf, 0x01, 0x00, 0x72, 0x27,
+	0xb1, 0x8f, 0x21, 0x01, 0x00, 0x00,
 }
@@ -46,11 +46,37 @@ func (x EventType) String() string {
 }
 func (EventType) EnumDescriptor() ([]byte, []int) { return fileDescriptor5, []int{0} }
 
+// MatchType tells how event_name is compared to the names of the events
+type ChaincodeReg_MatchType int32
+
+const (
+	ChaincodeReg_EXACT ChaincodeReg_MatchType = 0
+	ChaincodeReg_GLOB  ChaincodeReg_MatchType = 1
+	ChaincodeReg_REGEX ChaincodeReg_MatchType = 2
+)
+
+var ChaincodeReg_MatchType_name = map[int32]string{
+	0: "EXACT",
+	1: "GLOB",
+	2: "REGEX",
+}
+var ChaincodeReg_MatchType_value = map[string]int32{
+	"EXACT": 0,
+	"GLOB":  1,
+	"REGEX": 2,
+}
+
+func (x ChaincodeReg_MatchType) String() string {
+	return proto.EnumName(ChaincodeReg_MatchType_name, int32(x))
+}
+func (ChaincodeReg_MatchType) EnumDescriptor() ([]byte, []int) { return fileDescriptor5, []int{0, 0} }
+
 // ChaincodeReg is used for registering chaincode Interests
 // when EventType is CHAINCODE
 type ChaincodeReg struct {
-	ChaincodeId string `protobuf:"bytes,1,opt,name=chaincode_id,json=chaincodeId" json:"chaincode_id,omitempty"`
-	EventName   string `protobuf:"bytes,2,opt,name=event_name,json=eventName" json:"event_name,omitempty"`
+	ChaincodeId string                 `protobuf:"bytes,1,opt,name=chaincode_id,json=chaincodeId" json:"chaincode_id,omitempty"`
+	EventName   string                 `protobuf:"bytes,2,opt,name=event_name,json=eventName" json:"event_name,omitempty"`
+	MatchType   ChaincodeReg_MatchType `protobuf:"varint,3,opt,name=match_type,json=matchType,enum=protos.ChaincodeReg_MatchType" json:"match_type,omitempty"`
 }
 
 func (m *ChaincodeReg) Reset()                    { *m = ChaincodeReg{} }
@@ -442,6 +468,199 @@ func _Event_OneofSizer(msg proto.Message) (n int) {
 	return n
 }
 
+// FilteredBlock is a block stripped down to what a client needs to know
+// whether its transactions committed
+type FilteredBlock struct {
+	ChannelId            string                 `protobuf:"bytes,1,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+	Number               uint64                 `protobuf:"varint,2,opt,name=number" json:"number,omitempty"`
+	FilteredTransactions []*FilteredTransaction `protobuf:"bytes,3,rep,name=filtered_transactions,json=filteredTransactions" json:"filtered_transactions,omitempty"`
+}
+
+func (m *FilteredBlock) Reset()                    { *m = FilteredBlock{} }
+func (m *FilteredBlock) String() string            { return proto.CompactTextString(m) }
+func (*FilteredBlock) ProtoMessage()               {}
+func (*FilteredBlock) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{7} }
+
+func (m *FilteredBlock) GetFilteredTransactions() []*FilteredTransaction {
+	if m != nil {
+		return m.FilteredTransactions
+	}
+	return nil
+}
+
+// FilteredTransaction identifies a transaction of a FilteredBlock and tells
+// whether it is valid. The orderers, which do not validate transactions,
+// report them as NOT_VALIDATED
+type FilteredTransaction struct {
+	Txid             string            `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
+	Type             common.HeaderType `protobuf:"varint,2,opt,name=type,enum=common.HeaderType" json:"type,omitempty"`
+	TxValidationCode TxValidationCode  `protobuf:"varint,3,opt,name=tx_validation_code,json=txValidationCode,enum=protos.TxValidationCode" json:"tx_validation_code,omitempty"`
+	// The chaincode events of the transaction, only set when requested and
+	// unless the transaction is invalid
+	ChaincodeEvents []*ChaincodeEvent `protobuf:"bytes,4,rep,name=chaincode_events,json=chaincodeEvents" json:"chaincode_events,omitempty"`
+}
+
+func (m *FilteredTransaction) Reset()                    { *m = FilteredTransaction{} }
+func (m *FilteredTransaction) String() string            { return proto.CompactTextString(m) }
+func (*FilteredTransaction) ProtoMessage()               {}
+func (*FilteredTransaction) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{8} }
+
+func (m *FilteredTransaction) GetChaincodeEvents() []*ChaincodeEvent {
+	if m != nil {
+		return m.ChaincodeEvents
+	}
+	return nil
+}
+
+// DeliverResponse carries the blocks, or filtered blocks, requested from the
+// peer Deliver service followed by a status
+type DeliverResponse struct {
+	// Types that are valid to be assigned to Type:
+	//	*DeliverResponse_Status
+	//	*DeliverResponse_Block
+	//	*DeliverResponse_FilteredBlock
+	Type isDeliverResponse_Type `protobuf_oneof:"Type"`
+}
+
+func (m *DeliverResponse) Reset()                    { *m = DeliverResponse{} }
+func (m *DeliverResponse) String() string            { return proto.CompactTextString(m) }
+func (*DeliverResponse) ProtoMessage()               {}
+func (*DeliverResponse) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{9} }
+
+type isDeliverResponse_Type interface {
+	isDeliverResponse_Type()
+}
+
+type DeliverResponse_Status struct {
+	Status common.Status `protobuf:"varint,1,opt,name=status,enum=common.Status,oneof"`
+}
+type DeliverResponse_Block struct {
+	Block *common.Block `protobuf:"bytes,2,opt,name=block,oneof"`
+}
+type DeliverResponse_FilteredBlock struct {
+	FilteredBlock *FilteredBlock `protobuf:"bytes,3,opt,name=filtered_block,json=filteredBlock,oneof"`
+}
+
+func (*DeliverResponse_Status) isDeliverResponse_Type()        {}
+func (*DeliverResponse_Block) isDeliverResponse_Type()         {}
+func (*DeliverResponse_FilteredBlock) isDeliverResponse_Type() {}
+
+func (m *DeliverResponse) GetType() isDeliverResponse_Type {
+	if m != nil {
+		return m.Type
+	}
+	return nil
+}
+
+func (m *DeliverResponse) GetStatus() common.Status {
+	if x, ok := m.GetType().(*DeliverResponse_Status); ok {
+		return x.Status
+	}
+	return common.Status_UNKNOWN
+}
+
+func (m *DeliverResponse) GetBlock() *common.Block {
+	if x, ok := m.GetType().(*DeliverResponse_Block); ok {
+		return x.Block
+	}
+	return nil
+}
+
+func (m *DeliverResponse) GetFilteredBlock() *FilteredBlock {
+	if x, ok := m.GetType().(*DeliverResponse_FilteredBlock); ok {
+		return x.FilteredBlock
+	}
+	return nil
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*DeliverResponse) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _DeliverResponse_OneofMarshaler, _DeliverResponse_OneofUnmarshaler, _DeliverResponse_OneofSizer, []interface{}{
+		(*DeliverResponse_Status)(nil),
+		(*DeliverResponse_Block)(nil),
+		(*DeliverResponse_FilteredBlock)(nil),
+	}
+}
+
+func _DeliverResponse_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*DeliverResponse)
+	// Type
+	switch x := m.Type.(type) {
+	case *DeliverResponse_Status:
+		b.EncodeVarint(1<<3 | proto.WireVarint)
+		b.EncodeVarint(uint64(x.Status))
+	case *DeliverResponse_Block:
+		b.EncodeVarint(2<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Block); err != nil {
+			return err
+		}
+	case *DeliverResponse_FilteredBlock:
+		b.EncodeVarint(3<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.FilteredBlock); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		return fmt.Errorf("DeliverResponse.Type has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _DeliverResponse_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*DeliverResponse)
+	switch tag {
+	case 1: // Type.status
+		if wire != proto.WireVarint {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeVarint()
+		m.Type = &DeliverResponse_Status{common.Status(x)}
+		return true, err
+	case 2: // Type.block
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(common.Block)
+		err := b.DecodeMessage(msg)
+		m.Type = &DeliverResponse_Block{msg}
+		return true, err
+	case 3: // Type.filtered_block
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(FilteredBlock)
+		err := b.DecodeMessage(msg)
+		m.Type = &DeliverResponse_FilteredBlock{msg}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _DeliverResponse_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*DeliverResponse)
+	// Type
+	switch x := m.Type.(type) {
+	case *DeliverResponse_Status:
+		n += proto.SizeVarint(1<<3 | proto.WireVarint)
+		n += proto.SizeVarint(uint64(x.Status))
+	case *DeliverResponse_Block:
+		s := proto.Size(x.Block)
+		n += proto.SizeVarint(2<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *DeliverResponse_FilteredBlock:
+		s := proto.Size(x.FilteredBlock)
+		n += proto.SizeVarint(3<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
 func init() {
 	proto.RegisterType((*ChaincodeReg)(nil), "protos.ChaincodeReg")
 	proto.RegisterType((*Interest)(nil), "protos.Interest")
@@ -450,7 +669,11 @@ func init() {
 	proto.RegisterType((*Unregister)(nil), "protos.Unregister")
 	proto.RegisterType((*SignedEvent)(nil), "protos.SignedEvent")
 	proto.RegisterType((*Event)(nil), "protos.Event")
+	proto.RegisterType((*FilteredBlock)(nil), "protos.FilteredBlock")
+	proto.RegisterType((*FilteredTransaction)(nil), "protos.FilteredTransaction")
+	proto.RegisterType((*DeliverResponse)(nil), "protos.DeliverResponse")
 	proto.RegisterEnum("protos.EventType", EventType_name, EventType_value)
+	proto.RegisterEnum("protos.ChaincodeReg_MatchType", ChaincodeReg_MatchType_name, ChaincodeReg_MatchType_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -559,47 +782,165 @@ var _Events_serviceDesc = grpc.ServiceDesc{
 	Metadata: fileDescriptor5,
 }
 
+// Client API for Deliver service
+
+type DeliverClient interface {
+	// Deliver first requires an Envelope of type DELIVER_SEEK_INFO with Payload data as a marshaled orderer.SeekInfo message,
+	// then a stream of block replies is received.
+	Deliver(ctx context.Context, opts ...grpc.CallOption) (Deliver_DeliverClient, error)
+}
+
+type deliverClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewDeliverClient(cc *grpc.ClientConn) DeliverClient {
+	return &deliverClient{cc}
+}
+
+func (c *deliverClient) Deliver(ctx context.Context, opts ...grpc.CallOption) (Deliver_DeliverClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Deliver_serviceDesc.Streams[0], c.cc, "/protos.Deliver/Deliver", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &deliverDeliverClient{stream}
+	return x, nil
+}
+
+type Deliver_DeliverClient interface {
+	Send(*common.Envelope) error
+	Recv() (*DeliverResponse, error)
+	grpc.ClientStream
+}
+
+type deliverDeliverClient struct {
+	grpc.ClientStream
+}
+
+func (x *deliverDeliverClient) Send(m *common.Envelope) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *deliverDeliverClient) Recv() (*DeliverResponse, error) {
+	m := new(DeliverResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for Deliver service
+
+type DeliverServer interface {
+	// Deliver first requires an Envelope of type DELIVER_SEEK_INFO with Payload data as a marshaled orderer.SeekInfo message,
+	// then a stream of block replies is received.
+	Deliver(Deliver_DeliverServer) error
+}
+
+func RegisterDeliverServer(s *grpc.Server, srv DeliverServer) {
+	s.RegisterService(&_Deliver_serviceDesc, srv)
+}
+
+func _Deliver_Deliver_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DeliverServer).Deliver(&deliverDeliverServer{stream})
+}
+
+type Deliver_DeliverServer interface {
+	Send(*DeliverResponse) error
+	Recv() (*common.Envelope, error)
+	grpc.ServerStream
+}
+
+type deliverDeliverServer struct {
+	grpc.ServerStream
+}
+
+func (x *deliverDeliverServer) Send(m *DeliverResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *deliverDeliverServer) Recv() (*common.Envelope, error) {
+	m := new(common.Envelope)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _Deliver_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "protos.Deliver",
+	HandlerType: (*DeliverServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Deliver",
+			Handler:       _Deliver_Deliver_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: fileDescriptor5,
+}
+
 func init() { proto.RegisterFile("peer/events.proto", fileDescriptor5) }
 
 var fileDescriptor5 = []byte{
-	// 611 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x94, 0x54, 0x4f, 0x6f, 0x9b, 0x4e,
-	0x10, 0x05, 0x27, 0x76, 0xcc, 0xd8, 0xce, 0xcf, 0xd9, 0xfc, 0x14, 0x21, 0xf7, 0x8f, 0x52, 0xaa,
-	0x4a, 0x6e, 0x0f, 0x26, 0xa5, 0x51, 0x0f, 0xbd, 0x05, 0x82, 0x0a, 0x4d, 0xf3, 0x47, 0x1b, 0xf7,
-	0xd2, 0x43, 0x2d, 0x8c, 0x27, 0x98, 0x26, 0x06, 0x6b, 0xd9, 0x54, 0xf1, 0x27, 0xea, 0xa9, 0xdf,
-	0xb1, 0x62, 0x61, 0x81, 0xb4, 0xa7, 0x9e, 0xf0, 0xcc, 0xbc, 0x37, 0xfb, 0xf6, 0xcd, 0xac, 0x61,
-	0x6f, 0x8d, 0xc8, 0x4c, 0xfc, 0x81, 0x09, 0xcf, 0x26, 0x6b, 0x96, 0xf2, 0x94, 0x74, 0xc4, 0x27,
-	0x1b, 0xed, 0x87, 0xe9, 0x6a, 0x95, 0x26, 0x66, 0xf1, 0x29, 0x8a, 0xa3, 0x91, 0xc0, 0x87, 0xcb,
-	0x20, 0x4e, 0xc2, 0x74, 0x81, 0x33, 0xc1, 0x2c, 0x6b, 0x07, 0xa2, 0xc6, 0x59, 0x90, 0x64, 0x41,
-	0xc8, 0x63, 0xc9, 0x31, 0xae, 0xa0, 0xef, 0x48, 0x02, 0xc5, 0x88, 0xbc, 0x80, 0x7e, 0xdd, 0x20,
-	0x5e, 0xe8, 0xea, 0xa1, 0x3a, 0xd6, 0x68, 0xaf, 0xca, 0xf9, 0x0b, 0xf2, 0x0c, 0x40, 0x74, 0x9e,
-	0x25, 0xc1, 0x0a, 0xf5, 0x96, 0x00, 0x68, 0x22, 0x73, 0x11, 0xac, 0xd0, 0xf8, 0xa9, 0x42, 0xd7,
-	0x4f, 0x38, 0x32, 0xcc, 0x38, 0x39, 0x92, 0x58, 0xbe, 0x59, 0xa3, 0x68, 0xb6, 0x6b, 0xed, 0x15,
-	0x47, 0x67, 0x13, 0x37, 0xaf, 0x4c, 0x37, 0x6b, 0x2c, 0xe9, 0xf9, 0x4f, 0x72, 0x0a, 0xa4, 0x16,
-	0xc0, 0x30, 0x9a, 0xc5, 0xc9, 0x4d, 0x2a, 0x4e, 0xe9, 0x59, 0xff, 0x4b, 0x66, 0x53, 0xb2, 0xa7,
-	0xd0, 0x61, 0xd8, 0x88, 0xfd, 0xe4, 0x26, 0x25, 0x3a, 0xec, 0x88, 0x9c, 0x7f, 0xaa, 0x6f, 0x09,
-	0x81, 0x32, 0xb4, 0x35, 0xd8, 0x29, 0x41, 0xc6, 0x31, 0x74, 0x29, 0x46, 0x71, 0xc6, 0x91, 0x91,
-	0x31, 0x74, 0x0a, 0xa3, 0x75, 0xf5, 0x70, 0x6b, 0xdc, 0xb3, 0x86, 0xf2, 0x28, 0x79, 0x15, 0x5a,
-	0xd6, 0x8d, 0x73, 0xd0, 0x28, 0x7e, 0x47, 0x61, 0x22, 0x79, 0x09, 0x2d, 0xfe, 0x20, 0xee, 0xd5,
-	0xb3, 0xf6, 0x25, 0x65, 0x5a, 0xbb, 0x4c, 0x5b, 0xfc, 0x81, 0x3c, 0x01, 0x0d, 0x19, 0x4b, 0xd9,
-	0x6c, 0x95, 0x45, 0xa5, 0x5f, 0x5d, 0x91, 0x38, 0xcf, 0x22, 0xe3, 0x3d, 0xc0, 0x97, 0x84, 0xfd,
-	0xbb, 0x8c, 0x33, 0xe8, 0x5d, 0xc7, 0x51, 0x82, 0x0b, 0xe1, 0x22, 0x79, 0x0a, 0x5a, 0x16, 0x47,
-	0x49, 0xc0, 0xef, 0x59, 0xe1, 0x73, 0x9f, 0xd6, 0x09, 0xf2, 0xbc, 0x1c, 0x83, 0xbd, 0xe1, 0x98,
-	0x09, 0x09, 0x7d, 0xda, 0xc8, 0x18, 0xbf, 0x5a, 0xd0, 0x2e, 0xfa, 0x4c, 0xa0, 0x2b, 0xc5, 0x94,
-	0xd7, 0xaa, 0x24, 0x48, 0xaf, 0x3c, 0x85, 0x56, 0x18, 0xf2, 0x0a, 0xda, 0xf3, 0xbb, 0x34, 0xbc,
-	0x2d, 0x27, 0x34, 0x98, 0x94, 0x1b, 0x69, 0xe7, 0x49, 0x4f, 0xa1, 0x45, 0x95, 0x9c, 0xc0, 0x7f,
-	0x7f, 0xec, 0xa5, 0x98, 0x4b, 0xcf, 0x3a, 0xf8, 0x6b, 0xa4, 0x42, 0x87, 0xa7, 0xd0, 0xdd, 0xf0,
-	0x51, 0x86, 0xbc, 0x05, 0x8d, 0x49, 0xdf, 0xf5, 0x6d, 0x41, 0xde, 0xab, 0xa5, 0x95, 0x05, 0x4f,
-	0xa1, 0x35, 0x8a, 0x1c, 0x03, 0xdc, 0x57, 0xde, 0xea, 0x6d, 0xc1, 0x21, 0x92, 0x53, 0xbb, 0xee,
-	0x29, 0xb4, 0x81, 0x13, 0xbb, 0xc3, 0x30, 0xe0, 0x29, 0xd3, 0x3b, 0xc2, 0x29, 0x19, 0xda, 0x3b,
-	0xa5, 0x4b, 0x6f, 0x6c, 0xd0, 0xaa, 0xe5, 0x25, 0x7d, 0xe8, 0x52, 0xf7, 0xa3, 0x7f, 0x3d, 0x75,
-	0xe9, 0x50, 0x21, 0x1a, 0xb4, 0xed, 0xcf, 0x97, 0xce, 0xd9, 0x50, 0x25, 0x03, 0xd0, 0x1c, 0xef,
-	0xc4, 0xbf, 0x70, 0x2e, 0x4f, 0xdd, 0x61, 0x2b, 0x0f, 0xa9, 0xfb, 0xc9, 0x75, 0xa6, 0xfe, 0xe5,
-	0xc5, 0x70, 0xcb, 0xfa, 0x00, 0x1d, 0xd1, 0x23, 0x23, 0x47, 0xb0, 0xed, 0x2c, 0x03, 0x4e, 0xaa,
-	0x05, 0x6a, 0x0c, 0x76, 0x34, 0x78, 0xf4, 0x5a, 0x0c, 0x65, 0xac, 0x1e, 0xa9, 0xf6, 0x37, 0x30,
-	0x52, 0x16, 0x4d, 0x96, 0x9b, 0x35, 0xb2, 0x3b, 0x5c, 0x44, 0xc8, 0x26, 0x37, 0xc1, 0x9c, 0xc5,
-	0xa1, 0x04, 0xe7, 0xaf, 0xdd, 0x1e, 0x14, 0xfd, 0xaf, 0x82, 0xf0, 0x36, 0x88, 0xf0, 0xeb, 0xeb,
-	0x28, 0xe6, 0xcb, 0xfb, 0x79, 0x3e, 0x21, 0xb3, 0xc1, 0x34, 0x0b, 0xa6, 0x59, 0x30, 0xcd, 0x9c,
-	0x39, 0x2f, 0xfe, 0x66, 0xde, 0xfd, 0x0e, 0x00, 0x00, 0xff, 0xff, 0x19, 0x83, 0x34, 0xd3, 0x82,
-	0x04, 0x00, 0x00,
+	// 911 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x94, 0x55, 0xef, 0x6e, 0xe3, 0x44,
+	0x10, 0xb7, 0x93, 0x34, 0x8d, 0x27, 0x49, 0xeb, 0x6e, 0xef, 0x8a, 0x95, 0x83, 0x53, 0x31, 0x02,
+	0x05, 0x90, 0x92, 0x12, 0x4e, 0x7c, 0x38, 0x09, 0xa4, 0x3a, 0xf5, 0x35, 0xe1, 0xae, 0xed, 0x69,
+	0x1b, 0xd0, 0x89, 0x0f, 0x44, 0x8e, 0x33, 0x71, 0xcc, 0x25, 0x76, 0xb4, 0xbb, 0xa9, 0xd2, 0x37,
+	0xe1, 0x0d, 0x90, 0x90, 0x78, 0x03, 0x1e, 0x85, 0x87, 0x41, 0x5e, 0x7b, 0x1d, 0x37, 0x77, 0x48,
+	0xdc, 0x27, 0xef, 0xfe, 0x66, 0x66, 0xe7, 0xcf, 0x6f, 0x66, 0x0c, 0x47, 0x2b, 0x44, 0xd6, 0xc5,
+	0x3b, 0x8c, 0x04, 0xef, 0xac, 0x58, 0x2c, 0x62, 0x52, 0x95, 0x1f, 0xde, 0x3a, 0xf6, 0xe3, 0xe5,
+	0x32, 0x8e, 0xba, 0xe9, 0x27, 0x15, 0xb6, 0x5a, 0x52, 0xdf, 0x9f, 0x7b, 0x61, 0xe4, 0xc7, 0x53,
+	0x1c, 0x4b, 0xcb, 0x4c, 0x76, 0x22, 0x65, 0x82, 0x79, 0x11, 0xf7, 0x7c, 0x11, 0x2a, 0x1b, 0xfb,
+	0x6f, 0x1d, 0x1a, 0x7d, 0x65, 0x41, 0x31, 0x20, 0x9f, 0x42, 0x63, 0xfb, 0x42, 0x38, 0xb5, 0xf4,
+	0x53, 0xbd, 0x6d, 0xd0, 0x7a, 0x8e, 0x0d, 0xa7, 0xe4, 0x13, 0x00, 0xf9, 0xf4, 0x38, 0xf2, 0x96,
+	0x68, 0x95, 0xa4, 0x82, 0x21, 0x91, 0x6b, 0x6f, 0x89, 0xe4, 0x7b, 0x80, 0xa5, 0x27, 0xfc, 0xf9,
+	0x58, 0xdc, 0xaf, 0xd0, 0x2a, 0x9f, 0xea, 0xed, 0x83, 0xde, 0xd3, 0xd4, 0x1d, 0xef, 0x14, 0x7d,
+	0x75, 0xae, 0x12, 0xb5, 0xd1, 0xfd, 0x0a, 0xa9, 0xb1, 0x54, 0x47, 0xfb, 0x6b, 0x30, 0x72, 0x9c,
+	0x18, 0xb0, 0xe7, 0xbe, 0x39, 0xef, 0x8f, 0x4c, 0x8d, 0xd4, 0xa0, 0x72, 0xf9, 0xea, 0xc6, 0x31,
+	0xf5, 0x04, 0xa4, 0xee, 0xa5, 0xfb, 0xc6, 0x2c, 0xd9, 0x7f, 0xe8, 0x50, 0x1b, 0x46, 0x02, 0x19,
+	0x72, 0x41, 0xce, 0x54, 0x5c, 0xd2, 0xb1, 0x2e, 0x1d, 0x1f, 0x29, 0xc7, 0x6e, 0x22, 0x49, 0x7d,
+	0xa1, 0x3a, 0x92, 0x0b, 0x20, 0xdb, 0x64, 0x19, 0x06, 0xe3, 0x30, 0x9a, 0xc5, 0x32, 0xa3, 0x7a,
+	0xef, 0xd1, 0xfb, 0x42, 0x1e, 0x68, 0xd4, 0xf4, 0x0b, 0xf7, 0x61, 0x34, 0x8b, 0x89, 0x05, 0xfb,
+	0x12, 0x1b, 0x5e, 0xc8, 0x6c, 0x0d, 0xaa, 0xae, 0x8e, 0x01, 0xfb, 0x99, 0x92, 0xfd, 0x0c, 0x6a,
+	0x14, 0x83, 0x90, 0x0b, 0x64, 0xa4, 0x0d, 0xd5, 0x94, 0x55, 0x4b, 0x3f, 0x2d, 0xb7, 0xeb, 0x3d,
+	0x53, 0xb9, 0x52, 0xa9, 0xd0, 0x4c, 0x6e, 0x5f, 0x81, 0x41, 0xf1, 0x37, 0x94, 0x8c, 0x91, 0xcf,
+	0xa0, 0x24, 0x36, 0x32, 0xaf, 0x7a, 0xef, 0x58, 0x99, 0x8c, 0xb6, 0x94, 0xd2, 0x92, 0xd8, 0x90,
+	0x27, 0x60, 0x20, 0x63, 0x31, 0x1b, 0x2f, 0x79, 0x90, 0x71, 0x53, 0x93, 0xc0, 0x15, 0x0f, 0xec,
+	0xef, 0x00, 0x7e, 0x8a, 0xd8, 0x87, 0x87, 0xf1, 0x12, 0xea, 0xb7, 0x61, 0x10, 0xe1, 0x54, 0x56,
+	0x91, 0x7c, 0x0c, 0x06, 0x0f, 0x83, 0xc8, 0x13, 0x6b, 0x96, 0xd6, 0xb9, 0x41, 0xb7, 0x00, 0x79,
+	0x9a, 0xd1, 0xe0, 0xdc, 0x0b, 0xe4, 0x32, 0x84, 0x06, 0x2d, 0x20, 0xf6, 0x5f, 0x25, 0xd8, 0x4b,
+	0xdf, 0xe9, 0x40, 0x4d, 0x05, 0x93, 0xa5, 0x95, 0x87, 0xa0, 0x6a, 0x35, 0xd0, 0x68, 0xae, 0x43,
+	0x3e, 0x87, 0xbd, 0xc9, 0x22, 0xf6, 0xdf, 0x66, 0x0c, 0x35, 0x3b, 0x59, 0xfb, 0x3b, 0x09, 0x38,
+	0xd0, 0x68, 0x2a, 0x25, 0xe7, 0x70, 0xb8, 0x33, 0x04, 0x92, 0x97, 0x7a, 0xef, 0xe4, 0x1d, 0x4a,
+	0x65, 0x1c, 0x03, 0x8d, 0x1e, 0xf8, 0x0f, 0x10, 0xf2, 0x0d, 0x18, 0x4c, 0xd5, 0xdd, 0xaa, 0x48,
+	0xe3, 0xa3, 0x6d, 0x68, 0x99, 0x60, 0xa0, 0xd1, 0xad, 0x16, 0x79, 0x06, 0xb0, 0xce, 0x6b, 0x6b,
+	0xed, 0x49, 0x1b, 0xa2, 0x6c, 0xb6, 0x55, 0x1f, 0x68, 0xb4, 0xa0, 0x27, 0x7b, 0x87, 0xa1, 0x27,
+	0x62, 0x66, 0x55, 0x65, 0xa5, 0xd4, 0xd5, 0xd9, 0xcf, 0xaa, 0x64, 0xff, 0xae, 0x43, 0xf3, 0x45,
+	0xb8, 0x48, 0x28, 0x99, 0xca, 0x4c, 0x93, 0x01, 0xf4, 0xe7, 0x5e, 0x14, 0xe1, 0x62, 0x3b, 0xa1,
+	0x46, 0x86, 0x0c, 0xa7, 0xe4, 0x04, 0xaa, 0xd1, 0x7a, 0x39, 0x41, 0x26, 0xeb, 0x54, 0xa1, 0xd9,
+	0x8d, 0xbc, 0x86, 0xc7, 0xb3, 0xec, 0x9d, 0x71, 0x61, 0x13, 0x70, 0xab, 0x2c, 0xe9, 0x7f, 0xa2,
+	0x82, 0x55, 0xce, 0x8a, 0xad, 0xf5, 0x68, 0xf6, 0x2e, 0xc8, 0xed, 0x7f, 0x74, 0x38, 0x7e, 0x8f,
+	0x36, 0x21, 0x50, 0x11, 0x9b, 0x3c, 0x34, 0x79, 0x26, 0x5f, 0x40, 0x45, 0xce, 0x65, 0x49, 0xce,
+	0x25, 0x51, 0xdc, 0x0d, 0xd0, 0x9b, 0x22, 0x93, 0x83, 0x29, 0xe5, 0xe4, 0x05, 0x10, 0xb1, 0x19,
+	0xdf, 0x79, 0x8b, 0x70, 0xea, 0x25, 0x8f, 0x8d, 0x13, 0x56, 0xb2, 0x35, 0x62, 0xe5, 0x5d, 0xbf,
+	0xf9, 0x39, 0x57, 0xe8, 0x27, 0xa3, 0x68, 0x8a, 0x1d, 0x84, 0x9c, 0x83, 0xb9, 0xd3, 0x05, 0xdc,
+	0xaa, 0x9c, 0x96, 0xff, 0xbb, 0x0d, 0xe8, 0xe1, 0xc3, 0x26, 0xe0, 0xf6, 0x9f, 0x3a, 0x1c, 0x5e,
+	0xe0, 0x22, 0xbc, 0x43, 0x46, 0x91, 0xaf, 0xe2, 0x88, 0x63, 0x32, 0x34, 0x5c, 0x78, 0x62, 0xcd,
+	0xb3, 0x05, 0x73, 0xa0, 0x12, 0xb9, 0x95, 0xe8, 0x40, 0xa3, 0x99, 0xfc, 0xff, 0x76, 0xeb, 0x0f,
+	0x70, 0x90, 0xb3, 0x92, 0xea, 0xa7, 0xcd, 0xfa, 0x78, 0x97, 0x0e, 0x65, 0xd7, 0x9c, 0x15, 0x01,
+	0xa7, 0x0a, 0x95, 0xa4, 0x7a, 0x5f, 0x39, 0x60, 0xe4, 0x3b, 0x8e, 0x34, 0xa0, 0x46, 0xdd, 0xcb,
+	0xe1, 0xed, 0xc8, 0xa5, 0xa6, 0x96, 0x2c, 0x4c, 0xe7, 0xd5, 0x4d, 0xff, 0xa5, 0xa9, 0x93, 0x26,
+	0x18, 0xfd, 0xc1, 0xf9, 0xf0, 0xba, 0x7f, 0x73, 0xe1, 0x9a, 0xa5, 0xe4, 0x4a, 0xdd, 0x1f, 0xdd,
+	0xfe, 0x68, 0x78, 0x73, 0x6d, 0x96, 0x7b, 0xcf, 0xa1, 0x9a, 0xa6, 0x4e, 0xce, 0xa0, 0xd2, 0x9f,
+	0x7b, 0x82, 0xe4, 0x7b, 0xa6, 0x30, 0xff, 0xad, 0xe6, 0x83, 0xa5, 0x6a, 0x6b, 0x6d, 0xfd, 0x4c,
+	0xef, 0xb9, 0xb0, 0x9f, 0xd5, 0x8a, 0x3c, 0xdf, 0x1e, 0x4d, 0x95, 0xb5, 0x1b, 0xdd, 0xe1, 0x22,
+	0x5e, 0x61, 0xeb, 0x23, 0x65, 0xbc, 0x53, 0xd9, 0xf4, 0x19, 0xe7, 0x57, 0xb0, 0x63, 0x16, 0x74,
+	0xe6, 0xf7, 0x2b, 0x64, 0x0b, 0x9c, 0x06, 0xc8, 0x3a, 0x33, 0x6f, 0xc2, 0x42, 0x5f, 0x99, 0x25,
+	0x3f, 0x32, 0xa7, 0x99, 0x86, 0xf9, 0xda, 0xf3, 0xdf, 0x7a, 0x01, 0xfe, 0xf2, 0x65, 0x10, 0x8a,
+	0xf9, 0x7a, 0x92, 0xf8, 0xea, 0x16, 0x2c, 0xbb, 0xa9, 0x65, 0x37, 0xb5, 0xec, 0x26, 0x96, 0x93,
+	0xf4, 0x0f, 0xfa, 0xed, 0xbf, 0x03, 0x00, 0x95, 0x3b, 0x4a, 0x34, 0x5d, 0x07, 0x00, 0x00,
 }
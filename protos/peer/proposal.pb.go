@@ -152,7 +152,8 @@ type ChaincodeAction struct {
 	// chaincode executing this invocation.
 	Results []byte `protobuf:"bytes,1,opt,name=results,proto3" json:"results,omitempty"`
 	// This field contains the events generated by the chaincode executing this
-	// invocation.
+	// invocation: a ChaincodeEvent if it set one event, a ChaincodeEvents
+	// holding them in order if it set several.
 	Events []byte `protobuf:"bytes,2,opt,name=events,proto3" json:"events,omitempty"`
 	// This field contains the result of executing this invocation.
 	Response *Response `protobuf:"bytes,3,opt,name=response" json:"response,omitempty"`
@@ -162,6 +163,11 @@ type ChaincodeAction struct {
 	// Adding ChaincodeID to keep version opens up the possibility of multiple
 	// ChaincodeAction per transaction.
 	ChaincodeId *ChaincodeID `protobuf:"bytes,4,opt,name=chaincode_id,json=chaincodeId" json:"chaincode_id,omitempty"`
+	// This field contains the read sets produced on other channels by the
+	// chaincodes queried there while executing this invocation, serialized as
+	// a CrossChannelReadSets message. Those reads are not part of the results
+	// above since they cannot be validated against the ledger of this channel.
+	CrossChannelReads []byte `protobuf:"bytes,5,opt,name=cross_channel_reads,json=crossChannelReads,proto3" json:"cross_channel_reads,omitempty"`
 }
 
 func (m *ChaincodeAction) Reset()                    { *m = ChaincodeAction{} }
@@ -183,45 +189,81 @@ func (m *ChaincodeAction) GetChaincodeId() *ChaincodeID {
 	return nil
 }
 
+// CrossChannelReadSets holds the read sets produced on other channels during
+// the execution of a chaincode invocation.
+type CrossChannelReadSets struct {
+	ReadSets []*CrossChannelReadSet `protobuf:"bytes,1,rep,name=read_sets,json=readSets" json:"read_sets,omitempty"`
+}
+
+func (m *CrossChannelReadSets) Reset()                    { *m = CrossChannelReadSets{} }
+func (m *CrossChannelReadSets) String() string            { return proto.CompactTextString(m) }
+func (*CrossChannelReadSets) ProtoMessage()               {}
+func (*CrossChannelReadSets) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{5} }
+
+func (m *CrossChannelReadSets) GetReadSets() []*CrossChannelReadSet {
+	if m != nil {
+		return m.ReadSets
+	}
+	return nil
+}
+
+// CrossChannelReadSet is the read set produced on the channel channel_id. The
+// results field is a serialized TxReadWriteSet that has no writes.
+type CrossChannelReadSet struct {
+	ChannelId string `protobuf:"bytes,1,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+	Results   []byte `protobuf:"bytes,2,opt,name=results,proto3" json:"results,omitempty"`
+}
+
+func (m *CrossChannelReadSet) Reset()                    { *m = CrossChannelReadSet{} }
+func (m *CrossChannelReadSet) String() string            { return proto.CompactTextString(m) }
+func (*CrossChannelReadSet) ProtoMessage()               {}
+func (*CrossChannelReadSet) Descriptor() ([]byte, []int) { return fileDescriptor7, []int{6} }
+
 func init() {
 	proto.RegisterType((*SignedProposal)(nil), "protos.SignedProposal")
 	proto.RegisterType((*Proposal)(nil), "protos.Proposal")
 	proto.RegisterType((*ChaincodeHeaderExtension)(nil), "protos.ChaincodeHeaderExtension")
 	proto.RegisterType((*ChaincodeProposalPayload)(nil), "protos.ChaincodeProposalPayload")
 	proto.RegisterType((*ChaincodeAction)(nil), "protos.ChaincodeAction")
+	proto.RegisterType((*CrossChannelReadSets)(nil), "protos.CrossChannelReadSets")
+	proto.RegisterType((*CrossChannelReadSet)(nil), "protos.CrossChannelReadSet")
 }
 
 func init() { proto.RegisterFile("peer/proposal.proto", fileDescriptor7) }
 
 var fileDescriptor7 = []byte{
-	// 452 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x8c, 0x53, 0x4d, 0x6f, 0xd3, 0x4c,
-	0x10, 0x96, 0x93, 0xf7, 0xed, 0xc7, 0x24, 0xf4, 0x63, 0x5b, 0x21, 0x2b, 0xea, 0xa1, 0xb2, 0x84,
-	0x54, 0x24, 0xb0, 0xa5, 0x20, 0x21, 0xc4, 0x05, 0x11, 0xa8, 0x44, 0x0f, 0x48, 0x95, 0x81, 0x1e,
-	0x7a, 0x09, 0x6b, 0x7b, 0x70, 0x56, 0x35, 0xbb, 0xab, 0xdd, 0x75, 0x84, 0x8f, 0xfc, 0x1c, 0x7e,
-	0x0a, 0xff, 0x0a, 0xd9, 0xfb, 0xd1, 0x94, 0x5c, 0x38, 0x25, 0x33, 0xf3, 0x3c, 0xcf, 0xcc, 0x3c,
-	0xb3, 0x86, 0x13, 0x89, 0xa8, 0x32, 0xa9, 0x84, 0x14, 0x9a, 0x36, 0xa9, 0x54, 0xc2, 0x08, 0xb2,
-	0x33, 0xfc, 0xe8, 0xd9, 0xe9, 0x50, 0x2c, 0x57, 0x94, 0xf1, 0x52, 0x54, 0x68, 0xab, 0xb3, 0xb3,
-	0x07, 0x94, 0xa5, 0x42, 0x2d, 0x05, 0xd7, 0xae, 0x9a, 0x7c, 0x81, 0x83, 0x4f, 0xac, 0xe6, 0x58,
-	0x5d, 0x3b, 0x00, 0x79, 0x02, 0x07, 0x01, 0x5c, 0x74, 0x06, 0x75, 0x1c, 0x9d, 0x47, 0x17, 0xd3,
-	0xfc, 0x91, 0xcf, 0x2e, 0xfa, 0x24, 0x39, 0x83, 0x7d, 0xcd, 0x6a, 0x4e, 0x4d, 0xab, 0x30, 0x1e,
-	0x0d, 0x88, 0xfb, 0x44, 0x72, 0x0b, 0x7b, 0x41, 0xf0, 0x31, 0xec, 0xac, 0x90, 0x56, 0xa8, 0x9c,
-	0x90, 0x8b, 0x48, 0x0c, 0xbb, 0x92, 0x76, 0x8d, 0xa0, 0x95, 0xe3, 0xfb, 0xb0, 0xd7, 0xc6, 0x1f,
-	0x06, 0xb9, 0x66, 0x82, 0xc7, 0x63, 0xab, 0x1d, 0x12, 0xc9, 0xcf, 0x08, 0xe2, 0x77, 0x7e, 0xc9,
-	0x0f, 0x83, 0xd6, 0xa5, 0x2f, 0x92, 0xe7, 0x40, 0x9c, 0xca, 0x72, 0xcd, 0x34, 0x2b, 0x58, 0xc3,
-	0x4c, 0xe7, 0x1a, 0x1f, 0xbb, 0xca, 0x4d, 0x28, 0x90, 0x97, 0x30, 0x0d, 0x7e, 0x2d, 0x99, 0x1d,
-	0x64, 0x32, 0x3f, 0xb1, 0xe6, 0xe8, 0x34, 0xb4, 0xb9, 0x7a, 0x9f, 0x4f, 0x02, 0xf0, 0xaa, 0x4a,
-	0x7e, 0x6f, 0xce, 0xe0, 0x37, 0xbd, 0x76, 0xe3, 0x9f, 0xc2, 0xff, 0x8c, 0xcb, 0xd6, 0xb8, 0xb6,
-	0x36, 0x20, 0x37, 0x30, 0xfd, 0xac, 0x28, 0xd7, 0x0c, 0xb9, 0xf9, 0x48, 0x65, 0x3c, 0x3a, 0x1f,
-	0x5f, 0x4c, 0xe6, 0xf3, 0xad, 0x56, 0x7f, 0xa9, 0xa5, 0x9b, 0xa4, 0x4b, 0x6e, 0x54, 0x97, 0x3f,
-	0xd0, 0x99, 0xbd, 0x81, 0xe3, 0x2d, 0x08, 0x39, 0x82, 0xf1, 0x1d, 0xda, 0xbd, 0xf7, 0xf3, 0xfe,
-	0x6f, 0x3f, 0xd4, 0x9a, 0x36, 0xad, 0xbf, 0x95, 0x0d, 0x5e, 0x8f, 0x5e, 0x45, 0xc9, 0xaf, 0x08,
-	0x0e, 0x43, 0xf7, 0xb7, 0xa5, 0xe9, 0x6d, 0x8c, 0x61, 0x57, 0xa1, 0x6e, 0x1b, 0xe3, 0xaf, 0xef,
-	0xc3, 0xfe, 0x9a, 0xb8, 0x46, 0x6e, 0xb4, 0x13, 0x72, 0x11, 0x79, 0x06, 0x7b, 0xfe, 0x69, 0x0d,
-	0x27, 0x9b, 0xcc, 0x8f, 0xfc, 0x6a, 0xb9, 0xcb, 0xe7, 0x01, 0xb1, 0xe5, 0xfb, 0x7f, 0xff, 0xe6,
-	0xfb, 0xe2, 0x2b, 0x24, 0x42, 0xd5, 0xe9, 0xaa, 0x93, 0xa8, 0x1a, 0xac, 0x6a, 0x54, 0xe9, 0x37,
-	0x5a, 0x28, 0x56, 0x7a, 0x66, 0xff, 0xd8, 0x17, 0x87, 0xf7, 0x1e, 0x96, 0x77, 0xb4, 0xc6, 0xdb,
-	0xa7, 0x35, 0x33, 0xab, 0xb6, 0x48, 0x4b, 0xf1, 0x3d, 0xdb, 0xe0, 0x66, 0x96, 0x9b, 0x59, 0x6e,
-	0xd6, 0x73, 0x0b, 0xfb, 0x31, 0xbd, 0xf8, 0x13, 0x00, 0x00, 0xff, 0xff, 0x12, 0x75, 0xb6, 0xaf,
-	0x6a, 0x03, 0x00, 0x00,
+	// 526 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x8c, 0x54, 0x4d, 0x8b, 0x13, 0x41,
+	0x10, 0x25, 0x89, 0xfb, 0x91, 0x4a, 0xdc, 0x8f, 0x4e, 0x90, 0x21, 0xae, 0xb0, 0x0c, 0x08, 0x2b,
+	0xe8, 0x04, 0x22, 0xc8, 0xe2, 0x45, 0x4c, 0x5c, 0x30, 0x07, 0x25, 0xcc, 0xea, 0x1e, 0xf6, 0x12,
+	0x3b, 0x33, 0xe5, 0xa4, 0xd9, 0xb1, 0x7b, 0xe8, 0xee, 0x04, 0xe7, 0xe8, 0xcf, 0xf3, 0x27, 0xf8,
+	0x6f, 0xa4, 0xa7, 0xbb, 0x67, 0x93, 0x8d, 0x07, 0x4f, 0x49, 0xd5, 0xab, 0xf7, 0xaa, 0xea, 0x55,
+	0x27, 0xd0, 0x2b, 0x10, 0xe5, 0xb0, 0x90, 0xa2, 0x10, 0x8a, 0xe6, 0x51, 0x21, 0x85, 0x16, 0x64,
+	0xbf, 0xfa, 0x50, 0x83, 0x7e, 0x05, 0x26, 0x4b, 0xca, 0x78, 0x22, 0x52, 0xb4, 0xe8, 0xe0, 0x6c,
+	0x8b, 0x32, 0x97, 0xa8, 0x0a, 0xc1, 0x95, 0x43, 0xc3, 0xaf, 0x70, 0x74, 0xcd, 0x32, 0x8e, 0xe9,
+	0xcc, 0x15, 0x90, 0xe7, 0x70, 0x54, 0x17, 0x2f, 0x4a, 0x8d, 0x2a, 0x68, 0x9c, 0x37, 0x2e, 0xba,
+	0xf1, 0x63, 0x9f, 0x1d, 0x9b, 0x24, 0x39, 0x83, 0xb6, 0x62, 0x19, 0xa7, 0x7a, 0x25, 0x31, 0x68,
+	0x56, 0x15, 0xf7, 0x89, 0xf0, 0x16, 0x0e, 0x6b, 0xc1, 0x27, 0xb0, 0xbf, 0x44, 0x9a, 0xa2, 0x74,
+	0x42, 0x2e, 0x22, 0x01, 0x1c, 0x14, 0xb4, 0xcc, 0x05, 0x4d, 0x1d, 0xdf, 0x87, 0x46, 0x1b, 0x7f,
+	0x6a, 0xe4, 0x8a, 0x09, 0x1e, 0xb4, 0xac, 0x76, 0x9d, 0x08, 0x7f, 0x35, 0x20, 0x98, 0xf8, 0x25,
+	0x3f, 0x56, 0x5a, 0x57, 0x1e, 0x24, 0xaf, 0x80, 0x38, 0x95, 0xf9, 0x9a, 0x29, 0xb6, 0x60, 0x39,
+	0xd3, 0xa5, 0x6b, 0x7c, 0xea, 0x90, 0x9b, 0x1a, 0x20, 0x6f, 0xa0, 0x5b, 0xfb, 0x35, 0x67, 0x76,
+	0x90, 0xce, 0xa8, 0x67, 0xcd, 0x51, 0x51, 0xdd, 0x66, 0xfa, 0x21, 0xee, 0xd4, 0x85, 0xd3, 0x34,
+	0xfc, 0xbd, 0x39, 0x83, 0xdf, 0x74, 0xe6, 0xc6, 0xef, 0xc3, 0x1e, 0xe3, 0xc5, 0x4a, 0xbb, 0xb6,
+	0x36, 0x20, 0x37, 0xd0, 0xfd, 0x22, 0x29, 0x57, 0x0c, 0xb9, 0xfe, 0x44, 0x8b, 0xa0, 0x79, 0xde,
+	0xba, 0xe8, 0x8c, 0x46, 0x3b, 0xad, 0x1e, 0xa8, 0x45, 0x9b, 0xa4, 0x2b, 0xae, 0x65, 0x19, 0x6f,
+	0xe9, 0x0c, 0xde, 0xc1, 0xe9, 0x4e, 0x09, 0x39, 0x81, 0xd6, 0x1d, 0xda, 0xbd, 0xdb, 0xb1, 0xf9,
+	0x6a, 0x86, 0x5a, 0xd3, 0x7c, 0xe5, 0x6f, 0x65, 0x83, 0xb7, 0xcd, 0xcb, 0x46, 0xf8, 0xa7, 0x01,
+	0xc7, 0x75, 0xf7, 0xf7, 0x89, 0x36, 0x36, 0x06, 0x70, 0x20, 0x51, 0xad, 0x72, 0xed, 0xaf, 0xef,
+	0x43, 0x73, 0x4d, 0x5c, 0x23, 0xd7, 0xca, 0x09, 0xb9, 0x88, 0xbc, 0x84, 0x43, 0xff, 0xb4, 0xaa,
+	0x93, 0x75, 0x46, 0x27, 0x7e, 0xb5, 0xd8, 0xe5, 0xe3, 0xba, 0x62, 0xc7, 0xf7, 0x47, 0xff, 0xe7,
+	0x3b, 0x89, 0xa0, 0x97, 0x48, 0xa1, 0xd4, 0x3c, 0x59, 0x52, 0xce, 0xd1, 0x3c, 0x67, 0x9a, 0xaa,
+	0x60, 0xcf, 0xde, 0xb7, 0x82, 0x26, 0x16, 0x89, 0x0d, 0x10, 0xce, 0xa0, 0x3f, 0x79, 0x90, 0xbc,
+	0x46, 0xad, 0xc8, 0x25, 0xb4, 0x0d, 0x73, 0xae, 0xb0, 0xda, 0xd0, 0x5c, 0xe2, 0x69, 0xdd, 0x7c,
+	0x97, 0x60, 0x26, 0xb7, 0xcc, 0xf0, 0x33, 0xf4, 0xfe, 0x51, 0x40, 0x9e, 0x01, 0xf8, 0x91, 0x58,
+	0xea, 0x7c, 0x6f, 0xbb, 0xcc, 0x34, 0xdd, 0xf4, 0xb3, 0xb9, 0xe5, 0xe7, 0xf8, 0x1b, 0x84, 0x42,
+	0x66, 0xd1, 0xb2, 0x2c, 0x50, 0xe6, 0x98, 0x66, 0x28, 0xa3, 0xef, 0x74, 0x21, 0x59, 0xe2, 0xc7,
+	0x31, 0x3f, 0xdf, 0xf1, 0xf1, 0xfd, 0xab, 0x48, 0xee, 0x68, 0x86, 0xb7, 0x2f, 0x32, 0xa6, 0x97,
+	0xab, 0x45, 0x94, 0x88, 0x1f, 0xc3, 0x0d, 0xee, 0xd0, 0x72, 0x87, 0x96, 0x3b, 0x34, 0xdc, 0x85,
+	0xfd, 0x7b, 0x78, 0xfd, 0x77, 0x00, 0x80, 0xaf, 0x6c, 0x0f, 0x3c, 0x04, 0x00, 0x00,
 }
@@ -49,6 +49,8 @@ type ChaincodeInfo struct {
 	// the name of the VSCC for this chaincode. This will be
 	// blank if the query is returning information about installed chaincodes.
 	Vscc string `protobuf:"bytes,6,opt,name=vscc" json:"vscc,omitempty"`
+	// the hash of the chaincode package
+	Id []byte `protobuf:"bytes,7,opt,name=id,proto3" json:"id,omitempty"`
 }
 
 func (m *ChaincodeInfo) Reset()                    { *m = ChaincodeInfo{} }
@@ -85,33 +87,59 @@ func (m *ChannelInfo) String() string            { return proto.CompactTextStrin
 func (*ChannelInfo) ProtoMessage()               {}
 func (*ChannelInfo) Descriptor() ([]byte, []int) { return fileDescriptor9, []int{3} }
 
+// CommitReadinessResponse returns, for each organization of a channel, whether
+// it approved the chaincode definition queried through the lifecycle system
+// chaincode
+type CommitReadinessResponse struct {
+	Approvals map[string]bool `protobuf:"bytes,1,rep,name=approvals" json:"approvals,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+}
+
+func (m *CommitReadinessResponse) Reset()                    { *m = CommitReadinessResponse{} }
+func (m *CommitReadinessResponse) String() string            { return proto.CompactTextString(m) }
+func (*CommitReadinessResponse) ProtoMessage()               {}
+func (*CommitReadinessResponse) Descriptor() ([]byte, []int) { return fileDescriptor9, []int{4} }
+
+func (m *CommitReadinessResponse) GetApprovals() map[string]bool {
+	if m != nil {
+		return m.Approvals
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*ChaincodeQueryResponse)(nil), "protos.ChaincodeQueryResponse")
 	proto.RegisterType((*ChaincodeInfo)(nil), "protos.ChaincodeInfo")
 	proto.RegisterType((*ChannelQueryResponse)(nil), "protos.ChannelQueryResponse")
 	proto.RegisterType((*ChannelInfo)(nil), "protos.ChannelInfo")
+	proto.RegisterType((*CommitReadinessResponse)(nil), "protos.CommitReadinessResponse")
 }
 
 func init() { proto.RegisterFile("peer/query.proto", fileDescriptor9) }
 
 var fileDescriptor9 = []byte{
-	// 284 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x54, 0x91, 0xdf, 0x4a, 0xc3, 0x30,
-	0x14, 0xc6, 0xa9, 0xfb, 0xa3, 0x3b, 0x43, 0x90, 0x38, 0x25, 0x37, 0xc2, 0xe8, 0xd5, 0x04, 0x69,
-	0x40, 0xf1, 0x05, 0xdc, 0x85, 0xec, 0x6a, 0xb8, 0x4b, 0x6f, 0xa4, 0x4d, 0xcf, 0xda, 0xc0, 0x96,
-	0xc4, 0xa4, 0x1d, 0xec, 0x29, 0x7c, 0x65, 0x39, 0xc9, 0x3a, 0xba, 0xab, 0x9e, 0xf3, 0xfb, 0x7e,
-	0xa1, 0x7c, 0x09, 0xdc, 0x59, 0x44, 0x27, 0x7e, 0x5b, 0x74, 0xc7, 0xcc, 0x3a, 0xd3, 0x18, 0x36,
-	0x0e, 0x1f, 0x9f, 0xae, 0xe1, 0x71, 0x59, 0xe7, 0x4a, 0x4b, 0x53, 0xe2, 0x17, 0xe5, 0x1b, 0xf4,
-	0xd6, 0x68, 0x8f, 0xec, 0x1d, 0x40, 0x76, 0x89, 0xe7, 0xc9, 0x7c, 0xb0, 0x98, 0xbe, 0x3e, 0xc4,
-	0xd3, 0x3e, 0x3b, 0x9f, 0x59, 0xe9, 0xad, 0xd9, 0xf4, 0xc4, 0xf4, 0x2f, 0x81, 0xdb, 0x8b, 0x94,
-	0x31, 0x18, 0xea, 0x7c, 0x8f, 0x3c, 0x99, 0x27, 0x8b, 0xc9, 0x26, 0xcc, 0x8c, 0xc3, 0xf5, 0x01,
-	0x9d, 0x57, 0x46, 0xf3, 0xab, 0x80, 0xbb, 0x95, 0x6c, 0x9b, 0x37, 0x35, 0x1f, 0x44, 0x9b, 0x66,
-	0x36, 0x83, 0x91, 0xd2, 0xb6, 0x6d, 0xf8, 0x30, 0xc0, 0xb8, 0x90, 0x89, 0x5e, 0x4a, 0x3e, 0x8a,
-	0x26, 0xcd, 0xc4, 0x0e, 0xc4, 0xc6, 0x91, 0xd1, 0x9c, 0x7e, 0xc2, 0x6c, 0x59, 0xe7, 0x5a, 0xe3,
-	0xee, 0xb2, 0xa0, 0x80, 0x1b, 0x19, 0x79, 0x57, 0xef, 0xbe, 0x57, 0x8f, 0x78, 0x28, 0x77, 0x96,
-	0xd2, 0x17, 0x98, 0xf6, 0x02, 0xf6, 0x14, 0x2e, 0x88, 0xd6, 0x1f, 0x55, 0x9e, 0xda, 0x4d, 0x4e,
-	0x64, 0x55, 0x7e, 0xac, 0x21, 0x35, 0xae, 0xca, 0xea, 0xa3, 0x45, 0xb7, 0xc3, 0xb2, 0x42, 0x97,
-	0x6d, 0xf3, 0xc2, 0x29, 0xd9, 0xfd, 0x84, 0xde, 0xe4, 0xfb, 0xb9, 0x52, 0x4d, 0xdd, 0x16, 0x99,
-	0x34, 0x7b, 0xd1, 0x53, 0x45, 0x54, 0x45, 0x54, 0x05, 0xa9, 0x45, 0x7c, 0xb2, 0xb7, 0xff, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0x56, 0xd1, 0xfe, 0x74, 0xcd, 0x01, 0x00, 0x00,
+	// 373 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x6c, 0x92, 0x4f, 0x8b, 0xdb, 0x30,
+	0x10, 0xc5, 0xb1, 0xf3, 0x7f, 0xd2, 0x86, 0xa0, 0xa6, 0xad, 0x28, 0x14, 0x82, 0x4f, 0x29, 0x14,
+	0x1b, 0x5a, 0x0a, 0xa5, 0xf4, 0xd2, 0x86, 0x52, 0x02, 0x85, 0x50, 0x1f, 0xf7, 0xb2, 0x28, 0xf6,
+	0x24, 0x16, 0x1b, 0x4b, 0x5a, 0xc9, 0x36, 0xf8, 0xd3, 0xec, 0x69, 0xbf, 0xe7, 0x22, 0x2b, 0xf6,
+	0x3a, 0xb0, 0x27, 0xcf, 0xbc, 0xf7, 0x1b, 0x4b, 0x6f, 0x10, 0x2c, 0x15, 0xa2, 0x8e, 0xee, 0x4b,
+	0xd4, 0x75, 0xa8, 0xb4, 0x2c, 0x24, 0x19, 0x37, 0x1f, 0x13, 0xec, 0xe1, 0xdd, 0x36, 0x63, 0x5c,
+	0x24, 0x32, 0xc5, 0xff, 0xd6, 0x8f, 0xd1, 0x28, 0x29, 0x0c, 0x92, 0x6f, 0x00, 0x49, 0xeb, 0x18,
+	0xea, 0xad, 0x07, 0x9b, 0xf9, 0x97, 0xb7, 0x6e, 0xda, 0x84, 0xdd, 0xcc, 0x4e, 0x1c, 0x65, 0xdc,
+	0x03, 0x83, 0x07, 0x0f, 0x5e, 0x5f, 0xb9, 0x84, 0xc0, 0x50, 0xb0, 0x1c, 0xa9, 0xb7, 0xf6, 0x36,
+	0xb3, 0xb8, 0xa9, 0x09, 0x85, 0x49, 0x85, 0xda, 0x70, 0x29, 0xa8, 0xdf, 0xc8, 0x6d, 0x6b, 0x69,
+	0xc5, 0x8a, 0x8c, 0x0e, 0x1c, 0x6d, 0x6b, 0xb2, 0x82, 0x11, 0x17, 0xaa, 0x2c, 0xe8, 0xb0, 0x11,
+	0x5d, 0x63, 0x49, 0x34, 0x49, 0x42, 0x47, 0x8e, 0xb4, 0xb5, 0xd5, 0x2a, 0xab, 0x8d, 0x9d, 0x66,
+	0x6b, 0xb2, 0x00, 0x9f, 0xa7, 0x74, 0xb2, 0xf6, 0x36, 0xaf, 0x62, 0x9f, 0xa7, 0xc1, 0x5f, 0x58,
+	0x6d, 0x33, 0x26, 0x04, 0x9e, 0xaf, 0x03, 0x47, 0x30, 0x4d, 0x9c, 0xde, 0xc6, 0x7d, 0xd3, 0x8b,
+	0x6b, 0xf5, 0x26, 0x6c, 0x07, 0x05, 0x9f, 0x61, 0xde, 0x33, 0xc8, 0xc7, 0x66, 0x61, 0xb6, 0xbd,
+	0xe5, 0xe9, 0x25, 0xed, 0xec, 0xa2, 0xec, 0xd2, 0xe0, 0xd1, 0x83, 0xf7, 0x5b, 0x99, 0xe7, 0xbc,
+	0x88, 0x91, 0xa5, 0x5c, 0xa0, 0x31, 0xdd, 0xd1, 0xff, 0x60, 0xc6, 0x94, 0xd2, 0xb2, 0x62, 0xdd,
+	0xd9, 0x61, 0x77, 0xf6, 0xcb, 0x33, 0xe1, 0xaf, 0x76, 0xe0, 0x8f, 0x28, 0x74, 0x1d, 0x3f, 0xff,
+	0xe0, 0xc3, 0x4f, 0x58, 0x5c, 0x9b, 0x64, 0x09, 0x83, 0x3b, 0xac, 0x2f, 0x77, 0xb2, 0xa5, 0x5d,
+	0x69, 0xc5, 0xce, 0x25, 0x36, 0xeb, 0x9f, 0xc6, 0xae, 0xf9, 0xe1, 0x7f, 0xf7, 0x7e, 0xef, 0x21,
+	0x90, 0xfa, 0x14, 0x66, 0xb5, 0x42, 0x7d, 0xc6, 0xf4, 0x84, 0x3a, 0x3c, 0xb2, 0x83, 0xe6, 0x49,
+	0x7b, 0x21, 0xfb, 0x96, 0x6e, 0x3e, 0x9d, 0x78, 0x91, 0x95, 0x87, 0x30, 0x91, 0x79, 0xd4, 0x43,
+	0x23, 0x87, 0x46, 0x0e, 0x8d, 0x2c, 0x7a, 0x70, 0x4f, 0xed, 0xeb, 0xd3, 0x00, 0x57, 0xaf, 0x6a,
+	0xea, 0x85, 0x02, 0x00, 0x00,
 }
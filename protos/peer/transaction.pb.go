@@ -36,6 +36,7 @@ const (
 	TxValidationCode_MARSHAL_TX_ERROR             TxValidationCode = 15
 	TxValidationCode_NIL_TXACTION                 TxValidationCode = 16
 	TxValidationCode_EXPIRED_CHAINCODE            TxValidationCode = 17
+	TxValidationCode_NOT_VALIDATED                TxValidationCode = 254
 	TxValidationCode_INVALID_OTHER_REASON         TxValidationCode = 255
 )
 
@@ -58,6 +59,7 @@ var TxValidationCode_name = map[int32]string{
 	15:  "MARSHAL_TX_ERROR",
 	16:  "NIL_TXACTION",
 	17:  "EXPIRED_CHAINCODE",
+	254: "NOT_VALIDATED",
 	255: "INVALID_OTHER_REASON",
 }
 var TxValidationCode_value = map[string]int32{
@@ -79,6 +81,7 @@ var TxValidationCode_value = map[string]int32{
 	"MARSHAL_TX_ERROR":             15,
 	"NIL_TXACTION":                 16,
 	"EXPIRED_CHAINCODE":            17,
+	"NOT_VALIDATED":                254,
 	"INVALID_OTHER_REASON":         255,
 }
 
@@ -87,6 +90,28 @@ func (x TxValidationCode) String() string {
 }
 func (TxValidationCode) EnumDescriptor() ([]byte, []int) { return fileDescriptor11, []int{0} }
 
+// MetaDataKeys are the names of the entries of the metadata a chaincode can
+// associate with a key of its state
+type MetaDataKeys int32
+
+const (
+	// VALIDATION_PARAMETER is the key-level endorsement policy of the key, a
+	// serialized common.SignaturePolicyEnvelope
+	MetaDataKeys_VALIDATION_PARAMETER MetaDataKeys = 0
+)
+
+var MetaDataKeys_name = map[int32]string{
+	0: "VALIDATION_PARAMETER",
+}
+var MetaDataKeys_value = map[string]int32{
+	"VALIDATION_PARAMETER": 0,
+}
+
+func (x MetaDataKeys) String() string {
+	return proto.EnumName(MetaDataKeys_name, int32(x))
+}
+func (MetaDataKeys) EnumDescriptor() ([]byte, []int) { return fileDescriptor11, []int{1} }
+
 // This message is necessary to facilitate the verification of the signature
 // (in the signature field) over the bytes of the transaction (in the
 // transactionBytes field).
@@ -229,6 +254,25 @@ func (m *ChaincodeEndorsedAction) GetEndorsements() []*Endorsement {
 	return nil
 }
 
+// KeyEndorsementPolicies carries the key-level endorsement policies in effect
+// before a transaction for the keys the transaction writes. Keys without a
+// key-level policy are absent. The committer passes it to the VSCC
+type KeyEndorsementPolicies struct {
+	Policies map[string][]byte `protobuf:"bytes,1,rep,name=policies" json:"policies,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *KeyEndorsementPolicies) Reset()                    { *m = KeyEndorsementPolicies{} }
+func (m *KeyEndorsementPolicies) String() string            { return proto.CompactTextString(m) }
+func (*KeyEndorsementPolicies) ProtoMessage()               {}
+func (*KeyEndorsementPolicies) Descriptor() ([]byte, []int) { return fileDescriptor11, []int{6} }
+
+func (m *KeyEndorsementPolicies) GetPolicies() map[string][]byte {
+	if m != nil {
+		return m.Policies
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*SignedTransaction)(nil), "protos.SignedTransaction")
 	proto.RegisterType((*ProcessedTransaction)(nil), "protos.ProcessedTransaction")
@@ -236,59 +280,68 @@ func init() {
 	proto.RegisterType((*TransactionAction)(nil), "protos.TransactionAction")
 	proto.RegisterType((*ChaincodeActionPayload)(nil), "protos.ChaincodeActionPayload")
 	proto.RegisterType((*ChaincodeEndorsedAction)(nil), "protos.ChaincodeEndorsedAction")
+	proto.RegisterType((*KeyEndorsementPolicies)(nil), "protos.KeyEndorsementPolicies")
 	proto.RegisterEnum("protos.TxValidationCode", TxValidationCode_name, TxValidationCode_value)
+	proto.RegisterEnum("protos.MetaDataKeys", MetaDataKeys_name, MetaDataKeys_value)
 }
 
 func init() { proto.RegisterFile("peer/transaction.proto", fileDescriptor11) }
 
 var fileDescriptor11 = []byte{
-	// 761 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x74, 0x54, 0xc1, 0x6e, 0xe3, 0x36,
-	0x14, 0xac, 0x93, 0x26, 0x69, 0x9e, 0xd3, 0x84, 0x66, 0xb2, 0x5e, 0xc7, 0x08, 0xba, 0x0b, 0x1f,
-	0x8a, 0x6d, 0x0b, 0xd8, 0x40, 0xf6, 0x50, 0xa0, 0xe8, 0x85, 0x96, 0x98, 0x58, 0xa8, 0x4c, 0x0a,
-	0x34, 0x9d, 0x26, 0x3d, 0x94, 0x90, 0x2d, 0xae, 0x63, 0xd4, 0x16, 0x05, 0x49, 0x59, 0x34, 0xd7,
-	0x7e, 0x40, 0xfb, 0x7d, 0xfd, 0x9a, 0x16, 0x12, 0xa5, 0xc4, 0xce, 0xb6, 0x17, 0xcb, 0x9c, 0x19,
-	0xbe, 0x99, 0xf7, 0x44, 0x11, 0xda, 0x89, 0xd6, 0xe9, 0x20, 0x4f, 0xc3, 0x38, 0x0b, 0xe7, 0xf9,
-	0xd2, 0xc4, 0xfd, 0x24, 0x35, 0xb9, 0xc1, 0xfb, 0xe5, 0x23, 0xeb, 0xbe, 0x59, 0x18, 0xb3, 0x58,
-	0xe9, 0x41, 0xb9, 0x9c, 0x3d, 0x7c, 0x18, 0xe4, 0xcb, 0xb5, 0xce, 0xf2, 0x70, 0x9d, 0x58, 0x61,
-	0xf7, 0xa2, 0x2c, 0x90, 0xa4, 0x26, 0x31, 0x59, 0xb8, 0x52, 0xa9, 0xce, 0x12, 0x13, 0x67, 0xba,
-	0x62, 0x4f, 0xe7, 0x66, 0xbd, 0x36, 0xf1, 0xc0, 0x3e, 0x2c, 0xd8, 0xfb, 0x15, 0x5a, 0x93, 0xe5,
-	0x22, 0xd6, 0x91, 0x7c, 0xb6, 0xc5, 0xdf, 0x41, 0x6b, 0x23, 0x85, 0x9a, 0x3d, 0xe6, 0x3a, 0xeb,
-	0x34, 0xde, 0x36, 0xde, 0x1d, 0x09, 0xb4, 0x41, 0x0c, 0x0b, 0x1c, 0x5f, 0xc0, 0x61, 0xb6, 0x5c,
-	0xc4, 0x61, 0xfe, 0x90, 0xea, 0xce, 0x4e, 0x29, 0x7a, 0x06, 0x7a, 0x7f, 0x34, 0xe0, 0x2c, 0x48,
-	0xcd, 0x5c, 0x67, 0xd9, 0xb6, 0xc7, 0x10, 0x4e, 0x37, 0x4a, 0xd1, 0xf8, 0xa3, 0x5e, 0x99, 0x44,
-	0x97, 0x2e, 0xcd, 0x4b, 0xd4, 0xaf, 0x42, 0xd6, 0xb8, 0xf8, 0x2f, 0x31, 0xfe, 0x1a, 0x8e, 0x3f,
-	0x86, 0xab, 0x65, 0x14, 0x16, 0xa8, 0x63, 0x22, 0xeb, 0xbf, 0x27, 0x5e, 0xa0, 0xbd, 0x21, 0x34,
-	0x37, 0xad, 0xdf, 0xc3, 0x81, 0xfd, 0x57, 0x34, 0xb5, 0xfb, 0xae, 0x79, 0x79, 0x6e, 0x87, 0x91,
-	0xf5, 0x37, 0x54, 0xa4, 0xfc, 0x15, 0xb5, 0xb2, 0x47, 0xa1, 0xf5, 0x09, 0x8b, 0xdb, 0xb0, 0x7f,
-	0xaf, 0xc3, 0x48, 0xa7, 0xd5, 0x74, 0xaa, 0x15, 0xee, 0xc0, 0x41, 0x12, 0x3e, 0xae, 0x4c, 0x18,
-	0x55, 0x13, 0xa9, 0x97, 0xbd, 0xbf, 0x1a, 0xd0, 0x76, 0xee, 0xc3, 0x65, 0x3c, 0x37, 0x91, 0xb6,
-	0x55, 0x02, 0x4b, 0xe1, 0x1f, 0xa1, 0x3b, 0xaf, 0x19, 0xf5, 0xf4, 0x12, 0xeb, 0x3a, 0xd6, 0xa0,
-	0xf3, 0xa4, 0x08, 0x2a, 0x41, 0xbd, 0xfb, 0x7b, 0xd8, 0xb7, 0xd1, 0x4a, 0xc7, 0xe6, 0xe5, 0x9b,
-	0xba, 0xa7, 0x27, 0x37, 0x1a, 0x47, 0x26, 0xcd, 0x74, 0x54, 0x75, 0x56, 0xc9, 0x7b, 0x7f, 0x36,
-	0xe0, 0xf5, 0xff, 0x68, 0xf0, 0x0f, 0x70, 0xfe, 0xc9, 0x69, 0x7a, 0x91, 0xe8, 0x75, 0x2d, 0x10,
-	0x15, 0xff, 0x1c, 0xe8, 0x48, 0xdb, 0x6a, 0x6b, 0x1d, 0xe7, 0x59, 0x67, 0xa7, 0x1c, 0xf5, 0x69,
-	0x1d, 0x8b, 0x3e, 0x73, 0x62, 0x4b, 0xf8, 0xed, 0xdf, 0xbb, 0x80, 0xe4, 0xef, 0x37, 0x5b, 0xaf,
-	0x10, 0x1f, 0xc2, 0xde, 0x0d, 0xf1, 0x3d, 0x17, 0x7d, 0x86, 0x11, 0x1c, 0x31, 0xcf, 0x57, 0x94,
-	0xdd, 0x50, 0x9f, 0x07, 0x14, 0x35, 0xf0, 0x09, 0x34, 0x87, 0xc4, 0x55, 0x01, 0xb9, 0xf3, 0x39,
-	0x71, 0xd1, 0x0e, 0x7e, 0x05, 0xad, 0x02, 0x70, 0xf8, 0x78, 0xcc, 0x99, 0x1a, 0x51, 0xe2, 0x52,
-	0x81, 0x76, 0xf1, 0x39, 0xbc, 0x2a, 0x61, 0x41, 0x89, 0xe4, 0x42, 0x4d, 0xbc, 0x6b, 0x46, 0xe4,
-	0x54, 0x50, 0xf4, 0x39, 0x7e, 0x0b, 0x17, 0x1e, 0x2b, 0x1d, 0x14, 0x65, 0x2e, 0x17, 0x13, 0x2a,
-	0x94, 0x14, 0x84, 0x4d, 0x88, 0x23, 0x3d, 0xce, 0xd0, 0x1e, 0xfe, 0x0a, 0xba, 0xb5, 0xc2, 0xe1,
-	0xec, 0xca, 0xbb, 0xde, 0xe2, 0xf7, 0x71, 0x17, 0xda, 0x53, 0x36, 0x99, 0x06, 0x01, 0x17, 0x92,
-	0xba, 0x4a, 0xde, 0x3e, 0xe5, 0x39, 0xa8, 0xf3, 0x04, 0x82, 0x07, 0x7c, 0x42, 0x7c, 0x25, 0x6f,
-	0x3d, 0x17, 0x7d, 0x81, 0x31, 0x1c, 0xbb, 0xd3, 0xc0, 0xf7, 0x1c, 0x22, 0xa9, 0xc5, 0x0e, 0x0b,
-	0x9b, 0x2a, 0xc0, 0x98, 0x32, 0xa9, 0x02, 0xee, 0x7b, 0xce, 0x9d, 0xba, 0x22, 0x9e, 0x5f, 0x04,
-	0x05, 0xdc, 0x06, 0x3c, 0xbe, 0x71, 0x1c, 0x25, 0x28, 0xb1, 0x41, 0x7c, 0xcf, 0x91, 0xa8, 0x59,
-	0xf4, 0x16, 0x8c, 0x08, 0x93, 0x7c, 0xfc, 0x82, 0x3a, 0xc2, 0xa7, 0x70, 0x32, 0x65, 0x3f, 0x31,
-	0xfe, 0x33, 0x2b, 0x52, 0xc9, 0xbb, 0x80, 0xa2, 0x2f, 0x8b, 0xb8, 0x92, 0x88, 0x6b, 0x2a, 0x95,
-	0x33, 0x22, 0x1e, 0x53, 0x8c, 0x4b, 0x75, 0xc5, 0xa7, 0xcc, 0x45, 0xc7, 0xf8, 0x0c, 0xd0, 0x98,
-	0x88, 0xc9, 0xa8, 0x4c, 0xaa, 0xa8, 0x10, 0x5c, 0xa0, 0x93, 0x7a, 0xee, 0xf2, 0xb6, 0x6a, 0x19,
-	0x15, 0x6d, 0xd1, 0xdb, 0xc0, 0x13, 0xd4, 0xb5, 0x45, 0x1c, 0xee, 0x52, 0xd4, 0xc2, 0xe7, 0x70,
-	0x56, 0x4f, 0x8a, 0xcb, 0x11, 0x15, 0x45, 0xa0, 0x09, 0x67, 0xe8, 0x9f, 0xc6, 0x70, 0x0e, 0x3d,
-	0x93, 0x2e, 0xfa, 0xf7, 0x8f, 0x89, 0x4e, 0x57, 0x3a, 0x5a, 0xe8, 0xb4, 0xff, 0x21, 0x9c, 0xa5,
-	0xcb, 0x79, 0x7d, 0x2c, 0x8a, 0x1b, 0x6c, 0x88, 0x37, 0xbe, 0xb4, 0x20, 0x9c, 0xff, 0x16, 0x2e,
-	0xf4, 0x2f, 0xdf, 0x2c, 0x96, 0xf9, 0xfd, 0xc3, 0xac, 0xb8, 0x18, 0x06, 0x1b, 0xdb, 0x07, 0x76,
-	0xbb, 0xbd, 0x13, 0xb3, 0x41, 0xb1, 0x7d, 0x66, 0xef, 0xcb, 0xf7, 0xff, 0x06, 0x00, 0x00, 0xff,
-	0xff, 0xc1, 0x81, 0xd7, 0x0c, 0x50, 0x05, 0x00, 0x00,
+	// 872 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x74, 0x55, 0xcd, 0x6e, 0xe3, 0x36,
+	0x17, 0x1d, 0x25, 0x5f, 0x92, 0xc9, 0x75, 0x7e, 0x68, 0xc6, 0xe3, 0x71, 0x8c, 0xe0, 0x9b, 0x81,
+	0x17, 0x45, 0x3a, 0x2d, 0x6c, 0x20, 0xb3, 0x68, 0x31, 0xed, 0x86, 0x96, 0x98, 0x58, 0x88, 0x4d,
+	0x0a, 0x34, 0x9d, 0x26, 0x5d, 0x94, 0x90, 0x6d, 0x8e, 0x23, 0x8c, 0x2d, 0x09, 0x92, 0x12, 0xd4,
+	0xdb, 0x3e, 0x40, 0xfb, 0x08, 0x7d, 0xc2, 0xbe, 0x42, 0x5b, 0xe8, 0xcf, 0xb1, 0x33, 0xd3, 0x8d,
+	0x45, 0xde, 0x73, 0xee, 0x3d, 0xe7, 0x5e, 0x12, 0x34, 0xd4, 0x43, 0xad, 0xa3, 0x4e, 0x12, 0xb9,
+	0x7e, 0xec, 0x4e, 0x12, 0x2f, 0xf0, 0xdb, 0x61, 0x14, 0x24, 0x01, 0xde, 0xcd, 0x3e, 0x71, 0xf3,
+	0xcd, 0x2c, 0x08, 0x66, 0x73, 0xdd, 0xc9, 0xb6, 0xe3, 0x87, 0x8f, 0x9d, 0xc4, 0x5b, 0xe8, 0x38,
+	0x71, 0x17, 0x61, 0x4e, 0x6c, 0x9e, 0x65, 0x05, 0xc2, 0x28, 0x08, 0x83, 0xd8, 0x9d, 0xab, 0x48,
+	0xc7, 0x61, 0xe0, 0xc7, 0xba, 0x40, 0x4f, 0x26, 0xc1, 0x62, 0x11, 0xf8, 0x9d, 0xfc, 0x93, 0x07,
+	0x5b, 0xbf, 0x40, 0x75, 0xe8, 0xcd, 0x7c, 0x3d, 0x95, 0x4f, 0xb2, 0xf8, 0x1b, 0xa8, 0xae, 0xb9,
+	0x50, 0xe3, 0x65, 0xa2, 0xe3, 0x86, 0xf1, 0xd6, 0x38, 0x3f, 0x10, 0x68, 0x0d, 0xe8, 0xa6, 0x71,
+	0x7c, 0x06, 0xfb, 0xb1, 0x37, 0xf3, 0xdd, 0xe4, 0x21, 0xd2, 0x8d, 0xad, 0x8c, 0xf4, 0x14, 0x68,
+	0xfd, 0x66, 0x40, 0xcd, 0x89, 0x82, 0x89, 0x8e, 0xe3, 0x4d, 0x8d, 0x2e, 0x9c, 0xac, 0x95, 0xa2,
+	0xfe, 0xa3, 0x9e, 0x07, 0xa1, 0xce, 0x54, 0x2a, 0x17, 0xa8, 0x5d, 0x98, 0x2c, 0xe3, 0xe2, 0x4b,
+	0x64, 0xfc, 0x15, 0x1c, 0x3d, 0xba, 0x73, 0x6f, 0xea, 0xa6, 0x51, 0x33, 0x98, 0xe6, 0xfa, 0x3b,
+	0xe2, 0x59, 0xb4, 0xd5, 0x85, 0xca, 0xba, 0xf4, 0x7b, 0xd8, 0xcb, 0x57, 0x69, 0x53, 0xdb, 0xe7,
+	0x95, 0x8b, 0xd3, 0x7c, 0x18, 0x71, 0x7b, 0x8d, 0x45, 0xb2, 0x5f, 0x51, 0x32, 0x5b, 0x14, 0xaa,
+	0x9f, 0xa1, 0xb8, 0x0e, 0xbb, 0xf7, 0xda, 0x9d, 0xea, 0xa8, 0x98, 0x4e, 0xb1, 0xc3, 0x0d, 0xd8,
+	0x0b, 0xdd, 0xe5, 0x3c, 0x70, 0xa7, 0xc5, 0x44, 0xca, 0x6d, 0xeb, 0x0f, 0x03, 0xea, 0xe6, 0xbd,
+	0xeb, 0xf9, 0x93, 0x60, 0xaa, 0xf3, 0x2a, 0x4e, 0x0e, 0xe1, 0x1f, 0xa1, 0x39, 0x29, 0x11, 0xb5,
+	0x3a, 0xc4, 0xb2, 0x4e, 0x2e, 0xd0, 0x58, 0x31, 0x9c, 0x82, 0x50, 0x66, 0x7f, 0x07, 0xbb, 0xb9,
+	0xb5, 0x4c, 0xb1, 0x72, 0xf1, 0xa6, 0xec, 0x69, 0xa5, 0x46, 0xfd, 0x69, 0x10, 0xc5, 0x7a, 0x5a,
+	0x74, 0x56, 0xd0, 0x5b, 0xbf, 0x1b, 0xf0, 0xfa, 0x3f, 0x38, 0xf8, 0x03, 0x9c, 0x7e, 0x76, 0x9b,
+	0x9e, 0x39, 0x7a, 0x5d, 0x12, 0x44, 0x81, 0x3f, 0x19, 0x3a, 0xd0, 0x79, 0xb5, 0x85, 0xf6, 0x93,
+	0xb8, 0xb1, 0x95, 0x8d, 0xfa, 0xa4, 0xb4, 0x45, 0x9f, 0x30, 0xb1, 0x41, 0x6c, 0xfd, 0x69, 0x40,
+	0xfd, 0x5a, 0x2f, 0xd7, 0x08, 0x4e, 0x30, 0xf7, 0x26, 0x9e, 0x8e, 0x71, 0x0f, 0x5e, 0x86, 0xc5,
+	0xba, 0x38, 0xba, 0x6f, 0xcb, 0x7a, 0x5f, 0xce, 0x68, 0x97, 0x0b, 0xea, 0x27, 0xd1, 0x52, 0xac,
+	0xb2, 0x9b, 0x3f, 0xc0, 0xe1, 0x06, 0x84, 0x11, 0x6c, 0x7f, 0xd2, 0xcb, 0xac, 0xa9, 0x7d, 0x91,
+	0x2e, 0x71, 0x0d, 0x76, 0x1e, 0xdd, 0xf9, 0x43, 0x79, 0xa9, 0xf3, 0xcd, 0x87, 0xad, 0xef, 0x8d,
+	0x77, 0x7f, 0x6d, 0x03, 0x92, 0xbf, 0xde, 0x6c, 0x5c, 0x32, 0xbc, 0x0f, 0x3b, 0x37, 0xa4, 0x6f,
+	0x5b, 0xe8, 0x05, 0x46, 0x70, 0xc0, 0xec, 0xbe, 0xa2, 0xec, 0x86, 0xf6, 0xb9, 0x43, 0x91, 0x81,
+	0x8f, 0xa1, 0xd2, 0x25, 0x96, 0x72, 0xc8, 0x5d, 0x9f, 0x13, 0x0b, 0x6d, 0xe1, 0x57, 0x50, 0x4d,
+	0x03, 0x26, 0x1f, 0x0c, 0x38, 0x53, 0x3d, 0x4a, 0x2c, 0x2a, 0xd0, 0x36, 0x3e, 0x85, 0x57, 0x59,
+	0x58, 0x50, 0x22, 0xb9, 0x50, 0x43, 0xfb, 0x8a, 0x11, 0x39, 0x12, 0x14, 0xfd, 0x0f, 0xbf, 0x85,
+	0x33, 0x9b, 0x65, 0x0a, 0x8a, 0x32, 0x8b, 0x8b, 0x21, 0x15, 0x4a, 0x0a, 0xc2, 0x86, 0xc4, 0x94,
+	0x36, 0x67, 0x68, 0x07, 0xff, 0x1f, 0x9a, 0x25, 0xc3, 0xe4, 0xec, 0xd2, 0xbe, 0xda, 0xc0, 0x77,
+	0x71, 0x13, 0xea, 0x23, 0x36, 0x1c, 0x39, 0x0e, 0x17, 0x92, 0x5a, 0x4a, 0xde, 0xae, 0xfc, 0xec,
+	0x95, 0x7e, 0x1c, 0xc1, 0x1d, 0x3e, 0x24, 0x7d, 0x25, 0x6f, 0x6d, 0x0b, 0xbd, 0xc4, 0x18, 0x8e,
+	0xac, 0x91, 0xd3, 0xb7, 0x4d, 0x22, 0x69, 0x1e, 0xdb, 0x4f, 0x65, 0x0a, 0x03, 0x03, 0xca, 0xa4,
+	0x72, 0x78, 0xdf, 0x36, 0xef, 0xd4, 0x25, 0xb1, 0xfb, 0xa9, 0x51, 0xc0, 0x75, 0xc0, 0x83, 0x1b,
+	0xd3, 0x54, 0x82, 0x92, 0xdc, 0x48, 0xdf, 0x36, 0x25, 0xaa, 0xa4, 0xbd, 0x39, 0x3d, 0xc2, 0x24,
+	0x1f, 0x3c, 0x83, 0x0e, 0xf0, 0x09, 0x1c, 0x8f, 0xd8, 0x35, 0xe3, 0x3f, 0xb1, 0xd4, 0x95, 0xbc,
+	0x73, 0x28, 0x3a, 0x4c, 0xed, 0x4a, 0x22, 0xae, 0xa8, 0x54, 0x66, 0x8f, 0xd8, 0x4c, 0x31, 0x2e,
+	0xd5, 0x25, 0x1f, 0x31, 0x0b, 0x1d, 0xe1, 0x1a, 0xa0, 0x01, 0x11, 0xc3, 0x5e, 0xe6, 0x54, 0x51,
+	0x21, 0xb8, 0x40, 0xc7, 0xe5, 0xdc, 0xe5, 0x6d, 0xd1, 0x32, 0x4a, 0xdb, 0xa2, 0xb7, 0x8e, 0x2d,
+	0xa8, 0x95, 0x17, 0x31, 0xb9, 0x45, 0x51, 0x15, 0x63, 0x38, 0x4c, 0xab, 0x65, 0xb3, 0x22, 0x92,
+	0x5a, 0xe8, 0x6f, 0x03, 0x9f, 0x42, 0xad, 0x9c, 0x1e, 0x97, 0x3d, 0x2a, 0x52, 0x93, 0x43, 0xce,
+	0xd0, 0x3f, 0xc6, 0xbb, 0x73, 0x38, 0x18, 0xe8, 0xc4, 0xb5, 0xdc, 0xc4, 0xbd, 0xd6, 0xcb, 0x18,
+	0x37, 0xa0, 0x56, 0xa4, 0xda, 0x9c, 0x29, 0x87, 0x08, 0x32, 0xa0, 0x92, 0x0a, 0xf4, 0xa2, 0x3b,
+	0x81, 0x56, 0x10, 0xcd, 0xda, 0xf7, 0xcb, 0x50, 0x47, 0x73, 0x3d, 0x9d, 0xe9, 0xa8, 0xfd, 0xd1,
+	0x1d, 0x47, 0xde, 0xa4, 0xbc, 0xa6, 0xe9, 0x0b, 0xdd, 0xc5, 0x6b, 0x2f, 0x89, 0xe3, 0x4e, 0x3e,
+	0xb9, 0x33, 0xfd, 0xf3, 0xd7, 0x33, 0x2f, 0xb9, 0x7f, 0x18, 0xa7, 0x0f, 0x5f, 0x67, 0x2d, 0xbd,
+	0x93, 0xa7, 0xe7, 0x6f, 0x7e, 0xdc, 0x49, 0xd3, 0xc7, 0xf9, 0xff, 0xc1, 0xfb, 0x7f, 0x07, 0x00,
+	0x12, 0xc0, 0x98, 0x2f, 0x30, 0x06, 0x00, 0x00,
 }
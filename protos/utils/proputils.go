@@ -227,6 +227,44 @@ func GetChaincodeEvents(eBytes []byte) (*peer.ChaincodeEvent, error) {
 	return chaincodeEvent, nil
 }
 
+// GetChaincodeEventList gets all the ChaincodeEvents, in the order they were
+// set, given chaincode event bytes holding either a single ChaincodeEvent or
+// a ChaincodeEvents message
+func GetChaincodeEventList(eBytes []byte) ([]*peer.ChaincodeEvent, error) {
+	if len(eBytes) == 0 {
+		return nil, nil
+	}
+
+	chaincodeEvents := &peer.ChaincodeEvents{}
+	err := proto.Unmarshal(eBytes, chaincodeEvents)
+	if err != nil {
+		return nil, err
+	}
+	if len(chaincodeEvents.Events) > 0 {
+		return chaincodeEvents.Events, nil
+	}
+
+	chaincodeEvent, err := GetChaincodeEvents(eBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*peer.ChaincodeEvent{chaincodeEvent}, nil
+}
+
+// GetCrossChannelReadSets gets the read sets done on other channels given the
+// cross_channel_reads bytes of a ChaincodeAction. It returns an empty
+// CrossChannelReadSets if no other channel was read
+func GetCrossChannelReadSets(crossChannelReads []byte) (*peer.CrossChannelReadSets, error) {
+	readSets := &peer.CrossChannelReadSets{}
+	err := proto.Unmarshal(crossChannelReads, readSets)
+	if err != nil {
+		return nil, err
+	}
+
+	return readSets, nil
+}
+
 // GetProposalResponsePayload gets the proposal response payload
 func GetProposalResponsePayload(prpBytes []byte) (*peer.ProposalResponsePayload, error) {
 	prp := &peer.ProposalResponsePayload{}
@@ -383,7 +421,11 @@ func CreateChaincodeProposalWithTxIDNonceAndTransient(txid string, typ common.He
 
 // GetBytesProposalResponsePayload gets proposal response payload
 func GetBytesProposalResponsePayload(hash []byte, response *peer.Response, result []byte, event []byte, ccid *peer.ChaincodeID) ([]byte, error) {
-	cAct := &peer.ChaincodeAction{Events: event, Results: result, Response: response, ChaincodeId: ccid}
+	return getBytesProposalResponsePayload(hash, response, result, event, nil, ccid)
+}
+
+func getBytesProposalResponsePayload(hash []byte, response *peer.Response, result []byte, event []byte, crossChannelReads []byte, ccid *peer.ChaincodeID) ([]byte, error) {
+	cAct := &peer.ChaincodeAction{Events: event, Results: result, Response: response, ChaincodeId: ccid, CrossChannelReads: crossChannelReads}
 	cActBytes, err := proto.Marshal(cAct)
 	if err != nil {
 		return nil, err
@@ -428,6 +470,26 @@ func GetBytesChaincodeEvent(event *peer.ChaincodeEvent) ([]byte, error) {
 	return eventBytes, nil
 }
 
+// GetBytesChaincodeEvents gets the bytes of a list of ChaincodeEvents. A
+// single event is marshalled as a plain ChaincodeEvent so that consumers
+// which only know about one event per transaction keep working; nil is
+// returned when there are no events
+func GetBytesChaincodeEvents(events []*peer.ChaincodeEvent) ([]byte, error) {
+	switch len(events) {
+	case 0:
+		return nil, nil
+	case 1:
+		return GetBytesChaincodeEvent(events[0])
+	}
+
+	eventBytes, err := proto.Marshal(&peer.ChaincodeEvents{Events: events})
+	if err != nil {
+		return nil, err
+	}
+
+	return eventBytes, nil
+}
+
 // GetBytesChaincodeActionPayload get the bytes of ChaincodeActionPayload from the message
 func GetBytesChaincodeActionPayload(cap *peer.ChaincodeActionPayload) ([]byte, error) {
 	capBytes, err := proto.Marshal(cap)
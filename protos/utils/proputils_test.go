@@ -244,6 +244,44 @@ func TestProposalResponse(t *testing.T) {
 	}
 }
 
+func TestChaincodeEventList(t *testing.T) {
+	// no events
+	eBytes, err := utils.GetBytesChaincodeEvents(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, eBytes)
+	events, err := utils.GetChaincodeEventList(eBytes)
+	assert.NoError(t, err)
+	assert.Len(t, events, 0)
+
+	// a single event keeps the legacy encoding
+	single := &pb.ChaincodeEvent{ChaincodeId: "ccid", TxId: "txid", EventName: "e1", Payload: []byte("p1")}
+	eBytes, err = utils.GetBytesChaincodeEvents([]*pb.ChaincodeEvent{single})
+	assert.NoError(t, err)
+	legacy, err := utils.GetChaincodeEvents(eBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, "e1", legacy.EventName)
+	events, err = utils.GetChaincodeEventList(eBytes)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "e1", events[0].EventName)
+	assert.Equal(t, []byte("p1"), events[0].Payload)
+
+	// several events are returned in order
+	second := &pb.ChaincodeEvent{ChaincodeId: "ccid", TxId: "txid", EventName: "e2", Payload: []byte("p2")}
+	eBytes, err = utils.GetBytesChaincodeEvents([]*pb.ChaincodeEvent{single, second})
+	assert.NoError(t, err)
+	events, err = utils.GetChaincodeEventList(eBytes)
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, "e1", events[0].EventName)
+	assert.Equal(t, "e2", events[1].EventName)
+	assert.Equal(t, []byte("p2"), events[1].Payload)
+
+	// garbage
+	_, err = utils.GetChaincodeEventList([]byte("garbage"))
+	assert.Error(t, err)
+}
+
 func TestEnvelope(t *testing.T) {
 	// create a proposal from a ChaincodeInvocationSpec
 	prop, _, err := utils.CreateChaincodeProposal(common.HeaderType_ENDORSER_TRANSACTION, util.GetTestChainID(), createCIS(), signerSerialized)
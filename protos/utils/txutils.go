@@ -211,6 +211,13 @@ func CreateSignedTx(proposal *peer.Proposal, signer msp.SigningIdentity, resps .
 
 // CreateProposalResponse creates a proposal response.
 func CreateProposalResponse(hdrbytes []byte, payl []byte, response *peer.Response, results []byte, events []byte, ccid *peer.ChaincodeID, visibility []byte, signingEndorser msp.SigningIdentity) (*peer.ProposalResponse, error) {
+	return CreateProposalResponseWithCrossChannelReads(hdrbytes, payl, response, results, events, nil, ccid, visibility, signingEndorser)
+}
+
+// CreateProposalResponseWithCrossChannelReads creates a proposal response that
+// also records the read sets done on other channels, crossChannelReads being a
+// serialized CrossChannelReadSets (nil if no other channel was read).
+func CreateProposalResponseWithCrossChannelReads(hdrbytes []byte, payl []byte, response *peer.Response, results []byte, events []byte, crossChannelReads []byte, ccid *peer.ChaincodeID, visibility []byte, signingEndorser msp.SigningIdentity) (*peer.ProposalResponse, error) {
 	hdr, err := GetHeader(hdrbytes)
 	if err != nil {
 		return nil, err
@@ -223,7 +230,7 @@ func CreateProposalResponse(hdrbytes []byte, payl []byte, response *peer.Respons
 	}
 
 	// get the bytes of the proposal response payload - we need to sign them
-	prpBytes, err := GetBytesProposalResponsePayload(pHashBytes, response, results, events, ccid)
+	prpBytes, err := getBytesProposalResponsePayload(pHashBytes, response, results, events, crossChannelReads, ccid)
 	if err != nil {
 		return nil, errors.New("Failure while unmarshalling the ProposalResponsePayload")
 	}